@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/keep-network/keep-core/config"
+	"github.com/keep-network/keep-core/pkg/bitcoin"
+	"github.com/keep-network/keep-core/pkg/bitcoin/bitcoind"
+	"github.com/keep-network/keep-core/pkg/bitcoin/electrum"
+	"github.com/keep-network/keep-core/pkg/bitcoin/esplora"
+	"github.com/keep-network/keep-core/pkg/bitcoin/quorum"
+)
+
+// connectBitcoin connects to the Bitcoin chain as described in
+// connectBitcoinChain and applies the configured fee policy on top of it.
+func connectBitcoin(
+	ctx context.Context,
+	bitcoinConfig config.BitcoinConfig,
+) (bitcoin.Chain, error) {
+	chain, err := connectBitcoinChain(ctx, bitcoinConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return applyBitcoinFeePolicy(ctx, bitcoinConfig, chain)
+}
+
+// connectBitcoinChain connects to the Bitcoin chain using the backend
+// selected in the given configuration. If quorum-verified reads are enabled,
+// it connects to all quorum backends and returns a chain handle verifying
+// results across them.
+func connectBitcoinChain(
+	ctx context.Context,
+	bitcoinConfig config.BitcoinConfig,
+) (bitcoin.Chain, error) {
+	if !bitcoinConfig.Quorum.Enabled() {
+		return connectBitcoinBackend(
+			ctx,
+			bitcoinConfig,
+			bitcoinConfig.SelectedBackend(),
+		)
+	}
+
+	var backends []quorum.Backend
+
+	for _, backend := range bitcoinConfig.UsedBackends() {
+		chain, err := connectBitcoinBackend(ctx, bitcoinConfig, backend)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"could not connect to [%s] backend: [%w]",
+				backend,
+				err,
+			)
+		}
+
+		backends = append(
+			backends,
+			quorum.Backend{Name: string(backend), Chain: chain},
+		)
+	}
+
+	for _, url := range bitcoinConfig.Quorum.ElectrumURLs {
+		electrumConfig := bitcoinConfig.Electrum
+		electrumConfig.URL = url
+		electrumConfig.URLs = nil
+
+		chain, err := electrum.Connect(ctx, electrumConfig)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"could not connect to Electrum server [%s]: [%w]",
+				url,
+				err,
+			)
+		}
+
+		backends = append(
+			backends,
+			quorum.Backend{Name: url, Chain: chain},
+		)
+	}
+
+	chain, err := quorum.New(bitcoinConfig.Quorum.Threshold, backends...)
+	if err != nil {
+		return nil, fmt.Errorf("could not set up Bitcoin quorum: [%w]", err)
+	}
+
+	return chain, nil
+}
+
+// applyBitcoinFeePolicy decorates the given Bitcoin chain with the fee
+// policy defined in the given configuration. If no fee policy is configured,
+// the chain is returned as is. Fee sources that are the same backend as the
+// given chain reuse it instead of opening another connection.
+func applyBitcoinFeePolicy(
+	ctx context.Context,
+	bitcoinConfig config.BitcoinConfig,
+	chain bitcoin.Chain,
+) (bitcoin.Chain, error) {
+	if !bitcoinConfig.FeePolicy.Enabled() {
+		return chain, nil
+	}
+
+	var sources []bitcoin.FeeSource
+
+	for _, source := range bitcoinConfig.FeePolicy.Sources {
+		backend := config.BitcoinBackend(source)
+
+		if !bitcoinConfig.Quorum.Enabled() &&
+			backend == bitcoinConfig.SelectedBackend() {
+			sources = append(sources, chain)
+			continue
+		}
+
+		sourceChain, err := connectBitcoinBackend(ctx, bitcoinConfig, backend)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"could not connect to [%s] fee source: [%w]",
+				backend,
+				err,
+			)
+		}
+
+		sources = append(sources, sourceChain)
+	}
+
+	feePolicy, err := bitcoin.NewFeePolicy(
+		chain,
+		bitcoinConfig.FeePolicy.FeePolicyConfig,
+		sources...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not set up Bitcoin fee policy: [%w]", err)
+	}
+
+	return feePolicy, nil
+}
+
+func connectBitcoinBackend(
+	ctx context.Context,
+	bitcoinConfig config.BitcoinConfig,
+	backend config.BitcoinBackend,
+) (bitcoin.Chain, error) {
+	switch backend {
+	case config.ElectrumBackend:
+		return electrum.Connect(ctx, bitcoinConfig.Electrum)
+	case config.BitcoindBackend:
+		return bitcoind.Connect(ctx, bitcoinConfig.Bitcoind)
+	case config.EsploraBackend:
+		return esplora.Connect(ctx, bitcoinConfig.Esplora)
+	default:
+		return nil, fmt.Errorf("unsupported Bitcoin backend: [%s]", backend)
+	}
+}
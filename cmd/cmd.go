@@ -37,6 +37,9 @@ func init() {
 		EthereumCommand,
 		MaintainerCommand,
 		MaintainerCliCommand,
+		WalletCommand,
+		StorageCommand,
+		DebugCommand,
 	)
 }
 
@@ -0,0 +1,227 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/keep-network/keep-core/config"
+	"github.com/keep-network/keep-core/pkg/chain/ethereum"
+	"github.com/keep-network/keep-core/pkg/tbtc"
+	"github.com/keep-network/keep-core/pkg/tecdsa/signing"
+)
+
+const (
+	// replaySigningCommand:
+	sessionFlagName = "session"
+)
+
+// debugCategories are categories needed for the debug command.
+var debugCategories = []config.Category{
+	config.General,
+	config.Ethereum,
+	config.Storage,
+}
+
+// DebugCommand contains the definition of tools for investigating issues
+// of the client.
+var DebugCommand = &cobra.Command{
+	Use:              "debug",
+	Short:            "Debugging tools",
+	Long:             "The tool exposes commands for investigating issues of the client.",
+	TraverseChildren: true,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		if err := clientConfig.ReadConfig(
+			configFilePath,
+			cmd.Flags(),
+			debugCategories...,
+		); err != nil {
+			logger.Fatalf("error reading config: %v", err)
+		}
+	},
+}
+
+var replaySigningCommand = cobra.Command{
+	Use:   "replay-signing",
+	Short: "replay captured signing attempts offline",
+	Long: "Replays signing protocol messages captured during failed signing " +
+		"attempts and reports which members did not send their messages or " +
+		"sent invalid data. Messages are captured only if the client runs " +
+		"with the --tbtc.captureSigningMessages flag. Without the --" +
+		sessionFlagName + " flag, the command lists all captured attempts.",
+	TraverseChildren: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		sessionID, err := cmd.Flags().GetString(sessionFlagName)
+		if err != nil {
+			return fmt.Errorf("failed to find session flag: %v", err)
+		}
+
+		_, _, tbtcDataPersistence, _, err := initializePersistence()
+		if err != nil {
+			return fmt.Errorf("cannot initialize persistence: [%w]", err)
+		}
+
+		captures, err := tbtc.ReadSigningCaptures(tbtcDataPersistence)
+		if err != nil {
+			return fmt.Errorf("cannot read signing captures: [%w]", err)
+		}
+
+		if sessionID == "" {
+			for _, capture := range captures {
+				fmt.Printf(
+					"session [%s] member [%v] wallet [0x%x] blocks [%v-%v] "+
+						"messages [%v] error [%s]\n",
+					capture.SessionID,
+					capture.MemberIndex,
+					capture.WalletPublicKey,
+					capture.StartBlock,
+					capture.TimeoutBlock,
+					len(capture.Messages),
+					capture.Error,
+				)
+			}
+
+			return nil
+		}
+
+		chainSigning, err := ethereum.OperatorSigning(clientConfig.Ethereum)
+		if err != nil {
+			return fmt.Errorf("cannot get operator signing: [%w]", err)
+		}
+
+		replayed := 0
+		for _, capture := range captures {
+			if capture.SessionID != sessionID {
+				continue
+			}
+
+			printReplayReport(capture, capture.Replay(chainSigning))
+			replayed++
+		}
+
+		if replayed == 0 {
+			return fmt.Errorf("no captures of session [%s]", sessionID)
+		}
+
+		return nil
+	},
+}
+
+var attemptTimingCommand = cobra.Command{
+	Use:   "attempt-timing",
+	Short: "summarize signing and DKG attempt timings",
+	Long: "Summarizes outcomes and protocol durations of signing and DKG " +
+		"attempts recorded in the audit journal, per protocol and timing " +
+		"schedule version. The summary shows how close attempts come to " +
+		"their timeouts.",
+	TraverseChildren: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		_, _, tbtcDataPersistence, _, err := initializePersistence()
+		if err != nil {
+			return fmt.Errorf("cannot initialize persistence: [%w]", err)
+		}
+
+		entries, err := tbtc.QueryAuditJournal(
+			tbtcDataPersistence,
+			tbtc.JournalQuery{},
+		)
+		if err != nil {
+			return fmt.Errorf("cannot query audit journal: [%w]", err)
+		}
+
+		out := cmd.OutOrStdout()
+
+		report := tbtc.NewAttemptTimingReport(entries)
+		if len(report.Summaries) == 0 {
+			fmt.Fprintln(out, "no attempts recorded")
+			return nil
+		}
+
+		for _, summary := range report.Summaries {
+			fmt.Fprintf(
+				out,
+				"protocol [%s] timing version [%v] maximum protocol "+
+					"blocks [%v]\n"+
+					"  attempts [%v] succeeded [%v] failed [%v] "+
+					"timed out [%v] skipped [%v] not ready [%v]\n"+
+					"  protocol blocks min [%v] median [%v] max [%v] "+
+					"min margin [%v] near deadline [%v]\n",
+				summary.Protocol,
+				summary.TimingVersion,
+				summary.MaximumProtocolBlocks,
+				summary.Attempts,
+				summary.Succeeded,
+				summary.Failed,
+				summary.TimedOut,
+				summary.Skipped,
+				summary.NotReady,
+				summary.MinProtocolBlocks,
+				summary.MedianProtocolBlocks,
+				summary.MaxProtocolBlocks,
+				summary.MinMarginBlocks,
+				summary.NearDeadline,
+			)
+		}
+
+		return nil
+	},
+}
+
+// printReplayReport prints the report of the given replayed capture.
+func printReplayReport(
+	capture *tbtc.SigningCapture,
+	report *signing.ReplayReport,
+) {
+	fmt.Printf(
+		"session [%s] replayed as member [%v] (excluded: %v)\n"+
+			"attempt error: [%s]\n",
+		capture.SessionID,
+		capture.MemberIndex,
+		capture.ExcludedMembersIndexes,
+		capture.Error,
+	)
+
+	for _, phase := range report.Phases {
+		fmt.Printf(
+			"  phase [%s] senders: %v missing: %v\n",
+			phase.MessageType,
+			phase.Senders,
+			phase.Missing,
+		)
+
+		for _, finding := range phase.Invalid {
+			fmt.Printf(
+				"    invalid data from member [%v]: %s\n",
+				finding.SenderID,
+				finding.Reason,
+			)
+		}
+	}
+
+	for _, finding := range report.Ignored {
+		fmt.Printf(
+			"  ignored message from [0x%x] (member [%v]): %s\n",
+			finding.SenderPublicKey,
+			finding.SenderID,
+			finding.Reason,
+		)
+	}
+
+	fmt.Printf("  culprits: %v\n", report.Culprits())
+}
+
+func init() {
+	initFlags(DebugCommand, &configFilePath, clientConfig, debugCategories...)
+
+	// Replay Signing Subcommand.
+	replaySigningCommand.Flags().String(
+		sessionFlagName,
+		"",
+		"session ID of the signing attempt to replay",
+	)
+
+	DebugCommand.AddCommand(&replaySigningCommand)
+
+	// Attempt Timing Subcommand.
+	DebugCommand.AddCommand(&attemptTimingCommand)
+}
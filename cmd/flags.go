@@ -12,12 +12,17 @@ import (
 	"github.com/keep-network/keep-common/pkg/rate"
 	"github.com/keep-network/keep-core/config"
 	"github.com/keep-network/keep-core/config/network"
+	"github.com/keep-network/keep-core/pkg/bitcoin/bitcoind"
 	"github.com/keep-network/keep-core/pkg/bitcoin/electrum"
+	"github.com/keep-network/keep-core/pkg/bitcoin/esplora"
 	chainEthereum "github.com/keep-network/keep-core/pkg/chain/ethereum"
 	"github.com/keep-network/keep-core/pkg/clientinfo"
+	"github.com/keep-network/keep-core/pkg/maintainer/profitability"
 	"github.com/keep-network/keep-core/pkg/maintainer/spv"
+	"github.com/keep-network/keep-core/pkg/maintainer/watcher"
 	"github.com/keep-network/keep-core/pkg/net/libp2p"
 	"github.com/keep-network/keep-core/pkg/tbtc"
+	"github.com/keep-network/keep-core/pkg/tbtcpg"
 )
 
 func initGlobalFlags(
@@ -39,7 +44,11 @@ func initFlags(
 		case config.Ethereum:
 			initEthereumFlags(cmd, cfg)
 		case config.BitcoinElectrum:
+			initBitcoinBackendFlags(cmd, cfg)
 			initBitcoinElectrumFlags(cmd, cfg)
+			initBitcoinBitcoindFlags(cmd, cfg)
+			initBitcoinEsploraFlags(cmd, cfg)
+			initBitcoinQuorumFlags(cmd, cfg)
 		case config.Network:
 			initNetworkFlags(cmd, cfg)
 		case config.Storage:
@@ -153,6 +162,42 @@ func initEthereumFlags(cmd *cobra.Command, cfg *config.Config) {
 		*commonEthereum.WrapWei(big.NewInt(500000000000000000)), // 0.5 ether
 		"The minimum balance of operator account below which client starts reporting errors in logs.",
 	)
+
+	cmd.Flags().BoolVar(
+		&cfg.EventIndex.Disabled,
+		"eventIndex.disabled",
+		false,
+		"Disable the local index of past Bridge and WalletRegistry events. Past events are fetched from the Ethereum client on every query then.",
+	)
+
+	cmd.Flags().Uint64Var(
+		&cfg.EventIndex.StartBlock,
+		"eventIndex.startBlock",
+		0,
+		"The first block whose events are indexed. Must not be later than the Bridge and WalletRegistry deployment. If not set, the default of the network is used. If the network has no default, the deployment block is determined from the chain which requires an Ethereum client serving the historical state.",
+	)
+
+	cmd.Flags().Uint64Var(
+		&cfg.EventIndex.BlockRange,
+		"eventIndex.blockRange",
+		chainEthereum.DefaultEventIndexBlockRange,
+		"The maximum number of blocks whose events are fetched with a single query when the event index is built.",
+	)
+}
+
+// Initialize flags for Bitcoin backend selection.
+func initBitcoinBackendFlags(cmd *cobra.Command, cfg *config.Config) {
+	cmd.Flags().StringVar(
+		(*string)(&cfg.Bitcoin.Backend),
+		"bitcoin.backend",
+		string(config.ElectrumBackend),
+		fmt.Sprintf(
+			"Backend used to interact with the Bitcoin chain: [%s, %s, %s].",
+			config.ElectrumBackend,
+			config.BitcoindBackend,
+			config.EsploraBackend,
+		),
+	)
 }
 
 // Initialize flags for Bitcoin electrum configuration.
@@ -164,6 +209,13 @@ func initBitcoinElectrumFlags(cmd *cobra.Command, cfg *config.Config) {
 		"URL to the Electrum server in format: `scheme://hostname:port`.",
 	)
 
+	cmd.Flags().StringSliceVar(
+		&cfg.Bitcoin.Electrum.URLs,
+		"bitcoin.electrum.urls",
+		[]string{},
+		"Comma-separated list of additional Electrum servers in format: `scheme://hostname:port`, used for failover.",
+	)
+
 	cmd.Flags().DurationVar(
 		&cfg.Bitcoin.Electrum.ConnectTimeout,
 		"bitcoin.electrum.connectTimeout",
@@ -200,6 +252,99 @@ func initBitcoinElectrumFlags(cmd *cobra.Command, cfg *config.Config) {
 	)
 }
 
+// Initialize flags for Bitcoin bitcoind configuration.
+func initBitcoinBitcoindFlags(cmd *cobra.Command, cfg *config.Config) {
+	cmd.Flags().StringVar(
+		&cfg.Bitcoin.Bitcoind.URL,
+		"bitcoin.bitcoind.url",
+		"",
+		"URL to the bitcoind JSON-RPC interface in format: `scheme://hostname:port`.",
+	)
+
+	cmd.Flags().StringVar(
+		&cfg.Bitcoin.Bitcoind.Username,
+		"bitcoin.bitcoind.username",
+		"",
+		"Username for the bitcoind JSON-RPC interface.",
+	)
+
+	cmd.Flags().StringVar(
+		&cfg.Bitcoin.Bitcoind.Wallet,
+		"bitcoin.bitcoind.wallet",
+		bitcoind.DefaultWallet,
+		"Name of the bitcoind watch-only wallet used to track wallet transactions.",
+	)
+
+	cmd.Flags().Int64Var(
+		&cfg.Bitcoin.Bitcoind.RescanFromTimestamp,
+		"bitcoin.bitcoind.rescanFromTimestamp",
+		0,
+		"UNIX timestamp from which the bitcoind wallet is rescanned when a new wallet is watched. Required when bitcoind is used; should not be later than the Bridge deployment.",
+	)
+
+	cmd.Flags().DurationVar(
+		&cfg.Bitcoin.Bitcoind.RequestTimeout,
+		"bitcoin.bitcoind.requestTimeout",
+		bitcoind.DefaultRequestTimeout,
+		"Timeout for a single attempt of bitcoind JSON-RPC request.",
+	)
+
+	cmd.Flags().DurationVar(
+		&cfg.Bitcoin.Bitcoind.RequestRetryTimeout,
+		"bitcoin.bitcoind.requestRetryTimeout",
+		bitcoind.DefaultRequestRetryTimeout,
+		"Timeout for bitcoind JSON-RPC request retries.",
+	)
+}
+
+// Initialize flags for Bitcoin esplora configuration.
+func initBitcoinEsploraFlags(cmd *cobra.Command, cfg *config.Config) {
+	cmd.Flags().StringVar(
+		&cfg.Bitcoin.Esplora.URL,
+		"bitcoin.esplora.url",
+		"",
+		"URL to the Esplora HTTP API in format: `scheme://hostname:port/path`.",
+	)
+
+	cmd.Flags().DurationVar(
+		&cfg.Bitcoin.Esplora.RequestTimeout,
+		"bitcoin.esplora.requestTimeout",
+		esplora.DefaultRequestTimeout,
+		"Timeout for a single attempt of Esplora HTTP API request.",
+	)
+
+	cmd.Flags().DurationVar(
+		&cfg.Bitcoin.Esplora.RequestRetryTimeout,
+		"bitcoin.esplora.requestRetryTimeout",
+		esplora.DefaultRequestRetryTimeout,
+		"Timeout for Esplora HTTP API request retries.",
+	)
+}
+
+// Initialize flags for Bitcoin quorum configuration.
+func initBitcoinQuorumFlags(cmd *cobra.Command, cfg *config.Config) {
+	cmd.Flags().StringSliceVar(
+		&cfg.Bitcoin.Quorum.Backends,
+		"bitcoin.quorum.backends",
+		[]string{},
+		"Comma-separated list of Bitcoin backends whose results are verified against each other.",
+	)
+
+	cmd.Flags().StringSliceVar(
+		&cfg.Bitcoin.Quorum.ElectrumURLs,
+		"bitcoin.quorum.electrumUrls",
+		[]string{},
+		"Comma-separated list of Electrum servers in format: scheme://hostname:port, each taking part in the quorum as a separate backend.",
+	)
+
+	cmd.Flags().IntVar(
+		&cfg.Bitcoin.Quorum.Threshold,
+		"bitcoin.quorum.threshold",
+		0,
+		"Minimum number of Bitcoin backends that must return the same result. (0 = simple majority)",
+	)
+}
+
 // Initialize flags for Network configuration.
 func initNetworkFlags(cmd *cobra.Command, cfg *config.Config) {
 	cmd.Flags().BoolVar(
@@ -308,6 +453,32 @@ func initTbtcFlags(cmd *cobra.Command, cfg *config.Config) {
 		tbtc.DefaultKeyGenerationConcurrency,
 		"tECDSA key generation concurrency.",
 	)
+
+	cmd.Flags().BoolVar(
+		&cfg.Tbtc.ShadowMode,
+		"shadow",
+		false,
+		"Run the node in the shadow mode. The node follows chain events and "+
+			"coordination windows, generates and validates proposals locally, "+
+			"but never broadcasts messages, signs, nor submits on-chain "+
+			"transactions.",
+	)
+
+	cmd.Flags().BoolVar(
+		&cfg.Tbtc.CaptureSigningMessages,
+		"tbtc.captureSigningMessages",
+		false,
+		"Capture signing protocol messages of failed signing attempts in the "+
+			"work directory for an offline replay.",
+	)
+
+	cmd.Flags().StringVar(
+		&cfg.Tbtc.DepositSweepStrategy,
+		"tbtc.depositSweepStrategy",
+		tbtcpg.DefaultDepositSelectionStrategy,
+		"Strategy used to select deposits to sweep: oldest-first, "+
+			"value-weighted, group-by-vault or refund-locktime-risk.",
+	)
 }
 
 // Initialize flags for Maintainer configuration.
@@ -363,6 +534,81 @@ func initMaintainerFlags(command *cobra.Command, cfg *config.Config) {
 		"The wait time which should be applied when there are no more "+
 			"transaction proofs to submit.",
 	)
+
+	command.Flags().DurationVar(
+		&cfg.Maintainer.Spv.BitcoinBlockPollInterval,
+		"spv.bitcoinBlockPollInterval",
+		spv.DefaultBitcoinBlockPollInterval,
+		"The interval of checking for new Bitcoin blocks which may make "+
+			"pending transaction proofs provable.",
+	)
+
+	command.Flags().BoolVar(
+		&cfg.Maintainer.Watcher.Enabled,
+		"watcher",
+		false,
+		"Start watcher maintainer.",
+	)
+
+	command.Flags().BoolVar(
+		&cfg.Maintainer.Watcher.NotifyTimeouts,
+		"watcher.notifyTimeouts",
+		false,
+		"Notify the Bridge about timed out redemptions, moving funds and "+
+			"moved funds sweeps instead of only alerting about them.",
+	)
+
+	command.Flags().Uint64Var(
+		&cfg.Maintainer.Watcher.HistoryDepth,
+		"watcher.historyDepth",
+		watcher.DefaultHistoryDepth,
+		"Number of blocks to look back for past wallet-related events.",
+	)
+
+	command.Flags().IntVar(
+		&cfg.Maintainer.Watcher.TransactionLimit,
+		"watcher.transactionLimit",
+		watcher.DefaultTransactionLimit,
+		"The maximum number of latest transactions of each wallet checked "+
+			"against wallet actions known to the Bridge.",
+	)
+
+	command.Flags().DurationVar(
+		&cfg.Maintainer.Watcher.RestartBackoffTime,
+		"watcher.restartBackoffTime",
+		watcher.DefaultRestartBackoffTime,
+		"The restart backoff which should be applied when the watcher "+
+			"maintainer is restarted.",
+	)
+
+	command.Flags().DurationVar(
+		&cfg.Maintainer.Watcher.IdleBackoffTime,
+		"watcher.idleBackoffTime",
+		watcher.DefaultIdleBackoffTime,
+		"The wait time which should be applied between consecutive rounds "+
+			"of watching the protocol.",
+	)
+
+	command.Flags().StringVar(
+		(*string)(&cfg.Maintainer.Profitability.Policy),
+		"profitability.policy",
+		string(profitability.PolicySubmit),
+		fmt.Sprintf(
+			"Policy applied to reimbursable transactions whose estimated "+
+				"cost exceeds the estimated reimbursement: [%s, %s, %s].",
+			profitability.PolicySubmit,
+			profitability.PolicyWait,
+			profitability.PolicySkip,
+		),
+	)
+
+	command.Flags().DurationVar(
+		&cfg.Maintainer.Profitability.MaxDelay,
+		"profitability.maxDelay",
+		profitability.DefaultMaxDelay,
+		"The maximum time an unprofitable reimbursable transaction can be "+
+			"postponed for.",
+	)
 }
 
 // Initialize flags for Developer configuration.
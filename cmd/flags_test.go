@@ -22,6 +22,7 @@ import (
 	ethereumEcdsa "github.com/keep-network/keep-core/pkg/chain/ethereum/ecdsa/gen"
 	ethereumTbtc "github.com/keep-network/keep-core/pkg/chain/ethereum/tbtc/gen"
 	ethereumThreshold "github.com/keep-network/keep-core/pkg/chain/ethereum/threshold/gen"
+	"github.com/keep-network/keep-core/pkg/maintainer/profitability"
 )
 
 var cmdFlagsTests = map[string]struct {
@@ -80,6 +81,13 @@ var cmdFlagsTests = map[string]struct {
 		expectedValueFromFlag: big.NewInt(1250000000000000000),
 		defaultValue:          big.NewInt(500000000000000000),
 	},
+	"bitcoin.backend": {
+		readValueFunc:         func(c *config.Config) interface{} { return c.Bitcoin.Backend },
+		flagName:              "--bitcoin.backend",
+		flagValue:             "esplora",
+		expectedValueFromFlag: config.EsploraBackend,
+		defaultValue:          config.ElectrumBackend,
+	},
 	"bitcoin.electrum.url": {
 		readValueFunc:         func(c *config.Config) interface{} { return c.Bitcoin.Electrum.URL },
 		flagName:              "--bitcoin.electrum.url",
@@ -87,6 +95,16 @@ var cmdFlagsTests = map[string]struct {
 		expectedValueFromFlag: "tcp://url.to.electrum:18332",
 		defaultValue:          "",
 	},
+	"bitcoin.electrum.urls": {
+		readValueFunc: func(c *config.Config) interface{} { return c.Bitcoin.Electrum.URLs },
+		flagName:      "--bitcoin.electrum.urls",
+		flagValue:     `"tcp://url.to.electrum1:18332","ssl://url.to.electrum2:18333"`,
+		expectedValueFromFlag: []string{
+			"tcp://url.to.electrum1:18332",
+			"ssl://url.to.electrum2:18333",
+		},
+		defaultValue: []string{},
+	},
 	"bitcoin.electrum.connectTimeout": {
 		readValueFunc:         func(c *config.Config) interface{} { return c.Bitcoin.Electrum.ConnectTimeout },
 		flagName:              "--bitcoin.electrum.connectTimeout",
@@ -122,6 +140,93 @@ var cmdFlagsTests = map[string]struct {
 		expectedValueFromFlag: 660 * time.Second,
 		defaultValue:          300 * time.Second,
 	},
+	"bitcoin.bitcoind.url": {
+		readValueFunc:         func(c *config.Config) interface{} { return c.Bitcoin.Bitcoind.URL },
+		flagName:              "--bitcoin.bitcoind.url",
+		flagValue:             "http://url.to.bitcoind:18332",
+		expectedValueFromFlag: "http://url.to.bitcoind:18332",
+		defaultValue:          "",
+	},
+	"bitcoin.bitcoind.username": {
+		readValueFunc:         func(c *config.Config) interface{} { return c.Bitcoin.Bitcoind.Username },
+		flagName:              "--bitcoin.bitcoind.username",
+		flagValue:             "keep",
+		expectedValueFromFlag: "keep",
+		defaultValue:          "",
+	},
+	"bitcoin.bitcoind.wallet": {
+		readValueFunc:         func(c *config.Config) interface{} { return c.Bitcoin.Bitcoind.Wallet },
+		flagName:              "--bitcoin.bitcoind.wallet",
+		flagValue:             "watch-only",
+		expectedValueFromFlag: "watch-only",
+		defaultValue:          "keep-client",
+	},
+	"bitcoin.bitcoind.rescanFromTimestamp": {
+		readValueFunc:         func(c *config.Config) interface{} { return c.Bitcoin.Bitcoind.RescanFromTimestamp },
+		flagName:              "--bitcoin.bitcoind.rescanFromTimestamp",
+		flagValue:             "1672531200",
+		expectedValueFromFlag: int64(1672531200),
+		defaultValue:          int64(0),
+	},
+	"bitcoin.bitcoind.requestTimeout": {
+		readValueFunc:         func(c *config.Config) interface{} { return c.Bitcoin.Bitcoind.RequestTimeout },
+		flagName:              "--bitcoin.bitcoind.requestTimeout",
+		flagValue:             "47s",
+		expectedValueFromFlag: 47 * time.Second,
+		defaultValue:          30 * time.Second,
+	},
+	"bitcoin.bitcoind.requestRetryTimeout": {
+		readValueFunc:         func(c *config.Config) interface{} { return c.Bitcoin.Bitcoind.RequestRetryTimeout },
+		flagName:              "--bitcoin.bitcoind.requestRetryTimeout",
+		flagValue:             "4m",
+		expectedValueFromFlag: 240 * time.Second,
+		defaultValue:          120 * time.Second,
+	},
+	"bitcoin.esplora.url": {
+		readValueFunc:         func(c *config.Config) interface{} { return c.Bitcoin.Esplora.URL },
+		flagName:              "--bitcoin.esplora.url",
+		flagValue:             "https://url.to.esplora/api",
+		expectedValueFromFlag: "https://url.to.esplora/api",
+		defaultValue:          "",
+	},
+	"bitcoin.esplora.requestTimeout": {
+		readValueFunc:         func(c *config.Config) interface{} { return c.Bitcoin.Esplora.RequestTimeout },
+		flagName:              "--bitcoin.esplora.requestTimeout",
+		flagValue:             "12s",
+		expectedValueFromFlag: 12 * time.Second,
+		defaultValue:          30 * time.Second,
+	},
+	"bitcoin.esplora.requestRetryTimeout": {
+		readValueFunc:         func(c *config.Config) interface{} { return c.Bitcoin.Esplora.RequestRetryTimeout },
+		flagName:              "--bitcoin.esplora.requestRetryTimeout",
+		flagValue:             "3m",
+		expectedValueFromFlag: 180 * time.Second,
+		defaultValue:          120 * time.Second,
+	},
+	"bitcoin.quorum.backends": {
+		readValueFunc:         func(c *config.Config) interface{} { return c.Bitcoin.Quorum.Backends },
+		flagName:              "--bitcoin.quorum.backends",
+		flagValue:             "electrum,esplora",
+		expectedValueFromFlag: []string{"electrum", "esplora"},
+		defaultValue:          []string{},
+	},
+	"bitcoin.quorum.electrumUrls": {
+		readValueFunc: func(c *config.Config) interface{} { return c.Bitcoin.Quorum.ElectrumURLs },
+		flagName:      "--bitcoin.quorum.electrumUrls",
+		flagValue:     `"tcp://url.to.electrum1:18332","ssl://url.to.electrum2:18333"`,
+		expectedValueFromFlag: []string{
+			"tcp://url.to.electrum1:18332",
+			"ssl://url.to.electrum2:18333",
+		},
+		defaultValue: []string{},
+	},
+	"bitcoin.quorum.threshold": {
+		readValueFunc:         func(c *config.Config) interface{} { return c.Bitcoin.Quorum.Threshold },
+		flagName:              "--bitcoin.quorum.threshold",
+		flagValue:             "2",
+		expectedValueFromFlag: 2,
+		defaultValue:          0,
+	},
 	"network.bootstrap": {
 		readValueFunc:         func(c *config.Config) interface{} { return c.LibP2P.Bootstrap },
 		flagName:              "--network.bootstrap",
@@ -225,6 +330,27 @@ var cmdFlagsTests = map[string]struct {
 		expectedValueFromFlag: 101,
 		defaultValue:          runtime.GOMAXPROCS(0),
 	},
+	"tbtc.shadowMode": {
+		readValueFunc:         func(c *config.Config) interface{} { return c.Tbtc.ShadowMode },
+		flagName:              "--shadow",
+		flagValue:             "", // don't provide any value
+		expectedValueFromFlag: true,
+		defaultValue:          false,
+	},
+	"tbtc.captureSigningMessages": {
+		readValueFunc:         func(c *config.Config) interface{} { return c.Tbtc.CaptureSigningMessages },
+		flagName:              "--tbtc.captureSigningMessages",
+		flagValue:             "", // don't provide any value
+		expectedValueFromFlag: true,
+		defaultValue:          false,
+	},
+	"tbtc.depositSweepStrategy": {
+		readValueFunc:         func(c *config.Config) interface{} { return c.Tbtc.DepositSweepStrategy },
+		flagName:              "--tbtc.depositSweepStrategy",
+		flagValue:             "value-weighted",
+		expectedValueFromFlag: "value-weighted",
+		defaultValue:          "group-by-vault",
+	},
 	"maintainer.bitcoinDifficulty": {
 		readValueFunc:         func(c *config.Config) interface{} { return c.Maintainer.BitcoinDifficulty.Enabled },
 		flagName:              "--bitcoinDifficulty",
@@ -274,6 +400,69 @@ var cmdFlagsTests = map[string]struct {
 		expectedValueFromFlag: 20 * time.Minute,
 		defaultValue:          10 * time.Minute,
 	},
+	"maintainer.spv.bitcoinBlockPollInterval": {
+		readValueFunc:         func(c *config.Config) interface{} { return c.Maintainer.Spv.BitcoinBlockPollInterval },
+		flagName:              "--spv.bitcoinBlockPollInterval",
+		flagValue:             "1m",
+		expectedValueFromFlag: time.Minute,
+		defaultValue:          30 * time.Second,
+	},
+	"maintainer.watcher": {
+		readValueFunc:         func(c *config.Config) interface{} { return c.Maintainer.Watcher.Enabled },
+		flagName:              "--watcher",
+		flagValue:             "", // don't provide any value
+		expectedValueFromFlag: true,
+		defaultValue:          false,
+	},
+	"maintainer.watcher.notifyTimeouts": {
+		readValueFunc:         func(c *config.Config) interface{} { return c.Maintainer.Watcher.NotifyTimeouts },
+		flagName:              "--watcher.notifyTimeouts",
+		flagValue:             "", // don't provide any value
+		expectedValueFromFlag: true,
+		defaultValue:          false,
+	},
+	"maintainer.watcher.historyDepth": {
+		readValueFunc:         func(c *config.Config) interface{} { return c.Maintainer.Watcher.HistoryDepth },
+		flagName:              "--watcher.historyDepth",
+		flagValue:             "200000",
+		expectedValueFromFlag: uint64(200000),
+		defaultValue:          uint64(100800),
+	},
+	"maintainer.watcher.transactionLimit": {
+		readValueFunc:         func(c *config.Config) interface{} { return c.Maintainer.Watcher.TransactionLimit },
+		flagName:              "--watcher.transactionLimit",
+		flagValue:             "5",
+		expectedValueFromFlag: 5,
+		defaultValue:          20,
+	},
+	"maintainer.watcher.restartBackoffTime": {
+		readValueFunc:         func(c *config.Config) interface{} { return c.Maintainer.Watcher.RestartBackoffTime },
+		flagName:              "--watcher.restartBackoffTime",
+		flagValue:             "1h",
+		expectedValueFromFlag: time.Hour,
+		defaultValue:          30 * time.Minute,
+	},
+	"maintainer.watcher.idleBackoffTime": {
+		readValueFunc:         func(c *config.Config) interface{} { return c.Maintainer.Watcher.IdleBackoffTime },
+		flagName:              "--watcher.idleBackoffTime",
+		flagValue:             "20m",
+		expectedValueFromFlag: 20 * time.Minute,
+		defaultValue:          10 * time.Minute,
+	},
+	"maintainer.profitability.policy": {
+		readValueFunc:         func(c *config.Config) interface{} { return c.Maintainer.Profitability.Policy },
+		flagName:              "--profitability.policy",
+		flagValue:             "wait",
+		expectedValueFromFlag: profitability.PolicyWait,
+		defaultValue:          profitability.PolicySubmit,
+	},
+	"maintainer.profitability.maxDelay": {
+		readValueFunc:         func(c *config.Config) interface{} { return c.Maintainer.Profitability.MaxDelay },
+		flagName:              "--profitability.maxDelay",
+		flagValue:             "2h",
+		expectedValueFromFlag: 2 * time.Hour,
+		defaultValue:          6 * time.Hour,
+	},
 	"developer.randomBeaconAddress": {
 		readValueFunc: func(c *config.Config) interface{} {
 			address, _ := c.Ethereum.ContractAddress(chainEthereum.RandomBeaconContractName)
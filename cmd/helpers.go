@@ -4,6 +4,9 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"syscall"
+
+	"golang.org/x/term"
 
 	commonEthereum "github.com/keep-network/keep-common/pkg/chain/ethereum"
 	"github.com/keep-network/keep-core/build"
@@ -112,3 +115,16 @@ func buildContractAddresses(lineLength int, prefix, suffix string, ethereumConfi
 	}
 	return firstLine + buildMultiLine(lineLength, prefix, suffix, "", entries)
 }
+
+// readPassword prompts a user to enter a password. The password is read using
+// the system password reading call that does not echo the entered characters.
+func readPassword(prompt string) (string, error) {
+	fmt.Print(prompt)
+	bytePassword, err := term.ReadPassword(int(syscall.Stdin))
+	fmt.Print("\n")
+	if err != nil {
+		return "", fmt.Errorf("unable to read password: [%w]", err)
+	}
+
+	return strings.TrimSpace(string(bytePassword)), nil
+}
@@ -6,10 +6,13 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/keep-network/keep-common/pkg/persistence"
+
 	"github.com/keep-network/keep-core/config"
-	"github.com/keep-network/keep-core/pkg/bitcoin/electrum"
+	"github.com/keep-network/keep-core/pkg/bitcoin/headerstore"
 	"github.com/keep-network/keep-core/pkg/chain/ethereum"
 	"github.com/keep-network/keep-core/pkg/maintainer"
+	"github.com/keep-network/keep-core/pkg/storage"
 )
 
 // MaintainerCommand contains the definition of the maintainer command-line
@@ -37,6 +40,10 @@ func init() {
 		clientConfig,
 		config.MaintainerCategories...,
 	)
+
+	// The storage is optional for maintainers so it is not a part of
+	// maintainer categories validated on start.
+	initStorageFlags(MaintainerCommand, clientConfig)
 }
 
 // maintainers initializes maintainer tasks specified by flags passed to the
@@ -44,9 +51,16 @@ func init() {
 func maintainers(cmd *cobra.Command, args []string) error {
 	ctx := context.Background()
 
-	btcChain, err := electrum.Connect(ctx, clientConfig.Bitcoin.Electrum)
+	btcChain, err := connectBitcoin(ctx, clientConfig.Bitcoin)
+	if err != nil {
+		return fmt.Errorf("could not connect to Bitcoin chain: [%v]", err)
+	}
+
+	// Verified block headers are kept only in memory as the disk storage
+	// is optional for the maintainer.
+	btcChain, err = headerstore.New(btcChain, nil, headerstore.Config{})
 	if err != nil {
-		return fmt.Errorf("could not connect to Electrum chain: [%v]", err)
+		return fmt.Errorf("cannot initialize block header store: [%v]", err)
 	}
 
 	btcDiffChain, err := ethereum.ConnectBitcoinDifficulty(
@@ -72,14 +86,54 @@ func maintainers(cmd *cobra.Command, args []string) error {
 		)
 	}
 
+	workPersistence, err := initializeMaintainerPersistence()
+	if err != nil {
+		return fmt.Errorf("cannot initialize persistence: [%w]", err)
+	}
+
 	maintainer.Initialize(
 		ctx,
 		clientConfig.Maintainer,
 		btcChain,
 		btcDiffChain,
 		tbtcChain,
+		tbtcChain,
+		workPersistence,
 	)
 
 	<-ctx.Done()
 	return fmt.Errorf("unexpected context cancellation")
 }
+
+// initializeMaintainerPersistence initializes the persistence of maintainers'
+// work data if the storage directory is configured. Otherwise, it returns
+// a nil handle and maintainers keep their work data only in memory.
+func initializeMaintainerPersistence() (persistence.BasicHandle, error) {
+	if clientConfig.Storage.Dir == "" {
+		logger.Infof(
+			"storage directory not configured; maintainers work data " +
+				"will be kept only in memory",
+		)
+		return nil, nil
+	}
+
+	storage, err := storage.Initialize(
+		clientConfig.Storage,
+		clientConfig.Storage.EncryptionPassword(
+			clientConfig.Ethereum.KeyFilePassword,
+		),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("cannot initialize storage: [%w]", err)
+	}
+
+	workPersistence, err := storage.InitializeWorkPersistence("maintainer")
+	if err != nil {
+		return nil, fmt.Errorf(
+			"cannot initialize maintainer data persistence: [%w]",
+			err,
+		)
+	}
+
+	return workPersistence, nil
+}
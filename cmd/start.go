@@ -9,7 +9,7 @@ import (
 
 	"github.com/keep-network/keep-common/pkg/persistence"
 	"github.com/keep-network/keep-core/build"
-	"github.com/keep-network/keep-core/pkg/bitcoin/electrum"
+	"github.com/keep-network/keep-core/pkg/bitcoin/headerstore"
 	"github.com/keep-network/keep-core/pkg/operator"
 	"github.com/keep-network/keep-core/pkg/storage"
 
@@ -52,10 +52,12 @@ func init() {
 		fmt.Sprintf(`%s
 Environment variables:
     %s    Password for Keep operator account keyfile decryption.
+    %s     Password for storage encryption; defaults to the operator account keyfile password.
     %s                 Space-delimited set of log level directives; set to "help" for help.
 `,
 			StartCommand.UsageString(),
 			config.EthereumPasswordEnvVariable,
+			config.StoragePasswordEnvVariable,
 			config.LogLevelEnvVariable,
 		),
 	)
@@ -71,6 +73,24 @@ func start(cmd *cobra.Command) error {
 		return fmt.Errorf("error connecting to Ethereum node: [%v]", err)
 	}
 
+	// The network identity is derived from the operator key. Make sure
+	// the shadow node does not impersonate the production node whose
+	// storage it follows before joining the network.
+	if !isBootstrap() && clientConfig.Tbtc.ShadowMode {
+		_, tbtcKeyStorePersistence, _, _, err := initializePersistence()
+		if err != nil {
+			return fmt.Errorf("cannot initialize persistence: [%w]", err)
+		}
+
+		err = tbtc.EnsureShadowIdentity(
+			tbtcKeyStorePersistence,
+			signing.Address(),
+		)
+		if err != nil {
+			return fmt.Errorf("cannot run in the shadow mode: [%w]", err)
+		}
+	}
+
 	netProvider, err := initializeNetwork(
 		ctx,
 		[]firewall.Application{beaconChain, tbtcChain},
@@ -110,19 +130,67 @@ func start(cmd *cobra.Command) error {
 	// Skip initialization for bootstrap nodes as they are only used for network
 	// discovery.
 	if !isBootstrap() {
-		btcChain, err := electrum.Connect(ctx, clientConfig.Bitcoin.Electrum)
+		btcChain, err := connectBitcoinChain(ctx, clientConfig.Bitcoin)
 		if err != nil {
-			return fmt.Errorf("could not connect to Electrum chain: [%v]", err)
+			return fmt.Errorf("could not connect to Bitcoin chain: [%v]", err)
+		}
+
+		// Record Bitcoin backends disagreements if quorum-verified reads
+		// are enabled.
+		if perfMetrics != nil {
+			if setter, ok := btcChain.(interface {
+				SetMetricsRecorder(recorder interface {
+					IncrementCounter(name string, value float64)
+				})
+			}); ok {
+				setter.SetMetricsRecorder(perfMetrics)
+			}
+		}
+
+		// Apply the fee policy once metrics of the underlying chain are
+		// set up as the fee policy does not expose them.
+		btcChain, err = applyBitcoinFeePolicy(ctx, clientConfig.Bitcoin, btcChain)
+		if err != nil {
+			return fmt.Errorf("could not apply Bitcoin fee policy: [%v]", err)
 		}
 
 		beaconKeyStorePersistence,
 			tbtcKeyStorePersistence,
 			tbtcDataPersistence,
+			bitcoinDataPersistence,
 			err := initializePersistence()
 		if err != nil {
 			return fmt.Errorf("cannot initialize persistence: [%w]", err)
 		}
 
+		// Serve verified block headers from the local store to avoid
+		// refetching them for every SPV proof.
+		btcChain, err = headerstore.New(
+			btcChain,
+			bitcoinDataPersistence,
+			headerstore.Config{},
+		)
+		if err != nil {
+			return fmt.Errorf("cannot initialize block header store: [%w]", err)
+		}
+
+		// Serve past Bridge and WalletRegistry events from the local event
+		// index to avoid scanning the chain history on every query.
+		if !clientConfig.EventIndex.Disabled {
+			err = tbtcChain.StartEventIndex(
+				ctx,
+				tbtcDataPersistence,
+				clientConfig.EventIndex,
+			)
+			if err != nil {
+				logger.Warnf(
+					"cannot start event index; past events will be "+
+						"fetched from the Ethereum client: [%v]",
+					err,
+				)
+			}
+		}
+
 		scheduler := generator.StartScheduler()
 
 		if clientInfoRegistry != nil {
@@ -142,21 +210,44 @@ func start(cmd *cobra.Command) error {
 			rpcHealthChecker.Start(ctx)
 		}
 
-		err = beacon.Initialize(
-			ctx,
-			beaconChain,
-			netProvider,
-			beaconKeyStorePersistence,
-			scheduler,
-		)
-		if err != nil {
-			return fmt.Errorf("error initializing beacon: [%v]", err)
+		// The beacon is not initialized in the shadow mode as it takes
+		// part in DKG and relay entry signing.
+		if !clientConfig.Tbtc.ShadowMode {
+			err = beacon.Initialize(
+				ctx,
+				beaconChain,
+				netProvider,
+				beaconKeyStorePersistence,
+				scheduler,
+			)
+			if err != nil {
+				return fmt.Errorf("error initializing beacon: [%v]", err)
+			}
 		}
 
-		proposalGenerator := tbtcpg.NewProposalGenerator(
-			tbtcChain,
-			btcChain,
+		var proposalGenerator *tbtcpg.ProposalGenerator
+		if clientConfig.Tbtc.ShadowMode {
+			proposalGenerator = tbtcpg.NewShadowProposalGenerator(
+				tbtcChain,
+				btcChain,
+			)
+		} else {
+			proposalGenerator = tbtcpg.NewProposalGenerator(
+				tbtcChain,
+				btcChain,
+			)
+		}
+
+		depositSelectionStrategy, err := tbtcpg.NewDepositSelectionStrategy(
+			clientConfig.Tbtc.DepositSweepStrategy,
 		)
+		if err != nil {
+			return fmt.Errorf(
+				"error creating deposit selection strategy: [%v]",
+				err,
+			)
+		}
+		proposalGenerator.SetDepositSelectionStrategy(depositSelectionStrategy)
 
 		err = tbtc.Initialize(
 			ctx,
@@ -280,21 +371,24 @@ func initializePersistence() (
 	beaconKeyStorePersistence persistence.ProtectedHandle,
 	tbtcKeyStorePersistence persistence.ProtectedHandle,
 	tbtcDataPersistence persistence.BasicHandle,
+	bitcoinDataPersistence persistence.BasicHandle,
 	err error,
 ) {
 	storage, err := storage.Initialize(
 		clientConfig.Storage,
-		clientConfig.Ethereum.KeyFilePassword,
+		clientConfig.Storage.EncryptionPassword(
+			clientConfig.Ethereum.KeyFilePassword,
+		),
 	)
 	if err != nil {
-		return nil, nil, nil, fmt.Errorf("cannot initialize storage: [%w]", err)
+		return nil, nil, nil, nil, fmt.Errorf("cannot initialize storage: [%w]", err)
 	}
 
 	beaconKeyStorePersistence, err = storage.InitializeKeyStorePersistence(
 		"beacon",
 	)
 	if err != nil {
-		return nil, nil, nil, fmt.Errorf(
+		return nil, nil, nil, nil, fmt.Errorf(
 			"cannot initialize beacon keystore persistence: [%w]",
 			err,
 		)
@@ -304,7 +398,7 @@ func initializePersistence() (
 		"tbtc",
 	)
 	if err != nil {
-		return nil, nil, nil, fmt.Errorf(
+		return nil, nil, nil, nil, fmt.Errorf(
 			"cannot initialize tbtc keystore persistence: [%w]",
 			err,
 		)
@@ -312,11 +406,19 @@ func initializePersistence() (
 
 	tbtcDataPersistence, err = storage.InitializeWorkPersistence("tbtc")
 	if err != nil {
-		return nil, nil, nil, fmt.Errorf(
+		return nil, nil, nil, nil, fmt.Errorf(
 			"cannot initialize tbtc data persistence: [%w]",
 			err,
 		)
 	}
 
+	bitcoinDataPersistence, err = storage.InitializeWorkPersistence("bitcoin")
+	if err != nil {
+		return nil, nil, nil, nil, fmt.Errorf(
+			"cannot initialize bitcoin data persistence: [%w]",
+			err,
+		)
+	}
+
 	return
 }
@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/keep-network/keep-core/config"
+	"github.com/keep-network/keep-core/pkg/storage"
+)
+
+// #nosec G101 (look for hardcoded credentials)
+// This line doesn't contain any credentials.
+// It's just the name of the environment variable.
+const newStoragePasswordEnvVariable = "KEEP_NEW_STORAGE_PASSWORD"
+
+// storageCategories are categories needed for the storage command.
+var storageCategories = []config.Category{
+	config.General,
+	config.Storage,
+}
+
+// StorageCommand contains the definition of tools for managing the client's
+// persistent storage.
+var StorageCommand = &cobra.Command{
+	Use:              "storage",
+	Short:            "Storage tools",
+	Long:             "The tool exposes commands for managing the client's persistent storage.",
+	TraverseChildren: true,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		if err := clientConfig.ReadConfig(
+			configFilePath,
+			cmd.Flags(),
+			storageCategories...,
+		); err != nil {
+			logger.Fatalf("error reading config: %v", err)
+		}
+	},
+}
+
+var rotatePasswordCommand = cobra.Command{
+	Use:   "rotate-password",
+	Short: "re-encrypt the storage under a new password",
+	Long: "Re-encrypts all keystore and work files under a new password. " +
+		"The current password is the " + config.StoragePasswordEnvVariable +
+		" password if set, or the operator account keyfile password " +
+		"otherwise. The new password is read from the " +
+		newStoragePasswordEnvVariable + " environment variable or from " +
+		"the prompt. The client must be stopped during the rotation. " +
+		"Once rotated, the client must be started with the new password " +
+		"set as " + config.StoragePasswordEnvVariable + ", unless it is " +
+		"the new operator account keyfile password.",
+	TraverseChildren: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		newPassword := os.Getenv(newStoragePasswordEnvVariable)
+		if len(newPassword) == 0 {
+			password, err := readPassword("Enter new storage password: ")
+			if err != nil {
+				return err
+			}
+
+			if len(password) == 0 {
+				return fmt.Errorf("new storage password must not be empty")
+			}
+
+			repeated, err := readPassword("Repeat new storage password: ")
+			if err != nil {
+				return err
+			}
+
+			if repeated != password {
+				return fmt.Errorf("passwords do not match")
+			}
+
+			newPassword = password
+		}
+
+		err := storage.RotateEncryptionPassword(
+			clientConfig.Storage,
+			clientConfig.Storage.EncryptionPassword(
+				clientConfig.Ethereum.KeyFilePassword,
+			),
+			newPassword,
+		)
+		if err != nil {
+			return fmt.Errorf("cannot rotate storage password: [%w]", err)
+		}
+
+		fmt.Printf(
+			"storage [%s] re-encrypted under the new password\n",
+			clientConfig.Storage.Dir,
+		)
+
+		return nil
+	},
+}
+
+func init() {
+	initFlags(StorageCommand, &configFilePath, clientConfig, storageCategories...)
+
+	StorageCommand.AddCommand(&rotatePasswordCommand)
+}
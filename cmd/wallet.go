@@ -0,0 +1,334 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/keep-network/keep-common/pkg/persistence"
+
+	"github.com/keep-network/keep-core/config"
+	"github.com/keep-network/keep-core/pkg/beacon/registry"
+	"github.com/keep-network/keep-core/pkg/chain/ethereum"
+	"github.com/keep-network/keep-core/pkg/storage"
+	"github.com/keep-network/keep-core/pkg/tbtc"
+)
+
+const (
+	// BackupPasswordEnvVariable is the environment variable holding
+	// the password of the keystore backup archive.
+	BackupPasswordEnvVariable = "KEEP_BACKUP_PASSWORD"
+
+	// Names of keystore persistence directories included in the backup.
+	beaconKeyStoreName = "beacon"
+	tbtcKeyStoreName   = "tbtc"
+
+	// walletBackupCommand:
+	outputFlagName = "output"
+
+	// walletRestoreCommand:
+	inputFlagName = "input"
+)
+
+// walletCategories are categories needed for the wallet command.
+var walletCategories = []config.Category{
+	config.General,
+	config.Ethereum,
+	config.Storage,
+}
+
+// WalletCommand contains the definition of tools for managing key shares
+// held by the client.
+var WalletCommand = &cobra.Command{
+	Use:              "wallet",
+	Short:            "Wallet key shares tools",
+	Long:             "The tool exposes commands for managing key shares held in the keystore.",
+	TraverseChildren: true,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		if err := clientConfig.ReadConfig(
+			configFilePath,
+			cmd.Flags(),
+			walletCategories...,
+		); err != nil {
+			logger.Fatalf("error reading config: %v", err)
+		}
+	},
+}
+
+var walletBackupCommand = cobra.Command{
+	Use:   "backup",
+	Short: "export key shares into an encrypted archive",
+	Long: "Exports all tBTC wallet signers and beacon group memberships " +
+		"from the keystore into a single password-encrypted archive. " +
+		"The archive password is read from the " + BackupPasswordEnvVariable +
+		" environment variable or from the prompt.",
+	TraverseChildren: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		outputPath, err := cmd.Flags().GetString(outputFlagName)
+		if err != nil {
+			return fmt.Errorf("failed to find output flag: %v", err)
+		}
+
+		beaconKeyStorePersistence, tbtcKeyStorePersistence, _, _, err :=
+			initializePersistence()
+		if err != nil {
+			return fmt.Errorf("cannot initialize persistence: [%w]", err)
+		}
+
+		backup := &storage.Backup{CreatedAt: time.Now()}
+
+		for keystore, handle := range map[string]persistence.ProtectedHandle{
+			beaconKeyStoreName: beaconKeyStorePersistence,
+			tbtcKeyStoreName:   tbtcKeyStorePersistence,
+		} {
+			entries, err := storage.ReadBackupEntries(handle, keystore)
+			if err != nil {
+				return err
+			}
+
+			backup.Entries = append(backup.Entries, entries...)
+		}
+
+		password, err := readBackupPassword(true)
+		if err != nil {
+			return err
+		}
+
+		archive, err := storage.SealBackup(backup, password)
+		if err != nil {
+			return fmt.Errorf("cannot seal backup: [%w]", err)
+		}
+
+		// Never overwrite an existing file as it may hold a previous backup.
+		file, err := os.OpenFile(
+			outputPath,
+			os.O_WRONLY|os.O_CREATE|os.O_EXCL,
+			0600,
+		)
+		if err != nil {
+			return fmt.Errorf("cannot create backup file: [%w]", err)
+		}
+		defer file.Close()
+
+		if _, err := file.Write(archive); err != nil {
+			return fmt.Errorf("cannot write backup file: [%w]", err)
+		}
+
+		fmt.Printf(
+			"exported [%v] keystore entries to [%s]\n",
+			len(backup.Entries),
+			outputPath,
+		)
+
+		return nil
+	},
+}
+
+var walletRestoreCommand = cobra.Command{
+	Use:   "restore",
+	Short: "restore key shares from an encrypted archive",
+	Long: "Restores tBTC wallet signers and beacon group memberships from " +
+		"an archive created with the backup command. Each tBTC signer is " +
+		"verified against the on-chain wallet before anything is written. " +
+		"Entries already present in the keystore are never overwritten; " +
+		"the restore is refused if the keystore holds different data for " +
+		"any of them.",
+	TraverseChildren: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+
+		inputPath, err := cmd.Flags().GetString(inputFlagName)
+		if err != nil {
+			return fmt.Errorf("failed to find input flag: %v", err)
+		}
+
+		archive, err := os.ReadFile(inputPath)
+		if err != nil {
+			return fmt.Errorf("cannot read backup file: [%w]", err)
+		}
+
+		password, err := readBackupPassword(false)
+		if err != nil {
+			return err
+		}
+
+		backup, err := storage.OpenBackup(archive, password)
+		if err != nil {
+			return fmt.Errorf("cannot open backup: [%w]", err)
+		}
+
+		fmt.Printf(
+			"backup created at [%s] holds [%v] keystore entries\n",
+			backup.CreatedAt.Format(time.RFC3339),
+			len(backup.Entries),
+		)
+
+		_, tbtcChain, _, _, _, err := ethereum.Connect(
+			ctx,
+			clientConfig.Ethereum,
+		)
+		if err != nil {
+			return fmt.Errorf(
+				"could not connect to Ethereum chain: [%v]",
+				err,
+			)
+		}
+
+		entriesByKeyStore := make(map[string][]*storage.BackupEntry)
+		for _, entry := range backup.Entries {
+			switch entry.Keystore {
+			case beaconKeyStoreName:
+				membership := &registry.Membership{}
+				if err := membership.Unmarshal(entry.Data); err != nil {
+					return fmt.Errorf(
+						"invalid beacon membership [%s]: [%v]",
+						entry,
+						err,
+					)
+				}
+			case tbtcKeyStoreName:
+				info, err := tbtc.VerifySignerBackup(tbtcChain, entry.Data)
+				if err != nil {
+					return fmt.Errorf(
+						"invalid tBTC signer [%s]: [%v]",
+						entry,
+						err,
+					)
+				}
+
+				fmt.Printf(
+					"verified signer with index [%v] of wallet [0x%x]\n",
+					info.SigningGroupMemberIndex,
+					info.WalletPublicKeyHash,
+				)
+			default:
+				return fmt.Errorf(
+					"unknown keystore [%s] of entry [%s]",
+					entry.Keystore,
+					entry,
+				)
+			}
+
+			entriesByKeyStore[entry.Keystore] = append(
+				entriesByKeyStore[entry.Keystore],
+				entry,
+			)
+		}
+
+		beaconKeyStorePersistence, tbtcKeyStorePersistence, _, _, err :=
+			initializePersistence()
+		if err != nil {
+			return fmt.Errorf("cannot initialize persistence: [%w]", err)
+		}
+
+		handles := map[string]persistence.ProtectedHandle{
+			beaconKeyStoreName: beaconKeyStorePersistence,
+			tbtcKeyStoreName:   tbtcKeyStorePersistence,
+		}
+
+		// Select entries to restore in all keystores before saving anything
+		// so a conflict in one keystore does not leave the other one
+		// partially restored.
+		restorableByKeyStore := make(map[string][]*storage.BackupEntry)
+		for keystore, entries := range entriesByKeyStore {
+			restorable, err := storage.SelectRestorableEntries(
+				handles[keystore],
+				entries,
+			)
+			if err != nil {
+				return fmt.Errorf(
+					"cannot restore [%s] keystore: [%w]",
+					keystore,
+					err,
+				)
+			}
+
+			restorableByKeyStore[keystore] = restorable
+		}
+
+		for keystore, restorable := range restorableByKeyStore {
+			if err := storage.SaveBackupEntries(
+				handles[keystore],
+				restorable,
+			); err != nil {
+				return fmt.Errorf(
+					"cannot restore [%s] keystore: [%w]",
+					keystore,
+					err,
+				)
+			}
+
+			fmt.Printf(
+				"restored [%v] of [%v] entries of [%s] keystore\n",
+				len(restorable),
+				len(entriesByKeyStore[keystore]),
+				keystore,
+			)
+		}
+
+		return nil
+	},
+}
+
+// readBackupPassword reads the backup archive password from the environment
+// variable or the prompt. If confirm is true, the password entered in the
+// prompt must be repeated.
+func readBackupPassword(confirm bool) (string, error) {
+	if password := os.Getenv(BackupPasswordEnvVariable); password != "" {
+		return password, nil
+	}
+
+	password, err := readPassword("Enter backup password: ")
+	if err != nil {
+		return "", err
+	}
+
+	if password == "" {
+		return "", fmt.Errorf("backup password must not be empty")
+	}
+
+	if confirm {
+		repeated, err := readPassword("Repeat backup password: ")
+		if err != nil {
+			return "", err
+		}
+
+		if repeated != password {
+			return "", fmt.Errorf("passwords do not match")
+		}
+	}
+
+	return password, nil
+}
+
+func init() {
+	initFlags(WalletCommand, &configFilePath, clientConfig, walletCategories...)
+
+	// Backup Subcommand.
+	walletBackupCommand.Flags().String(
+		outputFlagName,
+		"",
+		"path of the backup archive to create",
+	)
+
+	if err := walletBackupCommand.MarkFlagRequired(outputFlagName); err != nil {
+		logger.Fatalf("failed to mark flag required: [%v]", err)
+	}
+
+	WalletCommand.AddCommand(&walletBackupCommand)
+
+	// Restore Subcommand.
+	walletRestoreCommand.Flags().String(
+		inputFlagName,
+		"",
+		"path of the backup archive to restore",
+	)
+
+	if err := walletRestoreCommand.MarkFlagRequired(inputFlagName); err != nil {
+		logger.Fatalf("failed to mark flag required: [%v]", err)
+	}
+
+	WalletCommand.AddCommand(&walletRestoreCommand)
+}
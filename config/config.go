@@ -16,15 +16,21 @@ import (
 	"github.com/mitchellh/mapstructure"
 	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
+	"golang.org/x/exp/slices"
 	"golang.org/x/term"
 
 	commonEthereum "github.com/keep-network/keep-common/pkg/chain/ethereum"
+	"github.com/keep-network/keep-core/pkg/bitcoin/bitcoind"
 	"github.com/keep-network/keep-core/pkg/bitcoin/electrum"
+	"github.com/keep-network/keep-core/pkg/bitcoin/esplora"
+	chainEthereum "github.com/keep-network/keep-core/pkg/chain/ethereum"
 	"github.com/keep-network/keep-core/pkg/clientinfo"
 	"github.com/keep-network/keep-core/pkg/maintainer"
+	"github.com/keep-network/keep-core/pkg/maintainer/profitability"
 	"github.com/keep-network/keep-core/pkg/net/libp2p"
 	"github.com/keep-network/keep-core/pkg/storage"
 	"github.com/keep-network/keep-core/pkg/tbtc"
+	"github.com/keep-network/keep-core/pkg/tbtcpg"
 )
 
 var logger = log.Logger("keep-config")
@@ -35,6 +41,11 @@ const (
 	// It's just the name of the environment variable.
 	EthereumPasswordEnvVariable = "KEEP_ETHEREUM_PASSWORD"
 
+	// #nosec G101 (look for hardcoded credentials)
+	// This line doesn't contain any credentials.
+	// It's just the name of the environment variable.
+	StoragePasswordEnvVariable = "KEEP_STORAGE_PASSWORD"
+
 	// LogLevelEnvVariable can be used to define logging configuration.
 	LogLevelEnvVariable = "LOG_LEVEL"
 
@@ -52,13 +63,103 @@ type Config struct {
 	ClientInfo clientinfo.Config
 	Maintainer maintainer.Config
 	Tbtc       tbtc.Config
+	EventIndex chainEthereum.EventIndexConfig
 }
 
+// BitcoinBackend is the type of the backend used to interact with the
+// Bitcoin chain.
+type BitcoinBackend string
+
+const (
+	// ElectrumBackend uses Electrum servers. This is the default backend.
+	ElectrumBackend BitcoinBackend = "electrum"
+	// BitcoindBackend uses the JSON-RPC interface of a bitcoind node.
+	BitcoindBackend BitcoinBackend = "bitcoind"
+	// EsploraBackend uses the Esplora HTTP API.
+	EsploraBackend BitcoinBackend = "esplora"
+)
+
 // BitcoinConfig defines the configuration for Bitcoin.
 type BitcoinConfig struct {
 	bitcoin.Network
+	// Backend is the type of the backend used to interact with the Bitcoin
+	// chain. If not set, the Electrum backend is used.
+	Backend BitcoinBackend
 	// Electrum defines the configuration for the Electrum client.
 	Electrum electrum.Config
+	// Bitcoind defines the configuration for the bitcoind client.
+	Bitcoind bitcoind.Config
+	// Esplora defines the configuration for the Esplora client.
+	Esplora esplora.Config
+	// Quorum defines the configuration for quorum-verified reads across
+	// multiple backends. If set, the Backend setting is ignored.
+	Quorum BitcoinQuorumConfig
+	// FeePolicy defines the policy applied to Bitcoin fee estimates.
+	FeePolicy BitcoinFeePolicyConfig
+}
+
+// SelectedBackend returns the type of the backend used to interact with the
+// Bitcoin chain.
+func (bc BitcoinConfig) SelectedBackend() BitcoinBackend {
+	if len(bc.Backend) == 0 {
+		return ElectrumBackend
+	}
+
+	return bc.Backend
+}
+
+// BitcoinQuorumConfig defines the configuration for quorum-verified reads
+// across multiple Bitcoin chain backends.
+type BitcoinQuorumConfig struct {
+	// Backends is the list of backends whose results are compared. Each
+	// backend is configured in its own section.
+	Backends []string
+	// ElectrumURLs is the list of additional Electrum servers, each taking
+	// part in the quorum as a separate backend.
+	ElectrumURLs []string
+	// Threshold is the minimum number of backends that must return the same
+	// result. If not set, a simple majority of backends is required.
+	Threshold int
+}
+
+// Enabled returns true if quorum-verified reads are configured.
+func (bqc BitcoinQuorumConfig) Enabled() bool {
+	return len(bqc.Backends)+len(bqc.ElectrumURLs) > 0
+}
+
+// BitcoinFeePolicyConfig defines the policy applied to Bitcoin fee estimates.
+type BitcoinFeePolicyConfig struct {
+	bitcoin.FeePolicyConfig `mapstructure:",squash"`
+	// Sources is the list of backends whose fee estimates are combined. Each
+	// backend is configured in its own section. If not set, fee estimates
+	// are taken from the Bitcoin chain the client is connected to.
+	Sources []string
+}
+
+// Enabled returns true if any fee policy setting is configured.
+func (bfpc BitcoinFeePolicyConfig) Enabled() bool {
+	return len(bfpc.Sources) > 0 ||
+		len(bfpc.StaticFile) > 0 ||
+		bfpc.MinSatPerVByteFee > 0 ||
+		bfpc.MaxSatPerVByteFee > 0 ||
+		bfpc.SmoothingWindow > 1 ||
+		len(bfpc.ConfirmationTargets) > 0
+}
+
+// UsedBackends returns the types of backends used to interact with the
+// Bitcoin chain. If quorum-verified reads are enabled, these are the quorum
+// backends. Otherwise, it is the selected backend.
+func (bc BitcoinConfig) UsedBackends() []BitcoinBackend {
+	if !bc.Quorum.Enabled() {
+		return []BitcoinBackend{bc.SelectedBackend()}
+	}
+
+	backends := make([]BitcoinBackend, len(bc.Quorum.Backends))
+	for i, backend := range bc.Quorum.Backends {
+		backends[i] = BitcoinBackend(backend)
+	}
+
+	return backends
 }
 
 // Bind the flags to the viper configuration. Viper reads configuration from
@@ -144,6 +245,13 @@ func (c *Config) ReadConfig(configFilePath string, flagSet *pflag.FlagSet, categ
 	// Resolve contracts addresses.
 	c.resolveContractsAddresses()
 
+	// Resolve the first block indexed by the event index.
+	if c.EventIndex.StartBlock == 0 {
+		c.EventIndex.StartBlock = chainEthereum.DefaultEventIndexStartBlock(
+			c.Ethereum.Network,
+		)
+	}
+
 	// Resolve network peers.
 	err := c.resolvePeers(clientNetwork)
 	if err != nil {
@@ -151,11 +259,13 @@ func (c *Config) ReadConfig(configFilePath string, flagSet *pflag.FlagSet, categ
 	}
 
 	// Resolve Electrum server.
-	// #nosec G404 (insecure random number source (rand))
-	// Picking up an Electrum server does not require secure randomness.
-	err = c.resolveElectrum(rand.New(rand.NewSource(time.Now().UnixNano())))
-	if err != nil {
-		return fmt.Errorf("failed to resolve Electrum: %w", err)
+	if slices.Contains(c.Bitcoin.UsedBackends(), ElectrumBackend) {
+		// #nosec G404 (insecure random number source (rand))
+		// Picking up an Electrum server does not require secure randomness.
+		err = c.resolveElectrum(rand.New(rand.NewSource(time.Now().UnixNano())))
+		if err != nil {
+			return fmt.Errorf("failed to resolve Electrum: %w", err)
+		}
 	}
 
 	// Validate configuration.
@@ -165,6 +275,10 @@ func (c *Config) ReadConfig(configFilePath string, flagSet *pflag.FlagSet, categ
 
 	// Don't use viper.BindEnv for password reading as it's too sensitive value
 	// to read it with an external library.
+	if c.Storage.Password == "" {
+		c.Storage.Password = os.Getenv(StoragePasswordEnvVariable)
+	}
+
 	if c.Ethereum.Account.KeyFilePassword == "" {
 		c.Ethereum.Account.KeyFilePassword = os.Getenv(EthereumPasswordEnvVariable)
 	}
@@ -210,10 +324,8 @@ func validateConfig(config *Config, categories ...Category) error {
 				))
 			}
 		case BitcoinElectrum:
-			if config.Bitcoin.Electrum.URL == "" {
-				result = multierror.Append(result, fmt.Errorf(
-					"missing value for bitcoin.electrum.url; see bitcoin electrum section in configuration",
-				))
+			for _, err := range validateBitcoinConfig(config.Bitcoin) {
+				result = multierror.Append(result, err)
 			}
 		case Network:
 			if config.LibP2P.Port == 0 {
@@ -227,12 +339,118 @@ func validateConfig(config *Config, categories ...Category) error {
 					"missing value for storage.dir; see storage section in configuration",
 				))
 			}
+		case Tbtc:
+			err := tbtcpg.ValidateDepositSelectionStrategy(
+				config.Tbtc.DepositSweepStrategy,
+			)
+			if err != nil {
+				result = multierror.Append(result, fmt.Errorf(
+					"invalid value for tbtc.depositSweepStrategy: [%w]; "+
+						"see tbtc section in configuration",
+					err,
+				))
+			}
+		case Maintainer:
+			err := profitability.ValidatePolicy(
+				config.Maintainer.Profitability.Policy,
+			)
+			if err != nil {
+				result = multierror.Append(result, fmt.Errorf(
+					"invalid value for profitability.policy: [%w]; see "+
+						"maintainer section in configuration",
+					err,
+				))
+			}
 		}
 	}
 
 	return result.ErrorOrNil()
 }
 
+func validateBitcoinConfig(config BitcoinConfig) []error {
+	var errs []error
+
+	backends := config.UsedBackends()
+	for _, source := range config.FeePolicy.Sources {
+		if !slices.Contains(backends, BitcoinBackend(source)) {
+			backends = append(backends, BitcoinBackend(source))
+		}
+	}
+
+	for _, backend := range backends {
+		switch backend {
+		case ElectrumBackend:
+			if config.Electrum.URL == "" && len(config.Electrum.URLs) == 0 {
+				errs = append(errs, fmt.Errorf(
+					"missing value for bitcoin.electrum.url; see bitcoin electrum section in configuration",
+				))
+			}
+		case BitcoindBackend:
+			if config.Bitcoind.URL == "" {
+				errs = append(errs, fmt.Errorf(
+					"missing value for bitcoin.bitcoind.url; see bitcoin bitcoind section in configuration",
+				))
+			}
+			// The rescan timestamp has no safe default. Rescanning from the
+			// import time hides the history of wallets created before,
+			// so the operator must choose how far back the node rescans.
+			if config.Bitcoind.RescanFromTimestamp <= 0 {
+				errs = append(errs, fmt.Errorf(
+					"missing value for bitcoin.bitcoind.rescanFromTimestamp; see bitcoin bitcoind section in configuration",
+				))
+			}
+		case EsploraBackend:
+			if config.Esplora.URL == "" {
+				errs = append(errs, fmt.Errorf(
+					"missing value for bitcoin.esplora.url; see bitcoin esplora section in configuration",
+				))
+			}
+		default:
+			errs = append(errs, fmt.Errorf(
+				"unsupported Bitcoin backend: [%s]; expected one of: [%s, %s, %s]",
+				backend,
+				ElectrumBackend,
+				BitcoindBackend,
+				EsploraBackend,
+			))
+		}
+	}
+
+	if config.Quorum.Enabled() {
+		backendsCount := len(config.Quorum.Backends) +
+			len(config.Quorum.ElectrumURLs)
+
+		if config.Quorum.Threshold < 0 ||
+			config.Quorum.Threshold > backendsCount {
+			errs = append(errs, fmt.Errorf(
+				"invalid value for bitcoin.quorum.threshold: [%d]; must be in range [0, %d]",
+				config.Quorum.Threshold,
+				backendsCount,
+			))
+		}
+	}
+
+	feePolicy := config.FeePolicy
+	if feePolicy.MinSatPerVByteFee < 0 || feePolicy.MaxSatPerVByteFee < 0 ||
+		(feePolicy.MaxSatPerVByteFee > 0 &&
+			feePolicy.MinSatPerVByteFee > feePolicy.MaxSatPerVByteFee) {
+		errs = append(errs, fmt.Errorf(
+			"invalid values for bitcoin.feePolicy.minSatPerVByteFee: [%d] and bitcoin.feePolicy.maxSatPerVByteFee: [%d]; minimum must not be greater than maximum",
+			feePolicy.MinSatPerVByteFee,
+			feePolicy.MaxSatPerVByteFee,
+		))
+	}
+
+	if feePolicy.SmoothingPercentile < 0 || feePolicy.SmoothingPercentile > 100 {
+		errs = append(errs, fmt.Errorf(
+			"invalid value for bitcoin.feePolicy.smoothingPercentile: [%d]; must be in range [0, 100]",
+			feePolicy.SmoothingPercentile,
+		))
+	}
+
+	return errs
+}
+
 // readConfigFile uses viper to read configuration from a config file. The config file
 // is not mandatory, if the path is
 func readConfigFile(configFilePath string) error {
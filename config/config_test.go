@@ -13,11 +13,15 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"golang.org/x/exp/slices"
 
+	"github.com/keep-network/keep-core/pkg/bitcoin"
+	"github.com/keep-network/keep-core/pkg/bitcoin/bitcoind"
+	"github.com/keep-network/keep-core/pkg/bitcoin/electrum"
 	"github.com/keep-network/keep-core/pkg/chain/ethereum"
 	ethereumBeacon "github.com/keep-network/keep-core/pkg/chain/ethereum/beacon/gen"
 	ethereumEcdsa "github.com/keep-network/keep-core/pkg/chain/ethereum/ecdsa/gen"
 	ethereumTbtc "github.com/keep-network/keep-core/pkg/chain/ethereum/tbtc/gen"
 	ethereumThreshold "github.com/keep-network/keep-core/pkg/chain/ethereum/threshold/gen"
+	"github.com/keep-network/keep-core/pkg/maintainer/profitability"
 )
 
 func TestReadConfigFromFile(t *testing.T) {
@@ -136,6 +140,10 @@ func TestReadConfigFromFile(t *testing.T) {
 			},
 			expectedValue: "0xfdc315b0e608b7cDE9166D9D69a1506779e3E0CA",
 		},
+		"Bitcoin.Backend": {
+			readValueFunc: func(c *Config) interface{} { return c.Bitcoin.Backend },
+			expectedValue: ElectrumBackend,
+		},
 		"Bitcoin.Electrum.URL": {
 			readValueFunc: func(c *Config) interface{} { return c.Bitcoin.Electrum.URL },
 			expectedValue: "ssl://url.to.electrum:18332",
@@ -161,6 +169,62 @@ func TestReadConfigFromFile(t *testing.T) {
 			readValueFunc: func(c *Config) interface{} { return c.Bitcoin.Electrum.KeepAliveInterval },
 			expectedValue: 720 * time.Second,
 		},
+		"Bitcoin.Bitcoind.URL": {
+			readValueFunc: func(c *Config) interface{} { return c.Bitcoin.Bitcoind.URL },
+			expectedValue: "http://url.to.bitcoind:18332",
+		},
+		"Bitcoin.Bitcoind.Username": {
+			readValueFunc: func(c *Config) interface{} { return c.Bitcoin.Bitcoind.Username },
+			expectedValue: "keep",
+		},
+		"Bitcoin.Bitcoind.Password": {
+			readValueFunc: func(c *Config) interface{} { return c.Bitcoin.Bitcoind.Password },
+			expectedValue: "secret",
+		},
+		"Bitcoin.Bitcoind.Wallet": {
+			readValueFunc: func(c *Config) interface{} { return c.Bitcoin.Bitcoind.Wallet },
+			expectedValue: "keep-test",
+		},
+		"Bitcoin.Bitcoind.RescanFromTimestamp": {
+			readValueFunc: func(c *Config) interface{} { return c.Bitcoin.Bitcoind.RescanFromTimestamp },
+			expectedValue: int64(1672531200),
+		},
+		"Bitcoin.Bitcoind.RequestTimeout": {
+			readValueFunc: func(c *Config) interface{} { return c.Bitcoin.Bitcoind.RequestTimeout },
+			expectedValue: 41 * time.Second,
+		},
+		"Bitcoin.Bitcoind.RequestRetryTimeout": {
+			readValueFunc: func(c *Config) interface{} { return c.Bitcoin.Bitcoind.RequestRetryTimeout },
+			expectedValue: 240 * time.Second,
+		},
+		"Bitcoin.Esplora.URL": {
+			readValueFunc: func(c *Config) interface{} { return c.Bitcoin.Esplora.URL },
+			expectedValue: "https://url.to.esplora/api",
+		},
+		"Bitcoin.Esplora.RequestTimeout": {
+			readValueFunc: func(c *Config) interface{} { return c.Bitcoin.Esplora.RequestTimeout },
+			expectedValue: 17 * time.Second,
+		},
+		"Bitcoin.Esplora.RequestRetryTimeout": {
+			readValueFunc: func(c *Config) interface{} { return c.Bitcoin.Esplora.RequestRetryTimeout },
+			expectedValue: 180 * time.Second,
+		},
+		"Bitcoin.FeePolicy.Sources": {
+			readValueFunc: func(c *Config) interface{} { return c.Bitcoin.FeePolicy.Sources },
+			expectedValue: []string{"electrum", "esplora"},
+		},
+		"Bitcoin.FeePolicy.MaxSatPerVByteFee": {
+			readValueFunc: func(c *Config) interface{} { return c.Bitcoin.FeePolicy.MaxSatPerVByteFee },
+			expectedValue: int64(120),
+		},
+		"Bitcoin.FeePolicy.SmoothingWindow": {
+			readValueFunc: func(c *Config) interface{} { return c.Bitcoin.FeePolicy.SmoothingWindow },
+			expectedValue: 6,
+		},
+		"Bitcoin.FeePolicy.ConfirmationTargets": {
+			readValueFunc: func(c *Config) interface{} { return c.Bitcoin.FeePolicy.ConfirmationTargets },
+			expectedValue: map[string]uint32{"depositsweep": 6},
+		},
 		"Network.Port": {
 			readValueFunc: func(c *Config) interface{} { return c.LibP2P.Port },
 			expectedValue: 27001,
@@ -227,6 +291,42 @@ func TestReadConfigFromFile(t *testing.T) {
 			readValueFunc: func(c *Config) interface{} { return c.Maintainer.Spv.IdleBackoffTime },
 			expectedValue: 15 * time.Minute,
 		},
+		"Maintainer.Spv.BitcoinBlockPollInterval": {
+			readValueFunc: func(c *Config) interface{} { return c.Maintainer.Spv.BitcoinBlockPollInterval },
+			expectedValue: 45 * time.Second,
+		},
+		"Maintainer.Watcher.Enabled": {
+			readValueFunc: func(c *Config) interface{} { return c.Maintainer.Watcher.Enabled },
+			expectedValue: true,
+		},
+		"Maintainer.Watcher.NotifyTimeouts": {
+			readValueFunc: func(c *Config) interface{} { return c.Maintainer.Watcher.NotifyTimeouts },
+			expectedValue: true,
+		},
+		"Maintainer.Watcher.HistoryDepth": {
+			readValueFunc: func(c *Config) interface{} { return c.Maintainer.Watcher.HistoryDepth },
+			expectedValue: uint64(75000),
+		},
+		"Maintainer.Watcher.TransactionLimit": {
+			readValueFunc: func(c *Config) interface{} { return c.Maintainer.Watcher.TransactionLimit },
+			expectedValue: 40,
+		},
+		"Maintainer.Watcher.RestartBackoffTime": {
+			readValueFunc: func(c *Config) interface{} { return c.Maintainer.Watcher.RestartBackoffTime },
+			expectedValue: time.Hour,
+		},
+		"Maintainer.Watcher.IdleBackoffTime": {
+			readValueFunc: func(c *Config) interface{} { return c.Maintainer.Watcher.IdleBackoffTime },
+			expectedValue: 5 * time.Minute,
+		},
+		"Maintainer.Profitability.Policy": {
+			readValueFunc: func(c *Config) interface{} { return c.Maintainer.Profitability.Policy },
+			expectedValue: profitability.PolicySkip,
+		},
+		"Maintainer.Profitability.MaxDelay": {
+			readValueFunc: func(c *Config) interface{} { return c.Maintainer.Profitability.MaxDelay },
+			expectedValue: 3 * time.Hour,
+		},
 	}
 
 	for _, filePath := range filePaths {
@@ -404,3 +504,132 @@ func TestReadConfig_ReadContracts(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateBitcoinConfig(t *testing.T) {
+	var tests = map[string]struct {
+		config       BitcoinConfig
+		expectedErrs []error
+	}{
+		"default backend configured": {
+			config: BitcoinConfig{
+				Electrum: electrum.Config{URL: "tcp://electrum:50001"},
+			},
+		},
+		"selected backend not configured": {
+			config: BitcoinConfig{
+				Backend:  EsploraBackend,
+				Electrum: electrum.Config{URL: "tcp://electrum:50001"},
+			},
+			expectedErrs: []error{
+				fmt.Errorf("missing value for bitcoin.esplora.url; see bitcoin esplora section in configuration"),
+			},
+		},
+		"unsupported backend": {
+			config: BitcoinConfig{Backend: "btcd"},
+			expectedErrs: []error{
+				fmt.Errorf("unsupported Bitcoin backend: [btcd]; expected one of: [electrum, bitcoind, esplora]"),
+			},
+		},
+		"quorum backends configured": {
+			config: BitcoinConfig{
+				Backend:  EsploraBackend,
+				Electrum: electrum.Config{URL: "tcp://electrum:50001"},
+				Bitcoind: bitcoind.Config{
+					URL:                 "http://bitcoind:8332",
+					RescanFromTimestamp: 1672531200,
+				},
+				Quorum: BitcoinQuorumConfig{
+					Backends:     []string{"electrum", "bitcoind"},
+					ElectrumURLs: []string{"tcp://electrum2:50001"},
+					Threshold:    3,
+				},
+			},
+		},
+		"bitcoind rescan timestamp not configured": {
+			config: BitcoinConfig{
+				Backend:  BitcoindBackend,
+				Bitcoind: bitcoind.Config{URL: "http://bitcoind:8332"},
+			},
+			expectedErrs: []error{
+				fmt.Errorf("missing value for bitcoin.bitcoind.rescanFromTimestamp; see bitcoin bitcoind section in configuration"),
+			},
+		},
+		"quorum backend not configured": {
+			config: BitcoinConfig{
+				Electrum: electrum.Config{URL: "tcp://electrum:50001"},
+				Quorum: BitcoinQuorumConfig{
+					Backends: []string{"electrum", "bitcoind"},
+				},
+			},
+			expectedErrs: []error{
+				fmt.Errorf("missing value for bitcoin.bitcoind.url; see bitcoin bitcoind section in configuration"),
+				fmt.Errorf("missing value for bitcoin.bitcoind.rescanFromTimestamp; see bitcoin bitcoind section in configuration"),
+			},
+		},
+		"quorum threshold above backends count": {
+			config: BitcoinConfig{
+				Electrum: electrum.Config{URL: "tcp://electrum:50001"},
+				Quorum: BitcoinQuorumConfig{
+					ElectrumURLs: []string{"tcp://electrum2:50001"},
+					Threshold:    2,
+				},
+			},
+			expectedErrs: []error{
+				fmt.Errorf("invalid value for bitcoin.quorum.threshold: [2]; must be in range [0, 1]"),
+			},
+		},
+		"fee policy sources configured": {
+			config: BitcoinConfig{
+				Electrum: electrum.Config{URL: "tcp://electrum:50001"},
+				Bitcoind: bitcoind.Config{
+					URL:                 "http://bitcoind:8332",
+					RescanFromTimestamp: 1672531200,
+				},
+				FeePolicy: BitcoinFeePolicyConfig{
+					Sources: []string{"electrum", "bitcoind"},
+				},
+			},
+		},
+		"fee policy source not configured": {
+			config: BitcoinConfig{
+				Electrum: electrum.Config{URL: "tcp://electrum:50001"},
+				FeePolicy: BitcoinFeePolicyConfig{
+					Sources: []string{"esplora"},
+				},
+			},
+			expectedErrs: []error{
+				fmt.Errorf("missing value for bitcoin.esplora.url; see bitcoin esplora section in configuration"),
+			},
+		},
+		"fee policy invalid clamps and percentile": {
+			config: BitcoinConfig{
+				Electrum: electrum.Config{URL: "tcp://electrum:50001"},
+				FeePolicy: BitcoinFeePolicyConfig{
+					FeePolicyConfig: bitcoin.FeePolicyConfig{
+						MinSatPerVByteFee:   10,
+						MaxSatPerVByteFee:   5,
+						SmoothingPercentile: 101,
+					},
+				},
+			},
+			expectedErrs: []error{
+				fmt.Errorf("invalid values for bitcoin.feePolicy.minSatPerVByteFee: [10] and bitcoin.feePolicy.maxSatPerVByteFee: [5]; minimum must not be greater than maximum"),
+				fmt.Errorf("invalid value for bitcoin.feePolicy.smoothingPercentile: [101]; must be in range [0, 100]"),
+			},
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			errs := validateBitcoinConfig(test.config)
+
+			if !reflect.DeepEqual(test.expectedErrs, errs) {
+				t.Errorf(
+					"unexpected errors\nexpected: %v\nactual:   %v",
+					test.expectedErrs,
+					errs,
+				)
+			}
+		})
+	}
+}
@@ -28,14 +28,15 @@ func readElectrumUrls(network bitcoin.Network) (
 	return urlsStrings, nil
 }
 
-// resolveElectrum checks if Electrum is already configured. If the Electrum URL
-// is empty it reads the Electrum configs from the embedded list for the given
-// network and picks up one randomly.
+// resolveElectrum checks if Electrum is already configured. If neither the
+// Electrum URL nor the additional Electrum URLs are set, it reads the Electrum
+// configs from the embedded list for the given network, picks up one randomly
+// as the primary server and uses the remaining ones for failover.
 func (c *Config) resolveElectrum(rng *rand.Rand) error {
 	network := c.Bitcoin.Network
 
 	// Return if Electrum is already set.
-	if len(c.Bitcoin.Electrum.URL) > 0 {
+	if len(c.Bitcoin.Electrum.URL) > 0 || len(c.Bitcoin.Electrum.URLs) > 0 {
 		return nil
 	}
 
@@ -63,13 +64,27 @@ func (c *Config) resolveElectrum(rng *rand.Rand) error {
 
 	// #nosec G404 (insecure random number source (rand))
 	// Picking up an Electrum server does not require secure randomness.
-	selectedURL := urls[rng.Intn(len(urls))]
+	selectedIndex := rng.Intn(len(urls))
+	selectedURL := urls[selectedIndex]
 
 	logger.Infof("auto-selecting Electrum server: [%v]", selectedURL)
 
-	// Set only the URL in the original config. Other fields may be already set,
-	// and we don't want to override them.
+	// Set only the URLs in the original config. Other fields may be already
+	// set, and we don't want to override them.
 	c.Bitcoin.Electrum.URL = selectedURL
 
+	for i, url := range urls {
+		if i != selectedIndex {
+			c.Bitcoin.Electrum.URLs = append(c.Bitcoin.Electrum.URLs, url)
+		}
+	}
+
+	if len(c.Bitcoin.Electrum.URLs) > 0 {
+		logger.Infof(
+			"using failover Electrum servers: [%v]",
+			c.Bitcoin.Electrum.URLs,
+		)
+	}
+
 	return nil
 }
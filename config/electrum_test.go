@@ -75,6 +75,43 @@ func TestResolveElectrum(t *testing.T) {
 	}
 }
 
+func TestResolveElectrum_AlreadyConfigured(t *testing.T) {
+	var tests = map[string]struct {
+		config electrum.Config
+	}{
+		"URL configured": {
+			config: electrum.Config{
+				URL: "tcp://url.to.electrum:18332",
+			},
+		},
+		"URLs configured": {
+			config: electrum.Config{
+				URLs: []string{
+					"tcp://url.to.electrum1:18332",
+					"ssl://url.to.electrum2:18333",
+				},
+			},
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			cfg := &Config{}
+			cfg.Bitcoin.Network = bitcoin.Mainnet
+			cfg.Bitcoin.Electrum = test.config
+
+			err := cfg.resolveElectrum(rand.New(&fakeRandSource{0}))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if diff := deep.Equal(cfg.Bitcoin.Electrum, test.config); diff != nil {
+				t.Errorf("compare failed: %v", diff)
+			}
+		})
+	}
+}
+
 type fakeRandSource struct {
 	expectedValue int64
 }
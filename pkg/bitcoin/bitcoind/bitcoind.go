@@ -0,0 +1,1112 @@
+package bitcoind
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ipfs/go-log"
+	"go.uber.org/zap"
+
+	"github.com/keep-network/keep-common/pkg/wrappers"
+	"github.com/keep-network/keep-core/pkg/bitcoin"
+	"github.com/keep-network/keep-core/pkg/bitcoin/internal/rawhex"
+)
+
+var logger = log.Logger("keep-bitcoind")
+
+// listTransactionsPageSize is the number of wallet transactions fetched
+// by a single `listtransactions` call.
+const listTransactionsPageSize = 1000
+
+// Connection is a handle for interactions with a bitcoind node.
+type Connection struct {
+	parentCtx context.Context
+	config    Config
+	client    *rpcClient
+	wallet    *rpcClient
+
+	watchedMutex sync.Mutex
+	// watched holds labels of public key hashes and scripts already observed
+	// by the watch-only wallet. It is nil until loaded from the wallet.
+	watched map[string]bool
+	// rescanning holds watched labels whose transactions may not be known
+	// to the wallet yet as the wallet is still rescanning the chain.
+	rescanning map[string]bool
+}
+
+// Connect initializes handle with provided Config.
+func Connect(parentCtx context.Context, config Config) (bitcoin.Chain, error) {
+	if len(config.URL) == 0 {
+		return nil, fmt.Errorf("bitcoind URL not configured")
+	}
+	if len(config.Wallet) == 0 {
+		config.Wallet = DefaultWallet
+	}
+	if config.RequestTimeout == 0 {
+		config.RequestTimeout = DefaultRequestTimeout
+	}
+	if config.RequestRetryTimeout == 0 {
+		config.RequestRetryTimeout = DefaultRequestRetryTimeout
+	}
+
+	client := newRPCClient(config.URL, config.Username, config.Password)
+
+	c := &Connection{
+		parentCtx: parentCtx,
+		config:    config,
+		client:    client,
+		wallet:    client.wallet(config.Wallet),
+	}
+
+	if err := c.verifyNode(); err != nil {
+		return nil, fmt.Errorf("failed to verify bitcoind node: [%w]", err)
+	}
+
+	if err := c.loadWallet(); err != nil {
+		return nil, fmt.Errorf(
+			"failed to load wallet [%s]: [%w]",
+			config.Wallet,
+			err,
+		)
+	}
+
+	return c, nil
+}
+
+// verifyNode checks the version and the chain of the bitcoind node.
+func (c *Connection) verifyNode() error {
+	type networkInfo struct {
+		Version    int    `json:"version"`
+		SubVersion string `json:"subversion"`
+	}
+
+	info, err := requestWithRetry(
+		c,
+		func(ctx context.Context) (*networkInfo, error) {
+			var result networkInfo
+			err := c.client.call(ctx, "getnetworkinfo", &result)
+			return &result, err
+		},
+		"getnetworkinfo",
+	)
+	if err != nil {
+		return fmt.Errorf("failed to get network info: [%w]", err)
+	}
+
+	type blockchainInfo struct {
+		Chain                string `json:"chain"`
+		InitialBlockDownload bool   `json:"initialblockdownload"`
+	}
+
+	chainInfo, err := requestWithRetry(
+		c,
+		func(ctx context.Context) (*blockchainInfo, error) {
+			var result blockchainInfo
+			err := c.client.call(ctx, "getblockchaininfo", &result)
+			return &result, err
+		},
+		"getblockchaininfo",
+	)
+	if err != nil {
+		return fmt.Errorf("failed to get blockchain info: [%w]", err)
+	}
+
+	logger.Infof(
+		"connected to bitcoind node [%s] [version: [%d], subversion: [%s], chain: [%s]]",
+		c.config.URL,
+		info.Version,
+		info.SubVersion,
+		chainInfo.Chain,
+	)
+
+	if chainInfo.InitialBlockDownload {
+		logger.Warnf(
+			"bitcoind node [%s] is in the initial block download; "+
+				"returned data may be outdated",
+			c.config.URL,
+		)
+	}
+
+	return nil
+}
+
+// GetTransaction gets the transaction with the given transaction hash.
+// If the transaction with the given hash was not found on the chain,
+// this function returns an error.
+func (c *Connection) GetTransaction(
+	transactionHash bitcoin.Hash,
+) (*bitcoin.Transaction, error) {
+	txID := transactionHash.Hex(bitcoin.ReversedByteOrder)
+
+	rawTransaction, err := requestWithRetry(
+		c,
+		func(ctx context.Context) (string, error) {
+			var result string
+			err := c.client.call(ctx, "getrawtransaction", &result, txID, false)
+			if err != nil && hasRPCErrorCode(err, rpcInvalidAddressOrKey) {
+				// Without the `-txindex` option, the node knows only
+				// mempool transactions. Fall back to the watch-only wallet
+				// which knows all transactions of observed public key
+				// hashes.
+				var walletTx walletTransaction
+				err = c.wallet.call(ctx, "gettransaction", &walletTx, txID, true)
+				result = walletTx.Hex
+			}
+
+			return result, err
+		},
+		"getrawtransaction",
+	)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to get raw transaction with ID [%s]: [%w]",
+			txID,
+			err,
+		)
+	}
+
+	result, err := rawhex.ConvertRawTransaction(rawTransaction)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert transaction: [%w]", err)
+	}
+
+	return result, nil
+}
+
+// GetTransactionConfirmations gets the number of confirmations for the
+// transaction with the given transaction hash. If the transaction with the
+// given hash was not found on the chain, this function returns an error.
+func (c *Connection) GetTransactionConfirmations(
+	transactionHash bitcoin.Hash,
+) (uint, error) {
+	txID := transactionHash.Hex(bitcoin.ReversedByteOrder)
+
+	confirmations, err := requestWithRetry(
+		c,
+		func(ctx context.Context) (int64, error) {
+			var result struct {
+				Confirmations int64 `json:"confirmations"`
+			}
+			err := c.client.call(ctx, "getrawtransaction", &result, txID, true)
+			if err != nil && hasRPCErrorCode(err, rpcInvalidAddressOrKey) {
+				// Fall back to the watch-only wallet. See GetTransaction.
+				err = c.wallet.call(ctx, "gettransaction", &result, txID, true)
+			}
+
+			return result.Confirmations, err
+		},
+		"getrawtransaction",
+	)
+	if err != nil {
+		return 0, fmt.Errorf(
+			"failed to get raw transaction with ID [%s]: [%w]",
+			txID,
+			err,
+		)
+	}
+
+	// The wallet returns a negative number of confirmations for transactions
+	// conflicting with the chain. Mempool transactions have no confirmations.
+	if confirmations < 0 {
+		return 0, nil
+	}
+
+	return uint(confirmations), nil
+}
+
+// BroadcastTransaction broadcasts the given transaction over the
+// network of the Bitcoin chain nodes. If the broadcast action could not be
+// done, this function returns an error. This function does not give any
+// guarantees regarding transaction mining. The transaction may be mined or
+// rejected eventually.
+func (c *Connection) BroadcastTransaction(
+	transaction *bitcoin.Transaction,
+) error {
+	rawTx := hex.EncodeToString(transaction.Serialize())
+
+	rawTxLogger := logger.With(
+		zap.String("rawTx", rawTx),
+	)
+	rawTxLogger.Debugf("broadcasting transaction")
+
+	response, err := requestWithRetry(
+		c,
+		func(ctx context.Context) (string, error) {
+			var result string
+			err := c.client.call(ctx, "sendrawtransaction", &result, rawTx)
+			return result, err
+		},
+		"sendrawtransaction",
+	)
+	if err != nil {
+		return fmt.Errorf("failed to broadcast the transaction: [%w]", err)
+	}
+
+	rawTxLogger.Infof("transaction broadcast successful: [%s]", response)
+
+	return nil
+}
+
+// GetLatestBlockHeight gets the height of the latest block (tip). If the
+// latest block was not determined, this function returns an error.
+func (c *Connection) GetLatestBlockHeight() (uint, error) {
+	blockHeight, err := requestWithRetry(
+		c,
+		func(ctx context.Context) (uint, error) {
+			var result uint
+			err := c.client.call(ctx, "getblockcount", &result)
+			return result, err
+		},
+		"getblockcount",
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get block count: [%w]", err)
+	}
+
+	return blockHeight, nil
+}
+
+// GetBlockHeader gets the block header for the given block height. If the
+// block with the given height was not found on the chain, this function
+// returns an error.
+func (c *Connection) GetBlockHeader(
+	blockHeight uint,
+) (*bitcoin.BlockHeader, error) {
+	blockHash, err := c.getBlockHash(blockHeight)
+	if err != nil {
+		return nil, err
+	}
+
+	rawBlockHeader, err := requestWithRetry(
+		c,
+		func(ctx context.Context) (string, error) {
+			var result string
+			err := c.client.call(ctx, "getblockheader", &result, blockHash, false)
+			return result, err
+		},
+		"getblockheader",
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get block header: [%w]", err)
+	}
+
+	blockHeader, err := rawhex.ConvertBlockHeader(rawBlockHeader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert block header: [%w]", err)
+	}
+
+	return blockHeader, nil
+}
+
+// getBlockHash gets the hash of the block with the given height, in the
+// RPC byte order.
+func (c *Connection) getBlockHash(blockHeight uint) (string, error) {
+	blockHash, err := requestWithRetry(
+		c,
+		func(ctx context.Context) (string, error) {
+			var result string
+			err := c.client.call(ctx, "getblockhash", &result, blockHeight)
+			return result, err
+		},
+		"getblockhash",
+	)
+	if err != nil {
+		return "", fmt.Errorf(
+			"failed to get hash of block [%d]: [%w]",
+			blockHeight,
+			err,
+		)
+	}
+
+	return blockHash, nil
+}
+
+// GetTransactionMerkleProof gets the Merkle proof for a given transaction.
+// The transaction's hash and the block the transaction was included in the
+// blockchain need to be provided.
+func (c *Connection) GetTransactionMerkleProof(
+	transactionHash bitcoin.Hash,
+	blockHeight uint,
+) (*bitcoin.TransactionMerkleProof, error) {
+	txID := transactionHash.Hex(bitcoin.ReversedByteOrder)
+
+	blockHash, err := c.getBlockHash(blockHeight)
+	if err != nil {
+		return nil, err
+	}
+
+	rawProof, err := requestWithRetry(
+		c,
+		func(ctx context.Context) (string, error) {
+			var result string
+			err := c.client.call(
+				ctx,
+				"gettxoutproof",
+				&result,
+				[]string{txID},
+				blockHash,
+			)
+			return result, err
+		},
+		"gettxoutproof",
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get merkle proof: [%w]", err)
+	}
+
+	proof, err := parseTxOutProof(rawProof, transactionHash, blockHeight)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse merkle proof: [%w]", err)
+	}
+
+	return proof, nil
+}
+
+// GetTransactionsForPublicKeyHash gets confirmed transactions that pays the
+// given public key hash using either a P2PKH or P2WPKH script. The returned
+// transactions are ordered by block height in the ascending order, i.e.
+// the latest transaction is at the end of the list. The returned list does
+// not contain unconfirmed transactions living in the mempool at the moment
+// of request. The returned transactions list can be limited using the
+// `limit` parameter. For example, if `limit` is set to `5`, only the
+// latest five transactions will be returned. Note that taking an unlimited
+// transaction history may be time-consuming as this function fetches
+// complete transactions with all necessary data.
+func (c *Connection) GetTransactionsForPublicKeyHash(
+	publicKeyHash [20]byte,
+	limit int,
+) ([]*bitcoin.Transaction, error) {
+	txHashes, err := c.GetTxHashesForPublicKeyHash(publicKeyHash)
+	if err != nil {
+		return nil, err
+	}
+
+	var selectedTxHashes []bitcoin.Hash
+	if len(txHashes) > limit {
+		selectedTxHashes = txHashes[len(txHashes)-limit:]
+	} else {
+		selectedTxHashes = txHashes
+	}
+
+	transactions := make([]*bitcoin.Transaction, len(selectedTxHashes))
+	for i, txHash := range selectedTxHashes {
+		transaction, err := c.GetTransaction(txHash)
+		if err != nil {
+			return nil, fmt.Errorf("cannot get transaction: [%v]", err)
+		}
+
+		transactions[i] = transaction
+	}
+
+	return transactions, nil
+}
+
+// GetTxHashesForPublicKeyHash gets hashes of confirmed transactions that pays
+// the given public key hash using either a P2PKH or P2WPKH script. The returned
+// transactions hashes are ordered by block height in the ascending order, i.e.
+// the latest transaction hash is at the end of the list. The returned list does
+// not contain unconfirmed transactions hashes living in the mempool at the
+// moment of request.
+func (c *Connection) GetTxHashesForPublicKeyHash(
+	publicKeyHash [20]byte,
+) ([]bitcoin.Hash, error) {
+	items, err := c.getPublicKeyHashHistory(publicKeyHash)
+	if err != nil {
+		return nil, err
+	}
+
+	txHashes := make([]bitcoin.Hash, 0)
+	for _, item := range items {
+		if item.confirmations > 0 {
+			txHashes = append(txHashes, item.txHash)
+		}
+	}
+
+	return txHashes, nil
+}
+
+// GetCoinbaseTxHash gets the hash of the coinbase transaction for the given
+// block height.
+func (c *Connection) GetCoinbaseTxHash(blockHeight uint) (bitcoin.Hash, error) {
+	blockHash, err := c.getBlockHash(blockHeight)
+	if err != nil {
+		return bitcoin.Hash{}, err
+	}
+
+	txHashString, err := requestWithRetry(
+		c,
+		func(ctx context.Context) (string, error) {
+			var result struct {
+				Tx []string `json:"tx"`
+			}
+			if err := c.client.call(ctx, "getblock", &result, blockHash, 1); err != nil {
+				return "", err
+			}
+
+			if len(result.Tx) == 0 {
+				return "", fmt.Errorf("block has no transactions")
+			}
+
+			return result.Tx[0], nil
+		},
+		"getblock",
+	)
+	if err != nil {
+		return bitcoin.Hash{}, fmt.Errorf(
+			"failed to get coinbase tx hash for block height [%v]: [%v]",
+			blockHeight,
+			err,
+		)
+	}
+
+	txHash, err := bitcoin.NewHashFromString(
+		txHashString,
+		bitcoin.ReversedByteOrder,
+	)
+	if err != nil {
+		return bitcoin.Hash{}, fmt.Errorf(
+			"cannot parse hash [%s]: [%v]",
+			txHashString,
+			err,
+		)
+	}
+
+	return txHash, nil
+}
+
+// GetSpendingTxHash gets the hash of the confirmed transaction spending
+// the given transaction outpoint. The returned boolean flag is false if
+// the outpoint is not spent by any confirmed transaction. Transactions
+// living in the mempool at the moment of request are not taken into
+// account. If the transaction holding the outpoint was not found on the
+// chain, this function returns an error.
+//
+// The node does not index spending transactions so the script of the
+// outpoint is imported to the watch-only wallet, with a rescan starting
+// at the block holding the outpoint, and the spending transaction is
+// looked up among outgoing transactions of the wallet.
+func (c *Connection) GetSpendingTxHash(
+	outpoint *bitcoin.TransactionOutpoint,
+) (bitcoin.Hash, bool, error) {
+	txID := outpoint.TransactionHash.Hex(bitcoin.ReversedByteOrder)
+
+	unspent, err := requestWithRetry(
+		c,
+		func(ctx context.Context) (bool, error) {
+			// The result is null if the output is spent by a confirmed
+			// transaction, as mempool is excluded, or if it does not
+			// belong to a confirmed transaction.
+			var result *struct {
+				Confirmations int64 `json:"confirmations"`
+			}
+			err := c.client.call(
+				ctx,
+				"gettxout",
+				&result,
+				txID,
+				outpoint.OutputIndex,
+				false,
+			)
+			return result != nil, err
+		},
+		"gettxout",
+	)
+	if err != nil {
+		return bitcoin.Hash{}, false, fmt.Errorf(
+			"failed to get output [%d] of transaction with ID [%s]: [%w]",
+			outpoint.OutputIndex,
+			txID,
+			err,
+		)
+	}
+
+	if unspent {
+		return bitcoin.Hash{}, false, nil
+	}
+
+	funding, err := requestWithRetry(
+		c,
+		func(ctx context.Context) (*walletTransaction, error) {
+			var result walletTransaction
+			err := c.client.call(ctx, "getrawtransaction", &result, txID, true)
+			if err != nil && hasRPCErrorCode(err, rpcInvalidAddressOrKey) {
+				// Fall back to the watch-only wallet. See GetTransaction.
+				err = c.wallet.call(ctx, "gettransaction", &result, txID, true)
+			}
+
+			return &result, err
+		},
+		"getrawtransaction",
+	)
+	if err != nil {
+		return bitcoin.Hash{}, false, fmt.Errorf(
+			"failed to get raw transaction with ID [%s]: [%w]",
+			txID,
+			err,
+		)
+	}
+
+	// Outputs of mempool transactions and transactions conflicting with
+	// the chain cannot be spent by confirmed transactions.
+	if funding.Confirmations <= 0 {
+		return bitcoin.Hash{}, false, nil
+	}
+
+	transaction, err := rawhex.ConvertRawTransaction(funding.Hex)
+	if err != nil {
+		return bitcoin.Hash{}, false, fmt.Errorf(
+			"failed to convert transaction: [%w]",
+			err,
+		)
+	}
+
+	if int(outpoint.OutputIndex) >= len(transaction.Outputs) {
+		return bitcoin.Hash{}, false, fmt.Errorf(
+			"transaction [%s] has no output [%d]",
+			txID,
+			outpoint.OutputIndex,
+		)
+	}
+
+	script := transaction.Outputs[outpoint.OutputIndex].PublicKeyScript
+	if err := c.watchScripts(
+		hex.EncodeToString(script),
+		funding.BlockTime,
+		script,
+	); err != nil {
+		return bitcoin.Hash{}, false, fmt.Errorf(
+			"cannot watch script [0x%x]: [%v]",
+			script,
+			err,
+		)
+	}
+
+	entries, err := c.listWalletTransactions()
+	if err != nil {
+		return bitcoin.Hash{}, false, fmt.Errorf(
+			"failed to list transactions for script [0x%x]: [%v]",
+			script,
+			err,
+		)
+	}
+
+	outpoints := map[string]bool{
+		fmt.Sprintf("%s:%d", txID, outpoint.OutputIndex): true,
+	}
+
+	checked := make(map[string]bool)
+	for _, entry := range entries {
+		if entry.Category != "send" || entry.Confirmations <= 0 ||
+			entry.BlockTime < funding.BlockTime || checked[entry.TxID] {
+			continue
+		}
+		checked[entry.TxID] = true
+
+		spends, err := c.spendsOutpoints(entry.TxID, outpoints)
+		if err != nil {
+			return bitcoin.Hash{}, false, err
+		}
+
+		if !spends {
+			continue
+		}
+
+		txHash, err := bitcoin.NewHashFromString(
+			entry.TxID,
+			bitcoin.ReversedByteOrder,
+		)
+		if err != nil {
+			return bitcoin.Hash{}, false, fmt.Errorf(
+				"cannot parse hash [%s]: [%v]",
+				entry.TxID,
+				err,
+			)
+		}
+
+		return txHash, true, nil
+	}
+
+	return bitcoin.Hash{}, false, nil
+}
+
+// GetMempoolForPublicKeyHash gets the unconfirmed mempool transactions
+// that pays the given public key hash using either a P2PKH or P2WPKH script.
+// The returned transactions are in an indefinite order.
+func (c *Connection) GetMempoolForPublicKeyHash(
+	publicKeyHash [20]byte,
+) ([]*bitcoin.Transaction, error) {
+	items, err := c.getPublicKeyHashHistory(publicKeyHash)
+	if err != nil {
+		return nil, err
+	}
+
+	transactions := make([]*bitcoin.Transaction, 0)
+	for _, item := range items {
+		if item.confirmations != 0 {
+			continue
+		}
+
+		transaction, err := c.GetTransaction(item.txHash)
+		if err != nil {
+			return nil, fmt.Errorf("cannot get transaction: [%v]", err)
+		}
+
+		transactions = append(transactions, transaction)
+	}
+
+	return transactions, nil
+}
+
+// GetUtxosForPublicKeyHash gets unspent outputs of confirmed transactions that
+// are controlled by the given public key hash (either a P2PKH or P2WPKH script).
+// The returned UTXOs are ordered by block height in the ascending order, i.e.
+// the latest UTXO is at the end of the list. The returned list does not contain
+// unspent outputs of unconfirmed transactions living in the mempool at the
+// moment of request. Outputs used as inputs of confirmed or mempool
+// transactions are not returned as well because they are no longer UTXOs.
+func (c *Connection) GetUtxosForPublicKeyHash(
+	publicKeyHash [20]byte,
+) ([]*bitcoin.UnspentTransactionOutput, error) {
+	return c.getPublicKeyHashUtxos(publicKeyHash, true)
+}
+
+// GetMempoolUtxosForPublicKeyHash gets unspent outputs of unconfirmed transactions
+// that are controlled by the given public key hash (either a P2PKH or P2WPKH script).
+// The returned UTXOs are in an indefinite order. The returned list does not
+// contain unspent outputs of confirmed transactions. Outputs used as inputs of
+// confirmed or mempool transactions are not returned as well because they are
+// no longer UTXOs.
+func (c *Connection) GetMempoolUtxosForPublicKeyHash(
+	publicKeyHash [20]byte,
+) ([]*bitcoin.UnspentTransactionOutput, error) {
+	return c.getPublicKeyHashUtxos(publicKeyHash, false)
+}
+
+// EstimateSatPerVByteFee returns the estimated sat/vbyte fee for a
+// transaction to be confirmed within the given number of blocks.
+func (c *Connection) EstimateSatPerVByteFee(blocks uint32) (int64, error) {
+	type estimateSmartFeeResult struct {
+		// FeeRate is the estimated fee rate in BTC/kvB. It is not set
+		// if the node does not have enough information to make an estimate.
+		FeeRate *float64 `json:"feerate"`
+		Errors  []string `json:"errors"`
+	}
+
+	estimate, err := requestWithRetry(
+		c,
+		func(ctx context.Context) (*estimateSmartFeeResult, error) {
+			var result estimateSmartFeeResult
+			err := c.client.call(ctx, "estimatesmartfee", &result, blocks)
+			return &result, err
+		},
+		"estimatesmartfee",
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to estimate fee: [%v]", err)
+	}
+
+	if estimate.FeeRate == nil {
+		return 0, fmt.Errorf(
+			"node does not have enough information to make an estimate: [%s]",
+			strings.Join(estimate.Errors, "; "),
+		)
+	}
+
+	return convertBtcKbToSatVByte(*estimate.FeeRate), nil
+}
+
+func convertBtcKbToSatVByte(btcPerKbFee float64) int64 {
+	// To convert from BTC/KB to sat/vbyte, we need to multiply by 1e8/1e3.
+	satPerVByte := (1e8 / 1e3) * btcPerKbFee
+	// Make sure the minimum returned sat/vbyte fee is always 1.
+	satPerVByte = math.Max(satPerVByte, 1)
+	// Round the returned fee to be an integer.
+	return int64(math.Round(satPerVByte))
+}
+
+func requestWithRetry[K interface{}](
+	c *Connection,
+	requestFn func(ctx context.Context) (K, error),
+	requestName string,
+) (K, error) {
+	startTime := time.Now()
+	logger.Debugf("starting [%s] request to bitcoind node", requestName)
+
+	var result K
+	var permanentErr error
+
+	err := wrappers.DoWithDefaultRetry(
+		c.parentCtx,
+		c.config.RequestRetryTimeout,
+		func(ctx context.Context) error {
+			requestCtx, requestCancel := context.WithTimeout(ctx, c.config.RequestTimeout)
+			defer requestCancel()
+
+			r, err := requestFn(requestCtx)
+			if err != nil {
+				if isPermanentErr(err) {
+					// There is no point in retrying the request and
+					// losing time.
+					permanentErr = err
+					return nil
+				}
+
+				return fmt.Errorf("request failed: [%w]", err)
+			}
+
+			result = r
+			return nil
+		})
+	if err == nil {
+		err = permanentErr
+	}
+
+	solveRequestOutcome := func(err error) string {
+		if err != nil {
+			return fmt.Sprintf("error: [%v]", err)
+		}
+		return "success"
+	}
+
+	logger.Debugf("[%s] request to bitcoind node completed with [%s] after [%s]",
+		requestName,
+		solveRequestOutcome(err),
+		time.Since(startTime),
+	)
+
+	return result, err
+}
+
+// walletTransaction is a transaction returned by the `gettransaction` call.
+type walletTransaction struct {
+	Hex           string `json:"hex"`
+	Confirmations int64  `json:"confirmations"`
+	BlockTime     int64  `json:"blocktime"`
+}
+
+// convertBtcToSatoshi converts the given amount expressed in BTC, as
+// returned by the node, to satoshi.
+func convertBtcToSatoshi(amount json.Number) (int64, error) {
+	btc, err := amount.Float64()
+	if err != nil {
+		return 0, err
+	}
+
+	return int64(math.Round(btc * 1e8)), nil
+}
+
+type historyItem struct {
+	txHash        bitcoin.Hash
+	blockHeight   uint
+	confirmations int64
+}
+
+// getPublicKeyHashHistory returns the history of transactions paying or
+// spending from the given public key hash, as seen by the watch-only wallet.
+// The returned list contains both confirmed and mempool transactions and is
+// sorted by the block height in the ascending order, with mempool
+// transactions at the end of the list. Transactions conflicting with the
+// chain are not returned.
+//
+// Wallet entries are labeled only on the receiving side so transactions
+// spending outputs of the public key hash without paying it back are found
+// by inspecting inputs of the wallet's outgoing transactions. This requires
+// fetching each outgoing transaction not paying the public key hash.
+func (c *Connection) getPublicKeyHashHistory(
+	publicKeyHash [20]byte,
+) ([]*historyItem, error) {
+	label, err := c.watchPublicKeyHash(publicKeyHash)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"cannot watch public key hash [0x%x]: [%v]",
+			publicKeyHash,
+			err,
+		)
+	}
+
+	entries, err := c.listWalletTransactions()
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to list transactions for public key hash [0x%x]: [%v]",
+			publicKeyHash,
+			err,
+		)
+	}
+
+	involved := make(map[string]bool)
+	ownedOutpoints := make(map[string]bool)
+	for _, entry := range entries {
+		if entry.Category == "receive" && entry.Label == label {
+			involved[entry.TxID] = true
+			ownedOutpoints[fmt.Sprintf("%s:%d", entry.TxID, entry.Vout)] = true
+		}
+	}
+
+	checked := make(map[string]bool)
+	for _, entry := range entries {
+		if entry.Category != "send" || involved[entry.TxID] ||
+			checked[entry.TxID] {
+			continue
+		}
+		checked[entry.TxID] = true
+
+		spends, err := c.spendsOutpoints(entry.TxID, ownedOutpoints)
+		if err != nil {
+			return nil, err
+		}
+
+		involved[entry.TxID] = spends
+	}
+
+	seen := make(map[string]bool)
+	items := make([]*historyItem, 0)
+	for _, entry := range entries {
+		// A transaction paying both P2PKH and P2WPKH outputs, or
+		// multiple outputs, is listed multiple times.
+		if !involved[entry.TxID] || seen[entry.TxID] ||
+			entry.Confirmations < 0 {
+			continue
+		}
+		seen[entry.TxID] = true
+
+		txHash, err := bitcoin.NewHashFromString(
+			entry.TxID,
+			bitcoin.ReversedByteOrder,
+		)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"cannot parse hash [%s]: [%v]",
+				entry.TxID,
+				err,
+			)
+		}
+
+		items = append(
+			items, &historyItem{
+				txHash:        txHash,
+				blockHeight:   entry.BlockHeight,
+				confirmations: entry.Confirmations,
+			},
+		)
+	}
+
+	sort.SliceStable(
+		items,
+		func(i, j int) bool {
+			// Mempool transactions have no block height so make sure
+			// they land at the end of the list.
+			if (items[i].confirmations == 0) != (items[j].confirmations == 0) {
+				return items[j].confirmations == 0
+			}
+
+			return items[i].blockHeight < items[j].blockHeight
+		},
+	)
+
+	return items, nil
+}
+
+// listTransactionsItem is a single entry returned by the `listtransactions`
+// call.
+type listTransactionsItem struct {
+	TxID          string `json:"txid"`
+	Category      string `json:"category"`
+	Label         string `json:"label"`
+	Vout          uint32 `json:"vout"`
+	Confirmations int64  `json:"confirmations"`
+	BlockHeight   uint   `json:"blockheight"`
+	BlockTime     int64  `json:"blocktime"`
+}
+
+// listWalletTransactions returns all entries of the watch-only wallet,
+// both incoming and outgoing.
+func (c *Connection) listWalletTransactions() ([]*listTransactionsItem, error) {
+	entries := make([]*listTransactionsItem, 0)
+
+	for skip := 0; ; skip += listTransactionsPageSize {
+		page, err := requestWithRetry(
+			c,
+			func(ctx context.Context) ([]*listTransactionsItem, error) {
+				var result []*listTransactionsItem
+				err := c.wallet.call(
+					ctx,
+					"listtransactions",
+					&result,
+					"*",
+					listTransactionsPageSize,
+					skip,
+					true,
+				)
+				return result, err
+			},
+			"listtransactions",
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, page...)
+
+		if len(page) < listTransactionsPageSize {
+			break
+		}
+	}
+
+	return entries, nil
+}
+
+// spendsOutpoints checks whether the transaction with the given ID spends
+// any of the given outpoints, identified by the `<txid>:<vout>` key.
+func (c *Connection) spendsOutpoints(
+	txID string,
+	outpoints map[string]bool,
+) (bool, error) {
+	txHash, err := bitcoin.NewHashFromString(txID, bitcoin.ReversedByteOrder)
+	if err != nil {
+		return false, fmt.Errorf("cannot parse hash [%s]: [%v]", txID, err)
+	}
+
+	transaction, err := c.GetTransaction(txHash)
+	if err != nil {
+		return false, fmt.Errorf("cannot get transaction: [%v]", err)
+	}
+
+	for _, input := range transaction.Inputs {
+		key := fmt.Sprintf(
+			"%s:%d",
+			input.Outpoint.TransactionHash.Hex(bitcoin.ReversedByteOrder),
+			input.Outpoint.OutputIndex,
+		)
+		if outpoints[key] {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// getPublicKeyHashUtxos returns unspent outputs of confirmed/unconfirmed
+// transactions that are controlled by the given public key hash, as seen
+// by the watch-only wallet.
+//
+// If the `confirmed` flag is true, the returned list contains unspent outputs
+// of confirmed transactions, sorted by the block height in the ascending order,
+// i.e. the latest UTXO is at the end of the list.
+//
+// If the `confirmed` flag is false, the returned list contains unspent outputs
+// of unconfirmed transactions, in an indefinite order.
+//
+// In both cases, the resulted list DOES NOT CONTAIN outputs already used as
+// inputs of confirmed or mempool transactions because they are no longer UTXOs.
+func (c *Connection) getPublicKeyHashUtxos(
+	publicKeyHash [20]byte,
+	confirmed bool,
+) ([]*bitcoin.UnspentTransactionOutput, error) {
+	label, err := c.watchPublicKeyHash(publicKeyHash)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"cannot watch public key hash [0x%x]: [%v]",
+			publicKeyHash,
+			err,
+		)
+	}
+
+	minConfirmations, maxConfirmations := 1, math.MaxInt32
+	if !confirmed {
+		minConfirmations, maxConfirmations = 0, 0
+	}
+
+	type listUnspentItem struct {
+		TxID          string      `json:"txid"`
+		Vout          uint32      `json:"vout"`
+		Label         string      `json:"label"`
+		Amount        json.Number `json:"amount"`
+		Confirmations int64       `json:"confirmations"`
+	}
+
+	items, err := requestWithRetry(
+		c,
+		func(ctx context.Context) ([]*listUnspentItem, error) {
+			var result []*listUnspentItem
+			err := c.wallet.call(
+				ctx,
+				"listunspent",
+				&result,
+				minConfirmations,
+				maxConfirmations,
+				[]string{},
+				// Unconfirmed outputs of transactions not created by the
+				// wallet are considered unsafe and must be included
+				// explicitly.
+				true,
+			)
+			return result, err
+		},
+		"listunspent",
+	)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to list UTXOs for public key hash [0x%x]: [%v]",
+			publicKeyHash,
+			err,
+		)
+	}
+
+	filteredItems := make([]*listUnspentItem, 0)
+	for _, item := range items {
+		if item.Label == label {
+			filteredItems = append(filteredItems, item)
+		}
+	}
+
+	if confirmed {
+		// The more confirmations the lower the block height.
+		sort.SliceStable(
+			filteredItems,
+			func(i, j int) bool {
+				return filteredItems[i].Confirmations > filteredItems[j].Confirmations
+			},
+		)
+	}
+
+	utxos := make([]*bitcoin.UnspentTransactionOutput, len(filteredItems))
+	for i, item := range filteredItems {
+		txHash, err := bitcoin.NewHashFromString(
+			item.TxID,
+			bitcoin.ReversedByteOrder,
+		)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"cannot parse hash [%s]: [%v]",
+				item.TxID,
+				err,
+			)
+		}
+
+		value, err := convertBtcToSatoshi(item.Amount)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"cannot parse amount [%s]: [%v]",
+				item.Amount,
+				err,
+			)
+		}
+
+		utxos[i] = &bitcoin.UnspentTransactionOutput{
+			Outpoint: &bitcoin.TransactionOutpoint{
+				TransactionHash: txHash,
+				OutputIndex:     item.Vout,
+			},
+			Value: value,
+		}
+	}
+
+	return utxos, nil
+}
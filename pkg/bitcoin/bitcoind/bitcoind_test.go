@@ -0,0 +1,926 @@
+package bitcoind
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/keep-network/keep-core/internal/testutils"
+	"github.com/keep-network/keep-core/pkg/bitcoin"
+)
+
+const (
+	fixturesFile = "testdata/rpc_fixtures.json"
+
+	watchedPublicKeyHash = "8db50eb52063ea9d98b3eac91489a90f738986f6"
+	newPublicKeyHash     = "e257eccafbc07c381642ce6e7e55120fb077fbed"
+
+	mempoolTxHash  = "435d4aff6d4bc34134877bd3213c17970142fdd04d4113d534120033b9eecb2e"
+	walletTxHash   = "4a5e1e4baab89f3a32518a88c31bc87f618f76673e2cc77ab2127b7afdeda33b"
+	blockTxHash    = "0e3e2357e806b6cdb1f70b54c3a3a17b6714ee1f0e68bebb44a74b1efd512098"
+	conflictTxHash = "9b0fc92260312ce44e74ef369f5c66bbb85848f2eddd5a7a1cde251e54ccfdd5"
+	missingTxHash  = "b0f1eb2e56e3ccdd3a68cf1cc5f0b1a4ee5bbf8b0a9cd1e2d1a7c88a1e0d3f44"
+)
+
+// rpcFixture is a recorded response of the bitcoind node to the JSON-RPC
+// call with the given method and parameters.
+type rpcFixture struct {
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  *rpcError       `json:"error,omitempty"`
+
+	// Times limits the number of calls the fixture is used for. Unlimited
+	// if zero.
+	Times int `json:"-"`
+	// Delay delays the response.
+	Delay time.Duration `json:"-"`
+}
+
+// fakeNode is a fake bitcoind node serving recorded JSON-RPC responses.
+type fakeNode struct {
+	t        *testing.T
+	server   *httptest.Server
+	fixtures []*rpcFixture
+
+	mutex sync.Mutex
+	calls []string
+	uses  map[*rpcFixture]int
+}
+
+func newFakeNode(t *testing.T, overrides ...*rpcFixture) *fakeNode {
+	fixturesJSON, err := os.ReadFile(fixturesFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var fixtures []*rpcFixture
+	if err := json.Unmarshal(fixturesJSON, &fixtures); err != nil {
+		t.Fatal(err)
+	}
+
+	fn := &fakeNode{
+		t:        t,
+		fixtures: append(overrides, fixtures...),
+		uses:     make(map[*rpcFixture]int),
+	}
+	fn.server = httptest.NewServer(http.HandlerFunc(fn.handle))
+	t.Cleanup(fn.server.Close)
+
+	return fn
+}
+
+func (fn *fakeNode) handle(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		ID     uint64          `json:"id"`
+		Method string          `json:"method"`
+		Params json.RawMessage `json:"params"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		fn.t.Errorf("cannot decode request: [%v]", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	fn.mutex.Lock()
+	fn.calls = append(fn.calls, r.URL.Path+" "+request.Method)
+	fn.mutex.Unlock()
+
+	response := map[string]interface{}{"id": request.ID}
+
+	fixture := fn.findFixture(request.Method, request.Params)
+	if fixture != nil && fixture.Delay > 0 {
+		select {
+		case <-time.After(fixture.Delay):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	if fixture == nil {
+		fn.t.Errorf(
+			"unexpected call [%s] with params [%s]",
+			request.Method,
+			request.Params,
+		)
+		w.WriteHeader(http.StatusNotFound)
+		response["error"] = &rpcError{Code: -32601, Message: "Method not found"}
+	} else if fixture.Error != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		response["error"] = fixture.Error
+	} else {
+		response["result"] = fixture.Result
+	}
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		fn.t.Errorf("cannot encode response: [%v]", err)
+	}
+}
+
+func (fn *fakeNode) findFixture(
+	method string,
+	params json.RawMessage,
+) *rpcFixture {
+	var actualParams interface{}
+	if err := json.Unmarshal(params, &actualParams); err != nil {
+		fn.t.Errorf("cannot decode params: [%v]", err)
+		return nil
+	}
+
+	for _, fixture := range fn.fixtures {
+		if fixture.Method != method {
+			continue
+		}
+
+		var fixtureParams interface{}
+		if err := json.Unmarshal(fixture.Params, &fixtureParams); err != nil {
+			fn.t.Errorf("cannot decode fixture params: [%v]", err)
+			return nil
+		}
+
+		if !reflect.DeepEqual(fixtureParams, actualParams) {
+			continue
+		}
+
+		fn.mutex.Lock()
+		exhausted := fixture.Times > 0 && fn.uses[fixture] >= fixture.Times
+		if !exhausted {
+			fn.uses[fixture]++
+		}
+		fn.mutex.Unlock()
+
+		if !exhausted {
+			return fixture
+		}
+	}
+
+	return nil
+}
+
+func (fn *fakeNode) callsCount(call string) int {
+	fn.mutex.Lock()
+	defer fn.mutex.Unlock()
+
+	count := 0
+	for _, c := range fn.calls {
+		if c == call {
+			count++
+		}
+	}
+
+	return count
+}
+
+func newTestConnection(t *testing.T, node *fakeNode) bitcoin.Chain {
+	ctx, cancelCtx := context.WithCancel(context.Background())
+	t.Cleanup(cancelCtx)
+
+	chain, err := Connect(
+		ctx,
+		Config{
+			URL:                 node.server.URL,
+			Username:            "user",
+			Password:            "password",
+			RescanFromTimestamp: 1672531200,
+			RequestTimeout:      1 * time.Second,
+			RequestRetryTimeout: 3 * time.Second,
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return chain
+}
+
+func TestConnect(t *testing.T) {
+	node := newFakeNode(t)
+
+	newTestConnection(t, node)
+
+	testutils.AssertIntsEqual(
+		t,
+		"getwalletinfo calls",
+		1,
+		node.callsCount("/wallet/keep-client getwalletinfo"),
+	)
+	testutils.AssertIntsEqual(
+		t,
+		"createwallet calls",
+		0,
+		node.callsCount("/ createwallet"),
+	)
+}
+
+func TestConnect_CreatesWallet(t *testing.T) {
+	node := newFakeNode(
+		t,
+		&rpcFixture{
+			Method: "getwalletinfo",
+			Params: json.RawMessage(`[]`),
+			Error: &rpcError{
+				Code:    rpcWalletNotFound,
+				Message: "Requested wallet does not exist or is not loaded",
+			},
+		},
+		&rpcFixture{
+			Method: "loadwallet",
+			Params: json.RawMessage(`["keep-client"]`),
+			Error: &rpcError{
+				Code:    rpcWalletNotFound,
+				Message: "Wallet file verification failed. Failed to load database path. Path does not exist.",
+			},
+		},
+		&rpcFixture{
+			Method: "createwallet",
+			Params: json.RawMessage(`["keep-client",true,true,"",false,true]`),
+			Result: json.RawMessage(`{"name":"keep-client"}`),
+		},
+	)
+
+	newTestConnection(t, node)
+
+	testutils.AssertIntsEqual(
+		t,
+		"createwallet calls",
+		1,
+		node.callsCount("/ createwallet"),
+	)
+}
+
+func TestConnect_Unauthorized(t *testing.T) {
+	server := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+		}),
+	)
+	defer server.Close()
+
+	_, err := Connect(
+		context.Background(),
+		Config{
+			URL:                 server.URL,
+			RequestRetryTimeout: 1 * time.Minute,
+		},
+	)
+
+	testutils.AssertAnyErrorInChainMatchesTarget(t, errUnauthorized, err)
+}
+
+func TestGetTransaction(t *testing.T) {
+	chain := newTestConnection(t, newFakeNode(t))
+
+	transaction, err := chain.GetTransaction(hashFromString(t, mempoolTxHash))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertStringsEqual(
+		t,
+		"transaction hash",
+		mempoolTxHash,
+		transaction.Hash().Hex(bitcoin.ReversedByteOrder),
+	)
+}
+
+func TestGetTransaction_NotFound(t *testing.T) {
+	node := newFakeNode(t)
+	chain := newTestConnection(t, node)
+
+	_, err := chain.GetTransaction(hashFromString(t, missingTxHash))
+
+	expectedErr := &rpcError{
+		Code:    rpcInvalidAddressOrKey,
+		Message: "Invalid or non-wallet transaction id",
+	}
+	if !reflect.DeepEqual(expectedErr, unwrapRPCError(err)) {
+		t.Errorf(
+			"unexpected error\nexpected: %v\nactual:   %v",
+			expectedErr,
+			err,
+		)
+	}
+
+	// Errors returned by the node must not be retried.
+	testutils.AssertIntsEqual(
+		t,
+		"getrawtransaction calls",
+		1,
+		node.callsCount("/ getrawtransaction"),
+	)
+}
+
+func TestGetTransactionConfirmations(t *testing.T) {
+	var tests = map[string]struct {
+		txHash                string
+		expectedConfirmations uint
+	}{
+		"mempool transaction known by the node": {
+			txHash:                mempoolTxHash,
+			expectedConfirmations: 0,
+		},
+		"transaction known by the wallet": {
+			txHash:                walletTxHash,
+			expectedConfirmations: 10,
+		},
+		"transaction conflicting with the chain": {
+			txHash:                conflictTxHash,
+			expectedConfirmations: 0,
+		},
+	}
+
+	chain := newTestConnection(t, newFakeNode(t))
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			confirmations, err := chain.GetTransactionConfirmations(
+				hashFromString(t, test.txHash),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			testutils.AssertUintsEqual(
+				t,
+				"confirmations",
+				uint64(test.expectedConfirmations),
+				uint64(confirmations),
+			)
+		})
+	}
+}
+
+func TestBroadcastTransaction(t *testing.T) {
+	node := newFakeNode(t)
+	chain := newTestConnection(t, node)
+
+	transaction, err := chain.GetTransaction(hashFromString(t, mempoolTxHash))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := chain.BroadcastTransaction(transaction); err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertIntsEqual(
+		t,
+		"sendrawtransaction calls",
+		1,
+		node.callsCount("/ sendrawtransaction"),
+	)
+}
+
+func TestGetLatestBlockHeight(t *testing.T) {
+	chain := newTestConnection(t, newFakeNode(t))
+
+	blockHeight, err := chain.GetLatestBlockHeight()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertUintsEqual(t, "block height", 820000, uint64(blockHeight))
+}
+
+func TestGetBlockHeader(t *testing.T) {
+	chain := newTestConnection(t, newFakeNode(t))
+
+	blockHeader, err := chain.GetBlockHeader(820000)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	serializedHeader := blockHeader.Serialize()
+
+	testutils.AssertStringsEqual(
+		t,
+		"block header",
+		"00000020bfb7d7c3e8c5336ab1bca5b1b6e3a85ce2d0a21e6d4e02000000000000"+
+			"0000004ec9a99a8006d19d69bfef41f24fecb39a23335cb6ca3dd76c34b9efd1da"+
+			"3aa400f153659438051739300000",
+		hex.EncodeToString(serializedHeader[:]),
+	)
+	testutils.AssertStringsEqual(
+		t,
+		"merkle root",
+		"a43adad1efb9346cd73dcab65c33239ab3ec4ff241efbf699dd106809aa9c94e",
+		blockHeader.MerkleRootHash.Hex(bitcoin.ReversedByteOrder),
+	)
+}
+
+func TestGetBlockHeader_OutOfRange(t *testing.T) {
+	chain := newTestConnection(t, newFakeNode(t))
+
+	_, err := chain.GetBlockHeader(900000)
+
+	expectedErr := &rpcError{
+		Code:    -8,
+		Message: "Block height out of range",
+	}
+	if !reflect.DeepEqual(expectedErr, unwrapRPCError(err)) {
+		t.Errorf(
+			"unexpected error\nexpected: %v\nactual:   %v",
+			expectedErr,
+			err,
+		)
+	}
+}
+
+func TestGetTransactionMerkleProof(t *testing.T) {
+	chain := newTestConnection(t, newFakeNode(t))
+
+	proof, err := chain.GetTransactionMerkleProof(
+		hashFromString(t, blockTxHash),
+		820000,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedProof := &bitcoin.TransactionMerkleProof{
+		BlockHeight: 820000,
+		MerkleNodes: []string{
+			"9b0fc92260312ce44e74ef369f5c66bbb85848f2eddd5a7a1cde251e54ccfdd5",
+			"0c6d915a8ba6b03081052a7417697a1b7fd39df31b4260a652e72e90fdf1ebe9",
+			"b6a8a7e7eccc07e869363ad7204c008b8318c932c5d8ba0ab2c7032ddfccba9c",
+		},
+		Position: 3,
+	}
+
+	if !reflect.DeepEqual(expectedProof, proof) {
+		t.Errorf(
+			"unexpected proof\nexpected: %+v\nactual:   %+v",
+			expectedProof,
+			proof,
+		)
+	}
+}
+
+func TestGetTxHashesForPublicKeyHash(t *testing.T) {
+	chain := newTestConnection(t, newFakeNode(t))
+
+	txHashes, err := chain.GetTxHashesForPublicKeyHash(
+		publicKeyHashFromString(t, watchedPublicKeyHash),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertHashes(t, []string{walletTxHash, blockTxHash}, txHashes)
+}
+
+func TestGetTxHashesForPublicKeyHash_SpendOnly(t *testing.T) {
+	// Transaction spending output 0 of the wallet transaction without
+	// paying the watched public key hash back.
+	spendTxHash := "6c88a24b7f7360d43fad4d6164885c46a4d6edd2812328548ff5924ab170361e"
+	// Transaction spending an output of another public key hash observed
+	// by the watch-only wallet.
+	foreignTxHash := "f08599cb89ff33fdffead50d9e715072d4ddf52d4dceabe106250d420213cf17"
+
+	node := newFakeNode(
+		t,
+		&rpcFixture{
+			Method: "listtransactions",
+			Params: json.RawMessage(`["*", 1000, 0, true]`),
+			Result: json.RawMessage(`[
+				{"category": "receive", "label": "` + watchedPublicKeyHash + `", "vout": 0, "confirmations": 10, "blockheight": 819991, "txid": "` + walletTxHash + `"},
+				{"category": "receive", "label": "` + newPublicKeyHash + `", "vout": 0, "confirmations": 5, "blockheight": 819996, "txid": "999e1c837c76a1b7fbb7e57baf87b309960f5ffefbf2a9b95dd890602272f644"},
+				{"category": "send", "vout": 0, "confirmations": 4, "blockheight": 819997, "txid": "` + spendTxHash + `"},
+				{"category": "send", "vout": 0, "confirmations": 2, "blockheight": 819999, "txid": "` + foreignTxHash + `"}
+			]`),
+		},
+		&rpcFixture{
+			Method: "getrawtransaction",
+			Params: json.RawMessage(`["` + spendTxHash + `", false]`),
+			Result: json.RawMessage(`"01000000013ba3edfd7a7b12b27ac72c3e67768f617fc81bc3888a51323a9fb8aa4b1e5e4a0000000000ffffffff0140aeeb0200000000160014111111111111111111111111111111111111111100000000"`),
+		},
+		&rpcFixture{
+			Method: "getrawtransaction",
+			Params: json.RawMessage(`["` + foreignTxHash + `", false]`),
+			Result: json.RawMessage(`"010000000144f672226090d85db9a9f2fbfe5f0f9609b387af7be5b7fbb7a1767c831c9e990000000000ffffffff01c0cb170700000000160014111111111111111111111111111111111111111100000000"`),
+		},
+	)
+	chain := newTestConnection(t, node)
+
+	txHashes, err := chain.GetTxHashesForPublicKeyHash(
+		publicKeyHashFromString(t, watchedPublicKeyHash),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertHashes(t, []string{walletTxHash, spendTxHash}, txHashes)
+}
+
+func TestGetMempoolForPublicKeyHash(t *testing.T) {
+	chain := newTestConnection(t, newFakeNode(t))
+
+	transactions, err := chain.GetMempoolForPublicKeyHash(
+		publicKeyHashFromString(t, watchedPublicKeyHash),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	txHashes := make([]bitcoin.Hash, len(transactions))
+	for i, transaction := range transactions {
+		txHashes[i] = transaction.Hash()
+	}
+
+	assertHashes(t, []string{mempoolTxHash}, txHashes)
+}
+
+func TestGetUtxosForPublicKeyHash(t *testing.T) {
+	chain := newTestConnection(t, newFakeNode(t))
+
+	utxos, err := chain.GetUtxosForPublicKeyHash(
+		publicKeyHashFromString(t, watchedPublicKeyHash),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedUtxos := []*bitcoin.UnspentTransactionOutput{
+		{
+			Outpoint: &bitcoin.TransactionOutpoint{
+				TransactionHash: hashFromString(t, walletTxHash),
+				OutputIndex:     0,
+			},
+			Value: 50000000,
+		},
+		{
+			Outpoint: &bitcoin.TransactionOutpoint{
+				TransactionHash: hashFromString(t, blockTxHash),
+				OutputIndex:     1,
+			},
+			Value: 10000,
+		},
+	}
+
+	if !reflect.DeepEqual(expectedUtxos, utxos) {
+		t.Errorf(
+			"unexpected UTXOs\nexpected: %v\nactual:   %v",
+			toJson(expectedUtxos),
+			toJson(utxos),
+		)
+	}
+}
+
+func TestGetMempoolUtxosForPublicKeyHash(t *testing.T) {
+	chain := newTestConnection(t, newFakeNode(t))
+
+	utxos, err := chain.GetMempoolUtxosForPublicKeyHash(
+		publicKeyHashFromString(t, watchedPublicKeyHash),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedUtxos := []*bitcoin.UnspentTransactionOutput{
+		{
+			Outpoint: &bitcoin.TransactionOutpoint{
+				TransactionHash: hashFromString(t, mempoolTxHash),
+				OutputIndex:     0,
+			},
+			Value: 60800,
+		},
+	}
+
+	if !reflect.DeepEqual(expectedUtxos, utxos) {
+		t.Errorf(
+			"unexpected UTXOs\nexpected: %v\nactual:   %v",
+			toJson(expectedUtxos),
+			toJson(utxos),
+		)
+	}
+}
+
+func TestWatchPublicKeyHash(t *testing.T) {
+	node := newFakeNode(t)
+	chain := newTestConnection(t, node)
+
+	publicKeyHash := publicKeyHashFromString(t, newPublicKeyHash)
+
+	for i := 0; i < 2; i++ {
+		txHashes, err := chain.GetTxHashesForPublicKeyHash(publicKeyHash)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		testutils.AssertIntsEqual(t, "tx hashes count", 0, len(txHashes))
+	}
+
+	testutils.AssertIntsEqual(
+		t,
+		"listlabels calls",
+		1,
+		node.callsCount("/wallet/keep-client listlabels"),
+	)
+	testutils.AssertIntsEqual(
+		t,
+		"importdescriptors calls",
+		1,
+		node.callsCount("/wallet/keep-client importdescriptors"),
+	)
+}
+
+func TestWatchPublicKeyHash_ImportTimeout(t *testing.T) {
+	node := newFakeNode(
+		t,
+		&rpcFixture{
+			Method: "listlabels",
+			Params: json.RawMessage(`[]`),
+			Result: json.RawMessage(`["", "` + watchedPublicKeyHash + `"]`),
+			Times:  1,
+		},
+		&rpcFixture{
+			Method: "listlabels",
+			Params: json.RawMessage(`[]`),
+			Result: json.RawMessage(
+				`["", "` + watchedPublicKeyHash + `", "` + newPublicKeyHash + `"]`,
+			),
+		},
+		// The wallet is not rescanning when the connection is established
+		// and when the watched labels are loaded.
+		&rpcFixture{
+			Method: "getwalletinfo",
+			Params: json.RawMessage(`[]`),
+			Result: json.RawMessage(`{"walletname": "keep-client", "scanning": false}`),
+			Times:  2,
+		},
+		&rpcFixture{
+			Method: "getwalletinfo",
+			Params: json.RawMessage(`[]`),
+			Result: json.RawMessage(
+				`{"walletname": "keep-client", "scanning": {"duration": 180, "progress": 0.25}}`,
+			),
+			Times: 1,
+		},
+		// The import request times out as the node rescans the chain.
+		&rpcFixture{
+			Method: "importdescriptors",
+			Params: json.RawMessage(`[[{"desc": "raw(76a914e257eccafbc07c381642ce6e7e55120fb077fbed88ac)#2xqeu2lw", "timestamp": 1672531200, "label": "e257eccafbc07c381642ce6e7e55120fb077fbed"}, {"desc": "raw(0014e257eccafbc07c381642ce6e7e55120fb077fbed)#mq4alxd6", "timestamp": 1672531200, "label": "e257eccafbc07c381642ce6e7e55120fb077fbed"}]]`),
+			Result: json.RawMessage(`[{"success": true}, {"success": true}]`),
+			Delay:  5 * time.Second,
+		},
+	)
+	chain := newTestConnection(t, node)
+
+	publicKeyHash := publicKeyHashFromString(t, newPublicKeyHash)
+
+	// The import times out and the rescan is still in progress.
+	for i := 0; i < 2; i++ {
+		_, err := chain.GetTxHashesForPublicKeyHash(publicKeyHash)
+		if err == nil {
+			t.Fatalf("expected error of call [%d]", i)
+		}
+	}
+
+	// The rescan is completed.
+	txHashes, err := chain.GetTxHashesForPublicKeyHash(publicKeyHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertIntsEqual(t, "tx hashes count", 0, len(txHashes))
+	testutils.AssertIntsEqual(
+		t,
+		"importdescriptors calls",
+		1,
+		node.callsCount("/wallet/keep-client importdescriptors"),
+	)
+}
+
+func TestGetSpendingTxHash(t *testing.T) {
+	fundingTxHash := "6c88a24b7f7360d43fad4d6164885c46a4d6edd2812328548ff5924ab170361e"
+	fundingTx := "01000000013ba3edfd7a7b12b27ac72c3e67768f617fc81bc3888a51323a9fb8aa4b1e5e4a0000000000ffffffff0140aeeb0200000000160014111111111111111111111111111111111111111100000000"
+	fundingScript := "00141111111111111111111111111111111111111111"
+	// Transaction spending output 0 of the funding transaction.
+	spendTxHash := "ba6361964cb3e8d8b6992406748ec5228550e8772650030625d810d663f0d8b3"
+	// Transaction spending an output of another transaction observed by
+	// the watch-only wallet.
+	foreignTxHash := "f08599cb89ff33fdffead50d9e715072d4ddf52d4dceabe106250d420213cf17"
+
+	var tests = map[string]struct {
+		txHash         string
+		fixtures       []*rpcFixture
+		expectedSpent  bool
+		expectedTxHash string
+		expectedErr    *rpcError
+	}{
+		"unspent": {
+			txHash: walletTxHash,
+			fixtures: []*rpcFixture{
+				{
+					Method: "gettxout",
+					Params: json.RawMessage(`["` + walletTxHash + `", 0, false]`),
+					Result: json.RawMessage(`{"confirmations": 10, "value": 0.5}`),
+				},
+			},
+			expectedSpent: false,
+		},
+		"spent": {
+			txHash: fundingTxHash,
+			fixtures: []*rpcFixture{
+				{
+					Method: "gettxout",
+					Params: json.RawMessage(`["` + fundingTxHash + `", 0, false]`),
+					Result: json.RawMessage(`null`),
+				},
+				{
+					Method: "getrawtransaction",
+					Params: json.RawMessage(`["` + fundingTxHash + `", true]`),
+					Result: json.RawMessage(`{"hex": "` + fundingTx + `", "confirmations": 5, "blocktime": 1700000000}`),
+				},
+				{
+					Method: "getdescriptorinfo",
+					Params: json.RawMessage(`["raw(` + fundingScript + `)"]`),
+					Result: json.RawMessage(`{"descriptor": "raw(` + fundingScript + `)#splzzvwf"}`),
+				},
+				{
+					Method: "importdescriptors",
+					Params: json.RawMessage(`[[{"desc": "raw(` + fundingScript + `)#splzzvwf", "timestamp": 1700000000, "label": "` + fundingScript + `"}]]`),
+					Result: json.RawMessage(`[{"success": true}]`),
+				},
+				{
+					Method: "listtransactions",
+					Params: json.RawMessage(`["*", 1000, 0, true]`),
+					Result: json.RawMessage(`[
+						{"category": "send", "vout": 0, "confirmations": 20, "blockheight": 819981, "blocktime": 1699990000, "txid": "` + walletTxHash + `"},
+						{"category": "send", "vout": 0, "confirmations": 4, "blockheight": 819997, "blocktime": 1700000600, "txid": "` + foreignTxHash + `"},
+						{"category": "send", "vout": 0, "confirmations": 2, "blockheight": 819999, "blocktime": 1700001800, "txid": "` + spendTxHash + `"}
+					]`),
+				},
+				{
+					Method: "getrawtransaction",
+					Params: json.RawMessage(`["` + foreignTxHash + `", false]`),
+					Result: json.RawMessage(`"010000000144f672226090d85db9a9f2fbfe5f0f9609b387af7be5b7fbb7a1767c831c9e990000000000ffffffff01c0cb170700000000160014111111111111111111111111111111111111111100000000"`),
+				},
+				{
+					Method: "getrawtransaction",
+					Params: json.RawMessage(`["` + spendTxHash + `", false]`),
+					Result: json.RawMessage(`"01000000011e3670b14a92f58f54282381d2edd6a4465c8864614dad3fd460737f4ba2886c0000000000ffffffff0140420f0000000000160014222222222222222222222222222222222222222200000000"`),
+				},
+			},
+			expectedSpent:  true,
+			expectedTxHash: spendTxHash,
+		},
+		"unknown transaction": {
+			txHash: missingTxHash,
+			fixtures: []*rpcFixture{
+				{
+					Method: "gettxout",
+					Params: json.RawMessage(`["` + missingTxHash + `", 0, false]`),
+					Result: json.RawMessage(`null`),
+				},
+				{
+					Method: "getrawtransaction",
+					Params: json.RawMessage(`["` + missingTxHash + `", true]`),
+					Error: &rpcError{
+						Code:    -5,
+						Message: "No such mempool or blockchain transaction. Use gettransaction for wallet transactions.",
+					},
+				},
+			},
+			expectedErr: &rpcError{
+				Code:    -5,
+				Message: "Invalid or non-wallet transaction id",
+			},
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			chain := newTestConnection(t, newFakeNode(t, test.fixtures...))
+
+			txHash, spent, err := chain.(bitcoin.SpendingTxFinder).GetSpendingTxHash(
+				&bitcoin.TransactionOutpoint{
+					TransactionHash: hashFromString(t, test.txHash),
+					OutputIndex:     0,
+				},
+			)
+
+			if test.expectedErr != nil {
+				if !reflect.DeepEqual(test.expectedErr, unwrapRPCError(err)) {
+					t.Errorf(
+						"unexpected error\nexpected: %v\nactual:   %v",
+						test.expectedErr,
+						err,
+					)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			testutils.AssertBoolsEqual(t, "spent", test.expectedSpent, spent)
+
+			if test.expectedSpent {
+				assertHashes(t, []string{test.expectedTxHash}, []bitcoin.Hash{txHash})
+			}
+		})
+	}
+}
+
+func TestEstimateSatPerVByteFee(t *testing.T) {
+	chain := newTestConnection(t, newFakeNode(t))
+
+	satPerVByteFee, err := chain.EstimateSatPerVByteFee(6)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertIntsEqual(t, "sat/vbyte fee", 12, int(satPerVByteFee))
+}
+
+func TestEstimateSatPerVByteFee_NotEnoughData(t *testing.T) {
+	chain := newTestConnection(t, newFakeNode(t))
+
+	_, err := chain.EstimateSatPerVByteFee(1)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+
+	testutils.AssertStringsEqual(
+		t,
+		"error",
+		"node does not have enough information to make an estimate: "+
+			"[Insufficient data or no feerate found]",
+		err.Error(),
+	)
+}
+
+func TestGetCoinbaseTxHash(t *testing.T) {
+	chain := newTestConnection(t, newFakeNode(t))
+
+	txHash, err := chain.GetCoinbaseTxHash(820000)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertHashes(t, []string{walletTxHash}, []bitcoin.Hash{txHash})
+}
+
+func hashFromString(t *testing.T, hash string) bitcoin.Hash {
+	result, err := bitcoin.NewHashFromString(hash, bitcoin.ReversedByteOrder)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return result
+}
+
+func publicKeyHashFromString(t *testing.T, publicKeyHash string) [20]byte {
+	bytes, err := hex.DecodeString(publicKeyHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var result [20]byte
+	copy(result[:], bytes)
+
+	return result
+}
+
+func unwrapRPCError(err error) *rpcError {
+	var re *rpcError
+	if errors.As(err, &re) {
+		return re
+	}
+
+	return nil
+}
+
+func assertHashes(t *testing.T, expected []string, actual []bitcoin.Hash) {
+	actualStrings := make([]string, len(actual))
+	for i, hash := range actual {
+		actualStrings[i] = hash.Hex(bitcoin.ReversedByteOrder)
+	}
+
+	if !reflect.DeepEqual(expected, actualStrings) {
+		t.Errorf(
+			"unexpected hashes\nexpected: %v\nactual:   %v",
+			expected,
+			actualStrings,
+		)
+	}
+}
+
+func toJson(val interface{}) string {
+	b, err := json.Marshal(val)
+	if err != nil {
+		panic(err)
+	}
+
+	return string(b)
+}
@@ -0,0 +1,43 @@
+package bitcoind
+
+import "time"
+
+const (
+	// DefaultWallet is a default name of the watch-only descriptor wallet
+	// used to index transactions of the observed public key hashes.
+	DefaultWallet = "keep-client"
+	// DefaultRequestTimeout is a default timeout used for a single attempt of
+	// JSON-RPC request.
+	DefaultRequestTimeout = 30 * time.Second
+	// DefaultRequestRetryTimeout is a default timeout used for JSON-RPC
+	// request retries.
+	DefaultRequestRetryTimeout = 2 * time.Minute
+)
+
+// Config holds configurable properties.
+type Config struct {
+	// URL to the JSON-RPC interface of the bitcoind node in format:
+	// `scheme://hostname:port`.
+	URL string
+	// Username used for the JSON-RPC authentication.
+	Username string
+	// Password used for the JSON-RPC authentication.
+	Password string
+	// Wallet is the name of the watch-only descriptor wallet used to index
+	// transactions and unspent outputs of the observed public key hashes.
+	// The wallet is created if it does not exist on the node.
+	Wallet string
+	// RescanFromTimestamp is the Unix timestamp the node rescans the chain
+	// from when a new public key hash is added to the watch-only wallet.
+	// Transactions older than this timestamp are not visible to the client
+	// so it should not be later than the Bridge contract deployment. The
+	// value is required. Keep in mind the node rescans all blocks since this
+	// timestamp for every newly watched public key hash which may take from
+	// minutes to hours depending on the node's hardware. Requests for the
+	// public key hash fail until the rescan completes.
+	RescanFromTimestamp int64
+	// Timeout for a single attempt of JSON-RPC request.
+	RequestTimeout time.Duration
+	// Timeout for JSON-RPC request retries.
+	RequestRetryTimeout time.Duration
+}
@@ -0,0 +1,205 @@
+package bitcoind
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/btcsuite/btcd/v2/wire"
+
+	"github.com/keep-network/keep-core/pkg/bitcoin"
+)
+
+// partialMerkleTree is a partial Merkle tree decoded from a merkle block
+// returned by the `gettxoutproof` call. See BIP 37 for the tree format.
+type partialMerkleTree struct {
+	transactionsCount uint32
+	hashes            []bitcoin.Hash
+	flags             []byte
+
+	hashesUsed int
+	flagsUsed  int
+	// nodes holds all nodes of the tree known after the traversal, keyed
+	// by their height and position.
+	nodes map[[2]uint32]bitcoin.Hash
+	// matched holds positions of the matched transactions.
+	matched map[bitcoin.Hash]uint32
+}
+
+// treeWidth returns the number of nodes at the given height of the tree.
+// Height 0 denotes leaves of the tree.
+func (pmt *partialMerkleTree) treeWidth(height uint32) uint32 {
+	return (pmt.transactionsCount + (1 << height) - 1) >> height
+}
+
+// treeHeight returns the height of the tree's root.
+func (pmt *partialMerkleTree) treeHeight() uint32 {
+	height := uint32(0)
+	for pmt.treeWidth(height) > 1 {
+		height++
+	}
+
+	return height
+}
+
+func (pmt *partialMerkleTree) nextFlag() (bool, error) {
+	if pmt.flagsUsed >= 8*len(pmt.flags) {
+		return false, fmt.Errorf("flags exhausted")
+	}
+
+	flag := pmt.flags[pmt.flagsUsed/8]&(1<<(pmt.flagsUsed%8)) != 0
+	pmt.flagsUsed++
+
+	return flag, nil
+}
+
+func (pmt *partialMerkleTree) nextHash() (bitcoin.Hash, error) {
+	if pmt.hashesUsed >= len(pmt.hashes) {
+		return bitcoin.Hash{}, fmt.Errorf("hashes exhausted")
+	}
+
+	hash := pmt.hashes[pmt.hashesUsed]
+	pmt.hashesUsed++
+
+	return hash, nil
+}
+
+// traverse walks the tree depth-first, computes hashes of all the nodes
+// that can be computed, and returns the hash of the node at the given
+// height and position.
+func (pmt *partialMerkleTree) traverse(
+	height uint32,
+	position uint32,
+) (bitcoin.Hash, error) {
+	flag, err := pmt.nextFlag()
+	if err != nil {
+		return bitcoin.Hash{}, err
+	}
+
+	var hash bitcoin.Hash
+
+	if height == 0 || !flag {
+		hash, err = pmt.nextHash()
+		if err != nil {
+			return bitcoin.Hash{}, err
+		}
+
+		if height == 0 && flag {
+			pmt.matched[hash] = position
+		}
+	} else {
+		left, err := pmt.traverse(height-1, 2*position)
+		if err != nil {
+			return bitcoin.Hash{}, err
+		}
+
+		right := left
+		if 2*position+1 < pmt.treeWidth(height-1) {
+			right, err = pmt.traverse(height-1, 2*position+1)
+			if err != nil {
+				return bitcoin.Hash{}, err
+			}
+
+			if right == left {
+				// Identical siblings enable CVE-2012-2459 attacks.
+				return bitcoin.Hash{}, fmt.Errorf(
+					"identical siblings at height [%d]",
+					height-1,
+				)
+			}
+		}
+
+		hash = bitcoin.ComputeHash(append(left[:], right[:]...))
+	}
+
+	pmt.nodes[[2]uint32{height, position}] = hash
+
+	return hash, nil
+}
+
+// parseTxOutProof parses the merkle block returned by the `gettxoutproof`
+// call and extracts the Merkle proof of the given transaction.
+func parseTxOutProof(
+	rawProof string,
+	transactionHash bitcoin.Hash,
+	blockHeight uint,
+) (*bitcoin.TransactionMerkleProof, error) {
+	proofBytes, err := hex.DecodeString(rawProof)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode a hex string: [%w]", err)
+	}
+
+	var merkleBlock wire.MsgMerkleBlock
+	if err := merkleBlock.BtcDecode(
+		bytes.NewReader(proofBytes),
+		wire.ProtocolVersion,
+		wire.BaseEncoding,
+	); err != nil {
+		return nil, fmt.Errorf("failed to decode a merkle block: [%w]", err)
+	}
+
+	if merkleBlock.Transactions == 0 {
+		return nil, fmt.Errorf("merkle block has no transactions")
+	}
+
+	pmt := &partialMerkleTree{
+		transactionsCount: merkleBlock.Transactions,
+		hashes:            make([]bitcoin.Hash, len(merkleBlock.Hashes)),
+		flags:             merkleBlock.Flags,
+		nodes:             make(map[[2]uint32]bitcoin.Hash),
+		matched:           make(map[bitcoin.Hash]uint32),
+	}
+	for i, hash := range merkleBlock.Hashes {
+		pmt.hashes[i] = bitcoin.Hash(*hash)
+	}
+
+	height := pmt.treeHeight()
+
+	root, err := pmt.traverse(height, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to traverse merkle tree: [%w]", err)
+	}
+
+	if root != bitcoin.Hash(merkleBlock.Header.MerkleRoot) {
+		return nil, fmt.Errorf(
+			"merkle root mismatch; computed [%s], block header has [%s]",
+			root.Hex(bitcoin.ReversedByteOrder),
+			merkleBlock.Header.MerkleRoot.String(),
+		)
+	}
+
+	position, ok := pmt.matched[transactionHash]
+	if !ok {
+		return nil, fmt.Errorf(
+			"transaction [%s] is not matched by the merkle block",
+			transactionHash.Hex(bitcoin.ReversedByteOrder),
+		)
+	}
+
+	merkleNodes := make([]string, height)
+	for h := uint32(0); h < height; h++ {
+		nodePosition := position >> h
+		siblingPosition := nodePosition ^ 1
+		if siblingPosition >= pmt.treeWidth(h) {
+			// The last node on the given height is hashed with itself.
+			siblingPosition = nodePosition
+		}
+
+		sibling, ok := pmt.nodes[[2]uint32{h, siblingPosition}]
+		if !ok {
+			return nil, fmt.Errorf(
+				"missing merkle tree node at height [%d] and position [%d]",
+				h,
+				siblingPosition,
+			)
+		}
+
+		merkleNodes[h] = sibling.Hex(bitcoin.ReversedByteOrder)
+	}
+
+	return &bitcoin.TransactionMerkleProof{
+		BlockHeight: blockHeight,
+		MerkleNodes: merkleNodes,
+		Position:    uint(position),
+	}, nil
+}
@@ -0,0 +1,122 @@
+package bitcoind
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/keep-network/keep-core/internal/testutils"
+	"github.com/keep-network/keep-core/pkg/bitcoin"
+)
+
+// Merkle blocks of a five-transaction block with a single transaction matched.
+const (
+	txOutProofPosition3 = "00000020bfb7d7c3e8c5336ab1bca5b1b6e3a85ce2d0a21e6d4e" +
+		"020000000000000000004ec9a99a8006d19d69bfef41f24fecb39a23335cb6ca3dd7" +
+		"6c34b9efd1da3aa400f1536594380517393000000500000004e9ebf1fd902ee752a6" +
+		"60421bf39dd37f1b7a6917742a058130b0a68b5a916d0cd5fdcc541e25de1c7a5add" +
+		"edf24858b8bb665c9f36ef744ee42c316022c90f9b982051fd1e4ba744bbbe680e1f" +
+		"ee14677ba1a3c3540bf7b1cdb606e857233e0e9cbaccdf2d03c7b20abad8c532c918" +
+		"838b004c20d73a3669e807ccece7a7a8b6012b"
+	txOutProofPosition4 = "00000020bfb7d7c3e8c5336ab1bca5b1b6e3a85ce2d0a21e6d4e" +
+		"020000000000000000004ec9a99a8006d19d69bfef41f24fecb39a23335cb6ca3dd7" +
+		"6c34b9efd1da3aa400f15365943805173930000005000000028f526bcf65aa191de0" +
+		"8d7c81f5eee9d05dd1f7797150ba0f14385326015664d844f672226090d85db9a9f2" +
+		"fbfe5f0f9609b387af7be5b7fbb7a1767c831c9e99011d"
+)
+
+func TestParseTxOutProof(t *testing.T) {
+	var tests = map[string]struct {
+		rawProof            string
+		transactionHash     string
+		expectedMerkleNodes []string
+		expectedPosition    uint
+		expectedErr         string
+	}{
+		"transaction in the middle of the block": {
+			rawProof:        txOutProofPosition3,
+			transactionHash: "0e3e2357e806b6cdb1f70b54c3a3a17b6714ee1f0e68bebb44a74b1efd512098",
+			expectedMerkleNodes: []string{
+				"9b0fc92260312ce44e74ef369f5c66bbb85848f2eddd5a7a1cde251e54ccfdd5",
+				"0c6d915a8ba6b03081052a7417697a1b7fd39df31b4260a652e72e90fdf1ebe9",
+				"b6a8a7e7eccc07e869363ad7204c008b8318c932c5d8ba0ab2c7032ddfccba9c",
+			},
+			expectedPosition: 3,
+		},
+		"last transaction of the block": {
+			rawProof:        txOutProofPosition4,
+			transactionHash: "999e1c837c76a1b7fbb7e57baf87b309960f5ffefbf2a9b95dd890602272f644",
+			expectedMerkleNodes: []string{
+				"999e1c837c76a1b7fbb7e57baf87b309960f5ffefbf2a9b95dd890602272f644",
+				"852a10a0f39305f736a429adb144672ad3b728bbdccc33336800803c708b6170",
+				"d8645601265338140fba507179f7d15dd0e9eef5817c8de01d19aa65cf6b528f",
+			},
+			expectedPosition: 4,
+		},
+		"transaction not matched": {
+			rawProof:        txOutProofPosition3,
+			transactionHash: "435d4aff6d4bc34134877bd3213c17970142fdd04d4113d534120033b9eecb2e",
+			expectedErr: "transaction [435d4aff6d4bc34134877bd3213c17970142fdd04d4113d534120033b9eecb2e] " +
+				"is not matched by the merkle block",
+		},
+		"merkle root mismatch": {
+			// The first byte of the Merkle root in the block header is changed.
+			rawProof:        txOutProofPosition3[:72] + "ff" + txOutProofPosition3[74:],
+			transactionHash: "0e3e2357e806b6cdb1f70b54c3a3a17b6714ee1f0e68bebb44a74b1efd512098",
+			expectedErr: "merkle root mismatch; " +
+				"computed [a43adad1efb9346cd73dcab65c33239ab3ec4ff241efbf699dd106809aa9c94e], " +
+				"block header has [a43adad1efb9346cd73dcab65c33239ab3ec4ff241efbf699dd106809aa9c9ff]",
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			transactionHash, err := bitcoin.NewHashFromString(
+				test.transactionHash,
+				bitcoin.ReversedByteOrder,
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			proof, err := parseTxOutProof(test.rawProof, transactionHash, 820000)
+
+			if len(test.expectedErr) > 0 {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+
+				testutils.AssertStringsEqual(
+					t,
+					"error",
+					test.expectedErr,
+					err.Error(),
+				)
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			testutils.AssertUintsEqual(
+				t,
+				"block height",
+				820000,
+				uint64(proof.BlockHeight),
+			)
+			testutils.AssertUintsEqual(
+				t,
+				"position",
+				uint64(test.expectedPosition),
+				uint64(proof.Position),
+			)
+			if !reflect.DeepEqual(test.expectedMerkleNodes, proof.MerkleNodes) {
+				t.Errorf(
+					"unexpected merkle nodes\nexpected: %v\nactual:   %v",
+					test.expectedMerkleNodes,
+					proof.MerkleNodes,
+				)
+			}
+		})
+	}
+}
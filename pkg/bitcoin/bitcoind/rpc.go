@@ -0,0 +1,179 @@
+package bitcoind
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
+)
+
+// JSON-RPC error codes returned by bitcoind.
+// See: https://github.com/bitcoin/bitcoin/blob/master/src/rpc/protocol.h
+const (
+	rpcInvalidAddressOrKey = -5
+	rpcWalletNotFound      = -18
+	rpcInWarmup            = -28
+	rpcWalletAlreadyLoaded = -35
+)
+
+// errUnauthorized is returned when the node rejects the JSON-RPC credentials.
+var errUnauthorized = fmt.Errorf("unauthorized; check the JSON-RPC credentials")
+
+// rpcError is an error returned by the node in the JSON-RPC response.
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (re *rpcError) Error() string {
+	return fmt.Sprintf("rpc error [%d]: %s", re.Code, re.Message)
+}
+
+// hasRPCErrorCode checks whether the given error is a JSON-RPC error with
+// the given code.
+func hasRPCErrorCode(err error, code int) bool {
+	var re *rpcError
+	return errors.As(err, &re) && re.Code == code
+}
+
+// isPermanentErr checks whether the given request error is permanent, i.e.
+// retrying the request will not change its outcome. Errors returned by the
+// node are permanent unless the node is still warming up.
+func isPermanentErr(err error) bool {
+	var re *rpcError
+	if errors.As(err, &re) {
+		return re.Code != rpcInWarmup
+	}
+
+	return errors.Is(err, errUnauthorized)
+}
+
+type rpcRequest struct {
+	JSONRPC string        `json:"jsonrpc"`
+	ID      uint64        `json:"id"`
+	Method  string        `json:"method"`
+	Params  []interface{} `json:"params"`
+}
+
+type rpcResponse struct {
+	Result json.RawMessage `json:"result"`
+	Error  *rpcError       `json:"error"`
+	ID     uint64          `json:"id"`
+}
+
+// rpcClient is a minimal bitcoind JSON-RPC client.
+type rpcClient struct {
+	url        string
+	username   string
+	password   string
+	httpClient *http.Client
+	nextID     uint64
+}
+
+func newRPCClient(url, username, password string) *rpcClient {
+	return &rpcClient{
+		url:        strings.TrimSuffix(url, "/"),
+		username:   username,
+		password:   password,
+		httpClient: &http.Client{},
+	}
+}
+
+// wallet returns a client executing wallet-specific calls against the
+// wallet with the given name.
+func (rc *rpcClient) wallet(name string) *rpcClient {
+	return &rpcClient{
+		url:        rc.url + "/wallet/" + url.PathEscape(name),
+		username:   rc.username,
+		password:   rc.password,
+		httpClient: rc.httpClient,
+	}
+}
+
+// call executes the given JSON-RPC method with the given parameters and
+// unmarshals the result into the given result value. The result value can
+// be nil if the caller is not interested in the result.
+func (rc *rpcClient) call(
+	ctx context.Context,
+	method string,
+	result interface{},
+	params ...interface{},
+) error {
+	if params == nil {
+		params = []interface{}{}
+	}
+
+	requestBody, err := json.Marshal(
+		&rpcRequest{
+			JSONRPC: "1.0",
+			ID:      atomic.AddUint64(&rc.nextID, 1),
+			Method:  method,
+			Params:  params,
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: [%w]", err)
+	}
+
+	request, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodPost,
+		rc.url,
+		bytes.NewReader(requestBody),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create request: [%w]", err)
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+	if len(rc.username) > 0 || len(rc.password) > 0 {
+		request.SetBasicAuth(rc.username, rc.password)
+	}
+
+	response, err := rc.httpClient.Do(request)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: [%w]", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode == http.StatusUnauthorized {
+		return errUnauthorized
+	}
+
+	responseBody, err := io.ReadAll(response.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: [%w]", err)
+	}
+
+	// bitcoind responds with a non-200 HTTP status code along with a
+	// regular JSON-RPC response if the call failed. The JSON-RPC error
+	// carries more information so try to decode the response first.
+	var rpcResp rpcResponse
+	if err := json.Unmarshal(responseBody, &rpcResp); err != nil {
+		return fmt.Errorf(
+			"failed to unmarshal response with HTTP status [%s]: [%w]",
+			response.Status,
+			err,
+		)
+	}
+
+	if rpcResp.Error != nil {
+		return rpcResp.Error
+	}
+
+	if result == nil {
+		return nil
+	}
+
+	if err := json.Unmarshal(rpcResp.Result, result); err != nil {
+		return fmt.Errorf("failed to unmarshal result: [%w]", err)
+	}
+
+	return nil
+}
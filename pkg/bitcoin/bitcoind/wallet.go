@@ -0,0 +1,313 @@
+package bitcoind
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/keep-network/keep-core/pkg/bitcoin"
+)
+
+// loadWallet makes sure the watch-only descriptor wallet is loaded by the
+// node. The wallet is created if it does not exist yet.
+func (c *Connection) loadWallet() error {
+	_, err := requestWithRetry(
+		c,
+		func(ctx context.Context) (interface{}, error) {
+			err := c.wallet.call(ctx, "getwalletinfo", nil)
+			if err == nil || !hasRPCErrorCode(err, rpcWalletNotFound) {
+				return nil, err
+			}
+
+			err = c.client.call(ctx, "loadwallet", nil, c.config.Wallet)
+			if err == nil || hasRPCErrorCode(err, rpcWalletAlreadyLoaded) {
+				return nil, nil
+			}
+			if !hasRPCErrorCode(err, rpcWalletNotFound) {
+				return nil, err
+			}
+
+			logger.Infof(
+				"creating watch-only wallet [%s]",
+				c.config.Wallet,
+			)
+
+			return nil, c.client.call(
+				ctx,
+				"createwallet",
+				nil,
+				c.config.Wallet,
+				true,  // disable_private_keys
+				true,  // blank
+				"",    // passphrase
+				false, // avoid_reuse
+				true,  // descriptors
+			)
+		},
+		"loadwallet",
+	)
+
+	return err
+}
+
+// watchPublicKeyHash makes sure the watch-only wallet observes P2PKH and
+// P2WPKH scripts of the given public key hash and returns the wallet label
+// assigned to them.
+func (c *Connection) watchPublicKeyHash(publicKeyHash [20]byte) (string, error) {
+	label := hex.EncodeToString(publicKeyHash[:])
+
+	p2pkh, err := bitcoin.PayToPublicKeyHash(publicKeyHash)
+	if err != nil {
+		return "", fmt.Errorf("cannot build P2PKH: [%v]", err)
+	}
+
+	p2wpkh, err := bitcoin.PayToWitnessPublicKeyHash(publicKeyHash)
+	if err != nil {
+		return "", fmt.Errorf("cannot build P2WPKH: [%v]", err)
+	}
+
+	err = c.watchScripts(label, c.config.RescanFromTimestamp, p2pkh, p2wpkh)
+	if err != nil {
+		return "", err
+	}
+
+	return label, nil
+}
+
+// watchScripts makes sure the watch-only wallet observes the given scripts
+// under the given label. Scripts are imported only if the label is not known
+// to the wallet yet. The wallet looks for transactions of imported scripts
+// in blocks created after the given timestamp. An error is returned as long
+// as the wallet rescans the chain for them as the wallet does not know all
+// their transactions until the rescan completes.
+func (c *Connection) watchScripts(
+	label string,
+	timestamp int64,
+	scripts ...bitcoin.Script,
+) error {
+	c.watchedMutex.Lock()
+	defer c.watchedMutex.Unlock()
+
+	if c.watched == nil {
+		labels, err := c.listLabels()
+		if err != nil {
+			return err
+		}
+
+		// The rescan may have been started before the client restarted.
+		// It is not known which labels it was started for so all of them
+		// are considered pending.
+		rescanning, err := c.isWalletRescanning()
+		if err != nil {
+			return err
+		}
+
+		c.watched = make(map[string]bool)
+		c.rescanning = make(map[string]bool)
+		for _, l := range labels {
+			c.watched[l] = true
+			c.rescanning[l] = rescanning
+		}
+	}
+
+	if c.watched[label] {
+		if !c.rescanning[label] {
+			return nil
+		}
+
+		rescanning, err := c.isWalletRescanning()
+		if err != nil {
+			return err
+		}
+
+		if rescanning {
+			return fmt.Errorf(
+				"wallet is rescanning the chain for scripts labeled [%s]",
+				label,
+			)
+		}
+
+		delete(c.rescanning, label)
+
+		return nil
+	}
+
+	if timestamp <= 0 {
+		return fmt.Errorf("rescan timestamp is not configured")
+	}
+
+	type importRequest struct {
+		Descriptor string `json:"desc"`
+		Timestamp  int64  `json:"timestamp"`
+		Label      string `json:"label"`
+	}
+
+	requests := make([]*importRequest, 0, len(scripts))
+	for _, script := range scripts {
+		descriptor, err := c.getDescriptorWithChecksum(
+			fmt.Sprintf("raw(%x)", script),
+		)
+		if err != nil {
+			return err
+		}
+
+		requests = append(
+			requests,
+			&importRequest{
+				Descriptor: descriptor,
+				Timestamp:  timestamp,
+				Label:      label,
+			},
+		)
+	}
+
+	logger.Infof(
+		"adding scripts labeled [%s] to watch-only wallet [%s]",
+		label,
+		c.config.Wallet,
+	)
+
+	type importResult struct {
+		Success bool      `json:"success"`
+		Error   *rpcError `json:"error"`
+	}
+
+	// Importing descriptors may trigger a chain rescan that takes a lot of
+	// time so the request is neither retried nor bounded by the single
+	// request timeout.
+	ctx, cancelCtx := context.WithTimeout(
+		c.parentCtx,
+		c.config.RequestRetryTimeout,
+	)
+	defer cancelCtx()
+
+	var results []*importResult
+	if err := c.wallet.call(
+		ctx,
+		"importdescriptors",
+		&results,
+		requests,
+	); err != nil {
+		// The node keeps importing the descriptors and rescanning the
+		// chain after the request times out. Importing them again would
+		// start another rescan so the import is considered pending once
+		// the wallet knows the label.
+		labels, listErr := c.listLabels()
+		if listErr != nil || !containsLabel(labels, label) {
+			return fmt.Errorf("failed to import descriptors: [%w]", err)
+		}
+
+		logger.Warnf(
+			"scripts labeled [%s] imported to watch-only wallet [%s]; "+
+				"waiting for the chain rescan to complete",
+			label,
+			c.config.Wallet,
+		)
+
+		c.watched[label] = true
+		c.rescanning[label] = true
+
+		return fmt.Errorf(
+			"wallet is rescanning the chain for scripts labeled [%s]",
+			label,
+		)
+	}
+
+	for i, result := range results {
+		if !result.Success {
+			return fmt.Errorf(
+				"failed to import descriptor [%s]: [%v]",
+				requests[i].Descriptor,
+				result.Error,
+			)
+		}
+	}
+
+	c.watched[label] = true
+
+	return nil
+}
+
+// listLabels returns all labels known to the watch-only wallet.
+func (c *Connection) listLabels() ([]string, error) {
+	labels, err := requestWithRetry(
+		c,
+		func(ctx context.Context) ([]string, error) {
+			var result []string
+			err := c.wallet.call(ctx, "listlabels", &result)
+			return result, err
+		},
+		"listlabels",
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list wallet labels: [%w]", err)
+	}
+
+	return labels, nil
+}
+
+// isWalletRescanning returns true if the watch-only wallet is rescanning
+// the chain.
+func (c *Connection) isWalletRescanning() (bool, error) {
+	scanning, err := requestWithRetry(
+		c,
+		func(ctx context.Context) (json.RawMessage, error) {
+			var result struct {
+				// Scanning is false if there is no rescan in progress or
+				// an object describing the rescan progress otherwise.
+				Scanning json.RawMessage `json:"scanning"`
+			}
+			err := c.wallet.call(ctx, "getwalletinfo", &result)
+			return result.Scanning, err
+		},
+		"getwalletinfo",
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to get wallet info: [%w]", err)
+	}
+
+	switch string(scanning) {
+	case "", "null", "false":
+		return false, nil
+	default:
+		return true, nil
+	}
+}
+
+func containsLabel(labels []string, label string) bool {
+	for _, l := range labels {
+		if l == label {
+			return true
+		}
+	}
+
+	return false
+}
+
+// getDescriptorWithChecksum returns the given output descriptor in the
+// canonical form, with the checksum appended.
+func (c *Connection) getDescriptorWithChecksum(
+	descriptor string,
+) (string, error) {
+	result, err := requestWithRetry(
+		c,
+		func(ctx context.Context) (string, error) {
+			var result struct {
+				Descriptor string `json:"descriptor"`
+			}
+			err := c.client.call(ctx, "getdescriptorinfo", &result, descriptor)
+			return result.Descriptor, err
+		},
+		"getdescriptorinfo",
+	)
+	if err != nil {
+		return "", fmt.Errorf(
+			"failed to get info of descriptor [%s]: [%w]",
+			descriptor,
+			err,
+		)
+	}
+
+	return result, nil
+}
@@ -2,9 +2,12 @@ package bitcoin
 
 import (
 	"encoding/binary"
+	"fmt"
 	"math/big"
 
 	"github.com/btcsuite/btcd/blockchain"
+
+	"github.com/keep-network/keep-core/pkg/internal/byteutils"
 )
 
 // BlockHeaderByteLength is the byte length of a serialized block header.
@@ -101,11 +104,36 @@ func (bh *BlockHeader) Deserialize(rawBlockHeader [BlockHeaderByteLength]byte) {
 // block header serialization format:
 // [Version][PreviousBlockHeaderHash][MerkleRootHash][Time][Bits][Nonce].
 func (bh *BlockHeader) Hash() Hash {
-	// TODO: Implementation of the Hash function that consists of the following:
-	//       1. Call bh.Serialize() to get the serialized block hash.
-	//       2. Compute the double SHA-256 over the serialized  block hash.
-	//       3. Construct the Hash instance appropriately.
-	return Hash{}
+	serializedBlockHeader := bh.Serialize()
+	return ComputeHash(serializedBlockHeader[:])
+}
+
+// VerifyHashMeetsDeclaredTarget checks whether the block header's hash is
+// lower than or equal to the difficulty target calculated from the header's
+// own `Bits` field. Returns an error if it is not. This is not a full
+// proof-of-work validation: the `Bits` field is self-declared and it is not
+// checked against the difficulty expected at the header's height, so
+// a header mined against an arbitrarily low difficulty passes this check.
+func (bh *BlockHeader) VerifyHashMeetsDeclaredTarget() error {
+	target := bh.Target()
+	if target.Sign() <= 0 {
+		return fmt.Errorf("invalid target for bits [0x%08x]", bh.Bits)
+	}
+
+	hash := bh.Hash()
+	// The hash is interpreted as a little-endian number so it must be
+	// reversed before being converted to a big integer.
+	hashValue := new(big.Int).SetBytes(byteutils.Reverse(hash[:]))
+
+	if hashValue.Cmp(target) > 0 {
+		return fmt.Errorf(
+			"block header hash [%s] is above the target [%064x]",
+			hash.Hex(ReversedByteOrder),
+			target,
+		)
+	}
+
+	return nil
 }
 
 // Target calculates the difficulty target of a block header. A Bitcoin block
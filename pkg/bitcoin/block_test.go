@@ -107,6 +107,99 @@ func TestBlockHeaderDeserialize(t *testing.T) {
 	}
 }
 
+func TestBlockHeaderHash(t *testing.T) {
+	blockHeader := testnetBlockHeader(t)
+
+	actualHash := blockHeader.Hash()
+
+	testutils.AssertStringsEqual(
+		t,
+		"block header hash",
+		"000000000000002af10911b8db32ed34dc6ea6515f84af5f7b82973c9a839e6d",
+		actualHash.Hex(ReversedByteOrder),
+	)
+}
+
+func TestBlockHeaderVerifyHashMeetsDeclaredTarget(t *testing.T) {
+	var tests = map[string]struct {
+		modifyFn    func(blockHeader *BlockHeader)
+		expectedErr string
+	}{
+		"hash meets target": {
+			modifyFn: func(blockHeader *BlockHeader) {},
+		},
+		"modified nonce": {
+			modifyFn: func(blockHeader *BlockHeader) {
+				blockHeader.Nonce++
+			},
+			expectedErr: "block header hash [ff2c76b05024570058106c129cb76100af48b27797621c6b0fdf52e39f40f6dc] " +
+				"is above the target [00000000000000c02a0000000000000000000000000000000000000000000000]",
+		},
+		"zero target": {
+			modifyFn: func(blockHeader *BlockHeader) {
+				blockHeader.Bits = 0
+			},
+			expectedErr: "invalid target for bits [0x00000000]",
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			blockHeader := testnetBlockHeader(t)
+			test.modifyFn(blockHeader)
+
+			err := blockHeader.VerifyHashMeetsDeclaredTarget()
+
+			if len(test.expectedErr) == 0 {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+
+			if err == nil {
+				t.Fatal("expected error")
+			}
+
+			testutils.AssertStringsEqual(
+				t,
+				"error",
+				test.expectedErr,
+				err.Error(),
+			)
+		})
+	}
+}
+
+// testnetBlockHeader returns the header of a Bitcoin testnet block:
+// https://live.blockcypher.com/btc-testnet/block/000000000000002af10911b8db32ed34dc6ea6515f84af5f7b82973c9a839e6d/
+func testnetBlockHeader(t *testing.T) *BlockHeader {
+	previousBlockHeaderHash, err := NewHashFromString(
+		"000000000066450030efdf72f233ed2495547a32295deea1e2f3a16b1e50a3a5",
+		ReversedByteOrder,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	merkleRootHash, err := NewHashFromString(
+		"1251774996b446f85462d5433f7a3e384ac1569072e617ab31e86da31c247de2",
+		ReversedByteOrder,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &BlockHeader{
+		Version:                 536870916,
+		PreviousBlockHeaderHash: previousBlockHeaderHash,
+		MerkleRootHash:          merkleRootHash,
+		Time:                    1641914003,
+		Bits:                    436256810,
+		Nonce:                   778087099,
+	}
+}
+
 func TestBlockHeaderTarget(t *testing.T) {
 	// Test data comes from a Bitcoin testnet block:
 	// https://live.blockcypher.com/btc-testnet/block/000000000000002af10911b8db32ed34dc6ea6515f84af5f7b82973c9a839e6d/
@@ -96,3 +96,16 @@ type Chain interface {
 	// block height.
 	GetCoinbaseTxHash(blockHeight uint) (Hash, error)
 }
+
+// SpendingTxFinder is an optional extension of Chain implemented by
+// backends able to find transactions spending a given outpoint. Users
+// should type-assert a Chain to find out whether it is supported.
+type SpendingTxFinder interface {
+	// GetSpendingTxHash gets the hash of the confirmed transaction spending
+	// the given transaction outpoint. The returned boolean flag is false if
+	// the outpoint is not spent by any confirmed transaction. Transactions
+	// living in the mempool at the moment of request are not taken into
+	// account. If the transaction holding the outpoint was not found on the
+	// chain, this function returns an error.
+	GetSpendingTxHash(outpoint *TransactionOutpoint) (Hash, bool, error)
+}
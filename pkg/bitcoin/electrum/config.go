@@ -24,6 +24,11 @@ const (
 type Config struct {
 	// URL to the Electrum server in format: `scheme://hostname:port`.
 	URL string
+	// URLs is an ordered list of additional Electrum servers, in format:
+	// `scheme://hostname:port`, used for failover in case the connection with
+	// the current server is lost. The server pointed by URL, if set, has
+	// always the highest priority.
+	URLs []string
 	// Timeout for a single attempt of Electrum connection establishment.
 	ConnectTimeout time.Duration
 	// Timeout for Electrum connection establishment retries.
@@ -37,3 +42,15 @@ type Config struct {
 	// for roughly 10 minutes.
 	KeepAliveInterval time.Duration
 }
+
+// ServerURLs returns the ordered list of all configured Electrum servers.
+// The server pointed by URL, if set, is the first one on the list.
+func (c Config) ServerURLs() []string {
+	urls := make([]string, 0, len(c.URLs)+1)
+
+	if len(c.URL) > 0 {
+		urls = append(urls, c.URL)
+	}
+
+	return append(urls, c.URLs...)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto/sha256"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"math"
 	"sort"
@@ -32,6 +33,7 @@ type Connection struct {
 	client      *electrum.Client
 	clientMutex *sync.Mutex
 	config      Config
+	servers     *serverPool
 }
 
 // Connect initializes handle with provided Config.
@@ -56,21 +58,22 @@ func Connect(parentCtx context.Context, config Config) (bitcoin.Chain, error) {
 		parentCtx:   parentCtx,
 		config:      config,
 		clientMutex: &sync.Mutex{},
+		servers:     newServerPool(config.ServerURLs()),
 	}
 
-	if err := c.electrumConnect(); err != nil {
-		return nil, fmt.Errorf("failed to initialize electrum client: [%w]", err)
+	if c.servers.size() == 0 {
+		return nil, fmt.Errorf("no electrum servers configured")
 	}
 
-	if err := c.verifyServer(); err != nil {
-		return nil, fmt.Errorf("failed to verify electrum server: [%w]", err)
+	if err := c.electrumConnect(false); err != nil {
+		return nil, fmt.Errorf("failed to initialize electrum client: [%w]", err)
 	}
 
-	// Keep the connection alive and check the connection health.
+	// Keep the connection alive and check the connection health. Lost
+	// connections are re-established with the healthiest available server
+	// on the next request.
 	go c.keepAlive()
 
-	// TODO: Add reconnects on lost connection.
-
 	return c, nil
 }
 
@@ -609,6 +612,63 @@ func (c *Connection) GetCoinbaseTxHash(blockHeight uint) (bitcoin.Hash, error) {
 	return txHash, nil
 }
 
+// GetSpendingTxHash gets the hash of the confirmed transaction spending
+// the given transaction outpoint. The returned boolean flag is false if
+// the outpoint is not spent by any confirmed transaction. Transactions
+// living in the mempool at the moment of request are not taken into
+// account. If the transaction holding the outpoint was not found on the
+// chain, this function returns an error.
+func (c *Connection) GetSpendingTxHash(
+	outpoint *bitcoin.TransactionOutpoint,
+) (bitcoin.Hash, bool, error) {
+	transaction, err := c.GetTransaction(outpoint.TransactionHash)
+	if err != nil {
+		return bitcoin.Hash{}, false, fmt.Errorf(
+			"cannot get transaction: [%v]",
+			err,
+		)
+	}
+
+	if int(outpoint.OutputIndex) >= len(transaction.Outputs) {
+		return bitcoin.Hash{}, false, fmt.Errorf(
+			"transaction [%s] has no output [%d]",
+			outpoint.TransactionHash.Hex(bitcoin.ReversedByteOrder),
+			outpoint.OutputIndex,
+		)
+	}
+
+	// The history of the output's script contains both the transaction
+	// holding the outpoint and the transaction spending it, if any.
+	items, err := c.getConfirmedScriptHistory(
+		transaction.Outputs[outpoint.OutputIndex].PublicKeyScript,
+	)
+	if err != nil {
+		return bitcoin.Hash{}, false, err
+	}
+
+	for _, item := range items {
+		if item.txHash == outpoint.TransactionHash {
+			continue
+		}
+
+		candidate, err := c.GetTransaction(item.txHash)
+		if err != nil {
+			return bitcoin.Hash{}, false, fmt.Errorf(
+				"cannot get transaction: [%v]",
+				err,
+			)
+		}
+
+		for _, input := range candidate.Inputs {
+			if *input.Outpoint == *outpoint {
+				return item.txHash, true, nil
+			}
+		}
+	}
+
+	return bitcoin.Hash{}, false, nil
+}
+
 // GetMempoolForPublicKeyHash gets the unconfirmed mempool transactions
 // that pays the given public key hash using either a P2PKH or P2WPKH script.
 // The returned transactions are in an indefinite order.
@@ -1003,58 +1063,49 @@ func convertBtcKbToSatVByte(btcPerKbFee float32) int64 {
 	return int64(math.Round(satPerVByte))
 }
 
-func (c *Connection) electrumConnect() error {
-	var client *electrum.Client
-	var err error
-
+// electrumConnect establishes a connection with the healthiest Electrum
+// server from the pool. If the `excludeCurrent` flag is set, the current
+// server is not taken into account while selecting the first server to
+// connect to. This function is not safe for concurrent use and must be
+// called either during connection initialization or under the client mutex.
+func (c *Connection) electrumConnect(excludeCurrent bool) error {
 	logger.Debug("establishing connection to electrum server...")
-	client, err = connectWithRetry(
-		c,
-		func(ctx context.Context) (*electrum.Client, error) {
-			return electrum.NewClient(ctx, c.config.URL, nil)
-		},
-	)
 
-	if err == nil {
-		c.client = client
+	client, err := connectWithRetry(c, excludeCurrent)
+	if err != nil {
+		return err
 	}
 
-	return err
-}
+	c.client = client
 
-func (c *Connection) verifyServer() error {
-	type Server struct {
-		version  string
-		protocol string
-	}
+	return nil
+}
 
-	server, err := requestWithRetry(
-		c,
-		func(ctx context.Context, client *electrum.Client) (*Server, error) {
-			serverVersion, protocolVersion, err := client.ServerVersion(ctx)
-			if err != nil {
-				return nil, err
-			}
-			return &Server{serverVersion, protocolVersion}, nil
-		},
-		"ServerVersion",
-	)
+// verifyServer checks the version of the Electrum server the given client
+// is connected to.
+func verifyServer(
+	ctx context.Context,
+	client *electrum.Client,
+	url string,
+) error {
+	serverVersion, protocolVersion, err := client.ServerVersion(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to get server version: [%w]", err)
 	}
 
 	logger.Infof(
-		"connected to electrum server [version: [%s], protocol: [%s]]",
-		server.version,
-		server.protocol,
+		"connected to electrum server [%s] [version: [%s], protocol: [%s]]",
+		url,
+		serverVersion,
+		protocolVersion,
 	)
 
 	// Log a warning if connected to a server running an unsupported protocol version.
-	if !slices.Contains(supportedProtocolVersions, server.protocol) {
+	if !slices.Contains(supportedProtocolVersions, protocolVersion) {
 		logger.Warnf(
 			"electrum server [%s] runs an unsupported protocol version: [%s]; expected one of: [%s]",
-			c.config.URL,
-			server.protocol,
+			url,
+			protocolVersion,
 			strings.Join(supportedProtocolVersions, ","),
 		)
 	}
@@ -1077,49 +1128,102 @@ func (c *Connection) keepAlive() {
 			)
 			if err != nil {
 				logger.Errorf(
-					"failed to ping the electrum server; "+
-						"please verify health of the electrum server: [%v]",
+					"failed to ping the electrum server [%s]; "+
+						"switching to another server on the next request: [%v]",
+					c.servers.currentURL(),
 					err,
 				)
+
+				// Shut down the unresponsive client so the next request
+				// triggers a failover to another server.
+				c.clientMutex.Lock()
+				c.client.Shutdown()
+				c.clientMutex.Unlock()
 			} else {
 				// Adjust ticker starting at the time of the latest successful ping.
 				ticker = time.NewTicker(c.config.KeepAliveInterval)
 			}
 		case <-c.parentCtx.Done():
 			ticker.Stop()
+			c.clientMutex.Lock()
 			c.client.Shutdown()
+			c.clientMutex.Unlock()
 			return
 		}
 	}
 }
 
+// connectWithRetry connects to the healthiest Electrum server from the pool
+// and verifies it. If the connection attempt fails, the failure is recorded
+// for the given server and the next attempt is made against another server
+// from the pool, if available. If the `excludeCurrent` flag is set, the
+// current server is not taken into account for the first attempt as well.
 func connectWithRetry(
 	c *Connection,
-	newClientFn func(ctx context.Context) (*electrum.Client, error),
+	excludeCurrent bool,
 ) (*electrum.Client, error) {
 	var result *electrum.Client
+	attempt := 0
+
 	err := wrappers.DoWithDefaultRetry(
 		c.parentCtx,
 		c.config.ConnectRetryTimeout,
 		func(ctx context.Context) error {
-			connectCtx, connectCancel := context.WithTimeout(
-				ctx,
-				c.config.ConnectTimeout,
-			)
-			defer connectCancel()
+			url := c.servers.next(excludeCurrent || attempt > 0)
+			attempt++
+
+			client, err := c.connectServer(ctx, url)
+			if err != nil {
+				c.servers.recordFailure(url)
+
+				logger.Warnf(
+					"failed to connect to electrum server [%s]: [%v]",
+					url,
+					err,
+				)
 
-			client, err := newClientFn(connectCtx)
-			if err == nil {
-				result = client
+				return err
 			}
 
-			return err
+			result = client
+			return nil
 		},
 	)
 
 	return result, err
 }
 
+// connectServer establishes a connection with the Electrum server of the
+// given URL and verifies it.
+func (c *Connection) connectServer(
+	ctx context.Context,
+	url string,
+) (*electrum.Client, error) {
+	connectCtx, connectCancel := context.WithTimeout(
+		ctx,
+		c.config.ConnectTimeout,
+	)
+	defer connectCancel()
+
+	client, err := electrum.NewClient(connectCtx, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	verifyCtx, verifyCancel := context.WithTimeout(
+		ctx,
+		c.config.RequestTimeout,
+	)
+	defer verifyCancel()
+
+	if err := verifyServer(verifyCtx, client, url); err != nil {
+		client.Shutdown()
+		return nil, fmt.Errorf("failed to verify electrum server: [%w]", err)
+	}
+
+	return client, nil
+}
+
 func requestWithRetry[K interface{}](
 	c *Connection,
 	requestFn func(ctx context.Context, client *electrum.Client) (K, error),
@@ -1142,13 +1246,24 @@ func requestWithRetry[K interface{}](
 			defer requestCancel()
 
 			c.clientMutex.Lock()
+			// Capture the server used for the request while holding the
+			// client mutex. The current server may change as soon as the
+			// mutex is released.
+			url := c.servers.currentURL()
 			r, err := requestFn(requestCtx, c.client)
+			if err != nil && c.isConnectionLost(err, url) {
+				// Shut down the client so the next attempt fails over to
+				// another server.
+				c.client.Shutdown()
+			}
 			c.clientMutex.Unlock()
 
 			if err != nil {
 				return fmt.Errorf("request failed: [%w]", err)
 			}
 
+			c.servers.recordSuccess(url)
+
 			result = r
 			return nil
 		})
@@ -1169,18 +1284,53 @@ func requestWithRetry[K interface{}](
 	return result, err
 }
 
+// isConnectionLost checks whether the given error of a request made against
+// the server with the given URL means the connection with that server is
+// lost, and the request should be retried against another server. A request
+// timeout lowers the server's health score but is treated as a lost
+// connection only after serverTimeoutsThreshold consecutive timeouts and only
+// if there are other servers to fail over to. This function must be called
+// under the client mutex.
+func (c *Connection) isConnectionLost(err error, url string) bool {
+	if c.client.IsShutdown() || errors.Is(err, electrum.ErrServerShutdown) {
+		return true
+	}
+
+	if !errors.Is(err, electrum.ErrTimeout) {
+		return false
+	}
+
+	timeouts := c.servers.recordTimeout(url)
+
+	return timeouts >= serverTimeoutsThreshold && c.servers.size() > 1
+}
+
+// reconnectIfShutdown checks whether the connection with the current server
+// is down and, if so, records the server failure and establishes a connection
+// with another server from the pool.
 func (c *Connection) reconnectIfShutdown() error {
 	c.clientMutex.Lock()
 	defer c.clientMutex.Unlock()
 
 	isClientShutdown := c.client.IsShutdown()
 	if isClientShutdown {
-		logger.Warn("connection to electrum server is down; reconnecting...")
-		err := c.electrumConnect()
+		failedURL := c.servers.currentURL()
+		c.servers.recordFailure(failedURL)
+
+		logger.Warnf(
+			"connection to electrum server [%s] is down; reconnecting...",
+			failedURL,
+		)
+
+		err := c.electrumConnect(true)
 		if err != nil {
 			return fmt.Errorf("failed to reconnect to electrum server: [%w]", err)
 		}
-		logger.Info("reconnected to electrum server")
+
+		logger.Infof(
+			"reconnected to electrum server [%s]",
+			c.servers.currentURL(),
+		)
 	}
 
 	return nil
@@ -0,0 +1,165 @@
+package electrum
+
+import (
+	"sync"
+	"time"
+)
+
+// serverFailuresExpiry is the period after which failures recorded for
+// a server are no longer taken into account while computing its health score.
+// This allows a server that recovered from a temporary outage to regain its
+// priority in the pool.
+const serverFailuresExpiry = 30 * time.Minute
+
+// serverTimeoutsThreshold is the number of consecutive request timeouts
+// after which the server is considered unresponsive and the connection
+// fails over to another server. A single slow request, e.g. fetching a long
+// transaction history, does not make the server unresponsive.
+const serverTimeoutsThreshold = 3
+
+// serverHealth holds health information about a single Electrum server.
+type serverHealth struct {
+	url string
+	// consecutiveFailures is the number of failures recorded for the server
+	// since the last successful interaction with it.
+	consecutiveFailures uint
+	// consecutiveTimeouts is the number of request timeouts recorded for
+	// the server since the last successful interaction with it.
+	consecutiveTimeouts uint
+	// lastFailure is the time of the last failure or timeout recorded for
+	// the server.
+	lastFailure time.Time
+}
+
+// score returns the health score of the server. The higher the score, the
+// healthier the server is. A server that has never failed, or has not failed
+// recently, has the maximum score of 0. Both failures and request timeouts
+// lower the score.
+func (sh *serverHealth) score(now time.Time) int {
+	if now.Sub(sh.lastFailure) > serverFailuresExpiry {
+		return 0
+	}
+
+	return -int(sh.consecutiveFailures + sh.consecutiveTimeouts)
+}
+
+// serverPool is an ordered pool of Electrum servers with health scoring.
+// The order of servers determines their priority; the first server has the
+// highest priority. The pool is safe for concurrent use.
+type serverPool struct {
+	mutex   sync.Mutex
+	servers []*serverHealth
+	// current is the index of the server the connection is currently
+	// established with.
+	current int
+}
+
+// newServerPool creates a new server pool for the given ordered list of
+// Electrum server URLs. Duplicated and empty URLs are ignored.
+func newServerPool(urls []string) *serverPool {
+	servers := make([]*serverHealth, 0, len(urls))
+	seen := make(map[string]bool)
+
+	for _, url := range urls {
+		if len(url) == 0 || seen[url] {
+			continue
+		}
+
+		seen[url] = true
+		servers = append(servers, &serverHealth{url: url})
+	}
+
+	return &serverPool{
+		servers: servers,
+	}
+}
+
+// size returns the number of servers in the pool.
+func (sp *serverPool) size() int {
+	sp.mutex.Lock()
+	defer sp.mutex.Unlock()
+
+	return len(sp.servers)
+}
+
+// currentURL returns the URL of the current server.
+func (sp *serverPool) currentURL() string {
+	sp.mutex.Lock()
+	defer sp.mutex.Unlock()
+
+	return sp.servers[sp.current].url
+}
+
+// next selects the healthiest server from the pool, sets it as the current
+// one and returns its URL. If multiple servers have the same health score,
+// the one with the highest priority is selected. If the `excludeCurrent`
+// flag is set and the pool has more than one server, the current server is
+// not taken into account during selection.
+func (sp *serverPool) next(excludeCurrent bool) string {
+	sp.mutex.Lock()
+	defer sp.mutex.Unlock()
+
+	now := time.Now()
+
+	selected := -1
+	for i, server := range sp.servers {
+		if excludeCurrent && len(sp.servers) > 1 && i == sp.current {
+			continue
+		}
+
+		if selected < 0 ||
+			server.score(now) > sp.servers[selected].score(now) {
+			selected = i
+		}
+	}
+
+	sp.current = selected
+
+	return sp.servers[selected].url
+}
+
+// recordFailure records a failure of the server with the given URL.
+func (sp *serverPool) recordFailure(url string) {
+	sp.mutex.Lock()
+	defer sp.mutex.Unlock()
+
+	for _, server := range sp.servers {
+		if server.url == url {
+			server.consecutiveFailures++
+			server.lastFailure = time.Now()
+			return
+		}
+	}
+}
+
+// recordTimeout records a request timeout of the server with the given URL
+// and returns the number of consecutive timeouts of that server.
+func (sp *serverPool) recordTimeout(url string) uint {
+	sp.mutex.Lock()
+	defer sp.mutex.Unlock()
+
+	for _, server := range sp.servers {
+		if server.url == url {
+			server.consecutiveTimeouts++
+			server.lastFailure = time.Now()
+			return server.consecutiveTimeouts
+		}
+	}
+
+	return 0
+}
+
+// recordSuccess records a successful interaction with the server with the
+// given URL and restores its health score.
+func (sp *serverPool) recordSuccess(url string) {
+	sp.mutex.Lock()
+	defer sp.mutex.Unlock()
+
+	for _, server := range sp.servers {
+		if server.url == url {
+			server.consecutiveFailures = 0
+			server.consecutiveTimeouts = 0
+			return
+		}
+	}
+}
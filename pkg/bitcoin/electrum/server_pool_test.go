@@ -0,0 +1,262 @@
+package electrum
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/keep-network/keep-core/internal/testutils"
+)
+
+func TestNewServerPool(t *testing.T) {
+	pool := newServerPool(
+		[]string{
+			"tcp://server1:50001",
+			"",
+			"tcp://server2:50001",
+			"tcp://server1:50001",
+			"tcp://server3:50001",
+		},
+	)
+
+	urls := make([]string, len(pool.servers))
+	for i, server := range pool.servers {
+		urls[i] = server.url
+	}
+
+	expectedURLs := []string{
+		"tcp://server1:50001",
+		"tcp://server2:50001",
+		"tcp://server3:50001",
+	}
+
+	if !reflect.DeepEqual(expectedURLs, urls) {
+		t.Errorf(
+			"unexpected servers\nexpected: %v\nactual:   %v",
+			expectedURLs,
+			urls,
+		)
+	}
+
+	testutils.AssertStringsEqual(
+		t,
+		"current server",
+		"tcp://server1:50001",
+		pool.currentURL(),
+	)
+}
+
+func TestServerPool_Next(t *testing.T) {
+	urls := []string{
+		"tcp://server1:50001",
+		"tcp://server2:50001",
+		"tcp://server3:50001",
+	}
+
+	var tests = map[string]struct {
+		failures       map[string]int
+		current        int
+		excludeCurrent bool
+		expectedURL    string
+	}{
+		"all servers healthy": {
+			expectedURL: "tcp://server1:50001",
+		},
+		"all servers healthy and current excluded": {
+			excludeCurrent: true,
+			expectedURL:    "tcp://server2:50001",
+		},
+		"current server excluded and not the first one": {
+			current:        1,
+			excludeCurrent: true,
+			expectedURL:    "tcp://server1:50001",
+		},
+		"first server failed": {
+			failures:    map[string]int{"tcp://server1:50001": 1},
+			expectedURL: "tcp://server2:50001",
+		},
+		"first and second server failed": {
+			failures: map[string]int{
+				"tcp://server1:50001": 1,
+				"tcp://server2:50001": 1,
+			},
+			expectedURL: "tcp://server3:50001",
+		},
+		"all servers failed": {
+			failures: map[string]int{
+				"tcp://server1:50001": 2,
+				"tcp://server2:50001": 1,
+				"tcp://server3:50001": 3,
+			},
+			expectedURL: "tcp://server2:50001",
+		},
+		"all servers failed and healthiest one excluded": {
+			failures: map[string]int{
+				"tcp://server1:50001": 2,
+				"tcp://server2:50001": 1,
+				"tcp://server3:50001": 3,
+			},
+			current:        1,
+			excludeCurrent: true,
+			expectedURL:    "tcp://server1:50001",
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			pool := newServerPool(urls)
+			pool.current = test.current
+
+			for url, failures := range test.failures {
+				for i := 0; i < failures; i++ {
+					pool.recordFailure(url)
+				}
+			}
+
+			testutils.AssertStringsEqual(
+				t,
+				"next server",
+				test.expectedURL,
+				pool.next(test.excludeCurrent),
+			)
+			testutils.AssertStringsEqual(
+				t,
+				"current server",
+				test.expectedURL,
+				pool.currentURL(),
+			)
+		})
+	}
+}
+
+func TestServerPool_Next_SingleServer(t *testing.T) {
+	pool := newServerPool([]string{"tcp://server1:50001"})
+
+	pool.recordFailure("tcp://server1:50001")
+
+	testutils.AssertStringsEqual(
+		t,
+		"next server",
+		"tcp://server1:50001",
+		pool.next(true),
+	)
+}
+
+func TestServerPool_RecordSuccess(t *testing.T) {
+	pool := newServerPool(
+		[]string{
+			"tcp://server1:50001",
+			"tcp://server2:50001",
+		},
+	)
+
+	pool.recordFailure("tcp://server1:50001")
+	pool.recordFailure("tcp://server1:50001")
+
+	testutils.AssertStringsEqual(
+		t,
+		"next server",
+		"tcp://server2:50001",
+		pool.next(false),
+	)
+
+	pool.recordSuccess("tcp://server1:50001")
+
+	testutils.AssertStringsEqual(
+		t,
+		"next server",
+		"tcp://server1:50001",
+		pool.next(false),
+	)
+}
+
+func TestServerPool_RecordTimeout(t *testing.T) {
+	pool := newServerPool(
+		[]string{
+			"tcp://server1:50001",
+			"tcp://server2:50001",
+		},
+	)
+
+	for i := uint(1); i <= serverTimeoutsThreshold; i++ {
+		testutils.AssertUintsEqual(
+			t,
+			"consecutive timeouts",
+			uint64(i),
+			uint64(pool.recordTimeout("tcp://server1:50001")),
+		)
+	}
+
+	// Timeouts lower the health score of the server.
+	testutils.AssertStringsEqual(
+		t,
+		"next server",
+		"tcp://server2:50001",
+		pool.next(false),
+	)
+
+	pool.recordSuccess("tcp://server1:50001")
+
+	testutils.AssertUintsEqual(
+		t,
+		"consecutive timeouts after success",
+		1,
+		uint64(pool.recordTimeout("tcp://server1:50001")),
+	)
+
+	testutils.AssertUintsEqual(
+		t,
+		"consecutive timeouts of unknown server",
+		0,
+		uint64(pool.recordTimeout("tcp://unknown:50001")),
+	)
+}
+
+func TestServerHealth_Score(t *testing.T) {
+	now := time.Now()
+
+	var tests = map[string]struct {
+		consecutiveFailures uint
+		consecutiveTimeouts uint
+		lastFailure         time.Time
+		expectedScore       int
+	}{
+		"no failures": {
+			expectedScore: 0,
+		},
+		"recent failures": {
+			consecutiveFailures: 3,
+			lastFailure:         now.Add(-time.Minute),
+			expectedScore:       -3,
+		},
+		"recent failures and timeouts": {
+			consecutiveFailures: 1,
+			consecutiveTimeouts: 2,
+			lastFailure:         now.Add(-time.Minute),
+			expectedScore:       -3,
+		},
+		"expired failures": {
+			consecutiveFailures: 3,
+			lastFailure:         now.Add(-serverFailuresExpiry - time.Second),
+			expectedScore:       0,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			server := &serverHealth{
+				url:                 "tcp://server1:50001",
+				consecutiveFailures: test.consecutiveFailures,
+				consecutiveTimeouts: test.consecutiveTimeouts,
+				lastFailure:         test.lastFailure,
+			}
+
+			testutils.AssertIntsEqual(
+				t,
+				"score",
+				test.expectedScore,
+				server.score(now),
+			)
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package esplora
+
+import "time"
+
+const (
+	// DefaultRequestTimeout is a default timeout used for a single attempt of
+	// Esplora API request.
+	DefaultRequestTimeout = 30 * time.Second
+	// DefaultRequestRetryTimeout is a default timeout used for Esplora API
+	// request retries.
+	DefaultRequestRetryTimeout = 2 * time.Minute
+)
+
+// Config holds configurable properties.
+type Config struct {
+	// URL to the Esplora HTTP API in format: `scheme://hostname[:port]/path`,
+	// e.g. `https://blockstream.info/api` or `https://mempool.space/api`.
+	URL string
+	// Timeout for a single attempt of Esplora API request.
+	RequestTimeout time.Duration
+	// Timeout for Esplora API request retries.
+	RequestRetryTimeout time.Duration
+}
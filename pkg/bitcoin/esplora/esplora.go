@@ -0,0 +1,921 @@
+package esplora
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ipfs/go-log"
+	"go.uber.org/zap"
+
+	"github.com/keep-network/keep-common/pkg/wrappers"
+	"github.com/keep-network/keep-core/pkg/bitcoin"
+	"github.com/keep-network/keep-core/pkg/bitcoin/internal/rawhex"
+	"github.com/keep-network/keep-core/pkg/internal/byteutils"
+)
+
+var logger = log.Logger("keep-esplora")
+
+// Connection is a handle for interactions with an Esplora HTTP API.
+type Connection struct {
+	parentCtx context.Context
+	config    Config
+	client    *httpClient
+}
+
+// Connect initializes handle with provided Config.
+func Connect(parentCtx context.Context, config Config) (bitcoin.Chain, error) {
+	if len(config.URL) == 0 {
+		return nil, fmt.Errorf("esplora URL not configured")
+	}
+	if config.RequestTimeout == 0 {
+		config.RequestTimeout = DefaultRequestTimeout
+	}
+	if config.RequestRetryTimeout == 0 {
+		config.RequestRetryTimeout = DefaultRequestRetryTimeout
+	}
+
+	c := &Connection{
+		parentCtx: parentCtx,
+		config:    config,
+		client:    newHTTPClient(config.URL),
+	}
+
+	// Make sure the API is reachable.
+	blockHeight, err := c.GetLatestBlockHeight()
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify esplora API: [%w]", err)
+	}
+
+	logger.Infof(
+		"connected to esplora API [%s] [tip height: [%d]]",
+		config.URL,
+		blockHeight,
+	)
+
+	return c, nil
+}
+
+// GetTransaction gets the transaction with the given transaction hash.
+// If the transaction with the given hash was not found on the chain,
+// this function returns an error.
+func (c *Connection) GetTransaction(
+	transactionHash bitcoin.Hash,
+) (*bitcoin.Transaction, error) {
+	txID := transactionHash.Hex(bitcoin.ReversedByteOrder)
+
+	rawTransaction, err := requestWithRetry(
+		c,
+		func(ctx context.Context) ([]byte, error) {
+			return c.client.get(ctx, "/tx/"+txID+"/hex")
+		},
+		"GetTransactionHex",
+	)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to get raw transaction with ID [%s]: [%w]",
+			txID,
+			err,
+		)
+	}
+
+	result, err := rawhex.ConvertRawTransaction(string(rawTransaction))
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert transaction: [%w]", err)
+	}
+
+	return result, nil
+}
+
+// transactionStatus is the confirmation status of a transaction.
+type transactionStatus struct {
+	Confirmed   bool `json:"confirmed"`
+	BlockHeight uint `json:"block_height"`
+}
+
+// GetTransactionConfirmations gets the number of confirmations for the
+// transaction with the given transaction hash. If the transaction with the
+// given hash was not found on the chain, this function returns an error.
+func (c *Connection) GetTransactionConfirmations(
+	transactionHash bitcoin.Hash,
+) (uint, error) {
+	txID := transactionHash.Hex(bitcoin.ReversedByteOrder)
+
+	status, err := requestWithRetry(
+		c,
+		func(ctx context.Context) (*transactionStatus, error) {
+			var result transactionStatus
+			err := c.client.getJSON(ctx, "/tx/"+txID+"/status", &result)
+			return &result, err
+		},
+		"GetTransactionStatus",
+	)
+	if err != nil {
+		return 0, fmt.Errorf(
+			"failed to get status of transaction with ID [%s]: [%w]",
+			txID,
+			err,
+		)
+	}
+
+	if !status.Confirmed {
+		return 0, nil
+	}
+
+	latestBlockHeight, err := c.GetLatestBlockHeight()
+	if err != nil {
+		return 0, fmt.Errorf(
+			"failed to get the latest block height: [%w]",
+			err,
+		)
+	}
+
+	if latestBlockHeight >= status.BlockHeight {
+		// Add `1` to the calculated difference as if the transaction block
+		// height equals the latest block height the transaction is already
+		// confirmed, so it has one confirmation.
+		return latestBlockHeight - status.BlockHeight + 1, nil
+	}
+
+	return 0, nil
+}
+
+// BroadcastTransaction broadcasts the given transaction over the
+// network of the Bitcoin chain nodes. If the broadcast action could not be
+// done, this function returns an error. This function does not give any
+// guarantees regarding transaction mining. The transaction may be mined or
+// rejected eventually.
+func (c *Connection) BroadcastTransaction(
+	transaction *bitcoin.Transaction,
+) error {
+	rawTx := hex.EncodeToString(transaction.Serialize())
+
+	rawTxLogger := logger.With(
+		zap.String("rawTx", rawTx),
+	)
+	rawTxLogger.Debugf("broadcasting transaction")
+
+	response, err := requestWithRetry(
+		c,
+		func(ctx context.Context) ([]byte, error) {
+			return c.client.post(ctx, "/tx", rawTx)
+		},
+		"BroadcastTransaction",
+	)
+	if err != nil {
+		return fmt.Errorf("failed to broadcast the transaction: [%w]", err)
+	}
+
+	rawTxLogger.Infof("transaction broadcast successful: [%s]", response)
+
+	return nil
+}
+
+// GetLatestBlockHeight gets the height of the latest block (tip). If the
+// latest block was not determined, this function returns an error.
+func (c *Connection) GetLatestBlockHeight() (uint, error) {
+	blockHeight, err := requestWithRetry(
+		c,
+		func(ctx context.Context) (uint, error) {
+			response, err := c.client.get(ctx, "/blocks/tip/height")
+			if err != nil {
+				return 0, err
+			}
+
+			return parseUint(response)
+		},
+		"GetTipHeight",
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get the blocks tip height: [%w]", err)
+	}
+
+	return blockHeight, nil
+}
+
+// GetBlockHeader gets the block header for the given block height. If the
+// block with the given height was not found on the chain, this function
+// returns an error.
+func (c *Connection) GetBlockHeader(
+	blockHeight uint,
+) (*bitcoin.BlockHeader, error) {
+	blockHash, err := c.getBlockHash(blockHeight)
+	if err != nil {
+		return nil, err
+	}
+
+	rawBlockHeader, err := requestWithRetry(
+		c,
+		func(ctx context.Context) ([]byte, error) {
+			return c.client.get(ctx, "/block/"+blockHash+"/header")
+		},
+		"GetBlockHeader",
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get block header: [%w]", err)
+	}
+
+	blockHeader, err := rawhex.ConvertBlockHeader(string(rawBlockHeader))
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert block header: [%w]", err)
+	}
+
+	return blockHeader, nil
+}
+
+// getBlockHash gets the hash of the block with the given height, in the
+// RPC byte order.
+func (c *Connection) getBlockHash(blockHeight uint) (string, error) {
+	blockHash, err := requestWithRetry(
+		c,
+		func(ctx context.Context) ([]byte, error) {
+			return c.client.get(ctx, fmt.Sprintf("/block-height/%d", blockHeight))
+		},
+		"GetBlockHash",
+	)
+	if err != nil {
+		return "", fmt.Errorf(
+			"failed to get hash of block [%d]: [%w]",
+			blockHeight,
+			err,
+		)
+	}
+
+	return strings.TrimSpace(string(blockHash)), nil
+}
+
+// GetTransactionMerkleProof gets the Merkle proof for a given transaction.
+// The transaction's hash and the block the transaction was included in the
+// blockchain need to be provided.
+func (c *Connection) GetTransactionMerkleProof(
+	transactionHash bitcoin.Hash,
+	blockHeight uint,
+) (*bitcoin.TransactionMerkleProof, error) {
+	txID := transactionHash.Hex(bitcoin.ReversedByteOrder)
+
+	type merkleProof struct {
+		BlockHeight uint     `json:"block_height"`
+		Merkle      []string `json:"merkle"`
+		Position    uint     `json:"pos"`
+	}
+
+	proof, err := requestWithRetry(
+		c,
+		func(ctx context.Context) (*merkleProof, error) {
+			var result merkleProof
+			err := c.client.getJSON(ctx, "/tx/"+txID+"/merkle-proof", &result)
+			return &result, err
+		},
+		"GetMerkleProof",
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get merkle proof: [%w]", err)
+	}
+
+	// Esplora determines the block on its own so make sure it is the
+	// expected one.
+	if proof.BlockHeight != blockHeight {
+		return nil, fmt.Errorf(
+			"transaction [%s] is included in block [%d] instead of [%d]",
+			txID,
+			proof.BlockHeight,
+			blockHeight,
+		)
+	}
+
+	return &bitcoin.TransactionMerkleProof{
+		BlockHeight: proof.BlockHeight,
+		MerkleNodes: proof.Merkle,
+		Position:    proof.Position,
+	}, nil
+}
+
+// GetTransactionsForPublicKeyHash gets confirmed transactions that pays the
+// given public key hash using either a P2PKH or P2WPKH script. The returned
+// transactions are ordered by block height in the ascending order, i.e.
+// the latest transaction is at the end of the list. The returned list does
+// not contain unconfirmed transactions living in the mempool at the moment
+// of request. The returned transactions list can be limited using the
+// `limit` parameter. For example, if `limit` is set to `5`, only the
+// latest five transactions will be returned. Note that taking an unlimited
+// transaction history may be time-consuming as this function fetches
+// complete transactions with all necessary data.
+func (c *Connection) GetTransactionsForPublicKeyHash(
+	publicKeyHash [20]byte,
+	limit int,
+) ([]*bitcoin.Transaction, error) {
+	txHashes, err := c.GetTxHashesForPublicKeyHash(publicKeyHash)
+	if err != nil {
+		return nil, err
+	}
+
+	var selectedTxHashes []bitcoin.Hash
+	if len(txHashes) > limit {
+		selectedTxHashes = txHashes[len(txHashes)-limit:]
+	} else {
+		selectedTxHashes = txHashes
+	}
+
+	transactions := make([]*bitcoin.Transaction, len(selectedTxHashes))
+	for i, txHash := range selectedTxHashes {
+		transaction, err := c.GetTransaction(txHash)
+		if err != nil {
+			return nil, fmt.Errorf("cannot get transaction: [%v]", err)
+		}
+
+		transactions[i] = transaction
+	}
+
+	return transactions, nil
+}
+
+// GetTxHashesForPublicKeyHash gets hashes of confirmed transactions that pays
+// the given public key hash using either a P2PKH or P2WPKH script. The returned
+// transactions hashes are ordered by block height in the ascending order, i.e.
+// the latest transaction hash is at the end of the list. The returned list does
+// not contain unconfirmed transactions hashes living in the mempool at the
+// moment of request.
+func (c *Connection) GetTxHashesForPublicKeyHash(
+	publicKeyHash [20]byte,
+) ([]bitcoin.Hash, error) {
+	p2pkh, p2wpkh, err := publicKeyHashScripts(publicKeyHash)
+	if err != nil {
+		return nil, err
+	}
+
+	p2pkhItems, err := c.getConfirmedScriptHistory(p2pkh)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"cannot get P2PKH history for public key hash [0x%x]: [%v]",
+			publicKeyHash,
+			err,
+		)
+	}
+
+	p2wpkhItems, err := c.getConfirmedScriptHistory(p2wpkh)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"cannot get P2WPKH history for public key hash [0x%x]: [%v]",
+			publicKeyHash,
+			err,
+		)
+	}
+
+	items := append(p2pkhItems, p2wpkhItems...)
+
+	sort.SliceStable(
+		items,
+		func(i, j int) bool {
+			return items[i].Status.BlockHeight < items[j].Status.BlockHeight
+		},
+	)
+
+	txHashes := make([]bitcoin.Hash, len(items))
+	for i, item := range items {
+		txHash, err := bitcoin.NewHashFromString(
+			item.TxID,
+			bitcoin.ReversedByteOrder,
+		)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"cannot parse hash [%s]: [%v]",
+				item.TxID,
+				err,
+			)
+		}
+
+		txHashes[i] = txHash
+	}
+
+	return txHashes, nil
+}
+
+// scriptHistoryItem is a transaction returned by the script hash history
+// endpoints. Only the fields needed by the client are decoded.
+type scriptHistoryItem struct {
+	TxID   string            `json:"txid"`
+	Status transactionStatus `json:"status"`
+}
+
+// getConfirmedScriptHistory returns a history of confirmed transactions for
+// the given script (P2PKH, P2WPKH, P2SH, P2WSH, etc.). The returned list
+// is sorted by the block height in the ascending order, i.e. the latest
+// transaction is at the end of the list. The resulting list does not contain
+// unconfirmed transactions living in the mempool at the moment of request.
+func (c *Connection) getConfirmedScriptHistory(
+	script []byte,
+) ([]*scriptHistoryItem, error) {
+	scriptHash := computeScriptHash(script)
+
+	items := make([]*scriptHistoryItem, 0)
+
+	// The API returns confirmed transactions in pages, starting from the
+	// newest ones. The next page is requested using the ID of the last
+	// transaction seen so far.
+	path := "/scripthash/" + scriptHash + "/txs/chain"
+	for {
+		page, err := requestWithRetry(
+			c,
+			func(ctx context.Context) ([]*scriptHistoryItem, error) {
+				var result []*scriptHistoryItem
+				err := c.client.getJSON(ctx, path, &result)
+				return result, err
+			},
+			"GetScriptHashTxsChain",
+		)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"failed to get history for script [0x%x]: [%v]",
+				script,
+				err,
+			)
+		}
+
+		if len(page) == 0 {
+			break
+		}
+
+		items = append(items, page...)
+
+		path = "/scripthash/" + scriptHash + "/txs/chain/" + page[len(page)-1].TxID
+	}
+
+	confirmedItems := make([]*scriptHistoryItem, 0)
+	for _, item := range items {
+		if item.Status.Confirmed {
+			confirmedItems = append(confirmedItems, item)
+		}
+	}
+
+	sort.SliceStable(
+		confirmedItems,
+		func(i, j int) bool {
+			return confirmedItems[i].Status.BlockHeight <
+				confirmedItems[j].Status.BlockHeight
+		},
+	)
+
+	return confirmedItems, nil
+}
+
+// GetCoinbaseTxHash gets the hash of the coinbase transaction for the given
+// block height.
+func (c *Connection) GetCoinbaseTxHash(blockHeight uint) (bitcoin.Hash, error) {
+	blockHash, err := c.getBlockHash(blockHeight)
+	if err != nil {
+		return bitcoin.Hash{}, err
+	}
+
+	response, err := requestWithRetry(
+		c,
+		func(ctx context.Context) ([]byte, error) {
+			return c.client.get(ctx, "/block/"+blockHash+"/txid/0")
+		},
+		"GetBlockTxID",
+	)
+	if err != nil {
+		return bitcoin.Hash{}, fmt.Errorf(
+			"failed to get coinbase tx hash for block height [%v]: [%v]",
+			blockHeight,
+			err,
+		)
+	}
+
+	txHashString := strings.TrimSpace(string(response))
+
+	txHash, err := bitcoin.NewHashFromString(
+		txHashString,
+		bitcoin.ReversedByteOrder,
+	)
+	if err != nil {
+		return bitcoin.Hash{}, fmt.Errorf(
+			"cannot parse hash [%s]: [%v]",
+			txHashString,
+			err,
+		)
+	}
+
+	return txHash, nil
+}
+
+// GetSpendingTxHash gets the hash of the confirmed transaction spending
+// the given transaction outpoint. The returned boolean flag is false if
+// the outpoint is not spent by any confirmed transaction. Transactions
+// living in the mempool at the moment of request are not taken into
+// account. If the transaction holding the outpoint was not found on the
+// chain, this function returns an error.
+func (c *Connection) GetSpendingTxHash(
+	outpoint *bitcoin.TransactionOutpoint,
+) (bitcoin.Hash, bool, error) {
+	txID := outpoint.TransactionHash.Hex(bitcoin.ReversedByteOrder)
+
+	type outspend struct {
+		Spent  bool              `json:"spent"`
+		TxID   string            `json:"txid"`
+		Status transactionStatus `json:"status"`
+	}
+
+	result, err := requestWithRetry(
+		c,
+		func(ctx context.Context) (*outspend, error) {
+			var result outspend
+			err := c.client.getJSON(
+				ctx,
+				fmt.Sprintf("/tx/%s/outspend/%d", txID, outpoint.OutputIndex),
+				&result,
+			)
+			return &result, err
+		},
+		"GetOutspend",
+	)
+	if err != nil {
+		return bitcoin.Hash{}, false, fmt.Errorf(
+			"failed to get spending status of output [%d] of "+
+				"transaction with ID [%s]: [%w]",
+			outpoint.OutputIndex,
+			txID,
+			err,
+		)
+	}
+
+	if !result.Spent || !result.Status.Confirmed {
+		// The API reports outputs of unknown transactions as unspent so
+		// make sure the transaction exists.
+		if _, err := c.GetTransactionConfirmations(
+			outpoint.TransactionHash,
+		); err != nil {
+			return bitcoin.Hash{}, false, err
+		}
+
+		return bitcoin.Hash{}, false, nil
+	}
+
+	txHash, err := bitcoin.NewHashFromString(
+		result.TxID,
+		bitcoin.ReversedByteOrder,
+	)
+	if err != nil {
+		return bitcoin.Hash{}, false, fmt.Errorf(
+			"cannot parse hash [%s]: [%v]",
+			result.TxID,
+			err,
+		)
+	}
+
+	return txHash, true, nil
+}
+
+// GetMempoolForPublicKeyHash gets the unconfirmed mempool transactions
+// that pays the given public key hash using either a P2PKH or P2WPKH script.
+// The returned transactions are in an indefinite order.
+func (c *Connection) GetMempoolForPublicKeyHash(
+	publicKeyHash [20]byte,
+) ([]*bitcoin.Transaction, error) {
+	p2pkh, p2wpkh, err := publicKeyHashScripts(publicKeyHash)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]*scriptHistoryItem, 0)
+	for _, script := range []bitcoin.Script{p2pkh, p2wpkh} {
+		scriptHash := computeScriptHash(script)
+
+		scriptItems, err := requestWithRetry(
+			c,
+			func(ctx context.Context) ([]*scriptHistoryItem, error) {
+				var result []*scriptHistoryItem
+				err := c.client.getJSON(
+					ctx,
+					"/scripthash/"+scriptHash+"/txs/mempool",
+					&result,
+				)
+				return result, err
+			},
+			"GetScriptHashTxsMempool",
+		)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"cannot get mempool items for public key hash [0x%x]: [%v]",
+				publicKeyHash,
+				err,
+			)
+		}
+
+		items = append(items, scriptItems...)
+	}
+
+	transactions := make([]*bitcoin.Transaction, len(items))
+	for i, item := range items {
+		txHash, err := bitcoin.NewHashFromString(
+			item.TxID,
+			bitcoin.ReversedByteOrder,
+		)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"cannot parse hash [%s]: [%v]",
+				item.TxID,
+				err,
+			)
+		}
+
+		transaction, err := c.GetTransaction(txHash)
+		if err != nil {
+			return nil, fmt.Errorf("cannot get transaction: [%v]", err)
+		}
+
+		transactions[i] = transaction
+	}
+
+	return transactions, nil
+}
+
+// GetUtxosForPublicKeyHash gets unspent outputs of confirmed transactions that
+// are controlled by the given public key hash (either a P2PKH or P2WPKH script).
+// The returned UTXOs are ordered by block height in the ascending order, i.e.
+// the latest UTXO is at the end of the list. The returned list does not contain
+// unspent outputs of unconfirmed transactions living in the mempool at the
+// moment of request. Outputs used as inputs of confirmed or mempool
+// transactions are not returned as well because they are no longer UTXOs.
+func (c *Connection) GetUtxosForPublicKeyHash(
+	publicKeyHash [20]byte,
+) ([]*bitcoin.UnspentTransactionOutput, error) {
+	return c.getPublicKeyHashUtxos(publicKeyHash, true)
+}
+
+// GetMempoolUtxosForPublicKeyHash gets unspent outputs of unconfirmed transactions
+// that are controlled by the given public key hash (either a P2PKH or P2WPKH script).
+// The returned UTXOs are in an indefinite order. The returned list does not
+// contain unspent outputs of confirmed transactions. Outputs used as inputs of
+// confirmed or mempool transactions are not returned as well because they are
+// no longer UTXOs.
+func (c *Connection) GetMempoolUtxosForPublicKeyHash(
+	publicKeyHash [20]byte,
+) ([]*bitcoin.UnspentTransactionOutput, error) {
+	return c.getPublicKeyHashUtxos(publicKeyHash, false)
+}
+
+// scriptUtxoItem is an unspent output returned by the script hash UTXO
+// endpoint.
+type scriptUtxoItem struct {
+	TxID   string            `json:"txid"`
+	Vout   uint32            `json:"vout"`
+	Value  int64             `json:"value"`
+	Status transactionStatus `json:"status"`
+}
+
+// getPublicKeyHashUtxos returns unspent outputs of confirmed/unconfirmed
+// transactions that are controlled by the given public key hash.
+//
+// If the `confirmed` flag is true, the returned list contains unspent outputs
+// of confirmed transactions, sorted by the block height in the ascending order,
+// i.e. the latest UTXO is at the end of the list.
+//
+// If the `confirmed` flag is false, the returned list contains unspent outputs
+// of unconfirmed transactions, in an indefinite order.
+//
+// In both cases, the resulted list DOES NOT CONTAIN outputs already used as
+// inputs of confirmed or mempool transactions because they are no longer UTXOs.
+func (c *Connection) getPublicKeyHashUtxos(
+	publicKeyHash [20]byte,
+	confirmed bool,
+) ([]*bitcoin.UnspentTransactionOutput, error) {
+	p2pkh, p2wpkh, err := publicKeyHashScripts(publicKeyHash)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]*scriptUtxoItem, 0)
+	for _, script := range []bitcoin.Script{p2pkh, p2wpkh} {
+		scriptHash := computeScriptHash(script)
+
+		scriptItems, err := requestWithRetry(
+			c,
+			func(ctx context.Context) ([]*scriptUtxoItem, error) {
+				var result []*scriptUtxoItem
+				err := c.client.getJSON(
+					ctx,
+					"/scripthash/"+scriptHash+"/utxo",
+					&result,
+				)
+				return result, err
+			},
+			"GetScriptHashUtxo",
+		)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"cannot get UTXOs for public key hash [0x%x]: [%v]",
+				publicKeyHash,
+				err,
+			)
+		}
+
+		for _, item := range scriptItems {
+			if item.Status.Confirmed == confirmed {
+				items = append(items, item)
+			}
+		}
+	}
+
+	if confirmed {
+		sort.SliceStable(
+			items,
+			func(i, j int) bool {
+				return items[i].Status.BlockHeight < items[j].Status.BlockHeight
+			},
+		)
+	}
+
+	utxos := make([]*bitcoin.UnspentTransactionOutput, len(items))
+	for i, item := range items {
+		txHash, err := bitcoin.NewHashFromString(
+			item.TxID,
+			bitcoin.ReversedByteOrder,
+		)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"cannot parse hash [%s]: [%v]",
+				item.TxID,
+				err,
+			)
+		}
+
+		utxos[i] = &bitcoin.UnspentTransactionOutput{
+			Outpoint: &bitcoin.TransactionOutpoint{
+				TransactionHash: txHash,
+				OutputIndex:     item.Vout,
+			},
+			Value: item.Value,
+		}
+	}
+
+	return utxos, nil
+}
+
+// EstimateSatPerVByteFee returns the estimated sat/vbyte fee for a
+// transaction to be confirmed within the given number of blocks.
+func (c *Connection) EstimateSatPerVByteFee(blocks uint32) (int64, error) {
+	// The API returns fee estimates in sat/vbyte for a fixed set of
+	// confirmation targets expressed in blocks.
+	estimates, err := requestWithRetry(
+		c,
+		func(ctx context.Context) (map[string]float64, error) {
+			var result map[string]float64
+			err := c.client.getJSON(ctx, "/fee-estimates", &result)
+			return result, err
+		},
+		"GetFeeEstimates",
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get fee estimates: [%v]", err)
+	}
+
+	satPerVByte, ok := selectFeeEstimate(estimates, blocks)
+	if !ok {
+		return 0, fmt.Errorf("no fee estimates available")
+	}
+
+	// Make sure the minimum returned sat/vbyte fee is always 1 and round
+	// the returned fee to be an integer.
+	return int64(math.Round(math.Max(satPerVByte, 1))), nil
+}
+
+// selectFeeEstimate selects the estimate for the greatest confirmation target
+// not exceeding the given number of blocks. If there is no such target, the
+// estimate for the lowest available target is selected. This way, the
+// selected fee is high enough to get the transaction confirmed within the
+// given number of blocks.
+func selectFeeEstimate(
+	estimates map[string]float64,
+	blocks uint32,
+) (float64, bool) {
+	targets := make([]uint64, 0, len(estimates))
+	targetEstimates := make(map[uint64]float64, len(estimates))
+	for key, estimate := range estimates {
+		target, err := strconv.ParseUint(key, 10, 32)
+		if err != nil {
+			logger.Warnf("ignoring malformed fee estimate target: [%s]", key)
+			continue
+		}
+
+		targets = append(targets, target)
+		targetEstimates[target] = estimate
+	}
+
+	if len(targets) == 0 {
+		return 0, false
+	}
+
+	sort.Slice(targets, func(i, j int) bool { return targets[i] < targets[j] })
+
+	selected := targets[0]
+	for _, target := range targets {
+		if target > uint64(blocks) {
+			break
+		}
+		selected = target
+	}
+
+	return targetEstimates[selected], true
+}
+
+// publicKeyHashScripts builds P2PKH and P2WPKH scripts for the given public
+// key hash.
+func publicKeyHashScripts(
+	publicKeyHash [20]byte,
+) (bitcoin.Script, bitcoin.Script, error) {
+	p2pkh, err := bitcoin.PayToPublicKeyHash(publicKeyHash)
+	if err != nil {
+		return nil, nil, fmt.Errorf(
+			"cannot build P2PKH for public key hash [0x%x]: [%v]",
+			publicKeyHash,
+			err,
+		)
+	}
+
+	p2wpkh, err := bitcoin.PayToWitnessPublicKeyHash(publicKeyHash)
+	if err != nil {
+		return nil, nil, fmt.Errorf(
+			"cannot build P2WPKH for public key hash [0x%x]: [%v]",
+			publicKeyHash,
+			err,
+		)
+	}
+
+	return p2pkh, p2wpkh, nil
+}
+
+// computeScriptHash computes the script hash used by the script hash
+// endpoints, that is, the SHA-256 of the script in the reversed byte order.
+func computeScriptHash(script []byte) string {
+	scriptHash := sha256.Sum256(script)
+	return hex.EncodeToString(byteutils.Reverse(scriptHash[:]))
+}
+
+// parseUint parses a plain text unsigned integer returned by the API.
+func parseUint(response []byte) (uint, error) {
+	value, err := strconv.ParseUint(strings.TrimSpace(string(response)), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse integer: [%w]", err)
+	}
+
+	return uint(value), nil
+}
+
+func requestWithRetry[K interface{}](
+	c *Connection,
+	requestFn func(ctx context.Context) (K, error),
+	requestName string,
+) (K, error) {
+	startTime := time.Now()
+	logger.Debugf("starting [%s] request to Esplora API", requestName)
+
+	var result K
+	var permanentErr error
+
+	err := wrappers.DoWithDefaultRetry(
+		c.parentCtx,
+		c.config.RequestRetryTimeout,
+		func(ctx context.Context) error {
+			requestCtx, requestCancel := context.WithTimeout(ctx, c.config.RequestTimeout)
+			defer requestCancel()
+
+			r, err := requestFn(requestCtx)
+			if err != nil {
+				if isPermanentErr(err) {
+					// There is no point in retrying the request and
+					// losing time.
+					permanentErr = err
+					return nil
+				}
+
+				return fmt.Errorf("request failed: [%w]", err)
+			}
+
+			result = r
+			return nil
+		})
+	if err == nil {
+		err = permanentErr
+	}
+
+	solveRequestOutcome := func(err error) string {
+		if err != nil {
+			return fmt.Sprintf("error: [%v]", err)
+		}
+		return "success"
+	}
+
+	logger.Debugf("[%s] request to Esplora API completed with [%s] after [%s]",
+		requestName,
+		solveRequestOutcome(err),
+		time.Since(startTime),
+	)
+
+	return result, err
+}
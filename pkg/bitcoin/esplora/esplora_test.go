@@ -0,0 +1,671 @@
+package esplora
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/keep-network/keep-core/internal/testutils"
+	"github.com/keep-network/keep-core/pkg/bitcoin"
+)
+
+const (
+	publicKeyHash = "8db50eb52063ea9d98b3eac91489a90f738986f6"
+	// Script hashes of the P2PKH and P2WPKH scripts of the public key hash.
+	p2pkhScriptHash  = "14374378ab41de4da854350eb999b01ba574ed56622f1e89db78d9a4d5b36c35"
+	p2wpkhScriptHash = "277f2a1e58f8112244f0dfe0f0b02659cd06f6253b862c2d4e348055eb71c1ce"
+
+	blockHash = "ae19a4092c46319f672a33e6e318591ee288c266fbb3bac627ccbf81895ad300"
+	txHash1   = "4a5e1e4baab89f3a32518a88c31bc87f618f76673e2cc77ab2127b7afdeda33b"
+	txHash2   = "0e3e2357e806b6cdb1f70b54c3a3a17b6714ee1f0e68bebb44a74b1efd512098"
+	txHash3   = "9b0fc92260312ce44e74ef369f5c66bbb85848f2eddd5a7a1cde251e54ccfdd5"
+	txHash4   = "435d4aff6d4bc34134877bd3213c17970142fdd04d4113d534120033b9eecb2e"
+
+	rawTransaction = "010000000001036896f9abcac13ce6bd2b80d125bedf997ff6330e999f2f605e" +
+		"a15ea542f2eaf80000000000ffffffffed0ae94da996c6f3b89dfe967675d480" +
+		"8251db93e81022ae9e038d06f92efed400000000c948304502210092327ddff6" +
+		"9a2b8c7ae787c5d590a2f14586089e6339e942d56e82aa42052cd902204c0d17" +
+		"00ba1ac617da27fee032a57937c9607f0187199ed3c46954df845643d7012103" +
+		"989d253b17a6a0f41838b84ff0d20e8898f9d7b1a98f2564da4cc29dcf8581d9" +
+		"4c5c14934b98637ca318a4d6e7ca6ffd1690b8e77df6377508f9f0c90d000395" +
+		"237576a9148db50eb52063ea9d98b3eac91489a90f738986f68763ac6776a914" +
+		"e257eccafbc07c381642ce6e7e55120fb077fbed8804e0250162b175ac68ffff" +
+		"ffffe37f552fc23fa0032bfd00c8eef5f5c22bf85fe4c6e735857719ff8a4ff6" +
+		"6eb80000000000ffffffff0180ed0000000000001600148db50eb52063ea9d98" +
+		"b3eac91489a90f738986f602483045022100baf754252d0d6a49aceba7eb0ec4" +
+		"0b4cc568e8c659e168b96598a11cf56dc078022051117466ee998a3fc7222100" +
+		"6817e8cfe9c2e71ad622ff811a0bf100d888d49c012103989d253b17a6a0f418" +
+		"38b84ff0d20e8898f9d7b1a98f2564da4cc29dcf8581d90003473044022014a5" +
+		"35eb334656665ac69a678dbf7c019c4f13262e9ea4d195c61a00cd5f698d0220" +
+		"23c0062913c4614bdff07f94475ceb4c585df53f71611776c3521ed8f8785913" +
+		"012103989d253b17a6a0f41838b84ff0d20e8898f9d7b1a98f2564da4cc29dcf" +
+		"8581d95c14934b98637ca318a4d6e7ca6ffd1690b8e77df6377508f9f0c90d00" +
+		"0395237576a9148db50eb52063ea9d98b3eac91489a90f738986f68763ac6776" +
+		"a914e257eccafbc07c381642ce6e7e55120fb077fbed8804e0250162b175ac68" +
+		"00000000"
+)
+
+type cannedResponse struct {
+	status int
+	body   string
+}
+
+// cannedResponses are responses of the Esplora API stand-in, keyed by
+// the request method and path.
+var cannedResponses = map[string]cannedResponse{
+	"GET /blocks/tip/height":      {200, "820000"},
+	"GET /tx/" + txHash4 + "/hex": {200, rawTransaction},
+	"GET /tx/" + txHash3 + "/hex": {404, "Transaction not found"},
+	"GET /tx/" + txHash1 + "/status": {
+		200,
+		`{"confirmed":true,"block_height":819991,"block_hash":"` + blockHash + `"}`,
+	},
+	"GET /tx/" + txHash4 + "/status": {200, `{"confirmed":false}`},
+	"POST /tx":                       {200, txHash4},
+	"GET /block-height/820000":       {200, blockHash},
+	"GET /block-height/900000":       {404, "Block not found"},
+	"GET /block/" + blockHash + "/header": {
+		200,
+		"00000020bfb7d7c3e8c5336ab1bca5b1b6e3a85ce2d0a21e6d4e02000000000000" +
+			"0000004ec9a99a8006d19d69bfef41f24fecb39a23335cb6ca3dd76c34b9efd1da" +
+			"3aa400f153659438051739300000",
+	},
+	"GET /block/" + blockHash + "/txid/0": {200, txHash1},
+	"GET /tx/" + txHash2 + "/merkle-proof": {
+		200,
+		`{"block_height":820000,"merkle":[` +
+			`"9b0fc92260312ce44e74ef369f5c66bbb85848f2eddd5a7a1cde251e54ccfdd5",` +
+			`"0c6d915a8ba6b03081052a7417697a1b7fd39df31b4260a652e72e90fdf1ebe9",` +
+			`"b6a8a7e7eccc07e869363ad7204c008b8318c932c5d8ba0ab2c7032ddfccba9c"` +
+			`],"pos":3}`,
+	},
+	"GET /scripthash/" + p2pkhScriptHash + "/txs/chain": {
+		200,
+		`[{"txid":"` + txHash3 + `","status":{"confirmed":true,"block_height":819999}},` +
+			`{"txid":"` + txHash2 + `","status":{"confirmed":true,"block_height":819998}}]`,
+	},
+	"GET /scripthash/" + p2pkhScriptHash + "/txs/chain/" + txHash2: {
+		200,
+		`[{"txid":"` + txHash1 + `","status":{"confirmed":true,"block_height":819991}}]`,
+	},
+	"GET /scripthash/" + p2pkhScriptHash + "/txs/chain/" + txHash1: {200, `[]`},
+	"GET /scripthash/" + p2wpkhScriptHash + "/txs/chain": {
+		200,
+		`[{"txid":"` + txHash2 + `","status":{"confirmed":true,"block_height":819998}}]`,
+	},
+	"GET /scripthash/" + p2wpkhScriptHash + "/txs/chain/" + txHash2: {200, `[]`},
+	"GET /scripthash/" + p2pkhScriptHash + "/txs/mempool":           {200, `[]`},
+	"GET /scripthash/" + p2wpkhScriptHash + "/txs/mempool": {
+		200,
+		`[{"txid":"` + txHash4 + `","status":{"confirmed":false}}]`,
+	},
+	"GET /scripthash/" + p2pkhScriptHash + "/utxo": {
+		200,
+		`[{"txid":"` + txHash2 + `","vout":1,"value":10000,` +
+			`"status":{"confirmed":true,"block_height":819998}}]`,
+	},
+	"GET /scripthash/" + p2wpkhScriptHash + "/utxo": {
+		200,
+		`[{"txid":"` + txHash4 + `","vout":0,"value":60800,"status":{"confirmed":false}},` +
+			`{"txid":"` + txHash1 + `","vout":0,"value":50000000,` +
+			`"status":{"confirmed":true,"block_height":819991}}]`,
+	},
+	"GET /tx/" + txHash1 + "/outspend/0": {
+		200,
+		`{"spent":true,"txid":"` + txHash2 + `","vin":0,` +
+			`"status":{"confirmed":true,"block_height":819998}}`,
+	},
+	"GET /tx/" + txHash1 + "/outspend/1": {
+		200,
+		`{"spent":true,"txid":"` + txHash4 + `","vin":0,` +
+			`"status":{"confirmed":false}}`,
+	},
+	"GET /tx/" + txHash4 + "/outspend/0": {200, `{"spent":false}`},
+	"GET /tx/" + txHash3 + "/outspend/0": {200, `{"spent":false}`},
+	"GET /tx/" + txHash3 + "/status":     {404, "Transaction not found"},
+	"GET /fee-estimates": {
+		200,
+		`{"1":30.1,"2":25.2,"3":20.5,"6":12.345,"144":3.2,"504":1.1,"1008":0.8}`,
+	},
+}
+
+// apiStandIn is an Esplora API stand-in serving canned responses.
+type apiStandIn struct {
+	t      *testing.T
+	server *httptest.Server
+
+	mutex    sync.Mutex
+	requests map[string]int
+	bodies   []string
+}
+
+func newAPIStandIn(t *testing.T) *apiStandIn {
+	asi := &apiStandIn{
+		t:        t,
+		requests: make(map[string]int),
+	}
+	asi.server = httptest.NewServer(http.HandlerFunc(asi.handle))
+	t.Cleanup(asi.server.Close)
+
+	return asi
+}
+
+func (asi *apiStandIn) handle(w http.ResponseWriter, r *http.Request) {
+	key := r.Method + " " + r.URL.Path
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		asi.t.Errorf("cannot read request body: [%v]", err)
+	}
+
+	asi.mutex.Lock()
+	asi.requests[key]++
+	if len(body) > 0 {
+		asi.bodies = append(asi.bodies, string(body))
+	}
+	asi.mutex.Unlock()
+
+	response, ok := cannedResponses[key]
+	if !ok {
+		asi.t.Errorf("unexpected request [%s]", key)
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(response.status)
+	if _, err := w.Write([]byte(response.body)); err != nil {
+		asi.t.Errorf("cannot write response: [%v]", err)
+	}
+}
+
+func (asi *apiStandIn) requestsCount(key string) int {
+	asi.mutex.Lock()
+	defer asi.mutex.Unlock()
+
+	return asi.requests[key]
+}
+
+func newTestConnection(t *testing.T, api *apiStandIn) bitcoin.Chain {
+	ctx, cancelCtx := context.WithCancel(context.Background())
+	t.Cleanup(cancelCtx)
+
+	chain, err := Connect(
+		ctx,
+		Config{
+			URL:                 api.server.URL + "/",
+			RequestTimeout:      1 * time.Second,
+			RequestRetryTimeout: 3 * time.Second,
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return chain
+}
+
+func TestGetTransaction(t *testing.T) {
+	chain := newTestConnection(t, newAPIStandIn(t))
+
+	transaction, err := chain.GetTransaction(hashFromString(t, txHash4))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertStringsEqual(
+		t,
+		"transaction hash",
+		txHash4,
+		transaction.Hash().Hex(bitcoin.ReversedByteOrder),
+	)
+}
+
+func TestGetTransaction_NotFound(t *testing.T) {
+	api := newAPIStandIn(t)
+	chain := newTestConnection(t, api)
+
+	_, err := chain.GetTransaction(hashFromString(t, txHash3))
+
+	expectedErr := &httpError{StatusCode: 404, Message: "Transaction not found"}
+	if !reflect.DeepEqual(expectedErr, unwrapHTTPError(err)) {
+		t.Errorf(
+			"unexpected error\nexpected: %v\nactual:   %v",
+			expectedErr,
+			err,
+		)
+	}
+
+	// Client errors must not be retried.
+	testutils.AssertIntsEqual(
+		t,
+		"requests count",
+		1,
+		api.requestsCount("GET /tx/"+txHash3+"/hex"),
+	)
+}
+
+func TestGetTransactionConfirmations(t *testing.T) {
+	var tests = map[string]struct {
+		txHash                string
+		expectedConfirmations uint
+	}{
+		"confirmed transaction": {
+			txHash:                txHash1,
+			expectedConfirmations: 10,
+		},
+		"mempool transaction": {
+			txHash:                txHash4,
+			expectedConfirmations: 0,
+		},
+	}
+
+	chain := newTestConnection(t, newAPIStandIn(t))
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			confirmations, err := chain.GetTransactionConfirmations(
+				hashFromString(t, test.txHash),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			testutils.AssertUintsEqual(
+				t,
+				"confirmations",
+				uint64(test.expectedConfirmations),
+				uint64(confirmations),
+			)
+		})
+	}
+}
+
+func TestBroadcastTransaction(t *testing.T) {
+	api := newAPIStandIn(t)
+	chain := newTestConnection(t, api)
+
+	transaction, err := chain.GetTransaction(hashFromString(t, txHash4))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := chain.BroadcastTransaction(transaction); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual([]string{rawTransaction}, api.bodies) {
+		t.Errorf(
+			"unexpected request bodies\nexpected: %v\nactual:   %v",
+			[]string{rawTransaction},
+			api.bodies,
+		)
+	}
+}
+
+func TestGetLatestBlockHeight(t *testing.T) {
+	chain := newTestConnection(t, newAPIStandIn(t))
+
+	blockHeight, err := chain.GetLatestBlockHeight()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertUintsEqual(t, "block height", 820000, uint64(blockHeight))
+}
+
+func TestGetBlockHeader(t *testing.T) {
+	chain := newTestConnection(t, newAPIStandIn(t))
+
+	blockHeader, err := chain.GetBlockHeader(820000)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	serializedHeader := blockHeader.Serialize()
+
+	testutils.AssertStringsEqual(
+		t,
+		"block header",
+		cannedResponses["GET /block/"+blockHash+"/header"].body,
+		hex.EncodeToString(serializedHeader[:]),
+	)
+}
+
+func TestGetBlockHeader_NotFound(t *testing.T) {
+	chain := newTestConnection(t, newAPIStandIn(t))
+
+	_, err := chain.GetBlockHeader(900000)
+
+	expectedErr := &httpError{StatusCode: 404, Message: "Block not found"}
+	if !reflect.DeepEqual(expectedErr, unwrapHTTPError(err)) {
+		t.Errorf(
+			"unexpected error\nexpected: %v\nactual:   %v",
+			expectedErr,
+			err,
+		)
+	}
+}
+
+func TestGetTransactionMerkleProof(t *testing.T) {
+	chain := newTestConnection(t, newAPIStandIn(t))
+
+	proof, err := chain.GetTransactionMerkleProof(
+		hashFromString(t, txHash2),
+		820000,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedProof := &bitcoin.TransactionMerkleProof{
+		BlockHeight: 820000,
+		MerkleNodes: []string{
+			"9b0fc92260312ce44e74ef369f5c66bbb85848f2eddd5a7a1cde251e54ccfdd5",
+			"0c6d915a8ba6b03081052a7417697a1b7fd39df31b4260a652e72e90fdf1ebe9",
+			"b6a8a7e7eccc07e869363ad7204c008b8318c932c5d8ba0ab2c7032ddfccba9c",
+		},
+		Position: 3,
+	}
+
+	if !reflect.DeepEqual(expectedProof, proof) {
+		t.Errorf(
+			"unexpected proof\nexpected: %+v\nactual:   %+v",
+			expectedProof,
+			proof,
+		)
+	}
+}
+
+func TestGetTransactionMerkleProof_WrongBlock(t *testing.T) {
+	chain := newTestConnection(t, newAPIStandIn(t))
+
+	_, err := chain.GetTransactionMerkleProof(
+		hashFromString(t, txHash2),
+		820001,
+	)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+
+	testutils.AssertStringsEqual(
+		t,
+		"error",
+		"transaction ["+txHash2+"] is included in block [820000] instead of [820001]",
+		err.Error(),
+	)
+}
+
+func TestGetTxHashesForPublicKeyHash(t *testing.T) {
+	api := newAPIStandIn(t)
+	chain := newTestConnection(t, api)
+
+	txHashes, err := chain.GetTxHashesForPublicKeyHash(
+		publicKeyHashFromString(t, publicKeyHash),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertHashes(t, []string{txHash1, txHash2, txHash2, txHash3}, txHashes)
+
+	// The second page of the P2PKH history must have been requested.
+	testutils.AssertIntsEqual(
+		t,
+		"requests count",
+		1,
+		api.requestsCount(
+			"GET /scripthash/"+p2pkhScriptHash+"/txs/chain/"+txHash2,
+		),
+	)
+}
+
+func TestGetMempoolForPublicKeyHash(t *testing.T) {
+	chain := newTestConnection(t, newAPIStandIn(t))
+
+	transactions, err := chain.GetMempoolForPublicKeyHash(
+		publicKeyHashFromString(t, publicKeyHash),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	txHashes := make([]bitcoin.Hash, len(transactions))
+	for i, transaction := range transactions {
+		txHashes[i] = transaction.Hash()
+	}
+
+	assertHashes(t, []string{txHash4}, txHashes)
+}
+
+func TestGetUtxosForPublicKeyHash(t *testing.T) {
+	chain := newTestConnection(t, newAPIStandIn(t))
+
+	utxos, err := chain.GetUtxosForPublicKeyHash(
+		publicKeyHashFromString(t, publicKeyHash),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedUtxos := []*bitcoin.UnspentTransactionOutput{
+		{
+			Outpoint: &bitcoin.TransactionOutpoint{
+				TransactionHash: hashFromString(t, txHash1),
+				OutputIndex:     0,
+			},
+			Value: 50000000,
+		},
+		{
+			Outpoint: &bitcoin.TransactionOutpoint{
+				TransactionHash: hashFromString(t, txHash2),
+				OutputIndex:     1,
+			},
+			Value: 10000,
+		},
+	}
+
+	if !reflect.DeepEqual(expectedUtxos, utxos) {
+		t.Errorf(
+			"unexpected UTXOs\nexpected: %v\nactual:   %v",
+			expectedUtxos,
+			utxos,
+		)
+	}
+}
+
+func TestGetMempoolUtxosForPublicKeyHash(t *testing.T) {
+	chain := newTestConnection(t, newAPIStandIn(t))
+
+	utxos, err := chain.GetMempoolUtxosForPublicKeyHash(
+		publicKeyHashFromString(t, publicKeyHash),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedUtxos := []*bitcoin.UnspentTransactionOutput{
+		{
+			Outpoint: &bitcoin.TransactionOutpoint{
+				TransactionHash: hashFromString(t, txHash4),
+				OutputIndex:     0,
+			},
+			Value: 60800,
+		},
+	}
+
+	if !reflect.DeepEqual(expectedUtxos, utxos) {
+		t.Errorf(
+			"unexpected UTXOs\nexpected: %v\nactual:   %v",
+			expectedUtxos,
+			utxos,
+		)
+	}
+}
+
+func TestEstimateSatPerVByteFee(t *testing.T) {
+	var tests = map[string]struct {
+		blocks                 uint32
+		expectedSatPerVByteFee int64
+	}{
+		"exact target": {
+			blocks:                 6,
+			expectedSatPerVByteFee: 12,
+		},
+		"target between available ones": {
+			blocks:                 10,
+			expectedSatPerVByteFee: 12,
+		},
+		"target lower than available ones": {
+			blocks:                 0,
+			expectedSatPerVByteFee: 30,
+		},
+		"target greater than available ones": {
+			blocks:                 2000,
+			expectedSatPerVByteFee: 1,
+		},
+	}
+
+	chain := newTestConnection(t, newAPIStandIn(t))
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			satPerVByteFee, err := chain.EstimateSatPerVByteFee(test.blocks)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			testutils.AssertIntsEqual(
+				t,
+				"sat/vbyte fee",
+				int(test.expectedSatPerVByteFee),
+				int(satPerVByteFee),
+			)
+		})
+	}
+}
+
+func TestGetCoinbaseTxHash(t *testing.T) {
+	chain := newTestConnection(t, newAPIStandIn(t))
+
+	txHash, err := chain.GetCoinbaseTxHash(820000)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertHashes(t, []string{txHash1}, []bitcoin.Hash{txHash})
+}
+
+func TestGetSpendingTxHash(t *testing.T) {
+	var tests = map[string]struct {
+		txHash          string
+		outputIndex     uint32
+		expectedSpent   bool
+		expectedTxHash  string
+		expectedErrCode int
+	}{
+		"spent by confirmed transaction": {
+			txHash:         txHash1,
+			outputIndex:    0,
+			expectedSpent:  true,
+			expectedTxHash: txHash2,
+		},
+		"spent by mempool transaction": {
+			txHash:        txHash1,
+			outputIndex:   1,
+			expectedSpent: false,
+		},
+		"unspent": {
+			txHash:        txHash4,
+			outputIndex:   0,
+			expectedSpent: false,
+		},
+		"unknown transaction": {
+			txHash:          txHash3,
+			outputIndex:     0,
+			expectedErrCode: 404,
+		},
+	}
+
+	chain := newTestConnection(t, newAPIStandIn(t))
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			txHash, spent, err := chain.(bitcoin.SpendingTxFinder).GetSpendingTxHash(
+				&bitcoin.TransactionOutpoint{
+					TransactionHash: hashFromString(t, test.txHash),
+					OutputIndex:     test.outputIndex,
+				},
+			)
+
+			if test.expectedErrCode != 0 {
+				httpErr := unwrapHTTPError(err)
+				if httpErr == nil || httpErr.StatusCode != test.expectedErrCode {
+					t.Fatalf("unexpected error: [%v]", err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			testutils.AssertBoolsEqual(t, "spent", test.expectedSpent, spent)
+
+			if test.expectedSpent {
+				assertHashes(t, []string{test.expectedTxHash}, []bitcoin.Hash{txHash})
+			}
+		})
+	}
+}
+
+func hashFromString(t *testing.T, hash string) bitcoin.Hash {
+	result, err := bitcoin.NewHashFromString(hash, bitcoin.ReversedByteOrder)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return result
+}
+
+func publicKeyHashFromString(t *testing.T, publicKeyHash string) [20]byte {
+	bytes, err := hex.DecodeString(publicKeyHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var result [20]byte
+	copy(result[:], bytes)
+
+	return result
+}
+
+func unwrapHTTPError(err error) *httpError {
+	var he *httpError
+	if errors.As(err, &he) {
+		return he
+	}
+
+	return nil
+}
+
+func assertHashes(t *testing.T, expected []string, actual []bitcoin.Hash) {
+	actualStrings := make([]string, len(actual))
+	for i, hash := range actual {
+		actualStrings[i] = hash.Hex(bitcoin.ReversedByteOrder)
+	}
+
+	if !reflect.DeepEqual(expected, actualStrings) {
+		t.Errorf(
+			"unexpected hashes\nexpected: %v\nactual:   %v",
+			expected,
+			actualStrings,
+		)
+	}
+}
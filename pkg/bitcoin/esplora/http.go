@@ -0,0 +1,126 @@
+package esplora
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// httpError is an error returned by the Esplora API along with a non-2xx
+// HTTP status code.
+type httpError struct {
+	StatusCode int
+	Message    string
+}
+
+func (he *httpError) Error() string {
+	return fmt.Sprintf(
+		"unexpected HTTP status [%d %s]: %s",
+		he.StatusCode,
+		http.StatusText(he.StatusCode),
+		he.Message,
+	)
+}
+
+// isPermanentErr checks whether the given request error is permanent, i.e.
+// retrying the request will not change its outcome. Client errors are
+// permanent unless the API rate limit has been hit.
+func isPermanentErr(err error) bool {
+	var he *httpError
+	if errors.As(err, &he) {
+		return he.StatusCode >= 400 &&
+			he.StatusCode < 500 &&
+			he.StatusCode != http.StatusTooManyRequests
+	}
+
+	return false
+}
+
+// httpClient is a minimal Esplora HTTP API client.
+type httpClient struct {
+	url    string
+	client *http.Client
+}
+
+func newHTTPClient(url string) *httpClient {
+	return &httpClient{
+		url:    strings.TrimSuffix(url, "/"),
+		client: &http.Client{},
+	}
+}
+
+// get executes a GET request against the given API path and returns the
+// response body.
+func (hc *httpClient) get(ctx context.Context, path string) ([]byte, error) {
+	return hc.do(ctx, http.MethodGet, path, nil)
+}
+
+// getJSON executes a GET request against the given API path and unmarshals
+// the JSON response body into the given result value.
+func (hc *httpClient) getJSON(
+	ctx context.Context,
+	path string,
+	result interface{},
+) error {
+	body, err := hc.get(ctx, path)
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(body, result); err != nil {
+		return fmt.Errorf("failed to unmarshal response: [%w]", err)
+	}
+
+	return nil
+}
+
+// post executes a POST request with the given plain text body against the
+// given API path and returns the response body.
+func (hc *httpClient) post(
+	ctx context.Context,
+	path string,
+	body string,
+) ([]byte, error) {
+	return hc.do(ctx, http.MethodPost, path, strings.NewReader(body))
+}
+
+func (hc *httpClient) do(
+	ctx context.Context,
+	method string,
+	path string,
+	body io.Reader,
+) ([]byte, error) {
+	request, err := http.NewRequestWithContext(ctx, method, hc.url+path, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: [%w]", err)
+	}
+
+	if body != nil {
+		request.Header.Set("Content-Type", "text/plain")
+	}
+
+	response, err := hc.client.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: [%w]", err)
+	}
+	defer response.Body.Close()
+
+	responseBody, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: [%w]", err)
+	}
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return nil, &httpError{
+			StatusCode: response.StatusCode,
+			Message:    string(bytes.TrimSpace(responseBody)),
+		}
+	}
+
+	return responseBody, nil
+}
@@ -17,6 +17,11 @@ var signaturePlaceholder = make([]byte, 72)
 // Compressed public keys have always 33 bytes.
 var publicKeyPlaceholder = make([]byte, 33)
 
+// BIP-340 Schnorr signatures have always 64 bytes. BIP-341 appends one more
+// byte if a sighash type other than SIGHASH_DEFAULT is used. For fee
+// estimation purposes, we take the greatest possible value.
+var schnorrSignaturePlaceholder = make([]byte, 65)
+
 // TransactionSizeEstimator is a component allowing to estimate the size
 // of a Bitcoin transaction of the provided shape, without constructing it.
 type TransactionSizeEstimator struct {
@@ -127,6 +132,32 @@ func (tse *TransactionSizeEstimator) AddScriptHashInputs(
 	return tse
 }
 
+// AddTaprootInputs adds the provided count of P2TR inputs spent using the
+// key path to the estimation. If the estimator already errored out during
+// previous actions, this method does nothing.
+func (tse *TransactionSizeEstimator) AddTaprootInputs(
+	count int,
+) *TransactionSizeEstimator {
+	if tse.err != nil {
+		return tse
+	}
+
+	// The key path spend witness consists of the signature only.
+	witness := wire.TxWitness{schnorrSignaturePlaceholder}
+
+	for i := 0; i < count; i++ {
+		tse.internal.AddTxIn(
+			wire.NewTxIn(
+				wire.NewOutPoint((*chainhash.Hash)(&[32]byte{}), 0),
+				nil,
+				witness,
+			),
+		)
+	}
+
+	return tse
+}
+
 // AddPublicKeyHashOutputs adds the provided count of P2WPKH (isWitness is true)
 // or P2PKH (isWitness is false) outputs to the estimation. If the estimator
 // already errored out during previous actions, this method does nothing.
@@ -201,6 +232,31 @@ func (tse *TransactionSizeEstimator) AddScriptHashOutputs(
 	return tse
 }
 
+// AddTaprootOutputs adds the provided count of P2TR outputs to the
+// estimation. If the estimator already errored out during previous actions,
+// this method does nothing.
+func (tse *TransactionSizeEstimator) AddTaprootOutputs(
+	count int,
+) *TransactionSizeEstimator {
+	if tse.err != nil {
+		return tse
+	}
+
+	scriptPlaceholder, err := PayToTaproot([32]byte{})
+	if err != nil {
+		tse.err = err
+		return tse
+	}
+
+	for i := 0; i < count; i++ {
+		tse.internal.AddTxOut(
+			wire.NewTxOut(0, scriptPlaceholder),
+		)
+	}
+
+	return tse
+}
+
 // VirtualSize returns the virtual size of the transaction whose shape was
 // provided to the estimator. If any errors occurred while building the
 // transaction shape, the first error will be returned.
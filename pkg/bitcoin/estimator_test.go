@@ -63,6 +63,21 @@ func TestTransactionSizeEstimator_VirtualSize(t *testing.T) {
 				AddScriptHashOutputs(1, true),
 			expectedVirtualSize: 250,
 		},
+		// A key path spend with a 65-byte signature. Real key path spends
+		// using SIGHASH_DEFAULT are 111 vbytes.
+		"1 P2TR input and 1 P2TR output": {
+			estimator: NewTransactionSizeEstimator().
+				AddTaprootInputs(1).
+				AddTaprootOutputs(1),
+			expectedVirtualSize: 112,
+		},
+		"1 P2WPKH input and 2 outputs (1 P2TR, 1 P2WPKH)": {
+			estimator: NewTransactionSizeEstimator().
+				AddPublicKeyHashInputs(1, true).
+				AddTaprootOutputs(1).
+				AddPublicKeyHashOutputs(1, true),
+			expectedVirtualSize: 153,
+		},
 	}
 
 	for testName, test := range tests {
@@ -0,0 +1,303 @@
+package bitcoin
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/ipfs/go-log"
+)
+
+var logger = log.Logger("keep-bitcoin")
+
+// DefaultFeeSmoothingPercentile is the default percentile of recent fee
+// estimates returned by the FeePolicy.
+const DefaultFeeSmoothingPercentile = 50
+
+// DefaultConfirmationTarget is the default number of blocks within which
+// wallet transactions are supposed to be confirmed.
+const DefaultConfirmationTarget = uint32(1)
+
+// FeeSource is a source of sat/vbyte fee estimates. Each Chain is a valid
+// FeeSource.
+type FeeSource interface {
+	// EstimateSatPerVByteFee returns the estimated sat/vbyte fee for a
+	// transaction to be confirmed within the given number of blocks.
+	EstimateSatPerVByteFee(blocks uint32) (int64, error)
+}
+
+// FeePolicyConfig holds the configuration of the FeePolicy.
+type FeePolicyConfig struct {
+	// StaticFile is the path to a JSON file holding static sat/vbyte fee
+	// estimates keyed by confirmation targets, e.g. `{"1": 25, "6": 10}`.
+	// If set, the file is used as an additional fee source. The file is read
+	// upon each estimation so, it can be updated without restarting the
+	// client.
+	StaticFile string
+	// MinSatPerVByteFee is the minimum sat/vbyte fee returned by the policy.
+	// Zero means no minimum.
+	MinSatPerVByteFee int64
+	// MaxSatPerVByteFee is the maximum sat/vbyte fee returned by the policy.
+	// Zero means no maximum.
+	MaxSatPerVByteFee int64
+	// SmoothingWindow is the count of recent estimates, per confirmation
+	// target, the smoothed estimate is computed from. Zero or one means no
+	// smoothing.
+	SmoothingWindow int
+	// SmoothingPercentile is the percentile of recent estimates returned as
+	// the smoothed estimate. Zero means DefaultFeeSmoothingPercentile.
+	SmoothingPercentile int
+	// ConfirmationTargets holds the number of blocks within which
+	// transactions of the given wallet actions are supposed to be confirmed.
+	// Keys are wallet action names and are case-insensitive. Actions that
+	// are not listed use DefaultConfirmationTarget.
+	ConfirmationTargets map[string]uint32
+}
+
+// FeePolicy is a Chain decorator that replaces the fee estimates of the
+// underlying chain with estimates combined from multiple fee sources. The
+// median of estimates returned by all working sources is smoothed using
+// the given percentile of recent estimates and clamped to the configured
+// range. This protects against overpaying during fee spikes and
+// underpaying right after them. All other Chain calls are passed to the
+// underlying chain.
+type FeePolicy struct {
+	Chain
+
+	config  FeePolicyConfig
+	sources []FeeSource
+
+	historyMutex sync.Mutex
+	// history holds recent combined estimates, keyed by confirmation
+	// targets, in the order they were obtained.
+	history map[uint32][]int64
+}
+
+// NewFeePolicy creates a new fee policy decorating the given chain. If no
+// sources are given, the chain itself is used as the only fee source. If
+// the config points to a static file, the file is used as an additional
+// fee source.
+func NewFeePolicy(
+	chain Chain,
+	config FeePolicyConfig,
+	sources ...FeeSource,
+) (*FeePolicy, error) {
+	if config.MinSatPerVByteFee < 0 || config.MaxSatPerVByteFee < 0 {
+		return nil, fmt.Errorf("sat/vbyte fee clamps must not be negative")
+	}
+
+	if config.MaxSatPerVByteFee > 0 &&
+		config.MinSatPerVByteFee > config.MaxSatPerVByteFee {
+		return nil, fmt.Errorf(
+			"minimum sat/vbyte fee [%d] is greater than maximum [%d]",
+			config.MinSatPerVByteFee,
+			config.MaxSatPerVByteFee,
+		)
+	}
+
+	if config.SmoothingWindow < 0 {
+		return nil, fmt.Errorf("smoothing window must not be negative")
+	}
+
+	if config.SmoothingPercentile < 0 || config.SmoothingPercentile > 100 {
+		return nil, fmt.Errorf(
+			"smoothing percentile [%d] must be in range [0, 100]",
+			config.SmoothingPercentile,
+		)
+	}
+
+	if config.SmoothingPercentile == 0 {
+		config.SmoothingPercentile = DefaultFeeSmoothingPercentile
+	}
+
+	if len(sources) == 0 {
+		sources = []FeeSource{chain}
+	}
+
+	if len(config.StaticFile) > 0 {
+		sources = append(sources, NewStaticFeeSource(config.StaticFile))
+	}
+
+	return &FeePolicy{
+		Chain:   chain,
+		config:  config,
+		sources: sources,
+		history: make(map[uint32][]int64),
+	}, nil
+}
+
+// EstimateSatPerVByteFee returns the sat/vbyte fee for a transaction to be
+// confirmed within the given number of blocks, according to the policy.
+func (fp *FeePolicy) EstimateSatPerVByteFee(blocks uint32) (int64, error) {
+	estimates := make([]int64, 0)
+	for i, source := range fp.sources {
+		estimate, err := source.EstimateSatPerVByteFee(blocks)
+		if err != nil {
+			logger.Warnf(
+				"fee source [%d] failed to estimate fee: [%v]",
+				i,
+				err,
+			)
+			continue
+		}
+
+		estimates = append(estimates, estimate)
+	}
+
+	if len(estimates) == 0 {
+		return 0, fmt.Errorf("none of the fee sources estimated fee")
+	}
+
+	combined := percentile(estimates, 50)
+	smoothed := fp.smooth(blocks, combined)
+
+	return fp.clamp(smoothed), nil
+}
+
+// ConfirmationTarget returns the number of blocks within which transactions
+// of the given wallet action are supposed to be confirmed.
+func (fp *FeePolicy) ConfirmationTarget(action string) uint32 {
+	for configuredAction, target := range fp.config.ConfirmationTargets {
+		if strings.EqualFold(configuredAction, action) && target > 0 {
+			return target
+		}
+	}
+
+	return DefaultConfirmationTarget
+}
+
+// smooth records the given estimate for the given confirmation target and
+// returns the configured percentile of recent estimates.
+func (fp *FeePolicy) smooth(blocks uint32, estimate int64) int64 {
+	if fp.config.SmoothingWindow <= 1 {
+		return estimate
+	}
+
+	fp.historyMutex.Lock()
+	defer fp.historyMutex.Unlock()
+
+	history := append(fp.history[blocks], estimate)
+	if len(history) > fp.config.SmoothingWindow {
+		history = history[len(history)-fp.config.SmoothingWindow:]
+	}
+	fp.history[blocks] = history
+
+	return percentile(history, fp.config.SmoothingPercentile)
+}
+
+// clamp limits the given estimate to the configured range.
+func (fp *FeePolicy) clamp(estimate int64) int64 {
+	if fp.config.MinSatPerVByteFee > 0 &&
+		estimate < fp.config.MinSatPerVByteFee {
+		return fp.config.MinSatPerVByteFee
+	}
+
+	if fp.config.MaxSatPerVByteFee > 0 &&
+		estimate > fp.config.MaxSatPerVByteFee {
+		return fp.config.MaxSatPerVByteFee
+	}
+
+	return estimate
+}
+
+// percentile returns the given percentile of values using the nearest-rank
+// method. The values slice must not be empty and is not modified.
+func percentile(values []int64, p int) int64 {
+	sorted := make([]int64, len(values))
+	copy(sorted, values)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+
+	return sorted[rank-1]
+}
+
+// StaticFeeSource is a FeeSource returning sat/vbyte fee estimates read
+// from a JSON file. The file holds an object whose keys are confirmation
+// targets and values are sat/vbyte fees, e.g. `{"1": 25, "6": 10}`.
+type StaticFeeSource struct {
+	path string
+}
+
+// NewStaticFeeSource creates a new static fee source reading estimates
+// from the given file.
+func NewStaticFeeSource(path string) *StaticFeeSource {
+	return &StaticFeeSource{path: path}
+}
+
+// EstimateSatPerVByteFee returns the fee of the greatest confirmation
+// target not exceeding the given number of blocks. If there is no such
+// target, the fee of the lowest target is returned.
+func (sfs *StaticFeeSource) EstimateSatPerVByteFee(blocks uint32) (int64, error) {
+	content, err := os.ReadFile(sfs.path)
+	if err != nil {
+		return 0, fmt.Errorf("cannot read static fee file: [%v]", err)
+	}
+
+	var entries map[string]int64
+	if err := json.Unmarshal(content, &entries); err != nil {
+		return 0, fmt.Errorf("cannot parse static fee file: [%v]", err)
+	}
+
+	targets := make([]uint32, 0, len(entries))
+	fees := make(map[uint32]int64, len(entries))
+	for key, fee := range entries {
+		target, err := strconv.ParseUint(key, 10, 32)
+		if err != nil {
+			return 0, fmt.Errorf(
+				"invalid confirmation target [%s] in static fee file: [%v]",
+				key,
+				err,
+			)
+		}
+
+		if fee <= 0 {
+			return 0, fmt.Errorf(
+				"invalid fee [%d] for confirmation target [%s] in "+
+					"static fee file",
+				fee,
+				key,
+			)
+		}
+
+		targets = append(targets, uint32(target))
+		fees[uint32(target)] = fee
+	}
+
+	if len(targets) == 0 {
+		return 0, fmt.Errorf("static fee file has no entries")
+	}
+
+	sort.Slice(targets, func(i, j int) bool { return targets[i] < targets[j] })
+
+	result := fees[targets[0]]
+	for _, target := range targets {
+		if target > blocks {
+			break
+		}
+		result = fees[target]
+	}
+
+	return result, nil
+}
+
+// ConfirmationTarget returns the number of blocks within which transactions
+// of the given wallet action are supposed to be confirmed. If the given
+// chain applies a FeePolicy, the target configured for the action is
+// returned. Otherwise, DefaultConfirmationTarget is returned.
+func ConfirmationTarget(chain Chain, action string) uint32 {
+	if resolver, ok := chain.(interface {
+		ConfirmationTarget(action string) uint32
+	}); ok {
+		return resolver.ConfirmationTarget(action)
+	}
+
+	return DefaultConfirmationTarget
+}
@@ -0,0 +1,295 @@
+package bitcoin
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/keep-network/keep-core/internal/testutils"
+)
+
+// feeSourceFn is a FeeSource implementation backed by a function.
+type feeSourceFn func(blocks uint32) (int64, error)
+
+func (fsf feeSourceFn) EstimateSatPerVByteFee(blocks uint32) (int64, error) {
+	return fsf(blocks)
+}
+
+// feeSequence returns a FeeSource returning the given estimates in order.
+// The last estimate is repeated once the sequence is exhausted.
+func feeSequence(estimates ...int64) FeeSource {
+	index := 0
+	return feeSourceFn(func(blocks uint32) (int64, error) {
+		estimate := estimates[index]
+		if index < len(estimates)-1 {
+			index++
+		}
+		return estimate, nil
+	})
+}
+
+func failingFeeSource() FeeSource {
+	return feeSourceFn(func(blocks uint32) (int64, error) {
+		return 0, fmt.Errorf("unavailable")
+	})
+}
+
+func TestNewFeePolicy(t *testing.T) {
+	var tests = map[string]struct {
+		config      FeePolicyConfig
+		expectedErr bool
+	}{
+		"empty config": {
+			config: FeePolicyConfig{},
+		},
+		"valid clamps": {
+			config: FeePolicyConfig{MinSatPerVByteFee: 1, MaxSatPerVByteFee: 1},
+		},
+		"negative clamp": {
+			config:      FeePolicyConfig{MinSatPerVByteFee: -1},
+			expectedErr: true,
+		},
+		"minimum greater than maximum": {
+			config:      FeePolicyConfig{MinSatPerVByteFee: 2, MaxSatPerVByteFee: 1},
+			expectedErr: true,
+		},
+		"negative smoothing window": {
+			config:      FeePolicyConfig{SmoothingWindow: -1},
+			expectedErr: true,
+		},
+		"percentile out of range": {
+			config:      FeePolicyConfig{SmoothingPercentile: 101},
+			expectedErr: true,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			_, err := NewFeePolicy(newLocalChain(), test.config)
+
+			testutils.AssertBoolsEqual(
+				t,
+				"error presence",
+				test.expectedErr,
+				err != nil,
+			)
+		})
+	}
+}
+
+func TestFeePolicy_EstimateSatPerVByteFee(t *testing.T) {
+	var tests = map[string]struct {
+		config            FeePolicyConfig
+		sources           []FeeSource
+		calls             int
+		expectedEstimates []int64
+		expectedErr       bool
+	}{
+		"median of sources": {
+			sources: []FeeSource{
+				feeSequence(10),
+				feeSequence(30),
+				feeSequence(20),
+			},
+			calls:             1,
+			expectedEstimates: []int64{20},
+		},
+		"failing source ignored": {
+			sources: []FeeSource{
+				feeSequence(10),
+				failingFeeSource(),
+			},
+			calls:             1,
+			expectedEstimates: []int64{10},
+		},
+		"all sources failing": {
+			sources: []FeeSource{
+				failingFeeSource(),
+				failingFeeSource(),
+			},
+			calls:       1,
+			expectedErr: true,
+		},
+		"clamped to range": {
+			config: FeePolicyConfig{
+				MinSatPerVByteFee: 5,
+				MaxSatPerVByteFee: 50,
+			},
+			sources:           []FeeSource{feeSequence(1, 20, 100)},
+			calls:             3,
+			expectedEstimates: []int64{5, 20, 50},
+		},
+		"smoothed spike": {
+			config: FeePolicyConfig{
+				SmoothingWindow: 3,
+			},
+			sources:           []FeeSource{feeSequence(10, 100, 12, 11)},
+			calls:             4,
+			expectedEstimates: []int64{10, 10, 12, 12},
+		},
+		"smoothed with custom percentile": {
+			config: FeePolicyConfig{
+				SmoothingWindow:     3,
+				SmoothingPercentile: 100,
+			},
+			sources:           []FeeSource{feeSequence(10, 100, 12, 11, 13)},
+			calls:             5,
+			expectedEstimates: []int64{10, 100, 100, 100, 13},
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			feePolicy, err := NewFeePolicy(
+				newLocalChain(),
+				test.config,
+				test.sources...,
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			estimates := make([]int64, 0)
+			for i := 0; i < test.calls; i++ {
+				estimate, err := feePolicy.EstimateSatPerVByteFee(1)
+				if test.expectedErr {
+					if err == nil {
+						t.Fatal("expected error")
+					}
+					return
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				estimates = append(estimates, estimate)
+			}
+
+			testutils.AssertIntsEqual(
+				t,
+				"estimates count",
+				len(test.expectedEstimates),
+				len(estimates),
+			)
+			for i := range estimates {
+				testutils.AssertIntsEqual(
+					t,
+					fmt.Sprintf("estimate [%d]", i),
+					int(test.expectedEstimates[i]),
+					int(estimates[i]),
+				)
+			}
+		})
+	}
+}
+
+func TestFeePolicy_ConfirmationTarget(t *testing.T) {
+	feePolicy, err := NewFeePolicy(
+		newLocalChain(),
+		FeePolicyConfig{
+			ConfirmationTargets: map[string]uint32{
+				// Viper lowercases map keys read from the config file.
+				"depositsweep": 6,
+				"Redemption":   2,
+			},
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertIntsEqual(
+		t,
+		"deposit sweep target",
+		6,
+		int(ConfirmationTarget(feePolicy, "DepositSweep")),
+	)
+	testutils.AssertIntsEqual(
+		t,
+		"redemption target",
+		2,
+		int(ConfirmationTarget(feePolicy, "Redemption")),
+	)
+	testutils.AssertIntsEqual(
+		t,
+		"moving funds target",
+		int(DefaultConfirmationTarget),
+		int(ConfirmationTarget(feePolicy, "MovingFunds")),
+	)
+	testutils.AssertIntsEqual(
+		t,
+		"plain chain target",
+		int(DefaultConfirmationTarget),
+		int(ConfirmationTarget(newLocalChain(), "DepositSweep")),
+	)
+}
+
+func TestStaticFeeSource_EstimateSatPerVByteFee(t *testing.T) {
+	var tests = map[string]struct {
+		content     string
+		blocks      uint32
+		expectedFee int64
+		expectedErr bool
+	}{
+		"exact target": {
+			content:     `{"1": 25, "6": 10}`,
+			blocks:      6,
+			expectedFee: 10,
+		},
+		"target between entries": {
+			content:     `{"1": 25, "6": 10}`,
+			blocks:      3,
+			expectedFee: 25,
+		},
+		"target below all entries": {
+			content:     `{"2": 25, "6": 10}`,
+			blocks:      1,
+			expectedFee: 25,
+		},
+		"target above all entries": {
+			content:     `{"1": 25, "6": 10}`,
+			blocks:      144,
+			expectedFee: 10,
+		},
+		"empty file": {
+			content:     `{}`,
+			blocks:      1,
+			expectedErr: true,
+		},
+		"invalid target": {
+			content:     `{"fast": 25}`,
+			blocks:      1,
+			expectedErr: true,
+		},
+		"invalid fee": {
+			content:     `{"1": 0}`,
+			blocks:      1,
+			expectedErr: true,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "fees.json")
+			if err := os.WriteFile(path, []byte(test.content), 0600); err != nil {
+				t.Fatal(err)
+			}
+
+			fee, err := NewStaticFeeSource(path).EstimateSatPerVByteFee(
+				test.blocks,
+			)
+			if test.expectedErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			testutils.AssertIntsEqual(t, "fee", int(test.expectedFee), int(fee))
+		})
+	}
+}
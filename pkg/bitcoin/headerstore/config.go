@@ -0,0 +1,30 @@
+package headerstore
+
+import "time"
+
+const (
+	// DefaultFinalityDepth is the default number of blocks that must be
+	// built on top of a block before its header is persisted.
+	DefaultFinalityDepth = 6
+	// DefaultTipRefreshInterval is the default interval after which the
+	// cached height of the latest block is refreshed.
+	DefaultTipRefreshInterval = 1 * time.Minute
+	// DefaultMaxReorgDepth is the default maximum number of stored block
+	// headers examined when looking for the fork point of a reorg.
+	DefaultMaxReorgDepth = 100
+)
+
+// Config holds configurable properties of the block header store.
+type Config struct {
+	// FinalityDepth is the number of blocks that must be built on top of
+	// a block before its header is persisted and served locally. Headers
+	// of more recent blocks are always fetched from the Bitcoin chain as
+	// they can still be reorganized.
+	FinalityDepth uint
+	// TipRefreshInterval is the interval after which the cached height of
+	// the latest block is refreshed.
+	TipRefreshInterval time.Duration
+	// MaxReorgDepth is the maximum number of stored block headers examined
+	// when looking for the fork point of a reorg.
+	MaxReorgDepth uint
+}
@@ -0,0 +1,359 @@
+// Package headerstore provides a bitcoin.Chain decorator keeping a local,
+// verified store of Bitcoin block headers.
+package headerstore
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ipfs/go-log"
+
+	"github.com/keep-network/keep-common/pkg/persistence"
+
+	"github.com/keep-network/keep-core/pkg/bitcoin"
+)
+
+var logger = log.Logger("keep-bitcoin-headerstore")
+
+// Chain is a bitcoin.Chain implementation serving block headers from
+// a local store. Block headers fetched from the underlying chain are
+// verified against the target declared in their bits and the stored
+// neighbouring headers before being used. Difficulty transitions are not
+// validated so the store does not protect against a header chain mined with
+// a lower difficulty than the real one. Headers of blocks that reached the configured finality
+// depth are persisted and served locally afterwards. All other calls are
+// passed to the underlying chain.
+type Chain struct {
+	bitcoin.Chain
+
+	config  Config
+	storage *headerStorage
+
+	headersMutex sync.Mutex
+	headers      map[uint]*bitcoin.BlockHeader
+
+	tipMutex       sync.Mutex
+	tipHeight      uint
+	tipRefreshedAt time.Time
+}
+
+// New creates a new block header store on top of the given Bitcoin chain.
+// Previously persisted headers are loaded from the given persistence handle.
+// The handle can be nil in which case headers are kept only in memory.
+func New(
+	chain bitcoin.Chain,
+	handle persistence.BasicHandle,
+	config Config,
+) (*Chain, error) {
+	if config.FinalityDepth == 0 {
+		config.FinalityDepth = DefaultFinalityDepth
+	}
+	if config.TipRefreshInterval == 0 {
+		config.TipRefreshInterval = DefaultTipRefreshInterval
+	}
+	if config.MaxReorgDepth == 0 {
+		config.MaxReorgDepth = DefaultMaxReorgDepth
+	}
+
+	storage := newHeaderStorage(handle)
+
+	headers, err := storage.readAll()
+	if err != nil {
+		return nil, fmt.Errorf("cannot read persisted headers: [%w]", err)
+	}
+
+	// Epochs with appended or dropped headers are rewritten once so
+	// the number of persisted files stays low.
+	epochsToCompact := storage.appendedEpochs()
+
+	for height, header := range headers {
+		if err := header.VerifyHashMeetsDeclaredTarget(); err != nil {
+			logger.Warnf(
+				"dropping persisted header at height [%d]: [%v]",
+				height,
+				err,
+			)
+			delete(headers, height)
+			epochsToCompact[height/epochLength] = true
+		}
+	}
+
+	logger.Infof("loaded [%d] persisted block headers", len(headers))
+
+	c := &Chain{
+		Chain:   chain,
+		config:  config,
+		storage: storage,
+		headers: headers,
+	}
+
+	for epoch := range epochsToCompact {
+		if err := c.saveEpoch(epoch); err != nil {
+			// Headers are still persisted in their segment files so
+			// the failure to merge them should not fail the store.
+			logger.Errorf("cannot compact epoch [%d] headers: [%v]", epoch, err)
+		}
+	}
+
+	return c, nil
+}
+
+// GetLatestBlockHeight gets the height of the latest block (tip) from the
+// underlying chain and caches it.
+func (c *Chain) GetLatestBlockHeight() (uint, error) {
+	height, err := c.Chain.GetLatestBlockHeight()
+	if err != nil {
+		return 0, err
+	}
+
+	c.tipMutex.Lock()
+	c.tipHeight = height
+	c.tipRefreshedAt = time.Now()
+	c.tipMutex.Unlock()
+
+	return height, nil
+}
+
+// GetBlockHeader gets the block header for the given block height. The
+// header is served from the local store if present. Otherwise, it is
+// fetched from the underlying chain and verified against its declared target
+// and the stored neighbouring headers. An error is returned if the fetched
+// header is invalid.
+func (c *Chain) GetBlockHeader(blockHeight uint) (*bitcoin.BlockHeader, error) {
+	if header, ok := c.storedHeader(blockHeight); ok {
+		return header, nil
+	}
+
+	header, err := c.fetchHeader(blockHeight)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.verifyLinks(blockHeight, header); err != nil {
+		return nil, err
+	}
+
+	tipHeight, err := c.cachedTipHeight()
+	if err != nil {
+		return nil, fmt.Errorf("cannot determine latest block height: [%w]", err)
+	}
+
+	if blockHeight+c.config.FinalityDepth <= tipHeight {
+		if err := c.storeHeader(blockHeight, header); err != nil {
+			// The header is valid so the failure to persist it should not
+			// fail the call.
+			logger.Errorf(
+				"cannot store header at height [%d]: [%v]",
+				blockHeight,
+				err,
+			)
+		}
+	}
+
+	return header, nil
+}
+
+// GetSpendingTxHash gets the hash of the confirmed transaction spending
+// the given transaction outpoint from the underlying chain. An error is
+// returned if the underlying chain does not implement
+// bitcoin.SpendingTxFinder.
+func (c *Chain) GetSpendingTxHash(
+	outpoint *bitcoin.TransactionOutpoint,
+) (bitcoin.Hash, bool, error) {
+	finder, ok := c.Chain.(bitcoin.SpendingTxFinder)
+	if !ok {
+		return bitcoin.Hash{}, false, fmt.Errorf(
+			"underlying chain does not support finding spending transactions",
+		)
+	}
+
+	return finder.GetSpendingTxHash(outpoint)
+}
+
+// storedHeader returns a copy of the stored header at the given height.
+func (c *Chain) storedHeader(blockHeight uint) (*bitcoin.BlockHeader, bool) {
+	c.headersMutex.Lock()
+	defer c.headersMutex.Unlock()
+
+	header, ok := c.headers[blockHeight]
+	if !ok {
+		return nil, false
+	}
+
+	headerCopy := *header
+	return &headerCopy, true
+}
+
+// fetchHeader fetches the header at the given height from the underlying
+// chain and verifies its hash meets the target declared in the header.
+func (c *Chain) fetchHeader(blockHeight uint) (*bitcoin.BlockHeader, error) {
+	header, err := c.Chain.GetBlockHeader(blockHeight)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := header.VerifyHashMeetsDeclaredTarget(); err != nil {
+		return nil, fmt.Errorf(
+			"invalid block header at height [%d]: [%w]",
+			blockHeight,
+			err,
+		)
+	}
+
+	return header, nil
+}
+
+// verifyLinks checks whether the given header links to the stored headers
+// at the previous and next heights. If it does not, the stored header is
+// compared with the one currently returned by the underlying chain. If they
+// differ, the chain was reorganized and stored headers from the fork point
+// are invalidated. Otherwise, the given header is inconsistent with the
+// chain and an error is returned.
+func (c *Chain) verifyLinks(blockHeight uint, header *bitcoin.BlockHeader) error {
+	if blockHeight > 0 {
+		if previous, ok := c.storedHeader(blockHeight - 1); ok &&
+			header.PreviousBlockHeaderHash != previous.Hash() {
+			if err := c.handleConflict(blockHeight - 1); err != nil {
+				return fmt.Errorf(
+					"block header at height [%d] does not point to "+
+						"the stored block header at height [%d]: [%w]",
+					blockHeight,
+					blockHeight-1,
+					err,
+				)
+			}
+		}
+	}
+
+	if next, ok := c.storedHeader(blockHeight + 1); ok &&
+		next.PreviousBlockHeaderHash != header.Hash() {
+		if err := c.handleConflict(blockHeight + 1); err != nil {
+			return fmt.Errorf(
+				"stored block header at height [%d] does not point to "+
+					"the block header at height [%d]: [%w]",
+				blockHeight+1,
+				blockHeight,
+				err,
+			)
+		}
+	}
+
+	return nil
+}
+
+// handleConflict checks whether the stored header at the given height is
+// still part of the chain. If the underlying chain returns the same header,
+// an error is returned. Otherwise, a reorg is assumed and stored headers are
+// invalidated starting from the fork point.
+func (c *Chain) handleConflict(blockHeight uint) error {
+	stored, ok := c.storedHeader(blockHeight)
+	if !ok {
+		return nil
+	}
+
+	current, err := c.fetchHeader(blockHeight)
+	if err != nil {
+		return fmt.Errorf(
+			"cannot fetch block header at height [%d]: [%w]",
+			blockHeight,
+			err,
+		)
+	}
+
+	if current.Hash() == stored.Hash() {
+		return fmt.Errorf("stored block header is still part of the chain")
+	}
+
+	forkHeight := blockHeight
+	for depth := uint(1); forkHeight > 0 && depth < c.config.MaxReorgDepth; depth++ {
+		stored, ok := c.storedHeader(forkHeight - 1)
+		if !ok {
+			break
+		}
+
+		current, err := c.fetchHeader(forkHeight - 1)
+		if err != nil {
+			return fmt.Errorf(
+				"cannot fetch block header at height [%d]: [%w]",
+				forkHeight-1,
+				err,
+			)
+		}
+
+		if current.Hash() == stored.Hash() {
+			break
+		}
+
+		forkHeight--
+	}
+
+	logger.Warnf(
+		"detected reorg of stored block headers; "+
+			"invalidating headers from height [%d]",
+		forkHeight,
+	)
+
+	return c.invalidateFrom(forkHeight)
+}
+
+// storeHeader adds the given header to the store and persists it.
+func (c *Chain) storeHeader(blockHeight uint, header *bitcoin.BlockHeader) error {
+	c.headersMutex.Lock()
+	defer c.headersMutex.Unlock()
+
+	headerCopy := *header
+	c.headers[blockHeight] = &headerCopy
+
+	return c.storage.append(blockHeight, &headerCopy)
+}
+
+// invalidateFrom removes all stored headers at or above the given height.
+func (c *Chain) invalidateFrom(blockHeight uint) error {
+	c.headersMutex.Lock()
+	defer c.headersMutex.Unlock()
+
+	epochs := make(map[uint]bool)
+	for height := range c.headers {
+		if height >= blockHeight {
+			delete(c.headers, height)
+			epochs[height/epochLength] = true
+		}
+	}
+
+	for epoch := range epochs {
+		if err := c.saveEpoch(epoch); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// saveEpoch persists stored headers of the given epoch. Must be called
+// with headersMutex held.
+func (c *Chain) saveEpoch(epoch uint) error {
+	epochHeaders := make(map[uint]*bitcoin.BlockHeader)
+	for height := epoch * epochLength; height < (epoch+1)*epochLength; height++ {
+		if header, ok := c.headers[height]; ok {
+			epochHeaders[height] = header
+		}
+	}
+
+	return c.storage.save(epoch, epochHeaders)
+}
+
+// cachedTipHeight returns the height of the latest block, refreshing it
+// from the underlying chain if the cached value is outdated.
+func (c *Chain) cachedTipHeight() (uint, error) {
+	c.tipMutex.Lock()
+	refreshedAt := c.tipRefreshedAt
+	tipHeight := c.tipHeight
+	c.tipMutex.Unlock()
+
+	if time.Since(refreshedAt) < c.config.TipRefreshInterval {
+		return tipHeight, nil
+	}
+
+	return c.GetLatestBlockHeight()
+}
@@ -0,0 +1,449 @@
+package headerstore
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/keep-network/keep-common/pkg/persistence"
+
+	"github.com/keep-network/keep-core/internal/testutils"
+	"github.com/keep-network/keep-core/pkg/bitcoin"
+)
+
+// regtestBits are the difficulty bits of the regtest network. About every
+// second hash is below the corresponding target so headers can be mined
+// instantly.
+const regtestBits = 0x207fffff
+
+func TestChain_GetBlockHeader_ServesFinalHeadersLocally(t *testing.T) {
+	upstream := newUpstreamChain(mineHeaders(bitcoin.Hash{}, 0, 10, 0), 9)
+
+	chain, err := New(upstream, newPersistenceHandle(), Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 3; i++ {
+		header, err := chain.GetBlockHeader(2)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assertHeadersEqual(t, upstream.header(2), header)
+	}
+
+	testutils.AssertIntsEqual(t, "upstream calls", 1, upstream.headerCalls(2))
+}
+
+func TestChain_GetBlockHeader_FetchesRecentHeaders(t *testing.T) {
+	upstream := newUpstreamChain(mineHeaders(bitcoin.Hash{}, 0, 10, 0), 9)
+
+	chain, err := New(upstream, newPersistenceHandle(), Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 3; i++ {
+		if _, err := chain.GetBlockHeader(5); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	testutils.AssertIntsEqual(t, "upstream calls", 3, upstream.headerCalls(5))
+}
+
+func TestChain_GetBlockHeader_HashAboveTarget(t *testing.T) {
+	headers := mineHeaders(bitcoin.Hash{}, 0, 10, 0)
+	// The mainnet minimum difficulty target is not met by headers mined
+	// against the regtest target.
+	headers[3].Bits = 0x1d00ffff
+
+	upstream := newUpstreamChain(headers, 9)
+
+	chain, err := New(upstream, newPersistenceHandle(), Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = chain.GetBlockHeader(3)
+	if err == nil || !strings.HasPrefix(
+		err.Error(),
+		"invalid block header at height [3]",
+	) {
+		t.Fatalf("unexpected error: [%v]", err)
+	}
+}
+
+func TestChain_GetBlockHeader_LoadsPersistedHeaders(t *testing.T) {
+	handle := newPersistenceHandle()
+	upstream := newUpstreamChain(mineHeaders(bitcoin.Hash{}, 0, 10, 0), 9)
+
+	chain, err := New(upstream, handle, Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for height := uint(0); height <= 3; height++ {
+		if _, err := chain.GetBlockHeader(height); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	restartedUpstream := newUpstreamChain(upstream.headers, 9)
+
+	restartedChain, err := New(restartedUpstream, handle, Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for height := uint(0); height <= 3; height++ {
+		header, err := restartedChain.GetBlockHeader(height)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assertHeadersEqual(t, upstream.header(height), header)
+		testutils.AssertIntsEqual(
+			t,
+			fmt.Sprintf("upstream calls for height %d", height),
+			0,
+			restartedUpstream.headerCalls(height),
+		)
+	}
+}
+
+func TestChain_GetBlockHeader_AppendsPersistedHeaders(t *testing.T) {
+	handle := newPersistenceHandle()
+	upstream := newUpstreamChain(mineHeaders(bitcoin.Hash{}, 0, 10, 0), 9)
+
+	chain, err := New(upstream, handle, Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for height := uint(0); height <= 3; height++ {
+		if _, err := chain.GetBlockHeader(height); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Each header is written once to its own segment file and no file
+	// is rewritten.
+	testutils.AssertIntsEqual(t, "files count", 4, len(handle.fileNames()))
+	for _, name := range handle.fileNames() {
+		testutils.AssertIntsEqual(
+			t,
+			fmt.Sprintf("saves of file %s", name),
+			1,
+			handle.saveCalls(name),
+		)
+	}
+
+	// Segment files are merged into the epoch file on the next load.
+	if _, err := New(newUpstreamChain(upstream.headers, 9), handle, Config{}); err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertStringsEqual(
+		t,
+		"files",
+		fmt.Sprintf("%s/%s0", dirName, epochFilePrefix),
+		strings.Join(handle.fileNames(), ","),
+	)
+
+	restartedUpstream := newUpstreamChain(upstream.headers, 9)
+
+	restartedChain, err := New(restartedUpstream, handle, Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for height := uint(0); height <= 3; height++ {
+		header, err := restartedChain.GetBlockHeader(height)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assertHeadersEqual(t, upstream.header(height), header)
+		testutils.AssertIntsEqual(
+			t,
+			fmt.Sprintf("upstream calls for height %d", height),
+			0,
+			restartedUpstream.headerCalls(height),
+		)
+	}
+}
+
+func TestChain_GetBlockHeader_Reorg(t *testing.T) {
+	headers := mineHeaders(bitcoin.Hash{}, 0, 20, 0)
+	upstream := newUpstreamChain(headers, 19)
+
+	chain, err := New(upstream, newPersistenceHandle(), Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for height := uint(0); height <= 10; height++ {
+		if _, err := chain.GetBlockHeader(height); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Build a competing branch forking off after block 7. A different
+	// timestamp makes the headers differ from the original ones.
+	fork := mineHeaders(headers[7].Hash(), 8, 12, 1)
+	for height, header := range fork {
+		upstream.setHeader(height, header)
+	}
+
+	// The header at height 11 does not point to the stored header at
+	// height 10. This is detected as a reorg and headers from height 8
+	// are invalidated.
+	header, err := chain.GetBlockHeader(11)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertHeadersEqual(t, fork[11], header)
+
+	for height := uint(8); height <= 10; height++ {
+		header, err := chain.GetBlockHeader(height)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertHeadersEqual(t, fork[height], header)
+	}
+
+	header, err = chain.GetBlockHeader(7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertHeadersEqual(t, headers[7], header)
+}
+
+func TestChain_GetBlockHeader_InconsistentHeader(t *testing.T) {
+	headers := mineHeaders(bitcoin.Hash{}, 0, 20, 0)
+	upstream := newUpstreamChain(headers, 19)
+
+	chain, err := New(upstream, newPersistenceHandle(), Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := chain.GetBlockHeader(10); err != nil {
+		t.Fatal(err)
+	}
+
+	// The header at height 11 does not point to the header at height 10
+	// but the header at height 10 is still returned by the upstream chain.
+	upstream.setHeader(11, mineHeaders(bitcoin.Hash{0xff}, 11, 11, 0)[11])
+
+	_, err = chain.GetBlockHeader(11)
+	if err == nil || !strings.HasPrefix(
+		err.Error(),
+		"block header at height [11] does not point to the stored block "+
+			"header at height [10]",
+	) {
+		t.Fatalf("unexpected error: [%v]", err)
+	}
+}
+
+func assertHeadersEqual(t *testing.T, expected, actual *bitcoin.BlockHeader) {
+	expectedSerialized := expected.Serialize()
+	actualSerialized := actual.Serialize()
+
+	testutils.AssertBytesEqual(t, expectedSerialized[:], actualSerialized[:])
+}
+
+// mineHeaders mines a chain of block headers at heights from the given
+// range, starting from the given previous block hash. The seed is used to
+// produce distinct chains with the same previous block hash.
+func mineHeaders(
+	previousHash bitcoin.Hash,
+	firstHeight uint,
+	lastHeight uint,
+	seed uint32,
+) map[uint]*bitcoin.BlockHeader {
+	headers := make(map[uint]*bitcoin.BlockHeader)
+
+	for height := firstHeight; height <= lastHeight; height++ {
+		header := &bitcoin.BlockHeader{
+			Version:                 4,
+			PreviousBlockHeaderHash: previousHash,
+			MerkleRootHash:          bitcoin.Hash{byte(height)},
+			Time:                    1700000000 + uint32(height)*600 + seed,
+			Bits:                    regtestBits,
+		}
+
+		for header.VerifyHashMeetsDeclaredTarget() != nil {
+			header.Nonce++
+		}
+
+		headers[height] = header
+		previousHash = header.Hash()
+	}
+
+	return headers
+}
+
+type upstreamChain struct {
+	bitcoin.Chain
+
+	mutex     sync.Mutex
+	headers   map[uint]*bitcoin.BlockHeader
+	tipHeight uint
+	calls     map[uint]int
+}
+
+func newUpstreamChain(
+	headers map[uint]*bitcoin.BlockHeader,
+	tipHeight uint,
+) *upstreamChain {
+	headersCopy := make(map[uint]*bitcoin.BlockHeader)
+	for height, header := range headers {
+		headerCopy := *header
+		headersCopy[height] = &headerCopy
+	}
+
+	return &upstreamChain{
+		headers:   headersCopy,
+		tipHeight: tipHeight,
+		calls:     make(map[uint]int),
+	}
+}
+
+func (uc *upstreamChain) GetBlockHeader(
+	blockHeight uint,
+) (*bitcoin.BlockHeader, error) {
+	uc.mutex.Lock()
+	defer uc.mutex.Unlock()
+
+	uc.calls[blockHeight]++
+
+	header, ok := uc.headers[blockHeight]
+	if !ok {
+		return nil, fmt.Errorf("no header at height [%d]", blockHeight)
+	}
+
+	headerCopy := *header
+	return &headerCopy, nil
+}
+
+func (uc *upstreamChain) GetLatestBlockHeight() (uint, error) {
+	return uc.tipHeight, nil
+}
+
+func (uc *upstreamChain) header(blockHeight uint) *bitcoin.BlockHeader {
+	uc.mutex.Lock()
+	defer uc.mutex.Unlock()
+
+	return uc.headers[blockHeight]
+}
+
+func (uc *upstreamChain) setHeader(
+	blockHeight uint,
+	header *bitcoin.BlockHeader,
+) {
+	uc.mutex.Lock()
+	defer uc.mutex.Unlock()
+
+	uc.headers[blockHeight] = header
+}
+
+func (uc *upstreamChain) headerCalls(blockHeight uint) int {
+	uc.mutex.Lock()
+	defer uc.mutex.Unlock()
+
+	return uc.calls[blockHeight]
+}
+
+type persistenceHandle struct {
+	mutex sync.Mutex
+	files map[string][]byte
+	saves map[string]int
+}
+
+func newPersistenceHandle() *persistenceHandle {
+	return &persistenceHandle{
+		files: make(map[string][]byte),
+		saves: make(map[string]int),
+	}
+}
+
+func (ph *persistenceHandle) Save(data []byte, directory, name string) error {
+	ph.mutex.Lock()
+	defer ph.mutex.Unlock()
+
+	ph.files[directory+"/"+name] = append([]byte{}, data...)
+	ph.saves[directory+"/"+name]++
+	return nil
+}
+
+func (ph *persistenceHandle) fileNames() []string {
+	ph.mutex.Lock()
+	defer ph.mutex.Unlock()
+
+	names := make([]string, 0, len(ph.files))
+	for path := range ph.files {
+		names = append(names, path)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+func (ph *persistenceHandle) saveCalls(path string) int {
+	ph.mutex.Lock()
+	defer ph.mutex.Unlock()
+
+	return ph.saves[path]
+}
+
+func (ph *persistenceHandle) Delete(directory, name string) error {
+	ph.mutex.Lock()
+	defer ph.mutex.Unlock()
+
+	delete(ph.files, directory+"/"+name)
+	return nil
+}
+
+func (ph *persistenceHandle) ReadAll() (
+	<-chan persistence.DataDescriptor,
+	<-chan error,
+) {
+	ph.mutex.Lock()
+	defer ph.mutex.Unlock()
+
+	dataChan := make(chan persistence.DataDescriptor, len(ph.files))
+	errorChan := make(chan error)
+
+	for path, content := range ph.files {
+		directory, name, _ := strings.Cut(path, "/")
+		dataChan <- &dataDescriptor{directory, name, content}
+	}
+
+	close(dataChan)
+	close(errorChan)
+
+	return dataChan, errorChan
+}
+
+type dataDescriptor struct {
+	directory string
+	name      string
+	content   []byte
+}
+
+func (dd *dataDescriptor) Name() string {
+	return dd.name
+}
+
+func (dd *dataDescriptor) Directory() string {
+	return dd.directory
+}
+
+func (dd *dataDescriptor) Content() ([]byte, error) {
+	return dd.content, nil
+}
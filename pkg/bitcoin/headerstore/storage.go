@@ -0,0 +1,283 @@
+package headerstore
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/keep-network/keep-common/pkg/persistence"
+
+	"github.com/keep-network/keep-core/pkg/bitcoin"
+)
+
+const (
+	// dirName is the name of the persistence directory holding block
+	// headers.
+	dirName = "headers"
+	// epochFilePrefix is the prefix of files holding block headers of a
+	// single difficulty epoch.
+	epochFilePrefix = "epoch_"
+	// epochLength is the number of blocks in a difficulty epoch. Headers are
+	// persisted in files grouping headers of a single epoch to keep the
+	// number of files low.
+	epochLength = 2016
+	// recordLength is the byte length of a single persisted header record
+	// in the [height][header] format.
+	recordLength = 4 + bitcoin.BlockHeaderByteLength
+)
+
+// headerStorage persists block headers using the given persistence handle.
+// The handle can be nil in which case headers are not persisted.
+//
+// Headers of an epoch are kept in a single epoch file. Headers stored while
+// the client is running are appended to their epoch by writing them to
+// separate segment files so existing files are never rewritten on the hot
+// path. Segment files are merged into their epoch files on the next load.
+type headerStorage struct {
+	handle persistence.BasicHandle
+
+	// appended holds heights of headers persisted in segment files that
+	// were not merged into their epoch files yet.
+	appended map[uint]bool
+}
+
+func newHeaderStorage(handle persistence.BasicHandle) *headerStorage {
+	return &headerStorage{
+		handle:   handle,
+		appended: make(map[uint]bool),
+	}
+}
+
+// append persists the given block header at the given height in a new
+// segment file of the header's epoch.
+func (hs *headerStorage) append(
+	height uint,
+	header *bitcoin.BlockHeader,
+) error {
+	if hs.handle == nil {
+		return nil
+	}
+
+	data := encodeRecords(map[uint]*bitcoin.BlockHeader{height: header})
+
+	if err := hs.handle.Save(data, dirName, segmentFileName(height)); err != nil {
+		return fmt.Errorf("cannot append header at height [%d]: [%w]", height, err)
+	}
+
+	hs.appended[height] = true
+
+	return nil
+}
+
+// save persists the given block headers of the given epoch in the epoch
+// file, replacing previously persisted headers of that epoch. Segment files
+// of the epoch are removed as their headers are expected to be part of the
+// given headers.
+func (hs *headerStorage) save(
+	epoch uint,
+	headers map[uint]*bitcoin.BlockHeader,
+) error {
+	if hs.handle == nil {
+		return nil
+	}
+
+	fileName := fmt.Sprintf("%s%d", epochFilePrefix, epoch)
+
+	if len(headers) == 0 {
+		if err := hs.handle.Delete(dirName, fileName); err != nil &&
+			!errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("cannot delete epoch [%d] headers: [%w]", epoch, err)
+		}
+	} else if err := hs.handle.Save(
+		encodeRecords(headers),
+		dirName,
+		fileName,
+	); err != nil {
+		return fmt.Errorf("cannot save epoch [%d] headers: [%w]", epoch, err)
+	}
+
+	for height := range hs.appended {
+		if height/epochLength != epoch {
+			continue
+		}
+
+		if err := hs.handle.Delete(dirName, segmentFileName(height)); err != nil {
+			return fmt.Errorf(
+				"cannot delete appended header at height [%d]: [%w]",
+				height,
+				err,
+			)
+		}
+
+		delete(hs.appended, height)
+	}
+
+	return nil
+}
+
+// appendedEpochs returns epochs having headers persisted in segment files.
+func (hs *headerStorage) appendedEpochs() map[uint]bool {
+	epochs := make(map[uint]bool)
+	for height := range hs.appended {
+		epochs[height/epochLength] = true
+	}
+
+	return epochs
+}
+
+// segmentFileName returns the name of the segment file holding the appended
+// header at the given height.
+func segmentFileName(height uint) string {
+	return fmt.Sprintf("%s%d_%d", epochFilePrefix, height/epochLength, height)
+}
+
+// encodeRecords encodes the given headers as [height][header] records
+// sorted by height.
+func encodeRecords(headers map[uint]*bitcoin.BlockHeader) []byte {
+	heights := make([]uint, 0, len(headers))
+	for height := range headers {
+		heights = append(heights, height)
+	}
+	sort.Slice(heights, func(i, j int) bool { return heights[i] < heights[j] })
+
+	data := make([]byte, 0, len(heights)*recordLength)
+	for _, height := range heights {
+		serializedHeader := headers[height].Serialize()
+
+		data = binary.LittleEndian.AppendUint32(data, uint32(height))
+		data = append(data, serializedHeader[:]...)
+	}
+
+	return data
+}
+
+// readAll reads all persisted block headers.
+func (hs *headerStorage) readAll() (map[uint]*bitcoin.BlockHeader, error) {
+	headers := make(map[uint]*bitcoin.BlockHeader)
+
+	if hs.handle == nil {
+		return headers, nil
+	}
+
+	descriptorsChan, errorsChan := hs.handle.ReadAll()
+
+	var errs []error
+
+	// Channels are not buffered so descriptors and errors must be read
+	// concurrently.
+	var wg sync.WaitGroup
+	wg.Add(2)
+
+	go func() {
+		defer wg.Done()
+
+		for descriptor := range descriptorsChan {
+			if descriptor.Directory() != dirName ||
+				!strings.HasPrefix(descriptor.Name(), epochFilePrefix) {
+				continue
+			}
+
+			content, err := descriptor.Content()
+			if err != nil {
+				errs = append(errs, fmt.Errorf(
+					"cannot read file [%s]: [%w]",
+					descriptor.Name(),
+					err,
+				))
+				continue
+			}
+
+			epochHeaders, segment, err := decodeEpochFile(
+				descriptor.Name(),
+				content,
+			)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+
+			for height, header := range epochHeaders {
+				headers[height] = header
+
+				if segment {
+					hs.appended[height] = true
+				}
+			}
+		}
+	}()
+
+	go func() {
+		defer wg.Done()
+
+		for err := range errorsChan {
+			logger.Errorf("cannot read persisted block headers: [%v]", err)
+		}
+	}()
+
+	wg.Wait()
+
+	if len(errs) > 0 {
+		return nil, errs[0]
+	}
+
+	return headers, nil
+}
+
+// decodeEpochFile decodes block headers persisted in the epoch or segment
+// file with the given name. The returned flag tells whether the file is
+// a segment file.
+func decodeEpochFile(
+	name string,
+	content []byte,
+) (map[uint]*bitcoin.BlockHeader, bool, error) {
+	epochPart, _, segment := strings.Cut(
+		strings.TrimPrefix(name, epochFilePrefix),
+		"_",
+	)
+
+	epoch, err := strconv.ParseUint(epochPart, 10, 32)
+	if err != nil {
+		return nil, false, fmt.Errorf(
+			"invalid epoch file name [%s]: [%w]",
+			name,
+			err,
+		)
+	}
+
+	if len(content)%recordLength != 0 {
+		return nil, false, fmt.Errorf(
+			"invalid length [%d] of epoch file [%s]",
+			len(content),
+			name,
+		)
+	}
+
+	headers := make(map[uint]*bitcoin.BlockHeader)
+
+	for offset := 0; offset < len(content); offset += recordLength {
+		height := uint(binary.LittleEndian.Uint32(content[offset:]))
+
+		if height/epochLength != uint(epoch) {
+			return nil, false, fmt.Errorf(
+				"header at height [%d] does not belong to epoch [%d]",
+				height,
+				epoch,
+			)
+		}
+
+		var serializedHeader [bitcoin.BlockHeaderByteLength]byte
+		copy(serializedHeader[:], content[offset+4:offset+recordLength])
+
+		header := &bitcoin.BlockHeader{}
+		header.Deserialize(serializedHeader)
+
+		headers[height] = header
+	}
+
+	return headers, segment, nil
+}
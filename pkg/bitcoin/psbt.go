@@ -0,0 +1,414 @@
+package bitcoin
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// psbtMagic is the magic prefix of each BIP-174 partially signed Bitcoin
+// transaction. It is the `psbt` ASCII string followed by the 0xff separator.
+var psbtMagic = []byte{0x70, 0x73, 0x62, 0x74, 0xff}
+
+// psbtMaxFieldLength is the maximum length of a PSBT key or value accepted
+// while parsing. It protects against allocating huge buffers for malformed
+// inputs.
+const psbtMaxFieldLength = 4000000
+
+// BIP-174 key types used by the TransactionBuilder. Worth noting that the
+// same numeric value can denote different key types depending on the map
+// the key belongs to.
+const (
+	psbtGlobalUnsignedTx = 0x00
+
+	psbtInputNonWitnessUtxo = 0x00
+	psbtInputWitnessUtxo    = 0x01
+	psbtInputPartialSig     = 0x02
+	psbtInputSighashType    = 0x03
+	psbtInputRedeemScript   = 0x04
+	psbtInputWitnessScript  = 0x05
+)
+
+// psbtField is a single key-value pair of a PSBT map. The key is composed of
+// the key type byte followed by optional key data.
+type psbtField struct {
+	key   []byte
+	value []byte
+}
+
+// keyType returns the key type of the field.
+func (pf *psbtField) keyType() byte {
+	return pf.key[0]
+}
+
+// keyData returns the key data of the field, i.e. the key without the
+// key type byte.
+func (pf *psbtField) keyData() []byte {
+	return pf.key[1:]
+}
+
+// psbtMap is a single PSBT map, i.e. the global map, an input map or an
+// output map. Fields are kept in the order they were added.
+type psbtMap []*psbtField
+
+// add appends a new field to the map.
+func (pm *psbtMap) add(keyType byte, keyData []byte, value []byte) {
+	*pm = append(*pm, &psbtField{
+		key:   append([]byte{keyType}, keyData...),
+		value: value,
+	})
+}
+
+// fieldsOfType returns all fields of the given key type.
+func (pm psbtMap) fieldsOfType(keyType byte) []*psbtField {
+	fields := make([]*psbtField, 0)
+	for _, field := range pm {
+		if field.keyType() == keyType {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// psbtPacket is an internal representation of a BIP-174 partially signed
+// Bitcoin transaction.
+type psbtPacket struct {
+	global  psbtMap
+	inputs  []psbtMap
+	outputs []psbtMap
+}
+
+// serialize serializes the packet according to BIP-174.
+func (pp *psbtPacket) serialize() ([]byte, error) {
+	var buffer bytes.Buffer
+
+	buffer.Write(psbtMagic)
+
+	maps := append([]psbtMap{pp.global}, pp.inputs...)
+	maps = append(maps, pp.outputs...)
+
+	for _, fields := range maps {
+		for _, field := range fields {
+			if err := wire.WriteVarBytes(&buffer, 0, field.key); err != nil {
+				return nil, err
+			}
+			if err := wire.WriteVarBytes(&buffer, 0, field.value); err != nil {
+				return nil, err
+			}
+		}
+
+		// Each map is terminated by a 0x00 separator.
+		buffer.WriteByte(0x00)
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// parsePSBT parses a BIP-174 partially signed Bitcoin transaction. Only the
+// structure of the packet is validated. The returned unsigned transaction
+// is the one held by the PSBT_GLOBAL_UNSIGNED_TX field of the global map.
+func parsePSBT(data []byte) (*psbtPacket, *wire.MsgTx, error) {
+	reader := bytes.NewReader(data)
+
+	magic := make([]byte, len(psbtMagic))
+	if _, err := io.ReadFull(reader, magic); err != nil {
+		return nil, nil, fmt.Errorf("cannot read magic bytes: [%v]", err)
+	}
+	if !bytes.Equal(magic, psbtMagic) {
+		return nil, nil, fmt.Errorf("wrong magic bytes")
+	}
+
+	global, err := readPSBTMap(reader)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot read global map: [%v]", err)
+	}
+
+	unsignedTxFields := global.fieldsOfType(psbtGlobalUnsignedTx)
+	if len(unsignedTxFields) != 1 ||
+		len(unsignedTxFields[0].keyData()) != 0 {
+		return nil, nil, fmt.Errorf(
+			"global map must contain exactly one unsigned transaction",
+		)
+	}
+
+	unsignedTx := wire.NewMsgTx(wire.TxVersion)
+	// The unsigned transaction must be serialized in the non-witness format.
+	err = unsignedTx.DeserializeNoWitness(
+		bytes.NewReader(unsignedTxFields[0].value),
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf(
+			"cannot deserialize unsigned transaction: [%v]",
+			err,
+		)
+	}
+
+	for i, input := range unsignedTx.TxIn {
+		if len(input.SignatureScript) > 0 || len(input.Witness) > 0 {
+			return nil, nil, fmt.Errorf(
+				"input [%v] of the unsigned transaction is not empty",
+				i,
+			)
+		}
+	}
+
+	packet := &psbtPacket{
+		global:  global,
+		inputs:  make([]psbtMap, len(unsignedTx.TxIn)),
+		outputs: make([]psbtMap, len(unsignedTx.TxOut)),
+	}
+
+	for i := range packet.inputs {
+		packet.inputs[i], err = readPSBTMap(reader)
+		if err != nil {
+			return nil, nil, fmt.Errorf(
+				"cannot read map of input [%v]: [%v]",
+				i,
+				err,
+			)
+		}
+	}
+
+	for i := range packet.outputs {
+		packet.outputs[i], err = readPSBTMap(reader)
+		if err != nil {
+			return nil, nil, fmt.Errorf(
+				"cannot read map of output [%v]: [%v]",
+				i,
+				err,
+			)
+		}
+	}
+
+	if reader.Len() > 0 {
+		return nil, nil, fmt.Errorf("unexpected trailing data")
+	}
+
+	return packet, unsignedTx, nil
+}
+
+// readPSBTMap reads a single PSBT map terminated by the 0x00 separator.
+// Duplicated keys are rejected as required by BIP-174.
+func readPSBTMap(reader *bytes.Reader) (psbtMap, error) {
+	result := make(psbtMap, 0)
+	keys := make(map[string]bool)
+
+	for {
+		key, err := wire.ReadVarBytes(reader, 0, psbtMaxFieldLength, "key")
+		if err != nil {
+			return nil, fmt.Errorf("cannot read key: [%v]", err)
+		}
+
+		// An empty key is actually the 0x00 separator.
+		if len(key) == 0 {
+			return result, nil
+		}
+
+		if keys[string(key)] {
+			return nil, fmt.Errorf("duplicated key [0x%x]", key)
+		}
+		keys[string(key)] = true
+
+		value, err := wire.ReadVarBytes(reader, 0, psbtMaxFieldLength, "value")
+		if err != nil {
+			return nil, fmt.Errorf("cannot read value: [%v]", err)
+		}
+
+		result = append(result, &psbtField{key: key, value: value})
+	}
+}
+
+// ExportPSBT exports the transaction being built as a BIP-174 partially
+// signed Bitcoin transaction. The resulting PSBT contains the unsigned
+// transaction and, for each input, the UTXO it spends, the redeem or
+// witness script for script hash inputs, and the sighash type that is
+// used to compute its signature hash. This allows inspecting and verifying
+// what is about to be signed using standard Bitcoin tooling. The exported
+// PSBT always describes the unsigned transaction, even if signatures were
+// already applied using AddSignatures.
+func (tb *TransactionBuilder) ExportPSBT() ([]byte, error) {
+	unsignedTx := tb.unsignedTransaction()
+
+	var unsignedTxBuffer bytes.Buffer
+	if err := unsignedTx.SerializeNoWitness(&unsignedTxBuffer); err != nil {
+		return nil, fmt.Errorf(
+			"cannot serialize unsigned transaction: [%v]",
+			err,
+		)
+	}
+
+	packet := &psbtPacket{
+		global:  make(psbtMap, 0),
+		inputs:  make([]psbtMap, len(unsignedTx.TxIn)),
+		outputs: make([]psbtMap, len(unsignedTx.TxOut)),
+	}
+
+	packet.global.add(psbtGlobalUnsignedTx, nil, unsignedTxBuffer.Bytes())
+
+	for i := range unsignedTx.TxIn {
+		sigHashArgs := tb.sigHashArgs[i]
+		inputMap := make(psbtMap, 0)
+
+		if sigHashArgs.witness {
+			var witnessUtxoBuffer bytes.Buffer
+			err := wire.WriteTxOut(
+				&witnessUtxoBuffer,
+				0,
+				0,
+				wire.NewTxOut(sigHashArgs.value, sigHashArgs.publicKeyScript),
+			)
+			if err != nil {
+				return nil, fmt.Errorf(
+					"cannot serialize UTXO of input [%v]: [%v]",
+					i,
+					err,
+				)
+			}
+
+			inputMap.add(psbtInputWitnessUtxo, nil, witnessUtxoBuffer.Bytes())
+		} else {
+			if sigHashArgs.previousTransaction == nil {
+				return nil, fmt.Errorf(
+					"missing transaction holding UTXO of input [%v]",
+					i,
+				)
+			}
+
+			inputMap.add(
+				psbtInputNonWitnessUtxo,
+				nil,
+				sigHashArgs.previousTransaction.Serialize(),
+			)
+		}
+
+		// The scriptCode of script hash inputs is the plain-text redeem
+		// script. For P2WSH, BIP-174 names it the witness script.
+		switch GetScriptType(sigHashArgs.publicKeyScript) {
+		case P2SHScript:
+			inputMap.add(psbtInputRedeemScript, nil, sigHashArgs.scriptCode)
+		case P2WSHScript:
+			inputMap.add(psbtInputWitnessScript, nil, sigHashArgs.scriptCode)
+		}
+
+		sigHashType := uint32(txscript.SigHashAll)
+		if sigHashArgs.taproot {
+			sigHashType = taprootSigHashDefault
+		}
+
+		sigHashTypeBytes := make([]byte, 4)
+		binary.LittleEndian.PutUint32(sigHashTypeBytes, sigHashType)
+
+		inputMap.add(psbtInputSighashType, nil, sigHashTypeBytes)
+
+		packet.inputs[i] = inputMap
+	}
+
+	for i := range packet.outputs {
+		packet.outputs[i] = make(psbtMap, 0)
+	}
+
+	return packet.serialize()
+}
+
+// ImportPSBTSignatures extracts signatures from the given BIP-174 partially
+// signed Bitcoin transaction. The PSBT must describe the very same unsigned
+// transaction as the one being built and must contain exactly one
+// SIGHASH_ALL partial signature for each input. The returned signatures are
+// ordered in the same way as transaction inputs and are supposed to be
+// applied using AddSignatures which verifies them against the computed
+// signature hashes.
+func (tb *TransactionBuilder) ImportPSBTSignatures(
+	psbt []byte,
+) ([]*SignatureContainer, error) {
+	packet, psbtUnsignedTx, err := parsePSBT(psbt)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse PSBT: [%v]", err)
+	}
+
+	if psbtUnsignedTx.TxHash() != tb.unsignedTransaction().TxHash() {
+		return nil, fmt.Errorf(
+			"PSBT does not describe the transaction being built",
+		)
+	}
+
+	signatures := make([]*SignatureContainer, len(packet.inputs))
+
+	for i, inputMap := range packet.inputs {
+		partialSigs := inputMap.fieldsOfType(psbtInputPartialSig)
+		if len(partialSigs) != 1 {
+			return nil, fmt.Errorf(
+				"input [%v] must have exactly one partial signature; "+
+					"got [%v]",
+				i,
+				len(partialSigs),
+			)
+		}
+
+		partialSig := partialSigs[0]
+
+		publicKey, err := btcec.ParsePubKey(
+			partialSig.keyData(),
+			btcec.S256(),
+		)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"cannot parse public key of input [%v]: [%v]",
+				i,
+				err,
+			)
+		}
+
+		// The partial signature is a DER-encoded signature followed by
+		// the sighash type byte.
+		if len(partialSig.value) == 0 {
+			return nil, fmt.Errorf("empty partial signature of input [%v]", i)
+		}
+
+		signatureBytes := partialSig.value[:len(partialSig.value)-1]
+		sigHashType := partialSig.value[len(partialSig.value)-1]
+
+		if txscript.SigHashType(sigHashType) != txscript.SigHashAll {
+			return nil, fmt.Errorf(
+				"partial signature of input [%v] has unsupported "+
+					"sighash type [0x%x]",
+				i,
+				sigHashType,
+			)
+		}
+
+		signature, err := btcec.ParseDERSignature(signatureBytes, btcec.S256())
+		if err != nil {
+			return nil, fmt.Errorf(
+				"cannot parse partial signature of input [%v]: [%v]",
+				i,
+				err,
+			)
+		}
+
+		signatures[i] = &SignatureContainer{
+			R:         signature.R,
+			S:         signature.S,
+			PublicKey: publicKey.ToECDSA(),
+		}
+	}
+
+	return signatures, nil
+}
+
+// unsignedTransaction returns a copy of the transaction being built with
+// signature scripts and witnesses of all inputs removed.
+func (tb *TransactionBuilder) unsignedTransaction() *wire.MsgTx {
+	unsignedTx := tb.internal.Copy()
+
+	for _, input := range unsignedTx.TxIn {
+		input.SignatureScript = nil
+		input.Witness = nil
+	}
+
+	return unsignedTx
+}
@@ -0,0 +1,336 @@
+package bitcoin
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/txscript"
+
+	"github.com/keep-network/keep-core/internal/testutils"
+)
+
+func TestTransactionBuilder_ExportPSBT(t *testing.T) {
+	builder, previousTransaction, redeemScript, _ := newPSBTTestBuilder(t)
+
+	psbt, err := builder.ExportPSBT()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertBytesEqual(t, psbtMagic, psbt[:len(psbtMagic)])
+
+	packet, unsignedTx, err := parsePSBT(psbt)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if unsignedTx.TxHash() != builder.unsignedTransaction().TxHash() {
+		t.Errorf("unexpected unsigned transaction")
+	}
+
+	for i, input := range unsignedTx.TxIn {
+		if len(input.SignatureScript) > 0 || len(input.Witness) > 0 {
+			t.Errorf("input [%v] of the unsigned transaction is not empty", i)
+		}
+	}
+
+	testutils.AssertIntsEqual(t, "inputs count", 3, len(packet.inputs))
+	testutils.AssertIntsEqual(t, "outputs count", 1, len(packet.outputs))
+
+	expectedWitnessUtxo := append(
+		[]byte{0x10, 0x27, 0, 0, 0, 0, 0, 0, 0x16},
+		previousTransaction.Outputs[0].PublicKeyScript...,
+	)
+
+	var tests = map[string]struct {
+		inputIndex     int
+		expectedFields map[byte][]byte
+	}{
+		"P2WPKH input": {
+			inputIndex: 0,
+			expectedFields: map[byte][]byte{
+				psbtInputWitnessUtxo: expectedWitnessUtxo,
+				psbtInputSighashType: {0x01, 0, 0, 0},
+			},
+		},
+		"P2SH input": {
+			inputIndex: 1,
+			expectedFields: map[byte][]byte{
+				psbtInputNonWitnessUtxo: previousTransaction.Serialize(),
+				psbtInputRedeemScript:   redeemScript,
+				psbtInputSighashType:    {0x01, 0, 0, 0},
+			},
+		},
+		"P2PKH input": {
+			inputIndex: 2,
+			expectedFields: map[byte][]byte{
+				psbtInputNonWitnessUtxo: previousTransaction.Serialize(),
+				psbtInputSighashType:    {0x01, 0, 0, 0},
+			},
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			inputMap := packet.inputs[test.inputIndex]
+
+			testutils.AssertIntsEqual(
+				t,
+				"fields count",
+				len(test.expectedFields),
+				len(inputMap),
+			)
+
+			for keyType, expectedValue := range test.expectedFields {
+				fields := inputMap.fieldsOfType(keyType)
+				if len(fields) != 1 {
+					t.Fatalf("missing field of type [0x%x]", keyType)
+				}
+
+				testutils.AssertBytesEqual(t, expectedValue, fields[0].value)
+			}
+		})
+	}
+}
+
+func TestTransactionBuilder_ImportPSBTSignatures(t *testing.T) {
+	var tests = map[string]struct {
+		modifyPacket  func(packet *psbtPacket)
+		expectedError bool
+	}{
+		"valid partial signatures": {
+			modifyPacket:  func(packet *psbtPacket) {},
+			expectedError: false,
+		},
+		"missing partial signature": {
+			modifyPacket: func(packet *psbtPacket) {
+				packet.inputs[1] = packet.inputs[1][:len(packet.inputs[1])-1]
+			},
+			expectedError: true,
+		},
+		"unsupported sighash type": {
+			modifyPacket: func(packet *psbtPacket) {
+				partialSig := packet.inputs[0][len(packet.inputs[0])-1]
+				partialSig.value[len(partialSig.value)-1] =
+					byte(txscript.SigHashSingle)
+			},
+			expectedError: true,
+		},
+		"different unsigned transaction": {
+			modifyPacket: func(packet *psbtPacket) {
+				unsignedTx := packet.global[0].value
+				// Change the locktime of the unsigned transaction.
+				unsignedTx[len(unsignedTx)-1] = 0x01
+			},
+			expectedError: true,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			builder, _, _, privateKey := newPSBTTestBuilder(t)
+
+			sigHashes, err := builder.ComputeSignatureHashes()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			psbt, err := builder.ExportPSBT()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			packet, _, err := parsePSBT(psbt)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			publicKeyBytes := (*btcec.PublicKey)(
+				&privateKey.PublicKey,
+			).SerializeCompressed()
+
+			for i, sigHash := range sigHashes {
+				r, s, err := ecdsa.Sign(rand.Reader, privateKey, sigHash.Bytes())
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				signature := append(
+					(&btcec.Signature{R: r, S: s}).Serialize(),
+					byte(txscript.SigHashAll),
+				)
+
+				packet.inputs[i].add(psbtInputPartialSig, publicKeyBytes, signature)
+			}
+
+			test.modifyPacket(packet)
+
+			modifiedPSBT, err := packet.serialize()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			signatures, err := builder.ImportPSBTSignatures(modifiedPSBT)
+			if test.expectedError {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			transaction, err := builder.AddSignatures(signatures)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// Signed inputs have a non-empty witness or signature script.
+			for i, input := range transaction.Inputs {
+				if len(input.Witness) == 0 && len(input.SignatureScript) == 0 {
+					t.Errorf("input [%v] is not signed", i)
+				}
+			}
+		})
+	}
+}
+
+func TestParsePSBT(t *testing.T) {
+	builder, _, _, _ := newPSBTTestBuilder(t)
+
+	psbt, err := builder.ExportPSBT()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var tests = map[string]struct {
+		psbt []byte
+	}{
+		"wrong magic": {
+			psbt: append([]byte{0x00}, psbt[1:]...),
+		},
+		"truncated": {
+			psbt: psbt[:len(psbt)-1],
+		},
+		"trailing data": {
+			psbt: append(append([]byte{}, psbt...), 0x00),
+		},
+		"duplicated key": {
+			// Two global fields with the same one-byte key and one-byte
+			// value.
+			psbt: append(
+				append([]byte{}, psbtMagic...),
+				0x01, psbtGlobalUnsignedTx, 0x01, 0x00,
+				0x01, psbtGlobalUnsignedTx, 0x01, 0x00,
+				0x00,
+			),
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			_, _, err := parsePSBT(test.psbt)
+			if err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
+}
+
+// newPSBTTestBuilder prepares a transaction builder with P2WPKH, P2SH and
+// P2PKH inputs that can be signed using the returned private key.
+func newPSBTTestBuilder(t *testing.T) (
+	*TransactionBuilder,
+	*Transaction,
+	Script,
+	*ecdsa.PrivateKey,
+) {
+	privateKey, err := ecdsa.GenerateKey(btcec.S256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	publicKeyBytes := (*btcec.PublicKey)(
+		&privateKey.PublicKey,
+	).SerializeCompressed()
+
+	publicKeyHash := PublicKeyHash(&privateKey.PublicKey)
+
+	witnessPublicKeyHashScript, err := PayToWitnessPublicKeyHash(publicKeyHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	publicKeyHashScript, err := PayToPublicKeyHash(publicKeyHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	redeemScript, err := txscript.NewScriptBuilder().
+		AddData(publicKeyBytes).
+		AddOp(txscript.OP_CHECKSIG).
+		Script()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scriptHashScript, err := PayToScriptHash(ScriptHash(redeemScript))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	previousTransaction := &Transaction{
+		Version: 1,
+		Inputs: []*TransactionInput{
+			{
+				Outpoint: &TransactionOutpoint{
+					TransactionHash: Hash{0x01},
+					OutputIndex:     0,
+				},
+				Sequence: 0xffffffff,
+			},
+		},
+		Outputs: []*TransactionOutput{
+			{Value: 10000, PublicKeyScript: witnessPublicKeyHashScript},
+			{Value: 20000, PublicKeyScript: scriptHashScript},
+			{Value: 30000, PublicKeyScript: publicKeyHashScript},
+		},
+	}
+
+	localChain := newLocalChain()
+	if err := localChain.addTransaction(previousTransaction); err != nil {
+		t.Fatal(err)
+	}
+
+	builder := NewTransactionBuilder(localChain)
+
+	for i, output := range previousTransaction.Outputs {
+		utxo := &UnspentTransactionOutput{
+			Outpoint: &TransactionOutpoint{
+				TransactionHash: previousTransaction.Hash(),
+				OutputIndex:     uint32(i),
+			},
+			Value: output.Value,
+		}
+
+		if bytes.Equal(output.PublicKeyScript, scriptHashScript) {
+			err = builder.AddScriptHashInput(utxo, redeemScript)
+		} else {
+			err = builder.AddPublicKeyHashInput(utxo)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	builder.AddOutput(&TransactionOutput{
+		Value:           59000,
+		PublicKeyScript: witnessPublicKeyHashScript,
+	})
+
+	return builder, previousTransaction, redeemScript, privateKey
+}
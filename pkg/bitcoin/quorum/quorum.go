@@ -0,0 +1,749 @@
+// Package quorum provides a bitcoin.Chain decorator that verifies reads
+// against multiple independent Bitcoin chain backends.
+package quorum
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ipfs/go-log"
+	"go.uber.org/zap"
+
+	"github.com/keep-network/keep-core/pkg/bitcoin"
+	"github.com/keep-network/keep-core/pkg/clientinfo"
+)
+
+var logger = log.Logger("keep-bitcoin-quorum")
+
+// Backend is a single Bitcoin chain backend taking part in the quorum.
+type Backend struct {
+	// Name identifies the backend in logs, e.g. `electrum` or
+	// `esplora`.
+	Name string
+	// Chain is the handle used to interact with the backend.
+	Chain bitcoin.Chain
+}
+
+// Chain is a bitcoin.Chain implementation fanning out reads to multiple
+// backends and returning only results agreed by a quorum of them. This
+// protects against a single malicious or lagging backend feeding wrong data
+// into SPV proofs and wallet proposals.
+type Chain struct {
+	backends  []Backend
+	threshold int
+
+	// metricsRecorderMutex protects concurrent access to metricsRecorder
+	metricsRecorderMutex sync.RWMutex
+	// metricsRecorder is optional and used for recording disagreements
+	// between backends
+	metricsRecorder interface {
+		IncrementCounter(name string, value float64)
+	}
+}
+
+// New creates a new quorum-verified Bitcoin chain handle over the given
+// backends. The threshold is the minimum number of backends that must return
+// the same result for it to be accepted. If the threshold is zero, a simple
+// majority of backends is required.
+func New(threshold int, backends ...Backend) (*Chain, error) {
+	if len(backends) == 0 {
+		return nil, fmt.Errorf("no backends configured")
+	}
+
+	if threshold == 0 {
+		threshold = len(backends)/2 + 1
+	}
+
+	if threshold < 1 || threshold > len(backends) {
+		return nil, fmt.Errorf(
+			"threshold [%d] must be in range [1, %d]",
+			threshold,
+			len(backends),
+		)
+	}
+
+	return &Chain{
+		backends:  backends,
+		threshold: threshold,
+	}, nil
+}
+
+// SetMetricsRecorder sets the metrics recorder used to record disagreements
+// between backends and failures to reach the quorum.
+func (c *Chain) SetMetricsRecorder(recorder interface {
+	IncrementCounter(name string, value float64)
+}) {
+	c.metricsRecorderMutex.Lock()
+	defer c.metricsRecorderMutex.Unlock()
+	c.metricsRecorder = recorder
+}
+
+func (c *Chain) incrementCounter(name string) {
+	c.metricsRecorderMutex.RLock()
+	defer c.metricsRecorderMutex.RUnlock()
+
+	if c.metricsRecorder != nil {
+		c.metricsRecorder.IncrementCounter(name, 1)
+	}
+}
+
+// GetTransaction gets the transaction with the given transaction hash.
+// The transaction must be returned by a quorum of backends.
+func (c *Chain) GetTransaction(
+	transactionHash bitcoin.Hash,
+) (*bitcoin.Transaction, error) {
+	return vote(
+		c,
+		"GetTransaction",
+		func(chain bitcoin.Chain) (*bitcoin.Transaction, error) {
+			return chain.GetTransaction(transactionHash)
+		},
+		transactionKey,
+	)
+}
+
+// GetTransactionConfirmations gets the number of confirmations for the
+// transaction with the given transaction hash. The returned value is the
+// highest number of confirmations reported by at least a quorum of backends
+// so a single backend cannot inflate it.
+func (c *Chain) GetTransactionConfirmations(
+	transactionHash bitcoin.Hash,
+) (uint, error) {
+	return voteAtLeast(
+		c,
+		"GetTransactionConfirmations",
+		func(chain bitcoin.Chain) (uint, error) {
+			return chain.GetTransactionConfirmations(transactionHash)
+		},
+	)
+}
+
+// BroadcastTransaction broadcasts the given transaction using all backends.
+// The broadcast is considered successful if at least one backend accepted
+// the transaction.
+func (c *Chain) BroadcastTransaction(transaction *bitcoin.Transaction) error {
+	errs := c.fanOut(func(backend Backend) error {
+		return backend.Chain.BroadcastTransaction(transaction)
+	})
+
+	var failures []string
+	for i, err := range errs {
+		if err != nil {
+			logger.Warnf(
+				"backend [%s] failed to broadcast transaction [%s]: [%v]",
+				c.backends[i].Name,
+				transaction.Hash().Hex(bitcoin.ReversedByteOrder),
+				err,
+			)
+			failures = append(
+				failures,
+				fmt.Sprintf("%s: %v", c.backends[i].Name, err),
+			)
+		}
+	}
+
+	if len(failures) == len(c.backends) {
+		return fmt.Errorf(
+			"all backends failed to broadcast transaction: [%s]",
+			strings.Join(failures, "; "),
+		)
+	}
+
+	return nil
+}
+
+// GetLatestBlockHeight gets the height of the latest block (tip). The
+// returned value is the highest block height reported by at least a quorum
+// of backends so lagging backends do not prevent progress as long as a
+// quorum of them is synced.
+func (c *Chain) GetLatestBlockHeight() (uint, error) {
+	return voteAtLeast(
+		c,
+		"GetLatestBlockHeight",
+		func(chain bitcoin.Chain) (uint, error) {
+			return chain.GetLatestBlockHeight()
+		},
+	)
+}
+
+// GetBlockHeader gets the block header for the given block height. The
+// block header must be returned by a quorum of backends.
+func (c *Chain) GetBlockHeader(
+	blockHeight uint,
+) (*bitcoin.BlockHeader, error) {
+	return vote(
+		c,
+		"GetBlockHeader",
+		func(chain bitcoin.Chain) (*bitcoin.BlockHeader, error) {
+			return chain.GetBlockHeader(blockHeight)
+		},
+		func(blockHeader *bitcoin.BlockHeader) string {
+			serialized := blockHeader.Serialize()
+			return hex.EncodeToString(serialized[:])
+		},
+	)
+}
+
+// GetTransactionMerkleProof gets the Merkle proof for a given transaction.
+// The proof must be returned by a quorum of backends.
+func (c *Chain) GetTransactionMerkleProof(
+	transactionHash bitcoin.Hash,
+	blockHeight uint,
+) (*bitcoin.TransactionMerkleProof, error) {
+	return vote(
+		c,
+		"GetTransactionMerkleProof",
+		func(chain bitcoin.Chain) (*bitcoin.TransactionMerkleProof, error) {
+			return chain.GetTransactionMerkleProof(transactionHash, blockHeight)
+		},
+		func(proof *bitcoin.TransactionMerkleProof) string {
+			return fmt.Sprintf(
+				"%d:%d:%s",
+				proof.BlockHeight,
+				proof.Position,
+				strings.Join(proof.MerkleNodes, ","),
+			)
+		},
+	)
+}
+
+// GetTransactionsForPublicKeyHash gets the confirmed transactions that pays
+// the given public key hash using either a P2PKH or P2WPKH script. Backends
+// may legitimately differ on the most recent transactions so only
+// transactions returned by a quorum of backends are returned.
+func (c *Chain) GetTransactionsForPublicKeyHash(
+	publicKeyHash [20]byte,
+	limit int,
+) ([]*bitcoin.Transaction, error) {
+	return voteSet(
+		c,
+		"GetTransactionsForPublicKeyHash",
+		func(chain bitcoin.Chain) ([]*bitcoin.Transaction, error) {
+			return chain.GetTransactionsForPublicKeyHash(publicKeyHash, limit)
+		},
+		transactionKey,
+	)
+}
+
+// GetTxHashesForPublicKeyHash gets hashes of confirmed transactions that pays
+// the given public key hash using either a P2PKH or P2WPKH script. Backends
+// may legitimately differ on the most recent transactions so only hashes
+// returned by a quorum of backends are returned.
+func (c *Chain) GetTxHashesForPublicKeyHash(
+	publicKeyHash [20]byte,
+) ([]bitcoin.Hash, error) {
+	return voteSet(
+		c,
+		"GetTxHashesForPublicKeyHash",
+		func(chain bitcoin.Chain) ([]bitcoin.Hash, error) {
+			return chain.GetTxHashesForPublicKeyHash(publicKeyHash)
+		},
+		func(hash bitcoin.Hash) string {
+			return hash.String()
+		},
+	)
+}
+
+// GetMempoolForPublicKeyHash gets the unconfirmed mempool transactions
+// that pays the given public key hash using either a P2PKH or P2WPKH script.
+// Mempool contents legitimately differ between backends so only
+// transactions returned by a quorum of backends are returned.
+func (c *Chain) GetMempoolForPublicKeyHash(
+	publicKeyHash [20]byte,
+) ([]*bitcoin.Transaction, error) {
+	return voteSet(
+		c,
+		"GetMempoolForPublicKeyHash",
+		func(chain bitcoin.Chain) ([]*bitcoin.Transaction, error) {
+			return chain.GetMempoolForPublicKeyHash(publicKeyHash)
+		},
+		transactionKey,
+	)
+}
+
+// GetUtxosForPublicKeyHash gets unspent outputs of confirmed transactions
+// that are controlled by the given public key hash. The unspent outputs must
+// be returned by a quorum of backends.
+func (c *Chain) GetUtxosForPublicKeyHash(
+	publicKeyHash [20]byte,
+) ([]*bitcoin.UnspentTransactionOutput, error) {
+	return vote(
+		c,
+		"GetUtxosForPublicKeyHash",
+		func(chain bitcoin.Chain) ([]*bitcoin.UnspentTransactionOutput, error) {
+			return chain.GetUtxosForPublicKeyHash(publicKeyHash)
+		},
+		func(utxos []*bitcoin.UnspentTransactionOutput) string {
+			return utxosKey(utxos, false)
+		},
+	)
+}
+
+// GetMempoolUtxosForPublicKeyHash gets unspent outputs of unconfirmed
+// transactions that are controlled by the given public key hash. Mempool
+// contents legitimately differ between backends so only unspent outputs
+// returned by a quorum of backends are returned.
+func (c *Chain) GetMempoolUtxosForPublicKeyHash(
+	publicKeyHash [20]byte,
+) ([]*bitcoin.UnspentTransactionOutput, error) {
+	return voteSet(
+		c,
+		"GetMempoolUtxosForPublicKeyHash",
+		func(chain bitcoin.Chain) ([]*bitcoin.UnspentTransactionOutput, error) {
+			return chain.GetMempoolUtxosForPublicKeyHash(publicKeyHash)
+		},
+		utxoKey,
+	)
+}
+
+// EstimateSatPerVByteFee returns the estimated sat/vbyte fee for a
+// transaction to be confirmed within the given number of blocks. Fee
+// estimates naturally differ between backends so the median of estimates
+// returned by at least a quorum of backends is used.
+func (c *Chain) EstimateSatPerVByteFee(blocks uint32) (int64, error) {
+	results := make([]int64, len(c.backends))
+	errs := c.fanOutIndexed(func(i int, backend Backend) error {
+		fee, err := backend.Chain.EstimateSatPerVByteFee(blocks)
+		results[i] = fee
+		return err
+	})
+
+	var fees []int64
+	for i, err := range errs {
+		if err != nil {
+			logger.Warnf(
+				"backend [%s] failed to estimate fee: [%v]",
+				c.backends[i].Name,
+				err,
+			)
+			continue
+		}
+		fees = append(fees, results[i])
+	}
+
+	if len(fees) < c.threshold {
+		c.incrementCounter(clientinfo.MetricBitcoinQuorumFailuresTotal)
+		return 0, fmt.Errorf(
+			"only [%d] backends estimated fee; required [%d]",
+			len(fees),
+			c.threshold,
+		)
+	}
+
+	sort.Slice(fees, func(i, j int) bool { return fees[i] < fees[j] })
+
+	return fees[len(fees)/2], nil
+}
+
+// GetCoinbaseTxHash gets the hash of the coinbase transaction for the given
+// block height. The hash must be returned by a quorum of backends.
+func (c *Chain) GetCoinbaseTxHash(blockHeight uint) (bitcoin.Hash, error) {
+	return vote(
+		c,
+		"GetCoinbaseTxHash",
+		func(chain bitcoin.Chain) (bitcoin.Hash, error) {
+			return chain.GetCoinbaseTxHash(blockHeight)
+		},
+		func(hash bitcoin.Hash) string {
+			return hash.String()
+		},
+	)
+}
+
+// GetSpendingTxHash gets the hash of the confirmed transaction spending
+// the given transaction outpoint. The result must be returned by a quorum
+// of backends. Backends not implementing bitcoin.SpendingTxFinder fail the
+// request so the quorum must be reached by those supporting it.
+func (c *Chain) GetSpendingTxHash(
+	outpoint *bitcoin.TransactionOutpoint,
+) (bitcoin.Hash, bool, error) {
+	type spending struct {
+		txHash bitcoin.Hash
+		spent  bool
+	}
+
+	result, err := vote(
+		c,
+		"GetSpendingTxHash",
+		func(chain bitcoin.Chain) (*spending, error) {
+			finder, ok := chain.(bitcoin.SpendingTxFinder)
+			if !ok {
+				return nil, fmt.Errorf(
+					"backend does not support finding spending transactions",
+				)
+			}
+
+			txHash, spent, err := finder.GetSpendingTxHash(outpoint)
+			return &spending{txHash, spent}, err
+		},
+		func(result *spending) string {
+			if !result.spent {
+				return "unspent"
+			}
+			return result.txHash.String()
+		},
+	)
+	if err != nil {
+		return bitcoin.Hash{}, false, err
+	}
+
+	return result.txHash, result.spent, nil
+}
+
+// fanOut executes the given function against all backends concurrently and
+// returns errors indexed the same way as backends.
+func (c *Chain) fanOut(fn func(backend Backend) error) []error {
+	return c.fanOutIndexed(func(_ int, backend Backend) error {
+		return fn(backend)
+	})
+}
+
+// fanOutIndexed executes the given function against all backends
+// concurrently and returns errors indexed the same way as backends.
+func (c *Chain) fanOutIndexed(fn func(i int, backend Backend) error) []error {
+	errs := make([]error, len(c.backends))
+
+	wg := sync.WaitGroup{}
+	wg.Add(len(c.backends))
+
+	for i, backend := range c.backends {
+		go func(i int, backend Backend) {
+			defer wg.Done()
+			errs[i] = fn(i, backend)
+		}(i, backend)
+	}
+
+	wg.Wait()
+
+	return errs
+}
+
+// vote fetches a result from all backends and returns the result agreed by
+// at least a quorum of them. Results are compared using the keys computed
+// by the given key function.
+func vote[T any](
+	c *Chain,
+	operation string,
+	fetch func(chain bitcoin.Chain) (T, error),
+	key func(result T) string,
+) (T, error) {
+	var zero T
+
+	results := make([]T, len(c.backends))
+	errs := c.fanOutIndexed(func(i int, backend Backend) error {
+		result, err := fetch(backend.Chain)
+		results[i] = result
+		return err
+	})
+
+	keys := make([]string, len(c.backends))
+	// votes holds the names of backends grouped by result key.
+	votes := make(map[string][]string)
+	// order holds result keys in order of appearance to keep the
+	// outcome deterministic.
+	var order []string
+	var failures []string
+
+	for i, err := range errs {
+		name := c.backends[i].Name
+
+		if err != nil {
+			logger.Warnf(
+				"backend [%s] failed to execute [%s]: [%v]",
+				name,
+				operation,
+				err,
+			)
+			failures = append(failures, fmt.Sprintf("%s: %v", name, err))
+			continue
+		}
+
+		keys[i] = key(results[i])
+		if _, ok := votes[keys[i]]; !ok {
+			order = append(order, keys[i])
+		}
+		votes[keys[i]] = append(votes[keys[i]], name)
+	}
+
+	if len(votes) > 1 {
+		groups := make([]string, 0, len(order))
+		for _, k := range order {
+			groups = append(groups, strings.Join(votes[k], ","))
+		}
+
+		logger.With(
+			zap.String("operation", operation),
+			zap.Strings("groups", groups),
+		).Warnf("backends returned [%d] different results", len(votes))
+
+		c.incrementCounter(clientinfo.MetricBitcoinQuorumDisagreementsTotal)
+	}
+
+	winner, winnerVotes, tie := "", 0, false
+	for _, k := range order {
+		switch count := len(votes[k]); {
+		case count > winnerVotes:
+			winner, winnerVotes, tie = k, count, false
+		case count == winnerVotes:
+			tie = true
+		}
+	}
+
+	if winnerVotes < c.threshold || tie {
+		c.incrementCounter(clientinfo.MetricBitcoinQuorumFailuresTotal)
+
+		message := fmt.Sprintf(
+			"quorum not reached for [%s]; [%d] agreeing backends, "+
+				"required [%d]",
+			operation,
+			winnerVotes,
+			c.threshold,
+		)
+		if tie {
+			message += "; tie between results"
+		}
+		if len(votes) > 1 {
+			results := make([]string, 0, len(order))
+			for _, k := range order {
+				results = append(
+					results,
+					fmt.Sprintf(
+						"%s: %s",
+						strings.Join(votes[k], ","),
+						abbreviateKey(k),
+					),
+				)
+			}
+
+			message += fmt.Sprintf(
+				"; results: [%s]",
+				strings.Join(results, "; "),
+			)
+		}
+		if len(failures) > 0 {
+			message += fmt.Sprintf(
+				"; failed backends: [%s]",
+				strings.Join(failures, "; "),
+			)
+		}
+
+		return zero, errors.New(message)
+	}
+
+	for i := range c.backends {
+		if errs[i] == nil && keys[i] == winner {
+			return results[i], nil
+		}
+	}
+
+	// Should never happen as the winner comes from one of the results.
+	return zero, fmt.Errorf("cannot find result agreed by quorum")
+}
+
+// voteSet fetches a list of elements from all backends and returns the
+// elements returned by at least a quorum of them. It is used for results
+// that legitimately differ between honest backends, like mempool contents
+// or the most recent transactions, where an exact match of whole results
+// would often fail. Elements are compared using the keys computed by the
+// given key function. At least a quorum of backends must respond.
+func voteSet[T any](
+	c *Chain,
+	operation string,
+	fetch func(chain bitcoin.Chain) ([]T, error),
+	key func(element T) string,
+) ([]T, error) {
+	results := make([][]T, len(c.backends))
+	errs := c.fanOutIndexed(func(i int, backend Backend) error {
+		result, err := fetch(backend.Chain)
+		results[i] = result
+		return err
+	})
+
+	var responding []int
+	var failures []string
+	for i, err := range errs {
+		if err != nil {
+			logger.Warnf(
+				"backend [%s] failed to execute [%s]: [%v]",
+				c.backends[i].Name,
+				operation,
+				err,
+			)
+			failures = append(
+				failures,
+				fmt.Sprintf("%s: %v", c.backends[i].Name, err),
+			)
+			continue
+		}
+		responding = append(responding, i)
+	}
+
+	if len(responding) < c.threshold {
+		c.incrementCounter(clientinfo.MetricBitcoinQuorumFailuresTotal)
+		return nil, fmt.Errorf(
+			"quorum not reached for [%s]; [%d] responding backends, "+
+				"required [%d]; failed backends: [%s]",
+			operation,
+			len(responding),
+			c.threshold,
+			strings.Join(failures, "; "),
+		)
+	}
+
+	// The longest result determines the order of elements so the order
+	// returned by backends is kept as much as possible.
+	sort.SliceStable(responding, func(i, j int) bool {
+		return len(results[responding[i]]) > len(results[responding[j]])
+	})
+
+	// votes holds the number of backends returning each element, by key.
+	votes := make(map[string]int)
+	// order holds elements in order of appearance.
+	var order []T
+	for _, i := range responding {
+		// A backend votes for each element once even if it returned the
+		// element multiple times.
+		seen := make(map[string]bool)
+		for _, element := range results[i] {
+			k := key(element)
+			if seen[k] {
+				continue
+			}
+			seen[k] = true
+
+			if votes[k] == 0 {
+				order = append(order, element)
+			}
+			votes[k]++
+		}
+	}
+
+	agreed := make([]T, 0)
+	for _, element := range order {
+		if votes[key(element)] >= c.threshold {
+			agreed = append(agreed, element)
+		}
+	}
+
+	if len(votes) != len(agreed) {
+		logger.With(
+			zap.String("operation", operation),
+			zap.Int("agreedElements", len(agreed)),
+			zap.Int("allElements", len(votes)),
+		).Warnf(
+			"[%d] elements were not returned by a quorum of backends",
+			len(votes)-len(agreed),
+		)
+
+		c.incrementCounter(clientinfo.MetricBitcoinQuorumDisagreementsTotal)
+	}
+
+	return agreed, nil
+}
+
+// voteAtLeast fetches a numeric value from all backends and returns the
+// highest value that at least a quorum of backends reached. This is used
+// for monotonically increasing values, like block height or number of
+// confirmations, where lagging backends naturally return lower values.
+func voteAtLeast(
+	c *Chain,
+	operation string,
+	fetch func(chain bitcoin.Chain) (uint, error),
+) (uint, error) {
+	results := make([]uint, len(c.backends))
+	errs := c.fanOutIndexed(func(i int, backend Backend) error {
+		result, err := fetch(backend.Chain)
+		results[i] = result
+		return err
+	})
+
+	var values []uint
+	var failures []string
+	for i, err := range errs {
+		if err != nil {
+			logger.Warnf(
+				"backend [%s] failed to execute [%s]: [%v]",
+				c.backends[i].Name,
+				operation,
+				err,
+			)
+			failures = append(
+				failures,
+				fmt.Sprintf("%s: %v", c.backends[i].Name, err),
+			)
+			continue
+		}
+		values = append(values, results[i])
+	}
+
+	if len(values) < c.threshold {
+		c.incrementCounter(clientinfo.MetricBitcoinQuorumFailuresTotal)
+		return 0, fmt.Errorf(
+			"quorum not reached for [%s]; [%d] responding backends, "+
+				"required [%d]; failed backends: [%s]",
+			operation,
+			len(values),
+			c.threshold,
+			strings.Join(failures, "; "),
+		)
+	}
+
+	sort.Slice(values, func(i, j int) bool { return values[i] > values[j] })
+
+	if values[0] != values[len(values)-1] {
+		logger.With(
+			zap.String("operation", operation),
+			zap.Uints("values", values),
+		).Warnf("backends returned different values")
+
+		c.incrementCounter(clientinfo.MetricBitcoinQuorumDisagreementsTotal)
+	}
+
+	return values[c.threshold-1], nil
+}
+
+func transactionKey(transaction *bitcoin.Transaction) string {
+	return hex.EncodeToString(transaction.Serialize(bitcoin.Witness))
+}
+
+func utxosKey(
+	utxos []*bitcoin.UnspentTransactionOutput,
+	unordered bool,
+) string {
+	keys := make([]string, len(utxos))
+	for i, utxo := range utxos {
+		keys[i] = utxoKey(utxo)
+	}
+
+	if unordered {
+		sort.Strings(keys)
+	}
+
+	return strings.Join(keys, ",")
+}
+
+func utxoKey(utxo *bitcoin.UnspentTransactionOutput) string {
+	return fmt.Sprintf(
+		"%s:%d:%d",
+		utxo.Outpoint.TransactionHash.String(),
+		utxo.Outpoint.OutputIndex,
+		utxo.Value,
+	)
+}
+
+// abbreviateKey shortens the given result key so results can be included in
+// errors without flooding logs with serialized transactions. Both ends of
+// the key are kept as results often share a common prefix.
+func abbreviateKey(key string) string {
+	const partLength = 48
+
+	if len(key) <= 2*partLength {
+		return key
+	}
+
+	return key[:partLength] + "..." + key[len(key)-partLength:]
+}
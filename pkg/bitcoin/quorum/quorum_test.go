@@ -0,0 +1,627 @@
+package quorum
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/keep-network/keep-core/internal/testutils"
+	"github.com/keep-network/keep-core/pkg/bitcoin"
+	"github.com/keep-network/keep-core/pkg/clientinfo"
+)
+
+func TestNew(t *testing.T) {
+	var tests = map[string]struct {
+		threshold         int
+		backendsCount     int
+		expectedThreshold int
+		expectedErr       error
+	}{
+		"default threshold for three backends": {
+			threshold:         0,
+			backendsCount:     3,
+			expectedThreshold: 2,
+		},
+		"default threshold for four backends": {
+			threshold:         0,
+			backendsCount:     4,
+			expectedThreshold: 3,
+		},
+		"explicit threshold": {
+			threshold:         3,
+			backendsCount:     3,
+			expectedThreshold: 3,
+		},
+		"threshold above backends count": {
+			threshold:     4,
+			backendsCount: 3,
+			expectedErr:   fmt.Errorf("threshold [4] must be in range [1, 3]"),
+		},
+		"no backends": {
+			threshold:     0,
+			backendsCount: 0,
+			expectedErr:   fmt.Errorf("no backends configured"),
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			backends := make([]Backend, test.backendsCount)
+			for i := range backends {
+				backends[i] = Backend{
+					Name:  fmt.Sprintf("backend-%d", i),
+					Chain: &stubChain{},
+				}
+			}
+
+			chain, err := New(test.threshold, backends...)
+
+			if !reflect.DeepEqual(test.expectedErr, err) {
+				t.Fatalf(
+					"unexpected error\nexpected: %v\nactual:   %v",
+					test.expectedErr,
+					err,
+				)
+			}
+
+			if test.expectedErr == nil {
+				testutils.AssertIntsEqual(
+					t,
+					"threshold",
+					test.expectedThreshold,
+					chain.threshold,
+				)
+			}
+		})
+	}
+}
+
+func TestChain_GetUtxosForPublicKeyHash(t *testing.T) {
+	utxo := func(txHashByte byte, value int64) *bitcoin.UnspentTransactionOutput {
+		return &bitcoin.UnspentTransactionOutput{
+			Outpoint: &bitcoin.TransactionOutpoint{
+				TransactionHash: bitcoin.Hash{txHashByte},
+				OutputIndex:     1,
+			},
+			Value: value,
+		}
+	}
+
+	honest := []*bitcoin.UnspentTransactionOutput{utxo(1, 1000), utxo(2, 2000)}
+	forged := []*bitcoin.UnspentTransactionOutput{utxo(1, 1000), utxo(3, 5000)}
+
+	var tests = map[string]struct {
+		results               [][]*bitcoin.UnspentTransactionOutput
+		errs                  []error
+		expectedUtxos         []*bitcoin.UnspentTransactionOutput
+		expectedErr           error
+		expectedDisagreements float64
+		expectedFailures      float64
+	}{
+		"all backends agree": {
+			results:       [][]*bitcoin.UnspentTransactionOutput{honest, honest, honest},
+			expectedUtxos: honest,
+		},
+		"single backend disagrees": {
+			results:               [][]*bitcoin.UnspentTransactionOutput{forged, honest, honest},
+			expectedUtxos:         honest,
+			expectedDisagreements: 1,
+		},
+		"single backend fails": {
+			results:       [][]*bitcoin.UnspentTransactionOutput{nil, honest, honest},
+			errs:          []error{fmt.Errorf("timeout"), nil, nil},
+			expectedUtxos: honest,
+		},
+		"no quorum": {
+			results: [][]*bitcoin.UnspentTransactionOutput{forged, honest, nil},
+			errs:    []error{nil, nil, fmt.Errorf("timeout")},
+			expectedErr: fmt.Errorf(
+				"quorum not reached for [GetUtxosForPublicKeyHash]; " +
+					"[1] agreeing backends, required [2]; tie between results; " +
+					"results: [" +
+					"backend-0: 010000000000000000000000000000000000000000000000" +
+					"...00000000000000000000000000000000000000000:1:5000; " +
+					"backend-1: 010000000000000000000000000000000000000000000000" +
+					"...00000000000000000000000000000000000000000:1:2000]; " +
+					"failed backends: [backend-2: timeout]",
+			),
+			expectedDisagreements: 1,
+			expectedFailures:      1,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			backends := make([]Backend, len(test.results))
+			for i := range test.results {
+				var err error
+				if test.errs != nil {
+					err = test.errs[i]
+				}
+
+				backends[i] = Backend{
+					Name:  fmt.Sprintf("backend-%d", i),
+					Chain: &stubChain{utxos: test.results[i], err: err},
+				}
+			}
+
+			chain, err := New(2, backends...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			recorder := newCountingRecorder()
+			chain.SetMetricsRecorder(recorder)
+
+			utxos, err := chain.GetUtxosForPublicKeyHash([20]byte{})
+
+			if !reflect.DeepEqual(test.expectedErr, err) {
+				t.Errorf(
+					"unexpected error\nexpected: %v\nactual:   %v",
+					test.expectedErr,
+					err,
+				)
+			}
+
+			if !reflect.DeepEqual(test.expectedUtxos, utxos) {
+				t.Errorf(
+					"unexpected UTXOs\nexpected: %v\nactual:   %v",
+					test.expectedUtxos,
+					utxos,
+				)
+			}
+
+			testutils.AssertIntsEqual(
+				t,
+				"disagreements",
+				int(test.expectedDisagreements),
+				int(recorder.get(clientinfo.MetricBitcoinQuorumDisagreementsTotal)),
+			)
+			testutils.AssertIntsEqual(
+				t,
+				"failures",
+				int(test.expectedFailures),
+				int(recorder.get(clientinfo.MetricBitcoinQuorumFailuresTotal)),
+			)
+		})
+	}
+}
+
+func TestChain_GetTxHashesForPublicKeyHash(t *testing.T) {
+	first, second, third := bitcoin.Hash{1}, bitcoin.Hash{2}, bitcoin.Hash{3}
+
+	var tests = map[string]struct {
+		results               [][]bitcoin.Hash
+		errs                  []error
+		expectedHashes        []bitcoin.Hash
+		expectedErr           error
+		expectedDisagreements float64
+		expectedFailures      float64
+	}{
+		"all backends agree": {
+			results: [][]bitcoin.Hash{
+				{first, second},
+				{first, second},
+				{first, second},
+			},
+			expectedHashes: []bitcoin.Hash{first, second},
+		},
+		"single backend lagging": {
+			results: [][]bitcoin.Hash{
+				{first},
+				{first, second},
+				{first, second},
+			},
+			expectedHashes: []bitcoin.Hash{first, second},
+		},
+		"backends know different recent transactions": {
+			results: [][]bitcoin.Hash{
+				{first, second},
+				{first, third},
+				{first},
+			},
+			expectedHashes:        []bitcoin.Hash{first},
+			expectedDisagreements: 1,
+		},
+		"not enough backends responding": {
+			results: [][]bitcoin.Hash{{first}, nil, nil},
+			errs:    []error{nil, fmt.Errorf("down"), fmt.Errorf("down")},
+			expectedErr: fmt.Errorf(
+				"quorum not reached for [GetTxHashesForPublicKeyHash]; " +
+					"[1] responding backends, required [2]; " +
+					"failed backends: [backend-1: down; backend-2: down]",
+			),
+			expectedFailures: 1,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			backends := make([]Backend, len(test.results))
+			for i := range test.results {
+				var err error
+				if test.errs != nil {
+					err = test.errs[i]
+				}
+
+				backends[i] = Backend{
+					Name:  fmt.Sprintf("backend-%d", i),
+					Chain: &stubChain{hashes: test.results[i], err: err},
+				}
+			}
+
+			chain, err := New(2, backends...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			recorder := newCountingRecorder()
+			chain.SetMetricsRecorder(recorder)
+
+			hashes, err := chain.GetTxHashesForPublicKeyHash([20]byte{})
+
+			if !reflect.DeepEqual(test.expectedErr, err) {
+				t.Errorf(
+					"unexpected error\nexpected: %v\nactual:   %v",
+					test.expectedErr,
+					err,
+				)
+			}
+
+			if err == nil && !reflect.DeepEqual(test.expectedHashes, hashes) {
+				t.Errorf(
+					"unexpected hashes\nexpected: %v\nactual:   %v",
+					test.expectedHashes,
+					hashes,
+				)
+			}
+
+			testutils.AssertIntsEqual(
+				t,
+				"disagreements",
+				int(test.expectedDisagreements),
+				int(recorder.get(clientinfo.MetricBitcoinQuorumDisagreementsTotal)),
+			)
+			testutils.AssertIntsEqual(
+				t,
+				"failures",
+				int(test.expectedFailures),
+				int(recorder.get(clientinfo.MetricBitcoinQuorumFailuresTotal)),
+			)
+		})
+	}
+}
+
+func TestChain_GetMempoolUtxosForPublicKeyHash_IndefiniteOrder(t *testing.T) {
+	first := &bitcoin.UnspentTransactionOutput{
+		Outpoint: &bitcoin.TransactionOutpoint{TransactionHash: bitcoin.Hash{1}},
+		Value:    1000,
+	}
+	second := &bitcoin.UnspentTransactionOutput{
+		Outpoint: &bitcoin.TransactionOutpoint{TransactionHash: bitcoin.Hash{2}},
+		Value:    2000,
+	}
+
+	chain, err := New(
+		2,
+		Backend{
+			Name: "first",
+			Chain: &stubChain{
+				utxos: []*bitcoin.UnspentTransactionOutput{first, second},
+			},
+		},
+		Backend{
+			Name: "second",
+			Chain: &stubChain{
+				utxos: []*bitcoin.UnspentTransactionOutput{second, first},
+			},
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	utxos, err := chain.GetMempoolUtxosForPublicKeyHash([20]byte{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertIntsEqual(t, "UTXOs count", 2, len(utxos))
+}
+
+func TestChain_GetLatestBlockHeight(t *testing.T) {
+	var tests = map[string]struct {
+		heights        []uint
+		errs           []error
+		threshold      int
+		expectedHeight uint
+		expectedErr    error
+	}{
+		"all backends synced": {
+			heights:        []uint{800000, 800000, 800000},
+			threshold:      2,
+			expectedHeight: 800000,
+		},
+		"single backend lagging": {
+			heights:        []uint{799998, 800000, 800000},
+			threshold:      2,
+			expectedHeight: 800000,
+		},
+		"single backend reporting inflated height": {
+			heights:        []uint{900000, 800000, 800000},
+			threshold:      2,
+			expectedHeight: 800000,
+		},
+		"all backends at different heights": {
+			heights:        []uint{800002, 800000, 800001},
+			threshold:      2,
+			expectedHeight: 800001,
+		},
+		"not enough backends responding": {
+			heights:   []uint{800000, 0, 0},
+			errs:      []error{nil, fmt.Errorf("down"), fmt.Errorf("down")},
+			threshold: 2,
+			expectedErr: fmt.Errorf(
+				"quorum not reached for [GetLatestBlockHeight]; " +
+					"[1] responding backends, required [2]; " +
+					"failed backends: [backend-1: down; backend-2: down]",
+			),
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			backends := make([]Backend, len(test.heights))
+			for i := range test.heights {
+				var err error
+				if test.errs != nil {
+					err = test.errs[i]
+				}
+
+				backends[i] = Backend{
+					Name:  fmt.Sprintf("backend-%d", i),
+					Chain: &stubChain{height: test.heights[i], err: err},
+				}
+			}
+
+			chain, err := New(test.threshold, backends...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			height, err := chain.GetLatestBlockHeight()
+
+			if !reflect.DeepEqual(test.expectedErr, err) {
+				t.Errorf(
+					"unexpected error\nexpected: %v\nactual:   %v",
+					test.expectedErr,
+					err,
+				)
+			}
+
+			testutils.AssertUintsEqual(
+				t,
+				"block height",
+				uint64(test.expectedHeight),
+				uint64(height),
+			)
+		})
+	}
+}
+
+func TestChain_BroadcastTransaction(t *testing.T) {
+	var tests = map[string]struct {
+		errs        []error
+		expectedErr error
+	}{
+		"all backends accept": {
+			errs: []error{nil, nil},
+		},
+		"single backend accepts": {
+			errs: []error{fmt.Errorf("rejected"), nil},
+		},
+		"all backends reject": {
+			errs: []error{fmt.Errorf("rejected"), fmt.Errorf("down")},
+			expectedErr: fmt.Errorf(
+				"all backends failed to broadcast transaction: " +
+					"[backend-0: rejected; backend-1: down]",
+			),
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			stubs := make([]*stubChain, len(test.errs))
+			backends := make([]Backend, len(test.errs))
+			for i := range test.errs {
+				stubs[i] = &stubChain{err: test.errs[i]}
+				backends[i] = Backend{
+					Name:  fmt.Sprintf("backend-%d", i),
+					Chain: stubs[i],
+				}
+			}
+
+			chain, err := New(1, backends...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = chain.BroadcastTransaction(&bitcoin.Transaction{})
+
+			if !reflect.DeepEqual(test.expectedErr, err) {
+				t.Errorf(
+					"unexpected error\nexpected: %v\nactual:   %v",
+					test.expectedErr,
+					err,
+				)
+			}
+
+			for i, stub := range stubs {
+				testutils.AssertIntsEqual(
+					t,
+					fmt.Sprintf("broadcasts of backend %d", i),
+					1,
+					stub.broadcasts,
+				)
+			}
+		})
+	}
+}
+
+func TestChain_EstimateSatPerVByteFee(t *testing.T) {
+	chain, err := New(
+		2,
+		Backend{Name: "low", Chain: &stubChain{fee: 5}},
+		Backend{Name: "high", Chain: &stubChain{fee: 500}},
+		Backend{Name: "mid", Chain: &stubChain{fee: 12}},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fee, err := chain.EstimateSatPerVByteFee(6)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertIntsEqual(t, "fee", 12, int(fee))
+}
+
+func TestChain_GetSpendingTxHash(t *testing.T) {
+	outpoint := &bitcoin.TransactionOutpoint{OutputIndex: 1}
+	spendingTxHash := bitcoin.Hash{0x01}
+
+	var tests = map[string]struct {
+		backends      []bitcoin.Chain
+		expectedError bool
+	}{
+		"quorum of backends supporting the request": {
+			backends: []bitcoin.Chain{
+				&stubChain{},
+				&spendingStubChain{txHash: spendingTxHash},
+				&spendingStubChain{txHash: spendingTxHash},
+			},
+		},
+		"not enough backends supporting the request": {
+			backends: []bitcoin.Chain{
+				&stubChain{},
+				&stubChain{},
+				&spendingStubChain{txHash: spendingTxHash},
+			},
+			expectedError: true,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			backends := make([]Backend, len(test.backends))
+			for i, backend := range test.backends {
+				backends[i] = Backend{
+					Name:  fmt.Sprintf("backend-%d", i),
+					Chain: backend,
+				}
+			}
+
+			chain, err := New(2, backends...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			txHash, spent, err := chain.GetSpendingTxHash(outpoint)
+
+			if test.expectedError {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			testutils.AssertBoolsEqual(t, "spent", true, spent)
+			testutils.AssertBytesEqual(t, spendingTxHash[:], txHash[:])
+		})
+	}
+}
+
+// stubChain is a bitcoin.Chain returning preconfigured results. Only methods
+// used by tests are implemented.
+type stubChain struct {
+	bitcoin.Chain
+
+	utxos      []*bitcoin.UnspentTransactionOutput
+	hashes     []bitcoin.Hash
+	height     uint
+	fee        int64
+	err        error
+	broadcasts int
+}
+
+func (sc *stubChain) GetUtxosForPublicKeyHash(
+	publicKeyHash [20]byte,
+) ([]*bitcoin.UnspentTransactionOutput, error) {
+	return sc.utxos, sc.err
+}
+
+func (sc *stubChain) GetMempoolUtxosForPublicKeyHash(
+	publicKeyHash [20]byte,
+) ([]*bitcoin.UnspentTransactionOutput, error) {
+	return sc.utxos, sc.err
+}
+
+func (sc *stubChain) GetTxHashesForPublicKeyHash(
+	publicKeyHash [20]byte,
+) ([]bitcoin.Hash, error) {
+	return sc.hashes, sc.err
+}
+
+func (sc *stubChain) GetLatestBlockHeight() (uint, error) {
+	return sc.height, sc.err
+}
+
+func (sc *stubChain) BroadcastTransaction(
+	transaction *bitcoin.Transaction,
+) error {
+	sc.broadcasts++
+	return sc.err
+}
+
+func (sc *stubChain) EstimateSatPerVByteFee(blocks uint32) (int64, error) {
+	return sc.fee, sc.err
+}
+
+// spendingStubChain is a stubChain able to find spending transactions.
+type spendingStubChain struct {
+	stubChain
+
+	txHash bitcoin.Hash
+}
+
+func (ssc *spendingStubChain) GetSpendingTxHash(
+	outpoint *bitcoin.TransactionOutpoint,
+) (bitcoin.Hash, bool, error) {
+	return ssc.txHash, true, ssc.err
+}
+
+type countingRecorder struct {
+	mutex    sync.Mutex
+	counters map[string]float64
+}
+
+func newCountingRecorder() *countingRecorder {
+	return &countingRecorder{counters: make(map[string]float64)}
+}
+
+func (cr *countingRecorder) IncrementCounter(name string, value float64) {
+	cr.mutex.Lock()
+	defer cr.mutex.Unlock()
+	cr.counters[name] += value
+}
+
+func (cr *countingRecorder) get(name string) float64 {
+	cr.mutex.Lock()
+	defer cr.mutex.Unlock()
+	return cr.counters[name]
+}
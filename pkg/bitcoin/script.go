@@ -19,6 +19,7 @@ const (
 	P2WPKHScript
 	P2SHScript
 	P2WSHScript
+	P2TRScript
 )
 
 func (st ScriptType) String() string {
@@ -31,6 +32,8 @@ func (st ScriptType) String() string {
 		return "P2SH"
 	case P2WSHScript:
 		return "P2WSH"
+	case P2TRScript:
+		return "P2TR"
 	default:
 		return "NonStandard"
 	}
@@ -147,8 +150,31 @@ func PayToScriptHash(scriptHash [20]byte) (Script, error) {
 		Script()
 }
 
+// PayToTaproot constructs a P2TR script for the provided 32-byte x-only
+// Taproot output key. The function assumes the provided output key is valid,
+// i.e. it was already tweaked according to BIP-341.
+func PayToTaproot(outputKey [32]byte) (Script, error) {
+	return txscript.NewScriptBuilder().
+		AddOp(txscript.OP_1).
+		AddData(outputKey[:]).
+		Script()
+}
+
+// isPayToTaproot checks whether the given Script is a P2TR script, i.e.
+// a version 1 witness program holding a 32-byte output key. The used
+// txscript version predates BIP-341 so this check must be done manually.
+func isPayToTaproot(script Script) bool {
+	return len(script) == 34 &&
+		script[0] == txscript.OP_1 &&
+		script[1] == txscript.OP_DATA_32
+}
+
 // GetScriptType gets the ScriptType of the given Script.
 func GetScriptType(script Script) ScriptType {
+	if isPayToTaproot(script) {
+		return P2TRScript
+	}
+
 	switch txscript.GetScriptClass(script) {
 	case txscript.PubKeyHashTy:
 		return P2PKHScript
@@ -189,3 +215,38 @@ func ExtractPublicKeyHash(script Script) ([20]byte, error) {
 
 	return publicKeyHash, nil
 }
+
+// ExtractTaprootOutputKey extracts the 32-byte x-only output key from
+// a P2TR script.
+func ExtractTaprootOutputKey(script Script) ([32]byte, error) {
+	if GetScriptType(script) != P2TRScript {
+		return [32]byte{}, fmt.Errorf("not a P2TR script")
+	}
+
+	var outputKey [32]byte
+	// Omit the first two 0x5120 bytes.
+	copy(outputKey[:], script[2:])
+
+	return outputKey, nil
+}
+
+// ExtractRedeemScript extracts the plain-text redeem script from the
+// unlocking data of the given input spending a P2SH or P2WSH UTXO. The redeem
+// script is the last item of the witness for P2WSH inputs and the last data
+// push of the signature script for P2SH inputs.
+func ExtractRedeemScript(input *TransactionInput) (Script, error) {
+	if len(input.Witness) > 0 {
+		return input.Witness[len(input.Witness)-1], nil
+	}
+
+	pushes, err := txscript.PushedData(input.SignatureScript)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse signature script: [%v]", err)
+	}
+
+	if len(pushes) == 0 {
+		return nil, fmt.Errorf("input does not contain a redeem script")
+	}
+
+	return pushes[len(pushes)-1], nil
+}
@@ -149,7 +149,10 @@ func createMerkleProof(txMerkleBranch *TransactionMerkleProof) (
 }
 
 // getHeadersChain gets a chain of Bitcoin block headers that starts at the
-// provided block height and has the specified chain length.
+// provided block height and has the specified chain length. Each header's
+// hash is checked against its declared target and its link to the previous
+// header is verified so proofs built on invalid headers are rejected before
+// being submitted. The difficulty itself is validated by the Bridge.
 func getHeadersChain(
 	btcChain Chain,
 	blockHeight uint,
@@ -158,12 +161,33 @@ func getHeadersChain(
 	// TODO: Consider exposing a function in the Bitcoin chain for returning
 	//       multiple block headers with one call.
 	var headersChain bytes.Buffer
+	var previousBlockHeader *BlockHeader
 
 	for i := blockHeight; i < blockHeight+chainLength; i++ {
 		blockHeader, err := btcChain.GetBlockHeader(i)
 		if err != nil {
 			return nil, err
 		}
+
+		if err := blockHeader.VerifyHashMeetsDeclaredTarget(); err != nil {
+			return nil, fmt.Errorf(
+				"invalid block header at height [%d]: [%w]",
+				i,
+				err,
+			)
+		}
+
+		if previousBlockHeader != nil &&
+			blockHeader.PreviousBlockHeaderHash != previousBlockHeader.Hash() {
+			return nil, fmt.Errorf(
+				"block header at height [%d] does not point to "+
+					"the block header at height [%d]",
+				i,
+				i-1,
+			)
+		}
+		previousBlockHeader = blockHeader
+
 		serializedBlockHeader := blockHeader.Serialize()
 		headersChain.Write(serializedBlockHeader[:])
 	}
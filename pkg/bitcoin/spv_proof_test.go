@@ -3,6 +3,7 @@ package bitcoin
 import (
 	"golang.org/x/exp/slices"
 	"reflect"
+	"strings"
 	"testing"
 
 	"encoding/hex"
@@ -450,3 +451,58 @@ func TestAssembleTransactionProof(t *testing.T) {
 		})
 	}
 }
+
+func TestGetHeadersChain_InvalidHeaders(t *testing.T) {
+	headersChain := SpvProofData["single input"].BitcoinChainData.HeadersChain
+
+	var tests = map[string]struct {
+		modifyFn    func(headers map[uint]*BlockHeader)
+		expectedErr string
+	}{
+		"hash above target": {
+			modifyFn: func(headers map[uint]*BlockHeader) {
+				headers[2164154].Nonce++
+			},
+			expectedErr: "invalid block header at height [2164154]",
+		},
+		"broken link to the previous header": {
+			modifyFn: func(headers map[uint]*BlockHeader) {
+				// Replace the header with a valid header of another block.
+				headers[2164154] = SpvProofData["multiple inputs"].
+					BitcoinChainData.HeadersChain[2164160]
+			},
+			expectedErr: "block header at height [2164154] does not point to " +
+				"the block header at height [2164153]",
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			headers := make(map[uint]*BlockHeader)
+			for height, header := range headersChain {
+				headerCopy := *header
+				headers[height] = &headerCopy
+			}
+
+			test.modifyFn(headers)
+
+			bitcoinChain := newLocalChain()
+			for height, header := range headers {
+				bitcoinChain.addBlockHeader(height, header)
+			}
+
+			_, err := getHeadersChain(bitcoinChain, 2164152, 6)
+			if err == nil {
+				t.Fatal("expected error")
+			}
+
+			if !strings.HasPrefix(err.Error(), test.expectedErr) {
+				t.Errorf(
+					"unexpected error\nexpected prefix: %s\nactual:          %s",
+					test.expectedErr,
+					err.Error(),
+				)
+			}
+		})
+	}
+}
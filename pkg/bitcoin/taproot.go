@@ -0,0 +1,151 @@
+package bitcoin
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/btcsuite/btcd/wire"
+)
+
+// taprootSigHashTag is the BIP-340 tag used to compute BIP-341 signature
+// hashes.
+const taprootSigHashTag = "TapSighash"
+
+// taprootSigHashDefault is the BIP-341 SIGHASH_DEFAULT sighash type. It
+// commits to all inputs and outputs of the transaction, just like
+// SIGHASH_ALL, but allows to omit the sighash type byte in the signature.
+const taprootSigHashDefault = 0x00
+
+// taggedHash computes the BIP-340 tagged hash of the given message, i.e.
+// SHA256(SHA256(tag) || SHA256(tag) || message).
+func taggedHash(tag string, message []byte) [32]byte {
+	tagHash := sha256.Sum256([]byte(tag))
+
+	hasher := sha256.New()
+	hasher.Write(tagHash[:])
+	hasher.Write(tagHash[:])
+	hasher.Write(message)
+
+	var result [32]byte
+	copy(result[:], hasher.Sum(nil))
+
+	return result
+}
+
+// taprootSigHashFragments holds the transaction-wide digests that are
+// common for BIP-341 signature hashes of all inputs of the given
+// transaction. Those can be computed once and reused for all inputs.
+type taprootSigHashFragments struct {
+	prevOutputs      [32]byte
+	amounts          [32]byte
+	publicKeyScripts [32]byte
+	sequences        [32]byte
+	outputs          [32]byte
+}
+
+// newTaprootSigHashFragments computes the BIP-341 transaction-wide digests
+// for the given transaction. The sigHashArgs slice must hold arguments of
+// all transaction inputs, in the same order as inputs.
+func newTaprootSigHashFragments(
+	transaction *wire.MsgTx,
+	sigHashArgs []*inputSigHashArgs,
+) (*taprootSigHashFragments, error) {
+	if len(sigHashArgs) != len(transaction.TxIn) {
+		return nil, fmt.Errorf("wrong sighash args count")
+	}
+
+	var prevOutputs, amounts, publicKeyScripts, sequences, outputs bytes.Buffer
+
+	for i, input := range transaction.TxIn {
+		prevOutputs.Write(input.PreviousOutPoint.Hash[:])
+		err := binary.Write(
+			&prevOutputs,
+			binary.LittleEndian,
+			input.PreviousOutPoint.Index,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		err = binary.Write(&amounts, binary.LittleEndian, sigHashArgs[i].value)
+		if err != nil {
+			return nil, err
+		}
+
+		err = wire.WriteVarBytes(
+			&publicKeyScripts,
+			0,
+			sigHashArgs[i].publicKeyScript,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		err = binary.Write(&sequences, binary.LittleEndian, input.Sequence)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, output := range transaction.TxOut {
+		if err := wire.WriteTxOut(&outputs, 0, 0, output); err != nil {
+			return nil, err
+		}
+	}
+
+	return &taprootSigHashFragments{
+		prevOutputs:      sha256.Sum256(prevOutputs.Bytes()),
+		amounts:          sha256.Sum256(amounts.Bytes()),
+		publicKeyScripts: sha256.Sum256(publicKeyScripts.Bytes()),
+		sequences:        sha256.Sum256(sequences.Bytes()),
+		outputs:          sha256.Sum256(outputs.Bytes()),
+	}, nil
+}
+
+// calcTaprootSigHash computes the BIP-341 signature hash for the key path
+// spend of the input with the given index, using the SIGHASH_DEFAULT sighash
+// type. Annexes are not supported.
+func calcTaprootSigHash(
+	fragments *taprootSigHashFragments,
+	transaction *wire.MsgTx,
+	index int,
+) ([]byte, error) {
+	if index < 0 || index >= len(transaction.TxIn) {
+		return nil, fmt.Errorf("input index out of range")
+	}
+
+	var message bytes.Buffer
+
+	// Sighash epoch.
+	message.WriteByte(0x00)
+	message.WriteByte(taprootSigHashDefault)
+
+	err := binary.Write(&message, binary.LittleEndian, transaction.Version)
+	if err != nil {
+		return nil, err
+	}
+	err = binary.Write(&message, binary.LittleEndian, transaction.LockTime)
+	if err != nil {
+		return nil, err
+	}
+
+	message.Write(fragments.prevOutputs[:])
+	message.Write(fragments.amounts[:])
+	message.Write(fragments.publicKeyScripts[:])
+	message.Write(fragments.sequences[:])
+	message.Write(fragments.outputs[:])
+
+	// Spend type: key path spend (ext_flag = 0) without annex.
+	message.WriteByte(0x00)
+
+	err = binary.Write(&message, binary.LittleEndian, uint32(index))
+	if err != nil {
+		return nil, err
+	}
+
+	sigHash := taggedHash(taprootSigHashTag, message.Bytes())
+
+	return sigHash[:], nil
+}
@@ -0,0 +1,191 @@
+package bitcoin
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+
+	"github.com/keep-network/keep-core/internal/testutils"
+)
+
+func TestTransactionBuilder_ComputeSignatureHashes_Taproot(t *testing.T) {
+	// Key path spend taken from the Bitcoin Core script assets test
+	// vectors. The second input spends a P2TR UTXO using SIGHASH_DEFAULT.
+	// The sighash is verified against the Schnorr signature provided by the
+	// vector so, a wrong sighash makes the verification fail.
+	transaction := transactionFrom(
+		t,
+		"0200000002bcb2054607a921b3c6df992a9486776863b28485e731a805931b6fe"+
+			"b14221acf3901000000294d73f38bd9b9012d1e9d0bc9c34df9d487a1d5663f1b"+
+			"37dbd4a857a2bddcbe25f0d0c4f000000000428d778904fbaeb6000000000017"+
+			"a9148f07d0f98cfe0d6aff29ca20bcda3fa93083937487580200000000000016"+
+			"0014619b982e9f6832d2edb1a1ee4e7656a8d72c65e758020000000000001600"+
+			"14deb4696df95e4685eae8f9ff2e77fc7edabbe2fc5802000000000000160014"+
+			"f19f1969da9e474444a7b8fc50ae71f46e1eb796f7b3ae3c",
+	)
+
+	previousOutputs := []string{
+		"d7b8770000000000225120b5149551dc0241ae0d4420d11e06c98ebd87b9a952c2fc2c5fa7ce9cbc250e4b",
+		"2ac54100000000002251202540f27e90740933c99d4f17ab2dfc6c82951cfb0b8674c83ad179cfbc247b89",
+	}
+
+	signature := hexToSlice(
+		t,
+		"2c4f4c08e82cd2748b627f594356ee1770e152d3ed937afef341d5d1405729e9"+
+			"4dcfb2a411d61060992531f5176fcc33e0ffb407fb249880edbc638e48a7e26c",
+	)
+
+	builder := NewTransactionBuilder(newLocalChain())
+	builder.internal.fromTransaction(transaction)
+
+	for _, previousOutputHex := range previousOutputs {
+		// Previous outputs are serialized as 8-byte little-endian values
+		// followed by variable length locking scripts.
+		previousOutput := hexToSlice(t, previousOutputHex)
+
+		publicKeyScript, err := NewScriptFromVarLenData(previousOutput[8:])
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		builder.sigHashArgs = append(builder.sigHashArgs, &inputSigHashArgs{
+			value:           int64(binary.LittleEndian.Uint64(previousOutput[:8])),
+			publicKeyScript: publicKeyScript,
+			witness:         true,
+			taproot:         true,
+		})
+	}
+
+	sigHashes, err := builder.ComputeSignatureHashes()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertIntsEqual(t, "sighashes count", 2, len(sigHashes))
+
+	outputKey, err := ExtractTaprootOutputKey(
+		builder.sigHashArgs[1].publicKeyScript,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	publicKey, err := schnorr.ParsePubKey(outputKey[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsedSignature, err := schnorr.ParseSignature(signature)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sigHash := make([]byte, 32)
+	sigHashes[1].FillBytes(sigHash)
+
+	if !parsedSignature.Verify(sigHash, publicKey) {
+		t.Errorf("signature is not valid for the computed sighash")
+	}
+}
+
+func TestTransactionBuilder_AddTaprootInput(t *testing.T) {
+	taprootScript := hexToSlice(
+		t,
+		"5120a60869f0dbcf1dc659c9cecbaf8050135ea9e8cdc487053f1dc6880949dc684c",
+	)
+	witnessPublicKeyHashScript := hexToSlice(
+		t,
+		"00148db50eb52063ea9d98b3eac91489a90f738986f6",
+	)
+
+	inputTransaction := &Transaction{
+		Version: 1,
+		Inputs: []*TransactionInput{
+			{
+				Outpoint: &TransactionOutpoint{
+					TransactionHash: Hash{0x01},
+					OutputIndex:     0,
+				},
+				Sequence: 0xffffffff,
+			},
+		},
+		Outputs: []*TransactionOutput{
+			{Value: 10000, PublicKeyScript: taprootScript},
+			{Value: 20000, PublicKeyScript: witnessPublicKeyHashScript},
+		},
+	}
+
+	var tests = map[string]struct {
+		outputIndex uint32
+		expectedErr bool
+	}{
+		"P2TR UTXO": {
+			outputIndex: 0,
+			expectedErr: false,
+		},
+		"P2WPKH UTXO": {
+			outputIndex: 1,
+			expectedErr: true,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			localChain := newLocalChain()
+
+			err := localChain.addTransaction(inputTransaction)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			builder := NewTransactionBuilder(localChain)
+
+			err = builder.AddTaprootInput(&UnspentTransactionOutput{
+				Outpoint: &TransactionOutpoint{
+					TransactionHash: inputTransaction.Hash(),
+					OutputIndex:     test.outputIndex,
+				},
+				Value: inputTransaction.Outputs[test.outputIndex].Value,
+			})
+
+			if test.expectedErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			testutils.AssertIntsEqual(
+				t,
+				"sighash args count",
+				1,
+				len(builder.sigHashArgs),
+			)
+			testutils.AssertBytesEqual(
+				t,
+				taprootScript,
+				builder.sigHashArgs[0].publicKeyScript,
+			)
+			testutils.AssertBoolsEqual(
+				t,
+				"sighash args taproot flag",
+				true,
+				builder.sigHashArgs[0].taproot,
+			)
+
+			if _, err := builder.ComputeSignatureHashes(); err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = builder.AddSignatures([]*SignatureContainer{{}})
+			if err == nil {
+				t.Fatal("expected error while applying ECDSA signature")
+			}
+		})
+	}
+}
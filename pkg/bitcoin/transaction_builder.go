@@ -21,6 +21,7 @@ type TransactionBuilder struct {
 	internal    *internalTransaction
 	sigHashArgs []*inputSigHashArgs
 	sigHashes   []*big.Int
+	parents     []*packageParent
 }
 
 // NewTransactionBuilder constructs a new TransactionBuilder instance.
@@ -37,7 +38,7 @@ func NewTransactionBuilder(chain Chain) *TransactionBuilder {
 func (tb *TransactionBuilder) AddPublicKeyHashInput(
 	utxo *UnspentTransactionOutput,
 ) error {
-	utxoScript, err := tb.getScript(utxo)
+	utxoTransaction, err := tb.getTransaction(utxo)
 	if err != nil {
 		return fmt.Errorf(
 			"cannot get locking script for UTXO pointed "+
@@ -46,6 +47,8 @@ func (tb *TransactionBuilder) AddPublicKeyHashInput(
 		)
 	}
 
+	utxoScript := utxoTransaction.Outputs[utxo.Outpoint.OutputIndex].PublicKeyScript
+
 	class := txscript.GetScriptClass(utxoScript)
 	isPublicKeyHashScript := class == txscript.PubKeyHashTy ||
 		class == txscript.WitnessV0PubKeyHashTy
@@ -62,9 +65,16 @@ func (tb *TransactionBuilder) AddPublicKeyHashInput(
 	// https://github.com/bitcoin/bips/blob/master/bip-0143.mediawiki#specification.
 	// That conversion is handled within the `txscript.CalcWitnessSigHash` call.
 	sigHashArgs := &inputSigHashArgs{
-		value:      utxo.Value,
-		scriptCode: utxoScript,
-		witness:    txscript.IsWitnessProgram(utxoScript),
+		value:           utxo.Value,
+		scriptCode:      utxoScript,
+		publicKeyScript: utxoScript,
+		witness:         txscript.IsWitnessProgram(utxoScript),
+	}
+
+	// Non-witness inputs commit to the whole transaction holding the UTXO
+	// so, it must be kept around in case the transaction is exported as PSBT.
+	if !sigHashArgs.witness {
+		sigHashArgs.previousTransaction = utxoTransaction
 	}
 
 	hash := chainhash.Hash(utxo.Outpoint.TransactionHash)
@@ -86,7 +96,7 @@ func (tb *TransactionBuilder) AddScriptHashInput(
 	utxo *UnspentTransactionOutput,
 	redeemScript Script,
 ) error {
-	utxoScript, err := tb.getScript(utxo)
+	utxoTransaction, err := tb.getTransaction(utxo)
 	if err != nil {
 		return fmt.Errorf(
 			"cannot get locking script for UTXO pointed "+
@@ -95,6 +105,8 @@ func (tb *TransactionBuilder) AddScriptHashInput(
 		)
 	}
 
+	utxoScript := utxoTransaction.Outputs[utxo.Outpoint.OutputIndex].PublicKeyScript
+
 	class := txscript.GetScriptClass(utxoScript)
 	isPublicKeyHashScript := class == txscript.ScriptHashTy ||
 		class == txscript.WitnessV0ScriptHashTy
@@ -108,9 +120,16 @@ func (tb *TransactionBuilder) AddScriptHashInput(
 	// to build the sighash is equivalent to the plain-text redeem script whose
 	// hash is included in the P2SH/P2WSH script.
 	sigHashArgs := &inputSigHashArgs{
-		value:      utxo.Value,
-		scriptCode: redeemScript,
-		witness:    txscript.IsWitnessProgram(utxoScript),
+		value:           utxo.Value,
+		scriptCode:      redeemScript,
+		publicKeyScript: utxoScript,
+		witness:         txscript.IsWitnessProgram(utxoScript),
+	}
+
+	// Non-witness inputs commit to the whole transaction holding the UTXO
+	// so, it must be kept around in case the transaction is exported as PSBT.
+	if !sigHashArgs.witness {
+		sigHashArgs.previousTransaction = utxoTransaction
 	}
 
 	hash := chainhash.Hash(utxo.Outpoint.TransactionHash)
@@ -133,11 +152,65 @@ func (tb *TransactionBuilder) AddScriptHashInput(
 	return nil
 }
 
+// AddTaprootInput adds an unsigned input pointing to a UTXO locked using
+// a P2TR script. The input is supposed to be spent using the key path so,
+// its signature hash is computed according to BIP-341, using the
+// SIGHASH_DEFAULT sighash type. Worth noting that key path spends require
+// BIP-340 Schnorr signatures which cannot be applied using AddSignatures
+// as the latter handles ECDSA signatures only.
+func (tb *TransactionBuilder) AddTaprootInput(
+	utxo *UnspentTransactionOutput,
+) error {
+	utxoScript, err := tb.getScript(utxo)
+	if err != nil {
+		return fmt.Errorf(
+			"cannot get locking script for UTXO pointed "+
+				"by the input: [%v]",
+			err,
+		)
+	}
+
+	if GetScriptType(utxoScript) != P2TRScript {
+		return fmt.Errorf("UTXO pointed by the input is not P2TR")
+	}
+
+	// BIP-341 signature hashes do not use the scriptCode. Instead, they
+	// commit to the locking scripts of all UTXOs spent by the transaction.
+	sigHashArgs := &inputSigHashArgs{
+		value:           utxo.Value,
+		publicKeyScript: utxoScript,
+		witness:         true,
+		taproot:         true,
+	}
+
+	hash := chainhash.Hash(utxo.Outpoint.TransactionHash)
+	outpoint := wire.NewOutPoint(&hash, utxo.Outpoint.OutputIndex)
+
+	tb.internal.AddTxIn(wire.NewTxIn(outpoint, nil, nil))
+
+	tb.sigHashArgs = append(tb.sigHashArgs, sigHashArgs)
+
+	return nil
+}
+
 // getScript gets the locking script (PublicKeyScript) for the given unspent
 // transaction output.
 func (tb *TransactionBuilder) getScript(
 	utxo *UnspentTransactionOutput,
 ) (Script, error) {
+	transaction, err := tb.getTransaction(utxo)
+	if err != nil {
+		return nil, err
+	}
+
+	return transaction.Outputs[utxo.Outpoint.OutputIndex].PublicKeyScript, nil
+}
+
+// getTransaction gets the transaction holding the given unspent transaction
+// output.
+func (tb *TransactionBuilder) getTransaction(
+	utxo *UnspentTransactionOutput,
+) (*Transaction, error) {
 	hash := utxo.Outpoint.TransactionHash
 	transaction, err := tb.chain.GetTransaction(hash)
 	if err != nil {
@@ -148,7 +221,7 @@ func (tb *TransactionBuilder) getScript(
 		)
 	}
 
-	return transaction.Outputs[utxo.Outpoint.OutputIndex].PublicKeyScript, nil
+	return transaction, nil
 }
 
 // AddOutput adds a new transaction's output.
@@ -168,13 +241,37 @@ func (tb *TransactionBuilder) ComputeSignatureHashes() ([]*big.Int, error) {
 	// sighash fragments can be pre-computed upfront and reused.
 	witnessSigHashFragments := txscript.NewTxSigHashes(tb.internal.MsgTx)
 
+	// The same applies to Taproot inputs, though their fragments are
+	// computed differently, according to BIP-341. Compute them lazily as
+	// Taproot inputs are rare.
+	var taprootSigHashFragments *taprootSigHashFragments
+
 	for i := range tb.internal.TxIn {
 		sigHashArgs := tb.sigHashArgs[i]
 
 		var sigHashBytes []byte
 		var err error
 
-		if sigHashArgs.witness {
+		if sigHashArgs.taproot {
+			if taprootSigHashFragments == nil {
+				taprootSigHashFragments, err = newTaprootSigHashFragments(
+					tb.internal.MsgTx,
+					tb.sigHashArgs,
+				)
+				if err != nil {
+					return nil, fmt.Errorf(
+						"cannot compute taproot sighash fragments: [%v]",
+						err,
+					)
+				}
+			}
+
+			sigHashBytes, err = calcTaprootSigHash(
+				taprootSigHashFragments,
+				tb.internal.MsgTx,
+				i,
+			)
+		} else if sigHashArgs.witness {
 			sigHashBytes, err = txscript.CalcWitnessSigHash(
 				sigHashArgs.scriptCode,
 				witnessSigHashFragments,
@@ -236,6 +333,14 @@ func (tb *TransactionBuilder) AddSignatures(
 	for i, input := range tb.internal.TxIn {
 		signature := signatures[i]
 
+		if tb.sigHashArgs[i].taproot {
+			return nil, fmt.Errorf(
+				"input [%v] is a taproot input and cannot be signed "+
+					"using an ECDSA signature",
+				i,
+			)
+		}
+
 		// Make a sanity check to avoid producing crap transactions.
 		if !ecdsa.Verify(
 			signature.PublicKey,
@@ -309,6 +414,133 @@ func (tb *TransactionBuilder) TotalInputsValue() int64 {
 	return totalInputsValue
 }
 
+// AddUnconfirmedParent registers an unconfirmed transaction one of whose
+// outputs is spent by the transaction being built. Registered parents
+// form a package with the transaction being built and are taken into
+// account while computing the package fee rate. This is what makes
+// child-pays-for-parent (CPFP) fee calibration possible. Registering a parent
+// does not add any input to the transaction being built. The fee paid by
+// the parent is determined using the outputs it spends so, those must be
+// available on the Bitcoin chain.
+func (tb *TransactionBuilder) AddUnconfirmedParent(parent *Transaction) error {
+	totalInputsValue := int64(0)
+	for i, input := range parent.Inputs {
+		hash := input.Outpoint.TransactionHash
+		transaction, err := tb.chain.GetTransaction(hash)
+		if err != nil {
+			return fmt.Errorf(
+				"cannot get transaction with hash [%s] spent by "+
+					"parent's input [%v]: [%v]",
+				hash.Hex(ReversedByteOrder),
+				i,
+				err,
+			)
+		}
+
+		if int(input.Outpoint.OutputIndex) >= len(transaction.Outputs) {
+			return fmt.Errorf(
+				"parent's input [%v] points to a non-existing output",
+				i,
+			)
+		}
+
+		totalInputsValue += transaction.Outputs[input.Outpoint.OutputIndex].Value
+	}
+
+	totalOutputsValue := int64(0)
+	for _, output := range parent.Outputs {
+		totalOutputsValue += output.Value
+	}
+
+	fee := totalInputsValue - totalOutputsValue
+	if fee < 0 {
+		return fmt.Errorf("parent's outputs exceed its inputs")
+	}
+
+	tb.parents = append(tb.parents, &packageParent{
+		fee:         fee,
+		virtualSize: parent.VirtualSize(),
+	})
+
+	return nil
+}
+
+// PackageFeeRate returns the fee rate, in satoshi per virtual byte, of the
+// package made of the registered unconfirmed parents and the transaction
+// being built, assuming the latter pays the given fee. As the transaction
+// being built is not signed yet, its final virtual size must be estimated
+// upfront, e.g. using the TransactionSizeEstimator. If no parents were
+// registered, the result is just the fee rate of the transaction being built.
+func (tb *TransactionBuilder) PackageFeeRate(
+	childFee int64,
+	childVirtualSize int64,
+) (float64, error) {
+	if childVirtualSize <= 0 {
+		return 0, fmt.Errorf("child virtual size must be positive")
+	}
+
+	parentsFee, parentsVirtualSize := tb.parentsTotals()
+
+	return float64(parentsFee+childFee) /
+		float64(parentsVirtualSize+childVirtualSize), nil
+}
+
+// PackageFee computes the fee the transaction being built must pay so the
+// package made of the registered unconfirmed parents and the transaction
+// being built reaches the given fee rate, expressed in satoshi per virtual
+// byte. The returned fee is never lower than the fee the transaction being
+// built would have to pay on its own to reach the given fee rate. That means
+// parents paying more than needed do not lower the fee of their child.
+// The virtual size of the transaction being built must be estimated upfront
+// as described in PackageFeeRate.
+func (tb *TransactionBuilder) PackageFee(
+	childVirtualSize int64,
+	satPerVByteFee int64,
+) (int64, error) {
+	if childVirtualSize <= 0 {
+		return 0, fmt.Errorf("child virtual size must be positive")
+	}
+
+	if satPerVByteFee <= 0 {
+		return 0, fmt.Errorf("fee rate must be positive")
+	}
+
+	parentsFee, parentsVirtualSize := tb.parentsTotals()
+
+	packageFee := satPerVByteFee*(parentsVirtualSize+childVirtualSize) -
+		parentsFee
+	childOwnFee := satPerVByteFee * childVirtualSize
+
+	if packageFee < childOwnFee {
+		return childOwnFee, nil
+	}
+
+	return packageFee, nil
+}
+
+// parentsTotals returns the total fee and the total virtual size of the
+// registered unconfirmed parents.
+func (tb *TransactionBuilder) parentsTotals() (int64, int64) {
+	fee := int64(0)
+	virtualSize := int64(0)
+
+	for _, parent := range tb.parents {
+		fee += parent.fee
+		virtualSize += parent.virtualSize
+	}
+
+	return fee, virtualSize
+}
+
+// packageParent is a helper structure holding data of an unconfirmed parent
+// transaction that are required to compute package fee rates.
+type packageParent struct {
+	// fee denotes the fee paid by the parent transaction.
+	fee int64
+	// virtualSize denotes the virtual size of the parent transaction.
+	virtualSize int64
+}
+
 // inputSigHashArgs is a helper structure holding some arguments required to
 // compute a sighash for the given input.
 type inputSigHashArgs struct {
@@ -318,9 +550,20 @@ type inputSigHashArgs struct {
 	// is actually executed while unlocking the given UTXO. The scriptCode
 	// depends on the script type that was used to lock the given UTXO.
 	scriptCode []byte
+	// publicKeyScript is the locking script of the UTXO pointed by the given
+	// input. Taproot sighashes commit to the locking scripts of all UTXOs
+	// spent by the transaction.
+	publicKeyScript []byte
 	// witness denotes whether the given input point's to a UTXO locked using
 	// a witness script.
 	witness bool
+	// taproot denotes whether the given input points to a UTXO locked using
+	// a P2TR script and is supposed to be spent using the key path.
+	taproot bool
+	// previousTransaction is the transaction holding the UTXO pointed by the
+	// given input. It is set only for non-witness inputs as it is required
+	// to export the transaction as PSBT.
+	previousTransaction *Transaction
 }
 
 // internalTransaction is an internal utility representation of the Transaction
@@ -504,3 +504,152 @@ func assertInternalOutput(
 
 	testutils.AssertBytesEqual(t, expected.PublicKeyScript, internalOutput.PkScript)
 }
+
+func TestTransactionBuilder_PackageFee(t *testing.T) {
+	script, err := PayToWitnessPublicKeyHash(
+		[20]byte{0x8d, 0xb5, 0x0e, 0xb5},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fundingTx := &Transaction{
+		Version: 1,
+		Inputs: []*TransactionInput{
+			{
+				Outpoint: &TransactionOutpoint{
+					TransactionHash: Hash{0x01},
+					OutputIndex:     0,
+				},
+				Sequence: 0xffffffff,
+			},
+		},
+		Outputs: []*TransactionOutput{
+			{Value: 100000, PublicKeyScript: script},
+		},
+	}
+
+	// The parent pays a fee of 500 satoshi.
+	parentTx := &Transaction{
+		Version: 1,
+		Inputs: []*TransactionInput{
+			{
+				Outpoint: &TransactionOutpoint{
+					TransactionHash: fundingTx.Hash(),
+					OutputIndex:     0,
+				},
+				Witness: [][]byte{
+					make([]byte, 72),
+					make([]byte, 33),
+				},
+				Sequence: 0xffffffff,
+			},
+		},
+		Outputs: []*TransactionOutput{
+			{Value: 99500, PublicKeyScript: script},
+		},
+	}
+
+	parentFee := int64(500)
+	parentVirtualSize := parentTx.VirtualSize()
+	childVirtualSize := int64(200)
+
+	var tests = map[string]struct {
+		withParent          bool
+		satPerVByteFee      int64
+		expectedFee         int64
+		expectedPackageRate float64
+	}{
+		"no parents": {
+			withParent:          false,
+			satPerVByteFee:      10,
+			expectedFee:         10 * childVirtualSize,
+			expectedPackageRate: 10,
+		},
+		"parent below the target fee rate": {
+			withParent:     true,
+			satPerVByteFee: 10,
+			expectedFee: 10*(parentVirtualSize+childVirtualSize) -
+				parentFee,
+			expectedPackageRate: 10,
+		},
+		"parent above the target fee rate": {
+			withParent:     true,
+			satPerVByteFee: 1,
+			expectedFee:    childVirtualSize,
+			expectedPackageRate: float64(parentFee+childVirtualSize) /
+				float64(parentVirtualSize+childVirtualSize),
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			localChain := newLocalChain()
+
+			err := localChain.addTransaction(fundingTx)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			builder := NewTransactionBuilder(localChain)
+
+			if test.withParent {
+				err = builder.AddUnconfirmedParent(parentTx)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			fee, err := builder.PackageFee(
+				childVirtualSize,
+				test.satPerVByteFee,
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			testutils.AssertIntsEqual(
+				t,
+				"package fee",
+				int(test.expectedFee),
+				int(fee),
+			)
+
+			packageRate, err := builder.PackageFeeRate(fee, childVirtualSize)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if test.expectedPackageRate != packageRate {
+				t.Errorf(
+					"unexpected package fee rate\n"+
+						"expected: [%v]\n"+
+						"actual:   [%v]",
+					test.expectedPackageRate,
+					packageRate,
+				)
+			}
+		})
+	}
+}
+
+func TestTransactionBuilder_AddUnconfirmedParent_UnknownInput(t *testing.T) {
+	builder := NewTransactionBuilder(newLocalChain())
+
+	err := builder.AddUnconfirmedParent(&Transaction{
+		Version: 1,
+		Inputs: []*TransactionInput{
+			{
+				Outpoint: &TransactionOutpoint{
+					TransactionHash: Hash{0x02},
+					OutputIndex:     0,
+				},
+				Sequence: 0xffffffff,
+			},
+		},
+		Outputs: []*TransactionOutput{},
+	})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+}
@@ -181,7 +181,14 @@ func (bdc *BitcoinDifficultyChain) Retarget(headers []*bitcoin.BlockHeader) erro
 // RetargetWithRefund adds a new epoch to the relay by providing a proof of the
 // difficulty before and after the retarget. The cost of calling this function
 // is refunded to the caller.
-func (bdc *BitcoinDifficultyChain) RetargetWithRefund(headers []*bitcoin.BlockHeader) error {
+//
+// The check is run against the estimated cost and reimbursement of the
+// transaction before it is submitted. The check can be nil in which case
+// the transaction is submitted without estimating its profitability.
+func (bdc *BitcoinDifficultyChain) RetargetWithRefund(
+	headers []*bitcoin.BlockHeader,
+	check chain.ReimbursementCheck,
+) error {
 	var serializedHeaders []byte
 	for _, header := range headers {
 		serializedHeader := header.Serialize()
@@ -198,6 +205,10 @@ func (bdc *BitcoinDifficultyChain) RetargetWithRefund(headers []*bitcoin.BlockHe
 		)
 	}
 
+	if err := bdc.checkRetargetProfitability(gasEstimate, check); err != nil {
+		return err
+	}
+
 	// Add 20% to the gas estimate as the transaction tends to fail with the
 	// original gas estimate.
 	gasEstimateWithMargin := float64(gasEstimate) * float64(1.2)
@@ -212,6 +223,84 @@ func (bdc *BitcoinDifficultyChain) RetargetWithRefund(headers []*bitcoin.BlockHe
 	return err
 }
 
+// checkRetargetProfitability estimates the cost and the reimbursement of
+// a retarget submitted via LightRelayMaintainerProxy and runs the given check
+// against the estimate. The check can be nil in which case nothing is
+// estimated.
+func (bdc *BitcoinDifficultyChain) checkRetargetProfitability(
+	gasEstimate uint64,
+	check chain.ReimbursementCheck,
+) error {
+	if check == nil {
+		return nil
+	}
+
+	gasOffset, err := bdc.lightRelayMaintainerProxy.RetargetGasOffset()
+	if err != nil {
+		return fmt.Errorf("cannot get retarget gas offset: [%w]", err)
+	}
+
+	poolAddress, err := bdc.lightRelayMaintainerProxy.ReimbursementPool()
+	if err != nil {
+		return fmt.Errorf("cannot get reimbursement pool address: [%w]", err)
+	}
+
+	maintainers, err := bdc.authorizedMaintainers()
+	if err != nil {
+		return fmt.Errorf("cannot get authorized maintainers: [%w]", err)
+	}
+
+	return checkProfitability(
+		bdc.client,
+		&reimbursementPool{address: poolAddress, client: bdc.client},
+		gasEstimate,
+		gasOffset,
+		countOtherMaintainers(maintainers, bdc.key.Address),
+		check,
+	)
+}
+
+// authorizedMaintainers returns maintainers currently authorized in
+// LightRelayMaintainerProxy. The proxy does not expose the list of authorized
+// maintainers so the list is built from past authorization events.
+func (bdc *BitcoinDifficultyChain) authorizedMaintainers() (
+	[]common.Address,
+	error,
+) {
+	events, err := bdc.lightRelayMaintainerProxy.PastMaintainerAuthorizedEvents(
+		0,
+		nil,
+		nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[common.Address]bool)
+	maintainers := make([]common.Address, 0)
+
+	for _, event := range events {
+		if seen[event.Maintainer] {
+			continue
+		}
+		seen[event.Maintainer] = true
+
+		// The maintainer could have been deauthorized since then.
+		isAuthorized, err := bdc.lightRelayMaintainerProxy.IsAuthorized(
+			event.Maintainer,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if isAuthorized {
+			maintainers = append(maintainers, event.Maintainer)
+		}
+	}
+
+	return maintainers, nil
+}
+
 // CurrentEpoch returns the number of the latest difficulty epoch which is
 // proven to the relay. If the genesis epoch's number is set correctly, and
 // retargets along the way have been legitimate, this equals the height of
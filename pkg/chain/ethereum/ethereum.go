@@ -522,6 +522,21 @@ func wrapClientAddons(
 	return loggingClient
 }
 
+// OperatorSigning returns the signing of the operator account pointed by
+// the config, without connecting to the chain. It allows to use the
+// operator's signing in offline tools.
+func OperatorSigning(config ethereum.Config) (chain.Signing, error) {
+	key, err := decryptKey(config)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to decrypt Ethereum key: [%v]",
+			err,
+		)
+	}
+
+	return newSigner(key), nil
+}
+
 // decryptKey decrypts the chain key pointed by the config.
 func decryptKey(config ethereum.Config) (*keystore.Key, error) {
 	return ethutil.DecryptKeyFile(
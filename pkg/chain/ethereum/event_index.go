@@ -0,0 +1,911 @@
+package ethereum
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/keep-network/keep-common/pkg/chain/ethereum"
+	"github.com/keep-network/keep-common/pkg/persistence"
+
+	"github.com/keep-network/keep-core/pkg/chain"
+	"github.com/keep-network/keep-core/pkg/tbtc"
+)
+
+const (
+	// DefaultEventIndexConfirmationDepth is the default number of blocks
+	// that must be built on top of a block before its events are indexed.
+	DefaultEventIndexConfirmationDepth = 64
+	// DefaultEventIndexIngestInterval is the default interval between two
+	// consecutive ingestions of new events into the event index.
+	DefaultEventIndexIngestInterval = 1 * time.Minute
+	// DefaultEventIndexBlockRange is the default maximum number of blocks
+	// whose events are fetched from the chain with a single query during
+	// the ingestion.
+	DefaultEventIndexBlockRange = 10000
+)
+
+// eventIndexStartBlocks holds default event index start blocks of networks
+// the contracts are deployed on. Each is the deployment block of the
+// WalletRegistry contract which was deployed before the Bridge contract.
+var eventIndexStartBlocks = map[ethereum.Network]uint64{
+	ethereum.Mainnet: 15639521,
+}
+
+// DefaultEventIndexStartBlock returns the default first block whose events
+// are indexed on the given network. Zero is returned if there is no default
+// for the network.
+func DefaultEventIndexStartBlock(network ethereum.Network) uint64 {
+	return eventIndexStartBlocks[network]
+}
+
+// EventIndexConfig holds configurable properties of the event index.
+type EventIndexConfig struct {
+	// Disabled disables the event index. Past events are always fetched
+	// from the chain if the index is disabled.
+	Disabled bool
+	// StartBlock is the first block whose events are indexed. It must not
+	// be later than the deployment of the Bridge and WalletRegistry
+	// contracts. If not set, the default of the network is used. If the
+	// network has no default, the deployment block of the contracts is
+	// determined from the chain, which requires the Ethereum client to
+	// serve the historical state.
+	StartBlock uint64
+	// BlockRange is the maximum number of blocks whose events are fetched
+	// from the chain with a single query during the ingestion. It should
+	// not exceed the range limit of the Ethereum client's logs queries.
+	BlockRange uint64
+	// ConfirmationDepth is the number of blocks that must be built on top
+	// of a block before its events are indexed. Events of more recent blocks
+	// are always fetched from the chain as they can still be reorganized.
+	ConfirmationDepth uint64
+	// IngestInterval is the interval between two consecutive ingestions of
+	// new events into the event index.
+	IngestInterval time.Duration
+}
+
+// eventIndexChain represents the chain the event index fetches events from.
+type eventIndexChain interface {
+	pastDKGStartedEvents(
+		filter *tbtc.DKGStartedEventFilter,
+	) ([]*tbtc.DKGStartedEvent, error)
+	pastDepositRevealedEvents(
+		filter *tbtc.DepositRevealedEventFilter,
+	) ([]*tbtc.DepositRevealedEvent, error)
+	pastRedemptionRequestedEvents(
+		filter *tbtc.RedemptionRequestedEventFilter,
+	) ([]*tbtc.RedemptionRequestedEvent, error)
+	pastNewWalletRegisteredEvents(
+		filter *tbtc.NewWalletRegisteredEventFilter,
+	) ([]*tbtc.NewWalletRegisteredEvent, error)
+	pastMovingFundsCommitmentSubmittedEvents(
+		filter *tbtc.MovingFundsCommitmentSubmittedEventFilter,
+	) ([]*tbtc.MovingFundsCommitmentSubmittedEvent, error)
+	pastMovingFundsCompletedEvents(
+		filter *tbtc.MovingFundsCompletedEventFilter,
+	) ([]*tbtc.MovingFundsCompletedEvent, error)
+}
+
+// eventIndexTopic represents events of a single type kept in the event index.
+type eventIndexTopic interface {
+	// topicName returns the name of the topic used to name persisted files.
+	topicName() string
+	// load restores events of the topic indexed from the given start block
+	// from the given persisted buckets.
+	load(buckets map[uint64][]byte, startBlock uint64) error
+	// ingest fetches events of the topic emitted up to the given confirmed
+	// block in ranges of at most the given number of blocks and adds them
+	// to the index.
+	ingest(ctx context.Context, confirmedBlock uint64, blockRange uint64) error
+}
+
+// eventIndex is a local index of past Bridge and WalletRegistry events.
+// Events are incrementally ingested from the chain up to the block with the
+// configured confirmation depth and persisted. Past events queries are served
+// from the index for the indexed block range and from the chain for the most
+// recent blocks that are not indexed yet.
+type eventIndex struct {
+	config       EventIndexConfig
+	blockCounter interface{ CurrentBlock() (uint64, error) }
+
+	dkgStarted *eventTopic[
+		*tbtc.DKGStartedEvent,
+		tbtc.DKGStartedEventFilter,
+	]
+	depositRevealed *eventTopic[
+		*tbtc.DepositRevealedEvent,
+		tbtc.DepositRevealedEventFilter,
+	]
+	redemptionRequested *eventTopic[
+		*tbtc.RedemptionRequestedEvent,
+		tbtc.RedemptionRequestedEventFilter,
+	]
+	newWalletRegistered *eventTopic[
+		*tbtc.NewWalletRegisteredEvent,
+		tbtc.NewWalletRegisteredEventFilter,
+	]
+	movingFundsCommitmentSubmitted *eventTopic[
+		*tbtc.MovingFundsCommitmentSubmittedEvent,
+		tbtc.MovingFundsCommitmentSubmittedEventFilter,
+	]
+	movingFundsCompleted *eventTopic[
+		*tbtc.MovingFundsCompletedEvent,
+		tbtc.MovingFundsCompletedEventFilter,
+	]
+}
+
+// StartEventIndex loads the event index persisted using the given handle and
+// starts ingesting new events in the background. Once started, past events
+// of the indexed types are served from the index. The ingestion stops when
+// the given context is done.
+func (tc *TbtcChain) StartEventIndex(
+	ctx context.Context,
+	handle persistence.BasicHandle,
+	config EventIndexConfig,
+) error {
+	if config.StartBlock == 0 {
+		startBlock, err := tc.contractsDeploymentBlock(ctx)
+		if err != nil {
+			return fmt.Errorf(
+				"cannot determine contracts deployment block; "+
+					"configure the start block explicitly: [%w]",
+				err,
+			)
+		}
+
+		config.StartBlock = startBlock
+	}
+
+	logger.Infof("indexing events from block [%d]", config.StartBlock)
+
+	index, err := newEventIndex(tc, tc.blockCounter, handle, config)
+	if err != nil {
+		return err
+	}
+
+	tc.eventIndex.Store(index)
+
+	go index.run(ctx)
+
+	return nil
+}
+
+// contractsDeploymentBlock returns the earliest deployment block of
+// contracts emitting events served by the event index.
+func (tc *TbtcChain) contractsDeploymentBlock(
+	ctx context.Context,
+) (uint64, error) {
+	currentBlock, err := tc.blockCounter.CurrentBlock()
+	if err != nil {
+		return 0, fmt.Errorf("cannot get current block: [%w]", err)
+	}
+
+	deploymentBlock := currentBlock
+
+	for _, address := range []common.Address{
+		tc.bridgeAddress,
+		tc.walletRegistryAddress,
+	} {
+		block, err := contractDeploymentBlock(
+			ctx,
+			tc.client,
+			address,
+			currentBlock,
+		)
+		if err != nil {
+			return 0, fmt.Errorf(
+				"cannot get deployment block of contract [%s]: [%w]",
+				address.Hex(),
+				err,
+			)
+		}
+
+		if block < deploymentBlock {
+			deploymentBlock = block
+		}
+	}
+
+	return deploymentBlock, nil
+}
+
+// contractDeploymentBlock returns the first block at which the code of the
+// contract with the given address is present. The block is searched
+// with a binary search up to the given latest block, so the code reader
+// must serve the historical state.
+func contractDeploymentBlock(
+	ctx context.Context,
+	codeReader interface {
+		CodeAt(
+			ctx context.Context,
+			contract common.Address,
+			blockNumber *big.Int,
+		) ([]byte, error)
+	},
+	address common.Address,
+	latestBlock uint64,
+) (uint64, error) {
+	hasCode := func(block uint64) (bool, error) {
+		code, err := codeReader.CodeAt(
+			ctx,
+			address,
+			new(big.Int).SetUint64(block),
+		)
+		if err != nil {
+			return false, fmt.Errorf(
+				"cannot get code at block [%d]: [%w]",
+				block,
+				err,
+			)
+		}
+
+		return len(code) > 0, nil
+	}
+
+	deployed, err := hasCode(latestBlock)
+	if err != nil {
+		return 0, err
+	}
+	if !deployed {
+		return 0, fmt.Errorf("no code at block [%d]", latestBlock)
+	}
+
+	low, high := uint64(0), latestBlock
+	for low < high {
+		middle := low + (high-low)/2
+
+		deployed, err := hasCode(middle)
+		if err != nil {
+			return 0, err
+		}
+
+		if deployed {
+			high = middle
+		} else {
+			low = middle + 1
+		}
+	}
+
+	return low, nil
+}
+
+// newEventIndex creates a new event index fetching events from the given
+// chain and restores its state from the given persistence handle.
+func newEventIndex(
+	source eventIndexChain,
+	blockCounter interface{ CurrentBlock() (uint64, error) },
+	handle persistence.BasicHandle,
+	config EventIndexConfig,
+) (*eventIndex, error) {
+	if config.ConfirmationDepth == 0 {
+		config.ConfirmationDepth = DefaultEventIndexConfirmationDepth
+	}
+	if config.IngestInterval == 0 {
+		config.IngestInterval = DefaultEventIndexIngestInterval
+	}
+	if config.BlockRange == 0 {
+		config.BlockRange = DefaultEventIndexBlockRange
+	}
+
+	storage := &eventStorage{handle: handle}
+
+	index := &eventIndex{
+		config:       config,
+		blockCounter: blockCounter,
+		dkgStarted: &eventTopic[
+			*tbtc.DKGStartedEvent,
+			tbtc.DKGStartedEventFilter,
+		]{
+			name:    "dkg_started",
+			storage: storage,
+			fetchFn: source.pastDKGStartedEvents,
+			rangeFn: func(
+				filter *tbtc.DKGStartedEventFilter,
+			) (uint64, *uint64) {
+				if filter == nil {
+					return 0, nil
+				}
+				return filter.StartBlock, filter.EndBlock
+			},
+			withRangeFn: func(
+				filter *tbtc.DKGStartedEventFilter,
+				startBlock uint64,
+				endBlock *uint64,
+			) *tbtc.DKGStartedEventFilter {
+				ranged := &tbtc.DKGStartedEventFilter{}
+				if filter != nil {
+					*ranged = *filter
+				}
+				ranged.StartBlock = startBlock
+				ranged.EndBlock = endBlock
+				return ranged
+			},
+			matchesFn: func(
+				filter *tbtc.DKGStartedEventFilter,
+				event *tbtc.DKGStartedEvent,
+			) bool {
+				return filter == nil || matchesSeed(filter.Seed, event.Seed)
+			},
+			blockNumberFn: func(event *tbtc.DKGStartedEvent) uint64 {
+				return event.BlockNumber
+			},
+		},
+		depositRevealed: &eventTopic[
+			*tbtc.DepositRevealedEvent,
+			tbtc.DepositRevealedEventFilter,
+		]{
+			name:    "deposit_revealed",
+			storage: storage,
+			fetchFn: source.pastDepositRevealedEvents,
+			rangeFn: func(
+				filter *tbtc.DepositRevealedEventFilter,
+			) (uint64, *uint64) {
+				if filter == nil {
+					return 0, nil
+				}
+				return filter.StartBlock, filter.EndBlock
+			},
+			withRangeFn: func(
+				filter *tbtc.DepositRevealedEventFilter,
+				startBlock uint64,
+				endBlock *uint64,
+			) *tbtc.DepositRevealedEventFilter {
+				ranged := &tbtc.DepositRevealedEventFilter{}
+				if filter != nil {
+					*ranged = *filter
+				}
+				ranged.StartBlock = startBlock
+				ranged.EndBlock = endBlock
+				return ranged
+			},
+			matchesFn: func(
+				filter *tbtc.DepositRevealedEventFilter,
+				event *tbtc.DepositRevealedEvent,
+			) bool {
+				return filter == nil ||
+					(matchesAddress(filter.Depositor, event.Depositor) &&
+						matchesValue(
+							filter.WalletPublicKeyHash,
+							event.WalletPublicKeyHash,
+						))
+			},
+			blockNumberFn: func(event *tbtc.DepositRevealedEvent) uint64 {
+				return event.BlockNumber
+			},
+		},
+		redemptionRequested: &eventTopic[
+			*tbtc.RedemptionRequestedEvent,
+			tbtc.RedemptionRequestedEventFilter,
+		]{
+			name:    "redemption_requested",
+			storage: storage,
+			fetchFn: source.pastRedemptionRequestedEvents,
+			rangeFn: func(
+				filter *tbtc.RedemptionRequestedEventFilter,
+			) (uint64, *uint64) {
+				if filter == nil {
+					return 0, nil
+				}
+				return filter.StartBlock, filter.EndBlock
+			},
+			withRangeFn: func(
+				filter *tbtc.RedemptionRequestedEventFilter,
+				startBlock uint64,
+				endBlock *uint64,
+			) *tbtc.RedemptionRequestedEventFilter {
+				ranged := &tbtc.RedemptionRequestedEventFilter{}
+				if filter != nil {
+					*ranged = *filter
+				}
+				ranged.StartBlock = startBlock
+				ranged.EndBlock = endBlock
+				return ranged
+			},
+			matchesFn: func(
+				filter *tbtc.RedemptionRequestedEventFilter,
+				event *tbtc.RedemptionRequestedEvent,
+			) bool {
+				return filter == nil ||
+					(matchesAddress(filter.Redeemer, event.Redeemer) &&
+						matchesValue(
+							filter.WalletPublicKeyHash,
+							event.WalletPublicKeyHash,
+						))
+			},
+			blockNumberFn: func(event *tbtc.RedemptionRequestedEvent) uint64 {
+				return event.BlockNumber
+			},
+		},
+		newWalletRegistered: &eventTopic[
+			*tbtc.NewWalletRegisteredEvent,
+			tbtc.NewWalletRegisteredEventFilter,
+		]{
+			name:    "new_wallet_registered",
+			storage: storage,
+			fetchFn: source.pastNewWalletRegisteredEvents,
+			rangeFn: func(
+				filter *tbtc.NewWalletRegisteredEventFilter,
+			) (uint64, *uint64) {
+				if filter == nil {
+					return 0, nil
+				}
+				return filter.StartBlock, filter.EndBlock
+			},
+			withRangeFn: func(
+				filter *tbtc.NewWalletRegisteredEventFilter,
+				startBlock uint64,
+				endBlock *uint64,
+			) *tbtc.NewWalletRegisteredEventFilter {
+				ranged := &tbtc.NewWalletRegisteredEventFilter{}
+				if filter != nil {
+					*ranged = *filter
+				}
+				ranged.StartBlock = startBlock
+				ranged.EndBlock = endBlock
+				return ranged
+			},
+			matchesFn: func(
+				filter *tbtc.NewWalletRegisteredEventFilter,
+				event *tbtc.NewWalletRegisteredEvent,
+			) bool {
+				return filter == nil ||
+					(matchesValue(filter.EcdsaWalletID, event.EcdsaWalletID) &&
+						matchesValue(
+							filter.WalletPublicKeyHash,
+							event.WalletPublicKeyHash,
+						))
+			},
+			blockNumberFn: func(event *tbtc.NewWalletRegisteredEvent) uint64 {
+				return event.BlockNumber
+			},
+		},
+		movingFundsCommitmentSubmitted: &eventTopic[
+			*tbtc.MovingFundsCommitmentSubmittedEvent,
+			tbtc.MovingFundsCommitmentSubmittedEventFilter,
+		]{
+			name:    "moving_funds_commitment_submitted",
+			storage: storage,
+			fetchFn: source.pastMovingFundsCommitmentSubmittedEvents,
+			rangeFn: func(
+				filter *tbtc.MovingFundsCommitmentSubmittedEventFilter,
+			) (uint64, *uint64) {
+				if filter == nil {
+					return 0, nil
+				}
+				return filter.StartBlock, filter.EndBlock
+			},
+			withRangeFn: func(
+				filter *tbtc.MovingFundsCommitmentSubmittedEventFilter,
+				startBlock uint64,
+				endBlock *uint64,
+			) *tbtc.MovingFundsCommitmentSubmittedEventFilter {
+				ranged := &tbtc.MovingFundsCommitmentSubmittedEventFilter{}
+				if filter != nil {
+					*ranged = *filter
+				}
+				ranged.StartBlock = startBlock
+				ranged.EndBlock = endBlock
+				return ranged
+			},
+			matchesFn: func(
+				filter *tbtc.MovingFundsCommitmentSubmittedEventFilter,
+				event *tbtc.MovingFundsCommitmentSubmittedEvent,
+			) bool {
+				return filter == nil ||
+					matchesValue(
+						filter.WalletPublicKeyHash,
+						event.WalletPublicKeyHash,
+					)
+			},
+			blockNumberFn: func(
+				event *tbtc.MovingFundsCommitmentSubmittedEvent,
+			) uint64 {
+				return event.BlockNumber
+			},
+		},
+		movingFundsCompleted: &eventTopic[
+			*tbtc.MovingFundsCompletedEvent,
+			tbtc.MovingFundsCompletedEventFilter,
+		]{
+			name:    "moving_funds_completed",
+			storage: storage,
+			fetchFn: source.pastMovingFundsCompletedEvents,
+			rangeFn: func(
+				filter *tbtc.MovingFundsCompletedEventFilter,
+			) (uint64, *uint64) {
+				if filter == nil {
+					return 0, nil
+				}
+				return filter.StartBlock, filter.EndBlock
+			},
+			withRangeFn: func(
+				filter *tbtc.MovingFundsCompletedEventFilter,
+				startBlock uint64,
+				endBlock *uint64,
+			) *tbtc.MovingFundsCompletedEventFilter {
+				ranged := &tbtc.MovingFundsCompletedEventFilter{}
+				if filter != nil {
+					*ranged = *filter
+				}
+				ranged.StartBlock = startBlock
+				ranged.EndBlock = endBlock
+				return ranged
+			},
+			matchesFn: func(
+				filter *tbtc.MovingFundsCompletedEventFilter,
+				event *tbtc.MovingFundsCompletedEvent,
+			) bool {
+				return filter == nil ||
+					matchesValue(
+						filter.WalletPublicKeyHash,
+						event.WalletPublicKeyHash,
+					)
+			},
+			blockNumberFn: func(event *tbtc.MovingFundsCompletedEvent) uint64 {
+				return event.BlockNumber
+			},
+		},
+	}
+
+	buckets, err := storage.readAll()
+	if err != nil {
+		return nil, fmt.Errorf("cannot read persisted events: [%w]", err)
+	}
+
+	for _, topic := range index.topics() {
+		err := topic.load(buckets[topic.topicName()], config.StartBlock)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"cannot load persisted [%s] events: [%w]",
+				topic.topicName(),
+				err,
+			)
+		}
+	}
+
+	return index, nil
+}
+
+// topics returns all topics of the event index.
+func (ei *eventIndex) topics() []eventIndexTopic {
+	return []eventIndexTopic{
+		ei.dkgStarted,
+		ei.depositRevealed,
+		ei.redemptionRequested,
+		ei.newWalletRegistered,
+		ei.movingFundsCommitmentSubmitted,
+		ei.movingFundsCompleted,
+	}
+}
+
+// run ingests new events periodically until the given context is done.
+func (ei *eventIndex) run(ctx context.Context) {
+	ticker := time.NewTicker(ei.config.IngestInterval)
+	defer ticker.Stop()
+
+	for {
+		ei.ingest(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ingest ingests events of all topics emitted up to the latest block with
+// the configured confirmation depth. The ingestion is interrupted when the
+// given context is done.
+func (ei *eventIndex) ingest(ctx context.Context) {
+	currentBlock, err := ei.blockCounter.CurrentBlock()
+	if err != nil {
+		logger.Errorf("cannot get current block for event index: [%v]", err)
+		return
+	}
+
+	if currentBlock < ei.config.ConfirmationDepth {
+		return
+	}
+
+	confirmedBlock := currentBlock - ei.config.ConfirmationDepth
+
+	for _, topic := range ei.topics() {
+		err := topic.ingest(ctx, confirmedBlock, ei.config.BlockRange)
+		if err != nil {
+			logger.Errorf(
+				"cannot ingest [%s] events up to block [%d]: [%v]",
+				topic.topicName(),
+				confirmedBlock,
+				err,
+			)
+		}
+	}
+}
+
+// eventTopic holds indexed events of type E, filtered using filters of
+// type F. Indexed events are ordered by their block numbers and cover the
+// contiguous block range from startBlock to nextBlock - 1. There are no
+// events before startBlock as the contracts were not deployed yet.
+type eventTopic[E any, F any] struct {
+	name    string
+	storage *eventStorage
+
+	// fetchFn fetches events matching the given filter from the chain.
+	fetchFn func(filter *F) ([]E, error)
+	// rangeFn returns the block range of the given filter.
+	rangeFn func(filter *F) (uint64, *uint64)
+	// withRangeFn returns a copy of the given filter with the block range
+	// replaced. The filter can be nil.
+	withRangeFn func(filter *F, startBlock uint64, endBlock *uint64) *F
+	// matchesFn determines whether the given event matches all criteria
+	// of the given filter other than the block range.
+	matchesFn func(filter *F, event E) bool
+	// blockNumberFn returns the block number of the given event.
+	blockNumberFn func(event E) uint64
+
+	mutex      sync.RWMutex
+	startBlock uint64
+	nextBlock  uint64
+	events     []E
+}
+
+func (et *eventTopic[E, F]) topicName() string {
+	return et.name
+}
+
+// query returns events matching the given filter. Events from the indexed
+// block range are served from the index, events from more recent blocks are
+// fetched from the chain.
+func (et *eventTopic[E, F]) query(filter *F) ([]E, error) {
+	startBlock, endBlock := et.rangeFn(filter)
+
+	et.mutex.RLock()
+	nextBlock := et.nextBlock
+	// Events are only ever appended so the snapshot stays valid.
+	indexed := et.events
+	et.mutex.RUnlock()
+
+	if startBlock >= nextBlock {
+		return et.fetchFn(filter)
+	}
+
+	result := make([]E, 0)
+
+	first := sort.Search(len(indexed), func(i int) bool {
+		return et.blockNumberFn(indexed[i]) >= startBlock
+	})
+	for _, event := range indexed[first:] {
+		if endBlock != nil && et.blockNumberFn(event) > *endBlock {
+			break
+		}
+
+		if et.matchesFn(filter, event) {
+			result = append(result, event)
+		}
+	}
+
+	if endBlock != nil && *endBlock < nextBlock {
+		return result, nil
+	}
+
+	recent, err := et.fetchFn(et.withRangeFn(filter, nextBlock, endBlock))
+	if err != nil {
+		return nil, err
+	}
+
+	return append(result, recent...), nil
+}
+
+// ingest fetches events emitted between the next block of the index and
+// the given confirmed block, persists them, and adds them to the index.
+// Events are fetched in ranges of at most the given number of blocks and
+// each range is persisted once fetched, so the progress is kept if the
+// ingestion fails or the given context is done in the meantime.
+func (et *eventTopic[E, F]) ingest(
+	ctx context.Context,
+	confirmedBlock uint64,
+	blockRange uint64,
+) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		default:
+		}
+
+		et.mutex.RLock()
+		fromBlock := et.nextBlock
+		et.mutex.RUnlock()
+
+		if confirmedBlock < fromBlock {
+			return nil
+		}
+
+		toBlock := confirmedBlock
+		if toBlock-fromBlock >= blockRange {
+			toBlock = fromBlock + blockRange - 1
+		}
+
+		if err := et.ingestRange(fromBlock, toBlock); err != nil {
+			return err
+		}
+	}
+}
+
+// ingestRange fetches events emitted between the given blocks, persists them,
+// and adds them to the index. The given from block must be the next block of
+// the index.
+func (et *eventTopic[E, F]) ingestRange(fromBlock uint64, toBlock uint64) error {
+	et.mutex.RLock()
+	startBlock := et.startBlock
+	indexed := et.events
+	et.mutex.RUnlock()
+
+	fetched, err := et.fetchFn(et.withRangeFn(nil, fromBlock, &toBlock))
+	if err != nil {
+		return fmt.Errorf(
+			"cannot fetch events from block [%d] to block [%d]: [%w]",
+			fromBlock,
+			toBlock,
+			err,
+		)
+	}
+
+	events := append(indexed, fetched...)
+
+	// Persist all buckets affected by the ingested block range before
+	// updating the in-memory state so both stay consistent on failure.
+	for bucket := fromBlock / eventBucketBlocks; bucket <= toBlock/eventBucketBlocks; bucket++ {
+		bucketStartBlock := bucket * eventBucketBlocks
+		bucketEndBlock := bucketStartBlock + eventBucketBlocks - 1
+		if bucketEndBlock > toBlock {
+			bucketEndBlock = toBlock
+		}
+
+		first := sort.Search(len(events), func(i int) bool {
+			return et.blockNumberFn(events[i]) >= bucketStartBlock
+		})
+		last := sort.Search(len(events), func(i int) bool {
+			return et.blockNumberFn(events[i]) > bucketEndBlock
+		})
+
+		err := saveEventBucket(
+			et.storage,
+			et.name,
+			bucket,
+			&eventBucket[E]{
+				StartBlock: max(bucketStartBlock, startBlock),
+				EndBlock:   bucketEndBlock,
+				Events:     events[first:last],
+			},
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	et.mutex.Lock()
+	et.events = events
+	et.nextBlock = toBlock + 1
+	et.mutex.Unlock()
+
+	return nil
+}
+
+// load restores events indexed from the given start block from the given
+// persisted buckets. Buckets must cover a contiguous block range from the
+// start block. Buckets following a gap are ignored and their block range is
+// ingested again.
+func (et *eventTopic[E, F]) load(
+	buckets map[uint64][]byte,
+	startBlock uint64,
+) error {
+	events := make([]E, 0)
+	nextBlock := startBlock
+
+	for bucket := startBlock / eventBucketBlocks; ; bucket++ {
+		content, ok := buckets[bucket]
+		if !ok {
+			break
+		}
+
+		decoded, err := decodeEventBucket[E](content)
+		if err != nil {
+			return fmt.Errorf("cannot decode bucket [%d]: [%w]", bucket, err)
+		}
+
+		bucketStartBlock := bucket * eventBucketBlocks
+		bucketEndBlock := bucketStartBlock + eventBucketBlocks - 1
+
+		if decoded.EndBlock < bucketStartBlock ||
+			decoded.EndBlock > bucketEndBlock {
+			return fmt.Errorf(
+				"end block [%d] does not belong to bucket [%d]",
+				decoded.EndBlock,
+				bucket,
+			)
+		}
+
+		// The bucket does not continue the loaded block range, for example
+		// because it was ingested with a later start block.
+		if decoded.StartBlock > nextBlock || decoded.EndBlock < nextBlock {
+			break
+		}
+
+		events = append(events, decoded.Events...)
+		nextBlock = decoded.EndBlock + 1
+
+		// A partially filled bucket is the last one ingested.
+		if decoded.EndBlock != bucketEndBlock {
+			break
+		}
+	}
+
+	logger.Infof(
+		"loaded [%d] indexed [%s] events up to block [%d]",
+		len(events),
+		et.name,
+		nextBlock,
+	)
+
+	et.mutex.Lock()
+	et.startBlock = startBlock
+	et.events = events
+	et.nextBlock = nextBlock
+	et.mutex.Unlock()
+
+	return nil
+}
+
+// matchesValue returns true if the given list of values is empty or contains
+// the given value.
+func matchesValue[T comparable](values []T, value T) bool {
+	if len(values) == 0 {
+		return true
+	}
+
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchesAddress returns true if the given list of addresses is empty or
+// contains the given address. Addresses are compared regardless of their
+// checksum encoding.
+func matchesAddress(addresses []chain.Address, address chain.Address) bool {
+	if len(addresses) == 0 {
+		return true
+	}
+
+	for _, a := range addresses {
+		if common.HexToAddress(a.String()) == common.HexToAddress(address.String()) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchesSeed returns true if the given list of seeds is empty or contains
+// the given seed.
+func matchesSeed(seeds []*big.Int, seed *big.Int) bool {
+	if len(seeds) == 0 {
+		return true
+	}
+
+	for _, s := range seeds {
+		if s.Cmp(seed) == 0 {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,161 @@
+package ethereum
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/keep-network/keep-common/pkg/persistence"
+)
+
+const (
+	// eventIndexDirName is the name of the persistence directory holding
+	// indexed events.
+	eventIndexDirName = "events"
+	// eventBucketBlocks is the number of blocks whose events are persisted
+	// in a single bucket file. Events are grouped into buckets to keep the
+	// number of files low and the size of rewritten files small.
+	eventBucketBlocks = 100000
+)
+
+// eventBucket is a persisted bucket of indexed events of a single topic.
+type eventBucket[E any] struct {
+	// StartBlock is the first block of the bucket whose events were ingested.
+	StartBlock uint64 `json:"start_block"`
+	// EndBlock is the last block of the bucket whose events were ingested.
+	EndBlock uint64 `json:"end_block"`
+	Events   []E    `json:"events"`
+}
+
+// decodeEventBucket decodes a persisted bucket of events of type E.
+func decodeEventBucket[E any](content []byte) (*eventBucket[E], error) {
+	bucket := &eventBucket[E]{}
+	if err := json.Unmarshal(content, bucket); err != nil {
+		return nil, err
+	}
+
+	return bucket, nil
+}
+
+// eventStorage persists indexed events using the given persistence handle.
+// The handle can be nil in which case events are not persisted.
+type eventStorage struct {
+	handle persistence.BasicHandle
+}
+
+// saveEventBucket persists the given bucket of events of the given topic
+// using the given storage, replacing the previously persisted bucket.
+func saveEventBucket[E any](
+	es *eventStorage,
+	topic string,
+	bucketIndex uint64,
+	bucket *eventBucket[E],
+) error {
+	if es.handle == nil {
+		return nil
+	}
+
+	data, err := json.Marshal(bucket)
+	if err != nil {
+		return fmt.Errorf(
+			"cannot encode bucket [%d] of [%s] events: [%w]",
+			bucketIndex,
+			topic,
+			err,
+		)
+	}
+
+	fileName := fmt.Sprintf("%s_%d", topic, bucketIndex)
+
+	if err := es.handle.Save(data, eventIndexDirName, fileName); err != nil {
+		return fmt.Errorf(
+			"cannot save bucket [%d] of [%s] events: [%w]",
+			bucketIndex,
+			topic,
+			err,
+		)
+	}
+
+	return nil
+}
+
+// readAll reads all persisted buckets, grouped by topic and bucket index.
+func (es *eventStorage) readAll() (map[string]map[uint64][]byte, error) {
+	buckets := make(map[string]map[uint64][]byte)
+
+	if es.handle == nil {
+		return buckets, nil
+	}
+
+	descriptorsChan, errorsChan := es.handle.ReadAll()
+
+	var errs []error
+
+	// Channels are not buffered so descriptors and errors must be read
+	// concurrently.
+	var wg sync.WaitGroup
+	wg.Add(2)
+
+	go func() {
+		defer wg.Done()
+
+		for descriptor := range descriptorsChan {
+			if descriptor.Directory() != eventIndexDirName {
+				continue
+			}
+
+			separator := strings.LastIndex(descriptor.Name(), "_")
+			if separator < 0 {
+				continue
+			}
+
+			topic := descriptor.Name()[:separator]
+			bucketIndex, err := strconv.ParseUint(
+				descriptor.Name()[separator+1:],
+				10,
+				64,
+			)
+			if err != nil {
+				errs = append(errs, fmt.Errorf(
+					"invalid bucket file name [%s]: [%w]",
+					descriptor.Name(),
+					err,
+				))
+				continue
+			}
+
+			content, err := descriptor.Content()
+			if err != nil {
+				errs = append(errs, fmt.Errorf(
+					"cannot read file [%s]: [%w]",
+					descriptor.Name(),
+					err,
+				))
+				continue
+			}
+
+			if _, ok := buckets[topic]; !ok {
+				buckets[topic] = make(map[uint64][]byte)
+			}
+			buckets[topic][bucketIndex] = content
+		}
+	}()
+
+	go func() {
+		defer wg.Done()
+
+		for err := range errorsChan {
+			logger.Errorf("cannot read persisted events: [%v]", err)
+		}
+	}()
+
+	wg.Wait()
+
+	if len(errs) > 0 {
+		return nil, errs[0]
+	}
+
+	return buckets, nil
+}
@@ -0,0 +1,622 @@
+package ethereum
+
+import (
+	"context"
+	"math/big"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/keep-network/keep-common/pkg/persistence"
+
+	"github.com/keep-network/keep-core/internal/testutils"
+	"github.com/keep-network/keep-core/pkg/chain"
+	"github.com/keep-network/keep-core/pkg/tbtc"
+)
+
+func TestEventIndex_DepositRevealedQuery(t *testing.T) {
+	depositor1 := chain.Address("0x7966C178f466B060aAeb2B91e9149A5FB2Ec9c53")
+	depositor2 := chain.Address("0x2219eC7Bf2a1D8Bd0C1e4D0F9ae7bBF7A1c0D8F3")
+
+	source := &eventIndexLocalChain{
+		depositRevealedEvents: []*tbtc.DepositRevealedEvent{
+			{Depositor: depositor1, BlockNumber: 100},
+			{Depositor: depositor2, BlockNumber: 200},
+			{Depositor: depositor1, BlockNumber: 300},
+			// Events below are not confirmed at the moment of ingestion.
+			{Depositor: depositor2, BlockNumber: 950},
+			{Depositor: depositor1, BlockNumber: 990},
+		},
+	}
+
+	index, err := newEventIndex(
+		source,
+		&eventIndexBlockCounter{currentBlock: 1000},
+		nil,
+		EventIndexConfig{ConfirmationDepth: 100},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	index.ingest(context.Background())
+
+	endBlock := func(block uint64) *uint64 { return &block }
+
+	var tests = map[string]struct {
+		filter                 *tbtc.DepositRevealedEventFilter
+		expectedBlocks         []uint64
+		expectedChainFromBlock []uint64
+	}{
+		"no filter": {
+			filter:                 nil,
+			expectedBlocks:         []uint64{100, 200, 300, 950, 990},
+			expectedChainFromBlock: []uint64{901},
+		},
+		"indexed range": {
+			filter: &tbtc.DepositRevealedEventFilter{
+				StartBlock: 150,
+				EndBlock:   endBlock(300),
+			},
+			expectedBlocks:         []uint64{200, 300},
+			expectedChainFromBlock: []uint64{},
+		},
+		"range spanning recent blocks": {
+			filter: &tbtc.DepositRevealedEventFilter{
+				StartBlock: 250,
+			},
+			expectedBlocks:         []uint64{300, 950, 990},
+			expectedChainFromBlock: []uint64{901},
+		},
+		"recent range": {
+			filter: &tbtc.DepositRevealedEventFilter{
+				StartBlock: 960,
+			},
+			expectedBlocks:         []uint64{990},
+			expectedChainFromBlock: []uint64{960},
+		},
+		"depositor filter": {
+			filter: &tbtc.DepositRevealedEventFilter{
+				Depositor: []chain.Address{
+					chain.Address(strings.ToLower(depositor1.String())),
+				},
+			},
+			expectedBlocks:         []uint64{100, 300, 990},
+			expectedChainFromBlock: []uint64{901},
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			source.resetCalls()
+
+			events, err := index.depositRevealed.query(test.filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			blocks := make([]uint64, len(events))
+			for i, event := range events {
+				blocks[i] = event.BlockNumber
+			}
+
+			if !reflect.DeepEqual(test.expectedBlocks, blocks) {
+				t.Errorf(
+					"unexpected events blocks\n"+
+						"expected: [%v]\n"+
+						"actual:   [%v]",
+					test.expectedBlocks,
+					blocks,
+				)
+			}
+
+			if !reflect.DeepEqual(
+				test.expectedChainFromBlock,
+				source.depositRevealedCalls,
+			) {
+				t.Errorf(
+					"unexpected chain queries\n"+
+						"expected: [%v]\n"+
+						"actual:   [%v]",
+					test.expectedChainFromBlock,
+					source.depositRevealedCalls,
+				)
+			}
+		})
+	}
+}
+
+func TestEventIndex_Persistence(t *testing.T) {
+	handle := newEventIndexPersistenceHandle()
+
+	source := &eventIndexLocalChain{
+		depositRevealedEvents: []*tbtc.DepositRevealedEvent{
+			{FundingOutputIndex: 1, BlockNumber: 10},
+			{FundingOutputIndex: 2, BlockNumber: eventBucketBlocks + 10},
+			{FundingOutputIndex: 3, BlockNumber: 2*eventBucketBlocks + 10},
+		},
+	}
+
+	blockCounter := &eventIndexBlockCounter{currentBlock: eventBucketBlocks + 100}
+
+	index, err := newEventIndex(
+		source,
+		blockCounter,
+		handle,
+		EventIndexConfig{ConfirmationDepth: 10},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	index.ingest(context.Background())
+
+	blockCounter.currentBlock = 2*eventBucketBlocks + 100
+	index.ingest(context.Background())
+
+	testutils.AssertIntsEqual(
+		t,
+		"persisted deposit revealed buckets",
+		3,
+		len(handle.filesWithPrefix(eventIndexDirName+"/deposit_revealed_")),
+	)
+
+	restored, err := newEventIndex(
+		source,
+		blockCounter,
+		handle,
+		EventIndexConfig{ConfirmationDepth: 10},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertUintsEqual(
+		t,
+		"next block",
+		2*eventBucketBlocks+91,
+		restored.depositRevealed.nextBlock,
+	)
+
+	if !reflect.DeepEqual(
+		index.depositRevealed.events,
+		restored.depositRevealed.events,
+	) {
+		t.Errorf(
+			"unexpected restored events\n"+
+				"expected: [%v]\n"+
+				"actual:   [%v]",
+			index.depositRevealed.events,
+			restored.depositRevealed.events,
+		)
+	}
+}
+
+func TestEventIndex_PersistenceGap(t *testing.T) {
+	handle := newEventIndexPersistenceHandle()
+
+	source := &eventIndexLocalChain{}
+	blockCounter := &eventIndexBlockCounter{currentBlock: 3 * eventBucketBlocks}
+
+	index, err := newEventIndex(
+		source,
+		blockCounter,
+		handle,
+		EventIndexConfig{ConfirmationDepth: 10},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	index.ingest(context.Background())
+
+	err = handle.Delete(eventIndexDirName, "deposit_revealed_1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	restored, err := newEventIndex(
+		source,
+		blockCounter,
+		handle,
+		EventIndexConfig{ConfirmationDepth: 10},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertUintsEqual(
+		t,
+		"deposit revealed next block",
+		eventBucketBlocks,
+		restored.depositRevealed.nextBlock,
+	)
+	testutils.AssertUintsEqual(
+		t,
+		"redemption requested next block",
+		3*eventBucketBlocks-9,
+		restored.redemptionRequested.nextBlock,
+	)
+}
+
+func TestEventIndex_BoundedIngestion(t *testing.T) {
+	source := &eventIndexLocalChain{
+		depositRevealedEvents: []*tbtc.DepositRevealedEvent{
+			{FundingOutputIndex: 1, BlockNumber: 1050},
+			{FundingOutputIndex: 2, BlockNumber: 1250},
+		},
+	}
+	source.resetCalls()
+
+	index, err := newEventIndex(
+		source,
+		&eventIndexBlockCounter{currentBlock: 1360},
+		nil,
+		EventIndexConfig{
+			ConfirmationDepth: 10,
+			StartBlock:        1000,
+			BlockRange:        100,
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	index.ingest(context.Background())
+
+	expectedChainFromBlock := []uint64{1000, 1100, 1200, 1300}
+	if !reflect.DeepEqual(expectedChainFromBlock, source.depositRevealedCalls) {
+		t.Errorf(
+			"unexpected chain queries\n"+
+				"expected: [%v]\n"+
+				"actual:   [%v]",
+			expectedChainFromBlock,
+			source.depositRevealedCalls,
+		)
+	}
+
+	testutils.AssertUintsEqual(
+		t,
+		"next block",
+		1351,
+		index.depositRevealed.nextBlock,
+	)
+	testutils.AssertIntsEqual(
+		t,
+		"indexed events",
+		2,
+		len(index.depositRevealed.events),
+	)
+
+	// There are no events before the start block so a query for them must
+	// not hit the chain.
+	source.resetCalls()
+
+	endBlock := uint64(1300)
+	events, err := index.depositRevealed.query(
+		&tbtc.DepositRevealedEventFilter{EndBlock: &endBlock},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertIntsEqual(t, "queried events", 2, len(events))
+	testutils.AssertIntsEqual(
+		t,
+		"chain queries",
+		0,
+		len(source.depositRevealedCalls),
+	)
+}
+
+func TestEventIndex_PersistenceStartBlockChanged(t *testing.T) {
+	handle := newEventIndexPersistenceHandle()
+
+	source := &eventIndexLocalChain{}
+	blockCounter := &eventIndexBlockCounter{currentBlock: 2 * eventBucketBlocks}
+
+	index, err := newEventIndex(
+		source,
+		blockCounter,
+		handle,
+		EventIndexConfig{
+			ConfirmationDepth: 10,
+			StartBlock:        eventBucketBlocks + 500,
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	index.ingest(context.Background())
+
+	var tests = map[string]struct {
+		startBlock        uint64
+		expectedNextBlock uint64
+	}{
+		"same start block": {
+			startBlock:        eventBucketBlocks + 500,
+			expectedNextBlock: 2*eventBucketBlocks - 9,
+		},
+		"later start block": {
+			startBlock:        eventBucketBlocks + 600,
+			expectedNextBlock: 2*eventBucketBlocks - 9,
+		},
+		"earlier start block in the same bucket": {
+			startBlock:        eventBucketBlocks + 400,
+			expectedNextBlock: eventBucketBlocks + 400,
+		},
+		"earlier start block in a previous bucket": {
+			startBlock:        10,
+			expectedNextBlock: 10,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			restored, err := newEventIndex(
+				source,
+				blockCounter,
+				handle,
+				EventIndexConfig{
+					ConfirmationDepth: 10,
+					StartBlock:        test.startBlock,
+				},
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			testutils.AssertUintsEqual(
+				t,
+				"next block",
+				test.expectedNextBlock,
+				restored.depositRevealed.nextBlock,
+			)
+		})
+	}
+}
+
+func TestContractDeploymentBlock(t *testing.T) {
+	address := common.HexToAddress("0x5e4861a80B55f035D899f66772117F00FA0E8e7B")
+
+	var tests = map[string]struct {
+		deploymentBlock uint64
+		latestBlock     uint64
+		expectedError   bool
+	}{
+		"deployed at the latest block": {
+			deploymentBlock: 20000,
+			latestBlock:     20000,
+		},
+		"deployed in the past": {
+			deploymentBlock: 12345,
+			latestBlock:     20000,
+		},
+		"deployed at the genesis block": {
+			deploymentBlock: 0,
+			latestBlock:     20000,
+		},
+		"not deployed": {
+			deploymentBlock: 20001,
+			latestBlock:     20000,
+			expectedError:   true,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			codeReader := &eventIndexCodeReader{
+				address:         address,
+				deploymentBlock: test.deploymentBlock,
+			}
+
+			block, err := contractDeploymentBlock(
+				context.Background(),
+				codeReader,
+				address,
+				test.latestBlock,
+			)
+
+			if test.expectedError {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			testutils.AssertUintsEqual(
+				t,
+				"deployment block",
+				test.deploymentBlock,
+				block,
+			)
+		})
+	}
+}
+
+// eventIndexCodeReader serves the contract code at the given address from
+// the given deployment block on.
+type eventIndexCodeReader struct {
+	address         common.Address
+	deploymentBlock uint64
+}
+
+func (eicr *eventIndexCodeReader) CodeAt(
+	ctx context.Context,
+	contract common.Address,
+	blockNumber *big.Int,
+) ([]byte, error) {
+	if contract != eicr.address ||
+		blockNumber.Uint64() < eicr.deploymentBlock {
+		return []byte{}, nil
+	}
+
+	return []byte{0x60, 0x80}, nil
+}
+
+type eventIndexBlockCounter struct {
+	currentBlock uint64
+}
+
+func (eibc *eventIndexBlockCounter) CurrentBlock() (uint64, error) {
+	return eibc.currentBlock, nil
+}
+
+// eventIndexLocalChain is a chain serving deposit revealed events from
+// memory. Other events are always empty.
+type eventIndexLocalChain struct {
+	depositRevealedEvents []*tbtc.DepositRevealedEvent
+	// depositRevealedCalls holds start blocks of deposit revealed queries.
+	depositRevealedCalls []uint64
+}
+
+func (eilc *eventIndexLocalChain) resetCalls() {
+	eilc.depositRevealedCalls = make([]uint64, 0)
+}
+
+func (eilc *eventIndexLocalChain) pastDKGStartedEvents(
+	filter *tbtc.DKGStartedEventFilter,
+) ([]*tbtc.DKGStartedEvent, error) {
+	return []*tbtc.DKGStartedEvent{}, nil
+}
+
+func (eilc *eventIndexLocalChain) pastDepositRevealedEvents(
+	filter *tbtc.DepositRevealedEventFilter,
+) ([]*tbtc.DepositRevealedEvent, error) {
+	eilc.depositRevealedCalls = append(
+		eilc.depositRevealedCalls,
+		filter.StartBlock,
+	)
+
+	events := make([]*tbtc.DepositRevealedEvent, 0)
+	for _, event := range eilc.depositRevealedEvents {
+		if event.BlockNumber < filter.StartBlock ||
+			(filter.EndBlock != nil && event.BlockNumber > *filter.EndBlock) {
+			continue
+		}
+
+		if !matchesAddress(filter.Depositor, event.Depositor) {
+			continue
+		}
+
+		events = append(events, event)
+	}
+
+	return events, nil
+}
+
+func (eilc *eventIndexLocalChain) pastRedemptionRequestedEvents(
+	filter *tbtc.RedemptionRequestedEventFilter,
+) ([]*tbtc.RedemptionRequestedEvent, error) {
+	return []*tbtc.RedemptionRequestedEvent{}, nil
+}
+
+func (eilc *eventIndexLocalChain) pastNewWalletRegisteredEvents(
+	filter *tbtc.NewWalletRegisteredEventFilter,
+) ([]*tbtc.NewWalletRegisteredEvent, error) {
+	return []*tbtc.NewWalletRegisteredEvent{}, nil
+}
+
+func (eilc *eventIndexLocalChain) pastMovingFundsCommitmentSubmittedEvents(
+	filter *tbtc.MovingFundsCommitmentSubmittedEventFilter,
+) ([]*tbtc.MovingFundsCommitmentSubmittedEvent, error) {
+	return []*tbtc.MovingFundsCommitmentSubmittedEvent{}, nil
+}
+
+func (eilc *eventIndexLocalChain) pastMovingFundsCompletedEvents(
+	filter *tbtc.MovingFundsCompletedEventFilter,
+) ([]*tbtc.MovingFundsCompletedEvent, error) {
+	return []*tbtc.MovingFundsCompletedEvent{}, nil
+}
+
+type eventIndexPersistenceHandle struct {
+	mutex sync.Mutex
+	files map[string][]byte
+}
+
+func newEventIndexPersistenceHandle() *eventIndexPersistenceHandle {
+	return &eventIndexPersistenceHandle{files: make(map[string][]byte)}
+}
+
+func (eiph *eventIndexPersistenceHandle) Save(
+	data []byte,
+	directory string,
+	name string,
+) error {
+	eiph.mutex.Lock()
+	defer eiph.mutex.Unlock()
+
+	eiph.files[directory+"/"+name] = append([]byte{}, data...)
+	return nil
+}
+
+func (eiph *eventIndexPersistenceHandle) Delete(
+	directory string,
+	name string,
+) error {
+	eiph.mutex.Lock()
+	defer eiph.mutex.Unlock()
+
+	delete(eiph.files, directory+"/"+name)
+	return nil
+}
+
+func (eiph *eventIndexPersistenceHandle) ReadAll() (
+	<-chan persistence.DataDescriptor,
+	<-chan error,
+) {
+	eiph.mutex.Lock()
+	defer eiph.mutex.Unlock()
+
+	dataChan := make(chan persistence.DataDescriptor, len(eiph.files))
+	errorChan := make(chan error)
+
+	for path, content := range eiph.files {
+		directory, name, _ := strings.Cut(path, "/")
+		dataChan <- &eventIndexDataDescriptor{directory, name, content}
+	}
+
+	close(dataChan)
+	close(errorChan)
+
+	return dataChan, errorChan
+}
+
+func (eiph *eventIndexPersistenceHandle) filesWithPrefix(prefix string) []string {
+	eiph.mutex.Lock()
+	defer eiph.mutex.Unlock()
+
+	paths := make([]string, 0)
+	for path := range eiph.files {
+		if strings.HasPrefix(path, prefix) {
+			paths = append(paths, path)
+		}
+	}
+
+	return paths
+}
+
+type eventIndexDataDescriptor struct {
+	directory string
+	name      string
+	content   []byte
+}
+
+func (eidd *eventIndexDataDescriptor) Name() string {
+	return eidd.name
+}
+
+func (eidd *eventIndexDataDescriptor) Directory() string {
+	return eidd.directory
+}
+
+func (eidd *eventIndexDataDescriptor) Content() ([]byte, error) {
+	return eidd.content, nil
+}
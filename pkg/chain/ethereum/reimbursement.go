@@ -0,0 +1,128 @@
+package ethereum
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	goethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/keep-network/keep-common/pkg/chain/ethereum/ethutil"
+	"github.com/keep-network/keep-core/pkg/chain"
+)
+
+// reimbursementPool is a read-only handle to the ReimbursementPool contract
+// refunding transactions submitted via maintainer proxies. Bindings of the
+// contract are not generated so the handle calls the contract's public
+// getters directly.
+type reimbursementPool struct {
+	address common.Address
+	client  ethutil.EthereumClient
+}
+
+// maxGasPrice returns the maximum gas price reimbursed by the pool.
+func (rp *reimbursementPool) maxGasPrice() (*big.Int, error) {
+	return rp.callUint256("maxGasPrice()")
+}
+
+// staticGas returns the static amount of gas the pool adds to the gas spent
+// by every reimbursed transaction.
+func (rp *reimbursementPool) staticGas() (*big.Int, error) {
+	return rp.callUint256("staticGas()")
+}
+
+func (rp *reimbursementPool) callUint256(signature string) (*big.Int, error) {
+	result, err := rp.client.CallContract(
+		context.Background(),
+		goethereum.CallMsg{
+			To:   &rp.address,
+			Data: crypto.Keccak256([]byte(signature))[:4],
+		},
+		nil,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("cannot call [%s]: [%w]", signature, err)
+	}
+
+	if len(result) != 32 {
+		return nil, fmt.Errorf(
+			"unexpected result length [%d] of [%s]",
+			len(result),
+			signature,
+		)
+	}
+
+	return new(big.Int).SetBytes(result), nil
+}
+
+// checkProfitability estimates the cost and the reimbursement of a
+// transaction submitted via a maintainer proxy and runs the given check
+// against the estimate. The check can be nil in which case nothing is
+// estimated. The reimbursement pool refunds the gas spent by the proxied call,
+// increased by the proxy's gas offset and the pool's static gas, at the
+// transaction gas price capped by the pool's maximum gas price.
+func checkProfitability(
+	client ethutil.EthereumClient,
+	pool *reimbursementPool,
+	gasEstimate uint64,
+	gasOffset *big.Int,
+	otherMaintainers int,
+	check chain.ReimbursementCheck,
+) error {
+	if check == nil {
+		return nil
+	}
+
+	gasPrice, err := client.SuggestGasPrice(context.Background())
+	if err != nil {
+		return fmt.Errorf("cannot get gas price: [%w]", err)
+	}
+
+	maxGasPrice, err := pool.maxGasPrice()
+	if err != nil {
+		return fmt.Errorf("cannot get reimbursed max gas price: [%w]", err)
+	}
+
+	staticGas, err := pool.staticGas()
+	if err != nil {
+		return fmt.Errorf("cannot get reimbursed static gas: [%w]", err)
+	}
+
+	reimbursedGasPrice := gasPrice
+	if maxGasPrice.Cmp(gasPrice) < 0 {
+		reimbursedGasPrice = maxGasPrice
+	}
+
+	reimbursedGas := new(big.Int).SetUint64(gasEstimate)
+	reimbursedGas.Add(reimbursedGas, gasOffset)
+	reimbursedGas.Add(reimbursedGas, staticGas)
+
+	return check(&chain.ReimbursementEstimate{
+		GasPrice:    gasPrice,
+		MaxGasPrice: maxGasPrice,
+		Cost: new(big.Int).Mul(
+			new(big.Int).SetUint64(gasEstimate),
+			gasPrice,
+		),
+		Reimbursement:    new(big.Int).Mul(reimbursedGas, reimbursedGasPrice),
+		OtherMaintainers: otherMaintainers,
+	})
+}
+
+// countOtherMaintainers returns the number of the given maintainers other
+// than the given one.
+func countOtherMaintainers(
+	maintainers []common.Address,
+	self common.Address,
+) int {
+	count := 0
+	for _, maintainer := range maintainers {
+		if maintainer != self {
+			count++
+		}
+	}
+
+	return count
+}
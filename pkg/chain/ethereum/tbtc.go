@@ -8,6 +8,7 @@ import (
 	"math/big"
 	"reflect"
 	"sort"
+	"sync/atomic"
 	"time"
 
 	"github.com/keep-network/keep-common/pkg/cache"
@@ -59,6 +60,16 @@ type TbtcChain struct {
 	redemptionWatchtower    *tbtccontract.RedemptionWatchtower
 
 	sweptDepositsCache *cache.GenericTimeCache[*tbtc.DepositChainRequest]
+
+	// Addresses of contracts emitting events served by the event index.
+	bridgeAddress         common.Address
+	walletRegistryAddress common.Address
+
+	// eventIndex is optional and serves past events from the local event
+	// index. Past events are fetched from the chain if it is nil. It is set
+	// once the index is started, possibly when the chain handle is already
+	// in use.
+	eventIndex atomic.Pointer[eventIndex]
 }
 
 // NewTbtcChain construct a new instance of the TBTC-specific Ethereum
@@ -247,6 +258,8 @@ func newTbtcChain(
 		walletProposalValidator: walletProposalValidator,
 		redemptionWatchtower:    redemptionWatchtower,
 		sweptDepositsCache:      cache.NewGenericTimeCache[*tbtc.DepositChainRequest](sweptDepositsCachePeriod),
+		bridgeAddress:           bridgeAddress,
+		walletRegistryAddress:   walletRegistryAddress,
 	}, nil
 }
 
@@ -485,6 +498,18 @@ func (tc *TbtcChain) OnDKGStarted(
 
 func (tc *TbtcChain) PastDKGStartedEvents(
 	filter *tbtc.DKGStartedEventFilter,
+) ([]*tbtc.DKGStartedEvent, error) {
+	if index := tc.eventIndex.Load(); index != nil {
+		return index.dkgStarted.query(filter)
+	}
+
+	return tc.pastDKGStartedEvents(filter)
+}
+
+// pastDKGStartedEvents fetches past events matching the given filter
+// directly from the chain, bypassing the event index.
+func (tc *TbtcChain) pastDKGStartedEvents(
+	filter *tbtc.DKGStartedEventFilter,
 ) ([]*tbtc.DKGStartedEvent, error) {
 	var startBlock uint64
 	var endBlock *uint64
@@ -1184,8 +1209,58 @@ func (tc *TbtcChain) GetInactivityClaimNonce(
 	return nonce, nil
 }
 
+func (tc *TbtcChain) OnDepositRevealed(
+	handler func(event *tbtc.DepositRevealedEvent),
+) subscription.EventSubscription {
+	onEvent := func(
+		fundingTxHash [32]byte,
+		fundingOutputIndex uint32,
+		depositor common.Address,
+		amount uint64,
+		blindingFactor [8]byte,
+		walletPublicKeyHash [20]byte,
+		refundPublicKeyHash [20]byte,
+		refundLocktime [4]byte,
+		vault common.Address,
+		blockNumber uint64,
+	) {
+		var vaultAddress *chain.Address
+		if vault != [20]byte{} {
+			v := chain.Address(vault.Hex())
+			vaultAddress = &v
+		}
+
+		handler(&tbtc.DepositRevealedEvent{
+			FundingTxHash:       fundingTxHash,
+			FundingOutputIndex:  fundingOutputIndex,
+			Depositor:           chain.Address(depositor.Hex()),
+			Amount:              amount,
+			BlindingFactor:      blindingFactor,
+			WalletPublicKeyHash: walletPublicKeyHash,
+			RefundPublicKeyHash: refundPublicKeyHash,
+			RefundLocktime:      refundLocktime,
+			Vault:               vaultAddress,
+			BlockNumber:         blockNumber,
+		})
+	}
+
+	return tc.bridge.DepositRevealedEvent(nil, nil, nil).OnEvent(onEvent)
+}
+
 func (tc *TbtcChain) PastDepositRevealedEvents(
 	filter *tbtc.DepositRevealedEventFilter,
+) ([]*tbtc.DepositRevealedEvent, error) {
+	if index := tc.eventIndex.Load(); index != nil {
+		return index.depositRevealed.query(filter)
+	}
+
+	return tc.pastDepositRevealedEvents(filter)
+}
+
+// pastDepositRevealedEvents fetches past events matching the given filter
+// directly from the chain, bypassing the event index.
+func (tc *TbtcChain) pastDepositRevealedEvents(
+	filter *tbtc.DepositRevealedEventFilter,
 ) ([]*tbtc.DepositRevealedEvent, error) {
 	var startBlock uint64
 	var endBlock *uint64
@@ -1251,8 +1326,57 @@ func (tc *TbtcChain) PastDepositRevealedEvents(
 	return convertedEvents, err
 }
 
+func (tc *TbtcChain) OnRedemptionRequested(
+	handler func(event *tbtc.RedemptionRequestedEvent),
+) subscription.EventSubscription {
+	onEvent := func(
+		walletPublicKeyHash [20]byte,
+		redeemerOutputScript []byte,
+		redeemer common.Address,
+		requestedAmount uint64,
+		treasuryFee uint64,
+		txMaxFee uint64,
+		blockNumber uint64,
+	) {
+		script, err := bitcoin.NewScriptFromVarLenData(redeemerOutputScript)
+		if err != nil {
+			logger.Errorf(
+				"cannot parse redeemer output script of redemption "+
+					"requested event at block [%v]: [%v]",
+				blockNumber,
+				err,
+			)
+			return
+		}
+
+		handler(&tbtc.RedemptionRequestedEvent{
+			WalletPublicKeyHash:  walletPublicKeyHash,
+			RedeemerOutputScript: script,
+			Redeemer:             chain.Address(redeemer.Hex()),
+			RequestedAmount:      requestedAmount,
+			TreasuryFee:          treasuryFee,
+			TxMaxFee:             txMaxFee,
+			BlockNumber:          blockNumber,
+		})
+	}
+
+	return tc.bridge.RedemptionRequestedEvent(nil, nil, nil).OnEvent(onEvent)
+}
+
 func (tc *TbtcChain) PastRedemptionRequestedEvents(
 	filter *tbtc.RedemptionRequestedEventFilter,
+) ([]*tbtc.RedemptionRequestedEvent, error) {
+	if index := tc.eventIndex.Load(); index != nil {
+		return index.redemptionRequested.query(filter)
+	}
+
+	return tc.pastRedemptionRequestedEvents(filter)
+}
+
+// pastRedemptionRequestedEvents fetches past events matching the given filter
+// directly from the chain, bypassing the event index.
+func (tc *TbtcChain) pastRedemptionRequestedEvents(
+	filter *tbtc.RedemptionRequestedEventFilter,
 ) ([]*tbtc.RedemptionRequestedEvent, error) {
 	var startBlock uint64
 	var endBlock *uint64
@@ -1371,6 +1495,18 @@ func (tc *TbtcChain) GetDepositRequest(
 
 func (tc *TbtcChain) PastNewWalletRegisteredEvents(
 	filter *tbtc.NewWalletRegisteredEventFilter,
+) ([]*tbtc.NewWalletRegisteredEvent, error) {
+	if index := tc.eventIndex.Load(); index != nil {
+		return index.newWalletRegistered.query(filter)
+	}
+
+	return tc.pastNewWalletRegisteredEvents(filter)
+}
+
+// pastNewWalletRegisteredEvents fetches past events matching the given filter
+// directly from the chain, bypassing the event index.
+func (tc *TbtcChain) pastNewWalletRegisteredEvents(
+	filter *tbtc.NewWalletRegisteredEventFilter,
 ) ([]*tbtc.NewWalletRegisteredEvent, error) {
 	var startBlock uint64
 	var endBlock *uint64
@@ -1486,6 +1622,148 @@ func (tc *TbtcChain) GetWallet(
 	}, nil
 }
 
+// GetWalletOperators returns the addresses of operators controlling the
+// given wallet's members, in the order of the members in the DKG result that
+// created the wallet and excluding members marked as misbehaved in that
+// result. An operator controlling multiple members occurs on the list
+// multiple times. The DKG result is looked up within the blocks between the
+// start of the DKG and the wallet registration.
+func (tc *TbtcChain) GetWalletOperators(
+	ecdsaWalletID [32]byte,
+) ([]chain.Address, error) {
+	registeredEvents, err := tc.PastNewWalletRegisteredEvents(
+		&tbtc.NewWalletRegisteredEventFilter{
+			EcdsaWalletID: [][32]byte{ecdsaWalletID},
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"cannot get new wallet registered events: [%w]",
+			err,
+		)
+	}
+
+	if len(registeredEvents) != 1 {
+		return nil, fmt.Errorf(
+			"unexpected number of new wallet registered events for wallet "+
+				"[0x%x]: [%d]",
+			ecdsaWalletID,
+			len(registeredEvents),
+		)
+	}
+
+	// The wallet is registered in the Bridge by the callback of the
+	// WalletRegistry creating the wallet so both events are emitted in
+	// the same block.
+	registrationBlock := registeredEvents[0].BlockNumber
+
+	walletCreatedEvents, err := tc.walletRegistry.PastWalletCreatedEvents(
+		registrationBlock,
+		&registrationBlock,
+		[][32]byte{ecdsaWalletID},
+		nil,
+	)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"cannot get wallet created events: [%w]",
+			err,
+		)
+	}
+
+	if len(walletCreatedEvents) != 1 {
+		return nil, fmt.Errorf(
+			"unexpected number of wallet created events for wallet "+
+				"[0x%x]: [%d]",
+			ecdsaWalletID,
+			len(walletCreatedEvents),
+		)
+	}
+
+	dkgResultHash := walletCreatedEvents[0].DkgResultHash
+
+	// The result was submitted during the latest DKG started before the
+	// wallet registration.
+	dkgStartedEvents, err := tc.PastDKGStartedEvents(
+		&tbtc.DKGStartedEventFilter{
+			EndBlock: &registrationBlock,
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"cannot get DKG started events: [%w]",
+			err,
+		)
+	}
+
+	if len(dkgStartedEvents) == 0 {
+		return nil, fmt.Errorf(
+			"no DKG started event before block [%d]",
+			registrationBlock,
+		)
+	}
+
+	dkgStartBlock := dkgStartedEvents[len(dkgStartedEvents)-1].BlockNumber
+
+	dkgResultSubmittedEvents, err := tc.walletRegistry.PastDkgResultSubmittedEvents(
+		dkgStartBlock,
+		&registrationBlock,
+		[][32]byte{dkgResultHash},
+		nil,
+	)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"cannot get DKG result submitted events: [%w]",
+			err,
+		)
+	}
+
+	if len(dkgResultSubmittedEvents) == 0 {
+		return nil, fmt.Errorf(
+			"no DKG result submitted event for result hash [0x%x]",
+			dkgResultHash,
+		)
+	}
+
+	// The same result cannot be approved twice so the latest submission
+	// is the one that created the wallet.
+	result := dkgResultSubmittedEvents[len(dkgResultSubmittedEvents)-1].Result
+
+	misbehaved := make(map[uint8]bool)
+	for _, memberIndex := range result.MisbehavedMembersIndices {
+		misbehaved[memberIndex] = true
+	}
+
+	operatorsIDs := make([]uint32, 0, len(result.Members))
+	for i, memberID := range result.Members {
+		// Member indexes start at 1.
+		if misbehaved[uint8(i+1)] {
+			continue
+		}
+
+		operatorsIDs = append(operatorsIDs, memberID)
+	}
+
+	operatorsAddresses, err := tc.sortitionPool.GetIDOperators(operatorsIDs)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"cannot convert operators' IDs to addresses: [%w]",
+			err,
+		)
+	}
+
+	// Should not happen as this is guaranteed by the contract but, just in case.
+	if len(operatorsIDs) != len(operatorsAddresses) {
+		return nil, fmt.Errorf("operators IDs and addresses mismatch")
+	}
+
+	walletOperators := make([]chain.Address, len(operatorsAddresses))
+	for i, operatorAddress := range operatorsAddresses {
+		walletOperators[i] = chain.Address(operatorAddress.String())
+	}
+
+	return walletOperators, nil
+}
+
 func (tc *TbtcChain) OnWalletClosed(
 	handler func(event *tbtc.WalletClosedEvent),
 ) subscription.EventSubscription {
@@ -1618,6 +1896,7 @@ func (tc *TbtcChain) SubmitRedemptionProofWithReimbursement(
 	proof *bitcoin.SpvProof,
 	mainUTXO bitcoin.UnspentTransactionOutput,
 	walletPublicKeyHash [20]byte,
+	check chain.ReimbursementCheck,
 ) error {
 	bitcoinTxInfo := tbtcabi.BitcoinTxInfo3{
 		Version:      transaction.SerializeVersion(),
@@ -1648,6 +1927,15 @@ func (tc *TbtcChain) SubmitRedemptionProofWithReimbursement(
 		return err
 	}
 
+	err = tc.checkMaintainerProxyProfitability(
+		gasEstimate,
+		tc.maintainerProxy.SubmitRedemptionProofGasOffset,
+		check,
+	)
+	if err != nil {
+		return err
+	}
+
 	// The original estimate for this contract call is too low and the call
 	// fails on reimbursing the submitter. Example:
 	// 0xe27a92883e0e64da8a3a54a15a260ea2f4d3d48470129ac5c09bfe9637d7e114
@@ -1696,6 +1984,7 @@ func (tc *TbtcChain) SubmitDepositSweepProofWithReimbursement(
 	proof *bitcoin.SpvProof,
 	mainUTXO bitcoin.UnspentTransactionOutput,
 	vault common.Address,
+	check chain.ReimbursementCheck,
 ) error {
 	bitcoinTxInfo := tbtcabi.BitcoinTxInfo3{
 		Version:      transaction.SerializeVersion(),
@@ -1726,6 +2015,15 @@ func (tc *TbtcChain) SubmitDepositSweepProofWithReimbursement(
 		return err
 	}
 
+	err = tc.checkMaintainerProxyProfitability(
+		gasEstimate,
+		tc.maintainerProxy.SubmitDepositSweepProofGasOffset,
+		check,
+	)
+	if err != nil {
+		return err
+	}
+
 	// The original estimate for this contract call is too low and the call
 	// fails on reimbursing the submitter. Example:
 	// 0xe27a92883e0e64da8a3a54a15a260ea2f4d3d48470129ac5c09bfe9637d7e114
@@ -1803,8 +2101,40 @@ func (tc *TbtcChain) GetLiveWalletsCount() (uint32, error) {
 	return tc.bridge.LiveWalletsCount()
 }
 
+func (tc *TbtcChain) OnMovingFundsCommitmentSubmitted(
+	handler func(event *tbtc.MovingFundsCommitmentSubmittedEvent),
+) subscription.EventSubscription {
+	onEvent := func(
+		walletPublicKeyHash [20]byte,
+		targetWallets [][20]byte,
+		submitter common.Address,
+		blockNumber uint64,
+	) {
+		handler(&tbtc.MovingFundsCommitmentSubmittedEvent{
+			WalletPublicKeyHash: walletPublicKeyHash,
+			TargetWallets:       targetWallets,
+			Submitter:           chain.Address(submitter.Hex()),
+			BlockNumber:         blockNumber,
+		})
+	}
+
+	return tc.bridge.MovingFundsCommitmentSubmittedEvent(nil, nil).OnEvent(onEvent)
+}
+
 func (tc *TbtcChain) PastMovingFundsCommitmentSubmittedEvents(
 	filter *tbtc.MovingFundsCommitmentSubmittedEventFilter,
+) ([]*tbtc.MovingFundsCommitmentSubmittedEvent, error) {
+	if index := tc.eventIndex.Load(); index != nil {
+		return index.movingFundsCommitmentSubmitted.query(filter)
+	}
+
+	return tc.pastMovingFundsCommitmentSubmittedEvents(filter)
+}
+
+// pastMovingFundsCommitmentSubmittedEvents fetches past events matching the given filter
+// directly from the chain, bypassing the event index.
+func (tc *TbtcChain) pastMovingFundsCommitmentSubmittedEvents(
+	filter *tbtc.MovingFundsCommitmentSubmittedEventFilter,
 ) ([]*tbtc.MovingFundsCommitmentSubmittedEvent, error) {
 	var startBlock uint64
 	var endBlock *uint64
@@ -1849,6 +2179,18 @@ func (tc *TbtcChain) PastMovingFundsCommitmentSubmittedEvents(
 
 func (tc *TbtcChain) PastMovingFundsCompletedEvents(
 	filter *tbtc.MovingFundsCompletedEventFilter,
+) ([]*tbtc.MovingFundsCompletedEvent, error) {
+	if index := tc.eventIndex.Load(); index != nil {
+		return index.movingFundsCompleted.query(filter)
+	}
+
+	return tc.pastMovingFundsCompletedEvents(filter)
+}
+
+// pastMovingFundsCompletedEvents fetches past events matching the given filter
+// directly from the chain, bypassing the event index.
+func (tc *TbtcChain) pastMovingFundsCompletedEvents(
+	filter *tbtc.MovingFundsCompletedEventFilter,
 ) ([]*tbtc.MovingFundsCompletedEvent, error) {
 	var startBlock uint64
 	var endBlock *uint64
@@ -2020,11 +2362,49 @@ func (tc *TbtcChain) SubmitMovingFundsCommitment(
 	return err
 }
 
+func (tc *TbtcChain) NotifyRedemptionTimeout(
+	walletPublicKeyHash [20]byte,
+	walletMembersIDs []uint32,
+	redeemerOutputScript bitcoin.Script,
+) error {
+	_, err := tc.bridge.NotifyRedemptionTimeout(
+		walletPublicKeyHash,
+		walletMembersIDs,
+		redeemerOutputScript,
+	)
+	return err
+}
+
+func (tc *TbtcChain) NotifyMovingFundsTimeout(
+	walletPublicKeyHash [20]byte,
+	walletMembersIDs []uint32,
+) error {
+	_, err := tc.bridge.NotifyMovingFundsTimeout(
+		walletPublicKeyHash,
+		walletMembersIDs,
+	)
+	return err
+}
+
+func (tc *TbtcChain) NotifyMovedFundsSweepTimeout(
+	movingFundsTxHash bitcoin.Hash,
+	movingFundsTxOutputIndex uint32,
+	walletMembersIDs []uint32,
+) error {
+	_, err := tc.bridge.NotifyMovedFundsSweepTimeout(
+		movingFundsTxHash,
+		movingFundsTxOutputIndex,
+		walletMembersIDs,
+	)
+	return err
+}
+
 func (tc *TbtcChain) SubmitMovingFundsProofWithReimbursement(
 	transaction *bitcoin.Transaction,
 	proof *bitcoin.SpvProof,
 	mainUTXO bitcoin.UnspentTransactionOutput,
 	walletPublicKeyHash [20]byte,
+	check chain.ReimbursementCheck,
 ) error {
 	bitcoinTxInfo := tbtcabi.BitcoinTxInfo3{
 		Version:      transaction.SerializeVersion(),
@@ -2055,6 +2435,15 @@ func (tc *TbtcChain) SubmitMovingFundsProofWithReimbursement(
 		return err
 	}
 
+	err = tc.checkMaintainerProxyProfitability(
+		gasEstimate,
+		tc.maintainerProxy.SubmitMovingFundsProofGasOffset,
+		check,
+	)
+	if err != nil {
+		return err
+	}
+
 	// The original estimate for this contract call is too low and the call
 	// fails on reimbursing the submitter. Example:
 	// 0xe27a92883e0e64da8a3a54a15a260ea2f4d3d48470129ac5c09bfe9637d7e114
@@ -2078,6 +2467,7 @@ func (tc *TbtcChain) SubmitMovedFundsSweepProofWithReimbursement(
 	transaction *bitcoin.Transaction,
 	proof *bitcoin.SpvProof,
 	mainUTXO bitcoin.UnspentTransactionOutput,
+	check chain.ReimbursementCheck,
 ) error {
 	bitcoinTxInfo := tbtcabi.BitcoinTxInfo3{
 		Version:      transaction.SerializeVersion(),
@@ -2107,6 +2497,15 @@ func (tc *TbtcChain) SubmitMovedFundsSweepProofWithReimbursement(
 		return err
 	}
 
+	err = tc.checkMaintainerProxyProfitability(
+		gasEstimate,
+		tc.maintainerProxy.SubmitMovedFundsSweepProofGasOffset,
+		check,
+	)
+	if err != nil {
+		return err
+	}
+
 	// The original estimate for this contract call is too low and the call
 	// fails on reimbursing the submitter. Example:
 	// 0xe27a92883e0e64da8a3a54a15a260ea2f4d3d48470129ac5c09bfe9637d7e114
@@ -2125,6 +2524,44 @@ func (tc *TbtcChain) SubmitMovedFundsSweepProofWithReimbursement(
 	return err
 }
 
+// checkMaintainerProxyProfitability estimates the cost and the reimbursement
+// of a transaction submitted via MaintainerProxy and runs the given check
+// against the estimate. The check can be nil in which case nothing is
+// estimated.
+func (tc *TbtcChain) checkMaintainerProxyProfitability(
+	gasEstimate uint64,
+	gasOffsetFn func() (*big.Int, error),
+	check chain.ReimbursementCheck,
+) error {
+	if check == nil {
+		return nil
+	}
+
+	gasOffset, err := gasOffsetFn()
+	if err != nil {
+		return fmt.Errorf("cannot get gas offset: [%w]", err)
+	}
+
+	poolAddress, err := tc.maintainerProxy.ReimbursementPool()
+	if err != nil {
+		return fmt.Errorf("cannot get reimbursement pool address: [%w]", err)
+	}
+
+	maintainers, err := tc.maintainerProxy.AllSpvMaintainers()
+	if err != nil {
+		return fmt.Errorf("cannot get SPV maintainers: [%w]", err)
+	}
+
+	return checkProfitability(
+		tc.client,
+		&reimbursementPool{address: poolAddress, client: tc.client},
+		gasEstimate,
+		gasOffset,
+		countOtherMaintainers(maintainers, tc.key.Address),
+		check,
+	)
+}
+
 func (tc *TbtcChain) ValidateMovedFundsSweepProposal(
 	walletPublicKeyHash [20]byte,
 	proposal *tbtc.MovedFundsSweepProposal,
@@ -2277,6 +2714,26 @@ func (tc *TbtcChain) GetMovingFundsParameters() (
 	return
 }
 
+// IsMainUtxoSpent checks whether the given outpoint was spent as the main
+// UTXO of a wallet by a transaction proven to the Bridge.
+func (tc *TbtcChain) IsMainUtxoSpent(
+	outpoint *bitcoin.TransactionOutpoint,
+) (bool, error) {
+	// The Bridge keys spent main UTXOs the same way as deposits.
+	utxoKey := buildDepositKey(outpoint.TransactionHash, outpoint.OutputIndex)
+
+	spent, err := tc.bridge.SpentMainUTXOs(utxoKey)
+	if err != nil {
+		return false, fmt.Errorf(
+			"cannot check main UTXO with key [0x%x]: [%v]",
+			utxoKey.Text(16),
+			err,
+		)
+	}
+
+	return spent, nil
+}
+
 func (tc *TbtcChain) GetMovedFundsSweepRequest(
 	movingFundsTxHash bitcoin.Hash,
 	movingFundsTxOutpointIndex uint32,
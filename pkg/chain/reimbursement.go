@@ -0,0 +1,32 @@
+package chain
+
+import "math/big"
+
+// ReimbursementEstimate is an estimation of the cost and the reimbursement
+// of a reimbursable transaction.
+type ReimbursementEstimate struct {
+	// GasPrice is the current gas price, in wei.
+	GasPrice *big.Int
+	// MaxGasPrice is the maximum gas price reimbursed by the reimbursement
+	// pool, in wei.
+	MaxGasPrice *big.Int
+	// Cost is the estimated cost of the transaction, in wei.
+	Cost *big.Int
+	// Reimbursement is the estimated reimbursement for the transaction,
+	// in wei.
+	Reimbursement *big.Int
+	// OtherMaintainers is the number of other maintainers authorized
+	// to submit the transaction.
+	OtherMaintainers int
+}
+
+// IsProfitable returns true if the estimated reimbursement covers
+// the estimated cost of the transaction.
+func (re *ReimbursementEstimate) IsProfitable() bool {
+	return re.Reimbursement.Cmp(re.Cost) >= 0
+}
+
+// ReimbursementCheck decides whether a reimbursable transaction with the
+// given estimate should be submitted. A non-nil error means the transaction
+// must not be submitted.
+type ReimbursementCheck func(estimate *ReimbursementEstimate) error
@@ -0,0 +1,99 @@
+package clientinfo
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// apiPathPrefix is the path prefix of all endpoints of the operator API.
+const apiPathPrefix = "/api/v1/"
+
+// APISource is a source of data exposed by a read-only endpoint of the
+// operator API. The source receives query parameters of the request and
+// returns a value serialized to JSON in the response.
+type APISource func(query url.Values) (interface{}, error)
+
+// APIError is an error returned by an APISource that should be reported
+// with the given HTTP status code. Other errors are reported with the
+// 500 Internal Server Error status code.
+type APIError struct {
+	StatusCode int
+	Message    string
+}
+
+// NewAPIError creates a new APIError with the given status code and
+// formatted message.
+func NewAPIError(statusCode int, format string, args ...interface{}) *APIError {
+	return &APIError{
+		StatusCode: statusCode,
+		Message:    fmt.Sprintf(format, args...),
+	}
+}
+
+func (ae *APIError) Error() string {
+	return ae.Message
+}
+
+// RegisterAPISource registers the given source as the GET /api/v1/<name>
+// endpoint of the read-only operator API served on the client info port.
+func (r *Registry) RegisterAPISource(name string, source APISource) {
+	r.api.Handle(apiPathPrefix+name, apiHandler(source))
+}
+
+// apiHandler returns an HTTP handler responding with the JSON representation
+// of data returned by the given source.
+func apiHandler(source APISource) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet {
+			w.Header().Set("Allow", http.MethodGet)
+			writeAPIResponse(
+				w,
+				http.StatusMethodNotAllowed,
+				apiErrorResponse{Error: "only GET requests are supported"},
+			)
+			return
+		}
+
+		data, err := source(req.URL.Query())
+		if err != nil {
+			statusCode := http.StatusInternalServerError
+
+			var apiErr *APIError
+			if errors.As(err, &apiErr) {
+				statusCode = apiErr.StatusCode
+			} else {
+				logger.Errorf(
+					"error on serving API request [%s]: [%v]",
+					req.URL.Path,
+					err,
+				)
+			}
+
+			writeAPIResponse(w, statusCode, apiErrorResponse{Error: err.Error()})
+			return
+		}
+
+		writeAPIResponse(w, http.StatusOK, data)
+	})
+}
+
+// apiErrorResponse describes data structure of an API error response.
+type apiErrorResponse struct {
+	Error string `json:"error"`
+}
+
+func writeAPIResponse(w http.ResponseWriter, statusCode int, data interface{}) {
+	bytes, err := json.Marshal(data)
+	if err != nil {
+		logger.Errorf("error on serializing API response to JSON: [%v]", err)
+		statusCode = http.StatusInternalServerError
+		bytes = []byte(`{"error":"cannot serialize response"}`)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_, _ = w.Write(bytes)
+}
@@ -0,0 +1,95 @@
+package clientinfo
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	keepclientinfo "github.com/keep-network/keep-common/pkg/clientinfo"
+
+	"github.com/keep-network/keep-core/internal/testutils"
+)
+
+func TestRegisterAPISource(t *testing.T) {
+	registry := &Registry{
+		Registry: keepclientinfo.NewRegistry(),
+		ctx:      context.Background(),
+		api:      http.NewServeMux(),
+	}
+
+	registry.RegisterAPISource("wallets", func(query url.Values) (interface{}, error) {
+		switch query.Get("wallet") {
+		case "":
+			return []string{"0x01", "0x02"}, nil
+		case "unknown":
+			return nil, NewAPIError(http.StatusNotFound, "wallet not found")
+		default:
+			return nil, fmt.Errorf("unexpected error")
+		}
+	})
+
+	var tests = map[string]struct {
+		method             string
+		target             string
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		"successful request": {
+			method:             http.MethodGet,
+			target:             "/api/v1/wallets",
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       `["0x01","0x02"]`,
+		},
+		"api error": {
+			method:             http.MethodGet,
+			target:             "/api/v1/wallets?wallet=unknown",
+			expectedStatusCode: http.StatusNotFound,
+			expectedBody:       `{"error":"wallet not found"}`,
+		},
+		"internal error": {
+			method:             http.MethodGet,
+			target:             "/api/v1/wallets?wallet=0x03",
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedBody:       `{"error":"unexpected error"}`,
+		},
+		"unsupported method": {
+			method:             http.MethodPost,
+			target:             "/api/v1/wallets",
+			expectedStatusCode: http.StatusMethodNotAllowed,
+			expectedBody:       `{"error":"only GET requests are supported"}`,
+		},
+		"unknown endpoint": {
+			method:             http.MethodGet,
+			target:             "/api/v1/unknown",
+			expectedStatusCode: http.StatusNotFound,
+			expectedBody:       "404 page not found\n",
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+
+			registry.api.ServeHTTP(
+				recorder,
+				httptest.NewRequest(test.method, test.target, nil),
+			)
+
+			testutils.AssertIntsEqual(
+				t,
+				"status code",
+				test.expectedStatusCode,
+				recorder.Code,
+			)
+			testutils.AssertStringsEqual(
+				t,
+				"body",
+				test.expectedBody,
+				recorder.Body.String(),
+			)
+		})
+	}
+}
@@ -2,6 +2,7 @@ package clientinfo
 
 import (
 	"context"
+	"net/http"
 	"time"
 
 	"github.com/ipfs/go-log"
@@ -26,6 +27,9 @@ type Registry struct {
 	*clientinfo.Registry
 
 	ctx context.Context
+
+	// api routes requests of the read-only operator API.
+	api *http.ServeMux
 }
 
 // Initialize set up the client info registry and enables metrics and
@@ -38,7 +42,16 @@ func Initialize(
 		return nil, false
 	}
 
-	registry := &Registry{clientinfo.NewRegistry(), ctx}
+	registry := &Registry{
+		Registry: clientinfo.NewRegistry(),
+		ctx:      ctx,
+		api:      http.NewServeMux(),
+	}
+
+	// The client info server uses the default serve mux so, endpoints of
+	// the operator API are served on the same port as metrics and
+	// diagnostics.
+	http.Handle(apiPathPrefix, registry.api)
 
 	registry.EnableServer(port)
 
@@ -139,6 +139,8 @@ func (pm *PerformanceMetrics) registerAllMetrics() {
 		MetricNetworkJoinRequestsFailedTotal,
 		MetricFirewallRejectionsTotal,
 		MetricWalletDispatcherRejectedTotal,
+		MetricBitcoinQuorumDisagreementsTotal,
+		MetricBitcoinQuorumFailuresTotal,
 	}
 
 	// First, initialize all counters in the map
@@ -678,6 +680,10 @@ const (
 	MetricWalletDispatcherActiveActions = "wallet_dispatcher_active_actions"
 	MetricWalletDispatcherRejectedTotal = "wallet_dispatcher_rejected_total"
 
+	// Bitcoin Quorum Metrics
+	MetricBitcoinQuorumDisagreementsTotal = "bitcoin_quorum_disagreements_total" // Backends returned different results
+	MetricBitcoinQuorumFailuresTotal      = "bitcoin_quorum_failures_total"      // Quorum not reached
+
 	// System Metrics
 	MetricCPUUtilization         = "cpu_utilization_percent"
 	MetricMemoryUsageMB          = "memory_usage_mb"
@@ -703,5 +709,6 @@ func GetAllWalletActionTypes() []string {
 		"redemption",
 		"moving_funds",
 		"moved_funds_sweep",
+		"fee_bump",
 	}
 }
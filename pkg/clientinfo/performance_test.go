@@ -16,7 +16,7 @@ func TestConcurrentCounterIncrement(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	registry := &Registry{keepclientinfo.NewRegistry(), ctx}
+	registry := &Registry{Registry: keepclientinfo.NewRegistry(), ctx: ctx}
 	pm := NewPerformanceMetrics(ctx, registry)
 
 	const (
@@ -50,7 +50,7 @@ func TestConcurrentCounterDifferentMetrics(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	registry := &Registry{keepclientinfo.NewRegistry(), ctx}
+	registry := &Registry{Registry: keepclientinfo.NewRegistry(), ctx: ctx}
 	pm := NewPerformanceMetrics(ctx, registry)
 
 	const (
@@ -114,7 +114,7 @@ func TestConcurrentDurationRecording(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	registry := &Registry{keepclientinfo.NewRegistry(), ctx}
+	registry := &Registry{Registry: keepclientinfo.NewRegistry(), ctx: ctx}
 	pm := NewPerformanceMetrics(ctx, registry)
 
 	const (
@@ -168,7 +168,7 @@ func TestConcurrentGaugeSet(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	registry := &Registry{keepclientinfo.NewRegistry(), ctx}
+	registry := &Registry{Registry: keepclientinfo.NewRegistry(), ctx: ctx}
 	pm := NewPerformanceMetrics(ctx, registry)
 
 	const (
@@ -204,7 +204,7 @@ func TestConcurrentDifferentOperations(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	registry := &Registry{keepclientinfo.NewRegistry(), ctx}
+	registry := &Registry{Registry: keepclientinfo.NewRegistry(), ctx: ctx}
 	pm := NewPerformanceMetrics(ctx, registry)
 
 	const (
@@ -263,7 +263,7 @@ func TestHistogramBucketPlacement(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	registry := &Registry{keepclientinfo.NewRegistry(), ctx}
+	registry := &Registry{Registry: keepclientinfo.NewRegistry(), ctx: ctx}
 	pm := NewPerformanceMetrics(ctx, registry)
 
 	metricName := "test_duration_seconds"
@@ -319,7 +319,7 @@ func TestMetricsInitialization(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	registry := &Registry{keepclientinfo.NewRegistry(), ctx}
+	registry := &Registry{Registry: keepclientinfo.NewRegistry(), ctx: ctx}
 	pm := NewPerformanceMetrics(ctx, registry)
 
 	// Test counters
@@ -358,7 +358,7 @@ func TestMetricsInitialization(t *testing.T) {
 func TestContextCancelation(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 
-	registry := &Registry{keepclientinfo.NewRegistry(), ctx}
+	registry := &Registry{Registry: keepclientinfo.NewRegistry(), ctx: ctx}
 	pm := NewPerformanceMetrics(ctx, registry)
 
 	// Cancel context immediately
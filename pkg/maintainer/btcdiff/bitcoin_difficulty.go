@@ -8,6 +8,7 @@ import (
 	"github.com/ipfs/go-log/v2"
 
 	"github.com/keep-network/keep-core/pkg/bitcoin"
+	"github.com/keep-network/keep-core/pkg/maintainer/profitability"
 )
 
 var logger = log.Logger("keep-maintainer-btcdiff")
@@ -44,6 +45,7 @@ func Initialize(
 	config Config,
 	btcChain bitcoin.Chain,
 	chain Chain,
+	guard *profitability.Guard,
 ) {
 	if config.RestartBackOffTime == 0 {
 		config.RestartBackOffTime = bitcoinDifficultyDefaultRestartBackoffTime
@@ -56,6 +58,7 @@ func Initialize(
 		config:   config,
 		btcChain: btcChain,
 		chain:    chain,
+		guard:    guard,
 	}
 
 	go bitcoinDifficultyMaintainer.startControlLoop(ctx)
@@ -67,6 +70,10 @@ type bitcoinDifficultyMaintainer struct {
 	config   Config
 	btcChain bitcoin.Chain
 	chain    Chain
+	// guard decides whether retargets are worth submitting given the
+	// current gas conditions. It can be nil in which case retargets are
+	// always submitted.
+	guard *profitability.Guard
 }
 
 // startControlLoop starts the loop responsible for controlling the Bitcoin
@@ -264,7 +271,28 @@ func (bdm *bitcoinDifficultyMaintainer) proveNextEpoch(ctx context.Context) (
 				)
 			}
 		} else {
-			if err := bdm.chain.RetargetWithRefund(headers); err != nil {
+			postponed := false
+			check := bdm.guard.Check(
+				fmt.Sprintf("retarget-%d", newEpoch),
+				func() {
+					postponed = true
+				},
+			)
+
+			err := bdm.chain.RetargetWithRefund(headers, check)
+			if err != nil && postponed {
+				// Retargeting is not worth submitting under the current gas
+				// conditions. Try again after the idle back-off time.
+				logger.Infof(
+					"postponed submitting block headers from range "+
+						"[%d:%d]: [%v]",
+					firstBlockHeaderHeight,
+					lastBlockHeaderHeight,
+					err,
+				)
+				return false, nil
+			}
+			if err != nil {
 				return false, fmt.Errorf(
 					"failed to submit block headers from range [%d:%d] via "+
 						"RetargetWithRefund: [%w]",
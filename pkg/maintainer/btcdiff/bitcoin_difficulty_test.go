@@ -621,6 +621,7 @@ func TestBitcoinDifficultyMaintainer_Integration(t *testing.T) {
 				config,
 				btcChain,
 				difficultyChain,
+				nil,
 			)
 
 			//************ Loop restart on error ************
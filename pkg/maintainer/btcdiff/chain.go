@@ -37,8 +37,13 @@ type Chain interface {
 
 	// RetargetWithRefund adds a new epoch to the relay by providing a proof of
 	// the difficulty before and after the retarget. The cost of calling this
-	// function is refunded to the caller.
-	RetargetWithRefund(headers []*bitcoin.BlockHeader) error
+	// function is refunded to the caller. The given check is run against the
+	// estimated cost and refund of the transaction before it is submitted;
+	// the check can be nil.
+	RetargetWithRefund(
+		headers []*bitcoin.BlockHeader,
+		check chain.ReimbursementCheck,
+	) error
 
 	// CurrentEpoch returns the number of the latest difficulty epoch which is
 	// proven to the relay. If the genesis epoch's number is set correctly, and
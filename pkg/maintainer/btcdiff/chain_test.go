@@ -81,6 +81,7 @@ func (lbdc *localBitcoinDifficultyChain) Retarget(
 // function is refunded to the caller.
 func (lbdc *localBitcoinDifficultyChain) RetargetWithRefund(
 	headers []*bitcoin.BlockHeader,
+	check chain.ReimbursementCheck,
 ) error {
 	// For simplicity, store block header bits instead of their difficulty
 	// targets.
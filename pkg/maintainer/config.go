@@ -2,11 +2,15 @@ package maintainer
 
 import (
 	"github.com/keep-network/keep-core/pkg/maintainer/btcdiff"
+	"github.com/keep-network/keep-core/pkg/maintainer/profitability"
 	"github.com/keep-network/keep-core/pkg/maintainer/spv"
+	"github.com/keep-network/keep-core/pkg/maintainer/watcher"
 )
 
 // Config contains maintainer configuration.
 type Config struct {
 	BitcoinDifficulty btcdiff.Config
 	Spv               spv.Config
+	Watcher           watcher.Config
+	Profitability     profitability.Config
 }
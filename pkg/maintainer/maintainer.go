@@ -2,11 +2,15 @@ package maintainer
 
 import (
 	"context"
+
 	"github.com/ipfs/go-log/v2"
+	"github.com/keep-network/keep-common/pkg/persistence"
 
 	"github.com/keep-network/keep-core/pkg/bitcoin"
 	"github.com/keep-network/keep-core/pkg/maintainer/btcdiff"
+	"github.com/keep-network/keep-core/pkg/maintainer/profitability"
 	"github.com/keep-network/keep-core/pkg/maintainer/spv"
+	"github.com/keep-network/keep-core/pkg/maintainer/watcher"
 )
 
 var logger = log.Logger("keep-maintainer")
@@ -17,22 +21,28 @@ func Initialize(
 	btcChain bitcoin.Chain,
 	btcDiffChain btcdiff.Chain,
 	spvChain spv.Chain,
+	watcherChain watcher.Chain,
+	workPersistence persistence.BasicHandle,
 ) {
 	// If none of the maintainers was specified in the config (i.e. no option was
 	// provided to the `maintainer` command), all maintainers should be launched.
 	launchAll := !config.BitcoinDifficulty.Enabled &&
-		!config.Spv.Enabled
+		!config.Spv.Enabled &&
+		!config.Watcher.Enabled
 
 	if launchAll {
 		logger.Info("initializing all maintainer modules...")
 	}
 
+	guard := profitability.NewGuard(config.Profitability)
+
 	if config.BitcoinDifficulty.Enabled || launchAll {
 		btcdiff.Initialize(
 			ctx,
 			config.BitcoinDifficulty,
 			btcChain,
 			btcDiffChain,
+			guard,
 		)
 	}
 
@@ -43,6 +53,17 @@ func Initialize(
 			spvChain,
 			btcDiffChain,
 			btcChain,
+			workPersistence,
+			guard,
+		)
+	}
+
+	if config.Watcher.Enabled || launchAll {
+		watcher.Initialize(
+			ctx,
+			config.Watcher,
+			watcherChain,
+			btcChain,
 		)
 	}
 
@@ -0,0 +1,204 @@
+// Package profitability decides whether reimbursable maintainer transactions
+// should be submitted given the current gas conditions.
+//
+// Maintainer transactions submitted via maintainer proxies are reimbursed
+// by the reimbursement pool. The reimbursed gas price is capped by the pool
+// so the maintainer runs at a loss when the gas price exceeds that cap.
+// The Guard applies the configured Policy to such transactions.
+package profitability
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ipfs/go-log/v2"
+
+	"github.com/keep-network/keep-core/pkg/chain"
+)
+
+var logger = log.Logger("keep-maintainer-profitability")
+
+// Policy determines what happens with a reimbursable transaction whose
+// estimated cost exceeds the estimated reimbursement.
+type Policy string
+
+const (
+	// PolicySubmit submits unprofitable transactions anyway. This is the
+	// default policy.
+	PolicySubmit Policy = "submit"
+	// PolicyWait postpones unprofitable transactions until the gas price
+	// drops enough or the maximum delay elapses.
+	PolicyWait Policy = "wait"
+	// PolicySkip leaves unprofitable transactions to other authorized
+	// maintainers as they are likely to submit them. If there are no other
+	// authorized maintainers, it behaves like PolicyWait.
+	PolicySkip Policy = "skip"
+)
+
+// DefaultMaxDelay is the default value for the maximum time an unprofitable
+// transaction can be postponed for under the wait policy.
+const DefaultMaxDelay = 6 * time.Hour
+
+// Config holds configurable properties.
+type Config struct {
+	// Policy is the policy applied to unprofitable transactions. If not set,
+	// unprofitable transactions are submitted anyway.
+	Policy Policy
+
+	// MaxDelay is the maximum time an unprofitable transaction can be
+	// postponed for. Once this time elapses, the transaction is submitted
+	// regardless of the gas price. The delay is counted from the first
+	// postponement of the transaction.
+	MaxDelay time.Duration
+}
+
+// ValidatePolicy returns an error if the given policy is not supported.
+// An empty policy is valid and means the default policy.
+func ValidatePolicy(policy Policy) error {
+	switch policy {
+	case "", PolicySubmit, PolicyWait, PolicySkip:
+		return nil
+	default:
+		return fmt.Errorf("unsupported profitability policy [%s]", policy)
+	}
+}
+
+// Guard applies the configured policy to reimbursable transactions.
+// Transactions are identified by keys chosen by the caller so the guard can
+// track for how long each of them has been postponed.
+type Guard struct {
+	config Config
+	now    func() time.Time
+
+	mutex sync.Mutex
+	// postponedAt holds the time of the first postponement of transactions
+	// that are currently postponed.
+	postponedAt map[string]time.Time
+}
+
+// NewGuard creates a new guard applying the given configuration.
+func NewGuard(config Config) *Guard {
+	if config.MaxDelay == 0 {
+		config.MaxDelay = DefaultMaxDelay
+	}
+
+	return &Guard{
+		config:      config,
+		now:         time.Now,
+		postponedAt: make(map[string]time.Time),
+	}
+}
+
+// Check returns a check of the transaction identified by the given key.
+// The returned check calls the given onPostponed function and returns an
+// error if the transaction should be postponed. The guard can be nil in which
+// case the returned check is nil and transactions are always submitted.
+func (g *Guard) Check(key string, onPostponed func()) chain.ReimbursementCheck {
+	if g == nil {
+		return nil
+	}
+
+	return func(estimate *chain.ReimbursementEstimate) error {
+		if g.shouldSubmit(key, estimate) {
+			return nil
+		}
+
+		if onPostponed != nil {
+			onPostponed()
+		}
+
+		return fmt.Errorf(
+			"transaction [%s] postponed; estimated cost [%v] wei exceeds "+
+				"estimated reimbursement [%v] wei",
+			key,
+			estimate.Cost,
+			estimate.Reimbursement,
+		)
+	}
+}
+
+// Forget removes the postponement record of the transaction identified by
+// the given key. It should be called once the transaction is no longer
+// pending, for example because it was submitted by another maintainer.
+// The guard can be nil in which case the call is a no-op.
+func (g *Guard) Forget(key string) {
+	if g == nil {
+		return
+	}
+
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	delete(g.postponedAt, key)
+}
+
+func (g *Guard) shouldSubmit(
+	key string,
+	estimate *chain.ReimbursementEstimate,
+) bool {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	if estimate.IsProfitable() {
+		delete(g.postponedAt, key)
+		return true
+	}
+
+	if g.config.Policy == "" || g.config.Policy == PolicySubmit {
+		logger.Warnf(
+			"submitting unprofitable transaction [%s]; estimated cost "+
+				"[%v] wei exceeds estimated reimbursement [%v] wei",
+			key,
+			estimate.Cost,
+			estimate.Reimbursement,
+		)
+		return true
+	}
+
+	now := g.now()
+
+	postponedAt, ok := g.postponedAt[key]
+	if !ok {
+		postponedAt = now
+		g.postponedAt[key] = postponedAt
+	}
+
+	if g.config.Policy == PolicySkip && estimate.OtherMaintainers > 0 {
+		logger.Infof(
+			"leaving unprofitable transaction [%s] to [%d] other "+
+				"maintainer(s); gas price [%v] wei exceeds reimbursed "+
+				"gas price [%v] wei",
+			key,
+			estimate.OtherMaintainers,
+			estimate.GasPrice,
+			estimate.MaxGasPrice,
+		)
+		return false
+	}
+
+	if now.Sub(postponedAt) >= g.config.MaxDelay {
+		logger.Warnf(
+			"submitting unprofitable transaction [%s] postponed for [%v]; "+
+				"estimated cost [%v] wei exceeds estimated reimbursement "+
+				"[%v] wei",
+			key,
+			now.Sub(postponedAt),
+			estimate.Cost,
+			estimate.Reimbursement,
+		)
+		delete(g.postponedAt, key)
+		return true
+	}
+
+	logger.Infof(
+		"postponing unprofitable transaction [%s] until [%v] at the latest; "+
+			"gas price [%v] wei exceeds reimbursed gas price [%v] wei",
+		key,
+		postponedAt.Add(g.config.MaxDelay).Format(time.RFC3339),
+		estimate.GasPrice,
+		estimate.MaxGasPrice,
+	)
+
+	return false
+}
@@ -0,0 +1,170 @@
+package profitability
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/keep-network/keep-core/internal/testutils"
+	"github.com/keep-network/keep-core/pkg/chain"
+)
+
+func TestGuard_ShouldSubmit(t *testing.T) {
+	profitable := &chain.ReimbursementEstimate{
+		GasPrice:      big.NewInt(50),
+		MaxGasPrice:   big.NewInt(100),
+		Cost:          big.NewInt(500),
+		Reimbursement: big.NewInt(600),
+	}
+	unprofitable := &chain.ReimbursementEstimate{
+		GasPrice:      big.NewInt(200),
+		MaxGasPrice:   big.NewInt(100),
+		Cost:          big.NewInt(2000),
+		Reimbursement: big.NewInt(1000),
+	}
+	unprofitableWithOthers := &chain.ReimbursementEstimate{
+		GasPrice:         big.NewInt(200),
+		MaxGasPrice:      big.NewInt(100),
+		Cost:             big.NewInt(2000),
+		Reimbursement:    big.NewInt(1000),
+		OtherMaintainers: 2,
+	}
+
+	start := time.Unix(1700000000, 0)
+
+	type check struct {
+		elapsed        time.Duration
+		estimate       *chain.ReimbursementEstimate
+		expectedSubmit bool
+	}
+
+	var tests = map[string]struct {
+		policy Policy
+		checks []check
+	}{
+		"default policy": {
+			policy: "",
+			checks: []check{
+				{0, unprofitable, true},
+			},
+		},
+		"submit policy": {
+			policy: PolicySubmit,
+			checks: []check{
+				{0, profitable, true},
+				{0, unprofitable, true},
+			},
+		},
+		"wait policy - gas price drops": {
+			policy: PolicyWait,
+			checks: []check{
+				{0, unprofitable, false},
+				{time.Hour, unprofitable, false},
+				{2 * time.Hour, profitable, true},
+			},
+		},
+		"wait policy - max delay elapses": {
+			policy: PolicyWait,
+			checks: []check{
+				{0, unprofitable, false},
+				{6*time.Hour - time.Second, unprofitable, false},
+				{6 * time.Hour, unprofitable, true},
+				// The postponement record is cleared after the submission.
+				{7 * time.Hour, unprofitable, false},
+			},
+		},
+		"wait policy - other maintainers": {
+			policy: PolicyWait,
+			checks: []check{
+				{0, unprofitableWithOthers, false},
+				{6 * time.Hour, unprofitableWithOthers, true},
+			},
+		},
+		"skip policy - other maintainers": {
+			policy: PolicySkip,
+			checks: []check{
+				{0, unprofitableWithOthers, false},
+				{24 * time.Hour, unprofitableWithOthers, false},
+				{25 * time.Hour, profitable, true},
+			},
+		},
+		"skip policy - no other maintainers": {
+			policy: PolicySkip,
+			checks: []check{
+				{0, unprofitable, false},
+				{6 * time.Hour, unprofitable, true},
+			},
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			guard := NewGuard(Config{Policy: test.policy})
+
+			for i, c := range test.checks {
+				guard.now = func() time.Time {
+					return start.Add(c.elapsed)
+				}
+
+				testutils.AssertBoolsEqual(
+					t,
+					"should submit",
+					c.expectedSubmit,
+					guard.shouldSubmit("key", c.estimate),
+				)
+
+				if t.Failed() {
+					t.Fatalf("unexpected result of check [%d]", i)
+				}
+			}
+		})
+	}
+}
+
+func TestGuard_Check(t *testing.T) {
+	unprofitable := &chain.ReimbursementEstimate{
+		GasPrice:      big.NewInt(200),
+		MaxGasPrice:   big.NewInt(100),
+		Cost:          big.NewInt(2000),
+		Reimbursement: big.NewInt(1000),
+	}
+
+	var nilGuard *Guard
+	if nilGuard.Check("key", nil) != nil {
+		t.Errorf("expected nil check for nil guard")
+	}
+
+	guard := NewGuard(Config{Policy: PolicyWait})
+
+	postponed := false
+	err := guard.Check("key", func() { postponed = true })(unprofitable)
+	if err == nil {
+		t.Errorf("expected error for postponed transaction")
+	}
+
+	testutils.AssertBoolsEqual(t, "postponed", true, postponed)
+}
+
+func TestValidatePolicy(t *testing.T) {
+	var tests = map[string]struct {
+		policy        Policy
+		expectedValid bool
+	}{
+		"empty":       {"", true},
+		"submit":      {PolicySubmit, true},
+		"wait":        {PolicyWait, true},
+		"skip":        {PolicySkip, true},
+		"unsupported": {"eager", false},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			testutils.AssertBoolsEqual(
+				t,
+				"valid",
+				test.expectedValid,
+				ValidatePolicy(test.policy) == nil,
+			)
+		})
+	}
+}
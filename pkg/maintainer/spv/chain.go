@@ -6,6 +6,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/keep-network/keep-core/pkg/bitcoin"
 	"github.com/keep-network/keep-core/pkg/chain"
+	"github.com/keep-network/keep-core/pkg/subscription"
 	"github.com/keep-network/keep-core/pkg/tbtc"
 )
 
@@ -13,11 +14,14 @@ type Chain interface {
 	// SubmitDepositSweepProofWithReimbursement submits the deposit sweep proof
 	// via MaintainerProxy. It is used to prove the deposit sweep Bitcoin
 	// transaction and update depositors' balances. The caller is reimbursed.
+	// The given check is run against the estimated cost and reimbursement
+	// of the transaction before it is submitted; the check can be nil.
 	SubmitDepositSweepProofWithReimbursement(
 		transaction *bitcoin.Transaction,
 		proof *bitcoin.SpvProof,
 		mainUTXO bitcoin.UnspentTransactionOutput,
 		vault common.Address,
+		check chain.ReimbursementCheck,
 	) error
 
 	// GetDepositRequest gets the on-chain deposit request for the given
@@ -61,30 +65,58 @@ type Chain interface {
 
 	// SubmitRedemptionProofWithReimbursement submits the redemption proof
 	// via MaintainerProxy. The caller is reimbursed.
+	// The given check is run against the estimated cost and reimbursement
+	// of the transaction before it is submitted; the check can be nil.
 	SubmitRedemptionProofWithReimbursement(
 		transaction *bitcoin.Transaction,
 		proof *bitcoin.SpvProof,
 		mainUTXO bitcoin.UnspentTransactionOutput,
 		walletPublicKeyHash [20]byte,
+		check chain.ReimbursementCheck,
 	) error
 
 	// SubmitMovingFundsProofWithReimbursement submits the moving funds proof
 	// via MaintainerProxy. The caller is reimbursed.
+	// The given check is run against the estimated cost and reimbursement
+	// of the transaction before it is submitted; the check can be nil.
 	SubmitMovingFundsProofWithReimbursement(
 		transaction *bitcoin.Transaction,
 		proof *bitcoin.SpvProof,
 		mainUTXO bitcoin.UnspentTransactionOutput,
 		walletPublicKeyHash [20]byte,
+		check chain.ReimbursementCheck,
 	) error
 
 	// SubmitMovedFundsSweepProofWithReimbursement submits the moved funds sweep
 	//  proof via MaintainerProxy. The caller is reimbursed.
+	// The given check is run against the estimated cost and reimbursement
+	// of the transaction before it is submitted; the check can be nil.
 	SubmitMovedFundsSweepProofWithReimbursement(
 		transaction *bitcoin.Transaction,
 		proof *bitcoin.SpvProof,
 		mainUTXO bitcoin.UnspentTransactionOutput,
+		check chain.ReimbursementCheck,
 	) error
 
+	// OnDepositRevealed registers a callback that is invoked when an on-chain
+	// notification of the deposit reveal is seen.
+	OnDepositRevealed(
+		handler func(event *tbtc.DepositRevealedEvent),
+	) subscription.EventSubscription
+
+	// OnRedemptionRequested registers a callback that is invoked when an
+	// on-chain notification of the redemption request is seen.
+	OnRedemptionRequested(
+		handler func(event *tbtc.RedemptionRequestedEvent),
+	) subscription.EventSubscription
+
+	// OnMovingFundsCommitmentSubmitted registers a callback that is invoked
+	// when an on-chain notification of the moving funds commitment submission
+	// is seen.
+	OnMovingFundsCommitmentSubmitted(
+		handler func(event *tbtc.MovingFundsCommitmentSubmittedEvent),
+	) subscription.EventSubscription
+
 	// PastDepositRevealedEvents fetches past deposit reveal events according
 	// to the provided filter or unfiltered if the filter is nil. Returned
 	// events are sorted by the block number in the ascending order, i.e. the
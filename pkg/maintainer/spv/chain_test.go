@@ -13,6 +13,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/keep-network/keep-core/pkg/bitcoin"
 	"github.com/keep-network/keep-core/pkg/chain"
+	"github.com/keep-network/keep-core/pkg/subscription"
 	"github.com/keep-network/keep-core/pkg/tbtc"
 )
 
@@ -85,6 +86,7 @@ func (lc *localChain) SubmitDepositSweepProofWithReimbursement(
 	proof *bitcoin.SpvProof,
 	mainUTXO bitcoin.UnspentTransactionOutput,
 	vault common.Address,
+	check chain.ReimbursementCheck,
 ) error {
 	lc.mutex.Lock()
 	defer lc.mutex.Unlock()
@@ -260,6 +262,7 @@ func (lc *localChain) SubmitRedemptionProofWithReimbursement(
 	proof *bitcoin.SpvProof,
 	mainUTXO bitcoin.UnspentTransactionOutput,
 	walletPublicKeyHash [20]byte,
+	check chain.ReimbursementCheck,
 ) error {
 	lc.mutex.Lock()
 	defer lc.mutex.Unlock()
@@ -289,6 +292,7 @@ func (lc *localChain) SubmitMovingFundsProofWithReimbursement(
 	proof *bitcoin.SpvProof,
 	mainUTXO bitcoin.UnspentTransactionOutput,
 	walletPublicKeyHash [20]byte,
+	check chain.ReimbursementCheck,
 ) error {
 	lc.mutex.Lock()
 	defer lc.mutex.Unlock()
@@ -317,6 +321,7 @@ func (lc *localChain) SubmitMovedFundsSweepProofWithReimbursement(
 	transaction *bitcoin.Transaction,
 	proof *bitcoin.SpvProof,
 	mainUTXO bitcoin.UnspentTransactionOutput,
+	check chain.ReimbursementCheck,
 ) error {
 	lc.mutex.Lock()
 	defer lc.mutex.Unlock()
@@ -363,7 +368,10 @@ func (lc *localChain) Retarget(headers []*bitcoin.BlockHeader) error {
 	panic("unsupported")
 }
 
-func (lc *localChain) RetargetWithRefund(headers []*bitcoin.BlockHeader) error {
+func (lc *localChain) RetargetWithRefund(
+	headers []*bitcoin.BlockHeader,
+	check chain.ReimbursementCheck,
+) error {
 	panic("unsupported")
 }
 
@@ -419,6 +427,24 @@ func (lc *localChain) setCurrentAndPrevEpochDifficulty(
 	lc.previousEpochDifficulty = previousEpochDifficulty
 }
 
+func (lc *localChain) OnDepositRevealed(
+	handler func(event *tbtc.DepositRevealedEvent),
+) subscription.EventSubscription {
+	panic("unsupported")
+}
+
+func (lc *localChain) OnRedemptionRequested(
+	handler func(event *tbtc.RedemptionRequestedEvent),
+) subscription.EventSubscription {
+	panic("unsupported")
+}
+
+func (lc *localChain) OnMovingFundsCommitmentSubmitted(
+	handler func(event *tbtc.MovingFundsCommitmentSubmittedEvent),
+) subscription.EventSubscription {
+	panic("unsupported")
+}
+
 func (lc *localChain) PastDepositRevealedEvents(
 	filter *tbtc.DepositRevealedEventFilter,
 ) ([]*tbtc.DepositRevealedEvent, error) {
@@ -27,6 +27,12 @@ const (
 
 	// DefaultIdleBackOffTime is the default value for idle back-off time.
 	DefaultIdleBackOffTime = 10 * time.Minute
+
+	// DefaultBitcoinBlockPollInterval is the default value for the interval
+	// of checking for new Bitcoin blocks. The value is much lower than the
+	// average Bitcoin block time so proofs are submitted shortly after
+	// the block making them provable is mined.
+	DefaultBitcoinBlockPollInterval = 30 * time.Second
 )
 
 // Config holds configurable properties.
@@ -63,6 +69,13 @@ type Config struct {
 	RestartBackoffTime time.Duration
 
 	// IdleBackoffTime is a wait time which should be applied when there are no
-	// more transaction proofs to submit.
+	// more transaction proofs to submit. The SPV maintainer looks for
+	// transactions to prove on every new Bitcoin block and every
+	// wallet-related chain event so this wait time applies only if none of
+	// them was observed.
 	IdleBackoffTime time.Duration
+
+	// BitcoinBlockPollInterval is the interval of checking for new Bitcoin
+	// blocks. Every new block may make pending transaction proofs provable.
+	BitcoinBlockPollInterval time.Duration
 }
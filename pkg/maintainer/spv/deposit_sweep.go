@@ -14,18 +14,22 @@ import (
 
 // SubmitDepositSweepProof prepares deposit sweep proof for the given
 // transaction and submits it to the on-chain contract. If the number of required
-// confirmations is `0`, an error is returned.
+// confirmations is `0`, an error is returned. The given
+// check decides whether the proof is worth submitting given the current gas
+// conditions; it can be nil.
 func SubmitDepositSweepProof(
 	transactionHash bitcoin.Hash,
 	requiredConfirmations uint,
 	btcChain bitcoin.Chain,
 	spvChain Chain,
+	check chain.ReimbursementCheck,
 ) error {
 	return submitDepositSweepProof(
 		transactionHash,
 		requiredConfirmations,
 		btcChain,
 		spvChain,
+		check,
 		bitcoin.AssembleSpvProof,
 		getGlobalMetricsRecorder(),
 	)
@@ -36,6 +40,7 @@ func submitDepositSweepProof(
 	requiredConfirmations uint,
 	btcChain bitcoin.Chain,
 	spvChain Chain,
+	check chain.ReimbursementCheck,
 	spvProofAssembler spvProofAssembler,
 	metricsRecorder interface {
 		IncrementCounter(name string, value float64)
@@ -90,6 +95,7 @@ func submitDepositSweepProof(
 		proof,
 		mainUTXO,
 		vault,
+		check,
 	); err != nil {
 		if metricsRecorder != nil {
 			metricsRecorder.IncrementCounter("deposit_sweep_proof_submissions_failed_total", 1)
@@ -95,6 +95,7 @@ func TestSubmitDepositSweepProof(t *testing.T) {
 		requiredConfirmations,
 		btcChain,
 		spvChain,
+		nil,
 		mockSpvProofAssembler,
 		getGlobalMetricsRecorder(),
 	)
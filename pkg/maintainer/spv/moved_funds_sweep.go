@@ -5,23 +5,28 @@ import (
 	"fmt"
 
 	"github.com/keep-network/keep-core/pkg/bitcoin"
+	"github.com/keep-network/keep-core/pkg/chain"
 	"github.com/keep-network/keep-core/pkg/tbtc"
 )
 
 // SubmitMovedFundsSweepProof prepares moved funds sweep proof for the given
 // transaction and submits it to the on-chain contract. If the number of
-// required confirmations is `0`, an error is returned.
+// required confirmations is `0`, an error is returned. The given
+// check decides whether the proof is worth submitting given the current gas
+// conditions; it can be nil.
 func SubmitMovedFundsSweepProof(
 	transactionHash bitcoin.Hash,
 	requiredConfirmations uint,
 	btcChain bitcoin.Chain,
 	spvChain Chain,
+	check chain.ReimbursementCheck,
 ) error {
 	return submitMovedFundsSweepProof(
 		transactionHash,
 		requiredConfirmations,
 		btcChain,
 		spvChain,
+		check,
 		bitcoin.AssembleSpvProof,
 	)
 }
@@ -31,6 +36,7 @@ func submitMovedFundsSweepProof(
 	requiredConfirmations uint,
 	btcChain bitcoin.Chain,
 	spvChain Chain,
+	check chain.ReimbursementCheck,
 	spvProofAssembler spvProofAssembler,
 ) error {
 	if requiredConfirmations == 0 {
@@ -66,6 +72,7 @@ func submitMovedFundsSweepProof(
 		transaction,
 		proof,
 		mainUTXO,
+		check,
 	); err != nil {
 		return fmt.Errorf(
 			"failed to submit moved funds sweep proof with reimbursement: [%v]",
@@ -117,6 +117,7 @@ func TestSubmitMovedFundsSweepProof(t *testing.T) {
 				requiredConfirmations,
 				btcChain,
 				spvChain,
+				nil,
 				mockSpvProofAssembler,
 			)
 			if err != nil {
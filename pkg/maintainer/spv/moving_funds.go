@@ -5,23 +5,28 @@ import (
 	"fmt"
 
 	"github.com/keep-network/keep-core/pkg/bitcoin"
+	"github.com/keep-network/keep-core/pkg/chain"
 	"github.com/keep-network/keep-core/pkg/tbtc"
 )
 
 // SubmitMovingFundsProof prepares moving funds proof for the given
 // transaction and submits it to the on-chain contract. If the number of
-// required confirmations is `0`, an error is returned.
+// required confirmations is `0`, an error is returned. The given
+// check decides whether the proof is worth submitting given the current gas
+// conditions; it can be nil.
 func SubmitMovingFundsProof(
 	transactionHash bitcoin.Hash,
 	requiredConfirmations uint,
 	btcChain bitcoin.Chain,
 	spvChain Chain,
+	check chain.ReimbursementCheck,
 ) error {
 	return submitMovingFundsProof(
 		transactionHash,
 		requiredConfirmations,
 		btcChain,
 		spvChain,
+		check,
 		bitcoin.AssembleSpvProof,
 	)
 }
@@ -31,6 +36,7 @@ func submitMovingFundsProof(
 	requiredConfirmations uint,
 	btcChain bitcoin.Chain,
 	spvChain Chain,
+	check chain.ReimbursementCheck,
 	spvProofAssembler spvProofAssembler,
 ) error {
 	if requiredConfirmations == 0 {
@@ -67,6 +73,7 @@ func submitMovingFundsProof(
 		proof,
 		mainUTXO,
 		walletPublicKeyHash,
+		check,
 	); err != nil {
 		return fmt.Errorf(
 			"failed to submit moving funds proof with reimbursement: [%v]",
@@ -75,6 +75,7 @@ func TestSubmitMovingFundsProof(t *testing.T) {
 		requiredConfirmations,
 		btcChain,
 		spvChain,
+		nil,
 		mockSpvProofAssembler,
 	)
 	if err != nil {
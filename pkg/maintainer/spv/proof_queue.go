@@ -0,0 +1,208 @@
+package spv
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/keep-network/keep-common/pkg/persistence"
+
+	"github.com/keep-network/keep-core/pkg/bitcoin"
+	"github.com/keep-network/keep-core/pkg/tbtc"
+)
+
+// proofQueueDirName is the name of the persistence directory holding
+// pending proofs.
+const proofQueueDirName = "spv"
+
+// pendingProof is an unproven Bitcoin transaction waiting until it
+// accumulates enough confirmations to be proven.
+type pendingProof struct {
+	TransactionHash bitcoin.Hash          `json:"transaction_hash"`
+	Action          tbtc.WalletActionType `json:"action"`
+	// ProvableHeight is the Bitcoin block height at which the transaction
+	// is expected to have accumulated the number of confirmations required
+	// by the proof. Zero means the height is not known yet, for example
+	// because the proof range goes outside the difficulty epochs known
+	// to the relay.
+	ProvableHeight uint `json:"provable_height"`
+}
+
+// isDue returns true if the proof should be evaluated at the given
+// Bitcoin block height.
+func (pp *pendingProof) isDue(blockHeight uint) bool {
+	return pp.ProvableHeight == 0 || pp.ProvableHeight <= blockHeight
+}
+
+// proofQueue is a queue of pending proofs. The queue is persisted using
+// the given persistence handle so pending proofs survive restarts. The handle
+// can be nil in which case the queue is kept only in memory.
+type proofQueue struct {
+	mutex  sync.Mutex
+	handle persistence.BasicHandle
+	proofs map[bitcoin.Hash]*pendingProof
+}
+
+// newProofQueue creates a new proof queue and restores pending proofs
+// persisted using the given handle. Persisted proofs that cannot be read are
+// skipped; they will be queued again once their transactions are found
+// by the maintainer.
+func newProofQueue(handle persistence.BasicHandle) *proofQueue {
+	pq := &proofQueue{
+		handle: handle,
+		proofs: make(map[bitcoin.Hash]*pendingProof),
+	}
+
+	pq.load()
+
+	return pq
+}
+
+func (pq *proofQueue) load() {
+	if pq.handle == nil {
+		return
+	}
+
+	descriptorsChan, errorsChan := pq.handle.ReadAll()
+
+	// Channels are not buffered so descriptors and errors must be read
+	// concurrently.
+	var wg sync.WaitGroup
+	wg.Add(2)
+
+	go func() {
+		defer wg.Done()
+
+		for descriptor := range descriptorsChan {
+			if descriptor.Directory() != proofQueueDirName {
+				continue
+			}
+
+			content, err := descriptor.Content()
+			if err != nil {
+				logger.Errorf(
+					"cannot read pending proof file [%s]: [%v]",
+					descriptor.Name(),
+					err,
+				)
+				continue
+			}
+
+			proof := &pendingProof{}
+			if err := json.Unmarshal(content, proof); err != nil {
+				logger.Errorf(
+					"cannot decode pending proof file [%s]: [%v]",
+					descriptor.Name(),
+					err,
+				)
+				continue
+			}
+
+			pq.proofs[proof.TransactionHash] = proof
+		}
+	}()
+
+	go func() {
+		defer wg.Done()
+
+		for err := range errorsChan {
+			logger.Errorf("cannot read persisted pending proofs: [%v]", err)
+		}
+	}()
+
+	wg.Wait()
+}
+
+// contains returns true if the proof of the given transaction is queued.
+func (pq *proofQueue) contains(transactionHash bitcoin.Hash) bool {
+	pq.mutex.Lock()
+	defer pq.mutex.Unlock()
+
+	_, ok := pq.proofs[transactionHash]
+	return ok
+}
+
+// put adds the given proof to the queue or replaces the queued proof
+// of the same transaction.
+func (pq *proofQueue) put(proof *pendingProof) error {
+	pq.mutex.Lock()
+	defer pq.mutex.Unlock()
+
+	if pq.handle != nil {
+		data, err := json.Marshal(proof)
+		if err != nil {
+			return fmt.Errorf("cannot encode pending proof: [%w]", err)
+		}
+
+		err = pq.handle.Save(
+			data,
+			proofQueueDirName,
+			proofFileName(proof.TransactionHash),
+		)
+		if err != nil {
+			return fmt.Errorf("cannot save pending proof: [%w]", err)
+		}
+	}
+
+	pq.proofs[proof.TransactionHash] = proof
+
+	return nil
+}
+
+// remove removes the proof of the given transaction from the queue.
+func (pq *proofQueue) remove(transactionHash bitcoin.Hash) error {
+	pq.mutex.Lock()
+	defer pq.mutex.Unlock()
+
+	if _, ok := pq.proofs[transactionHash]; !ok {
+		return nil
+	}
+
+	if pq.handle != nil {
+		err := pq.handle.Delete(
+			proofQueueDirName,
+			proofFileName(transactionHash),
+		)
+		if err != nil {
+			return fmt.Errorf("cannot delete pending proof: [%w]", err)
+		}
+	}
+
+	delete(pq.proofs, transactionHash)
+
+	return nil
+}
+
+// list returns queued proofs matching the given predicate, ordered by
+// their provable heights.
+func (pq *proofQueue) list(predicate func(*pendingProof) bool) []*pendingProof {
+	pq.mutex.Lock()
+	defer pq.mutex.Unlock()
+
+	proofs := make([]*pendingProof, 0)
+	for _, proof := range pq.proofs {
+		if predicate(proof) {
+			copied := *proof
+			proofs = append(proofs, &copied)
+		}
+	}
+
+	sort.Slice(proofs, func(i, j int) bool {
+		if proofs[i].ProvableHeight != proofs[j].ProvableHeight {
+			return proofs[i].ProvableHeight < proofs[j].ProvableHeight
+		}
+
+		return bytes.Compare(
+			proofs[i].TransactionHash[:],
+			proofs[j].TransactionHash[:],
+		) < 0
+	})
+
+	return proofs
+}
+
+func proofFileName(transactionHash bitcoin.Hash) string {
+	return transactionHash.Hex(bitcoin.InternalByteOrder)
+}
@@ -0,0 +1,188 @@
+package spv
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/keep-network/keep-common/pkg/persistence"
+
+	"github.com/keep-network/keep-core/internal/testutils"
+	"github.com/keep-network/keep-core/pkg/bitcoin"
+	"github.com/keep-network/keep-core/pkg/tbtc"
+)
+
+func TestProofQueue_Persistence(t *testing.T) {
+	handle := newProofQueuePersistenceHandle()
+
+	queue := newProofQueue(handle)
+
+	proofs := []*pendingProof{
+		{
+			TransactionHash: bitcoin.Hash{0x01},
+			Action:          tbtc.ActionDepositSweep,
+			ProvableHeight:  800010,
+		},
+		{
+			TransactionHash: bitcoin.Hash{0x02},
+			Action:          tbtc.ActionRedemption,
+			ProvableHeight:  0,
+		},
+		{
+			TransactionHash: bitcoin.Hash{0x03},
+			Action:          tbtc.ActionMovingFunds,
+			ProvableHeight:  800005,
+		},
+	}
+
+	for _, proof := range proofs {
+		if err := queue.put(proof); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := queue.remove(bitcoin.Hash{0x03}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Put the corrupted file that should be skipped on restore.
+	if err := handle.Save([]byte("corrupted"), proofQueueDirName, "04"); err != nil {
+		t.Fatal(err)
+	}
+
+	restored := newProofQueue(handle)
+
+	all := func(proof *pendingProof) bool { return true }
+
+	expectedProofs := []*pendingProof{proofs[1], proofs[0]}
+	if !reflect.DeepEqual(expectedProofs, restored.list(all)) {
+		t.Errorf(
+			"unexpected restored proofs\n"+
+				"expected: [%v]\n"+
+				"actual:   [%v]",
+			expectedProofs,
+			restored.list(all),
+		)
+	}
+
+	testutils.AssertBoolsEqual(
+		t,
+		"removed proof presence",
+		false,
+		restored.contains(bitcoin.Hash{0x03}),
+	)
+}
+
+func TestPendingProof_IsDue(t *testing.T) {
+	var tests = map[string]struct {
+		provableHeight uint
+		blockHeight    uint
+		expectedIsDue  bool
+	}{
+		"unknown provable height": {
+			provableHeight: 0,
+			blockHeight:    800000,
+			expectedIsDue:  true,
+		},
+		"provable height not reached": {
+			provableHeight: 800001,
+			blockHeight:    800000,
+			expectedIsDue:  false,
+		},
+		"provable height reached": {
+			provableHeight: 800000,
+			blockHeight:    800000,
+			expectedIsDue:  true,
+		},
+		"provable height exceeded": {
+			provableHeight: 799999,
+			blockHeight:    800000,
+			expectedIsDue:  true,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			proof := &pendingProof{ProvableHeight: test.provableHeight}
+
+			testutils.AssertBoolsEqual(
+				t,
+				"is due",
+				test.expectedIsDue,
+				proof.isDue(test.blockHeight),
+			)
+		})
+	}
+}
+
+type proofQueuePersistenceHandle struct {
+	mutex sync.Mutex
+	files map[string][]byte
+}
+
+func newProofQueuePersistenceHandle() *proofQueuePersistenceHandle {
+	return &proofQueuePersistenceHandle{files: make(map[string][]byte)}
+}
+
+func (pqph *proofQueuePersistenceHandle) Save(
+	data []byte,
+	directory string,
+	name string,
+) error {
+	pqph.mutex.Lock()
+	defer pqph.mutex.Unlock()
+
+	pqph.files[directory+"/"+name] = append([]byte{}, data...)
+	return nil
+}
+
+func (pqph *proofQueuePersistenceHandle) Delete(
+	directory string,
+	name string,
+) error {
+	pqph.mutex.Lock()
+	defer pqph.mutex.Unlock()
+
+	delete(pqph.files, directory+"/"+name)
+	return nil
+}
+
+func (pqph *proofQueuePersistenceHandle) ReadAll() (
+	<-chan persistence.DataDescriptor,
+	<-chan error,
+) {
+	pqph.mutex.Lock()
+	defer pqph.mutex.Unlock()
+
+	dataChan := make(chan persistence.DataDescriptor, len(pqph.files))
+	errorChan := make(chan error)
+
+	for path, content := range pqph.files {
+		directory, name, _ := strings.Cut(path, "/")
+		dataChan <- &proofQueueDataDescriptor{directory, name, content}
+	}
+
+	close(dataChan)
+	close(errorChan)
+
+	return dataChan, errorChan
+}
+
+type proofQueueDataDescriptor struct {
+	directory string
+	name      string
+	content   []byte
+}
+
+func (pqdd *proofQueueDataDescriptor) Name() string {
+	return pqdd.name
+}
+
+func (pqdd *proofQueueDataDescriptor) Directory() string {
+	return pqdd.directory
+}
+
+func (pqdd *proofQueueDataDescriptor) Content() ([]byte, error) {
+	return pqdd.content, nil
+}
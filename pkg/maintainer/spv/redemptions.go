@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/keep-network/keep-core/pkg/bitcoin"
+	"github.com/keep-network/keep-core/pkg/chain"
 	"github.com/keep-network/keep-core/pkg/clientinfo"
 	"github.com/keep-network/keep-core/pkg/tbtc"
 )
@@ -18,18 +19,22 @@ func getGlobalMetricsRecorder() interface {
 
 // SubmitRedemptionProof prepares redemption proof for the given transaction
 // and submits it to the on-chain contract. If the number of required
-// confirmations is `0`, an error is returned.
+// confirmations is `0`, an error is returned. The given
+// check decides whether the proof is worth submitting given the current gas
+// conditions; it can be nil.
 func SubmitRedemptionProof(
 	transactionHash bitcoin.Hash,
 	requiredConfirmations uint,
 	btcChain bitcoin.Chain,
 	spvChain Chain,
+	check chain.ReimbursementCheck,
 ) error {
 	return submitRedemptionProof(
 		transactionHash,
 		requiredConfirmations,
 		btcChain,
 		spvChain,
+		check,
 		bitcoin.AssembleSpvProof,
 		getGlobalMetricsRecorder(),
 	)
@@ -40,6 +45,7 @@ func submitRedemptionProof(
 	requiredConfirmations uint,
 	btcChain bitcoin.Chain,
 	spvChain Chain,
+	check chain.ReimbursementCheck,
 	spvProofAssembler spvProofAssembler,
 	metricsRecorder interface {
 		IncrementCounter(name string, value float64)
@@ -93,6 +99,7 @@ func submitRedemptionProof(
 		proof,
 		mainUTXO,
 		walletPublicKeyHash,
+		check,
 	); err != nil {
 		if metricsRecorder != nil {
 			metricsRecorder.IncrementCounter(clientinfo.MetricRedemptionProofSubmissionsFailedTotal, 1)
@@ -77,6 +77,7 @@ func TestSubmitRedemptionProof(t *testing.T) {
 		requiredConfirmations,
 		btcChain,
 		spvChain,
+		nil,
 		mockSpvProofAssembler,
 		getGlobalMetricsRecorder(),
 	)
@@ -12,9 +12,13 @@ import (
 	"github.com/keep-network/keep-core/pkg/tbtc"
 
 	"github.com/ipfs/go-log/v2"
+	"github.com/keep-network/keep-common/pkg/persistence"
 
 	"github.com/keep-network/keep-core/pkg/bitcoin"
+	"github.com/keep-network/keep-core/pkg/chain"
 	"github.com/keep-network/keep-core/pkg/maintainer/btcdiff"
+	"github.com/keep-network/keep-core/pkg/maintainer/profitability"
+	"github.com/keep-network/keep-core/pkg/subscription"
 )
 
 var logger = log.Logger("keep-maintainer-spv")
@@ -28,12 +32,17 @@ func Initialize(
 	spvChain Chain,
 	btcDiffChain btcdiff.Chain,
 	btcChain bitcoin.Chain,
+	workPersistence persistence.BasicHandle,
+	guard *profitability.Guard,
 ) {
 	spvMaintainer := &spvMaintainer{
 		config:       config,
 		spvChain:     spvChain,
 		btcDiffChain: btcDiffChain,
 		btcChain:     btcChain,
+		proofTypes:   proofTypes,
+		queue:        newProofQueue(workPersistence),
+		guard:        guard,
 	}
 
 	go spvMaintainer.startControlLoop(ctx)
@@ -67,12 +76,16 @@ func getMetricsRecorder() interface {
 	return globalMetricsRecorder
 }
 
-// proofTypes holds the information about proof types supported by the
-// SPV maintainer.
-var proofTypes = map[tbtc.WalletActionType]struct {
+// proofType holds the functions used to find and prove Bitcoin transactions
+// of the given wallet action type.
+type proofType struct {
 	unprovenTransactionsGetter unprovenTransactionsGetter
 	transactionProofSubmitter  transactionProofSubmitter
-}{
+}
+
+// proofTypes holds the information about proof types supported by the
+// SPV maintainer.
+var proofTypes = map[tbtc.WalletActionType]proofType{
 	tbtc.ActionDepositSweep: {
 		unprovenTransactionsGetter: getUnprovenDepositSweepTransactions,
 		transactionProofSubmitter:  SubmitDepositSweepProof,
@@ -96,6 +109,15 @@ type spvMaintainer struct {
 	spvChain     Chain
 	btcDiffChain btcdiff.Chain
 	btcChain     bitcoin.Chain
+
+	proofTypes map[tbtc.WalletActionType]proofType
+	// queue holds unproven transactions found by the maintainer until
+	// they accumulate enough confirmations to be proven.
+	queue *proofQueue
+	// guard decides whether proofs are worth submitting given the current
+	// gas conditions. It can be nil in which case proofs are always
+	// submitted.
+	guard *profitability.Guard
 }
 
 func (sm *spvMaintainer) startControlLoop(ctx context.Context) {
@@ -122,31 +144,71 @@ func (sm *spvMaintainer) startControlLoop(ctx context.Context) {
 	}
 }
 
+// maintainSpv looks for unproven transactions and submits their proofs once
+// they become provable. Unproven transactions are looked up on every new
+// Bitcoin block and on every wallet-related chain event announcing a new
+// wallet transaction. If none of them is observed, the lookup is repeated
+// after the idle back-off time.
 func (sm *spvMaintainer) maintainSpv(ctx context.Context) error {
-	for {
-		for action, v := range proofTypes {
-			logger.Infof("starting [%s] proof task execution...", action)
-
-			if err := sm.proveTransactions(
-				v.unprovenTransactionsGetter,
-				v.transactionProofSubmitter,
-			); err != nil {
-				return fmt.Errorf(
-					"error while proving [%s] transactions: [%v]",
-					action,
-					err,
-				)
-			}
+	loopCtx, cancelLoopCtx := context.WithCancel(ctx)
+	defer cancelLoopCtx()
 
-			logger.Infof("[%s] proof task completed", action)
+	walletEventsChan := make(chan struct{}, 1)
+	onWalletEvent := func() {
+		// Do not block the event handler if the previous event has not been
+		// handled yet. A single lookup will cover both.
+		select {
+		case walletEventsChan <- struct{}{}:
+		default:
 		}
+	}
 
-		logger.Infof(
-			"proof tasks completed; next run in [%s]",
-			sm.config.IdleBackoffTime,
-		)
+	subscriptions := []subscription.EventSubscription{
+		sm.spvChain.OnDepositRevealed(
+			func(event *tbtc.DepositRevealedEvent) {
+				onWalletEvent()
+			},
+		),
+		sm.spvChain.OnRedemptionRequested(
+			func(event *tbtc.RedemptionRequestedEvent) {
+				onWalletEvent()
+			},
+		),
+		sm.spvChain.OnMovingFundsCommitmentSubmitted(
+			func(event *tbtc.MovingFundsCommitmentSubmittedEvent) {
+				onWalletEvent()
+			},
+		),
+	}
+	defer func() {
+		for _, eventSubscription := range subscriptions {
+			eventSubscription.Unsubscribe()
+		}
+	}()
+
+	blocksChan := watchBitcoinBlocks(
+		loopCtx,
+		sm.btcChain,
+		sm.config.BitcoinBlockPollInterval,
+	)
+
+	for {
+		if err := sm.proveTransactions(); err != nil {
+			return err
+		}
 
 		select {
+		case blockHeight := <-blocksChan:
+			logger.Infof(
+				"observed new Bitcoin block [%d]; looking for "+
+					"transactions to prove",
+				blockHeight,
+			)
+		case <-walletEventsChan:
+			logger.Infof(
+				"observed wallet-related event; looking for " +
+					"transactions to prove",
+			)
 		case <-time.After(sm.config.IdleBackoffTime):
 		case <-ctx.Done():
 			return ctx.Err()
@@ -154,6 +216,62 @@ func (sm *spvMaintainer) maintainSpv(ctx context.Context) error {
 	}
 }
 
+// watchBitcoinBlocks polls the Bitcoin chain with the given interval and
+// notifies about new blocks using the returned channel. The channel is
+// buffered so only the latest block is delivered if the receiver does not
+// keep up.
+func watchBitcoinBlocks(
+	ctx context.Context,
+	btcChain bitcoin.Chain,
+	pollInterval time.Duration,
+) <-chan uint {
+	blocksChan := make(chan uint, 1)
+
+	// Blocks mined before the watcher started are not reported. If the
+	// height cannot be determined, the first observed block is reported.
+	latestBlockHeight, err := btcChain.GetLatestBlockHeight()
+	if err != nil {
+		logger.Warnf("failed to get latest Bitcoin block height: [%v]", err)
+	}
+
+	go func() {
+		ticker := time.NewTicker(pollInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				blockHeight, err := btcChain.GetLatestBlockHeight()
+				if err != nil {
+					logger.Warnf(
+						"failed to get latest Bitcoin block height: [%v]",
+						err,
+					)
+					continue
+				}
+
+				if blockHeight <= latestBlockHeight {
+					continue
+				}
+
+				latestBlockHeight = blockHeight
+
+				// Drop the stale notification, if any.
+				select {
+				case <-blocksChan:
+				default:
+				}
+
+				blocksChan <- blockHeight
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return blocksChan
+}
+
 // unprovenTransactionsGetter is a type representing a function that is
 // used to get unproven Bitcoin transactions.
 type unprovenTransactionsGetter func(
@@ -173,14 +291,45 @@ type transactionProofSubmitter func(
 	requiredConfirmations uint,
 	btcChain bitcoin.Chain,
 	spvChain Chain,
+	check chain.ReimbursementCheck,
 ) error
 
-// proveTransactions gets unproven Bitcoin transactions using the provided
-// unprovenTransactionsGetter, build the SPV proofs, and submits them using
-// the provided transactionProofSubmitter.
-func (sm *spvMaintainer) proveTransactions(
+// proveTransactions queues unproven transactions of all proof types and
+// submits proofs of queued transactions that accumulated enough
+// confirmations.
+func (sm *spvMaintainer) proveTransactions() error {
+	for action, v := range sm.proofTypes {
+		logger.Infof("looking for unproven [%s] transactions...", action)
+
+		if err := sm.queueTransactions(
+			action,
+			v.unprovenTransactionsGetter,
+		); err != nil {
+			return fmt.Errorf(
+				"error while looking for unproven [%s] transactions: [%v]",
+				action,
+				err,
+			)
+		}
+	}
+
+	if err := sm.submitProofs(); err != nil {
+		return fmt.Errorf("error while submitting proofs: [%v]", err)
+	}
+
+	logger.Infof("finished round of proving transactions")
+
+	return nil
+}
+
+// queueTransactions gets unproven Bitcoin transactions of the given action
+// type using the provided unprovenTransactionsGetter and synchronizes the
+// proof queue with them. Newly found transactions are added to the queue.
+// Queued transactions that are no longer unproven, for example because they
+// were proven by another maintainer, are removed from the queue.
+func (sm *spvMaintainer) queueTransactions(
+	action tbtc.WalletActionType,
 	unprovenTransactionsGetter unprovenTransactionsGetter,
-	transactionProofSubmitter transactionProofSubmitter,
 ) error {
 	transactions, err := unprovenTransactionsGetter(
 		sm.config.HistoryDepth,
@@ -194,17 +343,80 @@ func (sm *spvMaintainer) proveTransactions(
 
 	logger.Infof("found [%d] unproven transaction(s)", len(transactions))
 
+	unproven := make(map[bitcoin.Hash]struct{})
 	for _, transaction := range transactions {
-		// Print the transaction in the same endianness as block explorers do.
-		transactionHashStr := transaction.Hash().Hex(bitcoin.ReversedByteOrder)
+		transactionHash := transaction.Hash()
+		unproven[transactionHash] = struct{}{}
+
+		if sm.queue.contains(transactionHash) {
+			continue
+		}
+
+		err := sm.queue.put(&pendingProof{
+			TransactionHash: transactionHash,
+			Action:          action,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to queue transaction: [%v]", err)
+		}
 
 		logger.Infof(
-			"proceeding with proof for transaction [%s]",
-			transactionHashStr,
+			"queued proof for transaction [%s]",
+			// Print the transaction in the same endianness as block
+			// explorers do.
+			transactionHash.Hex(bitcoin.ReversedByteOrder),
+		)
+	}
+
+	queued := sm.queue.list(func(proof *pendingProof) bool {
+		return proof.Action == action
+	})
+	for _, proof := range queued {
+		if _, ok := unproven[proof.TransactionHash]; ok {
+			continue
+		}
+
+		if err := sm.queue.remove(proof.TransactionHash); err != nil {
+			return fmt.Errorf("failed to remove proved transaction: [%v]", err)
+		}
+
+		sm.guard.Forget(
+			proof.TransactionHash.Hex(bitcoin.ReversedByteOrder),
 		)
+	}
+
+	return nil
+}
+
+// submitProofs submits proofs of queued transactions that accumulated enough
+// confirmations at the current Bitcoin block height. Provable heights of
+// remaining transactions are updated so they are evaluated again exactly
+// once they can be proven.
+func (sm *spvMaintainer) submitProofs() error {
+	blockHeight, err := sm.btcChain.GetLatestBlockHeight()
+	if err != nil {
+		return fmt.Errorf("failed to get latest block height: [%v]", err)
+	}
+
+	dueProofs := sm.queue.list(func(proof *pendingProof) bool {
+		return proof.isDue(blockHeight)
+	})
+
+	for _, proof := range dueProofs {
+		// Print the transaction in the same endianness as block explorers do.
+		transactionHashStr := proof.TransactionHash.Hex(bitcoin.ReversedByteOrder)
+
+		v, ok := sm.proofTypes[proof.Action]
+		if !ok {
+			return fmt.Errorf(
+				"unsupported proof type [%s] of transaction [%s]",
+				proof.Action,
+				transactionHashStr,
+			)
+		}
 
 		isProofWithinRelayRange, accumulatedConfirmations, requiredConfirmations, err := getProofInfo(
-			transaction.Hash(),
+			proof.TransactionHash,
 			sm.btcChain,
 			sm.spvChain,
 			sm.btcDiffChain,
@@ -215,36 +427,75 @@ func (sm *spvMaintainer) proveTransactions(
 
 		if !isProofWithinRelayRange {
 			// The required proof goes outside the previous and current
-			// difficulty epochs as seen by the relay. Skip the transaction. It
-			// will most likely be proven later.
+			// difficulty epochs as seen by the relay. Keep the transaction
+			// in the queue and evaluate it again at the next block. It will
+			// most likely be proven later.
 			logger.Warnf(
 				"skipped proving transaction [%s]; the range "+
 					"of the required proof goes outside the previous and "+
 					"current difficulty epochs as seen by the relay",
 				transactionHashStr,
 			)
+
+			proof.ProvableHeight = 0
+			if err := sm.queue.put(proof); err != nil {
+				return fmt.Errorf("failed to update queued transaction: [%v]", err)
+			}
+
 			continue
 		}
 
 		if accumulatedConfirmations < requiredConfirmations {
-			// Skip the transaction as it has not accumulated enough
-			// confirmations. It will be proven later.
+			// Keep the transaction in the queue until the block at which
+			// it accumulates enough confirmations.
+			proof.ProvableHeight = blockHeight +
+				requiredConfirmations - accumulatedConfirmations
+
 			logger.Infof(
-				"skipped proving transaction [%s]; transaction "+
-					"has [%v/%v] confirmations",
+				"postponed proving transaction [%s]; transaction "+
+					"has [%v/%v] confirmations and will be proven at "+
+					"block [%v]",
 				transactionHashStr,
 				accumulatedConfirmations,
 				requiredConfirmations,
+				proof.ProvableHeight,
 			)
+
+			if err := sm.queue.put(proof); err != nil {
+				return fmt.Errorf("failed to update queued transaction: [%v]", err)
+			}
+
 			continue
 		}
 
-		err = transactionProofSubmitter(
-			transaction.Hash(),
+		logger.Infof(
+			"proceeding with proof for transaction [%s]",
+			transactionHashStr,
+		)
+
+		postponed := false
+		check := sm.guard.Check(transactionHashStr, func() {
+			postponed = true
+		})
+
+		err = v.transactionProofSubmitter(
+			proof.TransactionHash,
 			requiredConfirmations,
 			sm.btcChain,
 			sm.spvChain,
+			check,
 		)
+		if err != nil && postponed {
+			// The proof is not worth submitting under the current gas
+			// conditions. Keep the transaction in the queue so it is
+			// evaluated again at the next block.
+			logger.Infof(
+				"postponed proving transaction [%s]: [%v]",
+				transactionHashStr,
+				err,
+			)
+			continue
+		}
 		if err != nil {
 			return err
 		}
@@ -253,9 +504,13 @@ func (sm *spvMaintainer) proveTransactions(
 			"successfully submitted proof for transaction [%s]",
 			transactionHashStr,
 		)
-	}
 
-	logger.Infof("finished round of proving transactions")
+		if err := sm.queue.remove(proof.TransactionHash); err != nil {
+			return fmt.Errorf("failed to remove proved transaction: [%v]", err)
+		}
+
+		sm.guard.Forget(transactionHashStr)
+	}
 
 	return nil
 }
@@ -2,12 +2,15 @@ package spv
 
 import (
 	"encoding/hex"
+	"fmt"
 	"math/big"
 	"reflect"
 	"testing"
 
 	"github.com/keep-network/keep-core/internal/testutils"
 	"github.com/keep-network/keep-core/pkg/bitcoin"
+	"github.com/keep-network/keep-core/pkg/chain"
+	"github.com/keep-network/keep-core/pkg/maintainer/profitability"
 	"github.com/keep-network/keep-core/pkg/tbtc"
 )
 
@@ -324,3 +327,268 @@ func TestIsInputCurrentWalletsMainUTXO(t *testing.T) {
 		})
 	}
 }
+
+func TestSpvMaintainer_ProveTransactions(t *testing.T) {
+	provenTransaction := &bitcoin.Transaction{Version: 1, Locktime: 1}
+	pendingTransaction := &bitcoin.Transaction{Version: 1, Locktime: 2}
+	// A transaction queued before but no longer reported as unproven,
+	// for example because it was proven by another maintainer.
+	staleTransactionHash := bitcoin.Hash{0x01}
+
+	spvChain := newLocalChain()
+	spvChain.setTxProofDifficultyFactor(big.NewInt(6))
+	// Block 800000 belongs to the difficulty epoch 396.
+	spvChain.setCurrentEpoch(396)
+
+	btcChain := newLocalBitcoinChain()
+	btcChain.addBlockHeader(800000, &bitcoin.BlockHeader{})
+	btcChain.addTransactionConfirmations(provenTransaction.Hash(), 6)
+	btcChain.addTransactionConfirmations(pendingTransaction.Hash(), 2)
+
+	unprovenTransactions := []*bitcoin.Transaction{
+		provenTransaction,
+		pendingTransaction,
+	}
+	submittedTransactions := make([]bitcoin.Hash, 0)
+
+	maintainer := &spvMaintainer{
+		config: Config{
+			HistoryDepth:     DefaultHistoryDepth,
+			TransactionLimit: DefaultTransactionLimit,
+		},
+		spvChain:     spvChain,
+		btcDiffChain: spvChain,
+		btcChain:     btcChain,
+		proofTypes: map[tbtc.WalletActionType]proofType{
+			tbtc.ActionDepositSweep: {
+				unprovenTransactionsGetter: func(
+					historyDepth uint64,
+					transactionLimit int,
+					btcChain bitcoin.Chain,
+					spvChain Chain,
+				) ([]*bitcoin.Transaction, error) {
+					return unprovenTransactions, nil
+				},
+				transactionProofSubmitter: func(
+					transactionHash bitcoin.Hash,
+					requiredConfirmations uint,
+					btcChain bitcoin.Chain,
+					spvChain Chain,
+					check chain.ReimbursementCheck,
+				) error {
+					submittedTransactions = append(
+						submittedTransactions,
+						transactionHash,
+					)
+					return nil
+				},
+			},
+		},
+		queue: newProofQueue(nil),
+	}
+
+	err := maintainer.queue.put(&pendingProof{
+		TransactionHash: staleTransactionHash,
+		Action:          tbtc.ActionDepositSweep,
+		ProvableHeight:  800000,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	all := func(proof *pendingProof) bool { return true }
+
+	err = maintainer.proveTransactions()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedSubmittedTransactions := []bitcoin.Hash{provenTransaction.Hash()}
+	if !reflect.DeepEqual(expectedSubmittedTransactions, submittedTransactions) {
+		t.Errorf(
+			"unexpected submitted transactions\n"+
+				"expected: [%v]\n"+
+				"actual:   [%v]",
+			expectedSubmittedTransactions,
+			submittedTransactions,
+		)
+	}
+
+	expectedQueue := []*pendingProof{
+		{
+			TransactionHash: pendingTransaction.Hash(),
+			Action:          tbtc.ActionDepositSweep,
+			ProvableHeight:  800004,
+		},
+	}
+	if !reflect.DeepEqual(expectedQueue, maintainer.queue.list(all)) {
+		t.Errorf(
+			"unexpected queue\n"+
+				"expected: [%v]\n"+
+				"actual:   [%v]",
+			expectedQueue,
+			maintainer.queue.list(all),
+		)
+	}
+
+	// The pending transaction is not proven before its provable height
+	// is reached.
+	unprovenTransactions = []*bitcoin.Transaction{pendingTransaction}
+	btcChain.addBlockHeader(800003, &bitcoin.BlockHeader{})
+	btcChain.transactionConfirmations[pendingTransaction.Hash()] = 5
+
+	err = maintainer.proveTransactions()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertIntsEqual(
+		t,
+		"submitted transactions count",
+		1,
+		len(submittedTransactions),
+	)
+
+	// The pending transaction is proven once the provable height is reached.
+	btcChain.addBlockHeader(800004, &bitcoin.BlockHeader{})
+	btcChain.transactionConfirmations[pendingTransaction.Hash()] = 6
+
+	err = maintainer.proveTransactions()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedSubmittedTransactions = append(
+		expectedSubmittedTransactions,
+		pendingTransaction.Hash(),
+	)
+	if !reflect.DeepEqual(expectedSubmittedTransactions, submittedTransactions) {
+		t.Errorf(
+			"unexpected submitted transactions\n"+
+				"expected: [%v]\n"+
+				"actual:   [%v]",
+			expectedSubmittedTransactions,
+			submittedTransactions,
+		)
+	}
+
+	testutils.AssertIntsEqual(
+		t,
+		"queue length",
+		0,
+		len(maintainer.queue.list(all)),
+	)
+}
+
+func TestSpvMaintainer_ProveTransactions_Unprofitable(t *testing.T) {
+	transaction := &bitcoin.Transaction{Version: 1, Locktime: 1}
+
+	spvChain := newLocalChain()
+	spvChain.setTxProofDifficultyFactor(big.NewInt(6))
+	// Block 800000 belongs to the difficulty epoch 396.
+	spvChain.setCurrentEpoch(396)
+
+	btcChain := newLocalBitcoinChain()
+	btcChain.addBlockHeader(800000, &bitcoin.BlockHeader{})
+	btcChain.addTransactionConfirmations(transaction.Hash(), 6)
+
+	estimate := &chain.ReimbursementEstimate{
+		GasPrice:      big.NewInt(200),
+		MaxGasPrice:   big.NewInt(100),
+		Cost:          big.NewInt(2000),
+		Reimbursement: big.NewInt(1000),
+	}
+	submittedTransactions := make([]bitcoin.Hash, 0)
+
+	maintainer := &spvMaintainer{
+		config: Config{
+			HistoryDepth:     DefaultHistoryDepth,
+			TransactionLimit: DefaultTransactionLimit,
+		},
+		spvChain:     spvChain,
+		btcDiffChain: spvChain,
+		btcChain:     btcChain,
+		proofTypes: map[tbtc.WalletActionType]proofType{
+			tbtc.ActionDepositSweep: {
+				unprovenTransactionsGetter: func(
+					historyDepth uint64,
+					transactionLimit int,
+					btcChain bitcoin.Chain,
+					spvChain Chain,
+				) ([]*bitcoin.Transaction, error) {
+					return []*bitcoin.Transaction{transaction}, nil
+				},
+				transactionProofSubmitter: func(
+					transactionHash bitcoin.Hash,
+					requiredConfirmations uint,
+					btcChain bitcoin.Chain,
+					spvChain Chain,
+					check chain.ReimbursementCheck,
+				) error {
+					if err := check(estimate); err != nil {
+						return fmt.Errorf("check failed: [%v]", err)
+					}
+
+					submittedTransactions = append(
+						submittedTransactions,
+						transactionHash,
+					)
+					return nil
+				},
+			},
+		},
+		queue: newProofQueue(nil),
+		guard: profitability.NewGuard(profitability.Config{
+			Policy: profitability.PolicyWait,
+		}),
+	}
+
+	all := func(proof *pendingProof) bool { return true }
+
+	// The unprofitable proof is postponed and stays in the queue.
+	err := maintainer.proveTransactions()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertIntsEqual(
+		t,
+		"submitted transactions count",
+		0,
+		len(submittedTransactions),
+	)
+	testutils.AssertIntsEqual(
+		t,
+		"queue length",
+		1,
+		len(maintainer.queue.list(all)),
+	)
+
+	// The proof is submitted once it becomes profitable.
+	estimate.GasPrice = big.NewInt(50)
+	estimate.Cost = big.NewInt(500)
+	estimate.Reimbursement = big.NewInt(600)
+
+	err = maintainer.proveTransactions()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedSubmittedTransactions := []bitcoin.Hash{transaction.Hash()}
+	if !reflect.DeepEqual(expectedSubmittedTransactions, submittedTransactions) {
+		t.Errorf(
+			"unexpected submitted transactions\n"+
+				"expected: [%v]\n"+
+				"actual:   [%v]",
+			expectedSubmittedTransactions,
+			submittedTransactions,
+		)
+	}
+
+	testutils.AssertIntsEqual(
+		t,
+		"queue length",
+		0,
+		len(maintainer.queue.list(all)),
+	)
+}
@@ -0,0 +1,153 @@
+package watcher
+
+import (
+	"math/big"
+
+	"github.com/keep-network/keep-core/pkg/bitcoin"
+	"github.com/keep-network/keep-core/pkg/chain"
+	"github.com/keep-network/keep-core/pkg/tbtc"
+)
+
+// Chain is an interface that provides the ability to communicate with the
+// Bridge on-chain contract.
+type Chain interface {
+	// BlockCounter returns the chain's block counter.
+	BlockCounter() (chain.BlockCounter, error)
+
+	// GetWallet gets the on-chain data for the given wallet. Returns an error
+	// if the wallet was not found.
+	GetWallet(walletPublicKeyHash [20]byte) (*tbtc.WalletChainData, error)
+
+	// GetWalletOperators returns the addresses of operators controlling the
+	// given wallet's members, in the order of the members. An operator
+	// controlling multiple members occurs on the list multiple times.
+	GetWalletOperators(ecdsaWalletID [32]byte) ([]chain.Address, error)
+
+	// GetOperatorID returns the operator ID for the given operator address.
+	GetOperatorID(operatorAddress chain.Address) (chain.OperatorID, error)
+
+	// GetPendingRedemptionRequest gets the on-chain pending redemption request
+	// for the given wallet public key hash and redeemer output script.
+	// The returned bool value indicates whether the request was found or not.
+	GetPendingRedemptionRequest(
+		walletPublicKeyHash [20]byte,
+		redeemerOutputScript bitcoin.Script,
+	) (*tbtc.RedemptionRequest, bool, error)
+
+	// GetDepositRequest gets the on-chain deposit request for the given
+	// funding transaction hash and output index. The returned bool value
+	// indicates whether the request was found or not.
+	GetDepositRequest(
+		fundingTxHash bitcoin.Hash,
+		fundingOutputIndex uint32,
+	) (*tbtc.DepositChainRequest, bool, error)
+
+	// IsMainUtxoSpent checks whether the given outpoint was spent as the main
+	// UTXO of a wallet by a transaction proven to the Bridge.
+	IsMainUtxoSpent(outpoint *bitcoin.TransactionOutpoint) (bool, error)
+
+	// GetMovedFundsSweepRequest gets the on-chain moved funds sweep request for
+	// the given moving funds transaction hash and output index.
+	// The returned bool value indicates whether the request was found or not.
+	GetMovedFundsSweepRequest(
+		movingFundsTxHash bitcoin.Hash,
+		movingFundsTxOutpointIndex uint32,
+	) (*tbtc.MovedFundsSweepRequest, bool, error)
+
+	// GetRedemptionParameters gets the current value of parameters relevant
+	// for the redemption process.
+	GetRedemptionParameters() (
+		dustThreshold uint64,
+		treasuryFeeDivisor uint64,
+		txMaxFee uint64,
+		txMaxTotalFee uint64,
+		timeout uint32,
+		timeoutSlashingAmount *big.Int,
+		timeoutNotifierRewardMultiplier uint32,
+		err error,
+	)
+
+	// GetMovingFundsParameters gets the current value of parameters relevant
+	// for the moving funds process.
+	GetMovingFundsParameters() (
+		txMaxTotalFee uint64,
+		dustThreshold uint64,
+		timeoutResetDelay uint32,
+		timeout uint32,
+		timeoutSlashingAmount *big.Int,
+		timeoutNotifierRewardMultiplier uint32,
+		commitmentGasOffset uint16,
+		sweepTxMaxTotalFee uint64,
+		sweepTimeout uint32,
+		sweepTimeoutSlashingAmount *big.Int,
+		sweepTimeoutNotifierRewardMultiplier uint32,
+		err error,
+	)
+
+	// PastNewWalletRegisteredEvents fetches past new wallet registered events
+	// according to the provided filter or unfiltered if the filter is nil.
+	// Returned events are sorted by the block number in the ascending order,
+	// i.e. the latest event is at the end of the slice.
+	PastNewWalletRegisteredEvents(
+		filter *tbtc.NewWalletRegisteredEventFilter,
+	) ([]*tbtc.NewWalletRegisteredEvent, error)
+
+	// PastDepositRevealedEvents fetches past deposit reveal events according
+	// to the provided filter or unfiltered if the filter is nil. Returned
+	// events are sorted by the block number in the ascending order, i.e. the
+	// latest event is at the end of the slice.
+	PastDepositRevealedEvents(
+		filter *tbtc.DepositRevealedEventFilter,
+	) ([]*tbtc.DepositRevealedEvent, error)
+
+	// PastRedemptionRequestedEvents fetches past redemption requested events
+	// according to the provided filter or unfiltered if the filter is nil.
+	// Returned events are sorted by the block number in the ascending order,
+	// i.e. the latest event is at the end of the slice.
+	PastRedemptionRequestedEvents(
+		filter *tbtc.RedemptionRequestedEventFilter,
+	) ([]*tbtc.RedemptionRequestedEvent, error)
+
+	// PastMovingFundsCommitmentSubmittedEvents fetches past moving funds
+	// commitment submitted events according to the provided filter or
+	// unfiltered if the filter is nil. Returned events are sorted by the block
+	// number in the ascending order, i.e. the latest event is at the end of the
+	// slice.
+	PastMovingFundsCommitmentSubmittedEvents(
+		filter *tbtc.MovingFundsCommitmentSubmittedEventFilter,
+	) ([]*tbtc.MovingFundsCommitmentSubmittedEvent, error)
+
+	// PastMovingFundsCompletedEvents fetches past moving funds completed events
+	// according to the provided filter or unfiltered if the filter is nil.
+	// Returned events are sorted by the block number in the ascending order,
+	// i.e. the latest event is at the end of the slice.
+	PastMovingFundsCompletedEvents(
+		filter *tbtc.MovingFundsCompletedEventFilter,
+	) ([]*tbtc.MovingFundsCompletedEvent, error)
+
+	// NotifyRedemptionTimeout notifies the Bridge about the timed out
+	// redemption request. The wallet operators are slashed and the caller
+	// is rewarded.
+	NotifyRedemptionTimeout(
+		walletPublicKeyHash [20]byte,
+		walletMembersIDs []uint32,
+		redeemerOutputScript bitcoin.Script,
+	) error
+
+	// NotifyMovingFundsTimeout notifies the Bridge about the timed out moving
+	// funds process of the given wallet. The wallet operators are slashed and
+	// the caller is rewarded.
+	NotifyMovingFundsTimeout(
+		walletPublicKeyHash [20]byte,
+		walletMembersIDs []uint32,
+	) error
+
+	// NotifyMovedFundsSweepTimeout notifies the Bridge about the timed out
+	// moved funds sweep request. The operators of the wallet the funds were
+	// moved to are slashed and the caller is rewarded.
+	NotifyMovedFundsSweepTimeout(
+		movingFundsTxHash bitcoin.Hash,
+		movingFundsTxOutputIndex uint32,
+		walletMembersIDs []uint32,
+	) error
+}
@@ -0,0 +1,586 @@
+package watcher
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/keep-network/keep-core/pkg/bitcoin"
+	"github.com/keep-network/keep-core/pkg/chain"
+	"github.com/keep-network/keep-core/pkg/tbtc"
+)
+
+type notifiedRedemptionTimeout struct {
+	walletPublicKeyHash  [20]byte
+	walletMembersIDs     []uint32
+	redeemerOutputScript bitcoin.Script
+}
+
+type notifiedMovingFundsTimeout struct {
+	walletPublicKeyHash [20]byte
+	walletMembersIDs    []uint32
+}
+
+type notifiedMovedFundsSweepTimeout struct {
+	movingFundsTxHash        bitcoin.Hash
+	movingFundsTxOutputIndex uint32
+	walletMembersIDs         []uint32
+}
+
+type localChain struct {
+	mutex sync.Mutex
+
+	blockCounter              chain.BlockCounter
+	wallets                   map[[20]byte]*tbtc.WalletChainData
+	walletOperators           map[[32]byte][]chain.Address
+	operatorIDs               map[chain.Address]chain.OperatorID
+	walletOperatorsCalls      int
+	pendingRedemptionRequests map[string]*tbtc.RedemptionRequest
+	movedFundsSweepRequests   map[string]*tbtc.MovedFundsSweepRequest
+	depositRequests           map[string]*tbtc.DepositChainRequest
+	spentMainUtxos            map[bitcoin.TransactionOutpoint]bool
+
+	redemptionTimeout      uint32
+	movingFundsTimeout     uint32
+	movedFundsSweepTimeout uint32
+
+	pastNewWalletRegisteredEvents            []*tbtc.NewWalletRegisteredEvent
+	pastDepositRevealedEvents                []*tbtc.DepositRevealedEvent
+	pastRedemptionRequestedEvents            []*tbtc.RedemptionRequestedEvent
+	pastMovingFundsCommitmentSubmittedEvents []*tbtc.MovingFundsCommitmentSubmittedEvent
+	pastMovingFundsCompletedEvents           []*tbtc.MovingFundsCompletedEvent
+
+	notifiedRedemptionTimeouts      []*notifiedRedemptionTimeout
+	notifiedMovingFundsTimeouts     []*notifiedMovingFundsTimeout
+	notifiedMovedFundsSweepTimeouts []*notifiedMovedFundsSweepTimeout
+}
+
+func newLocalChain() *localChain {
+	blockCounter := newMockBlockCounter()
+	blockCounter.SetCurrentBlock(1000)
+
+	return &localChain{
+		blockCounter:              blockCounter,
+		wallets:                   make(map[[20]byte]*tbtc.WalletChainData),
+		walletOperators:           make(map[[32]byte][]chain.Address),
+		operatorIDs:               make(map[chain.Address]chain.OperatorID),
+		pendingRedemptionRequests: make(map[string]*tbtc.RedemptionRequest),
+		movedFundsSweepRequests:   make(map[string]*tbtc.MovedFundsSweepRequest),
+		depositRequests:           make(map[string]*tbtc.DepositChainRequest),
+		spentMainUtxos:            make(map[bitcoin.TransactionOutpoint]bool),
+	}
+}
+
+func (lc *localChain) BlockCounter() (chain.BlockCounter, error) {
+	return lc.blockCounter, nil
+}
+
+func (lc *localChain) GetWallet(
+	walletPublicKeyHash [20]byte,
+) (*tbtc.WalletChainData, error) {
+	lc.mutex.Lock()
+	defer lc.mutex.Unlock()
+
+	wallet, ok := lc.wallets[walletPublicKeyHash]
+	if !ok {
+		return nil, fmt.Errorf("no wallet for given PKH")
+	}
+
+	return wallet, nil
+}
+
+// addWallet registers the given wallet along with its members IDs. Each
+// member is controlled by an operator whose address is derived from the
+// member ID.
+func (lc *localChain) addWallet(
+	walletPublicKeyHash [20]byte,
+	wallet *tbtc.WalletChainData,
+	walletMembersIDs []uint32,
+) {
+	lc.mutex.Lock()
+	defer lc.mutex.Unlock()
+
+	lc.wallets[walletPublicKeyHash] = wallet
+	walletOperators := make([]chain.Address, len(walletMembersIDs))
+	for i, memberID := range walletMembersIDs {
+		walletOperators[i] = chain.Address(fmt.Sprintf("operator-%d", memberID))
+		lc.operatorIDs[walletOperators[i]] = memberID
+	}
+	lc.walletOperators[wallet.EcdsaWalletID] = walletOperators
+	lc.pastNewWalletRegisteredEvents = append(
+		lc.pastNewWalletRegisteredEvents,
+		&tbtc.NewWalletRegisteredEvent{
+			EcdsaWalletID:       wallet.EcdsaWalletID,
+			WalletPublicKeyHash: walletPublicKeyHash,
+		},
+	)
+}
+
+func (lc *localChain) GetWalletOperators(
+	ecdsaWalletID [32]byte,
+) ([]chain.Address, error) {
+	lc.mutex.Lock()
+	defer lc.mutex.Unlock()
+
+	lc.walletOperatorsCalls++
+
+	walletOperators, ok := lc.walletOperators[ecdsaWalletID]
+	if !ok {
+		return nil, fmt.Errorf("no operators for given wallet ID")
+	}
+
+	return walletOperators, nil
+}
+
+func (lc *localChain) GetOperatorID(
+	operatorAddress chain.Address,
+) (chain.OperatorID, error) {
+	lc.mutex.Lock()
+	defer lc.mutex.Unlock()
+
+	operatorID, ok := lc.operatorIDs[operatorAddress]
+	if !ok {
+		return 0, fmt.Errorf("no operator ID for given address")
+	}
+
+	return operatorID, nil
+}
+
+func (lc *localChain) GetPendingRedemptionRequest(
+	walletPublicKeyHash [20]byte,
+	redeemerOutputScript bitcoin.Script,
+) (*tbtc.RedemptionRequest, bool, error) {
+	lc.mutex.Lock()
+	defer lc.mutex.Unlock()
+
+	request, ok := lc.pendingRedemptionRequests[buildRedemptionRequestKey(
+		walletPublicKeyHash,
+		redeemerOutputScript,
+	)]
+
+	return request, ok, nil
+}
+
+// addRedemptionRequest adds the redemption requested event and, if the
+// request is still pending, the pending redemption request.
+func (lc *localChain) addRedemptionRequest(
+	walletPublicKeyHash [20]byte,
+	request *tbtc.RedemptionRequest,
+	pending bool,
+) {
+	lc.mutex.Lock()
+	defer lc.mutex.Unlock()
+
+	lc.pastRedemptionRequestedEvents = append(
+		lc.pastRedemptionRequestedEvents,
+		&tbtc.RedemptionRequestedEvent{
+			WalletPublicKeyHash:  walletPublicKeyHash,
+			RedeemerOutputScript: request.RedeemerOutputScript,
+			BlockNumber:          900,
+		},
+	)
+
+	if pending {
+		lc.pendingRedemptionRequests[buildRedemptionRequestKey(
+			walletPublicKeyHash,
+			request.RedeemerOutputScript,
+		)] = request
+	}
+}
+
+func buildRedemptionRequestKey(
+	walletPublicKeyHash [20]byte,
+	redeemerOutputScript bitcoin.Script,
+) string {
+	return fmt.Sprintf(
+		"%x/%s",
+		walletPublicKeyHash,
+		hex.EncodeToString(redeemerOutputScript),
+	)
+}
+
+func (lc *localChain) GetDepositRequest(
+	fundingTxHash bitcoin.Hash,
+	fundingOutputIndex uint32,
+) (*tbtc.DepositChainRequest, bool, error) {
+	lc.mutex.Lock()
+	defer lc.mutex.Unlock()
+
+	request, ok := lc.depositRequests[buildDepositRequestKey(
+		fundingTxHash,
+		fundingOutputIndex,
+	)]
+
+	return request, ok, nil
+}
+
+// addDeposit adds the deposit revealed event along with the deposit request.
+func (lc *localChain) addDeposit(
+	walletPublicKeyHash [20]byte,
+	fundingTxHash bitcoin.Hash,
+	fundingOutputIndex uint32,
+	request *tbtc.DepositChainRequest,
+) {
+	lc.mutex.Lock()
+	defer lc.mutex.Unlock()
+
+	lc.pastDepositRevealedEvents = append(
+		lc.pastDepositRevealedEvents,
+		&tbtc.DepositRevealedEvent{
+			FundingTxHash:       fundingTxHash,
+			FundingOutputIndex:  fundingOutputIndex,
+			WalletPublicKeyHash: walletPublicKeyHash,
+			BlockNumber:         900,
+		},
+	)
+
+	lc.depositRequests[buildDepositRequestKey(
+		fundingTxHash,
+		fundingOutputIndex,
+	)] = request
+}
+
+func buildDepositRequestKey(
+	fundingTxHash bitcoin.Hash,
+	fundingOutputIndex uint32,
+) string {
+	return fmt.Sprintf(
+		"%s/%d",
+		fundingTxHash.Hex(bitcoin.InternalByteOrder),
+		fundingOutputIndex,
+	)
+}
+
+func (lc *localChain) IsMainUtxoSpent(
+	outpoint *bitcoin.TransactionOutpoint,
+) (bool, error) {
+	lc.mutex.Lock()
+	defer lc.mutex.Unlock()
+
+	return lc.spentMainUtxos[*outpoint], nil
+}
+
+func (lc *localChain) setMainUtxoSpent(outpoint *bitcoin.TransactionOutpoint) {
+	lc.mutex.Lock()
+	defer lc.mutex.Unlock()
+
+	lc.spentMainUtxos[*outpoint] = true
+}
+
+func (lc *localChain) GetMovedFundsSweepRequest(
+	movingFundsTxHash bitcoin.Hash,
+	movingFundsTxOutpointIndex uint32,
+) (*tbtc.MovedFundsSweepRequest, bool, error) {
+	lc.mutex.Lock()
+	defer lc.mutex.Unlock()
+
+	request, ok := lc.movedFundsSweepRequests[buildMovedFundsSweepRequestKey(
+		movingFundsTxHash,
+		movingFundsTxOutpointIndex,
+	)]
+
+	return request, ok, nil
+}
+
+func (lc *localChain) setMovedFundsSweepRequest(
+	movingFundsTxHash bitcoin.Hash,
+	movingFundsTxOutpointIndex uint32,
+	request *tbtc.MovedFundsSweepRequest,
+) {
+	lc.mutex.Lock()
+	defer lc.mutex.Unlock()
+
+	lc.movedFundsSweepRequests[buildMovedFundsSweepRequestKey(
+		movingFundsTxHash,
+		movingFundsTxOutpointIndex,
+	)] = request
+}
+
+func buildMovedFundsSweepRequestKey(
+	movingFundsTxHash bitcoin.Hash,
+	movingFundsTxOutpointIndex uint32,
+) string {
+	return fmt.Sprintf(
+		"%s/%d",
+		movingFundsTxHash.Hex(bitcoin.InternalByteOrder),
+		movingFundsTxOutpointIndex,
+	)
+}
+
+func (lc *localChain) GetRedemptionParameters() (
+	dustThreshold uint64,
+	treasuryFeeDivisor uint64,
+	txMaxFee uint64,
+	txMaxTotalFee uint64,
+	timeout uint32,
+	timeoutSlashingAmount *big.Int,
+	timeoutNotifierRewardMultiplier uint32,
+	err error,
+) {
+	lc.mutex.Lock()
+	defer lc.mutex.Unlock()
+
+	return 0, 0, 0, 0, lc.redemptionTimeout, nil, 0, nil
+}
+
+func (lc *localChain) GetMovingFundsParameters() (
+	txMaxTotalFee uint64,
+	dustThreshold uint64,
+	timeoutResetDelay uint32,
+	timeout uint32,
+	timeoutSlashingAmount *big.Int,
+	timeoutNotifierRewardMultiplier uint32,
+	commitmentGasOffset uint16,
+	sweepTxMaxTotalFee uint64,
+	sweepTimeout uint32,
+	sweepTimeoutSlashingAmount *big.Int,
+	sweepTimeoutNotifierRewardMultiplier uint32,
+	err error,
+) {
+	lc.mutex.Lock()
+	defer lc.mutex.Unlock()
+
+	return 0, 0, 0, lc.movingFundsTimeout, nil, 0, 0, 0,
+		lc.movedFundsSweepTimeout, nil, 0, nil
+}
+
+func (lc *localChain) PastNewWalletRegisteredEvents(
+	filter *tbtc.NewWalletRegisteredEventFilter,
+) ([]*tbtc.NewWalletRegisteredEvent, error) {
+	lc.mutex.Lock()
+	defer lc.mutex.Unlock()
+
+	return lc.pastNewWalletRegisteredEvents, nil
+}
+
+func (lc *localChain) PastDepositRevealedEvents(
+	filter *tbtc.DepositRevealedEventFilter,
+) ([]*tbtc.DepositRevealedEvent, error) {
+	lc.mutex.Lock()
+	defer lc.mutex.Unlock()
+
+	events := make([]*tbtc.DepositRevealedEvent, 0)
+	for _, event := range lc.pastDepositRevealedEvents {
+		if event.BlockNumber < filter.StartBlock {
+			continue
+		}
+
+		if !matchesWalletFilter(
+			event.WalletPublicKeyHash,
+			filter.WalletPublicKeyHash,
+		) {
+			continue
+		}
+
+		events = append(events, event)
+	}
+
+	return events, nil
+}
+
+func (lc *localChain) PastRedemptionRequestedEvents(
+	filter *tbtc.RedemptionRequestedEventFilter,
+) ([]*tbtc.RedemptionRequestedEvent, error) {
+	lc.mutex.Lock()
+	defer lc.mutex.Unlock()
+
+	events := make([]*tbtc.RedemptionRequestedEvent, 0)
+	for _, event := range lc.pastRedemptionRequestedEvents {
+		if event.BlockNumber < filter.StartBlock {
+			continue
+		}
+
+		if !matchesWalletFilter(
+			event.WalletPublicKeyHash,
+			filter.WalletPublicKeyHash,
+		) {
+			continue
+		}
+
+		events = append(events, event)
+	}
+
+	return events, nil
+}
+
+func (lc *localChain) addMovingFundsCommitmentSubmittedEvent(
+	event *tbtc.MovingFundsCommitmentSubmittedEvent,
+) {
+	lc.mutex.Lock()
+	defer lc.mutex.Unlock()
+
+	lc.pastMovingFundsCommitmentSubmittedEvents = append(
+		lc.pastMovingFundsCommitmentSubmittedEvents,
+		event,
+	)
+}
+
+func (lc *localChain) PastMovingFundsCommitmentSubmittedEvents(
+	filter *tbtc.MovingFundsCommitmentSubmittedEventFilter,
+) ([]*tbtc.MovingFundsCommitmentSubmittedEvent, error) {
+	lc.mutex.Lock()
+	defer lc.mutex.Unlock()
+
+	events := make([]*tbtc.MovingFundsCommitmentSubmittedEvent, 0)
+	for _, event := range lc.pastMovingFundsCommitmentSubmittedEvents {
+		if event.BlockNumber < filter.StartBlock {
+			continue
+		}
+
+		if !matchesWalletFilter(
+			event.WalletPublicKeyHash,
+			filter.WalletPublicKeyHash,
+		) {
+			continue
+		}
+
+		events = append(events, event)
+	}
+
+	return events, nil
+}
+
+func (lc *localChain) addMovingFundsCompletedEvent(
+	event *tbtc.MovingFundsCompletedEvent,
+) {
+	lc.mutex.Lock()
+	defer lc.mutex.Unlock()
+
+	lc.pastMovingFundsCompletedEvents = append(
+		lc.pastMovingFundsCompletedEvents,
+		event,
+	)
+}
+
+func (lc *localChain) PastMovingFundsCompletedEvents(
+	filter *tbtc.MovingFundsCompletedEventFilter,
+) ([]*tbtc.MovingFundsCompletedEvent, error) {
+	lc.mutex.Lock()
+	defer lc.mutex.Unlock()
+
+	events := make([]*tbtc.MovingFundsCompletedEvent, 0)
+	for _, event := range lc.pastMovingFundsCompletedEvents {
+		if event.BlockNumber < filter.StartBlock {
+			continue
+		}
+
+		events = append(events, event)
+	}
+
+	return events, nil
+}
+
+func matchesWalletFilter(
+	walletPublicKeyHash [20]byte,
+	filter [][20]byte,
+) bool {
+	if len(filter) == 0 {
+		return true
+	}
+
+	for _, filteredWallet := range filter {
+		if filteredWallet == walletPublicKeyHash {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (lc *localChain) NotifyRedemptionTimeout(
+	walletPublicKeyHash [20]byte,
+	walletMembersIDs []uint32,
+	redeemerOutputScript bitcoin.Script,
+) error {
+	lc.mutex.Lock()
+	defer lc.mutex.Unlock()
+
+	lc.notifiedRedemptionTimeouts = append(
+		lc.notifiedRedemptionTimeouts,
+		&notifiedRedemptionTimeout{
+			walletPublicKeyHash:  walletPublicKeyHash,
+			walletMembersIDs:     walletMembersIDs,
+			redeemerOutputScript: redeemerOutputScript,
+		},
+	)
+
+	return nil
+}
+
+func (lc *localChain) NotifyMovingFundsTimeout(
+	walletPublicKeyHash [20]byte,
+	walletMembersIDs []uint32,
+) error {
+	lc.mutex.Lock()
+	defer lc.mutex.Unlock()
+
+	lc.notifiedMovingFundsTimeouts = append(
+		lc.notifiedMovingFundsTimeouts,
+		&notifiedMovingFundsTimeout{
+			walletPublicKeyHash: walletPublicKeyHash,
+			walletMembersIDs:    walletMembersIDs,
+		},
+	)
+
+	return nil
+}
+
+func (lc *localChain) NotifyMovedFundsSweepTimeout(
+	movingFundsTxHash bitcoin.Hash,
+	movingFundsTxOutputIndex uint32,
+	walletMembersIDs []uint32,
+) error {
+	lc.mutex.Lock()
+	defer lc.mutex.Unlock()
+
+	lc.notifiedMovedFundsSweepTimeouts = append(
+		lc.notifiedMovedFundsSweepTimeouts,
+		&notifiedMovedFundsSweepTimeout{
+			movingFundsTxHash:        movingFundsTxHash,
+			movingFundsTxOutputIndex: movingFundsTxOutputIndex,
+			walletMembersIDs:         walletMembersIDs,
+		},
+	)
+
+	return nil
+}
+
+type mockBlockCounter struct {
+	mutex        sync.Mutex
+	currentBlock uint64
+}
+
+func newMockBlockCounter() *mockBlockCounter {
+	return &mockBlockCounter{}
+}
+
+func (mbc *mockBlockCounter) WaitForBlockHeight(blockNumber uint64) error {
+	panic("unsupported")
+}
+
+func (mbc *mockBlockCounter) BlockHeightWaiter(blockNumber uint64) (
+	<-chan uint64,
+	error,
+) {
+	panic("unsupported")
+}
+
+func (mbc *mockBlockCounter) CurrentBlock() (uint64, error) {
+	mbc.mutex.Lock()
+	defer mbc.mutex.Unlock()
+
+	return mbc.currentBlock, nil
+}
+
+func (mbc *mockBlockCounter) SetCurrentBlock(block uint64) {
+	mbc.mutex.Lock()
+	defer mbc.mutex.Unlock()
+
+	mbc.currentBlock = block
+}
+
+func (mbc *mockBlockCounter) WatchBlocks(ctx context.Context) <-chan uint64 {
+	panic("unsupported")
+}
@@ -0,0 +1,61 @@
+package watcher
+
+import (
+	"time"
+)
+
+const (
+	// DefaultHistoryDepth is the default value for history depth which is the
+	// number of blocks to look back from the current block when searching for
+	// past wallet-related events. The value is the approximate number of
+	// Ethereum blocks in two weeks, assuming one block is 12s. It is higher
+	// than the SPV maintainer's default as redemption requests must be
+	// watched until they are handled or time out.
+	DefaultHistoryDepth = 100800
+
+	// DefaultTransactionLimit is the default value for the limit of
+	// transactions returned for a given wallet public key hash when looking
+	// for wallet transactions without a matching wallet action.
+	DefaultTransactionLimit = 20
+
+	// DefaultRestartBackoffTime is the default value for restart back-off time.
+	DefaultRestartBackoffTime = 30 * time.Minute
+
+	// DefaultIdleBackoffTime is the default value for idle back-off time.
+	DefaultIdleBackoffTime = 10 * time.Minute
+)
+
+// Config holds configurable properties.
+type Config struct {
+	// Enabled indicates whether the watcher maintainer should be started.
+	Enabled bool
+
+	// NotifyTimeouts indicates whether the watcher should notify the Bridge
+	// about timed out redemptions, moving funds and moved funds sweeps. The
+	// notifier is rewarded from the slashed stakes of the wallet operators.
+	// If not set, the watcher only alerts about timeouts.
+	NotifyTimeouts bool
+
+	// HistoryDepth is the number of blocks to look back from the current block
+	// when searching for past wallet-related events. The watcher inspects
+	// redemption requests, revealed deposits and moving funds events that
+	// happened within this depth. Redemption requests older than the depth
+	// are not watched for timeouts so the depth should cover the redemption
+	// timeout. Spends of deposits revealed before the depth are not checked.
+	HistoryDepth uint64
+
+	// TransactionLimit sets the maximum number of confirmed transactions
+	// returned when getting transactions for a wallet public key hash.
+	// Only that many latest transactions of each wallet are checked against
+	// the wallet actions known to the Bridge.
+	TransactionLimit int
+
+	// RestartBackoffTime is a restart backoff which should be applied when the
+	// watcher maintainer is restarted. It helps to avoid being flooded with
+	// error logs in case of a permanent error in the watcher maintainer.
+	RestartBackoffTime time.Duration
+
+	// IdleBackoffTime is a wait time which should be applied between
+	// consecutive rounds of watching the protocol.
+	IdleBackoffTime time.Duration
+}
@@ -0,0 +1,478 @@
+package watcher
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcutil"
+
+	"github.com/keep-network/keep-core/pkg/bitcoin"
+	"github.com/keep-network/keep-core/pkg/tbtc"
+)
+
+// checkWalletTransactions looks for Bitcoin transactions spending funds
+// of wallets holding funds without a matching wallet action known to the
+// Bridge. Wallet funds are outputs locked to the wallet's public key hash
+// and deposits revealed to the wallet. Every wallet action pays either back
+// to the wallet itself, to redeemers who requested redemptions from the
+// wallet or to target wallets the wallet committed to move funds to.
+// A transaction spending wallet funds and paying anywhere else was not
+// proposed by the wallet coordination and is a likely fraud, unless the
+// Bridge already accepted its proof.
+//
+// Suspicious transactions are only alerted about. Submitting a fraud
+// challenge requires putting a deposit at stake so it is left to the
+// operator of the watcher.
+func (w *watcher) checkWalletTransactions(
+	startBlock uint64,
+	wallets []*walletInfo,
+) error {
+	activeWallets := make([]*walletInfo, 0)
+	activeWalletsPublicKeyHashes := make([][20]byte, 0)
+	for _, wallet := range wallets {
+		switch wallet.data.State {
+		case tbtc.StateLive, tbtc.StateMovingFunds, tbtc.StateClosing:
+			activeWallets = append(activeWallets, wallet)
+			activeWalletsPublicKeyHashes = append(
+				activeWalletsPublicKeyHashes,
+				wallet.publicKeyHash,
+			)
+		}
+	}
+
+	if len(activeWallets) == 0 {
+		return nil
+	}
+
+	redemptionEvents, err := w.chain.PastRedemptionRequestedEvents(
+		&tbtc.RedemptionRequestedEventFilter{
+			StartBlock:          startBlock,
+			WalletPublicKeyHash: activeWalletsPublicKeyHashes,
+		},
+	)
+	if err != nil {
+		return fmt.Errorf(
+			"failed to get past redemption requested events: [%v]",
+			err,
+		)
+	}
+
+	commitmentEvents, err := w.chain.PastMovingFundsCommitmentSubmittedEvents(
+		&tbtc.MovingFundsCommitmentSubmittedEventFilter{
+			WalletPublicKeyHash: activeWalletsPublicKeyHashes,
+		},
+	)
+	if err != nil {
+		return fmt.Errorf(
+			"failed to get past moving funds commitment submitted "+
+				"events: [%v]",
+			err,
+		)
+	}
+
+	depositEvents, err := w.chain.PastDepositRevealedEvents(
+		&tbtc.DepositRevealedEventFilter{
+			StartBlock:          startBlock,
+			WalletPublicKeyHash: activeWalletsPublicKeyHashes,
+		},
+	)
+	if err != nil {
+		return fmt.Errorf(
+			"failed to get past deposit revealed events: [%v]",
+			err,
+		)
+	}
+
+	deposits := make(map[[20]byte][]*bitcoin.TransactionOutpoint)
+	for _, event := range depositEvents {
+		deposits[event.WalletPublicKeyHash] = append(
+			deposits[event.WalletPublicKeyHash],
+			&bitcoin.TransactionOutpoint{
+				TransactionHash: event.FundingTxHash,
+				OutputIndex:     event.FundingOutputIndex,
+			},
+		)
+	}
+
+	expectedScripts := make(map[[20]byte]map[string]bool)
+	for _, wallet := range activeWallets {
+		scripts, err := walletScripts(wallet.publicKeyHash)
+		if err != nil {
+			return err
+		}
+		expectedScripts[wallet.publicKeyHash] = scripts
+	}
+
+	for _, event := range redemptionEvents {
+		scripts, ok := expectedScripts[event.WalletPublicKeyHash]
+		if !ok {
+			continue
+		}
+		scripts[hex.EncodeToString(event.RedeemerOutputScript)] = true
+	}
+
+	for _, event := range commitmentEvents {
+		scripts, ok := expectedScripts[event.WalletPublicKeyHash]
+		if !ok {
+			continue
+		}
+		for _, targetWallet := range event.TargetWallets {
+			targetWalletScripts, err := walletScripts(targetWallet)
+			if err != nil {
+				return err
+			}
+
+			for script := range targetWalletScripts {
+				scripts[script] = true
+			}
+		}
+	}
+
+	for _, wallet := range activeWallets {
+		err := w.checkTransactionsOfWallet(
+			wallet.publicKeyHash,
+			expectedScripts[wallet.publicKeyHash],
+			deposits[wallet.publicKeyHash],
+		)
+		if err != nil {
+			return fmt.Errorf(
+				"failed to check transactions of wallet [0x%x]: [%v]",
+				wallet.publicKeyHash,
+				err,
+			)
+		}
+	}
+
+	return nil
+}
+
+// checkTransactionsOfWallet checks the latest Bitcoin transactions of the
+// given wallet, along with transactions spending its unswept deposits, and
+// alerts about those spending wallet funds and paying to output scripts
+// other than the expected ones.
+func (w *watcher) checkTransactionsOfWallet(
+	walletPublicKeyHash [20]byte,
+	expectedScripts map[string]bool,
+	deposits []*bitcoin.TransactionOutpoint,
+) error {
+	transactions, err := w.btcChain.GetTransactionsForPublicKeyHash(
+		walletPublicKeyHash,
+		w.config.TransactionLimit,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to get transactions: [%v]", err)
+	}
+
+	// A transaction spending only deposits does not have to pay the wallet
+	// so it must be looked up separately.
+	depositTransactions, err := w.getDepositSpendingTransactions(deposits)
+	if err != nil {
+		return err
+	}
+	transactions = append(transactions, depositTransactions...)
+
+	ownScripts, err := walletScripts(walletPublicKeyHash)
+	if err != nil {
+		return err
+	}
+
+	depositOutpoints := make(map[bitcoin.TransactionOutpoint]bool)
+	for _, deposit := range deposits {
+		depositOutpoints[*deposit] = true
+	}
+
+	checked := make(map[bitcoin.Hash]bool)
+	for _, transaction := range transactions {
+		transactionHash := transaction.Hash()
+		if checked[transactionHash] {
+			continue
+		}
+		checked[transactionHash] = true
+
+		unexpectedScripts := make([]bitcoin.Script, 0)
+		for _, output := range transaction.Outputs {
+			script := output.PublicKeyScript
+			if !expectedScripts[hex.EncodeToString(script)] {
+				unexpectedScripts = append(unexpectedScripts, script)
+			}
+		}
+
+		// Look up the inputs only if the transaction is suspicious as
+		// it requires fetching all previous transactions.
+		if len(unexpectedScripts) == 0 {
+			continue
+		}
+
+		spendsWalletFunds, err := w.spendsWalletFunds(
+			walletPublicKeyHash,
+			transaction,
+			ownScripts,
+			depositOutpoints,
+		)
+		if err != nil {
+			return err
+		}
+
+		if !spendsWalletFunds {
+			// Someone sent funds directly to the wallet.
+			continue
+		}
+
+		// Redemptions requested before the lookback window are not
+		// covered by the events so check the pending ones on-chain.
+		unexpectedScripts, err = w.filterPendingRedemptions(
+			walletPublicKeyHash,
+			unexpectedScripts,
+		)
+		if err != nil {
+			return err
+		}
+
+		if len(unexpectedScripts) == 0 {
+			continue
+		}
+
+		proven, err := w.isProvenToBridge(transaction)
+		if err != nil {
+			return err
+		}
+
+		if proven {
+			continue
+		}
+
+		unexpectedScriptsHex := make([]string, len(unexpectedScripts))
+		for i, script := range unexpectedScripts {
+			unexpectedScriptsHex[i] = hex.EncodeToString(script)
+		}
+
+		w.alert(
+			fmt.Sprintf(
+				"unmatched-transaction/%s",
+				transactionHash.Hex(bitcoin.InternalByteOrder),
+			),
+			"transaction [%s] spends funds of wallet [0x%x] without "+
+				"a matching wallet action; it pays to unexpected output "+
+				"scripts [%s]",
+			// Print the transaction in the same endianness as block
+			// explorers do.
+			transactionHash.Hex(bitcoin.ReversedByteOrder),
+			walletPublicKeyHash,
+			strings.Join(unexpectedScriptsHex, ", "),
+		)
+	}
+
+	return nil
+}
+
+// getDepositSpendingTransactions returns confirmed transactions spending
+// the given deposits that were not swept according to the Bridge. Swept
+// deposits were spent by sweep transactions proven to the Bridge. No
+// transactions are returned if the Bitcoin chain cannot find spending
+// transactions.
+func (w *watcher) getDepositSpendingTransactions(
+	deposits []*bitcoin.TransactionOutpoint,
+) ([]*bitcoin.Transaction, error) {
+	transactions := make([]*bitcoin.Transaction, 0)
+
+	if w.spendingTxFinder == nil {
+		return transactions, nil
+	}
+
+	for _, deposit := range deposits {
+		request, found, err := w.chain.GetDepositRequest(
+			deposit.TransactionHash,
+			deposit.OutputIndex,
+		)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"failed to get deposit request: [%v]",
+				err,
+			)
+		}
+
+		if !found || request.SweptAt.Unix() != 0 {
+			continue
+		}
+
+		spendingTxHash, spent, err := w.spendingTxFinder.GetSpendingTxHash(deposit)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"failed to get transaction spending deposit [%s:%d]: [%v]",
+				deposit.TransactionHash.Hex(bitcoin.ReversedByteOrder),
+				deposit.OutputIndex,
+				err,
+			)
+		}
+
+		if !spent {
+			continue
+		}
+
+		transaction, err := w.btcChain.GetTransaction(spendingTxHash)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"failed to get deposit spending transaction: [%v]",
+				err,
+			)
+		}
+
+		transactions = append(transactions, transaction)
+	}
+
+	return transactions, nil
+}
+
+// spendsWalletFunds returns true if any input of the given transaction
+// spends an output locked with one of the given wallet scripts or spends
+// one of the given deposits using the wallet's key.
+func (w *watcher) spendsWalletFunds(
+	walletPublicKeyHash [20]byte,
+	transaction *bitcoin.Transaction,
+	scripts map[string]bool,
+	deposits map[bitcoin.TransactionOutpoint]bool,
+) (bool, error) {
+	for _, input := range transaction.Inputs {
+		if deposits[*input.Outpoint] {
+			// The depositor can take the deposit back once the refund
+			// locktime passes. Such a refund does not touch wallet funds.
+			publicKeyHash, ok := unlockingPublicKeyHash(input)
+			if ok && publicKeyHash == walletPublicKeyHash {
+				return true, nil
+			}
+
+			continue
+		}
+
+		previousTransaction, err := w.btcChain.GetTransaction(
+			input.Outpoint.TransactionHash,
+		)
+		if err != nil {
+			return false, fmt.Errorf(
+				"failed to get previous transaction: [%v]",
+				err,
+			)
+		}
+
+		outputIndex := int(input.Outpoint.OutputIndex)
+		if outputIndex >= len(previousTransaction.Outputs) {
+			return false, fmt.Errorf(
+				"previous transaction [%s] has no output [%d]",
+				input.Outpoint.TransactionHash.Hex(bitcoin.ReversedByteOrder),
+				outputIndex,
+			)
+		}
+
+		script := previousTransaction.Outputs[outputIndex].PublicKeyScript
+		if scripts[hex.EncodeToString(script)] {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// filterPendingRedemptions returns the given output scripts except those
+// of redemptions pending on-chain for the given wallet.
+func (w *watcher) filterPendingRedemptions(
+	walletPublicKeyHash [20]byte,
+	scripts []bitcoin.Script,
+) ([]bitcoin.Script, error) {
+	filtered := make([]bitcoin.Script, 0)
+
+	for _, script := range scripts {
+		_, found, err := w.chain.GetPendingRedemptionRequest(
+			walletPublicKeyHash,
+			script,
+		)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"failed to get pending redemption request: [%v]",
+				err,
+			)
+		}
+
+		if !found {
+			filtered = append(filtered, script)
+		}
+	}
+
+	return filtered, nil
+}
+
+// isProvenToBridge returns true if the Bridge accepted the proof of the
+// given transaction. A proven transaction marks the main UTXO it spends as
+// spent and the main UTXO cannot be spent by any other transaction.
+func (w *watcher) isProvenToBridge(
+	transaction *bitcoin.Transaction,
+) (bool, error) {
+	for _, input := range transaction.Inputs {
+		spent, err := w.chain.IsMainUtxoSpent(input.Outpoint)
+		if err != nil {
+			return false, fmt.Errorf(
+				"failed to check main UTXO spent state: [%v]",
+				err,
+			)
+		}
+
+		if spent {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// unlockingPublicKeyHash returns the hash of the public key used to unlock
+// the given input spending a deposit. Both the wallet and the refund path
+// of the deposit script are unlocked with a signature and a public key,
+// followed by the deposit script itself. The returned bool value is false
+// if the input does not have such a form.
+func unlockingPublicKeyHash(input *bitcoin.TransactionInput) ([20]byte, bool) {
+	items := input.Witness
+	if len(items) == 0 {
+		pushes, err := txscript.PushedData(input.SignatureScript)
+		if err != nil {
+			return [20]byte{}, false
+		}
+		items = pushes
+	}
+
+	if len(items) < 3 {
+		return [20]byte{}, false
+	}
+
+	var publicKeyHash [20]byte
+	copy(publicKeyHash[:], btcutil.Hash160(items[len(items)-2]))
+
+	return publicKeyHash, true
+}
+
+// walletScripts returns the hex-encoded P2PKH and P2WPKH scripts of the
+// wallet with the given public key hash.
+func walletScripts(walletPublicKeyHash [20]byte) (map[string]bool, error) {
+	p2pkh, err := bitcoin.PayToPublicKeyHash(walletPublicKeyHash)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"cannot build P2PKH for wallet [0x%x]: [%v]",
+			walletPublicKeyHash,
+			err,
+		)
+	}
+
+	p2wpkh, err := bitcoin.PayToWitnessPublicKeyHash(walletPublicKeyHash)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"cannot build P2WPKH for wallet [0x%x]: [%v]",
+			walletPublicKeyHash,
+			err,
+		)
+	}
+
+	return map[string]bool{
+		hex.EncodeToString(p2pkh):  true,
+		hex.EncodeToString(p2wpkh): true,
+	}, nil
+}
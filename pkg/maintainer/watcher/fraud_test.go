@@ -0,0 +1,385 @@
+package watcher
+
+import (
+	"testing"
+	"time"
+
+	"github.com/keep-network/keep-core/internal/testutils"
+	"github.com/keep-network/keep-core/pkg/bitcoin"
+	"github.com/keep-network/keep-core/pkg/tbtc"
+)
+
+func TestWatcher_CheckWalletTransactions(t *testing.T) {
+	walletScript, err := bitcoin.PayToWitnessPublicKeyHash(
+		testWalletPublicKeyHash,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targetWalletScript, err := bitcoin.PayToWitnessPublicKeyHash(
+		testTargetWalletPublicKeyHash,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	redeemerOutputScript := bitcoin.Script{
+		0x00, 0x14, 0x7a, 0xc2, 0xd9, 0x37, 0x8a, 0x1c, 0x47, 0xe5, 0x89,
+		0xdf, 0xb8, 0x09, 0x5c, 0xa9, 0x5e, 0xd2, 0x14, 0x0d, 0x27, 0x26,
+	}
+
+	// processedRedeemerOutputScript belongs to a redemption request that
+	// is no longer pending.
+	processedRedeemerOutputScript := bitcoin.Script{
+		0x00, 0x14, 0x22, 0x22, 0x22, 0x22, 0x22, 0x22, 0x22, 0x22, 0x22,
+		0x22, 0x22, 0x22, 0x22, 0x22, 0x22, 0x22, 0x22, 0x22, 0x22, 0x22,
+	}
+
+	unknownScript := bitcoin.Script{
+		0x00, 0x14, 0x11, 0x11, 0x11, 0x11, 0x11, 0x11, 0x11, 0x11, 0x11,
+		0x11, 0x11, 0x11, 0x11, 0x11, 0x11, 0x11, 0x11, 0x11, 0x11, 0x11,
+	}
+
+	// externalTransaction holds funds of someone else than the wallet.
+	externalTransaction := &bitcoin.Transaction{
+		Version: 1,
+		Inputs: []*bitcoin.TransactionInput{
+			{
+				Outpoint: &bitcoin.TransactionOutpoint{
+					TransactionHash: bitcoin.Hash{0x01},
+					OutputIndex:     0,
+				},
+			},
+		},
+		Outputs: []*bitcoin.TransactionOutput{
+			{Value: 100000, PublicKeyScript: unknownScript},
+		},
+	}
+
+	// depositTransaction sends external funds to the wallet. It pays to the
+	// unknown script as well so its inputs are checked.
+	depositTransaction := &bitcoin.Transaction{
+		Version: 1,
+		Inputs: []*bitcoin.TransactionInput{
+			{
+				Outpoint: &bitcoin.TransactionOutpoint{
+					TransactionHash: externalTransaction.Hash(),
+					OutputIndex:     0,
+				},
+			},
+		},
+		Outputs: []*bitcoin.TransactionOutput{
+			{Value: 60000, PublicKeyScript: walletScript},
+			{Value: 39000, PublicKeyScript: unknownScript},
+		},
+	}
+
+	spendWalletFunds := func(outputs ...*bitcoin.TransactionOutput) *bitcoin.Transaction {
+		return &bitcoin.Transaction{
+			Version: 1,
+			Inputs: []*bitcoin.TransactionInput{
+				{
+					Outpoint: &bitcoin.TransactionOutpoint{
+						TransactionHash: depositTransaction.Hash(),
+						OutputIndex:     0,
+					},
+				},
+			},
+			Outputs: outputs,
+		}
+	}
+
+	// The public key hashing to the test wallet's public key hash.
+	walletPublicKey := []byte{
+		0x03, 0x98, 0x9d, 0x25, 0x3b, 0x17, 0xa6, 0xa0, 0xf4, 0x18, 0x38,
+		0xb8, 0x4f, 0xf0, 0xd2, 0x0e, 0x88, 0x98, 0xf9, 0xd7, 0xb1, 0xa9,
+		0x8f, 0x25, 0x64, 0xda, 0x4c, 0xc2, 0x9d, 0xcf, 0x85, 0x81, 0xd9,
+	}
+
+	refundPublicKey := []byte{
+		0x02, 0x33, 0x33, 0x33, 0x33, 0x33, 0x33, 0x33, 0x33, 0x33, 0x33,
+		0x33, 0x33, 0x33, 0x33, 0x33, 0x33, 0x33, 0x33, 0x33, 0x33, 0x33,
+		0x33, 0x33, 0x33, 0x33, 0x33, 0x33, 0x33, 0x33, 0x33, 0x33, 0x33,
+	}
+
+	// depositScript stands for the deposit script revealed to the Bridge.
+	// The watcher does not inspect its content.
+	depositScript := bitcoin.Script{0x14, 0x44, 0x44, 0x44, 0x75, 0x76}
+
+	depositScriptHash, err := bitcoin.PayToWitnessScriptHash(
+		bitcoin.WitnessScriptHash(depositScript),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// revealedDepositTransaction funds a deposit revealed to the Bridge.
+	revealedDepositTransaction := &bitcoin.Transaction{
+		Version: 1,
+		Inputs: []*bitcoin.TransactionInput{
+			{
+				Outpoint: &bitcoin.TransactionOutpoint{
+					TransactionHash: bitcoin.Hash{0x02},
+					OutputIndex:     0,
+				},
+			},
+		},
+		Outputs: []*bitcoin.TransactionOutput{
+			{Value: 80000, PublicKeyScript: depositScriptHash},
+		},
+	}
+
+	spendDeposit := func(
+		publicKey []byte,
+		outputs ...*bitcoin.TransactionOutput,
+	) *bitcoin.Transaction {
+		return &bitcoin.Transaction{
+			Version: 1,
+			Inputs: []*bitcoin.TransactionInput{
+				{
+					Outpoint: &bitcoin.TransactionOutpoint{
+						TransactionHash: revealedDepositTransaction.Hash(),
+						OutputIndex:     0,
+					},
+					Witness: [][]byte{{0x30}, publicKey, depositScript},
+				},
+			},
+			Outputs: outputs,
+		}
+	}
+
+	var tests = map[string]struct {
+		transaction         *bitcoin.Transaction
+		startBlock          uint64
+		mainUtxoSpent       bool
+		expectedAlertsCount int
+	}{
+		"funds sent to the wallet": {
+			transaction:         depositTransaction,
+			expectedAlertsCount: 0,
+		},
+		"redemption": {
+			transaction: spendWalletFunds(
+				&bitcoin.TransactionOutput{
+					Value:           20000,
+					PublicKeyScript: redeemerOutputScript,
+				},
+				&bitcoin.TransactionOutput{
+					Value:           39000,
+					PublicKeyScript: walletScript,
+				},
+			),
+			expectedAlertsCount: 0,
+		},
+		"moving funds": {
+			transaction: spendWalletFunds(
+				&bitcoin.TransactionOutput{
+					Value:           59000,
+					PublicKeyScript: targetWalletScript,
+				},
+			),
+			expectedAlertsCount: 0,
+		},
+		"unmatched spend of wallet funds": {
+			transaction: spendWalletFunds(
+				&bitcoin.TransactionOutput{
+					Value:           20000,
+					PublicKeyScript: unknownScript,
+				},
+				&bitcoin.TransactionOutput{
+					Value:           39000,
+					PublicKeyScript: walletScript,
+				},
+			),
+			expectedAlertsCount: 1,
+		},
+		"redemption requested before the lookback window": {
+			transaction: spendWalletFunds(
+				&bitcoin.TransactionOutput{
+					Value:           20000,
+					PublicKeyScript: redeemerOutputScript,
+				},
+				&bitcoin.TransactionOutput{
+					Value:           39000,
+					PublicKeyScript: walletScript,
+				},
+			),
+			startBlock:          950,
+			expectedAlertsCount: 0,
+		},
+		"unproven spend paying a processed redemption": {
+			transaction: spendWalletFunds(
+				&bitcoin.TransactionOutput{
+					Value:           20000,
+					PublicKeyScript: processedRedeemerOutputScript,
+				},
+				&bitcoin.TransactionOutput{
+					Value:           39000,
+					PublicKeyScript: walletScript,
+				},
+			),
+			startBlock:          950,
+			expectedAlertsCount: 1,
+		},
+		"proven spend paying a processed redemption": {
+			transaction: spendWalletFunds(
+				&bitcoin.TransactionOutput{
+					Value:           20000,
+					PublicKeyScript: processedRedeemerOutputScript,
+				},
+				&bitcoin.TransactionOutput{
+					Value:           39000,
+					PublicKeyScript: walletScript,
+				},
+			),
+			startBlock:          950,
+			mainUtxoSpent:       true,
+			expectedAlertsCount: 0,
+		},
+		"deposit sweep": {
+			transaction: spendDeposit(
+				walletPublicKey,
+				&bitcoin.TransactionOutput{
+					Value:           79000,
+					PublicKeyScript: walletScript,
+				},
+			),
+			expectedAlertsCount: 0,
+		},
+		"unmatched spend of a deposit": {
+			transaction: spendDeposit(
+				walletPublicKey,
+				&bitcoin.TransactionOutput{
+					Value:           79000,
+					PublicKeyScript: unknownScript,
+				},
+			),
+			expectedAlertsCount: 1,
+		},
+		"deposit refund": {
+			transaction: spendDeposit(
+				refundPublicKey,
+				&bitcoin.TransactionOutput{
+					Value:           79000,
+					PublicKeyScript: unknownScript,
+				},
+			),
+			expectedAlertsCount: 0,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			chain := newLocalChain()
+			chain.addWallet(
+				testWalletPublicKeyHash,
+				&tbtc.WalletChainData{
+					EcdsaWalletID: testWalletID,
+					State:         tbtc.StateMovingFunds,
+				},
+				testWalletMembersIDs,
+			)
+			chain.addRedemptionRequest(
+				testWalletPublicKeyHash,
+				&tbtc.RedemptionRequest{
+					RedeemerOutputScript: redeemerOutputScript,
+				},
+				true,
+			)
+			chain.addRedemptionRequest(
+				testWalletPublicKeyHash,
+				&tbtc.RedemptionRequest{
+					RedeemerOutputScript: processedRedeemerOutputScript,
+				},
+				false,
+			)
+			chain.addDeposit(
+				testWalletPublicKeyHash,
+				revealedDepositTransaction.Hash(),
+				0,
+				&tbtc.DepositChainRequest{
+					SweptAt: time.Unix(0, 0),
+				},
+			)
+			if test.mainUtxoSpent {
+				chain.setMainUtxoSpent(
+					&bitcoin.TransactionOutpoint{
+						TransactionHash: depositTransaction.Hash(),
+						OutputIndex:     0,
+					},
+				)
+			}
+			chain.addMovingFundsCommitmentSubmittedEvent(
+				&tbtc.MovingFundsCommitmentSubmittedEvent{
+					WalletPublicKeyHash: testWalletPublicKeyHash,
+					TargetWallets: [][20]byte{
+						testTargetWalletPublicKeyHash,
+					},
+					BlockNumber: 10,
+				},
+			)
+
+			btcChain := newLocalBitcoinChain()
+			transactions := []*bitcoin.Transaction{
+				externalTransaction,
+				depositTransaction,
+				revealedDepositTransaction,
+			}
+			if test.transaction != depositTransaction {
+				transactions = append(transactions, test.transaction)
+			}
+			for _, transaction := range transactions {
+				err := btcChain.BroadcastTransaction(transaction)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			w := newTestWatcher(chain, btcChain, false)
+
+			wallets, err := w.wallets()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = w.checkWalletTransactions(test.startBlock, wallets)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			testutils.AssertIntsEqual(
+				t,
+				"alerts count",
+				test.expectedAlertsCount,
+				len(w.alerts),
+			)
+		})
+	}
+}
+
+func TestNewWatcher_SpendingTxFinder(t *testing.T) {
+	w := newWatcher(Config{}, newLocalChain(), newLocalBitcoinChain())
+	if w.spendingTxFinder == nil {
+		t.Error("expected the spending transaction finder to be set")
+	}
+
+	// Embedding the chain as bitcoin.Chain hides the optional method.
+	w = newWatcher(
+		Config{},
+		newLocalChain(),
+		struct{ bitcoin.Chain }{newLocalBitcoinChain()},
+	)
+	if w.spendingTxFinder != nil {
+		t.Error("expected the spending transaction finder not to be set")
+	}
+
+	transactions, err := w.getDepositSpendingTransactions(
+		[]*bitcoin.TransactionOutpoint{{OutputIndex: 0}},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertIntsEqual(t, "transactions count", 0, len(transactions))
+}
@@ -0,0 +1,305 @@
+package watcher
+
+import (
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/keep-network/keep-core/pkg/bitcoin"
+	"github.com/keep-network/keep-core/pkg/tbtc"
+)
+
+// checkRedemptionTimeouts looks for pending redemption requests created
+// since the given block that exceeded the redemption timeout.
+func (w *watcher) checkRedemptionTimeouts(startBlock uint64) error {
+	_, _, _, _, timeout, _, _, err := w.chain.GetRedemptionParameters()
+	if err != nil {
+		return fmt.Errorf("failed to get redemption parameters: [%v]", err)
+	}
+
+	events, err := w.chain.PastRedemptionRequestedEvents(
+		&tbtc.RedemptionRequestedEventFilter{
+			StartBlock: startBlock,
+		},
+	)
+	if err != nil {
+		return fmt.Errorf(
+			"failed to get past redemption requested events: [%v]",
+			err,
+		)
+	}
+
+	// The same redemption key can be used by multiple requests over time
+	// but only one of them can be pending.
+	checked := make(map[string]bool)
+
+	for _, event := range events {
+		redemptionKey := fmt.Sprintf(
+			"%x/%s",
+			event.WalletPublicKeyHash,
+			hex.EncodeToString(event.RedeemerOutputScript),
+		)
+		if checked[redemptionKey] {
+			continue
+		}
+		checked[redemptionKey] = true
+
+		request, found, err := w.chain.GetPendingRedemptionRequest(
+			event.WalletPublicKeyHash,
+			event.RedeemerOutputScript,
+		)
+		if err != nil {
+			return fmt.Errorf(
+				"failed to get pending redemption request [%s]: [%v]",
+				redemptionKey,
+				err,
+			)
+		}
+
+		if !found {
+			// The request has been already handled or notified as
+			// timed out.
+			continue
+		}
+
+		deadline := request.RequestedAt.Add(
+			time.Duration(timeout) * time.Second,
+		)
+		if !w.now().After(deadline) {
+			continue
+		}
+
+		walletPublicKeyHash := event.WalletPublicKeyHash
+		redeemerOutputScript := event.RedeemerOutputScript
+
+		w.handleTimeout(
+			fmt.Sprintf(
+				"redemption-timeout/%s/%d",
+				redemptionKey,
+				request.RequestedAt.Unix(),
+			),
+			fmt.Sprintf(
+				"redemption request [%s] of wallet [0x%x] requested at [%s]",
+				hex.EncodeToString(redeemerOutputScript),
+				walletPublicKeyHash,
+				request.RequestedAt.Format(time.RFC3339),
+			),
+			walletPublicKeyHash,
+			func(walletMembersIDs []uint32) error {
+				return w.chain.NotifyRedemptionTimeout(
+					walletPublicKeyHash,
+					walletMembersIDs,
+					redeemerOutputScript,
+				)
+			},
+		)
+	}
+
+	return nil
+}
+
+// checkMovingFundsTimeouts looks for wallets in the moving funds state that
+// exceeded the moving funds timeout.
+func (w *watcher) checkMovingFundsTimeouts(wallets []*walletInfo) error {
+	_, _, _, timeout, _, _, _, _, _, _, _, err :=
+		w.chain.GetMovingFundsParameters()
+	if err != nil {
+		return fmt.Errorf("failed to get moving funds parameters: [%v]", err)
+	}
+
+	for _, wallet := range wallets {
+		if wallet.data.State != tbtc.StateMovingFunds {
+			continue
+		}
+
+		requestedAt := wallet.data.MovingFundsRequestedAt
+
+		deadline := requestedAt.Add(time.Duration(timeout) * time.Second)
+		if !w.now().After(deadline) {
+			continue
+		}
+
+		walletPublicKeyHash := wallet.publicKeyHash
+
+		w.handleTimeout(
+			fmt.Sprintf(
+				"moving-funds-timeout/%x/%d",
+				walletPublicKeyHash,
+				requestedAt.Unix(),
+			),
+			fmt.Sprintf(
+				"moving funds of wallet [0x%x] requested at [%s]",
+				walletPublicKeyHash,
+				requestedAt.Format(time.RFC3339),
+			),
+			walletPublicKeyHash,
+			func(walletMembersIDs []uint32) error {
+				return w.chain.NotifyMovingFundsTimeout(
+					walletPublicKeyHash,
+					walletMembersIDs,
+				)
+			},
+		)
+	}
+
+	return nil
+}
+
+// checkMovedFundsSweepTimeouts looks for pending moved funds sweep requests
+// created by moving funds completed since the given block that exceeded
+// the moved funds sweep timeout.
+func (w *watcher) checkMovedFundsSweepTimeouts(startBlock uint64) error {
+	_, _, _, _, _, _, _, _, sweepTimeout, _, _, err :=
+		w.chain.GetMovingFundsParameters()
+	if err != nil {
+		return fmt.Errorf("failed to get moving funds parameters: [%v]", err)
+	}
+
+	completedEvents, err := w.chain.PastMovingFundsCompletedEvents(
+		&tbtc.MovingFundsCompletedEventFilter{
+			StartBlock: startBlock,
+		},
+	)
+	if err != nil {
+		return fmt.Errorf(
+			"failed to get past moving funds completed events: [%v]",
+			err,
+		)
+	}
+
+	if len(completedEvents) == 0 {
+		return nil
+	}
+
+	sourceWallets := make([][20]byte, 0, len(completedEvents))
+	for _, event := range completedEvents {
+		sourceWallets = append(sourceWallets, event.WalletPublicKeyHash)
+	}
+
+	// Commitments are submitted before moving funds is completed so they
+	// can precede the start block.
+	commitmentEvents, err := w.chain.PastMovingFundsCommitmentSubmittedEvents(
+		&tbtc.MovingFundsCommitmentSubmittedEventFilter{
+			WalletPublicKeyHash: sourceWallets,
+		},
+	)
+	if err != nil {
+		return fmt.Errorf(
+			"failed to get past moving funds commitment submitted "+
+				"events: [%v]",
+			err,
+		)
+	}
+
+	// Events are sorted by block number so the latest commitment of each
+	// wallet is kept.
+	targetWallets := make(map[[20]byte][][20]byte)
+	for _, event := range commitmentEvents {
+		targetWallets[event.WalletPublicKeyHash] = event.TargetWallets
+	}
+
+	for _, event := range completedEvents {
+		movingFundsTxHash := event.MovingFundsTxHash
+
+		// The moving funds transaction has one output per target wallet,
+		// in the order of the commitment. Each output is a moved funds
+		// sweep request.
+		for i := range targetWallets[event.WalletPublicKeyHash] {
+			outputIndex := uint32(i)
+
+			request, found, err := w.chain.GetMovedFundsSweepRequest(
+				movingFundsTxHash,
+				outputIndex,
+			)
+			if err != nil {
+				return fmt.Errorf(
+					"failed to get moved funds sweep request [%s:%d]: [%v]",
+					movingFundsTxHash.Hex(bitcoin.ReversedByteOrder),
+					outputIndex,
+					err,
+				)
+			}
+
+			if !found || request.State != tbtc.MovedFundsStatePending {
+				continue
+			}
+
+			deadline := request.CreatedAt.Add(
+				time.Duration(sweepTimeout) * time.Second,
+			)
+			if !w.now().After(deadline) {
+				continue
+			}
+
+			w.handleTimeout(
+				fmt.Sprintf(
+					"moved-funds-sweep-timeout/%s/%d",
+					movingFundsTxHash.Hex(bitcoin.InternalByteOrder),
+					outputIndex,
+				),
+				fmt.Sprintf(
+					"moved funds sweep request [%s:%d] of wallet [0x%x] "+
+						"created at [%s]",
+					// Print the transaction in the same endianness as block
+					// explorers do.
+					movingFundsTxHash.Hex(bitcoin.ReversedByteOrder),
+					outputIndex,
+					request.WalletPublicKeyHash,
+					request.CreatedAt.Format(time.RFC3339),
+				),
+				request.WalletPublicKeyHash,
+				func(walletMembersIDs []uint32) error {
+					return w.chain.NotifyMovedFundsSweepTimeout(
+						movingFundsTxHash,
+						outputIndex,
+						walletMembersIDs,
+					)
+				},
+			)
+		}
+	}
+
+	return nil
+}
+
+// handleTimeout handles the timed out wallet action identified by the given
+// key. If notifying timeouts is enabled, the Bridge is notified using the
+// given notify function which receives the members IDs of the wallet with
+// the given public key hash. Otherwise, the timeout is only alerted about.
+// Failed notifications are logged and retried in the next round. If another
+// maintainer notified about the timeout in the meantime, the action is no
+// longer found timed out.
+func (w *watcher) handleTimeout(
+	key string,
+	description string,
+	walletPublicKeyHash [20]byte,
+	notify func(walletMembersIDs []uint32) error,
+) {
+	if !w.config.NotifyTimeouts {
+		w.alert(key, "%s timed out", description)
+		return
+	}
+
+	logger.Infof("notifying about timed out %s", description)
+
+	walletMembersIDs, err := w.walletMembersIDs(walletPublicKeyHash)
+	if err != nil {
+		logger.Errorf(
+			"cannot notify about timed out %s: [%v]",
+			description,
+			err,
+		)
+		return
+	}
+
+	if err := notify(walletMembersIDs); err != nil {
+		logger.Errorf(
+			"cannot notify about timed out %s: [%v]",
+			description,
+			err,
+		)
+		return
+	}
+
+	logger.Infof("successfully notified about timed out %s", description)
+}
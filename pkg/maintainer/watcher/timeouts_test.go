@@ -0,0 +1,381 @@
+package watcher
+
+import (
+	"testing"
+	"time"
+
+	"github.com/keep-network/keep-core/internal/testutils"
+	"github.com/keep-network/keep-core/pkg/bitcoin"
+	"github.com/keep-network/keep-core/pkg/tbtc"
+)
+
+var (
+	testNow = time.Unix(1700000000, 0)
+
+	testWalletPublicKeyHash = [20]byte{
+		0x8d, 0xb5, 0x0e, 0xb5, 0x20, 0x63, 0xea, 0x9d, 0x98, 0xb3,
+		0xea, 0xc9, 0x14, 0x89, 0xa9, 0x0f, 0x73, 0x89, 0x86, 0xf6,
+	}
+	testTargetWalletPublicKeyHash = [20]byte{
+		0x03, 0xb7, 0x4d, 0x6b, 0x9d, 0xc6, 0x5f, 0x4b, 0xc1, 0x55,
+		0x68, 0x41, 0x0a, 0xe1, 0xb1, 0x7c, 0xc7, 0xc4, 0x37, 0xf7,
+	}
+
+	testWalletID       = [32]byte{0x01}
+	testTargetWalletID = [32]byte{0x02}
+
+	testWalletMembersIDs       = []uint32{1, 2, 3}
+	testTargetWalletMembersIDs = []uint32{4, 5, 6}
+)
+
+func newTestWatcher(
+	chain *localChain,
+	btcChain *localBitcoinChain,
+	notifyTimeouts bool,
+) *watcher {
+	w := newWatcher(
+		Config{
+			NotifyTimeouts:   notifyTimeouts,
+			HistoryDepth:     DefaultHistoryDepth,
+			TransactionLimit: DefaultTransactionLimit,
+		},
+		chain,
+		btcChain,
+	)
+	w.now = func() time.Time { return testNow }
+
+	return w
+}
+
+func TestWatcher_CheckRedemptionTimeouts(t *testing.T) {
+	redeemerOutputScript := bitcoin.Script{
+		0x00, 0x14, 0x7a, 0xc2, 0xd9, 0x37, 0x8a, 0x1c, 0x47, 0xe5, 0x89,
+		0xdf, 0xb8, 0x09, 0x5c, 0xa9, 0x5e, 0xd2, 0x14, 0x0d, 0x27, 0x26,
+	}
+
+	var tests = map[string]struct {
+		requestedAt             time.Time
+		pending                 bool
+		notifyTimeouts          bool
+		expectedNotifications   int
+		expectedAlertsCount     int
+		expectedWalletMemberIDs []uint32
+	}{
+		"request not timed out": {
+			requestedAt:           testNow.Add(-1 * time.Hour),
+			pending:               true,
+			notifyTimeouts:        true,
+			expectedNotifications: 0,
+			expectedAlertsCount:   0,
+		},
+		"request no longer pending": {
+			requestedAt:           testNow.Add(-48 * time.Hour),
+			pending:               false,
+			notifyTimeouts:        true,
+			expectedNotifications: 0,
+			expectedAlertsCount:   0,
+		},
+		"request timed out - notifying disabled": {
+			requestedAt:           testNow.Add(-48 * time.Hour),
+			pending:               true,
+			notifyTimeouts:        false,
+			expectedNotifications: 0,
+			expectedAlertsCount:   1,
+		},
+		"request timed out - notifying enabled": {
+			requestedAt:             testNow.Add(-48 * time.Hour),
+			pending:                 true,
+			notifyTimeouts:          true,
+			expectedNotifications:   1,
+			expectedAlertsCount:     0,
+			expectedWalletMemberIDs: testWalletMembersIDs,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			chain := newLocalChain()
+			chain.redemptionTimeout = uint32((24 * time.Hour).Seconds())
+			chain.addWallet(
+				testWalletPublicKeyHash,
+				&tbtc.WalletChainData{
+					EcdsaWalletID: testWalletID,
+					State:         tbtc.StateLive,
+				},
+				testWalletMembersIDs,
+			)
+			chain.addRedemptionRequest(
+				testWalletPublicKeyHash,
+				&tbtc.RedemptionRequest{
+					RedeemerOutputScript: redeemerOutputScript,
+					RequestedAt:          test.requestedAt,
+				},
+				test.pending,
+			)
+
+			w := newTestWatcher(
+				chain,
+				newLocalBitcoinChain(),
+				test.notifyTimeouts,
+			)
+
+			err := w.checkRedemptionTimeouts(0)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			testutils.AssertIntsEqual(
+				t,
+				"notifications count",
+				test.expectedNotifications,
+				len(chain.notifiedRedemptionTimeouts),
+			)
+			testutils.AssertIntsEqual(
+				t,
+				"alerts count",
+				test.expectedAlertsCount,
+				len(w.alerts),
+			)
+
+			if test.expectedNotifications > 0 {
+				notification := chain.notifiedRedemptionTimeouts[0]
+				testutils.AssertBytesEqual(
+					t,
+					testWalletPublicKeyHash[:],
+					notification.walletPublicKeyHash[:],
+				)
+				testutils.AssertBytesEqual(
+					t,
+					redeemerOutputScript,
+					notification.redeemerOutputScript,
+				)
+				assertMembersIDs(
+					t,
+					test.expectedWalletMemberIDs,
+					notification.walletMembersIDs,
+				)
+			}
+		})
+	}
+}
+
+func TestWatcher_CheckMovingFundsTimeouts(t *testing.T) {
+	var tests = map[string]struct {
+		state                  tbtc.WalletState
+		movingFundsRequestedAt time.Time
+		expectedNotifications  int
+	}{
+		"live wallet": {
+			state:                  tbtc.StateLive,
+			movingFundsRequestedAt: time.Time{},
+			expectedNotifications:  0,
+		},
+		"moving funds not timed out": {
+			state:                  tbtc.StateMovingFunds,
+			movingFundsRequestedAt: testNow.Add(-1 * time.Hour),
+			expectedNotifications:  0,
+		},
+		"moving funds timed out": {
+			state:                  tbtc.StateMovingFunds,
+			movingFundsRequestedAt: testNow.Add(-30 * 24 * time.Hour),
+			expectedNotifications:  1,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			chain := newLocalChain()
+			chain.movingFundsTimeout = uint32((7 * 24 * time.Hour).Seconds())
+			chain.addWallet(
+				testWalletPublicKeyHash,
+				&tbtc.WalletChainData{
+					EcdsaWalletID:          testWalletID,
+					State:                  test.state,
+					MovingFundsRequestedAt: test.movingFundsRequestedAt,
+				},
+				testWalletMembersIDs,
+			)
+
+			w := newTestWatcher(chain, newLocalBitcoinChain(), true)
+
+			wallets, err := w.wallets()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = w.checkMovingFundsTimeouts(wallets)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			testutils.AssertIntsEqual(
+				t,
+				"notifications count",
+				test.expectedNotifications,
+				len(chain.notifiedMovingFundsTimeouts),
+			)
+
+			if test.expectedNotifications > 0 {
+				notification := chain.notifiedMovingFundsTimeouts[0]
+				testutils.AssertBytesEqual(
+					t,
+					testWalletPublicKeyHash[:],
+					notification.walletPublicKeyHash[:],
+				)
+				assertMembersIDs(
+					t,
+					testWalletMembersIDs,
+					notification.walletMembersIDs,
+				)
+			}
+		})
+	}
+}
+
+func TestWatcher_CheckMovedFundsSweepTimeouts(t *testing.T) {
+	chain := newLocalChain()
+	chain.movedFundsSweepTimeout = uint32((7 * 24 * time.Hour).Seconds())
+
+	chain.addWallet(
+		testWalletPublicKeyHash,
+		&tbtc.WalletChainData{
+			EcdsaWalletID: testWalletID,
+			State:         tbtc.StateClosing,
+		},
+		testWalletMembersIDs,
+	)
+	chain.addWallet(
+		testTargetWalletPublicKeyHash,
+		&tbtc.WalletChainData{
+			EcdsaWalletID: testTargetWalletID,
+			State:         tbtc.StateLive,
+		},
+		testTargetWalletMembersIDs,
+	)
+
+	otherTargetWalletPublicKeyHash := [20]byte{0xff}
+
+	// The commitment precedes the start block of the watcher's history.
+	chain.addMovingFundsCommitmentSubmittedEvent(
+		&tbtc.MovingFundsCommitmentSubmittedEvent{
+			WalletPublicKeyHash: testWalletPublicKeyHash,
+			TargetWallets: [][20]byte{
+				testTargetWalletPublicKeyHash,
+				otherTargetWalletPublicKeyHash,
+			},
+			BlockNumber: 10,
+		},
+	)
+
+	movingFundsTxHash := bitcoin.Hash{0xaa}
+
+	chain.addMovingFundsCompletedEvent(
+		&tbtc.MovingFundsCompletedEvent{
+			WalletPublicKeyHash: testWalletPublicKeyHash,
+			MovingFundsTxHash:   movingFundsTxHash,
+			BlockNumber:         950,
+		},
+	)
+
+	// The first request timed out while the second one was already swept.
+	chain.setMovedFundsSweepRequest(
+		movingFundsTxHash,
+		0,
+		&tbtc.MovedFundsSweepRequest{
+			WalletPublicKeyHash: testTargetWalletPublicKeyHash,
+			CreatedAt:           testNow.Add(-30 * 24 * time.Hour),
+			State:               tbtc.MovedFundsStatePending,
+		},
+	)
+	chain.setMovedFundsSweepRequest(
+		movingFundsTxHash,
+		1,
+		&tbtc.MovedFundsSweepRequest{
+			WalletPublicKeyHash: otherTargetWalletPublicKeyHash,
+			CreatedAt:           testNow.Add(-30 * 24 * time.Hour),
+			State:               tbtc.MovedFundsStateProcessed,
+		},
+	)
+
+	w := newTestWatcher(chain, newLocalBitcoinChain(), true)
+
+	err := w.checkMovedFundsSweepTimeouts(900)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertIntsEqual(
+		t,
+		"notifications count",
+		1,
+		len(chain.notifiedMovedFundsSweepTimeouts),
+	)
+
+	notification := chain.notifiedMovedFundsSweepTimeouts[0]
+	testutils.AssertBytesEqual(
+		t,
+		movingFundsTxHash[:],
+		notification.movingFundsTxHash[:],
+	)
+	testutils.AssertUintsEqual(
+		t,
+		"moving funds transaction output index",
+		0,
+		uint64(notification.movingFundsTxOutputIndex),
+	)
+	assertMembersIDs(
+		t,
+		testTargetWalletMembersIDs,
+		notification.walletMembersIDs,
+	)
+}
+
+func TestWatcher_WalletMembersIDs(t *testing.T) {
+	chain := newLocalChain()
+	// The operator of the first member controls the last member as well.
+	walletMembersIDs := []uint32{1, 2, 1}
+	chain.addWallet(
+		testWalletPublicKeyHash,
+		&tbtc.WalletChainData{
+			EcdsaWalletID: testWalletID,
+			State:         tbtc.StateLive,
+		},
+		walletMembersIDs,
+	)
+
+	w := newTestWatcher(chain, newLocalBitcoinChain(), true)
+
+	for i := 0; i < 2; i++ {
+		actualMembersIDs, err := w.walletMembersIDs(testWalletPublicKeyHash)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assertMembersIDs(t, walletMembersIDs, actualMembersIDs)
+	}
+
+	testutils.AssertIntsEqual(
+		t,
+		"wallet operators calls",
+		1,
+		chain.walletOperatorsCalls,
+	)
+}
+
+func assertMembersIDs(t *testing.T, expected []uint32, actual []uint32) {
+	testutils.AssertIntsEqual(
+		t,
+		"wallet members IDs count",
+		len(expected),
+		len(actual),
+	)
+
+	for i := range expected {
+		testutils.AssertUintsEqual(
+			t,
+			"wallet member ID",
+			uint64(expected[i]),
+			uint64(actual[i]),
+		)
+	}
+}
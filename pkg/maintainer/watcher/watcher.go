@@ -0,0 +1,273 @@
+// Package watcher implements a maintainer guarding the tBTC protocol against
+// misbehaving wallets. The watcher tracks pending redemptions, moving funds
+// and moved funds sweeps against their timeouts and looks for wallet
+// transactions that do not match any wallet action known to the Bridge.
+package watcher
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ipfs/go-log/v2"
+
+	"github.com/keep-network/keep-core/pkg/bitcoin"
+	"github.com/keep-network/keep-core/pkg/tbtc"
+)
+
+var logger = log.Logger("keep-maintainer-watcher")
+
+func Initialize(
+	ctx context.Context,
+	config Config,
+	chain Chain,
+	btcChain bitcoin.Chain,
+) {
+	if config.HistoryDepth == 0 {
+		config.HistoryDepth = DefaultHistoryDepth
+	}
+	if config.TransactionLimit == 0 {
+		config.TransactionLimit = DefaultTransactionLimit
+	}
+	if config.RestartBackoffTime == 0 {
+		config.RestartBackoffTime = DefaultRestartBackoffTime
+	}
+	if config.IdleBackoffTime == 0 {
+		config.IdleBackoffTime = DefaultIdleBackoffTime
+	}
+
+	watcher := newWatcher(config, chain, btcChain)
+
+	go watcher.startControlLoop(ctx)
+}
+
+// watcher is the part of maintainer responsible for guarding the protocol
+// against misbehaving wallets.
+type watcher struct {
+	config   Config
+	chain    Chain
+	btcChain bitcoin.Chain
+	// spendingTxFinder is nil if the Bitcoin chain cannot find transactions
+	// spending given outpoints. Transactions spending only unswept deposits
+	// are not checked in that case.
+	spendingTxFinder bitcoin.SpendingTxFinder
+
+	// now returns the current time. Timeouts are evaluated against it.
+	now func() time.Time
+
+	alertsMutex sync.Mutex
+	// alerts holds the keys of problems that were already reported so they
+	// are not reported again on every round.
+	alerts map[string]bool
+
+	walletMembersIDsMutex sync.Mutex
+	// walletMembersIDsCache holds members IDs of wallets, by ECDSA wallet ID.
+	// Members of a wallet never change so they are fetched only once.
+	walletMembersIDsCache map[[32]byte][]uint32
+}
+
+func newWatcher(config Config, chain Chain, btcChain bitcoin.Chain) *watcher {
+	spendingTxFinder, ok := btcChain.(bitcoin.SpendingTxFinder)
+	if !ok {
+		logger.Warn(
+			"the Bitcoin chain cannot find spending transactions; " +
+				"transactions spending only unswept deposits will not " +
+				"be checked",
+		)
+	}
+
+	return &watcher{
+		config:           config,
+		chain:            chain,
+		btcChain:         btcChain,
+		spendingTxFinder: spendingTxFinder,
+		now:              time.Now,
+		alerts:           make(map[string]bool),
+
+		walletMembersIDsCache: make(map[[32]byte][]uint32),
+	}
+}
+
+// startControlLoop starts the loop responsible for controlling the watcher.
+func (w *watcher) startControlLoop(ctx context.Context) {
+	logger.Info("starting watcher maintainer")
+
+	defer func() {
+		logger.Info("stopping watcher maintainer")
+	}()
+
+	for {
+		err := w.watch(ctx)
+		if err != nil {
+			logger.Errorf(
+				"error while watching the protocol: [%v]; restarting "+
+					"maintainer",
+				err,
+			)
+		}
+
+		select {
+		case <-time.After(w.config.RestartBackoffTime):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// watch runs rounds of watching the protocol until the context is done or
+// a round fails.
+func (w *watcher) watch(ctx context.Context) error {
+	for {
+		if err := w.watchRound(); err != nil {
+			return err
+		}
+
+		select {
+		case <-time.After(w.config.IdleBackoffTime):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// watchRound performs all checks of the watcher once.
+func (w *watcher) watchRound() error {
+	startBlock, err := w.startBlock()
+	if err != nil {
+		return fmt.Errorf("cannot determine start block: [%v]", err)
+	}
+
+	if err := w.checkRedemptionTimeouts(startBlock); err != nil {
+		return fmt.Errorf("error while checking redemptions: [%v]", err)
+	}
+
+	wallets, err := w.wallets()
+	if err != nil {
+		return fmt.Errorf("cannot get wallets: [%v]", err)
+	}
+
+	if err := w.checkMovingFundsTimeouts(wallets); err != nil {
+		return fmt.Errorf("error while checking moving funds: [%v]", err)
+	}
+
+	if err := w.checkMovedFundsSweepTimeouts(startBlock); err != nil {
+		return fmt.Errorf(
+			"error while checking moved funds sweeps: [%v]",
+			err,
+		)
+	}
+
+	if err := w.checkWalletTransactions(startBlock, wallets); err != nil {
+		return fmt.Errorf(
+			"error while checking wallet transactions: [%v]",
+			err,
+		)
+	}
+
+	logger.Infof("finished round of watching the protocol")
+
+	return nil
+}
+
+// startBlock returns the first block of the history inspected by the watcher.
+func (w *watcher) startBlock() (uint64, error) {
+	blockCounter, err := w.chain.BlockCounter()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get block counter: [%v]", err)
+	}
+
+	currentBlock, err := blockCounter.CurrentBlock()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get current block: [%v]", err)
+	}
+
+	if currentBlock < w.config.HistoryDepth {
+		return 0, nil
+	}
+
+	return currentBlock - w.config.HistoryDepth, nil
+}
+
+// walletInfo is a wallet registered in the Bridge along with its current
+// on-chain data.
+type walletInfo struct {
+	publicKeyHash [20]byte
+	data          *tbtc.WalletChainData
+}
+
+// wallets returns all wallets registered in the Bridge.
+func (w *watcher) wallets() ([]*walletInfo, error) {
+	events, err := w.chain.PastNewWalletRegisteredEvents(nil)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to get past new wallet registered events: [%v]",
+			err,
+		)
+	}
+
+	wallets := make([]*walletInfo, 0, len(events))
+	for _, event := range events {
+		data, err := w.chain.GetWallet(event.WalletPublicKeyHash)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"failed to get wallet [0x%x]: [%v]",
+				event.WalletPublicKeyHash,
+				err,
+			)
+		}
+
+		wallets = append(wallets, &walletInfo{
+			publicKeyHash: event.WalletPublicKeyHash,
+			data:          data,
+		})
+	}
+
+	return wallets, nil
+}
+
+// alert reports the problem identified by the given key unless it was
+// already reported.
+func (w *watcher) alert(key string, format string, args ...interface{}) {
+	w.alertsMutex.Lock()
+	defer w.alertsMutex.Unlock()
+
+	if w.alerts[key] {
+		return
+	}
+	w.alerts[key] = true
+
+	logger.Warnf("ALERT: "+format, args...)
+}
+
+// walletMembersIDs returns the members IDs of the wallet with the given
+// public key hash.
+func (w *watcher) walletMembersIDs(walletPublicKeyHash [20]byte) ([]uint32, error) {
+	walletData, err := w.chain.GetWallet(walletPublicKeyHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get wallet: [%v]", err)
+	}
+
+	w.walletMembersIDsMutex.Lock()
+	defer w.walletMembersIDsMutex.Unlock()
+
+	if walletMembersIDs, ok := w.walletMembersIDsCache[walletData.EcdsaWalletID]; ok {
+		return walletMembersIDs, nil
+	}
+
+	walletOperators, err := w.chain.GetWalletOperators(
+		walletData.EcdsaWalletID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get wallet operators: [%v]", err)
+	}
+
+	walletMembersIDs, err := tbtc.WalletMembersIDs(walletOperators, w.chain)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get wallet members IDs: [%v]", err)
+	}
+
+	w.walletMembersIDsCache[walletData.EcdsaWalletID] = walletMembersIDs
+
+	return walletMembersIDs, nil
+}
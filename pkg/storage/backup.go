@@ -0,0 +1,318 @@
+package storage
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/scrypt"
+
+	"github.com/keep-network/keep-common/pkg/persistence"
+)
+
+// BackupVersion is the version of the keystore backup archive format
+// produced by SealBackup.
+const BackupVersion = uint16(1)
+
+const (
+	// backupMagic is the prefix identifying keystore backup archives.
+	backupMagic = "KEEPBKP"
+	// backupSaltLength is the length of the random salt used to derive
+	// the archive encryption key from the password.
+	backupSaltLength = 16
+	// Parameters of the scrypt key derivation function.
+	backupScryptN = 1 << 15
+	backupScryptR = 8
+	backupScryptP = 1
+	// backupKeyLength is the length of the AES-256 archive encryption key.
+	backupKeyLength = 32
+	// backupHeaderLength is the length of the archive header consisting of
+	// the magic, the format version and the key derivation salt.
+	backupHeaderLength = len(backupMagic) + 2 + backupSaltLength
+)
+
+// BackupEntry is a single keystore file stored in the backup archive.
+type BackupEntry struct {
+	// Keystore is the name of the keystore persistence the file belongs to,
+	// e.g. beacon or tbtc.
+	Keystore  string `json:"keystore"`
+	Directory string `json:"directory"`
+	Name      string `json:"name"`
+	Data      []byte `json:"data"`
+}
+
+// String returns the path of the entry relative to the keystore directory.
+func (be *BackupEntry) String() string {
+	return fmt.Sprintf(
+		"%s/%s/%s",
+		be.Keystore,
+		be.Directory,
+		strings.TrimPrefix(be.Name, "/"),
+	)
+}
+
+// Backup is the content of the keystore backup archive.
+type Backup struct {
+	CreatedAt time.Time      `json:"created_at"`
+	Entries   []*BackupEntry `json:"entries"`
+}
+
+// SealBackup serializes the given backup into an archive encrypted with
+// a key derived from the given password. The archive is encrypted using
+// AES-256-GCM so any modification of the archive, including its header,
+// is detected upon opening.
+func SealBackup(backup *Backup, password string) ([]byte, error) {
+	payload, err := json.Marshal(backup)
+	if err != nil {
+		return nil, fmt.Errorf("cannot marshal backup: [%w]", err)
+	}
+
+	header := make([]byte, backupHeaderLength)
+	copy(header, backupMagic)
+	binary.BigEndian.PutUint16(header[len(backupMagic):], BackupVersion)
+	salt := header[len(backupMagic)+2:]
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("cannot generate salt: [%w]", err)
+	}
+
+	aead, err := newBackupCipher(password, salt)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("cannot generate nonce: [%w]", err)
+	}
+
+	archive := append(header, nonce...)
+	archive = aead.Seal(archive, nonce, payload, header)
+
+	return archive, nil
+}
+
+// OpenBackup decrypts the given archive using the given password and
+// deserializes the backup stored there. Returns an error if the archive
+// version is not supported, the password is wrong, or the archive has been
+// modified.
+func OpenBackup(archive []byte, password string) (*Backup, error) {
+	if len(archive) < backupHeaderLength ||
+		!bytes.Equal(archive[:len(backupMagic)], []byte(backupMagic)) {
+		return nil, fmt.Errorf("not a keystore backup archive")
+	}
+
+	header := archive[:backupHeaderLength]
+
+	version := binary.BigEndian.Uint16(header[len(backupMagic):])
+	if version != BackupVersion {
+		return nil, fmt.Errorf(
+			"unsupported backup archive version [%v]; supported version is [%v]",
+			version,
+			BackupVersion,
+		)
+	}
+
+	aead, err := newBackupCipher(password, header[len(backupMagic)+2:])
+	if err != nil {
+		return nil, err
+	}
+
+	body := archive[backupHeaderLength:]
+	if len(body) < aead.NonceSize() {
+		return nil, fmt.Errorf("backup archive is truncated")
+	}
+
+	payload, err := aead.Open(
+		nil,
+		body[:aead.NonceSize()],
+		body[aead.NonceSize():],
+		header,
+	)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"cannot decrypt backup archive; the password is wrong " +
+				"or the archive is corrupted",
+		)
+	}
+
+	backup := &Backup{}
+	if err := json.Unmarshal(payload, backup); err != nil {
+		return nil, fmt.Errorf("cannot unmarshal backup: [%w]", err)
+	}
+
+	return backup, nil
+}
+
+func newBackupCipher(password string, salt []byte) (cipher.AEAD, error) {
+	key, err := scrypt.Key(
+		[]byte(password),
+		salt,
+		backupScryptN,
+		backupScryptR,
+		backupScryptP,
+		backupKeyLength,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("cannot derive encryption key: [%w]", err)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create block cipher: [%w]", err)
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create AEAD cipher: [%w]", err)
+	}
+
+	return aead, nil
+}
+
+// ReadBackupEntries reads all files of the given keystore persistence.
+// Contrary to the regular loading of the keystore, any read error is
+// returned as the backup must be complete.
+func ReadBackupEntries(
+	handle persistence.ProtectedHandle,
+	keystore string,
+) ([]*BackupEntry, error) {
+	var (
+		entries []*BackupEntry
+		errs    []string
+	)
+
+	descriptorsChan, errorsChan := handle.ReadAll()
+
+	// Read descriptors and errors concurrently as the order in which they
+	// are written to channels is unknown.
+	var wg sync.WaitGroup
+	wg.Add(2)
+
+	go func() {
+		defer wg.Done()
+
+		for descriptor := range descriptorsChan {
+			content, err := descriptor.Content()
+			if err != nil {
+				errs = append(errs, fmt.Sprintf(
+					"cannot read file [%v] in directory [%v]: [%v]",
+					descriptor.Name(),
+					descriptor.Directory(),
+					err,
+				))
+				continue
+			}
+
+			entries = append(entries, &BackupEntry{
+				Keystore:  keystore,
+				Directory: descriptor.Directory(),
+				Name:      strings.TrimPrefix(descriptor.Name(), "/"),
+				Data:      content,
+			})
+		}
+	}()
+
+	var readErrs []string
+	go func() {
+		defer wg.Done()
+
+		for err := range errorsChan {
+			readErrs = append(readErrs, err.Error())
+		}
+	}()
+
+	wg.Wait()
+
+	if errs = append(errs, readErrs...); len(errs) > 0 {
+		return nil, fmt.Errorf(
+			"cannot read [%s] keystore: [%s]",
+			keystore,
+			strings.Join(errs, "; "),
+		)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].String() < entries[j].String()
+	})
+
+	return entries, nil
+}
+
+// SelectRestorableEntries returns those of the given entries that are
+// missing in the given keystore persistence. Entries already present in
+// the keystore with the same content are skipped. If any entry would
+// overwrite a keystore file with a different content, an error is returned;
+// files present in the keystore are considered newer than the backup.
+func SelectRestorableEntries(
+	handle persistence.ProtectedHandle,
+	entries []*BackupEntry,
+) ([]*BackupEntry, error) {
+	existingEntries, err := ReadBackupEntries(handle, "")
+	if err != nil {
+		return nil, err
+	}
+
+	existingData := make(map[string][]byte, len(existingEntries))
+	for _, entry := range existingEntries {
+		existingData[entry.Directory+"/"+entry.Name] = entry.Data
+	}
+
+	var (
+		restorable []*BackupEntry
+		conflicts  []string
+	)
+	for _, entry := range entries {
+		key := entry.Directory + "/" + strings.TrimPrefix(entry.Name, "/")
+
+		data, exists := existingData[key]
+		if !exists {
+			restorable = append(restorable, entry)
+			continue
+		}
+
+		if !bytes.Equal(data, entry.Data) {
+			conflicts = append(conflicts, entry.String())
+		}
+	}
+
+	if len(conflicts) > 0 {
+		return nil, fmt.Errorf(
+			"keystore holds different data for entries [%s]; refusing to "+
+				"overwrite them",
+			strings.Join(conflicts, ", "),
+		)
+	}
+
+	return restorable, nil
+}
+
+// SaveBackupEntries saves the given entries using the given keystore
+// persistence. Entries should be selected using SelectRestorableEntries
+// beforehand so no keystore file is overwritten.
+func SaveBackupEntries(
+	handle persistence.ProtectedHandle,
+	entries []*BackupEntry,
+) error {
+	for _, entry := range entries {
+		// File names are prefixed with a slash the same way the beacon and
+		// tbtc applications do when saving their keystore files.
+		err := handle.Save(
+			entry.Data,
+			entry.Directory,
+			"/"+strings.TrimPrefix(entry.Name, "/"),
+		)
+		if err != nil {
+			return fmt.Errorf("cannot save entry [%s]: [%w]", entry, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,219 @@
+package storage
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/keep-network/keep-common/pkg/persistence"
+
+	"github.com/keep-network/keep-core/internal/testutils"
+)
+
+func TestSealOpenBackup(t *testing.T) {
+	backup := &Backup{
+		CreatedAt: time.Unix(1700000000, 0).UTC(),
+		Entries: []*BackupEntry{
+			{
+				Keystore:  "tbtc",
+				Directory: "a1b2",
+				Name:      "membership_1",
+				Data:      []byte{0x01, 0x02, 0x03},
+			},
+			{
+				Keystore:  "beacon",
+				Directory: "c3d4",
+				Name:      "membership_7",
+				Data:      []byte{0x04},
+			},
+		},
+	}
+
+	archive, err := SealBackup(backup, "password")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var tests = map[string]struct {
+		archive       func() []byte
+		password      string
+		expectedError bool
+	}{
+		"correct password": {
+			archive:  func() []byte { return archive },
+			password: "password",
+		},
+		"wrong password": {
+			archive:       func() []byte { return archive },
+			password:      "wrong",
+			expectedError: true,
+		},
+		"modified payload": {
+			archive: func() []byte {
+				modified := append([]byte{}, archive...)
+				modified[len(modified)-1] ^= 0xff
+				return modified
+			},
+			password:      "password",
+			expectedError: true,
+		},
+		"modified salt": {
+			archive: func() []byte {
+				modified := append([]byte{}, archive...)
+				modified[backupHeaderLength-1] ^= 0xff
+				return modified
+			},
+			password:      "password",
+			expectedError: true,
+		},
+		"unsupported version": {
+			archive: func() []byte {
+				modified := append([]byte{}, archive...)
+				modified[len(backupMagic)+1]++
+				return modified
+			},
+			password:      "password",
+			expectedError: true,
+		},
+		"truncated archive": {
+			archive:       func() []byte { return archive[:backupHeaderLength+4] },
+			password:      "password",
+			expectedError: true,
+		},
+		"not an archive": {
+			archive:       func() []byte { return []byte("something else") },
+			password:      "password",
+			expectedError: true,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			opened, err := OpenBackup(test.archive(), test.password)
+			if test.expectedError {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(backup, opened) {
+				t.Errorf(
+					"unexpected backup\nexpected: %+v\nactual:   %+v",
+					backup,
+					opened,
+				)
+			}
+		})
+	}
+}
+
+func TestReadAndRestoreBackupEntries(t *testing.T) {
+	source := newProtectedHandle(t)
+
+	for name, data := range map[string][]byte{
+		"/membership_1": {0x01},
+		"/membership_2": {0x02},
+	} {
+		if err := source.Save(data, "wallet", name); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	entries, err := ReadBackupEntries(source, "tbtc")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertIntsEqual(t, "entries count", 2, len(entries))
+	testutils.AssertStringsEqual(
+		t,
+		"first entry",
+		"tbtc/wallet/membership_1",
+		entries[0].String(),
+	)
+
+	var tests = map[string]struct {
+		existing           map[string][]byte
+		expectedRestorable int
+		expectedError      bool
+	}{
+		"empty keystore": {
+			existing:           map[string][]byte{},
+			expectedRestorable: 2,
+		},
+		"same entry present": {
+			existing:           map[string][]byte{"/membership_1": {0x01}},
+			expectedRestorable: 1,
+		},
+		"all entries present": {
+			existing: map[string][]byte{
+				"/membership_1": {0x01},
+				"/membership_2": {0x02},
+			},
+			expectedRestorable: 0,
+		},
+		"different entry present": {
+			existing:      map[string][]byte{"/membership_2": {0x03}},
+			expectedError: true,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			target := newProtectedHandle(t)
+			for name, data := range test.existing {
+				if err := target.Save(data, "wallet", name); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			restorable, err := SelectRestorableEntries(target, entries)
+			if test.expectedError {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			testutils.AssertIntsEqual(
+				t,
+				"restorable entries count",
+				test.expectedRestorable,
+				len(restorable),
+			)
+
+			if err := SaveBackupEntries(target, restorable); err != nil {
+				t.Fatal(err)
+			}
+
+			restored, err := ReadBackupEntries(target, "tbtc")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(entries, restored) {
+				t.Errorf(
+					"unexpected keystore entries\nexpected: %v\nactual:   %v",
+					entries,
+					restored,
+				)
+			}
+		})
+	}
+}
+
+func newProtectedHandle(t *testing.T) persistence.ProtectedHandle {
+	handle, err := persistence.NewProtectedDiskHandle(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return handle
+}
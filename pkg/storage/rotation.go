@@ -0,0 +1,279 @@
+package storage
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/ipfs/go-log"
+
+	"github.com/keep-network/keep-common/pkg/encryption"
+)
+
+var logger = log.Logger("keep-storage")
+
+const (
+	// stagingDirSuffix is the suffix of directories holding data re-encrypted
+	// under the new password during the password rotation.
+	stagingDirSuffix = ".rotation"
+	// previousDirSuffix is the suffix of directories holding data encrypted
+	// under the previous password during the password rotation.
+	previousDirSuffix = ".previous"
+)
+
+// encryptionCheckValue is the value stored in the encryption check file.
+var encryptionCheckValue = []byte("keep-storage-encryption-check")
+
+// newEncryptionBox creates a box encrypting data the same way as the
+// encrypted persistence handles do.
+func newEncryptionBox(password string) encryption.Box {
+	return encryption.NewBox(sha256.Sum256([]byte(password)))
+}
+
+// checkEncryptionPassword verifies the given password against the encryption
+// check file stored in the given key store directory. If the file does not
+// exist yet, it is created using the given password.
+func checkEncryptionPassword(keystoreDir string, password string) error {
+	box := newEncryptionBox(password)
+	path := filepath.Join(keystoreDir, encryptionCheckFileName)
+
+	content, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		encrypted, err := box.Encrypt(encryptionCheckValue)
+		if err != nil {
+			return fmt.Errorf("cannot encrypt encryption check value: [%w]", err)
+		}
+
+		if err := os.WriteFile(path, encrypted, 0600); err != nil {
+			return fmt.Errorf("cannot write encryption check file: [%w]", err)
+		}
+
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("cannot read encryption check file: [%w]", err)
+	}
+
+	decrypted, err := box.Decrypt(content)
+	if err != nil || !bytes.Equal(decrypted, encryptionCheckValue) {
+		return fmt.Errorf(
+			"storage encryption password does not match the password " +
+				"the storage was encrypted with; if the password was " +
+				"changed, the storage password must be rotated first",
+		)
+	}
+
+	return nil
+}
+
+// RotateEncryptionPassword re-encrypts all key store and work files of
+// the storage under the new password. All files are first re-encrypted into
+// staging directories and verified. Then, the staging directories replace
+// the original ones. If any step fails, the storage is left encrypted under
+// the current password.
+func RotateEncryptionPassword(
+	config Config,
+	currentPassword string,
+	newPassword string,
+) error {
+	if len(newPassword) == 0 {
+		return fmt.Errorf("new password must not be empty")
+	}
+	if newPassword == currentPassword {
+		return fmt.Errorf("new password must differ from the current one")
+	}
+
+	storageRootDir := filepath.Clean(config.Dir)
+
+	var dirs []string
+	for _, dirName := range []string{keyStoreDirName, workDirName} {
+		dir := filepath.Join(storageRootDir, dirName)
+
+		for _, leftover := range []string{
+			dir + stagingDirSuffix,
+			dir + previousDirSuffix,
+		} {
+			if _, err := os.Stat(leftover); !errors.Is(err, fs.ErrNotExist) {
+				return fmt.Errorf(
+					"directory [%s] left by an interrupted rotation exists; "+
+						"inspect and remove it before rotating again",
+					leftover,
+				)
+			}
+		}
+
+		if _, err := os.Stat(dir); errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+
+		dirs = append(dirs, dir)
+	}
+
+	if err := checkEncryptionPassword(
+		filepath.Join(storageRootDir, keyStoreDirName),
+		currentPassword,
+	); err != nil {
+		return err
+	}
+
+	currentBox := newEncryptionBox(currentPassword)
+	newBox := newEncryptionBox(newPassword)
+
+	removeStagingDirs := func() {
+		for _, dir := range dirs {
+			if err := os.RemoveAll(dir + stagingDirSuffix); err != nil {
+				logger.Errorf(
+					"cannot remove staging directory [%s]: [%v]",
+					dir+stagingDirSuffix,
+					err,
+				)
+			}
+		}
+	}
+
+	for _, dir := range dirs {
+		if err := reencryptDir(
+			dir,
+			dir+stagingDirSuffix,
+			currentBox,
+			newBox,
+		); err != nil {
+			removeStagingDirs()
+			return fmt.Errorf("cannot re-encrypt [%s]: [%w]", dir, err)
+		}
+	}
+
+	// Swap directories. Each swap consists of two renames. Keep track of
+	// the performed renames to revert them in the reverse order on failure.
+	type rename struct{ from, to string }
+	var performed []rename
+
+	rollback := func() {
+		for i := len(performed) - 1; i >= 0; i-- {
+			if err := os.Rename(performed[i].to, performed[i].from); err != nil {
+				logger.Errorf(
+					"cannot revert rename of [%s] to [%s]: [%v]",
+					performed[i].from,
+					performed[i].to,
+					err,
+				)
+			}
+		}
+		removeStagingDirs()
+	}
+
+	for _, dir := range dirs {
+		for _, r := range []rename{
+			{from: dir, to: dir + previousDirSuffix},
+			{from: dir + stagingDirSuffix, to: dir},
+		} {
+			if err := os.Rename(r.from, r.to); err != nil {
+				rollback()
+				return fmt.Errorf(
+					"cannot rename [%s] to [%s]: [%w]",
+					r.from,
+					r.to,
+					err,
+				)
+			}
+
+			performed = append(performed, r)
+		}
+	}
+
+	for _, dir := range dirs {
+		if err := os.RemoveAll(dir + previousDirSuffix); err != nil {
+			// The rotation succeeded. Leftovers encrypted under the previous
+			// password must be removed manually.
+			logger.Warnf(
+				"cannot remove directory [%s] encrypted under the previous "+
+					"password: [%v]",
+				dir+previousDirSuffix,
+				err,
+			)
+		}
+	}
+
+	return nil
+}
+
+// reencryptDir decrypts all files of the source directory using the current
+// box and writes them, encrypted using the new box, to the same paths in
+// the target directory. Every written file is verified by decrypting it
+// back.
+func reencryptDir(
+	sourceDir string,
+	targetDir string,
+	currentBox encryption.Box,
+	newBox encryption.Box,
+) error {
+	return filepath.WalkDir(
+		sourceDir,
+		func(path string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			relativePath, err := filepath.Rel(sourceDir, path)
+			if err != nil {
+				return err
+			}
+			targetPath := filepath.Join(targetDir, relativePath)
+
+			info, err := entry.Info()
+			if err != nil {
+				return err
+			}
+
+			if entry.IsDir() {
+				return os.MkdirAll(targetPath, info.Mode().Perm())
+			}
+
+			if !info.Mode().IsRegular() {
+				return fmt.Errorf("[%s] is not a regular file", path)
+			}
+
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+
+			plaintext, err := currentBox.Decrypt(content)
+			if err != nil {
+				return fmt.Errorf("cannot decrypt [%s]: [%w]", path, err)
+			}
+
+			ciphertext, err := newBox.Encrypt(plaintext)
+			if err != nil {
+				return fmt.Errorf("cannot encrypt [%s]: [%w]", path, err)
+			}
+
+			if err := os.WriteFile(
+				targetPath,
+				ciphertext,
+				info.Mode().Perm(),
+			); err != nil {
+				return err
+			}
+
+			written, err := os.ReadFile(targetPath)
+			if err != nil {
+				return err
+			}
+
+			verified, err := newBox.Decrypt(written)
+			if err != nil || !bytes.Equal(verified, plaintext) {
+				return fmt.Errorf(
+					"verification of re-encrypted [%s] failed",
+					path,
+				)
+			}
+
+			return nil
+		},
+	)
+}
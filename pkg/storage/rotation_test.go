@@ -0,0 +1,148 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/keep-network/keep-core/internal/testutils"
+)
+
+func TestRotateEncryptionPassword(t *testing.T) {
+	files := map[string][]byte{
+		"keystore/tbtc/current/wallet/membership_1":  {0x01},
+		"keystore/tbtc/archive/closed/membership_2":  {0x02},
+		"keystore/beacon/current/group/membership_3": {0x03},
+		"work/tbtc/journal/segment_0000000000":       {0x04},
+	}
+
+	var tests = map[string]struct {
+		currentPassword string
+		newPassword     string
+		prepare         func(t *testing.T, storageDir string)
+		expectedError   bool
+	}{
+		"successful rotation": {
+			currentPassword: "current",
+			newPassword:     "new",
+		},
+		"empty new password": {
+			currentPassword: "current",
+			newPassword:     "",
+			expectedError:   true,
+		},
+		"same password": {
+			currentPassword: "current",
+			newPassword:     "current",
+			expectedError:   true,
+		},
+		"leftovers of interrupted rotation": {
+			currentPassword: "current",
+			newPassword:     "new",
+			prepare: func(t *testing.T, storageDir string) {
+				err := os.Mkdir(
+					filepath.Join(storageDir, "work"+previousDirSuffix),
+					0700,
+				)
+				if err != nil {
+					t.Fatal(err)
+				}
+			},
+			expectedError: true,
+		},
+		"re-encryption failure": {
+			currentPassword: "current",
+			newPassword:     "new",
+			prepare: func(t *testing.T, storageDir string) {
+				// Only regular files can be re-encrypted.
+				err := os.Symlink(
+					filepath.Join(storageDir, "keystore"),
+					filepath.Join(storageDir, "work", "link"),
+				)
+				if err != nil {
+					t.Fatal(err)
+				}
+			},
+			expectedError: true,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			config := Config{Dir: t.TempDir()}
+
+			if _, err := Initialize(config, test.currentPassword); err != nil {
+				t.Fatal(err)
+			}
+
+			for path, content := range files {
+				fullPath := filepath.Join(config.Dir, path)
+				if err := os.MkdirAll(filepath.Dir(fullPath), 0700); err != nil {
+					t.Fatal(err)
+				}
+
+				encrypted, err := newEncryptionBox(test.currentPassword).
+					Encrypt(content)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if err := os.WriteFile(fullPath, encrypted, 0600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if test.prepare != nil {
+				test.prepare(t, config.Dir)
+			}
+
+			err := RotateEncryptionPassword(
+				config,
+				test.currentPassword,
+				test.newPassword,
+			)
+
+			expectedPassword := test.newPassword
+			if test.expectedError {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				expectedPassword = test.currentPassword
+			} else if err != nil {
+				t.Fatal(err)
+			}
+
+			for path, content := range files {
+				encrypted, err := os.ReadFile(filepath.Join(config.Dir, path))
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				decrypted, err := newEncryptionBox(expectedPassword).
+					Decrypt(encrypted)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				testutils.AssertBytesEqual(t, content, decrypted)
+			}
+
+			for _, dirName := range []string{keyStoreDirName, workDirName} {
+				_, err := os.Stat(
+					filepath.Join(config.Dir, dirName+stagingDirSuffix),
+				)
+				testutils.AssertBoolsEqual(
+					t,
+					"staging directory removed",
+					true,
+					os.IsNotExist(err),
+				)
+			}
+
+			// The storage must be usable with the expected password.
+			if _, err := Initialize(config, expectedPassword); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
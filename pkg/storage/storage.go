@@ -12,6 +12,20 @@ import (
 type Config struct {
 	// Path to the persistent storage directory on disk.
 	Dir string
+	// Password used to encrypt the persisted data. If not set, the data
+	// are encrypted with the operator's Ethereum key file password.
+	Password string
+}
+
+// EncryptionPassword returns the password used to encrypt the persisted
+// data. The dedicated storage password takes precedence over the given
+// default password.
+func (c Config) EncryptionPassword(defaultPassword string) string {
+	if len(c.Password) > 0 {
+		return c.Password
+	}
+
+	return defaultPassword
 }
 
 const (
@@ -25,6 +39,10 @@ const (
 	// lead to losing rewards as a result of inactivity but is not
 	// a protocol violation.
 	workDirName = "work"
+	// The encryption check file is stored in the key store directory and
+	// holds a known value encrypted with the storage encryption password.
+	// It allows to detect a wrong password before any stored data are read.
+	encryptionCheckFileName = ".encryption_check"
 )
 
 // Storage is a disk persistent storage for the client.
@@ -66,6 +84,13 @@ func Initialize(config Config, encryptionPassword string) (Storage, error) {
 
 	storage.encryptionPassword = encryptionPassword
 
+	if err := checkEncryptionPassword(
+		storage.keystoreDir,
+		encryptionPassword,
+	); err != nil {
+		return storage, err
+	}
+
 	return storage, nil
 }
 
@@ -0,0 +1,290 @@
+package tbtc
+
+import (
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/keep-network/keep-core/pkg/bitcoin"
+	"github.com/keep-network/keep-core/pkg/clientinfo"
+	"github.com/keep-network/keep-core/pkg/protocol/group"
+)
+
+// APIWallet describes data structure of a wallet exposed by the operator API.
+type APIWallet struct {
+	PublicKeyHash string `json:"public_key_hash"`
+	PublicKey     string `json:"public_key"`
+	// MemberIndexes are signing group member indexes controlled by the node.
+	MemberIndexes    []group.MemberIndex `json:"member_indexes"`
+	SigningGroupSize int                 `json:"signing_group_size"`
+	// State is the on-chain state of the wallet. Empty if the state could
+	// not be determined; StateError holds the reason then.
+	State      string `json:"state,omitempty"`
+	StateError string `json:"state_error,omitempty"`
+	// Action is the wallet action currently executed by the node. Empty if
+	// the wallet is idle.
+	Action string `json:"action,omitempty"`
+}
+
+// APIWalletAction describes data structure of an in-flight wallet action
+// exposed by the operator API.
+type APIWalletAction struct {
+	WalletPublicKeyHash string `json:"wallet_public_key_hash"`
+	Action              string `json:"action"`
+}
+
+// APIDKG describes data structure of the DKG state exposed by the operator
+// API.
+type APIDKG struct {
+	State          string `json:"state,omitempty"`
+	StateError     string `json:"state_error,omitempty"`
+	PreParamsCount int    `json:"pre_params_count"`
+}
+
+// registerOperatorAPI registers endpoints of the read-only operator API
+// exposing the state of the given node.
+func registerOperatorAPI(clientInfo *clientinfo.Registry, node *node) {
+	clientInfo.RegisterAPISource(
+		"tbtc/wallets",
+		func(query url.Values) (interface{}, error) {
+			return node.apiWallets(query)
+		},
+	)
+	clientInfo.RegisterAPISource(
+		"tbtc/actions",
+		func(query url.Values) (interface{}, error) {
+			return node.apiActions(), nil
+		},
+	)
+	clientInfo.RegisterAPISource(
+		"tbtc/coordination_windows",
+		func(query url.Values) (interface{}, error) {
+			summary := node.GetCoordinationWindowsSummary()
+			if summary == nil {
+				return &WindowMetricsSummary{Windows: []*windowMetrics{}}, nil
+			}
+			return summary, nil
+		},
+	)
+	clientInfo.RegisterAPISource(
+		"tbtc/dkg",
+		func(query url.Values) (interface{}, error) {
+			return node.apiDKG(), nil
+		},
+	)
+	clientInfo.RegisterAPISource(
+		"tbtc/journal",
+		func(query url.Values) (interface{}, error) {
+			journalQuery, err := parseAPIJournalQuery(query)
+			if err != nil {
+				return nil, err
+			}
+			return node.journal.query(journalQuery)
+		},
+	)
+	clientInfo.RegisterAPISource(
+		"tbtc/attempt_timing",
+		func(query url.Values) (interface{}, error) {
+			journalQuery, err := parseAPIJournalQuery(query)
+			if err != nil {
+				return nil, err
+			}
+			entries, err := node.journal.query(journalQuery)
+			if err != nil {
+				return nil, err
+			}
+			return NewAttemptTimingReport(entries), nil
+		},
+	)
+}
+
+// apiWallets returns wallets controlled by the node. If the wallet query
+// parameter holds a wallet public key hash, only that wallet is returned.
+func (n *node) apiWallets(query url.Values) ([]*APIWallet, error) {
+	walletPublicKeyHash, err := parseAPIWalletPublicKeyHash(query)
+	if err != nil {
+		return nil, err
+	}
+
+	actions := n.walletDispatcher.inFlightActions()
+
+	wallets := make([]*APIWallet, 0)
+	for _, walletPublicKey := range n.walletRegistry.getWalletsPublicKeys() {
+		currentWalletPublicKeyHash := bitcoin.PublicKeyHash(walletPublicKey)
+		if walletPublicKeyHash != nil &&
+			*walletPublicKeyHash != currentWalletPublicKeyHash {
+			continue
+		}
+
+		walletPublicKeyBytes, err := marshalPublicKey(walletPublicKey)
+		if err != nil {
+			return nil, err
+		}
+
+		signers := n.walletRegistry.getSigners(walletPublicKey)
+		if len(signers) == 0 {
+			// The wallet was archived in the meantime.
+			continue
+		}
+
+		apiWallet := &APIWallet{
+			PublicKeyHash:    formatWalletPublicKeyHash(currentWalletPublicKeyHash),
+			PublicKey:        "0x" + hex.EncodeToString(walletPublicKeyBytes),
+			MemberIndexes:    make([]group.MemberIndex, 0, len(signers)),
+			SigningGroupSize: len(signers[0].wallet.signingGroupOperators),
+		}
+
+		for _, signer := range signers {
+			apiWallet.MemberIndexes = append(
+				apiWallet.MemberIndexes,
+				signer.signingGroupMemberIndex,
+			)
+		}
+		sort.Slice(apiWallet.MemberIndexes, func(i, j int) bool {
+			return apiWallet.MemberIndexes[i] < apiWallet.MemberIndexes[j]
+		})
+
+		walletChainData, err := n.chain.GetWallet(currentWalletPublicKeyHash)
+		if err != nil {
+			apiWallet.StateError = err.Error()
+		} else {
+			apiWallet.State = walletChainData.State.String()
+		}
+
+		if action, ok := actions[hex.EncodeToString(walletPublicKeyBytes)]; ok {
+			apiWallet.Action = action.String()
+		}
+
+		wallets = append(wallets, apiWallet)
+	}
+
+	if walletPublicKeyHash != nil && len(wallets) == 0 {
+		return nil, clientinfo.NewAPIError(
+			http.StatusNotFound,
+			"wallet is not controlled by the node",
+		)
+	}
+
+	sort.Slice(wallets, func(i, j int) bool {
+		return wallets[i].PublicKeyHash < wallets[j].PublicKeyHash
+	})
+
+	return wallets, nil
+}
+
+// apiActions returns wallet actions currently executed by the node.
+func (n *node) apiActions() []*APIWalletAction {
+	actions := make([]*APIWalletAction, 0)
+	for key, actionType := range n.walletDispatcher.inFlightActions() {
+		walletPublicKeyBytes, err := hex.DecodeString(key)
+		if err != nil {
+			logger.Errorf("cannot decode wallet public key: [%v]", err)
+			continue
+		}
+
+		walletPublicKeyHash := bitcoin.PublicKeyHash(
+			unmarshalPublicKey(walletPublicKeyBytes),
+		)
+
+		actions = append(actions, &APIWalletAction{
+			WalletPublicKeyHash: formatWalletPublicKeyHash(walletPublicKeyHash),
+			Action:              actionType.String(),
+		})
+	}
+
+	sort.Slice(actions, func(i, j int) bool {
+		return actions[i].WalletPublicKeyHash < actions[j].WalletPublicKeyHash
+	})
+
+	return actions
+}
+
+// apiDKG returns the DKG state and the size of the pre-parameters pool.
+func (n *node) apiDKG() *APIDKG {
+	apiDKG := &APIDKG{
+		PreParamsCount: n.dkgExecutor.preParamsCount(),
+	}
+
+	state, err := n.chain.GetDKGState()
+	if err != nil {
+		apiDKG.StateError = err.Error()
+	} else {
+		apiDKG.State = state.String()
+	}
+
+	return apiDKG
+}
+
+// parseAPIJournalQuery parses query parameters of the journal endpoint.
+// Supported parameters are wallet, from_block, to_block and action.
+func parseAPIJournalQuery(query url.Values) (JournalQuery, error) {
+	walletPublicKeyHash, err := parseAPIWalletPublicKeyHash(query)
+	if err != nil {
+		return JournalQuery{}, err
+	}
+
+	journalQuery := JournalQuery{WalletPublicKeyHash: walletPublicKeyHash}
+
+	for key, target := range map[string]*uint64{
+		"from_block": &journalQuery.FromBlock,
+		"to_block":   &journalQuery.ToBlock,
+	} {
+		value := query.Get(key)
+		if len(value) == 0 {
+			continue
+		}
+
+		block, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return JournalQuery{}, clientinfo.NewAPIError(
+				http.StatusBadRequest,
+				"invalid %s parameter: [%v]",
+				key,
+				err,
+			)
+		}
+
+		*target = block
+	}
+
+	if action := query.Get("action"); len(action) > 0 {
+		actionType, err := parseWalletActionTypeName(action)
+		if err != nil {
+			return JournalQuery{}, clientinfo.NewAPIError(
+				http.StatusBadRequest,
+				"invalid action parameter: [%v]",
+				err,
+			)
+		}
+
+		journalQuery.ActionType = &actionType
+	}
+
+	return journalQuery, nil
+}
+
+// parseAPIWalletPublicKeyHash parses the wallet query parameter holding
+// a hex-encoded wallet public key hash. Returns nil if the parameter is
+// not set.
+func parseAPIWalletPublicKeyHash(query url.Values) (*[20]byte, error) {
+	value := query.Get("wallet")
+	if len(value) == 0 {
+		return nil, nil
+	}
+
+	bytes, err := hex.DecodeString(strings.TrimPrefix(value, "0x"))
+	if err != nil || len(bytes) != 20 {
+		return nil, clientinfo.NewAPIError(
+			http.StatusBadRequest,
+			"wallet parameter must be a 20-byte hex-encoded public key hash",
+		)
+	}
+
+	var walletPublicKeyHash [20]byte
+	copy(walletPublicKeyHash[:], bytes)
+
+	return &walletPublicKeyHash, nil
+}
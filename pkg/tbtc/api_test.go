@@ -0,0 +1,82 @@
+package tbtc
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestParseAPIJournalQuery(t *testing.T) {
+	walletPublicKeyHash := [20]byte{
+		0x8d, 0xb5, 0x0e, 0xb5, 0x20, 0x63, 0xea, 0x9d, 0x98, 0xb3,
+		0xea, 0xc9, 0x14, 0x89, 0xa9, 0x0f, 0x73, 0x89, 0x86, 0xf6,
+	}
+	redemption := ActionRedemption
+
+	var tests = map[string]struct {
+		query         string
+		expectedQuery JournalQuery
+		expectedError bool
+	}{
+		"no parameters": {
+			query:         "",
+			expectedQuery: JournalQuery{},
+		},
+		"all parameters": {
+			query: "wallet=0x8db50eb52063ea9d98b3eac91489a90f738986f6" +
+				"&from_block=100&to_block=200&action=Redemption",
+			expectedQuery: JournalQuery{
+				WalletPublicKeyHash: &walletPublicKeyHash,
+				FromBlock:           100,
+				ToBlock:             200,
+				ActionType:          &redemption,
+			},
+		},
+		"wallet without prefix": {
+			query: "wallet=8db50eb52063ea9d98b3eac91489a90f738986f6",
+			expectedQuery: JournalQuery{
+				WalletPublicKeyHash: &walletPublicKeyHash,
+			},
+		},
+		"wallet of wrong length": {
+			query:         "wallet=0x8db50eb5",
+			expectedError: true,
+		},
+		"invalid block": {
+			query:         "from_block=abc",
+			expectedError: true,
+		},
+		"unknown action": {
+			query:         "action=Unknown",
+			expectedError: true,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			values, err := url.ParseQuery(test.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			query, err := parseAPIJournalQuery(values)
+			if test.expectedError {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(test.expectedQuery, query) {
+				t.Errorf(
+					"unexpected query\nexpected: %+v\nactual:   %+v",
+					test.expectedQuery,
+					query,
+				)
+			}
+		})
+	}
+}
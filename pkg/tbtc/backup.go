@@ -0,0 +1,77 @@
+package tbtc
+
+import (
+	"fmt"
+
+	"github.com/keep-network/keep-core/pkg/bitcoin"
+	"github.com/keep-network/keep-core/pkg/protocol/group"
+)
+
+// SignerBackupInfo describes a signer stored in a keystore backup.
+type SignerBackupInfo struct {
+	WalletPublicKeyHash     [20]byte
+	SigningGroupMemberIndex group.MemberIndex
+}
+
+// VerifySignerBackup verifies the given signer, serialized the same way as
+// in the keystore. The signer's private key share must correspond to the
+// signer's wallet public key and the wallet must be registered on-chain
+// under an ID matching that public key.
+func VerifySignerBackup(chain Chain, data []byte) (*SignerBackupInfo, error) {
+	signer := &signer{}
+	if err := signer.Unmarshal(data); err != nil {
+		return nil, fmt.Errorf("cannot unmarshal signer: [%v]", err)
+	}
+
+	walletPublicKey := signer.wallet.publicKey
+	if walletPublicKey.X == nil || walletPublicKey.Y == nil {
+		return nil, fmt.Errorf(
+			"invalid wallet public key of signer with index [%v]",
+			signer.signingGroupMemberIndex,
+		)
+	}
+
+	info := &SignerBackupInfo{
+		WalletPublicKeyHash:     bitcoin.PublicKeyHash(walletPublicKey),
+		SigningGroupMemberIndex: signer.signingGroupMemberIndex,
+	}
+
+	privateKeySharePublicKey := signer.privateKeyShare.PublicKey()
+	if walletPublicKey.X.Cmp(privateKeySharePublicKey.X) != 0 ||
+		walletPublicKey.Y.Cmp(privateKeySharePublicKey.Y) != 0 {
+		return nil, fmt.Errorf(
+			"private key share of %s does not match the wallet public key",
+			signer,
+		)
+	}
+
+	walletChainData, err := chain.GetWallet(info.WalletPublicKeyHash)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"cannot get on-chain data of wallet [0x%x]: [%v]",
+			info.WalletPublicKeyHash,
+			err,
+		)
+	}
+
+	walletID, err := chain.CalculateWalletID(walletPublicKey)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"cannot calculate ID of wallet [0x%x]: [%v]",
+			info.WalletPublicKeyHash,
+			err,
+		)
+	}
+
+	if walletChainData.EcdsaWalletID != walletID {
+		return nil, fmt.Errorf(
+			"wallet public key does not match the on-chain wallet "+
+				"[0x%x]; expected ID [0x%x], on-chain ID [0x%x]",
+			info.WalletPublicKeyHash,
+			walletID,
+			walletChainData.EcdsaWalletID,
+		)
+	}
+
+	return info, nil
+}
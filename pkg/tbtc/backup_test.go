@@ -0,0 +1,119 @@
+package tbtc
+
+import (
+	"crypto/ecdsa"
+	"encoding/hex"
+	"testing"
+
+	"github.com/keep-network/keep-core/internal/testutils"
+	"github.com/keep-network/keep-core/pkg/bitcoin"
+)
+
+func TestVerifySignerBackup(t *testing.T) {
+	otherPublicKeyBytes, err := hex.DecodeString(
+		"0471e30bca60f6548d7b42582a478ea37ada63b402af7b3ddd57f0c95bb6843175" +
+			"aa0d2053a91a050a6797d85c38f2909cb7027f2344a01986aa2f9f8ca7a0c289",
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherPublicKey := unmarshalPublicKey(otherPublicKeyBytes)
+
+	var tests = map[string]struct {
+		walletPublicKey    *ecdsa.PublicKey
+		registerWallet     bool
+		registeredWalletID func(walletID [32]byte) [32]byte
+		data               []byte
+		expectedError      bool
+	}{
+		"valid signer": {
+			registerWallet: true,
+		},
+		"wallet not registered": {
+			registerWallet: false,
+			expectedError:  true,
+		},
+		"on-chain wallet ID mismatch": {
+			registerWallet: true,
+			registeredWalletID: func(walletID [32]byte) [32]byte {
+				walletID[0] ^= 0xff
+				return walletID
+			},
+			expectedError: true,
+		},
+		"private key share mismatch": {
+			walletPublicKey: otherPublicKey,
+			registerWallet:  true,
+			expectedError:   true,
+		},
+		"invalid data": {
+			data:          []byte{0x01, 0x02},
+			expectedError: true,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			localChain := Connect()
+
+			signer := createMockSigner(t)
+			if test.walletPublicKey != nil {
+				signer.wallet.publicKey = test.walletPublicKey
+			}
+
+			data := test.data
+			if data == nil {
+				data, err = signer.Marshal()
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			walletPublicKeyHash := bitcoin.PublicKeyHash(signer.wallet.publicKey)
+
+			if test.registerWallet {
+				walletID, err := localChain.CalculateWalletID(
+					signer.wallet.publicKey,
+				)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if test.registeredWalletID != nil {
+					walletID = test.registeredWalletID(walletID)
+				}
+
+				localChain.setWallet(
+					walletPublicKeyHash,
+					&WalletChainData{
+						EcdsaWalletID: walletID,
+						State:         StateLive,
+					},
+				)
+			}
+
+			info, err := VerifySignerBackup(localChain, data)
+			if test.expectedError {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			testutils.AssertBytesEqual(
+				t,
+				walletPublicKeyHash[:],
+				info.WalletPublicKeyHash[:],
+			)
+			testutils.AssertUintsEqual(
+				t,
+				"signing group member index",
+				uint64(signer.signingGroupMemberIndex),
+				uint64(info.SigningGroupMemberIndex),
+			)
+		})
+	}
+}
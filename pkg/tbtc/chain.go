@@ -2,6 +2,7 @@ package tbtc
 
 import (
 	"crypto/ecdsa"
+	"fmt"
 	"math/big"
 	"time"
 
@@ -24,6 +25,21 @@ const (
 	Challenge
 )
 
+func (ds DKGState) String() string {
+	switch ds {
+	case Idle:
+		return "Idle"
+	case AwaitingSeed:
+		return "AwaitingSeed"
+	case AwaitingResult:
+		return "AwaitingResult"
+	case Challenge:
+		return "Challenge"
+	default:
+		return fmt.Sprintf("Unknown(%d)", int(ds))
+	}
+}
+
 // GroupSelectionChain defines the subset of the TBTC chain interface that
 // pertains to the group selection activities.
 type GroupSelectionChain interface {
@@ -300,6 +316,29 @@ type BridgeChain interface {
 		movingFundsTxOutpointIndex uint32,
 	) (*MovedFundsSweepRequest, bool, error)
 
+	// GetDepositParameters gets the current value of parameters relevant
+	// for the depositing process.
+	GetDepositParameters() (
+		dustThreshold uint64,
+		treasuryFeeDivisor uint64,
+		txMaxFee uint64,
+		revealAheadPeriod uint32,
+		err error,
+	)
+
+	// GetRedemptionParameters gets the current value of parameters relevant
+	// for the redemption process.
+	GetRedemptionParameters() (
+		dustThreshold uint64,
+		treasuryFeeDivisor uint64,
+		txMaxFee uint64,
+		txMaxTotalFee uint64,
+		timeout uint32,
+		timeoutSlashingAmount *big.Int,
+		timeoutNotifierRewardMultiplier uint32,
+		err error,
+	)
+
 	// GetMovingFundsParameters gets the current value of parameters relevant
 	// for the moving funds process.
 	GetMovingFundsParameters() (
@@ -32,6 +32,23 @@ const (
 	stakingProvider      = chain.Address("0x1111111111111111111111111111111111111111")
 )
 
+type depositParameters = struct {
+	dustThreshold      uint64
+	treasuryFeeDivisor uint64
+	txMaxFee           uint64
+	revealAheadPeriod  uint32
+}
+
+type redemptionParameters = struct {
+	dustThreshold                   uint64
+	treasuryFeeDivisor              uint64
+	txMaxFee                        uint64
+	txMaxTotalFee                   uint64
+	timeout                         uint32
+	timeoutSlashingAmount           *big.Int
+	timeoutNotifierRewardMultiplier uint32
+}
+
 type movingFundsParameters = struct {
 	txMaxTotalFee                        uint64
 	dustThreshold                        uint64
@@ -108,6 +125,12 @@ type localChain struct {
 	depositRequestsMutex sync.Mutex
 	depositRequests      map[[32]byte]*DepositChainRequest
 
+	depositParametersMutex sync.Mutex
+	depositParameters      depositParameters
+
+	redemptionParametersMutex sync.Mutex
+	redemptionParameters      redemptionParameters
+
 	movingFundsParametersMutex sync.Mutex
 	movingFundsParameters      movingFundsParameters
 
@@ -288,6 +311,22 @@ func (lc *localChain) GetMovedFundsSweepRequest(
 	return request, true, nil
 }
 
+func (lc *localChain) setMovedFundsSweepRequest(
+	movingFundsTxHash bitcoin.Hash,
+	movingFundsTxOutpointIndex uint32,
+	request *MovedFundsSweepRequest,
+) {
+	lc.movedFundsSweepRequestsMutex.Lock()
+	defer lc.movedFundsSweepRequestsMutex.Unlock()
+
+	requestKey := buildMovedFundsSweepRequestKey(
+		movingFundsTxHash,
+		movingFundsTxOutpointIndex,
+	)
+
+	lc.movedFundsSweepRequests[requestKey] = request
+}
+
 func (lc *localChain) GetOperatorID(
 	operatorAddress chain.Address,
 ) (chain.OperatorID, error) {
@@ -1307,6 +1346,86 @@ func buildMovedFundsSweepProposalValidationKey(
 	return sha256.Sum256(buffer.Bytes()), nil
 }
 
+func (lc *localChain) GetDepositParameters() (
+	dustThreshold uint64,
+	treasuryFeeDivisor uint64,
+	txMaxFee uint64,
+	revealAheadPeriod uint32,
+	err error,
+) {
+	lc.depositParametersMutex.Lock()
+	defer lc.depositParametersMutex.Unlock()
+
+	return lc.depositParameters.dustThreshold,
+		lc.depositParameters.treasuryFeeDivisor,
+		lc.depositParameters.txMaxFee,
+		lc.depositParameters.revealAheadPeriod,
+		nil
+}
+
+func (lc *localChain) SetDepositParameters(
+	dustThreshold uint64,
+	treasuryFeeDivisor uint64,
+	txMaxFee uint64,
+	revealAheadPeriod uint32,
+) {
+	lc.depositParametersMutex.Lock()
+	defer lc.depositParametersMutex.Unlock()
+
+	lc.depositParameters = depositParameters{
+		dustThreshold:      dustThreshold,
+		treasuryFeeDivisor: treasuryFeeDivisor,
+		txMaxFee:           txMaxFee,
+		revealAheadPeriod:  revealAheadPeriod,
+	}
+}
+
+func (lc *localChain) GetRedemptionParameters() (
+	dustThreshold uint64,
+	treasuryFeeDivisor uint64,
+	txMaxFee uint64,
+	txMaxTotalFee uint64,
+	timeout uint32,
+	timeoutSlashingAmount *big.Int,
+	timeoutNotifierRewardMultiplier uint32,
+	err error,
+) {
+	lc.redemptionParametersMutex.Lock()
+	defer lc.redemptionParametersMutex.Unlock()
+
+	return lc.redemptionParameters.dustThreshold,
+		lc.redemptionParameters.treasuryFeeDivisor,
+		lc.redemptionParameters.txMaxFee,
+		lc.redemptionParameters.txMaxTotalFee,
+		lc.redemptionParameters.timeout,
+		lc.redemptionParameters.timeoutSlashingAmount,
+		lc.redemptionParameters.timeoutNotifierRewardMultiplier,
+		nil
+}
+
+func (lc *localChain) SetRedemptionParameters(
+	dustThreshold uint64,
+	treasuryFeeDivisor uint64,
+	txMaxFee uint64,
+	txMaxTotalFee uint64,
+	timeout uint32,
+	timeoutSlashingAmount *big.Int,
+	timeoutNotifierRewardMultiplier uint32,
+) {
+	lc.redemptionParametersMutex.Lock()
+	defer lc.redemptionParametersMutex.Unlock()
+
+	lc.redemptionParameters = redemptionParameters{
+		dustThreshold:                   dustThreshold,
+		treasuryFeeDivisor:              treasuryFeeDivisor,
+		txMaxFee:                        txMaxFee,
+		txMaxTotalFee:                   txMaxTotalFee,
+		timeout:                         timeout,
+		timeoutSlashingAmount:           timeoutSlashingAmount,
+		timeoutNotifierRewardMultiplier: timeoutNotifierRewardMultiplier,
+	}
+}
+
 func (lc *localChain) GetMovingFundsParameters() (
 	txMaxTotalFee uint64,
 	dustThreshold uint64,
@@ -1473,6 +1592,7 @@ func ConnectWithKey(
 		redemptionProposalValidations:            make(map[[32]byte]bool),
 		movingFundsProposalValidations:           make(map[[32]byte]bool),
 		movedFundsSweepProposalValidations:       make(map[[32]byte]bool),
+		movedFundsSweepRequests:                  make(map[[32]byte]*MovedFundsSweepRequest),
 		heartbeatProposalValidations:             make(map[[16]byte]bool),
 		depositRequests:                          make(map[[32]byte]*DepositChainRequest),
 		eligibleStakes:                           make(map[chain.Address]*big.Int),
@@ -0,0 +1,239 @@
+package tbtc
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/rand"
+)
+
+// coordinationLowFrequencyWindows is the number of coordination windows
+// between two consecutive checks of low-frequency wallet actions.
+const coordinationLowFrequencyWindows = 4
+
+// ChecklistPolicy determines wallet actions that should be checked during
+// coordination windows. All operators must derive the same checklist for
+// the given window so implementations must be deterministic and depend only
+// on the window index and the coordination seed.
+type ChecklistPolicy interface {
+	// ActionsChecklist returns a list of wallet actions that should be
+	// checked for the coordination window with the given index and seed.
+	// The window index is guaranteed to be greater than 0.
+	ActionsChecklist(windowIndex uint64, seed [32]byte) []WalletActionType
+}
+
+// ChecklistEntry describes how often the given wallet action should be
+// checked.
+type ChecklistEntry struct {
+	Action WalletActionType
+	// FrequencyWindows is the number of coordination windows between two
+	// consecutive checks of the action. The action is checked on windows
+	// whose index is divisible by this value. Values 0 and 1 mean the
+	// action is checked on every coordination window.
+	FrequencyWindows uint64
+}
+
+// FrequencyChecklistPolicy is a data-driven checklist policy. Actions are
+// put on the checklist in the order of entries, according to their
+// frequencies. Additionally, the heartbeat action is drawn using the
+// coordination seed and put at the end of the checklist.
+type FrequencyChecklistPolicy struct {
+	Entries []ChecklistEntry
+	// HeartbeatProbability is the probability of proposing a heartbeat
+	// action, assuming no other higher-priority action is proposed.
+	HeartbeatProbability float64
+}
+
+// ActionsChecklist implements ChecklistPolicy.
+func (fcp *FrequencyChecklistPolicy) ActionsChecklist(
+	windowIndex uint64,
+	seed [32]byte,
+) []WalletActionType {
+	var actions []WalletActionType
+
+	for _, entry := range fcp.Entries {
+		if entry.FrequencyWindows <= 1 ||
+			windowIndex%entry.FrequencyWindows == 0 {
+			actions = append(actions, entry.Action)
+		}
+	}
+
+	// #nosec G404 (insecure random number source (rand))
+	// Drawing a decision about heartbeat does not require secure randomness.
+	// Use first 8 bytes of the seed to initialize the RNG.
+	rng := rand.New(rand.NewSource(int64(binary.BigEndian.Uint64(seed[:8]))))
+	if rng.Float64() < fcp.HeartbeatProbability {
+		actions = append(actions, ActionHeartbeat)
+	}
+
+	return actions
+}
+
+// ChecklistPolicyVersion is a checklist policy in force since the given
+// activation block.
+type ChecklistPolicyVersion struct {
+	Version         uint
+	ActivationBlock uint64
+	Policy          ChecklistPolicy
+}
+
+// ChecklistPolicySchedule is a list of checklist policy versions, ordered
+// by their activation blocks. The policy used for the given coordination
+// window is the one with the highest activation block not greater than
+// the coordination block.
+type ChecklistPolicySchedule []ChecklistPolicyVersion
+
+// NewChecklistPolicySchedule creates a new checklist policy schedule from
+// the given versions. The first version must activate at block 0 so there
+// is a policy for every coordination window. Subsequent versions must have
+// increasing version numbers and activation blocks.
+func NewChecklistPolicySchedule(
+	versions ...ChecklistPolicyVersion,
+) (ChecklistPolicySchedule, error) {
+	if len(versions) == 0 {
+		return nil, fmt.Errorf("at least one policy version is required")
+	}
+
+	if versions[0].ActivationBlock != 0 {
+		return nil, fmt.Errorf(
+			"first policy version must activate at block 0; "+
+				"has activation block [%v]",
+			versions[0].ActivationBlock,
+		)
+	}
+
+	for i, version := range versions {
+		if version.Policy == nil {
+			return nil, fmt.Errorf(
+				"policy of version [%v] is nil",
+				version.Version,
+			)
+		}
+
+		if i == 0 {
+			continue
+		}
+
+		previous := versions[i-1]
+
+		if version.Version <= previous.Version {
+			return nil, fmt.Errorf(
+				"version [%v] does not follow version [%v]",
+				version.Version,
+				previous.Version,
+			)
+		}
+
+		if version.ActivationBlock <= previous.ActivationBlock {
+			return nil, fmt.Errorf(
+				"activation block [%v] of version [%v] is not after "+
+					"activation block [%v] of version [%v]",
+				version.ActivationBlock,
+				version.Version,
+				previous.ActivationBlock,
+				previous.Version,
+			)
+		}
+	}
+
+	return versions, nil
+}
+
+// policyAt returns the policy version in force at the given coordination
+// block.
+func (cps ChecklistPolicySchedule) policyAt(
+	coordinationBlock uint64,
+) ChecklistPolicyVersion {
+	selected := cps[0]
+	for _, version := range cps[1:] {
+		if coordinationBlock < version.ActivationBlock {
+			break
+		}
+		selected = version
+	}
+
+	return selected
+}
+
+// DefaultChecklistPolicySchedule returns the checklist policy schedule of
+// the tBTC protocol. Protocol-wide schedule changes are introduced as new
+// versions appended to this schedule. All operators must upgrade to a binary
+// containing the new version before its activation block is reached.
+func DefaultChecklistPolicySchedule() ChecklistPolicySchedule {
+	schedule, err := NewChecklistPolicySchedule(
+		// Redemption action is a priority action and is checked on every
+		// coordination window. Other actions are checked with a lower
+		// frequency.
+		ChecklistPolicyVersion{
+			Version:         1,
+			ActivationBlock: 0,
+			Policy: &FrequencyChecklistPolicy{
+				Entries: []ChecklistEntry{
+					{Action: ActionRedemption},
+					{
+						Action:           ActionDepositSweep,
+						FrequencyWindows: coordinationLowFrequencyWindows,
+					},
+					{
+						Action:           ActionMovedFundsSweep,
+						FrequencyWindows: coordinationLowFrequencyWindows,
+					},
+					{
+						Action:           ActionMovingFunds,
+						FrequencyWindows: coordinationLowFrequencyWindows,
+					},
+				},
+				HeartbeatProbability: coordinationHeartbeatProbability,
+			},
+		},
+		// DepositSweep and MovedFundsSweep are checked on every coordination
+		// window. MovingFunds retains the frequency guard because its
+		// proposal generator (MovingFundsTask.Run) calls FindDeposits which
+		// scans from block 0, i.e. the full Ethereum history. Removing this
+		// guard would multiply the scan load proportionally. The guard is
+		// kept even though past events may be served from the local event
+		// index: the index health differs from node to node while the
+		// checklist must be the same for all of them.
+		ChecklistPolicyVersion{
+			Version:         2,
+			ActivationBlock: DepositSweepEveryWindowActivationBlock,
+			Policy: &FrequencyChecklistPolicy{
+				Entries: []ChecklistEntry{
+					{Action: ActionRedemption},
+					{Action: ActionDepositSweep},
+					{Action: ActionMovedFundsSweep},
+					{
+						Action:           ActionMovingFunds,
+						FrequencyWindows: coordinationLowFrequencyWindows,
+					},
+				},
+				HeartbeatProbability: coordinationHeartbeatProbability,
+			},
+		},
+		// FeeBump action goes first. A stuck wallet transaction blocks all
+		// other actions of the wallet until it gets confirmed.
+		ChecklistPolicyVersion{
+			Version:         3,
+			ActivationBlock: FeeBumpActivationBlock,
+			Policy: &FrequencyChecklistPolicy{
+				Entries: []ChecklistEntry{
+					{Action: ActionFeeBump},
+					{Action: ActionRedemption},
+					{Action: ActionDepositSweep},
+					{Action: ActionMovedFundsSweep},
+					{
+						Action:           ActionMovingFunds,
+						FrequencyWindows: coordinationLowFrequencyWindows,
+					},
+				},
+				HeartbeatProbability: coordinationHeartbeatProbability,
+			},
+		},
+	)
+	if err != nil {
+		// The default schedule is static so this can happen only if
+		// the schedule is broken by a programming error.
+		panic(fmt.Sprintf("invalid default checklist policy schedule: [%v]", err))
+	}
+
+	return schedule
+}
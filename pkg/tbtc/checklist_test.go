@@ -0,0 +1,271 @@
+package tbtc
+
+import (
+	"crypto/sha256"
+	"math/big"
+	"testing"
+
+	"github.com/go-test/deep"
+
+	"github.com/keep-network/keep-core/internal/testutils"
+)
+
+func TestFrequencyChecklistPolicy_ActionsChecklist(t *testing.T) {
+	policy := &FrequencyChecklistPolicy{
+		Entries: []ChecklistEntry{
+			{Action: ActionRedemption},
+			{Action: ActionDepositSweep, FrequencyWindows: 1},
+			{Action: ActionMovedFundsSweep, FrequencyWindows: 2},
+			{Action: ActionMovingFunds, FrequencyWindows: 3},
+		},
+	}
+
+	tests := map[string]struct {
+		windowIndex          uint64
+		heartbeatProbability float64
+		expectedChecklist    []WalletActionType
+	}{
+		"window 1": {
+			windowIndex: 1,
+			expectedChecklist: []WalletActionType{
+				ActionRedemption,
+				ActionDepositSweep,
+			},
+		},
+		"window 2": {
+			windowIndex: 2,
+			expectedChecklist: []WalletActionType{
+				ActionRedemption,
+				ActionDepositSweep,
+				ActionMovedFundsSweep,
+			},
+		},
+		"window 3": {
+			windowIndex: 3,
+			expectedChecklist: []WalletActionType{
+				ActionRedemption,
+				ActionDepositSweep,
+				ActionMovingFunds,
+			},
+		},
+		"window 6": {
+			windowIndex: 6,
+			expectedChecklist: []WalletActionType{
+				ActionRedemption,
+				ActionDepositSweep,
+				ActionMovedFundsSweep,
+				ActionMovingFunds,
+			},
+		},
+		"window 1 with certain heartbeat": {
+			windowIndex:          1,
+			heartbeatProbability: 1,
+			expectedChecklist: []WalletActionType{
+				ActionRedemption,
+				ActionDepositSweep,
+				ActionHeartbeat,
+			},
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			policy.HeartbeatProbability = test.heartbeatProbability
+
+			checklist := policy.ActionsChecklist(
+				test.windowIndex,
+				sha256.Sum256(big.NewInt(int64(test.windowIndex)).Bytes()),
+			)
+
+			if diff := deep.Equal(checklist, test.expectedChecklist); diff != nil {
+				t.Errorf(
+					"compare failed: %v\nactual: %s\nexpected: %s",
+					diff,
+					checklist,
+					test.expectedChecklist,
+				)
+			}
+		})
+	}
+}
+
+func TestNewChecklistPolicySchedule(t *testing.T) {
+	policy := &FrequencyChecklistPolicy{}
+
+	tests := map[string]struct {
+		versions      []ChecklistPolicyVersion
+		expectedError bool
+	}{
+		"valid schedule": {
+			versions: []ChecklistPolicyVersion{
+				{Version: 1, ActivationBlock: 0, Policy: policy},
+				{Version: 2, ActivationBlock: 100, Policy: policy},
+			},
+		},
+		"no versions": {
+			versions:      nil,
+			expectedError: true,
+		},
+		"first version not active from genesis": {
+			versions: []ChecklistPolicyVersion{
+				{Version: 1, ActivationBlock: 100, Policy: policy},
+			},
+			expectedError: true,
+		},
+		"nil policy": {
+			versions: []ChecklistPolicyVersion{
+				{Version: 1, ActivationBlock: 0, Policy: nil},
+			},
+			expectedError: true,
+		},
+		"non-increasing version": {
+			versions: []ChecklistPolicyVersion{
+				{Version: 1, ActivationBlock: 0, Policy: policy},
+				{Version: 1, ActivationBlock: 100, Policy: policy},
+			},
+			expectedError: true,
+		},
+		"non-increasing activation block": {
+			versions: []ChecklistPolicyVersion{
+				{Version: 1, ActivationBlock: 0, Policy: policy},
+				{Version: 2, ActivationBlock: 100, Policy: policy},
+				{Version: 3, ActivationBlock: 100, Policy: policy},
+			},
+			expectedError: true,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			_, err := NewChecklistPolicySchedule(test.versions...)
+
+			testutils.AssertBoolsEqual(
+				t,
+				"error",
+				test.expectedError,
+				err != nil,
+			)
+		})
+	}
+}
+
+func TestChecklistPolicySchedule_PolicyAt(t *testing.T) {
+	policy := &FrequencyChecklistPolicy{}
+
+	schedule, err := NewChecklistPolicySchedule(
+		ChecklistPolicyVersion{Version: 1, ActivationBlock: 0, Policy: policy},
+		ChecklistPolicyVersion{Version: 2, ActivationBlock: 1000, Policy: policy},
+		ChecklistPolicyVersion{Version: 5, ActivationBlock: 5000, Policy: policy},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		coordinationBlock uint64
+		expectedVersion   uint
+	}{
+		"genesis": {
+			coordinationBlock: 0,
+			expectedVersion:   1,
+		},
+		"before second version": {
+			coordinationBlock: 999,
+			expectedVersion:   1,
+		},
+		"at second version activation": {
+			coordinationBlock: 1000,
+			expectedVersion:   2,
+		},
+		"before last version": {
+			coordinationBlock: 4999,
+			expectedVersion:   2,
+		},
+		"after last version activation": {
+			coordinationBlock: 9000,
+			expectedVersion:   5,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			version := schedule.policyAt(test.coordinationBlock)
+
+			testutils.AssertUintsEqual(
+				t,
+				"version",
+				uint64(test.expectedVersion),
+				uint64(version.Version),
+			)
+		})
+	}
+}
+
+func TestCoordinationExecutor_GetActionsChecklist_CustomSchedule(t *testing.T) {
+	schedule, err := NewChecklistPolicySchedule(
+		ChecklistPolicyVersion{
+			Version:         1,
+			ActivationBlock: 0,
+			Policy: &FrequencyChecklistPolicy{
+				Entries: []ChecklistEntry{{Action: ActionRedemption}},
+			},
+		},
+		ChecklistPolicyVersion{
+			Version:         2,
+			ActivationBlock: 1800,
+			Policy: &FrequencyChecklistPolicy{
+				Entries: []ChecklistEntry{
+					{Action: ActionDepositSweep},
+					{Action: ActionRedemption},
+				},
+			},
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	executor := &coordinationExecutor{checklistPolicySchedule: schedule}
+
+	tests := map[string]struct {
+		coordinationBlock uint64
+		expectedChecklist []WalletActionType
+	}{
+		"incorrect window": {
+			coordinationBlock: 901,
+			expectedChecklist: nil,
+		},
+		"first version": {
+			coordinationBlock: 900,
+			expectedChecklist: []WalletActionType{ActionRedemption},
+		},
+		"second version": {
+			coordinationBlock: 1800,
+			expectedChecklist: []WalletActionType{
+				ActionDepositSweep,
+				ActionRedemption,
+			},
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			window := newCoordinationWindow(test.coordinationBlock)
+
+			checklist := executor.getActionsChecklist(
+				window.index(),
+				[32]byte{},
+				window.coordinationBlock,
+			)
+
+			if diff := deep.Equal(checklist, test.expectedChecklist); diff != nil {
+				t.Errorf(
+					"compare failed: %v\nactual: %s\nexpected: %s",
+					diff,
+					checklist,
+					test.expectedChecklist,
+				)
+			}
+		})
+	}
+}
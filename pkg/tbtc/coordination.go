@@ -66,6 +66,26 @@ const (
 	// upgrade to a binary containing this constant before the activation block
 	// is reached.
 	DepositSweepEveryWindowActivationBlock = uint64(24559289)
+
+	// FeeBumpActivationBlock is the Ethereum block height at which the
+	// FeeBump action becomes available on every coordination window. All
+	// operators must upgrade to a binary containing this constant before the
+	// activation block is reached. The block is expected around 2027-01-12,
+	// assuming 12-second blocks, which leaves operators about 12 weeks to
+	// upgrade. It is the first of the staged activations; deposit sweeps
+	// spending unconfirmed change and batch signing follow two and four
+	// weeks later, respectively, so an operator who did not upgrade in time
+	// diverges from the others on a single protocol change at once.
+	FeeBumpActivationBlock = uint64(26835400)
+
+	// DepositSweepCPFPActivationBlock is the Ethereum block height at which
+	// deposit sweep proposals may start spending an unconfirmed wallet
+	// transaction's change (child-pays-for-parent). All operators must
+	// upgrade to a binary containing this constant before the activation
+	// block is reached. The block is expected around 2027-01-26, assuming
+	// 12-second blocks, two weeks after FeeBumpActivationBlock so the two
+	// changes of the deposit sweep flow are not activated together.
+	DepositSweepCPFPActivationBlock = uint64(26936200)
 )
 
 // errCoordinationExecutorBusy is an error returned when the coordination
@@ -212,6 +232,15 @@ type CoordinationProposalRequest struct {
 	WalletOperators     []chain.Address
 	ExecutingOperator   chain.Address
 	ActionsChecklist    []WalletActionType
+	// CoordinationBlock is the first block of the coordination window the
+	// proposal is generated for. Followers decide whether features gated by
+	// an activation block can be used based on this block.
+	CoordinationBlock uint64
+	// UnconfirmedTransactions holds the moments the executing operator
+	// first observed the wallet's unconfirmed transactions in the Bitcoin
+	// mempool. It is populated only if the checklist contains the FeeBump
+	// action.
+	UnconfirmedTransactions map[bitcoin.Hash]time.Time
 }
 
 // CoordinationProposalGenerator is a component responsible for generating
@@ -304,6 +333,19 @@ type coordinationExecutor struct {
 
 	waitForBlockFn waitForBlockFn
 
+	// checklistPolicySchedule determines actions checklists of coordination
+	// windows. The default schedule is used if not set.
+	checklistPolicySchedule ChecklistPolicySchedule
+
+	// transactionsTracker is optional and used to track unconfirmed wallet
+	// transactions that may become subject of a fee bump.
+	transactionsTracker *unconfirmedTransactionsTracker
+
+	// shadowMode makes the executor accept coordination messages sent by
+	// members controlled by the node. In the shadow mode, such messages
+	// are sent by the production node sharing the keystore with this one.
+	shadowMode bool
+
 	// metricsRecorder is optional and used for recording performance metrics
 	metricsRecorder interface {
 		IncrementCounter(name string, value float64)
@@ -324,6 +366,7 @@ func newCoordinationExecutor(
 	membershipValidator *group.MembershipValidator,
 	protocolLatch *generator.ProtocolLatch,
 	waitForBlockFn waitForBlockFn,
+	transactionsTracker *unconfirmedTransactionsTracker,
 ) *coordinationExecutor {
 	return &coordinationExecutor{
 		lock:                semaphore.NewWeighted(1),
@@ -336,6 +379,9 @@ func newCoordinationExecutor(
 		membershipValidator: membershipValidator,
 		protocolLatch:       protocolLatch,
 		waitForBlockFn:      waitForBlockFn,
+		transactionsTracker: transactionsTracker,
+
+		checklistPolicySchedule: DefaultChecklistPolicySchedule(),
 	}
 }
 
@@ -398,6 +444,23 @@ func (ce *coordinationExecutor) coordinate(
 
 	execLogger.Infof("actions checklist is: [%v]", actionsChecklist)
 
+	// Every wallet member observes the wallet's mempool on its own so, all
+	// members have their own view on the age of unconfirmed transactions
+	// when a fee bump is proposed.
+	var unconfirmedTransactions map[bitcoin.Hash]time.Time
+	if ce.transactionsTracker != nil &&
+		slices.Contains(actionsChecklist, ActionFeeBump) {
+		unconfirmedTransactions, err = ce.transactionsTracker.observe(
+			ce.walletPublicKeyHash(),
+		)
+		if err != nil {
+			execLogger.Warnf(
+				"cannot observe unconfirmed wallet transactions: [%v]",
+				err,
+			)
+		}
+	}
+
 	// Set up a context that is automatically cancelled when the active phase
 	// of the coordination window ends.
 	//
@@ -425,6 +488,7 @@ func (ce *coordinationExecutor) coordinate(
 			ctx,
 			window.coordinationBlock,
 			actionsChecklist,
+			unconfirmedTransactions,
 		)
 		if err != nil {
 			// Cancel the context upon leader's routine failure. There is
@@ -577,8 +641,9 @@ func (ce *coordinationExecutor) getLeader(seed [32]byte) chain.Address {
 }
 
 // getActionsChecklist returns a list of wallet actions that should be checked
-// for the given coordination window. Returns nil for incorrect coordination
-// windows whose index is 0.
+// for the given coordination window. The checklist is determined by the
+// checklist policy in force at the coordination block. Returns nil for
+// incorrect coordination windows whose index is 0.
 func (ce *coordinationExecutor) getActionsChecklist(
 	windowIndex uint64,
 	seed [32]byte,
@@ -589,57 +654,15 @@ func (ce *coordinationExecutor) getActionsChecklist(
 		return nil
 	}
 
-	var actions []WalletActionType
-
-	// Redemption action is a priority action and should be checked on every
-	// coordination window.
-	actions = append(actions, ActionRedemption)
-
-	// Other actions should be checked with a lower frequency. The default
-	// frequency is every 4 coordination windows.
-	frequencyWindows := uint64(4)
-
-	// The activation gate determines how often DepositSweep and
-	// MovedFundsSweep actions are checked. Before the activation block,
-	// all three actions (DepositSweep, MovedFundsSweep, MovingFunds)
-	// are gated by the frequency window. After the activation block,
-	// DepositSweep and MovedFundsSweep are checked on every coordination
-	// window while MovingFunds stays frequency-gated because its
-	// proposal generator performs a full-history chain scan.
-	if coordinationBlock < DepositSweepEveryWindowActivationBlock {
-		if windowIndex%frequencyWindows == 0 {
-			actions = append(actions, ActionDepositSweep)
-		}
-
-		if windowIndex%frequencyWindows == 0 {
-			actions = append(actions, ActionMovedFundsSweep)
-		}
-
-		if windowIndex%frequencyWindows == 0 {
-			actions = append(actions, ActionMovingFunds)
-		}
-	} else {
-		actions = append(actions, ActionDepositSweep)
-		actions = append(actions, ActionMovedFundsSweep)
-
-		// MovingFunds retains the frequency guard because its proposal
-		// generator (MovingFundsTask.Run) calls FindDeposits which scans
-		// from block 0, i.e. the full Ethereum history. Removing this
-		// guard would multiply the scan load proportionally.
-		if windowIndex%frequencyWindows == 0 {
-			actions = append(actions, ActionMovingFunds)
-		}
-	}
-
-	// #nosec G404 (insecure random number source (rand))
-	// Drawing a decision about heartbeat does not require secure randomness.
-	// Use first 8 bytes of the seed to initialize the RNG.
-	rng := rand.New(rand.NewSource(int64(binary.BigEndian.Uint64(seed[:8]))))
-	if rng.Float64() < coordinationHeartbeatProbability {
-		actions = append(actions, ActionHeartbeat)
+	schedule := ce.checklistPolicySchedule
+	if schedule == nil {
+		schedule = DefaultChecklistPolicySchedule()
 	}
 
-	return actions
+	return schedule.policyAt(coordinationBlock).Policy.ActionsChecklist(
+		windowIndex,
+		seed,
+	)
 }
 
 // executeLeaderRoutine executes the leader's routine for the given coordination
@@ -649,15 +672,18 @@ func (ce *coordinationExecutor) executeLeaderRoutine(
 	ctx context.Context,
 	coordinationBlock uint64,
 	actionsChecklist []WalletActionType,
+	unconfirmedTransactions map[bitcoin.Hash]time.Time,
 ) (CoordinationProposal, error) {
 	walletPublicKeyHash := ce.walletPublicKeyHash()
 
 	proposal, err := ce.generateProposal(
 		&CoordinationProposalRequest{
-			WalletPublicKeyHash: walletPublicKeyHash,
-			WalletOperators:     ce.coordinatedWallet.signingGroupOperators,
-			ExecutingOperator:   ce.operatorAddress,
-			ActionsChecklist:    actionsChecklist,
+			WalletPublicKeyHash:     walletPublicKeyHash,
+			WalletOperators:         ce.coordinatedWallet.signingGroupOperators,
+			ExecutingOperator:       ce.operatorAddress,
+			ActionsChecklist:        actionsChecklist,
+			CoordinationBlock:       coordinationBlock,
+			UnconfirmedTransactions: unconfirmedTransactions,
 		},
 		2,             // 2 attempts at most
 		1*time.Minute, // 1 minute between attempts
@@ -764,7 +790,8 @@ loop:
 			}
 
 			// Filter out messages from self.
-			if slices.Contains(ce.membersIndexes, message.senderID) {
+			if !ce.shadowMode &&
+				slices.Contains(ce.membersIndexes, message.senderID) {
 				continue
 			}
 
@@ -812,6 +839,21 @@ loop:
 				continue
 			}
 
+			// Filter out messages that propose features not active yet
+			// at the given coordination block.
+			if err := validateProposalActivation(
+				message.proposal,
+				coordinationBlock,
+			); err != nil {
+				faults = append(
+					faults, &coordinationFault{
+						culprit:   leader,
+						faultType: FaultLeaderMistake,
+					},
+				)
+				continue
+			}
+
 			return message.proposal, faults, nil
 		case <-ctx.Done():
 			break loop
@@ -827,3 +869,24 @@ loop:
 
 	return nil, faults, fmt.Errorf("coordination message not received on time")
 }
+
+// validateProposalActivation checks whether the given proposal uses only
+// features that are active at the given coordination block. Returns an error
+// if the proposal uses a feature before its activation block.
+func validateProposalActivation(
+	proposal CoordinationProposal,
+	coordinationBlock uint64,
+) error {
+	if p, ok := proposal.(*DepositSweepProposal); ok &&
+		p.ParentTxHash != (bitcoin.Hash{}) &&
+		coordinationBlock < DepositSweepCPFPActivationBlock {
+		return fmt.Errorf(
+			"child-pays-for-parent deposit sweep is not active before "+
+				"block [%v]; coordination block is [%v]",
+			DepositSweepCPFPActivationBlock,
+			coordinationBlock,
+		)
+	}
+
+	return nil
+}
@@ -371,6 +371,7 @@ func TestCoordinationExecutor_Coordinate(t *testing.T) {
 			membershipValidator,
 			protocolLatch,
 			operator.waitForBlockHeight,
+			nil,
 		)
 	}
 
@@ -869,6 +870,68 @@ func TestCoordinationExecutor_GetActionsChecklist_PostActivation(t *testing.T) {
 	}
 }
 
+func TestCoordinationExecutor_GetActionsChecklist_FeeBumpActivation(t *testing.T) {
+	tests := map[string]struct {
+		coordinationBlock uint64
+		expectedFeeBump   bool
+	}{
+		"last window before activation": {
+			coordinationBlock: 26835300,
+			expectedFeeBump:   false,
+		},
+		"first window after activation": {
+			coordinationBlock: 26836200,
+			expectedFeeBump:   true,
+		},
+		"later window after activation": {
+			coordinationBlock: 26837100,
+			expectedFeeBump:   true,
+		},
+	}
+
+	executor := &coordinationExecutor{}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			window := newCoordinationWindow(test.coordinationBlock)
+
+			seed := sha256.Sum256(
+				big.NewInt(int64(window.coordinationBlock) + 2).Bytes(),
+			)
+
+			checklist := executor.getActionsChecklist(
+				window.index(),
+				seed,
+				window.coordinationBlock,
+			)
+
+			testutils.AssertBoolsEqual(
+				t,
+				"fee bump presence",
+				test.expectedFeeBump,
+				slices.Contains(checklist, ActionFeeBump),
+			)
+
+			// The fee bump must be checked before any other action while
+			// the redemption stays the first one of the remaining actions.
+			expectedRedemptionIndex := 0
+			if test.expectedFeeBump {
+				if checklist[0] != ActionFeeBump {
+					t.Errorf("fee bump is not the first action: %s", checklist)
+				}
+				expectedRedemptionIndex = 1
+			}
+
+			testutils.AssertIntsEqual(
+				t,
+				"redemption index",
+				expectedRedemptionIndex,
+				slices.Index(checklist, ActionRedemption),
+			)
+		})
+	}
+}
+
 // assertPostActivationSafety verifies the safety invariants that must hold
 // for every non-nil post-activation checklist:
 //   - ActionRedemption is at index 0.
@@ -1056,7 +1119,7 @@ func TestCoordinationExecutor_ExecuteLeaderRoutine(t *testing.T) {
 		cancelCtx()
 	})
 
-	proposal, err := executor.executeLeaderRoutine(ctx, 900, actionsChecklist)
+	proposal, err := executor.executeLeaderRoutine(ctx, 900, actionsChecklist, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -1298,12 +1361,14 @@ func TestCoordinationExecutor_ExecuteFollowerRoutine(t *testing.T) {
 		// Send message of wrong type.
 		err := leader.channel.Send(ctx, &signingDoneMessage{
 			senderID:      leaderID,
-			message:       big.NewInt(100),
+			messages:      []*big.Int{big.NewInt(100)},
 			attemptNumber: 2,
-			signature: &tecdsa.Signature{
-				R:          big.NewInt(200),
-				S:          big.NewInt(300),
-				RecoveryID: 3,
+			signatures: []*tecdsa.Signature{
+				{
+					R:          big.NewInt(200),
+					S:          big.NewInt(300),
+					RecoveryID: 3,
+				},
 			},
 			endBlock: 4500,
 		})
@@ -1389,6 +1454,22 @@ func TestCoordinationExecutor_ExecuteFollowerRoutine(t *testing.T) {
 			return
 		}
 
+		// Send message with child-pays-for-parent deposit sweep proposal
+		// before its activation block.
+		err = leader.channel.Send(ctx, &coordinationMessage{
+			senderID:            leaderID,
+			coordinationBlock:   900,
+			walletPublicKeyHash: executor.walletPublicKeyHash(),
+			proposal: &DepositSweepProposal{
+				SweepTxFee:   big.NewInt(10000),
+				ParentTxHash: bitcoin.Hash{0x01},
+			},
+		})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
 		// Send a proper message.
 		err = leader.channel.Send(ctx, &coordinationMessage{
 			senderID:            leaderID,
@@ -1412,7 +1493,7 @@ func TestCoordinationExecutor_ExecuteFollowerRoutine(t *testing.T) {
 		ctx,
 		leader.address,
 		900,
-		[]WalletActionType{ActionRedemption, ActionNoop},
+		[]WalletActionType{ActionDepositSweep, ActionRedemption, ActionNoop},
 	)
 	if err != nil {
 		t.Fatal(err)
@@ -1445,6 +1526,10 @@ func TestCoordinationExecutor_ExecuteFollowerRoutine(t *testing.T) {
 			culprit:   leader.address,
 			faultType: FaultLeaderMistake,
 		},
+		{
+			culprit:   leader.address,
+			faultType: FaultLeaderMistake,
+		},
 	}
 	if !reflect.DeepEqual(expectedFaults, faults) {
 		t.Errorf(
@@ -1588,3 +1673,49 @@ func (mcpg *mockCoordinationProposalGenerator) Generate(
 		mcpg.calls,
 	)
 }
+
+func TestValidateProposalActivation(t *testing.T) {
+	var tests = map[string]struct {
+		proposal          CoordinationProposal
+		coordinationBlock uint64
+		expectedErr       bool
+	}{
+		"deposit sweep before activation": {
+			proposal:          &DepositSweepProposal{},
+			coordinationBlock: DepositSweepCPFPActivationBlock - 1,
+		},
+		"child-pays-for-parent deposit sweep before activation": {
+			proposal: &DepositSweepProposal{
+				ParentTxHash: bitcoin.Hash{0x01},
+			},
+			coordinationBlock: DepositSweepCPFPActivationBlock - 1,
+			expectedErr:       true,
+		},
+		"child-pays-for-parent deposit sweep at activation": {
+			proposal: &DepositSweepProposal{
+				ParentTxHash: bitcoin.Hash{0x01},
+			},
+			coordinationBlock: DepositSweepCPFPActivationBlock,
+		},
+		"other proposal": {
+			proposal:          &NoopProposal{},
+			coordinationBlock: 900,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			err := validateProposalActivation(
+				test.proposal,
+				test.coordinationBlock,
+			)
+
+			testutils.AssertBoolsEqual(
+				t,
+				"error",
+				test.expectedErr,
+				err != nil,
+			)
+		})
+	}
+}
@@ -60,6 +60,12 @@ type DepositSweepProposal struct {
 	}
 	SweepTxFee           *big.Int
 	DepositsRevealBlocks []*big.Int
+	// ParentTxHash is the hash of an unconfirmed wallet transaction whose
+	// change output should be spent by the sweep transaction instead of the
+	// main UTXO registered in the Bridge. This makes the sweep a
+	// child-pays-for-parent transaction speeding up the confirmation of
+	// its parent. Zero value means the sweep spends the registered main UTXO.
+	ParentTxHash bitcoin.Hash
 }
 
 func (dsp *DepositSweepProposal) ActionType() WalletActionType {
@@ -175,28 +181,54 @@ func (dsa *depositSweepAction) execute() error {
 		)
 	}
 
-	err = EnsureWalletSyncedBetweenChains(
-		walletPublicKeyHash,
-		walletMainUtxo,
-		dsa.chain,
-		dsa.btcChain,
-	)
-	if err != nil {
-		if dsa.metricsRecorder != nil {
-			dsa.metricsRecorder.IncrementCounter("deposit_sweep_executions_failed_total", 1)
-			dsa.metricsRecorder.RecordDuration("deposit_sweep_execution_duration_seconds", time.Since(executionStartTime))
+	// The UTXO that will be spent by the sweep transaction along with
+	// the deposits. This is the registered main UTXO unless the sweep
+	// is a child-pays-for-parent transaction.
+	sweptWalletUtxo := walletMainUtxo
+
+	if dsa.proposal.ParentTxHash != (bitcoin.Hash{}) {
+		// The wallet state is not synced between chains by definition
+		// as the parent transaction is still unconfirmed. Instead, make
+		// sure the parent is the only wallet transaction the Bridge does
+		// not know about, i.e. it spends the registered main UTXO.
+		sweptWalletUtxo, err = dsa.determineParentChange(
+			walletPublicKeyHash,
+			walletMainUtxo,
+		)
+		if err != nil {
+			if dsa.metricsRecorder != nil {
+				dsa.metricsRecorder.IncrementCounter("deposit_sweep_executions_failed_total", 1)
+				dsa.metricsRecorder.RecordDuration("deposit_sweep_execution_duration_seconds", time.Since(executionStartTime))
+			}
+			return fmt.Errorf(
+				"error while determining parent transaction's change: [%v]",
+				err,
+			)
 		}
-		return fmt.Errorf(
-			"error while ensuring wallet state is synced between "+
-				"BTC and host chain: [%v]",
-			err,
+	} else {
+		err = EnsureWalletSyncedBetweenChains(
+			walletPublicKeyHash,
+			walletMainUtxo,
+			dsa.chain,
+			dsa.btcChain,
 		)
+		if err != nil {
+			if dsa.metricsRecorder != nil {
+				dsa.metricsRecorder.IncrementCounter("deposit_sweep_executions_failed_total", 1)
+				dsa.metricsRecorder.RecordDuration("deposit_sweep_execution_duration_seconds", time.Since(executionStartTime))
+			}
+			return fmt.Errorf(
+				"error while ensuring wallet state is synced between "+
+					"BTC and host chain: [%v]",
+				err,
+			)
+		}
 	}
 
 	unsignedSweepTx, err := assembleDepositSweepTransaction(
 		dsa.btcChain,
 		dsa.wallet().publicKey,
-		walletMainUtxo,
+		sweptWalletUtxo,
 		validatedDeposits,
 		dsa.proposal.SweepTxFee.Int64(),
 	)
@@ -469,6 +501,40 @@ func ValidateDepositSweepProposal(
 	return deposits, nil
 }
 
+// determineParentChange determines the change output of the unconfirmed
+// parent transaction pointed by the proposal. The parent must spend the
+// given wallet main UTXO registered in the Bridge.
+func (dsa *depositSweepAction) determineParentChange(
+	walletPublicKeyHash [20]byte,
+	walletMainUtxo *bitcoin.UnspentTransactionOutput,
+) (*bitcoin.UnspentTransactionOutput, error) {
+	parentTx, change, err := DetermineWalletUnconfirmedChange(
+		walletPublicKeyHash,
+		walletMainUtxo,
+		dsa.btcChain,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if parentTx == nil {
+		return nil, fmt.Errorf(
+			"wallet main UTXO is not spent by any unconfirmed transaction",
+		)
+	}
+
+	if parentTx.Hash() != dsa.proposal.ParentTxHash {
+		return nil, fmt.Errorf(
+			"unconfirmed transaction [%s] spending wallet main UTXO "+
+				"is not the proposed parent [%s]",
+			parentTx.Hash().Hex(bitcoin.ReversedByteOrder),
+			dsa.proposal.ParentTxHash.Hex(bitcoin.ReversedByteOrder),
+		)
+	}
+
+	return change, nil
+}
+
 func (dsa *depositSweepAction) wallet() wallet {
 	return dsa.sweepingWallet
 }
@@ -477,6 +543,12 @@ func (dsa *depositSweepAction) actionType() WalletActionType {
 	return ActionDepositSweep
 }
 
+// broadcastedTransaction returns the hash of the transaction broadcasted
+// by the action, or nil if the action did not broadcast a transaction.
+func (dsa *depositSweepAction) broadcastedTransaction() *bitcoin.Hash {
+	return dsa.transactionExecutor.broadcastedTransaction()
+}
+
 // setMetricsRecorder sets the metrics recorder for the deposit sweep action.
 func (dsa *depositSweepAction) setMetricsRecorder(recorder interface {
 	IncrementCounter(name string, value float64)
@@ -68,6 +68,14 @@ type dkgExecutor struct {
 
 	tecdsaExecutor *dkg.Executor
 
+	// timingSchedule determines attempt timings of DKG protocols started
+	// at the given block.
+	timingSchedule TimingSchedule
+
+	// journal is optional and used for recording outcomes and phase timings
+	// of DKG attempts.
+	journal *auditJournal
+
 	// metricsRecorder is optional and used for recording performance metrics
 	metricsRecorder interface {
 		IncrementCounter(name string, value float64)
@@ -112,6 +120,7 @@ func newDkgExecutor(
 		protocolLatch:   protocolLatch,
 		tecdsaExecutor:  tecdsaExecutor,
 		waitForBlockFn:  waitForBlockFn,
+		timingSchedule:  DefaultTimingSchedule(),
 	}
 }
 
@@ -301,6 +310,8 @@ func (de *dkgExecutor) generateSigningGroup(
 
 	dkgTimeoutBlock := startBlock + dkgParameters.SubmissionTimeoutBlocks
 
+	timing := de.timingSchedule.timingAt(startBlock)
+
 	for _, index := range memberIndexes {
 		// Capture the member index for the goroutine.
 		memberIndex := index
@@ -350,13 +361,31 @@ func (de *dkgExecutor) generateSigningGroup(
 				memberIndex,
 				groupSelectionResult.OperatorsAddresses,
 				de.groupParameters,
+				timing,
 				announcer,
 				dkgAttemptsLimit,
 			)
 
+			if de.journal != nil {
+				retryLoop.attemptRecorder = func(
+					attempt *JournalAttemptTiming,
+					attemptErr error,
+				) {
+					de.journal.recordAttempt(nil, attempt, attemptErr)
+				}
+			}
+
 			result, err := retryLoop.start(
 				ctx,
 				de.waitForBlockFn,
+				func() (uint64, error) {
+					blockCounter, err := de.chain.BlockCounter()
+					if err != nil {
+						return 0, err
+					}
+
+					return blockCounter.CurrentBlock()
+				},
 				func(attempt *dkgAttemptParams) (*dkg.Result, error) {
 					dkgAttemptLogger := dkgLogger.With(
 						zap.Uint("attempt", attempt.number),
@@ -16,6 +16,8 @@ import (
 	"golang.org/x/exp/slices"
 )
 
+// Default durations of the DKG attempt phases. These are the DKG timings of
+// the first version of DefaultTimingSchedule.
 const (
 	// dkgAttemptAnnouncementDelayBlocks determines the duration of the
 	// announcement phase delay that is preserved before starting the
@@ -33,15 +35,6 @@ const (
 	dkgAttemptCoolDownBlocks = 5
 )
 
-// dkgAttemptMaximumBlocks returns the maximum block duration of a single
-// DKG attempt.
-func dkgAttemptMaximumBlocks() uint {
-	return dkgAttemptAnnouncementDelayBlocks +
-		dkgAttemptAnnouncementActiveBlocks +
-		dkgAttemptMaximumProtocolBlocks +
-		dkgAttemptCoolDownBlocks
-}
-
 // dkgAnnouncer represents a component responsible for exchanging readiness
 // announcements for the given DKG attempt for the given seed.
 type dkgAnnouncer interface {
@@ -64,6 +57,7 @@ type dkgRetryLoop struct {
 	selectedOperators chain.Addresses
 
 	groupParameters *GroupParameters
+	timing          *TimingScheduleVersion
 
 	announcer dkgAnnouncer
 
@@ -75,6 +69,10 @@ type dkgRetryLoop struct {
 	attemptDelayBlocks uint64
 
 	attemptsLimit uint
+
+	// attemptRecorder is optional and used for recording outcomes and
+	// phase timings of DKG attempts.
+	attemptRecorder func(attempt *JournalAttemptTiming, attemptErr error)
 }
 
 func newDkgRetryLoop(
@@ -84,6 +82,7 @@ func newDkgRetryLoop(
 	memberIndex group.MemberIndex,
 	selectedOperators chain.Addresses,
 	groupParameters *GroupParameters,
+	timing *TimingScheduleVersion,
 	announcer dkgAnnouncer,
 	attemptsLimit uint,
 ) *dkgRetryLoop {
@@ -100,6 +99,7 @@ func newDkgRetryLoop(
 		memberIndex:        memberIndex,
 		selectedOperators:  selectedOperators,
 		groupParameters:    groupParameters,
+		timing:             timing,
 		announcer:          announcer,
 		attemptCounter:     0,
 		attemptStartBlock:  initialStartBlock,
@@ -126,6 +126,7 @@ type dkgAttemptFn func(*dkgAttemptParams) (*dkg.Result, error)
 func (drl *dkgRetryLoop) start(
 	ctx context.Context,
 	waitForBlockFn waitForBlockFn,
+	getCurrentBlockFn getCurrentBlockFn,
 	dkgAttemptFn dkgAttemptFn,
 ) (*dkg.Result, error) {
 	for {
@@ -148,17 +149,34 @@ func (drl *dkgRetryLoop) start(
 		// by some additional delay blocks. We need a small cool down in
 		// order to mitigate all corner cases where the actual attempt duration
 		// was slightly longer than the expected duration determined by the
-		// dkgAttemptMaximumProtocolBlocks constant.
+		// maximum protocol duration of the DKG timing.
 		//
 		// For example, the attempt may fail at the end of the protocol but the
 		// error is returned after some time and more blocks than expected are
 		// mined in the meantime.
 		if drl.attemptCounter > 1 {
 			drl.attemptStartBlock = drl.attemptStartBlock +
-				uint64(dkgAttemptMaximumBlocks())
+				drl.timing.DKG.MaximumBlocks()
+		}
+
+		announcementStartBlock := drl.attemptStartBlock +
+			drl.timing.DKG.AnnouncementDelayBlocks
+		announcementEndBlock := announcementStartBlock +
+			drl.timing.DKG.AnnouncementActiveBlocks
+		timeoutBlock := announcementEndBlock +
+			drl.timing.DKG.MaximumProtocolBlocks
+
+		attemptTiming := &JournalAttemptTiming{
+			Protocol:           "dkg",
+			SessionID:          fmt.Sprintf("%v-%v", drl.seed.Text(16), drl.attemptCounter),
+			MemberIndex:        drl.memberIndex,
+			AttemptNumber:      drl.attemptCounter,
+			TimingVersion:      drl.timing.Version,
+			StartBlock:         drl.attemptStartBlock,
+			ProtocolStartBlock: announcementEndBlock,
+			TimeoutBlock:       timeoutBlock,
 		}
 
-		announcementStartBlock := drl.attemptStartBlock + dkgAttemptAnnouncementDelayBlocks
 		err := waitForBlockFn(ctx, announcementStartBlock)
 		if err != nil {
 			return nil, fmt.Errorf(
@@ -172,7 +190,6 @@ func (drl *dkgRetryLoop) start(
 
 		// Set up the announcement phase stop signal.
 		announceCtx, cancelAnnounceCtx := context.WithCancel(ctx)
-		announcementEndBlock := announcementStartBlock + dkgAttemptAnnouncementActiveBlocks
 		go func() {
 			defer cancelAnnounceCtx()
 
@@ -207,6 +224,7 @@ func (drl *dkgRetryLoop) start(
 				drl.attemptCounter,
 				err,
 			)
+			drl.recordAttempt(attemptTiming, AttemptNotReady, err)
 			continue
 		}
 
@@ -241,6 +259,7 @@ func (drl *dkgRetryLoop) start(
 				len(readyMembersIndexes),
 				unreadyMembersIndexes,
 			)
+			drl.recordAttempt(attemptTiming, AttemptNotReady, nil)
 			continue
 		}
 
@@ -260,8 +279,6 @@ func (drl *dkgRetryLoop) start(
 			drl.memberIndex,
 		)
 
-		timeoutBlock := announcementEndBlock + dkgAttemptMaximumProtocolBlocks
-
 		var result *dkg.Result
 		var attemptErr error
 
@@ -280,14 +297,44 @@ func (drl *dkgRetryLoop) start(
 			)
 		}
 
-		if attemptSkipped || attemptErr != nil {
+		if attemptSkipped {
+			drl.recordAttempt(attemptTiming, AttemptSkipped, nil)
 			continue
 		}
 
+		attemptTiming.Participated = true
+		if drl.attemptRecorder != nil {
+			if currentBlock, blockErr := getCurrentBlockFn(); blockErr == nil {
+				attemptTiming.ProtocolEndBlock = currentBlock
+			}
+		}
+
+		if attemptErr != nil {
+			drl.recordAttempt(attemptTiming, AttemptFailed, attemptErr)
+			continue
+		}
+
+		drl.recordAttempt(attemptTiming, AttemptSucceeded, nil)
+
 		return result, nil
 	}
 }
 
+// recordAttempt records the given outcome of the given attempt, if the attempt
+// recorder is set.
+func (drl *dkgRetryLoop) recordAttempt(
+	attempt *JournalAttemptTiming,
+	outcome string,
+	attemptErr error,
+) {
+	if drl.attemptRecorder == nil {
+		return
+	}
+
+	attempt.Outcome = outcome
+	drl.attemptRecorder(attempt, attemptErr)
+}
+
 // performMembersSelection runs the member selection process whose result
 // is a list of members' indexes that should be excluded by the client
 // for the given DKG attempt.
@@ -279,6 +279,7 @@ func TestDkgRetryLoop(t *testing.T) {
 				test.memberIndex,
 				selectedOperators,
 				groupParameters,
+				DefaultTimingSchedule().timingAt(200),
 				announcer,
 				test.attemptsLimit,
 			)
@@ -293,6 +294,9 @@ func TestDkgRetryLoop(t *testing.T) {
 				func(ctx context.Context, attemptStartBlock uint64) error {
 					return nil
 				},
+				func() (uint64, error) {
+					return 0, nil
+				},
 				func(params *dkgAttemptParams) (*dkg.Result, error) {
 					lastAttempt = params
 					return test.dkgAttemptFn(params)
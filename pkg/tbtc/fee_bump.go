@@ -0,0 +1,968 @@
+package tbtc
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ipfs/go-log/v2"
+	"go.uber.org/zap"
+
+	"github.com/keep-network/keep-core/pkg/bitcoin"
+)
+
+const (
+	// feeBumpProposalValidityBlocks determines the fee bump proposal validity
+	// time expressed in blocks. In other words, this is the worst-case time
+	// for a fee bump during which the wallet is busy and cannot take another
+	// actions. The value of 600 blocks is roughly 2 hours, assuming 12 seconds
+	// per block.
+	feeBumpProposalValidityBlocks = 600
+	// feeBumpSigningTimeoutSafetyMarginBlocks determines the duration of the
+	// safety margin that must be preserved between the signing timeout and
+	// the timeout of the entire fee bump action. This safety margin prevents
+	// against the case where signing completes late and there is not enough
+	// time to broadcast the replacement transaction properly. In such a case,
+	// wallet signatures may leak and make the wallet subject of fraud
+	// accusations. Usage of the safety margin ensures there is enough time to
+	// perform post-signing steps of the fee bump action. The value of 300
+	// blocks is roughly 1 hour, assuming 12 seconds per block.
+	feeBumpSigningTimeoutSafetyMarginBlocks = 300
+	// feeBumpBroadcastTimeout determines the time window for the replacement
+	// transaction broadcast. It is guaranteed that at least
+	// feeBumpSigningTimeoutSafetyMarginBlocks is preserved for the broadcast
+	// step. However, the happy path for the broadcast step is usually quick
+	// and few retries are needed to recover from temporary problems. That
+	// said, if the broadcast step does not succeed in a tight timeframe,
+	// there is no point to retry for the entire possible time window. Hence,
+	// the timeout for broadcast step is set as 25% of the entire time widow
+	// determined by feeBumpSigningTimeoutSafetyMarginBlocks.
+	feeBumpBroadcastTimeout = 15 * time.Minute
+	// feeBumpBroadcastCheckDelay determines the delay that must be preserved
+	// between transaction broadcast and the check that ensures the
+	// transaction is known on the Bitcoin chain. This delay is needed as
+	// spreading the transaction over the Bitcoin network takes time.
+	feeBumpBroadcastCheckDelay = 1 * time.Minute
+	// feeBumpIncrementalRelayFee is the minimum fee rate, in satoshi per
+	// virtual byte, the replacement transaction must pay on top of the fee of
+	// the replaced transaction. This is the default incremental relay fee
+	// used by Bitcoin Core to enforce rule 4 of BIP-125.
+	feeBumpIncrementalRelayFee = int64(1)
+)
+
+// FeeBumpTransactionMinAge is the minimum time a wallet transaction must
+// stay unconfirmed in the mempool before it can be subject of a fee bump.
+// The age is measured from the moment the transaction was first observed
+// by the node.
+const FeeBumpTransactionMinAge = 6 * time.Hour
+
+// FeeBumpProposal represents a fee bump proposal issued by a wallet's
+// coordination leader. The proposal points to an unconfirmed wallet
+// transaction that should be replaced with a transaction spending the same
+// inputs but paying the given total fee.
+type FeeBumpProposal struct {
+	TransactionHash bitcoin.Hash
+	TxFee           *big.Int
+}
+
+func (fbp *FeeBumpProposal) ActionType() WalletActionType {
+	return ActionFeeBump
+}
+
+func (fbp *FeeBumpProposal) ValidityBlocks() uint64 {
+	return feeBumpProposalValidityBlocks
+}
+
+// unconfirmedTransactionsTracker keeps track of the moment the node first
+// observed unconfirmed transactions of specific wallets in the Bitcoin
+// mempool. Bitcoin backends do not expose the time a transaction entered
+// their mempool so, the tracker is used to determine whether a transaction
+// is stuck long enough to be subject of a fee bump.
+type unconfirmedTransactionsTracker struct {
+	btcChain bitcoin.Chain
+
+	mutex sync.Mutex
+	// firstSeen holds the first-seen times of unconfirmed transactions
+	// grouped by the 20-byte public key hash of the wallet.
+	firstSeen map[[20]byte]map[bitcoin.Hash]time.Time
+}
+
+func newUnconfirmedTransactionsTracker(
+	btcChain bitcoin.Chain,
+) *unconfirmedTransactionsTracker {
+	return &unconfirmedTransactionsTracker{
+		btcChain:  btcChain,
+		firstSeen: make(map[[20]byte]map[bitcoin.Hash]time.Time),
+	}
+}
+
+// observe fetches the current mempool of the given wallet and records
+// transactions that were not seen before. Transactions that left the mempool
+// are forgotten. Returns a copy of first-seen times of all unconfirmed
+// transactions of the wallet.
+func (uct *unconfirmedTransactionsTracker) observe(
+	walletPublicKeyHash [20]byte,
+) (map[bitcoin.Hash]time.Time, error) {
+	mempool, err := uct.btcChain.GetMempoolForPublicKeyHash(walletPublicKeyHash)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get wallet mempool: [%v]", err)
+	}
+
+	uct.mutex.Lock()
+	defer uct.mutex.Unlock()
+
+	previous := uct.firstSeen[walletPublicKeyHash]
+	current := make(map[bitcoin.Hash]time.Time, len(mempool))
+	now := time.Now()
+
+	for _, transaction := range mempool {
+		txHash := transaction.Hash()
+
+		if firstSeen, ok := previous[txHash]; ok {
+			current[txHash] = firstSeen
+		} else {
+			current[txHash] = now
+		}
+	}
+
+	uct.firstSeen[walletPublicKeyHash] = current
+
+	result := make(map[bitcoin.Hash]time.Time, len(current))
+	for txHash, firstSeen := range current {
+		result[txHash] = firstSeen
+	}
+
+	return result, nil
+}
+
+// firstSeenAt returns the moment the given transaction of the given wallet
+// was first observed. The returned bool value indicates whether the
+// transaction is known to the tracker.
+func (uct *unconfirmedTransactionsTracker) firstSeenAt(
+	walletPublicKeyHash [20]byte,
+	txHash bitcoin.Hash,
+) (time.Time, bool) {
+	uct.mutex.Lock()
+	defer uct.mutex.Unlock()
+
+	firstSeen, ok := uct.firstSeen[walletPublicKeyHash][txHash]
+	return firstSeen, ok
+}
+
+type feeBumpAction struct {
+	logger   *zap.SugaredLogger
+	chain    Chain
+	btcChain bitcoin.Chain
+
+	feeBumpingWallet    wallet
+	transactionExecutor *walletTransactionExecutor
+	transactionsTracker *unconfirmedTransactionsTracker
+
+	proposal                     *FeeBumpProposal
+	proposalProcessingStartBlock uint64
+	proposalExpiryBlock          uint64
+
+	transactionMinAge                time.Duration
+	signingTimeoutSafetyMarginBlocks uint64
+	broadcastTimeout                 time.Duration
+	broadcastCheckDelay              time.Duration
+}
+
+func newFeeBumpAction(
+	logger *zap.SugaredLogger,
+	chain Chain,
+	btcChain bitcoin.Chain,
+	feeBumpingWallet wallet,
+	signingExecutor walletSigningExecutor,
+	transactionsTracker *unconfirmedTransactionsTracker,
+	proposal *FeeBumpProposal,
+	proposalProcessingStartBlock uint64,
+	proposalExpiryBlock uint64,
+	waitForBlockFn waitForBlockFn,
+) *feeBumpAction {
+	transactionExecutor := newWalletTransactionExecutor(
+		btcChain,
+		feeBumpingWallet,
+		signingExecutor,
+		waitForBlockFn,
+	)
+
+	return &feeBumpAction{
+		logger:                           logger,
+		chain:                            chain,
+		btcChain:                         btcChain,
+		feeBumpingWallet:                 feeBumpingWallet,
+		transactionExecutor:              transactionExecutor,
+		transactionsTracker:              transactionsTracker,
+		proposal:                         proposal,
+		proposalProcessingStartBlock:     proposalProcessingStartBlock,
+		proposalExpiryBlock:              proposalExpiryBlock,
+		transactionMinAge:                FeeBumpTransactionMinAge,
+		signingTimeoutSafetyMarginBlocks: feeBumpSigningTimeoutSafetyMarginBlocks,
+		broadcastTimeout:                 feeBumpBroadcastTimeout,
+		broadcastCheckDelay:              feeBumpBroadcastCheckDelay,
+	}
+}
+
+func (fba *feeBumpAction) execute() error {
+	validateProposalLogger := fba.logger.With(
+		zap.String("step", "validateProposal"),
+	)
+
+	walletPublicKeyHash := bitcoin.PublicKeyHash(fba.wallet().publicKey)
+
+	analysis, err := validateFeeBumpProposal(
+		validateProposalLogger,
+		walletPublicKeyHash,
+		fba.proposal,
+		fba.chain,
+		fba.btcChain,
+	)
+	if err != nil {
+		return fmt.Errorf("validate proposal step failed: [%v]", err)
+	}
+
+	// The leader's view on the transaction age cannot be trusted so, every
+	// signer checks the age against its own observations.
+	if fba.transactionsTracker == nil {
+		return fmt.Errorf("unconfirmed transactions tracker is not set")
+	}
+
+	firstSeen, ok := fba.transactionsTracker.firstSeenAt(
+		walletPublicKeyHash,
+		fba.proposal.TransactionHash,
+	)
+	if !ok {
+		return fmt.Errorf(
+			"transaction [%s] was not observed in the wallet mempool",
+			fba.proposal.TransactionHash.Hex(bitcoin.ReversedByteOrder),
+		)
+	}
+	if age := time.Since(firstSeen); age < fba.transactionMinAge {
+		return fmt.Errorf(
+			"transaction [%s] is unconfirmed for [%v] which is less "+
+				"than the required [%v]",
+			fba.proposal.TransactionHash.Hex(bitcoin.ReversedByteOrder),
+			age,
+			fba.transactionMinAge,
+		)
+	}
+
+	unsignedReplacementTx, err := assembleFeeBumpTransaction(
+		fba.btcChain,
+		fba.wallet().publicKey,
+		analysis,
+		fba.proposal.TxFee.Int64(),
+	)
+	if err != nil {
+		return fmt.Errorf(
+			"error while assembling replacement transaction: [%v]",
+			err,
+		)
+	}
+
+	signTxLogger := fba.logger.With(
+		zap.String("step", "signTransaction"),
+	)
+
+	// Just in case. This should never happen.
+	if fba.proposalExpiryBlock < fba.signingTimeoutSafetyMarginBlocks {
+		return fmt.Errorf("invalid proposal expiry block")
+	}
+
+	replacementTx, err := fba.transactionExecutor.signTransaction(
+		signTxLogger,
+		unsignedReplacementTx,
+		fba.proposalProcessingStartBlock,
+		fba.proposalExpiryBlock-fba.signingTimeoutSafetyMarginBlocks,
+	)
+	if err != nil {
+		return fmt.Errorf("sign transaction step failed: [%v]", err)
+	}
+
+	broadcastTxLogger := fba.logger.With(
+		zap.String("step", "broadcastTransaction"),
+		zap.String(
+			"replacementTxHash",
+			replacementTx.Hash().Hex(bitcoin.ReversedByteOrder),
+		),
+	)
+
+	err = fba.transactionExecutor.broadcastTransaction(
+		broadcastTxLogger,
+		replacementTx,
+		fba.broadcastTimeout,
+		fba.broadcastCheckDelay,
+	)
+	if err != nil {
+		return fmt.Errorf("broadcast transaction step failed: [%v]", err)
+	}
+
+	return nil
+}
+
+func (fba *feeBumpAction) wallet() wallet {
+	return fba.feeBumpingWallet
+}
+
+func (fba *feeBumpAction) actionType() WalletActionType {
+	return ActionFeeBump
+}
+
+// broadcastedTransaction returns the hash of the transaction broadcasted
+// by the action, or nil if the action did not broadcast a transaction.
+func (fba *feeBumpAction) broadcastedTransaction() *bitcoin.Hash {
+	return fba.transactionExecutor.broadcastedTransaction()
+}
+
+// feeBumpChain is the subset of the host chain required to determine the
+// fee bounds of a wallet transaction.
+type feeBumpChain interface {
+	// GetDepositRequest gets the on-chain deposit request for the given
+	// funding transaction hash and output index. The returned bool value
+	// indicates whether the request was found or not.
+	GetDepositRequest(
+		fundingTxHash bitcoin.Hash,
+		fundingOutputIndex uint32,
+	) (*DepositChainRequest, bool, error)
+
+	// GetMovedFundsSweepRequest gets the on-chain moved funds sweep request
+	// for the given moving funds transaction hash and output index. The
+	// returned bool value indicates whether the request was found or not.
+	GetMovedFundsSweepRequest(
+		movingFundsTxHash bitcoin.Hash,
+		movingFundsTxOutpointIndex uint32,
+	) (*MovedFundsSweepRequest, bool, error)
+
+	// GetPendingRedemptionRequest gets the on-chain pending redemption request
+	// for the given wallet public key hash and redeemer output script.
+	// The returned bool value indicates whether the request was found or not.
+	GetPendingRedemptionRequest(
+		walletPublicKeyHash [20]byte,
+		redeemerOutputScript bitcoin.Script,
+	) (*RedemptionRequest, bool, error)
+
+	// GetDepositParameters gets the current value of parameters relevant
+	// for the depositing process.
+	GetDepositParameters() (
+		dustThreshold uint64,
+		treasuryFeeDivisor uint64,
+		txMaxFee uint64,
+		revealAheadPeriod uint32,
+		err error,
+	)
+
+	// GetRedemptionParameters gets the current value of parameters relevant
+	// for the redemption process.
+	GetRedemptionParameters() (
+		dustThreshold uint64,
+		treasuryFeeDivisor uint64,
+		txMaxFee uint64,
+		txMaxTotalFee uint64,
+		timeout uint32,
+		timeoutSlashingAmount *big.Int,
+		timeoutNotifierRewardMultiplier uint32,
+		err error,
+	)
+
+	// GetMovingFundsParameters gets the current value of parameters relevant
+	// for the moving funds process.
+	GetMovingFundsParameters() (
+		txMaxTotalFee uint64,
+		dustThreshold uint64,
+		timeoutResetDelay uint32,
+		timeout uint32,
+		timeoutSlashingAmount *big.Int,
+		timeoutNotifierRewardMultiplier uint32,
+		commitmentGasOffset uint16,
+		sweepTxMaxTotalFee uint64,
+		sweepTimeout uint32,
+		sweepTimeoutSlashingAmount *big.Int,
+		sweepTimeoutNotifierRewardMultiplier uint32,
+		err error,
+	)
+}
+
+// feeBumpInput represents an input of the transaction being replaced.
+type feeBumpInput struct {
+	utxo *bitcoin.UnspentTransactionOutput
+	// redeemScript is set only for inputs spending deposit UTXOs.
+	redeemScript bitcoin.Script
+}
+
+// feeBumpTransactionAnalysis holds the outcome of the analysis of a wallet
+// transaction that is going to be replaced.
+type feeBumpTransactionAnalysis struct {
+	transaction *bitcoin.Transaction
+	inputs      []*feeBumpInput
+	// actionType is the type of the wallet action that produced the
+	// transaction. It can be ActionDepositSweep, ActionMovedFundsSweep or
+	// ActionRedemption.
+	actionType WalletActionType
+	// redemptionRequests holds redemption requests handled by the
+	// transaction, ordered in the same way as the redeemer outputs. Set only
+	// for redemption transactions.
+	redemptionRequests []*RedemptionRequest
+	// redemptionShape is the shape of the redemption transaction. Set only
+	// for redemption transactions.
+	redemptionShape RedemptionTransactionShape
+
+	currentFee int64
+	minFee     int64
+	maxFee     int64
+}
+
+// analyzeFeeBumpTransaction determines the kind of the given wallet
+// transaction and computes the range of fees a replacement transaction is
+// allowed to pay. The lower bound follows the BIP-125 replacement rules
+// while the upper bound follows the maximum fees enforced by the Bridge
+// upon SPV proof submission.
+func analyzeFeeBumpTransaction(
+	walletPublicKeyHash [20]byte,
+	transaction *bitcoin.Transaction,
+	chain feeBumpChain,
+	btcChain bitcoin.Chain,
+) (*feeBumpTransactionAnalysis, error) {
+	if len(transaction.Inputs) == 0 || len(transaction.Outputs) == 0 {
+		return nil, fmt.Errorf("transaction has no inputs or no outputs")
+	}
+
+	inputs := make([]*feeBumpInput, len(transaction.Inputs))
+	totalInputsValue := int64(0)
+	depositsCount := 0
+	movedFundsCount := 0
+
+	for i, input := range transaction.Inputs {
+		previousTx, err := btcChain.GetTransaction(
+			input.Outpoint.TransactionHash,
+		)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"cannot get transaction spent by input [%v]: [%v]",
+				i,
+				err,
+			)
+		}
+
+		if int(input.Outpoint.OutputIndex) >= len(previousTx.Outputs) {
+			return nil, fmt.Errorf(
+				"input [%v] points to a non-existing output",
+				i,
+			)
+		}
+
+		previousOutput := previousTx.Outputs[input.Outpoint.OutputIndex]
+
+		utxo := &bitcoin.UnspentTransactionOutput{
+			Outpoint: input.Outpoint,
+			Value:    previousOutput.Value,
+		}
+
+		totalInputsValue += utxo.Value
+
+		switch bitcoin.GetScriptType(previousOutput.PublicKeyScript) {
+		case bitcoin.P2PKHScript, bitcoin.P2WPKHScript:
+			publicKeyHash, err := bitcoin.ExtractPublicKeyHash(
+				previousOutput.PublicKeyScript,
+			)
+			if err != nil {
+				return nil, fmt.Errorf(
+					"cannot extract public key hash of input [%v]: [%v]",
+					i,
+					err,
+				)
+			}
+
+			if publicKeyHash != walletPublicKeyHash {
+				return nil, fmt.Errorf(
+					"input [%v] does not belong to the wallet",
+					i,
+				)
+			}
+
+			// A wallet-controlled input is either the wallet's main UTXO
+			// or a moved funds UTXO waiting to be swept.
+			request, found, err := chain.GetMovedFundsSweepRequest(
+				input.Outpoint.TransactionHash,
+				input.Outpoint.OutputIndex,
+			)
+			if err != nil {
+				return nil, fmt.Errorf(
+					"cannot get moved funds sweep request for input [%v]: [%v]",
+					i,
+					err,
+				)
+			}
+			if found && request.State == MovedFundsStatePending {
+				movedFundsCount++
+			}
+
+			inputs[i] = &feeBumpInput{utxo: utxo}
+		case bitcoin.P2SHScript, bitcoin.P2WSHScript:
+			redeemScript, err := bitcoin.ExtractRedeemScript(input)
+			if err != nil {
+				return nil, fmt.Errorf(
+					"cannot extract redeem script of input [%v]: [%v]",
+					i,
+					err,
+				)
+			}
+
+			var expectedScript bitcoin.Script
+			if bitcoin.GetScriptType(previousOutput.PublicKeyScript) ==
+				bitcoin.P2WSHScript {
+				expectedScript, err = bitcoin.PayToWitnessScriptHash(
+					bitcoin.WitnessScriptHash(redeemScript),
+				)
+			} else {
+				expectedScript, err = bitcoin.PayToScriptHash(
+					bitcoin.ScriptHash(redeemScript),
+				)
+			}
+			if err != nil {
+				return nil, fmt.Errorf(
+					"cannot compute locking script of input [%v]: [%v]",
+					i,
+					err,
+				)
+			}
+
+			if !bytes.Equal(expectedScript, previousOutput.PublicKeyScript) {
+				return nil, fmt.Errorf(
+					"redeem script of input [%v] does not match the "+
+						"spent output",
+					i,
+				)
+			}
+
+			request, found, err := chain.GetDepositRequest(
+				input.Outpoint.TransactionHash,
+				input.Outpoint.OutputIndex,
+			)
+			if err != nil {
+				return nil, fmt.Errorf(
+					"cannot get deposit request for input [%v]: [%v]",
+					i,
+					err,
+				)
+			}
+			if !found {
+				return nil, fmt.Errorf(
+					"input [%v] is not a revealed deposit",
+					i,
+				)
+			}
+			if request.SweptAt.Unix() != 0 {
+				return nil, fmt.Errorf("deposit of input [%v] is already swept", i)
+			}
+
+			depositsCount++
+
+			inputs[i] = &feeBumpInput{utxo: utxo, redeemScript: redeemScript}
+		default:
+			return nil, fmt.Errorf("input [%v] has a non-standard script", i)
+		}
+	}
+
+	totalOutputsValue := int64(0)
+	for _, output := range transaction.Outputs {
+		totalOutputsValue += output.Value
+	}
+
+	analysis := &feeBumpTransactionAnalysis{
+		transaction: transaction,
+		inputs:      inputs,
+		currentFee:  totalInputsValue - totalOutputsValue,
+		// According to BIP-125, the replacement must pay for its own
+		// bandwidth on top of the fee paid by the replaced transaction.
+		// The replacement has the same shape as the replaced transaction
+		// so, its virtual size is the same.
+		minFee: totalInputsValue - totalOutputsValue +
+			transaction.VirtualSize()*feeBumpIncrementalRelayFee,
+	}
+
+	switch {
+	case depositsCount > 0 && movedFundsCount > 0:
+		return nil, fmt.Errorf(
+			"transaction sweeps both deposits and moved funds",
+		)
+	case depositsCount > 0:
+		err := checkFeeBumpSweepOutputs(walletPublicKeyHash, transaction)
+		if err != nil {
+			return nil, err
+		}
+
+		_, _, depositTxMaxFee, _, err := chain.GetDepositParameters()
+		if err != nil {
+			return nil, fmt.Errorf("cannot get deposit parameters: [%v]", err)
+		}
+
+		analysis.actionType = ActionDepositSweep
+		analysis.maxFee = int64(depositTxMaxFee) * int64(depositsCount)
+	case movedFundsCount > 0:
+		if movedFundsCount != 1 {
+			return nil, fmt.Errorf(
+				"transaction sweeps [%v] moved funds UTXOs",
+				movedFundsCount,
+			)
+		}
+
+		err := checkFeeBumpSweepOutputs(walletPublicKeyHash, transaction)
+		if err != nil {
+			return nil, err
+		}
+
+		_, _, _, _, _, _, _, sweepTxMaxTotalFee, _, _, _, err :=
+			chain.GetMovingFundsParameters()
+		if err != nil {
+			return nil, fmt.Errorf(
+				"cannot get moving funds parameters: [%v]",
+				err,
+			)
+		}
+
+		analysis.actionType = ActionMovedFundsSweep
+		analysis.maxFee = int64(sweepTxMaxTotalFee)
+	default:
+		if len(transaction.Inputs) != 1 {
+			return nil, fmt.Errorf(
+				"redemption transaction must have exactly one input",
+			)
+		}
+
+		err := analyzeFeeBumpRedemption(
+			walletPublicKeyHash,
+			transaction,
+			chain,
+			analysis,
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return analysis, nil
+}
+
+// checkFeeBumpSweepOutputs makes sure the given sweep transaction has a
+// single output locking funds on the wallet itself.
+func checkFeeBumpSweepOutputs(
+	walletPublicKeyHash [20]byte,
+	transaction *bitcoin.Transaction,
+) error {
+	if len(transaction.Outputs) != 1 {
+		return fmt.Errorf("sweep transaction must have exactly one output")
+	}
+
+	if !isWalletOutput(walletPublicKeyHash, transaction.Outputs[0]) {
+		return fmt.Errorf("sweep transaction output does not pay the wallet")
+	}
+
+	return nil
+}
+
+// analyzeFeeBumpRedemption completes the analysis of a redemption
+// transaction by matching its outputs with pending redemption requests.
+func analyzeFeeBumpRedemption(
+	walletPublicKeyHash [20]byte,
+	transaction *bitcoin.Transaction,
+	chain feeBumpChain,
+	analysis *feeBumpTransactionAnalysis,
+) error {
+	changeIndex := -1
+	requests := make([]*RedemptionRequest, 0)
+
+	for i, output := range transaction.Outputs {
+		if isWalletOutput(walletPublicKeyHash, output) {
+			if changeIndex >= 0 {
+				return fmt.Errorf(
+					"redemption transaction has more than one change output",
+				)
+			}
+
+			changeIndex = i
+			continue
+		}
+
+		request, found, err := chain.GetPendingRedemptionRequest(
+			walletPublicKeyHash,
+			output.PublicKeyScript,
+		)
+		if err != nil {
+			return fmt.Errorf(
+				"cannot get pending redemption request for output [%v]: [%v]",
+				i,
+				err,
+			)
+		}
+		if !found {
+			return fmt.Errorf(
+				"output [%v] is not a pending redemption request",
+				i,
+			)
+		}
+
+		requests = append(requests, request)
+	}
+
+	if len(requests) == 0 {
+		return fmt.Errorf("redemption transaction has no redeemer outputs")
+	}
+
+	shape := RedemptionChangeFirst
+	if changeIndex > 0 {
+		if changeIndex != len(transaction.Outputs)-1 {
+			return fmt.Errorf(
+				"change output must be the first or the last output",
+			)
+		}
+
+		shape = RedemptionChangeLast
+	}
+
+	_, _, _, txMaxTotalFee, _, _, _, err := chain.GetRedemptionParameters()
+	if err != nil {
+		return fmt.Errorf("cannot get redemption parameters: [%v]", err)
+	}
+
+	// Fee shares are spread using withRedemptionSpreadFee so, the highest
+	// share never exceeds the total fee divided by the requests count,
+	// rounded up.
+	minTxMaxFee := requests[0].TxMaxFee
+	for _, request := range requests[1:] {
+		if request.TxMaxFee < minTxMaxFee {
+			minTxMaxFee = request.TxMaxFee
+		}
+	}
+
+	maxFee := int64(minTxMaxFee) * int64(len(requests))
+	if int64(txMaxTotalFee) < maxFee {
+		maxFee = int64(txMaxTotalFee)
+	}
+
+	analysis.actionType = ActionRedemption
+	analysis.redemptionRequests = requests
+	analysis.redemptionShape = shape
+	analysis.maxFee = maxFee
+
+	return nil
+}
+
+// isWalletOutput determines whether the given output locks funds on the
+// wallet with the given public key hash.
+func isWalletOutput(
+	walletPublicKeyHash [20]byte,
+	output *bitcoin.TransactionOutput,
+) bool {
+	publicKeyHash, err := bitcoin.ExtractPublicKeyHash(output.PublicKeyScript)
+	if err != nil {
+		return false
+	}
+
+	return publicKeyHash == walletPublicKeyHash
+}
+
+// withRedemptionSpreadFee is a fee distribution function that takes a total
+// transaction fee and distributes it over all redemption requests. If the
+// fee cannot be divided evenly, the remainder is spread over the first
+// requests, one satoshi each. That way, no fee share exceeds the total fee
+// divided by the requests count, rounded up.
+func withRedemptionSpreadFee(totalFee int64) redemptionFeeDistributionFn {
+	return func(requests []*RedemptionRequest) []int64 {
+		requestsCount := int64(len(requests))
+		remainder := totalFee % requestsCount
+		feePerRequest := (totalFee - remainder) / requestsCount
+
+		feeShares := make([]int64, requestsCount)
+		for i := range requests {
+			feeShare := feePerRequest
+
+			if int64(i) < remainder {
+				feeShare++
+			}
+
+			feeShares[i] = feeShare
+		}
+
+		return feeShares
+	}
+}
+
+// DetermineFeeBumpRange determines the fee currently paid by the given
+// unconfirmed wallet transaction and the range of fees a replacement
+// transaction is allowed to pay. The returned minFee and maxFee bounds are
+// inclusive. If minFee is greater than maxFee, the transaction cannot be
+// replaced.
+func DetermineFeeBumpRange(
+	walletPublicKeyHash [20]byte,
+	transaction *bitcoin.Transaction,
+	chain BridgeChain,
+	btcChain bitcoin.Chain,
+) (currentFee int64, minFee int64, maxFee int64, err error) {
+	analysis, err := analyzeFeeBumpTransaction(
+		walletPublicKeyHash,
+		transaction,
+		chain,
+		btcChain,
+	)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	return analysis.currentFee, analysis.minFee, analysis.maxFee, nil
+}
+
+// ValidateFeeBumpProposal checks the fee bump proposal against the state of
+// the Bitcoin mempool and on-chain fee limits. The proposed fee must be high
+// enough to replace the original transaction and must not exceed the maximum
+// fee the Bridge accepts for the given kind of transaction.
+func ValidateFeeBumpProposal(
+	validateProposalLogger log.StandardLogger,
+	walletPublicKeyHash [20]byte,
+	proposal *FeeBumpProposal,
+	chain BridgeChain,
+	btcChain bitcoin.Chain,
+) error {
+	_, err := validateFeeBumpProposal(
+		validateProposalLogger,
+		walletPublicKeyHash,
+		proposal,
+		chain,
+		btcChain,
+	)
+
+	return err
+}
+
+func validateFeeBumpProposal(
+	validateProposalLogger log.StandardLogger,
+	walletPublicKeyHash [20]byte,
+	proposal *FeeBumpProposal,
+	chain feeBumpChain,
+	btcChain bitcoin.Chain,
+) (*feeBumpTransactionAnalysis, error) {
+	validateProposalLogger.Infof("looking up transaction in wallet mempool")
+
+	mempool, err := btcChain.GetMempoolForPublicKeyHash(walletPublicKeyHash)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get wallet mempool: [%v]", err)
+	}
+
+	var transaction *bitcoin.Transaction
+	for _, mempoolTx := range mempool {
+		if mempoolTx.Hash() == proposal.TransactionHash {
+			transaction = mempoolTx
+			break
+		}
+	}
+
+	if transaction == nil {
+		return nil, fmt.Errorf(
+			"transaction [%s] is not in the wallet mempool",
+			proposal.TransactionHash.Hex(bitcoin.ReversedByteOrder),
+		)
+	}
+
+	analysis, err := analyzeFeeBumpTransaction(
+		walletPublicKeyHash,
+		transaction,
+		chain,
+		btcChain,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("cannot analyze transaction: [%v]", err)
+	}
+
+	if proposal.TxFee == nil || !proposal.TxFee.IsInt64() {
+		return nil, fmt.Errorf("invalid proposed fee")
+	}
+
+	fee := proposal.TxFee.Int64()
+
+	if fee < analysis.minFee {
+		return nil, fmt.Errorf(
+			"proposed fee [%v] is lower than the minimum replacement fee [%v]",
+			fee,
+			analysis.minFee,
+		)
+	}
+
+	if fee > analysis.maxFee {
+		return nil, fmt.Errorf(
+			"proposed fee [%v] exceeds the maximum fee [%v]",
+			fee,
+			analysis.maxFee,
+		)
+	}
+
+	validateProposalLogger.Infof("fee bump proposal is valid")
+
+	return analysis, nil
+}
+
+// assembleFeeBumpTransaction constructs an unsigned transaction replacing
+// the analyzed wallet transaction. The replacement spends the same inputs
+// in the same order and differs only by the values of outputs that carry
+// the fee. For sweeps, the fee is deducted from the single wallet output.
+// For redemptions, the fee is spread over redeemer outputs while the change
+// output stays untouched.
+//
+// Transactions built by bitcoin.TransactionBuilder do not signal
+// replaceability explicitly so, the replacement propagates only through
+// nodes enforcing the full replace-by-fee policy. Signaling it would change
+// the sighash of all wallet transactions and require an activation block
+// for all operators. The limitation is documented for operators in the
+// fee bump section of docs/run-keep-node.adoc.
+func assembleFeeBumpTransaction(
+	bitcoinChain bitcoin.Chain,
+	walletPublicKey *ecdsa.PublicKey,
+	analysis *feeBumpTransactionAnalysis,
+	fee int64,
+) (*bitcoin.TransactionBuilder, error) {
+	if analysis.actionType == ActionRedemption {
+		return assembleRedemptionTransaction(
+			bitcoinChain,
+			walletPublicKey,
+			analysis.inputs[0].utxo,
+			analysis.redemptionRequests,
+			withRedemptionSpreadFee(fee),
+			analysis.redemptionShape,
+		)
+	}
+
+	builder := bitcoin.NewTransactionBuilder(bitcoinChain)
+
+	for i, input := range analysis.inputs {
+		var err error
+		if input.redeemScript != nil {
+			err = builder.AddScriptHashInput(input.utxo, input.redeemScript)
+		} else {
+			err = builder.AddPublicKeyHashInput(input.utxo)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("cannot add input [%v]: [%v]", i, err)
+		}
+	}
+
+	outputValue := builder.TotalInputsValue() - fee
+	if outputValue <= 0 {
+		return nil, fmt.Errorf("fee exceeds the value of inputs")
+	}
+
+	outputScript, err := bitcoin.PayToWitnessPublicKeyHash(
+		bitcoin.PublicKeyHash(walletPublicKey),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("cannot compute output script: [%v]", err)
+	}
+
+	builder.AddOutput(&bitcoin.TransactionOutput{
+		Value:           outputValue,
+		PublicKeyScript: outputScript,
+	})
+
+	return builder, nil
+}
@@ -0,0 +1,541 @@
+package tbtc
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/keep-network/keep-core/internal/testutils"
+	"github.com/keep-network/keep-core/pkg/bitcoin"
+	"github.com/keep-network/keep-core/pkg/tbtc/internal/test"
+)
+
+func TestFeeBump_Redemption(t *testing.T) {
+	scenarios, err := test.LoadRedemptionTestScenarios()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, scenario := range scenarios {
+		t.Run(scenario.Title, func(t *testing.T) {
+			hostChain := Connect()
+			bitcoinChain := newLocalBitcoinChain()
+
+			walletPublicKeyHash := bitcoin.PublicKeyHash(scenario.WalletPublicKey)
+
+			err := bitcoinChain.BroadcastTransaction(scenario.InputTransaction)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// Raise the per-request maximum fee so there is room for
+			// the bump. Fixtures use fee shares equal to the maximum.
+			txMaxFeeIncrease := uint64(5000)
+			for _, request := range scenario.RedemptionRequests {
+				hostChain.setPendingRedemptionRequest(
+					walletPublicKeyHash,
+					&RedemptionRequest{
+						Redeemer:             request.Redeemer,
+						RedeemerOutputScript: request.RedeemerOutputScript,
+						RequestedAmount:      request.RequestedAmount,
+						TreasuryFee:          request.TreasuryFee,
+						TxMaxFee:             request.TxMaxFee + txMaxFeeIncrease,
+						RequestedAt:          request.RequestedAt,
+					},
+				)
+			}
+
+			hostChain.SetRedemptionParameters(0, 0, 0, 1000000, 0, nil, 0)
+
+			stuckTx := scenario.ExpectedRedemptionTransaction
+
+			currentFee, minFee, maxFee, err := DetermineFeeBumpRange(
+				walletPublicKeyHash,
+				stuckTx,
+				hostChain,
+				bitcoinChain,
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expectedCurrentFee := int64(0)
+			minTxMaxFee := scenario.RedemptionRequests[0].TxMaxFee
+			for i, feeShare := range scenario.FeeShares {
+				expectedCurrentFee += feeShare
+
+				if scenario.RedemptionRequests[i].TxMaxFee < minTxMaxFee {
+					minTxMaxFee = scenario.RedemptionRequests[i].TxMaxFee
+				}
+			}
+
+			testutils.AssertIntsEqual(
+				t,
+				"current fee",
+				int(expectedCurrentFee),
+				int(currentFee),
+			)
+			testutils.AssertIntsEqual(
+				t,
+				"min fee",
+				int(expectedCurrentFee+stuckTx.VirtualSize()),
+				int(minFee),
+			)
+			testutils.AssertIntsEqual(
+				t,
+				"max fee",
+				int(minTxMaxFee+txMaxFeeIncrease)*len(scenario.FeeShares),
+				int(maxFee),
+			)
+
+			analysis, err := analyzeFeeBumpTransaction(
+				walletPublicKeyHash,
+				stuckTx,
+				hostChain,
+				bitcoinChain,
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			builder, err := assembleFeeBumpTransaction(
+				bitcoinChain,
+				scenario.WalletPublicKey,
+				analysis,
+				minFee,
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			replacementTx := signFeeBumpTransaction(
+				t,
+				builder,
+				scenario.WalletPublicKey,
+				scenario.WalletPrivateKey,
+			)
+
+			assertReplacementTransaction(
+				t,
+				stuckTx,
+				replacementTx,
+				builder.TotalInputsValue(),
+				minFee,
+			)
+
+			// The change output, if any, must stay untouched.
+			for i, output := range stuckTx.Outputs {
+				if isWalletOutput(walletPublicKeyHash, output) {
+					testutils.AssertIntsEqual(
+						t,
+						"change value",
+						int(output.Value),
+						int(replacementTx.Outputs[i].Value),
+					)
+				}
+			}
+		})
+	}
+}
+
+func TestFeeBump_DepositSweep(t *testing.T) {
+	scenarios, err := test.LoadDepositSweepTestScenarios()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, scenario := range scenarios {
+		t.Run(scenario.Title, func(t *testing.T) {
+			hostChain := Connect()
+			bitcoinChain := newLocalBitcoinChain()
+
+			walletPublicKeyHash := bitcoin.PublicKeyHash(scenario.WalletPublicKey)
+
+			for _, transaction := range scenario.InputTransactions {
+				err := bitcoinChain.BroadcastTransaction(transaction)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			for _, deposit := range scenario.Deposits {
+				hostChain.setDepositRequest(
+					deposit.Utxo.Outpoint.TransactionHash,
+					deposit.Utxo.Outpoint.OutputIndex,
+					&DepositChainRequest{
+						Depositor: deposit.Depositor,
+						Amount:    uint64(deposit.Utxo.Value),
+						SweptAt:   time.Unix(0, 0),
+					},
+				)
+			}
+
+			depositTxMaxFee := uint64(10000)
+			hostChain.SetDepositParameters(0, 0, depositTxMaxFee, 0)
+
+			stuckTx := scenario.ExpectedSweepTransaction
+
+			currentFee, minFee, maxFee, err := DetermineFeeBumpRange(
+				walletPublicKeyHash,
+				stuckTx,
+				hostChain,
+				bitcoinChain,
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			testutils.AssertIntsEqual(
+				t,
+				"current fee",
+				int(scenario.Fee),
+				int(currentFee),
+			)
+			testutils.AssertIntsEqual(
+				t,
+				"min fee",
+				int(scenario.Fee+stuckTx.VirtualSize()),
+				int(minFee),
+			)
+			testutils.AssertIntsEqual(
+				t,
+				"max fee",
+				int(depositTxMaxFee)*len(scenario.Deposits),
+				int(maxFee),
+			)
+
+			analysis, err := analyzeFeeBumpTransaction(
+				walletPublicKeyHash,
+				stuckTx,
+				hostChain,
+				bitcoinChain,
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			builder, err := assembleFeeBumpTransaction(
+				bitcoinChain,
+				scenario.WalletPublicKey,
+				analysis,
+				minFee,
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			replacementTx := signFeeBumpTransaction(
+				t,
+				builder,
+				scenario.WalletPublicKey,
+				scenario.WalletPrivateKey,
+			)
+
+			assertReplacementTransaction(
+				t,
+				stuckTx,
+				replacementTx,
+				builder.TotalInputsValue(),
+				minFee,
+			)
+		})
+	}
+}
+
+func TestValidateFeeBumpProposal(t *testing.T) {
+	scenarios, err := test.LoadRedemptionTestScenarios()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Take the scenario with multiple redemptions and a change output.
+	scenario := scenarios[4]
+	walletPublicKeyHash := bitcoin.PublicKeyHash(scenario.WalletPublicKey)
+	stuckTx := scenario.ExpectedRedemptionTransaction
+
+	currentFee := int64(0)
+	for _, feeShare := range scenario.FeeShares {
+		currentFee += feeShare
+	}
+	minFee := currentFee + stuckTx.VirtualSize()
+
+	var tests = map[string]struct {
+		txHash        bitcoin.Hash
+		txFee         *big.Int
+		txMaxTotalFee uint64
+		expectedErr   error
+	}{
+		"valid proposal": {
+			txHash:        stuckTx.Hash(),
+			txFee:         big.NewInt(minFee),
+			txMaxTotalFee: 1000000,
+		},
+		"transaction not in mempool": {
+			txHash:        bitcoin.Hash{0x01},
+			txFee:         big.NewInt(minFee),
+			txMaxTotalFee: 1000000,
+			expectedErr: fmt.Errorf(
+				"transaction [%s] is not in the wallet mempool",
+				bitcoin.Hash{0x01}.Hex(bitcoin.ReversedByteOrder),
+			),
+		},
+		"fee too low to replace": {
+			txHash:        stuckTx.Hash(),
+			txFee:         big.NewInt(minFee - 1),
+			txMaxTotalFee: 1000000,
+			expectedErr: fmt.Errorf(
+				"proposed fee [%v] is lower than the minimum replacement fee [%v]",
+				minFee-1,
+				minFee,
+			),
+		},
+		"fee exceeds the total maximum": {
+			txHash:        stuckTx.Hash(),
+			txFee:         big.NewInt(minFee),
+			txMaxTotalFee: uint64(minFee - 1),
+			expectedErr: fmt.Errorf(
+				"proposed fee [%v] exceeds the maximum fee [%v]",
+				minFee,
+				minFee-1,
+			),
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			hostChain := Connect()
+			bitcoinChain := newLocalBitcoinChain()
+
+			err := bitcoinChain.BroadcastTransaction(scenario.InputTransaction)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			bitcoinChain.mempool = append(bitcoinChain.mempool, stuckTx)
+
+			for _, request := range scenario.RedemptionRequests {
+				hostChain.setPendingRedemptionRequest(
+					walletPublicKeyHash,
+					&RedemptionRequest{
+						Redeemer:             request.Redeemer,
+						RedeemerOutputScript: request.RedeemerOutputScript,
+						RequestedAmount:      request.RequestedAmount,
+						TreasuryFee:          request.TreasuryFee,
+						TxMaxFee:             request.TxMaxFee + 5000,
+						RequestedAt:          request.RequestedAt,
+					},
+				)
+			}
+
+			hostChain.SetRedemptionParameters(
+				0,
+				0,
+				0,
+				test.txMaxTotalFee,
+				0,
+				nil,
+				0,
+			)
+
+			err = ValidateFeeBumpProposal(
+				&testutils.MockLogger{},
+				walletPublicKeyHash,
+				&FeeBumpProposal{
+					TransactionHash: test.txHash,
+					TxFee:           test.txFee,
+				},
+				hostChain,
+				bitcoinChain,
+			)
+
+			if !reflect.DeepEqual(test.expectedErr, err) {
+				t.Errorf(
+					"unexpected error\nexpected: [%v]\nactual:   [%v]",
+					test.expectedErr,
+					err,
+				)
+			}
+		})
+	}
+}
+
+func TestUnconfirmedTransactionsTracker(t *testing.T) {
+	scenarios, err := test.LoadRedemptionTestScenarios()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scenario := scenarios[0]
+	walletPublicKeyHash := bitcoin.PublicKeyHash(scenario.WalletPublicKey)
+	txHash := scenario.ExpectedRedemptionTransaction.Hash()
+
+	bitcoinChain := newLocalBitcoinChain()
+	tracker := newUnconfirmedTransactionsTracker(bitcoinChain)
+
+	bitcoinChain.mempool = append(
+		bitcoinChain.mempool,
+		scenario.ExpectedRedemptionTransaction,
+	)
+
+	firstObservation, err := tracker.observe(walletPublicKeyHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertIntsEqual(t, "observed count", 1, len(firstObservation))
+
+	secondObservation, err := tracker.observe(walletPublicKeyHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !firstObservation[txHash].Equal(secondObservation[txHash]) {
+		t.Errorf("first-seen time should not change between observations")
+	}
+
+	firstSeen, ok := tracker.firstSeenAt(walletPublicKeyHash, txHash)
+	if !ok {
+		t.Fatal("transaction should be known to the tracker")
+	}
+	if !firstSeen.Equal(firstObservation[txHash]) {
+		t.Errorf("unexpected first-seen time")
+	}
+
+	// Simulate the transaction got confirmed.
+	bitcoinChain.mempool = nil
+
+	_, err = tracker.observe(walletPublicKeyHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := tracker.firstSeenAt(walletPublicKeyHash, txHash); ok {
+		t.Errorf("transaction should be forgotten by the tracker")
+	}
+}
+
+func TestWithRedemptionSpreadFee(t *testing.T) {
+	var tests = map[string]struct {
+		totalFee          int64
+		requestsCount     int
+		expectedFeeShares []int64
+	}{
+		"total fee divisible by the requests count": {
+			totalFee:          10000,
+			requestsCount:     5,
+			expectedFeeShares: []int64{2000, 2000, 2000, 2000, 2000},
+		},
+		"total fee indivisible by the requests count": {
+			totalFee:          10003,
+			requestsCount:     5,
+			expectedFeeShares: []int64{2001, 2001, 2001, 2000, 2000},
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			requests := make([]*RedemptionRequest, test.requestsCount)
+
+			feeShares := withRedemptionSpreadFee(test.totalFee)(requests)
+
+			if !reflect.DeepEqual(test.expectedFeeShares, feeShares) {
+				t.Errorf(
+					"unexpected fee shares\nexpected: [%v]\nactual:   [%v]",
+					test.expectedFeeShares,
+					feeShares,
+				)
+			}
+		})
+	}
+}
+
+func signFeeBumpTransaction(
+	t *testing.T,
+	builder *bitcoin.TransactionBuilder,
+	walletPublicKey *ecdsa.PublicKey,
+	walletPrivateKey *big.Int,
+) *bitcoin.Transaction {
+	sigHashes, err := builder.ComputeSignatureHashes()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	privateKey := &ecdsa.PrivateKey{
+		PublicKey: *walletPublicKey,
+		D:         walletPrivateKey,
+	}
+
+	signatures := make([]*bitcoin.SignatureContainer, len(sigHashes))
+	for i, sigHash := range sigHashes {
+		r, s, err := ecdsa.Sign(rand.Reader, privateKey, sigHash.Bytes())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		signatures[i] = &bitcoin.SignatureContainer{
+			R:         r,
+			S:         s,
+			PublicKey: walletPublicKey,
+		}
+	}
+
+	transaction, err := builder.AddSignatures(signatures)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return transaction
+}
+
+func assertReplacementTransaction(
+	t *testing.T,
+	stuckTx *bitcoin.Transaction,
+	replacementTx *bitcoin.Transaction,
+	totalInputsValue int64,
+	expectedFee int64,
+) {
+	testutils.AssertIntsEqual(
+		t,
+		"inputs count",
+		len(stuckTx.Inputs),
+		len(replacementTx.Inputs),
+	)
+	for i, input := range stuckTx.Inputs {
+		if *input.Outpoint != *replacementTx.Inputs[i].Outpoint {
+			t.Errorf("input [%v] spends a different outpoint", i)
+		}
+	}
+
+	testutils.AssertIntsEqual(
+		t,
+		"outputs count",
+		len(stuckTx.Outputs),
+		len(replacementTx.Outputs),
+	)
+
+	totalOutputsValue := int64(0)
+	for i, output := range replacementTx.Outputs {
+		testutils.AssertBytesEqual(
+			t,
+			stuckTx.Outputs[i].PublicKeyScript,
+			output.PublicKeyScript,
+		)
+
+		totalOutputsValue += output.Value
+	}
+
+	testutils.AssertIntsEqual(
+		t,
+		"replacement fee",
+		int(expectedFee),
+		int(totalInputsValue-totalOutputsValue),
+	)
+
+	if replacementTx.Hash() == stuckTx.Hash() {
+		t.Errorf("replacement must differ from the stuck transaction")
+	}
+}
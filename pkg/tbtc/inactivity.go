@@ -12,7 +12,6 @@ import (
 	"golang.org/x/sync/semaphore"
 
 	"github.com/keep-network/keep-core/pkg/bitcoin"
-	"github.com/keep-network/keep-core/pkg/chain"
 	"github.com/keep-network/keep-core/pkg/generator"
 	"github.com/keep-network/keep-core/pkg/net"
 	"github.com/keep-network/keep-core/pkg/protocol/group"
@@ -189,29 +188,7 @@ func (ice *inactivityClaimExecutor) claimInactivity(
 }
 
 func (ice *inactivityClaimExecutor) getWalletOperatorsIDs() ([]uint32, error) {
-	// Cache mapping operator addresses to their wallet member IDs. It helps to
-	// limit the number of calls to the ETH client if some operator addresses
-	// occur on the list multiple times.
-	operatorIDCache := make(map[chain.Address]uint32)
-
-	walletMemberIDs := make([]uint32, 0)
-
-	for _, operatorAddress := range ice.wallet().signingGroupOperators {
-		// Search for the operator address in the cache. Store the operator
-		// address in the cache if it's not there.
-		if operatorID, found := operatorIDCache[operatorAddress]; !found {
-			fetchedOperatorID, err := ice.chain.GetOperatorID(operatorAddress)
-			if err != nil {
-				return nil, fmt.Errorf("could not get operator ID: [%w]", err)
-			}
-			operatorIDCache[operatorAddress] = fetchedOperatorID
-			walletMemberIDs = append(walletMemberIDs, fetchedOperatorID)
-		} else {
-			walletMemberIDs = append(walletMemberIDs, operatorID)
-		}
-	}
-
-	return walletMemberIDs, nil
+	return WalletMembersIDs(ice.wallet().signingGroupOperators, ice.chain)
 }
 
 func (ice *inactivityClaimExecutor) publishInactivityClaim(
@@ -0,0 +1,602 @@
+package tbtc
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/keep-network/keep-common/pkg/persistence"
+
+	"github.com/keep-network/keep-core/pkg/bitcoin"
+	"github.com/keep-network/keep-core/pkg/protocol/group"
+)
+
+const (
+	// journalDirectory is the name of the work persistence directory
+	// holding the audit journal.
+	journalDirectory = "journal"
+	// journalSegmentPrefix is the prefix of files holding consecutive
+	// entries of the audit journal.
+	journalSegmentPrefix = "segment_"
+	// journalSegmentLength is the maximum number of entries held by a single
+	// segment file. The current segment is rewritten upon each new entry so,
+	// segments are kept relatively small.
+	journalSegmentLength = 500
+)
+
+// JournalEntryType is the type of an audit journal entry.
+type JournalEntryType string
+
+const (
+	// JournalCoordinationWindow denotes the start of a coordination window.
+	JournalCoordinationWindow JournalEntryType = "coordination_window"
+	// JournalWalletCoordination denotes the outcome of the coordination
+	// procedure of a single wallet within a coordination window.
+	JournalWalletCoordination JournalEntryType = "wallet_coordination"
+	// JournalWalletActionStarted denotes the start of a wallet action.
+	JournalWalletActionStarted JournalEntryType = "wallet_action_started"
+	// JournalWalletActionFinished denotes the end of a wallet action.
+	JournalWalletActionFinished JournalEntryType = "wallet_action_finished"
+	// JournalShadowCoordination denotes the outcome of the coordination
+	// procedure of a single wallet executed in the shadow mode.
+	JournalShadowCoordination JournalEntryType = "shadow_coordination"
+	// JournalAttempt denotes the outcome and phase timings of a single
+	// signing or DKG attempt.
+	JournalAttempt JournalEntryType = "attempt"
+)
+
+// JournalEntry is a single entry of the audit journal.
+type JournalEntry struct {
+	// Sequence is the position of the entry in the journal.
+	Sequence uint64           `json:"sequence"`
+	Type     JournalEntryType `json:"type"`
+	Time     time.Time        `json:"time"`
+	// Block is the block height at the moment the entry was recorded.
+	Block uint64 `json:"block"`
+
+	WindowIndex       uint64 `json:"window_index,omitempty"`
+	CoordinationBlock uint64 `json:"coordination_block,omitempty"`
+
+	WalletPublicKeyHash string `json:"wallet_public_key_hash,omitempty"`
+	Leader              string `json:"leader,omitempty"`
+	ActionType          string `json:"action_type,omitempty"`
+	// Proposal is the coordination proposal encoded the same way as in
+	// coordination messages. Use DecodeProposal to decode it.
+	Proposal        []byte         `json:"proposal,omitempty"`
+	Faults          []JournalFault `json:"faults,omitempty"`
+	TransactionHash string         `json:"transaction_hash,omitempty"`
+	Error           string         `json:"error,omitempty"`
+
+	// Fields below are set only for entries recorded in the shadow mode.
+	// ActionsChecklist is the checklist of the coordination window while
+	// LocalActionType and LocalProposal describe the proposal generated
+	// locally on behalf of the leader.
+	ActionsChecklist []string `json:"actions_checklist,omitempty"`
+	LocalActionType  string   `json:"local_action_type,omitempty"`
+	LocalProposal    []byte   `json:"local_proposal,omitempty"`
+	ProposalsMatch   bool     `json:"proposals_match,omitempty"`
+
+	// Attempt is set only for attempt entries.
+	Attempt *JournalAttemptTiming `json:"attempt,omitempty"`
+}
+
+// DecodeProposal decodes the coordination proposal held by the entry.
+func (je *JournalEntry) DecodeProposal() (CoordinationProposal, error) {
+	if len(je.Proposal) == 0 {
+		return nil, fmt.Errorf("entry does not hold a proposal")
+	}
+
+	actionType, err := parseWalletActionTypeName(je.ActionType)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse proposal action type: [%v]", err)
+	}
+
+	return unmarshalCoordinationProposal(uint32(actionType), je.Proposal)
+}
+
+// JournalAttemptTiming holds the outcome and phase timings of a single
+// signing or DKG attempt, as seen by the given member.
+type JournalAttemptTiming struct {
+	// Protocol is either "signing" or "dkg".
+	Protocol      string            `json:"protocol"`
+	SessionID     string            `json:"session_id"`
+	MemberIndex   group.MemberIndex `json:"member_index"`
+	AttemptNumber uint              `json:"attempt_number"`
+	TimingVersion uint              `json:"timing_version"`
+	// StartBlock is the block at which the attempt started, including
+	// the announcement phase.
+	StartBlock uint64 `json:"start_block"`
+	// ProtocolStartBlock is the block at which the announcement phase ended
+	// and the actual protocol started.
+	ProtocolStartBlock uint64 `json:"protocol_start_block"`
+	// ProtocolEndBlock is the block at which the member completed or failed
+	// the actual protocol. Zero if the member did not participate.
+	ProtocolEndBlock uint64 `json:"protocol_end_block,omitempty"`
+	// DoneBlock is the block at which the slowest signer completed
+	// the signing, as agreed in the signing done check. Set only for
+	// succeeded signing attempts.
+	DoneBlock    uint64 `json:"done_block,omitempty"`
+	TimeoutBlock uint64 `json:"timeout_block"`
+	// Participated is true if the member was selected for the attempt.
+	Participated bool   `json:"participated"`
+	Outcome      string `json:"outcome"`
+}
+
+// JournalFault is a coordination fault recorded in the audit journal.
+type JournalFault struct {
+	Type    string `json:"type"`
+	Culprit string `json:"culprit"`
+}
+
+// JournalQuery holds the criteria of an audit journal query. Zero values
+// of all fields match all entries.
+type JournalQuery struct {
+	// WalletPublicKeyHash restricts the result to entries of the given
+	// wallet.
+	WalletPublicKeyHash *[20]byte
+	// FromBlock restricts the result to entries recorded at or after the
+	// given block.
+	FromBlock uint64
+	// ToBlock restricts the result to entries recorded at or before the
+	// given block.
+	ToBlock uint64
+	// ActionType restricts the result to entries of the given wallet action.
+	ActionType *WalletActionType
+}
+
+// matches returns true if the given entry matches the query.
+func (jq *JournalQuery) matches(entry *JournalEntry) bool {
+	if jq.WalletPublicKeyHash != nil &&
+		entry.WalletPublicKeyHash != formatWalletPublicKeyHash(*jq.WalletPublicKeyHash) {
+		return false
+	}
+
+	if entry.Block < jq.FromBlock {
+		return false
+	}
+
+	if jq.ToBlock != 0 && entry.Block > jq.ToBlock {
+		return false
+	}
+
+	if jq.ActionType != nil && entry.ActionType != jq.ActionType.String() {
+		return false
+	}
+
+	return true
+}
+
+// auditJournal is a durable, append-only journal recording coordination
+// windows, coordination outcomes and wallet actions seen by the node. It
+// allows reconstructing the node's view long after the facts, e.g. when
+// an inactivity claim is filed against the operator. Entries are persisted
+// as JSON lines in segment files of the work persistence.
+type auditJournal struct {
+	mutex sync.Mutex
+
+	handle         persistence.BasicHandle
+	currentBlockFn func() (uint64, error)
+
+	// nextSequence is the sequence number of the next entry.
+	nextSequence uint64
+	// segment holds the content of the current segment file.
+	segment []byte
+}
+
+// newAuditJournal creates a new audit journal backed by the given
+// persistence handle. Entries persisted previously are preserved and new
+// entries are appended after them.
+func newAuditJournal(
+	handle persistence.BasicHandle,
+	currentBlockFn func() (uint64, error),
+) (*auditJournal, error) {
+	journal := &auditJournal{
+		handle:         handle,
+		currentBlockFn: currentBlockFn,
+	}
+
+	segments, err := journal.readSegments()
+	if err != nil {
+		return nil, fmt.Errorf("cannot read journal segments: [%v]", err)
+	}
+
+	if len(segments) == 0 {
+		return journal, nil
+	}
+
+	lastSegmentIndex := uint64(0)
+	for index := range segments {
+		if index > lastSegmentIndex {
+			lastSegmentIndex = index
+		}
+	}
+
+	lastSegment := segments[lastSegmentIndex]
+	entries := decodeJournalSegment(lastSegment)
+
+	journal.nextSequence = lastSegmentIndex * journalSegmentLength
+	if len(entries) > 0 {
+		journal.nextSequence = entries[len(entries)-1].Sequence + 1
+	}
+
+	// Continue the last segment unless it is full. A trailing line torn by
+	// a crash is dropped so new entries are not appended to it.
+	if journal.nextSequence%journalSegmentLength != 0 {
+		journal.segment = lastSegment[:bytes.LastIndexByte(lastSegment, '\n')+1]
+	}
+
+	return journal, nil
+}
+
+// record appends the given entry to the journal. The sequence number, time
+// and block of the entry are set by the journal. Errors are logged and not
+// returned as journaling must never disrupt the recorded activities.
+func (aj *auditJournal) record(entry *JournalEntry) {
+	if aj == nil {
+		return
+	}
+
+	block, err := aj.currentBlockFn()
+	if err != nil {
+		logger.Warnf("cannot get current block for journal entry: [%v]", err)
+	}
+
+	aj.mutex.Lock()
+	defer aj.mutex.Unlock()
+
+	entry.Sequence = aj.nextSequence
+	entry.Time = time.Now()
+	entry.Block = block
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		logger.Errorf("cannot encode journal entry: [%v]", err)
+		return
+	}
+
+	if entry.Sequence%journalSegmentLength == 0 {
+		aj.segment = nil
+	}
+
+	segment := append(append(aj.segment, line...), '\n')
+
+	err = aj.handle.Save(
+		segment,
+		journalDirectory,
+		journalSegmentName(entry.Sequence/journalSegmentLength),
+	)
+	if err != nil {
+		logger.Errorf("cannot persist journal entry: [%v]", err)
+		return
+	}
+
+	aj.segment = segment
+	aj.nextSequence++
+}
+
+// recordCoordinationWindow records the start of the given coordination
+// window.
+func (aj *auditJournal) recordCoordinationWindow(window *coordinationWindow) {
+	aj.record(&JournalEntry{
+		Type:              JournalCoordinationWindow,
+		WindowIndex:       window.index(),
+		CoordinationBlock: window.coordinationBlock,
+	})
+}
+
+// recordWalletCoordination records the outcome of the coordination
+// procedure of the given wallet. The result can be partial or nil if the
+// procedure failed.
+func (aj *auditJournal) recordWalletCoordination(
+	window *coordinationWindow,
+	walletPublicKeyHash [20]byte,
+	result *coordinationResult,
+	coordinationErr error,
+) {
+	entry := &JournalEntry{
+		Type:                JournalWalletCoordination,
+		WindowIndex:         window.index(),
+		CoordinationBlock:   window.coordinationBlock,
+		WalletPublicKeyHash: formatWalletPublicKeyHash(walletPublicKeyHash),
+	}
+
+	if result != nil {
+		entry.Leader = result.leader.String()
+
+		if result.proposal != nil {
+			entry.ActionType = result.proposal.ActionType().String()
+
+			proposal, err := result.proposal.Marshal()
+			if err != nil {
+				logger.Warnf("cannot encode proposal for journal: [%v]", err)
+			}
+			entry.Proposal = proposal
+		}
+
+		for _, fault := range result.faults {
+			entry.Faults = append(entry.Faults, JournalFault{
+				Type:    fault.faultType.String(),
+				Culprit: fault.culprit.String(),
+			})
+		}
+	}
+
+	if coordinationErr != nil {
+		entry.Error = coordinationErr.Error()
+	}
+
+	aj.record(entry)
+}
+
+// recordShadowCoordination records the outcome of the coordination
+// procedure of the given wallet executed in the shadow mode.
+func (aj *auditJournal) recordShadowCoordination(
+	walletPublicKeyHash [20]byte,
+	result *shadowCoordinationResult,
+) {
+	entry := &JournalEntry{
+		Type:                JournalShadowCoordination,
+		WindowIndex:         result.window.index(),
+		CoordinationBlock:   result.window.coordinationBlock,
+		WalletPublicKeyHash: formatWalletPublicKeyHash(walletPublicKeyHash),
+		Leader:              result.leader.String(),
+		ProposalsMatch:      result.proposalsMatch(),
+	}
+
+	for _, actionType := range result.actionsChecklist {
+		entry.ActionsChecklist = append(
+			entry.ActionsChecklist,
+			actionType.String(),
+		)
+	}
+
+	encodeProposal := func(proposal CoordinationProposal) []byte {
+		payload, err := proposal.Marshal()
+		if err != nil {
+			logger.Warnf("cannot encode proposal for journal: [%v]", err)
+		}
+		return payload
+	}
+
+	if result.receivedProposal != nil {
+		entry.ActionType = result.receivedProposal.ActionType().String()
+		entry.Proposal = encodeProposal(result.receivedProposal)
+	}
+
+	if result.localProposal != nil {
+		entry.LocalActionType = result.localProposal.ActionType().String()
+		entry.LocalProposal = encodeProposal(result.localProposal)
+	}
+
+	for _, fault := range result.faults {
+		entry.Faults = append(entry.Faults, JournalFault{
+			Type:    fault.faultType.String(),
+			Culprit: fault.culprit.String(),
+		})
+	}
+
+	var errs []string
+	for _, err := range []struct {
+		description string
+		err         error
+	}{
+		{"local proposal generation failed", result.localProposalErr},
+		{"proposal not received", result.receivedProposalErr},
+		{"received proposal invalid", result.validationErr},
+	} {
+		if err.err != nil {
+			errs = append(errs, fmt.Sprintf("%s: [%v]", err.description, err.err))
+		}
+	}
+	entry.Error = strings.Join(errs, "; ")
+
+	aj.record(entry)
+}
+
+// recordWalletActionStarted records the start of the given wallet action.
+func (aj *auditJournal) recordWalletActionStarted(
+	walletPublicKeyHash [20]byte,
+	actionType WalletActionType,
+) {
+	aj.record(&JournalEntry{
+		Type:                JournalWalletActionStarted,
+		WalletPublicKeyHash: formatWalletPublicKeyHash(walletPublicKeyHash),
+		ActionType:          actionType.String(),
+	})
+}
+
+// recordWalletActionFinished records the end of the given wallet action.
+// The transaction hash is nil if the action did not broadcast any Bitcoin
+// transaction.
+func (aj *auditJournal) recordWalletActionFinished(
+	walletPublicKeyHash [20]byte,
+	actionType WalletActionType,
+	transactionHash *bitcoin.Hash,
+	actionErr error,
+) {
+	entry := &JournalEntry{
+		Type:                JournalWalletActionFinished,
+		WalletPublicKeyHash: formatWalletPublicKeyHash(walletPublicKeyHash),
+		ActionType:          actionType.String(),
+	}
+
+	if transactionHash != nil {
+		entry.TransactionHash = transactionHash.Hex(bitcoin.ReversedByteOrder)
+	}
+
+	if actionErr != nil {
+		entry.Error = actionErr.Error()
+	}
+
+	aj.record(entry)
+}
+
+// recordAttempt records the outcome and phase timings of the given signing
+// or DKG attempt. The wallet public key hash is nil for DKG attempts.
+func (aj *auditJournal) recordAttempt(
+	walletPublicKeyHash *[20]byte,
+	attempt *JournalAttemptTiming,
+	attemptErr error,
+) {
+	entry := &JournalEntry{
+		Type:    JournalAttempt,
+		Attempt: attempt,
+	}
+
+	if walletPublicKeyHash != nil {
+		entry.WalletPublicKeyHash = formatWalletPublicKeyHash(*walletPublicKeyHash)
+	}
+
+	if attemptErr != nil {
+		entry.Error = attemptErr.Error()
+	}
+
+	aj.record(entry)
+}
+
+// query returns all journal entries matching the given query, ordered by
+// their sequence numbers.
+func (aj *auditJournal) query(query JournalQuery) ([]*JournalEntry, error) {
+	segments, err := aj.readSegments()
+	if err != nil {
+		return nil, fmt.Errorf("cannot read journal segments: [%v]", err)
+	}
+
+	result := make([]*JournalEntry, 0)
+	for _, segment := range segments {
+		for _, entry := range decodeJournalSegment(segment) {
+			if query.matches(entry) {
+				result = append(result, entry)
+			}
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Sequence < result[j].Sequence
+	})
+
+	return result, nil
+}
+
+// QueryAuditJournal returns all entries of the audit journal persisted in
+// the given work persistence handle that match the given query. The
+// journal can be queried while the client is not running.
+func QueryAuditJournal(
+	handle persistence.BasicHandle,
+	query JournalQuery,
+) ([]*JournalEntry, error) {
+	return (&auditJournal{handle: handle}).query(query)
+}
+
+// readSegments reads all persisted journal segments, keyed by their
+// indexes.
+func (aj *auditJournal) readSegments() (map[uint64][]byte, error) {
+	segments := make(map[uint64][]byte)
+
+	descriptorsChan, errorsChan := aj.handle.ReadAll()
+
+	var errs []error
+
+	// Channels are not buffered so descriptors and errors must be read
+	// concurrently.
+	var wg sync.WaitGroup
+	wg.Add(2)
+
+	go func() {
+		defer wg.Done()
+
+		for descriptor := range descriptorsChan {
+			if descriptor.Directory() != journalDirectory ||
+				!strings.HasPrefix(descriptor.Name(), journalSegmentPrefix) {
+				continue
+			}
+
+			index, err := strconv.ParseUint(
+				strings.TrimPrefix(descriptor.Name(), journalSegmentPrefix),
+				10,
+				64,
+			)
+			if err != nil {
+				errs = append(errs, fmt.Errorf(
+					"invalid segment file name [%s]: [%v]",
+					descriptor.Name(),
+					err,
+				))
+				continue
+			}
+
+			content, err := descriptor.Content()
+			if err != nil {
+				errs = append(errs, fmt.Errorf(
+					"cannot read segment file [%s]: [%v]",
+					descriptor.Name(),
+					err,
+				))
+				continue
+			}
+
+			segments[index] = content
+		}
+	}()
+
+	go func() {
+		defer wg.Done()
+
+		for err := range errorsChan {
+			logger.Errorf("cannot read persisted work data: [%v]", err)
+		}
+	}()
+
+	wg.Wait()
+
+	if len(errs) > 0 {
+		return nil, errs[0]
+	}
+
+	return segments, nil
+}
+
+// decodeJournalSegment decodes entries held by the given segment. Lines
+// that cannot be decoded, e.g. a line torn by a crash, are skipped.
+func decodeJournalSegment(segment []byte) []*JournalEntry {
+	entries := make([]*JournalEntry, 0)
+
+	scanner := bufio.NewScanner(bytes.NewReader(segment))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		entry := &JournalEntry{}
+		if err := json.Unmarshal(line, entry); err != nil {
+			logger.Warnf("skipping malformed journal entry: [%v]", err)
+			continue
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries
+}
+
+// journalSegmentName returns the name of the segment file with the given
+// index. Indexes are zero-padded so segment files sort naturally.
+func journalSegmentName(index uint64) string {
+	return fmt.Sprintf("%s%010d", journalSegmentPrefix, index)
+}
+
+// formatWalletPublicKeyHash formats the given wallet public key hash the
+// same way as the coordination window metrics do.
+func formatWalletPublicKeyHash(walletPublicKeyHash [20]byte) string {
+	return "0x" + hex.EncodeToString(walletPublicKeyHash[:])
+}
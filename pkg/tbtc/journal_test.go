@@ -0,0 +1,324 @@
+package tbtc
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/keep-network/keep-common/pkg/persistence"
+
+	"github.com/keep-network/keep-core/internal/testutils"
+	"github.com/keep-network/keep-core/pkg/bitcoin"
+	"github.com/keep-network/keep-core/pkg/chain"
+)
+
+func TestAuditJournal_RecordAndReload(t *testing.T) {
+	handle := newJournalPersistenceHandle()
+
+	currentBlock := uint64(100)
+	currentBlockFn := func() (uint64, error) { return currentBlock, nil }
+
+	journal, err := newAuditJournal(handle, currentBlockFn)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	walletPublicKeyHash := [20]byte{0x01}
+	window := newCoordinationWindow(900)
+
+	journal.recordCoordinationWindow(window)
+	journal.recordWalletCoordination(
+		window,
+		walletPublicKeyHash,
+		&coordinationResult{
+			leader: chain.Address("leader"),
+			proposal: &HeartbeatProposal{
+				Message: [16]byte{0xff, 0xff, 0xff, 0xff, 0x01},
+			},
+			faults: []*coordinationFault{
+				{
+					culprit:   chain.Address("culprit"),
+					faultType: FaultLeaderIdleness,
+				},
+			},
+		},
+		nil,
+	)
+
+	// Simulate a restart of the client.
+	currentBlock = 200
+	journal, err = newAuditJournal(handle, currentBlockFn)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	transactionHash := bitcoin.Hash{0x02}
+	journal.recordWalletActionStarted(walletPublicKeyHash, ActionDepositSweep)
+	journal.recordWalletActionFinished(
+		walletPublicKeyHash,
+		ActionDepositSweep,
+		&transactionHash,
+		fmt.Errorf("unexpected error"),
+	)
+
+	entries, err := journal.query(JournalQuery{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertIntsEqual(t, "entries count", 4, len(entries))
+
+	expectedTypes := []JournalEntryType{
+		JournalCoordinationWindow,
+		JournalWalletCoordination,
+		JournalWalletActionStarted,
+		JournalWalletActionFinished,
+	}
+	expectedBlocks := []uint64{100, 100, 200, 200}
+	for i, entry := range entries {
+		testutils.AssertIntsEqual(
+			t,
+			fmt.Sprintf("sequence of entry [%d]", i),
+			i,
+			int(entry.Sequence),
+		)
+		testutils.AssertStringsEqual(
+			t,
+			fmt.Sprintf("type of entry [%d]", i),
+			string(expectedTypes[i]),
+			string(entry.Type),
+		)
+		testutils.AssertIntsEqual(
+			t,
+			fmt.Sprintf("block of entry [%d]", i),
+			int(expectedBlocks[i]),
+			int(entry.Block),
+		)
+	}
+
+	coordinationEntry := entries[1]
+	testutils.AssertIntsEqual(t, "window index", 1, int(coordinationEntry.WindowIndex))
+	testutils.AssertStringsEqual(t, "leader", "leader", coordinationEntry.Leader)
+	testutils.AssertStringsEqual(
+		t,
+		"action type",
+		ActionHeartbeat.String(),
+		coordinationEntry.ActionType,
+	)
+	expectedFaults := []JournalFault{
+		{Type: FaultLeaderIdleness.String(), Culprit: "culprit"},
+	}
+	if !reflect.DeepEqual(expectedFaults, coordinationEntry.Faults) {
+		t.Errorf(
+			"unexpected faults\nexpected: %v\nactual:   %v",
+			expectedFaults,
+			coordinationEntry.Faults,
+		)
+	}
+
+	proposal, err := coordinationEntry.DecodeProposal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedProposal := &HeartbeatProposal{
+		Message: [16]byte{0xff, 0xff, 0xff, 0xff, 0x01},
+	}
+	if !reflect.DeepEqual(expectedProposal, proposal) {
+		t.Errorf(
+			"unexpected proposal\nexpected: %v\nactual:   %v",
+			expectedProposal,
+			proposal,
+		)
+	}
+
+	finishedEntry := entries[3]
+	testutils.AssertStringsEqual(
+		t,
+		"transaction hash",
+		transactionHash.Hex(bitcoin.ReversedByteOrder),
+		finishedEntry.TransactionHash,
+	)
+	testutils.AssertStringsEqual(
+		t,
+		"error",
+		"unexpected error",
+		finishedEntry.Error,
+	)
+}
+
+func TestAuditJournal_SegmentRollover(t *testing.T) {
+	handle := newJournalPersistenceHandle()
+	currentBlockFn := func() (uint64, error) { return 0, nil }
+
+	journal, err := newAuditJournal(handle, currentBlockFn)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < journalSegmentLength+1; i++ {
+		journal.recordCoordinationWindow(newCoordinationWindow(900))
+	}
+
+	testutils.AssertIntsEqual(t, "segments count", 2, len(handle.files))
+
+	// Simulate a restart of the client and a torn write of the last line.
+	lastSegment := journalDirectory + "/" + journalSegmentName(1)
+	handle.files[lastSegment] = append(handle.files[lastSegment], []byte("{\"seq")...)
+
+	journal, err = newAuditJournal(handle, currentBlockFn)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	journal.recordCoordinationWindow(newCoordinationWindow(1800))
+
+	entries, err := QueryAuditJournal(handle, JournalQuery{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertIntsEqual(
+		t,
+		"entries count",
+		journalSegmentLength+2,
+		len(entries),
+	)
+	for i, entry := range entries {
+		if entry.Sequence != uint64(i) {
+			t.Fatalf(
+				"unexpected sequence of entry [%d]: [%d]",
+				i,
+				entry.Sequence,
+			)
+		}
+	}
+}
+
+func TestAuditJournal_Query(t *testing.T) {
+	handle := newJournalPersistenceHandle()
+
+	currentBlock := uint64(0)
+	journal, err := newAuditJournal(
+		handle,
+		func() (uint64, error) { return currentBlock, nil },
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wallet1 := [20]byte{0x01}
+	wallet2 := [20]byte{0x02}
+
+	currentBlock = 100
+	journal.recordWalletActionStarted(wallet1, ActionDepositSweep)
+	currentBlock = 200
+	journal.recordWalletActionStarted(wallet2, ActionRedemption)
+	currentBlock = 300
+	journal.recordWalletActionStarted(wallet1, ActionRedemption)
+
+	redemption := ActionRedemption
+
+	var tests = map[string]struct {
+		query             JournalQuery
+		expectedSequences []uint64
+	}{
+		"no criteria": {
+			query:             JournalQuery{},
+			expectedSequences: []uint64{0, 1, 2},
+		},
+		"by wallet": {
+			query:             JournalQuery{WalletPublicKeyHash: &wallet1},
+			expectedSequences: []uint64{0, 2},
+		},
+		"by block range": {
+			query:             JournalQuery{FromBlock: 150, ToBlock: 300},
+			expectedSequences: []uint64{1, 2},
+		},
+		"by open block range": {
+			query:             JournalQuery{FromBlock: 250},
+			expectedSequences: []uint64{2},
+		},
+		"by action type": {
+			query:             JournalQuery{ActionType: &redemption},
+			expectedSequences: []uint64{1, 2},
+		},
+		"by all criteria": {
+			query: JournalQuery{
+				WalletPublicKeyHash: &wallet1,
+				ToBlock:             200,
+				ActionType:          &redemption,
+			},
+			expectedSequences: []uint64{},
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			entries, err := journal.query(test.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			sequences := make([]uint64, 0)
+			for _, entry := range entries {
+				sequences = append(sequences, entry.Sequence)
+			}
+
+			if !reflect.DeepEqual(test.expectedSequences, sequences) {
+				t.Errorf(
+					"unexpected sequences\nexpected: %v\nactual:   %v",
+					test.expectedSequences,
+					sequences,
+				)
+			}
+		})
+	}
+}
+
+// journalPersistenceHandle is a persistence handle holding files in a map
+// so that saving a file again replaces its content.
+type journalPersistenceHandle struct {
+	files map[string][]byte
+}
+
+func newJournalPersistenceHandle() *journalPersistenceHandle {
+	return &journalPersistenceHandle{files: make(map[string][]byte)}
+}
+
+func (jph *journalPersistenceHandle) Save(
+	data []byte,
+	directory string,
+	name string,
+) error {
+	jph.files[directory+"/"+name] = append([]byte{}, data...)
+	return nil
+}
+
+func (jph *journalPersistenceHandle) Delete(directory string, name string) error {
+	delete(jph.files, directory+"/"+name)
+	return nil
+}
+
+func (jph *journalPersistenceHandle) ReadAll() (
+	<-chan persistence.DataDescriptor,
+	<-chan error,
+) {
+	outputData := make(chan persistence.DataDescriptor, len(jph.files))
+	outputErrors := make(chan error)
+
+	for path, content := range jph.files {
+		parts := strings.SplitN(path, "/", 2)
+
+		outputData <- &mockDescriptor{
+			name:      parts[1],
+			directory: parts[0],
+			content:   content,
+		}
+	}
+
+	close(outputData)
+	close(outputErrors)
+
+	return outputData, outputErrors
+}
@@ -87,20 +87,40 @@ func (s *signer) Unmarshal(bytes []byte) error {
 	return nil
 }
 
-// Marshal converts the signingDoneMessage to a byte array.
+// Marshal converts the signingDoneMessage to a byte array. A single signed
+// message and its signature are put into the dedicated single-value fields
+// to keep the message compatible with clients not supporting signing batches.
 func (sdm *signingDoneMessage) Marshal() ([]byte, error) {
-	signatureBytes, err := sdm.signature.Marshal()
-	if err != nil {
-		return nil, err
+	signaturesBytes := make([][]byte, len(sdm.signatures))
+	for i, signature := range sdm.signatures {
+		signatureBytes, err := signature.Marshal()
+		if err != nil {
+			return nil, err
+		}
+
+		signaturesBytes[i] = signatureBytes
+	}
+
+	messagesBytes := make([][]byte, len(sdm.messages))
+	for i, message := range sdm.messages {
+		messagesBytes[i] = message.Bytes()
 	}
 
-	return proto.Marshal(&pb.SigningDoneMessage{
+	pbMsg := &pb.SigningDoneMessage{
 		SenderID:      uint32(sdm.senderID),
-		Message:       sdm.message.Bytes(),
 		AttemptNumber: sdm.attemptNumber,
-		Signature:     signatureBytes,
 		EndBlock:      sdm.endBlock,
-	})
+	}
+
+	if len(messagesBytes) == 1 && len(signaturesBytes) == 1 {
+		pbMsg.Message = messagesBytes[0]
+		pbMsg.Signature = signaturesBytes[0]
+	} else {
+		pbMsg.Messages = messagesBytes
+		pbMsg.Signatures = signaturesBytes
+	}
+
+	return proto.Marshal(pbMsg)
 }
 
 // Unmarshal converts a byte array back to the signingDoneMessage.
@@ -114,15 +134,40 @@ func (sdm *signingDoneMessage) Unmarshal(bytes []byte) error {
 		return err
 	}
 
-	signature := &tecdsa.Signature{}
-	if err := signature.Unmarshal(pbMsg.Signature); err != nil {
-		return fmt.Errorf("cannot unmarshal signature: [%v]", err)
+	messagesBytes := pbMsg.Messages
+	signaturesBytes := pbMsg.Signatures
+	if len(messagesBytes) == 0 && len(signaturesBytes) == 0 {
+		messagesBytes = [][]byte{pbMsg.Message}
+		signaturesBytes = [][]byte{pbMsg.Signature}
+	}
+
+	if len(messagesBytes) != len(signaturesBytes) {
+		return fmt.Errorf(
+			"messages count [%v] does not match signatures count [%v]",
+			len(messagesBytes),
+			len(signaturesBytes),
+		)
+	}
+
+	messages := make([]*big.Int, len(messagesBytes))
+	for i, messageBytes := range messagesBytes {
+		messages[i] = new(big.Int).SetBytes(messageBytes)
+	}
+
+	signatures := make([]*tecdsa.Signature, len(signaturesBytes))
+	for i, signatureBytes := range signaturesBytes {
+		signature := &tecdsa.Signature{}
+		if err := signature.Unmarshal(signatureBytes); err != nil {
+			return fmt.Errorf("cannot unmarshal signature: [%v]", err)
+		}
+
+		signatures[i] = signature
 	}
 
 	sdm.senderID = group.MemberIndex(pbMsg.SenderID)
-	sdm.message = new(big.Int).SetBytes(pbMsg.Message)
+	sdm.messages = messages
 	sdm.attemptNumber = pbMsg.AttemptNumber
-	sdm.signature = signature
+	sdm.signatures = signatures
 	sdm.endBlock = pbMsg.EndBlock
 
 	return nil
@@ -232,6 +277,7 @@ func unmarshalCoordinationProposal(actionType uint32, payload []byte) (
 		ActionRedemption:      &RedemptionProposal{},
 		ActionMovingFunds:     &MovingFundsProposal{},
 		ActionMovedFundsSweep: &MovedFundsSweepProposal{},
+		ActionFeeBump:         &FeeBumpProposal{},
 	}[parsedActionType]
 	if !ok {
 		return nil, fmt.Errorf(
@@ -306,11 +352,20 @@ func (dsp *DepositSweepProposal) Marshal() ([]byte, error) {
 		depositsRevealBlocks[i] = block.Uint64()
 	}
 
+	// The parent transaction hash is set only for child-pays-for-parent
+	// sweeps. Leave it empty otherwise so regular proposals are encoded
+	// the same way as before.
+	var parentTxHash []byte
+	if dsp.ParentTxHash != (bitcoin.Hash{}) {
+		parentTxHash = append([]byte{}, dsp.ParentTxHash[:]...)
+	}
+
 	return proto.Marshal(
 		&pb.DepositSweepProposal{
 			DepositsKeys:         depositsKeys,
 			SweepTxFee:           dsp.SweepTxFee.Bytes(),
 			DepositsRevealBlocks: depositsRevealBlocks,
+			ParentTxHash:         parentTxHash,
 		},
 	)
 }
@@ -355,9 +410,26 @@ func (dsp *DepositSweepProposal) Unmarshal(bytes []byte) error {
 		depositsRevealBlocks[i] = big.NewInt(int64(block))
 	}
 
+	var parentTxHash bitcoin.Hash
+	if len(pbMsg.ParentTxHash) > 0 {
+		hash, err := bitcoin.NewHash(
+			pbMsg.ParentTxHash,
+			bitcoin.InternalByteOrder,
+		)
+		if err != nil {
+			return fmt.Errorf(
+				"failed to unmarshal parent tx hash: [%v]",
+				err,
+			)
+		}
+
+		parentTxHash = hash
+	}
+
 	dsp.DepositsKeys = depositsKeys
 	dsp.SweepTxFee = new(big.Int).SetBytes(pbMsg.SweepTxFee)
 	dsp.DepositsRevealBlocks = depositsRevealBlocks
+	dsp.ParentTxHash = parentTxHash
 
 	return nil
 }
@@ -463,6 +535,35 @@ func (mfsp *MovedFundsSweepProposal) Unmarshal(data []byte) error {
 	return nil
 }
 
+// Marshal converts the feeBumpProposal to a byte array.
+func (fbp *FeeBumpProposal) Marshal() ([]byte, error) {
+	return proto.Marshal(
+		&pb.FeeBumpProposal{
+			TransactionHash: fbp.TransactionHash[:],
+			TxFee:           fbp.TxFee.Bytes(),
+		})
+}
+
+// Unmarshal converts a byte array back to the feeBumpProposal.
+func (fbp *FeeBumpProposal) Unmarshal(data []byte) error {
+	pbMsg := pb.FeeBumpProposal{}
+	if err := proto.Unmarshal(data, &pbMsg); err != nil {
+		return fmt.Errorf("failed to unmarshal FeeBumpProposal: [%v]", err)
+	}
+
+	if len(pbMsg.TransactionHash) != 32 {
+		return fmt.Errorf(
+			"invalid transaction hash length: [%v]",
+			len(pbMsg.TransactionHash),
+		)
+	}
+
+	copy(fbp.TransactionHash[:], pbMsg.TransactionHash)
+	fbp.TxFee = new(big.Int).SetBytes(pbMsg.TxFee)
+
+	return nil
+}
+
 // marshalPublicKey converts an ECDSA public key to a byte
 // array (uncompressed).
 func marshalPublicKey(publicKey *ecdsa.PublicKey) ([]byte, error) {
@@ -11,10 +11,12 @@ import (
 	"github.com/keep-network/keep-core/pkg/bitcoin"
 
 	fuzz "github.com/google/gofuzz"
+	"google.golang.org/protobuf/proto"
 
 	"github.com/keep-network/keep-core/internal/testutils"
 	"github.com/keep-network/keep-core/pkg/internal/pbutils"
 	"github.com/keep-network/keep-core/pkg/protocol/group"
+	"github.com/keep-network/keep-core/pkg/tbtc/gen/pb"
 	"github.com/keep-network/keep-core/pkg/tecdsa"
 )
 
@@ -49,27 +51,97 @@ func TestSignerMarshalling_NonTECDSAKey(t *testing.T) {
 }
 
 func TestSigningDoneMessage_MarshalingRoundtrip(t *testing.T) {
+	var tests = map[string]struct {
+		messages   []*big.Int
+		signatures []*tecdsa.Signature
+	}{
+		"single message": {
+			messages: []*big.Int{big.NewInt(100)},
+			signatures: []*tecdsa.Signature{
+				{
+					R:          big.NewInt(200),
+					S:          big.NewInt(300),
+					RecoveryID: 3,
+				},
+			},
+		},
+		"batch of messages": {
+			messages: []*big.Int{big.NewInt(100), big.NewInt(101)},
+			signatures: []*tecdsa.Signature{
+				{
+					R:          big.NewInt(200),
+					S:          big.NewInt(300),
+					RecoveryID: 3,
+				},
+				{
+					R:          big.NewInt(201),
+					S:          big.NewInt(301),
+					RecoveryID: 1,
+				},
+			},
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			msg := &signingDoneMessage{
+				senderID:      group.MemberIndex(10),
+				messages:      test.messages,
+				attemptNumber: 2,
+				signatures:    test.signatures,
+				endBlock:      4500,
+			}
+			unmarshaled := &signingDoneMessage{}
+
+			err := pbutils.RoundTrip(msg, unmarshaled)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(msg, unmarshaled) {
+				t.Fatalf("unexpected content of unmarshaled message")
+			}
+		})
+	}
+}
+
+// TestSigningDoneMessage_SingleMessageCompatibility ensures a single signed
+// message is marshaled the same way as by clients not supporting signing
+// batches.
+func TestSigningDoneMessage_SingleMessageCompatibility(t *testing.T) {
+	signature := &tecdsa.Signature{
+		R:          big.NewInt(200),
+		S:          big.NewInt(300),
+		RecoveryID: 3,
+	}
+
 	msg := &signingDoneMessage{
 		senderID:      group.MemberIndex(10),
-		message:       big.NewInt(100),
+		messages:      []*big.Int{big.NewInt(100)},
 		attemptNumber: 2,
-		signature: &tecdsa.Signature{
-			R:          big.NewInt(200),
-			S:          big.NewInt(300),
-			RecoveryID: 3,
-		},
-		endBlock: 4500,
+		signatures:    []*tecdsa.Signature{signature},
+		endBlock:      4500,
 	}
-	unmarshaled := &signingDoneMessage{}
 
-	err := pbutils.RoundTrip(msg, unmarshaled)
+	bytes, err := msg.Marshal()
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	if !reflect.DeepEqual(msg, unmarshaled) {
-		t.Fatalf("unexpected content of unmarshaled message")
+	pbMsg := &pb.SigningDoneMessage{}
+	if err := proto.Unmarshal(bytes, pbMsg); err != nil {
+		t.Fatal(err)
+	}
+
+	signatureBytes, err := signature.Marshal()
+	if err != nil {
+		t.Fatal(err)
 	}
+
+	testutils.AssertBytesEqual(t, big.NewInt(100).Bytes(), pbMsg.Message)
+	testutils.AssertBytesEqual(t, signatureBytes, pbMsg.Signature)
+	testutils.AssertIntsEqual(t, "messages count", 0, len(pbMsg.Messages))
+	testutils.AssertIntsEqual(t, "signatures count", 0, len(pbMsg.Signatures))
 }
 
 func TestFuzzSigningDoneMessage_MarshalingRoundtrip(t *testing.T) {
@@ -94,9 +166,9 @@ func TestFuzzSigningDoneMessage_MarshalingRoundtrip(t *testing.T) {
 
 		doneMessage := &signingDoneMessage{
 			senderID:      senderID,
-			message:       &message,
+			messages:      []*big.Int{&message},
 			attemptNumber: attemptNumber,
-			signature:     &signature,
+			signatures:    []*tecdsa.Signature{&signature},
 			endBlock:      endBlock,
 		}
 
@@ -175,6 +247,24 @@ func TestCoordinationMessage_MarshalingRoundtrip(t *testing.T) {
 				},
 			},
 		},
+		"with child-pays-for-parent deposit sweep proposal": {
+			proposal: &DepositSweepProposal{
+				DepositsKeys: []struct {
+					FundingTxHash      bitcoin.Hash
+					FundingOutputIndex uint32
+				}{
+					{
+						FundingTxHash:      parseHash("709b55bd3da0f5a838125bd0ee20c5bfdd7caba173912d4281cae816b79a201b"),
+						FundingOutputIndex: 0,
+					},
+				},
+				SweepTxFee: big.NewInt(10000),
+				DepositsRevealBlocks: []*big.Int{
+					big.NewInt(100),
+				},
+				ParentTxHash: parseHash("27ca64c092a959c7edc525ed45e845b1de6a7590d173fd2fad9133c8a779a1e3"),
+			},
+		},
 		"with redemption proposal": {
 			proposal: &RedemptionProposal{
 				RedeemersOutputScripts: []bitcoin.Script{
@@ -200,6 +290,12 @@ func TestCoordinationMessage_MarshalingRoundtrip(t *testing.T) {
 				SweepTxFee:               big.NewInt(8000),
 			},
 		},
+		"with fee bump proposal": {
+			proposal: &FeeBumpProposal{
+				TransactionHash: parseHash("27ca64c092a959c7edc525ed45e845b1de6a7590d173fd2fad9133c8a779a1e3"),
+				TxFee:           big.NewInt(12000),
+			},
+		},
 	}
 
 	walletPublicKeyHash := toByte20("aa768412ceed10bd423c025542ca90071f9fb62d")
@@ -371,6 +467,35 @@ func TestFuzzCoordinationMessage_MarshalingRoundtrip_WithMovedFundsSweepProposal
 	}
 }
 
+func TestFuzzCoordinationMessage_MarshalingRoundtrip_WithFeeBumpProposal(t *testing.T) {
+	for i := 0; i < 10; i++ {
+		var (
+			senderID            group.MemberIndex
+			coordinationBlock   uint64
+			walletPublicKeyHash [20]byte
+			proposal            FeeBumpProposal
+		)
+
+		f := fuzz.New().NilChance(0.1).
+			NumElements(0, 512).
+			Funcs(pbutils.FuzzFuncs()...)
+
+		f.Fuzz(&senderID)
+		f.Fuzz(&coordinationBlock)
+		f.Fuzz(&walletPublicKeyHash)
+		f.Fuzz(&proposal)
+
+		coordinationMsg := &coordinationMessage{
+			senderID:            senderID,
+			coordinationBlock:   coordinationBlock,
+			walletPublicKeyHash: walletPublicKeyHash,
+			proposal:            &proposal,
+		}
+
+		_ = pbutils.RoundTrip(coordinationMsg, &coordinationMessage{})
+	}
+}
+
 func TestFuzzCoordinationMessage_MarshalingRoundtrip_WithNoopProposal(t *testing.T) {
 	for i := 0; i < 10; i++ {
 		var (
@@ -275,6 +275,12 @@ func (mfsa *movedFundsSweepAction) actionType() WalletActionType {
 	return ActionMovedFundsSweep
 }
 
+// broadcastedTransaction returns the hash of the transaction broadcasted
+// by the action, or nil if the action did not broadcast a transaction.
+func (mfsa *movedFundsSweepAction) broadcastedTransaction() *bitcoin.Hash {
+	return mfsa.transactionExecutor.broadcastedTransaction()
+}
+
 // ValidateMovedFundsSweepProposal checks the moved funds sweep proposal with
 // on-chain validation rules.
 func ValidateMovedFundsSweepProposal(
@@ -469,6 +469,12 @@ func (mfa *movingFundsAction) actionType() WalletActionType {
 	return ActionMovingFunds
 }
 
+// broadcastedTransaction returns the hash of the transaction broadcasted
+// by the action, or nil if the action did not broadcast a transaction.
+func (mfa *movingFundsAction) broadcastedTransaction() *bitcoin.Hash {
+	return mfa.transactionExecutor.broadcastedTransaction()
+}
+
 func isWalletPendingMovingFundsTarget(
 	walletPublicKeyHash [20]byte,
 	chain interface {
@@ -123,6 +123,23 @@ type node struct {
 
 	// windowMetricsTracker tracks detailed metrics for individual coordination windows
 	windowMetricsTracker *coordinationWindowMetrics
+
+	// transactionsTracker tracks unconfirmed transactions of wallets
+	// controlled by the node. It is shared by all coordination executors
+	// and fee bump actions.
+	transactionsTracker *unconfirmedTransactionsTracker
+
+	// journal is the audit journal recording coordination windows and
+	// wallet actions seen by the node.
+	journal *auditJournal
+
+	// shadowMode determines whether the node runs in the shadow mode.
+	// See Config.ShadowMode for details.
+	shadowMode bool
+
+	// signingCaptureRecorder captures signing protocol messages. It is nil
+	// if capturing is disabled. See Config.CaptureSigningMessages for details.
+	signingCaptureRecorder *signingCaptureRecorder
 }
 
 func newNode(
@@ -147,19 +164,36 @@ func newNode(
 	latch := generator.NewProtocolLatch()
 	scheduler.RegisterProtocol(latch)
 
+	blockCounter, err := chain.BlockCounter()
+	if err != nil {
+		return nil, fmt.Errorf("cannot get block counter: [%v]", err)
+	}
+
+	journal, err := newAuditJournal(workPersistence, blockCounter.CurrentBlock)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create audit journal: [%v]", err)
+	}
+
 	node := &node{
 		groupParameters:          groupParameters,
 		chain:                    chain,
 		btcChain:                 btcChain,
 		netProvider:              netProvider,
 		walletRegistry:           walletRegistry,
-		walletDispatcher:         newWalletDispatcher(),
+		walletDispatcher:         newWalletDispatcher(journal),
 		protocolLatch:            latch,
 		heartbeatFailureCounter:  newHeartbeatFailureCounter(),
 		signingExecutors:         make(map[string]*signingExecutor),
 		inactivityClaimExecutors: make(map[string]*inactivityClaimExecutor),
 		coordinationExecutors:    make(map[string]*coordinationExecutor),
 		proposalGenerator:        proposalGenerator,
+		transactionsTracker:      newUnconfirmedTransactionsTracker(btcChain),
+		journal:                  journal,
+		shadowMode:               config.ShadowMode,
+	}
+
+	if config.CaptureSigningMessages {
+		node.signingCaptureRecorder = newSigningCaptureRecorder(workPersistence)
 	}
 
 	// Archive any wallets that might have been closed or terminated while the
@@ -192,6 +226,7 @@ func newNode(
 		scheduler,
 		node.waitForBlockHeight,
 	)
+	node.dkgExecutor.journal = journal
 
 	return node, nil
 }
@@ -409,6 +444,9 @@ func (n *node) getSigningExecutor(
 		executor.setMetricsRecorder(n.performanceMetrics)
 	}
 
+	executor.signingCaptureRecorder = n.signingCaptureRecorder
+	executor.journal = n.journal
+
 	n.signingExecutors[executorKey] = executor
 
 	return executor, true, nil
@@ -507,7 +545,9 @@ func (n *node) getCoordinationExecutor(
 		membershipValidator,
 		n.protocolLatch,
 		n.waitForBlockHeight,
+		n.transactionsTracker,
 	)
+	executor.shadowMode = n.shadowMode
 
 	// Wire metrics recorder if available
 	if n.performanceMetrics != nil {
@@ -976,6 +1016,75 @@ func (n *node) handleMovedFundsSweepProposal(
 	walletActionLogger.Infof("wallet action dispatched successfully")
 }
 
+// handleFeeBumpProposal handles an incoming fee bump proposal by
+// orchestrating and dispatching an appropriate wallet action.
+func (n *node) handleFeeBumpProposal(
+	wallet wallet,
+	proposal *FeeBumpProposal,
+	startBlock uint64,
+	expiryBlock uint64,
+) {
+	walletPublicKeyBytes, err := marshalPublicKey(wallet.publicKey)
+	if err != nil {
+		logger.Errorf("cannot marshal wallet public key: [%v]", err)
+		return
+	}
+
+	signingExecutor, ok, err := n.getSigningExecutor(wallet.publicKey)
+	if err != nil {
+		logger.Errorf("cannot get signing executor: [%v]", err)
+		return
+	}
+	// This check is actually redundant. We know the node controls some
+	// wallet signers as we just got the wallet from the registry using their
+	// public key hash. However, we are doing it just in case. The API
+	// contract of getSigningExecutor may change one day.
+	if !ok {
+		logger.Infof(
+			"node does not control signers of wallet PKH [0x%x]; "+
+				"ignoring the received fee bump proposal",
+			walletPublicKeyBytes,
+		)
+		return
+	}
+
+	logger.Infof(
+		"starting orchestration of the fee bump action for wallet "+
+			"[0x%x]; 20-byte public key hash of that wallet is [0x%x]",
+		walletPublicKeyBytes,
+		bitcoin.PublicKeyHash(wallet.publicKey),
+	)
+
+	walletActionLogger := logger.With(
+		zap.String("wallet", fmt.Sprintf("0x%x", walletPublicKeyBytes)),
+		zap.String("action", ActionFeeBump.String()),
+		zap.Uint64("startBlock", startBlock),
+		zap.Uint64("expiryBlock", expiryBlock),
+	)
+	walletActionLogger.Infof("dispatching wallet action")
+
+	action := newFeeBumpAction(
+		walletActionLogger,
+		n.chain,
+		n.btcChain,
+		wallet,
+		signingExecutor,
+		n.transactionsTracker,
+		proposal,
+		startBlock,
+		expiryBlock,
+		n.waitForBlockHeight,
+	)
+
+	err = n.walletDispatcher.dispatch(action)
+	if err != nil {
+		walletActionLogger.Errorf("cannot dispatch wallet action: [%v]", err)
+		return
+	}
+
+	walletActionLogger.Infof("wallet action dispatched successfully")
+}
+
 // coordinationLayerSettings represents settings for the coordination layer.
 type coordinationLayerSettings struct {
 	// executeCoordinationProcedureFn is a function executing the coordination
@@ -1050,6 +1159,8 @@ func (n *node) runCoordinationLayer(
 		previousWindow = window
 		previousWindowMu.Unlock()
 
+		n.journal.recordCoordinationWindow(window)
+
 		// Fetch all wallets controlled by the node. It is important to
 		// get the wallets every time the window is triggered as the
 		// node may have started controlling a new wallet in the meantime.
@@ -1143,6 +1254,13 @@ func executeCoordinationProcedure(
 	result, err := executor.coordinate(window)
 	duration := time.Since(startTime)
 
+	node.journal.recordWalletCoordination(
+		window,
+		bitcoin.PublicKeyHash(walletPublicKey),
+		result,
+		err,
+	)
+
 	if err != nil {
 		procedureLogger.Errorf("coordination procedure failed: [%v]", err)
 		// Metrics are already recorded in executor.coordinate() for failures
@@ -1265,6 +1383,15 @@ func processCoordinationResult(node *node, result *coordinationResult) {
 				expiryBlock,
 			)
 		}
+	case ActionFeeBump:
+		if proposal, ok := result.proposal.(*FeeBumpProposal); ok {
+			node.handleFeeBumpProposal(
+				result.wallet,
+				proposal,
+				startBlock,
+				expiryBlock,
+			)
+		}
 	default:
 		logger.Errorf("no handler for coordination result [%s]", result)
 	}
@@ -385,6 +385,12 @@ func (ra *redemptionAction) actionType() WalletActionType {
 	return ActionRedemption
 }
 
+// broadcastedTransaction returns the hash of the transaction broadcasted
+// by the action, or nil if the action did not broadcast a transaction.
+func (ra *redemptionAction) broadcastedTransaction() *bitcoin.Hash {
+	return ra.transactionExecutor.broadcastedTransaction()
+}
+
 // redemptionFeeDistributionFn calculates the redemption transaction fee
 // distribution for the given redemption requests. The resulting list
 // contains the fee shares ordered in the same way as the input requests, i.e.
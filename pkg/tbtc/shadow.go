@@ -0,0 +1,363 @@
+package tbtc
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"time"
+
+	"github.com/ipfs/go-log/v2"
+	"go.uber.org/zap"
+	"golang.org/x/exp/slices"
+
+	"github.com/keep-network/keep-common/pkg/persistence"
+
+	"github.com/keep-network/keep-core/pkg/bitcoin"
+	"github.com/keep-network/keep-core/pkg/chain"
+)
+
+// EnsureShadowIdentity makes sure the node running in the shadow mode uses
+// an operator key different from the one of the production node whose
+// storage it follows. Both nodes would announce the same network identity
+// otherwise. Returns an error if the given operator is a member of any wallet
+// found in the given keystore.
+func EnsureShadowIdentity(
+	keyStorePersistence persistence.ProtectedHandle,
+	operatorAddress chain.Address,
+) error {
+	signersByWallet := newWalletStorage(keyStorePersistence).loadSigners()
+
+	for _, signers := range signersByWallet {
+		// All signers of the given wallet share the same wallet data.
+		wallet := signers[0].wallet
+
+		if len(wallet.membersByOperator(operatorAddress)) > 0 {
+			return fmt.Errorf(
+				"operator [%s] is a member of wallet [0x%x] found in the "+
+					"storage; the shadow mode must use an operator key "+
+					"different from the production node's one",
+				operatorAddress,
+				bitcoin.PublicKeyHash(wallet.publicKey),
+			)
+		}
+	}
+
+	return nil
+}
+
+// shadowCoordinationResult represents the result of the coordination
+// procedure executed in the shadow mode.
+type shadowCoordinationResult struct {
+	window           *coordinationWindow
+	leader           chain.Address
+	actionsChecklist []WalletActionType
+
+	// localProposal is the proposal generated locally on behalf of the
+	// leader. Nil if the generation failed; localProposalErr holds the
+	// reason then.
+	localProposal    CoordinationProposal
+	localProposalErr error
+
+	// receivedProposal is the proposal received from the leader. Nil if
+	// the proposal was not received; receivedProposalErr holds the reason
+	// then.
+	receivedProposal    CoordinationProposal
+	receivedProposalErr error
+	faults              []*coordinationFault
+
+	// validationErr is the error returned by the validation of the received
+	// proposal. Nil if the proposal is valid or was not received.
+	validationErr error
+}
+
+// proposalsMatch returns true if the locally generated proposal is the same
+// as the proposal received from the leader.
+func (scr *shadowCoordinationResult) proposalsMatch() bool {
+	if scr.localProposal == nil || scr.receivedProposal == nil {
+		return false
+	}
+
+	if scr.localProposal.ActionType() != scr.receivedProposal.ActionType() {
+		return false
+	}
+
+	localPayload, err := scr.localProposal.Marshal()
+	if err != nil {
+		return false
+	}
+
+	receivedPayload, err := scr.receivedProposal.Marshal()
+	if err != nil {
+		return false
+	}
+
+	return bytes.Equal(localPayload, receivedPayload)
+}
+
+func (scr *shadowCoordinationResult) String() string {
+	actionType := func(proposal CoordinationProposal) string {
+		if proposal == nil {
+			return "none"
+		}
+		return proposal.ActionType().String()
+	}
+
+	return fmt.Sprintf(
+		"leader [%s], local proposal [%s], received proposal [%s], "+
+			"proposals match [%v], faults [%v]",
+		scr.leader,
+		actionType(scr.localProposal),
+		actionType(scr.receivedProposal),
+		scr.proposalsMatch(),
+		scr.faults,
+	)
+}
+
+// shadow executes the coordination procedure for the given coordination
+// window in the shadow mode. The executor determines the leader and the
+// actions checklist, generates a proposal on behalf of the leader, and
+// receives the proposal actually sent by the leader. The executor never
+// sends any messages.
+func (ce *coordinationExecutor) shadow(
+	window *coordinationWindow,
+) (*shadowCoordinationResult, error) {
+	if lockAcquired := ce.lock.TryAcquire(1); !lockAcquired {
+		return nil, errCoordinationExecutorBusy
+	}
+	defer ce.lock.Release(1)
+
+	// Just in case, check if the window is valid.
+	if window.index() == 0 {
+		return nil, fmt.Errorf(
+			"invalid coordination block [%v]",
+			window.coordinationBlock,
+		)
+	}
+
+	seed, err := ce.getSeed(window.coordinationBlock)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute coordination seed: [%v]", err)
+	}
+
+	result := &shadowCoordinationResult{
+		window: window,
+		leader: ce.getLeader(seed),
+		actionsChecklist: ce.getActionsChecklist(
+			window.index(),
+			seed,
+			window.coordinationBlock,
+		),
+	}
+
+	var unconfirmedTransactions map[bitcoin.Hash]time.Time
+	if ce.transactionsTracker != nil &&
+		slices.Contains(result.actionsChecklist, ActionFeeBump) {
+		unconfirmedTransactions, err = ce.transactionsTracker.observe(
+			ce.walletPublicKeyHash(),
+		)
+		if err != nil {
+			logger.Warnf(
+				"cannot observe unconfirmed wallet transactions: [%v]",
+				err,
+			)
+		}
+	}
+
+	ctx, cancelCtx := withCancelOnBlock(
+		context.Background(),
+		window.activePhaseEndBlock(),
+		ce.waitForBlockFn,
+	)
+	defer cancelCtx()
+
+	// Start listening for the leader's message before generating the local
+	// proposal. The generation may take a while and the message could be
+	// missed otherwise.
+	followerDone := make(chan struct{})
+	go func() {
+		defer close(followerDone)
+
+		result.receivedProposal, result.faults, result.receivedProposalErr =
+			ce.executeFollowerRoutine(
+				ctx,
+				result.leader,
+				window.coordinationBlock,
+				append(result.actionsChecklist, ActionNoop),
+			)
+	}()
+
+	result.localProposal, result.localProposalErr = ce.generateProposal(
+		&CoordinationProposalRequest{
+			WalletPublicKeyHash:     ce.walletPublicKeyHash(),
+			WalletOperators:         ce.coordinatedWallet.signingGroupOperators,
+			ExecutingOperator:       result.leader,
+			ActionsChecklist:        result.actionsChecklist,
+			CoordinationBlock:       window.coordinationBlock,
+			UnconfirmedTransactions: unconfirmedTransactions,
+		},
+		1, // a single attempt is enough as nothing depends on the result
+		0,
+	)
+
+	<-followerDone
+
+	return result, nil
+}
+
+// executeShadowCoordinationProcedure executes the coordination procedure for
+// the given wallet and coordination window in the shadow mode. The proposal
+// received from the leader is validated and compared with the locally
+// generated one. The outcome is logged and recorded in the audit journal.
+// The function never returns a result to process so, no wallet action is
+// ever executed.
+func executeShadowCoordinationProcedure(
+	node *node,
+	window *coordinationWindow,
+	walletPublicKey *ecdsa.PublicKey,
+) (*coordinationResult, bool) {
+	walletPublicKeyHash := bitcoin.PublicKeyHash(walletPublicKey)
+
+	procedureLogger := logger.With(
+		zap.Uint64("coordinationBlock", window.coordinationBlock),
+		zap.String("walletPKH", fmt.Sprintf("0x%x", walletPublicKeyHash)),
+		zap.Bool("shadow", true),
+	)
+
+	procedureLogger.Infof("starting shadow coordination procedure")
+
+	executor, ok, err := node.getCoordinationExecutor(walletPublicKey)
+	if err != nil {
+		procedureLogger.Errorf("cannot get coordination executor: [%v]", err)
+		return nil, false
+	}
+	if !ok {
+		procedureLogger.Infof("node does not control signers of this wallet")
+		return nil, false
+	}
+
+	result, err := executor.shadow(window)
+	if err != nil {
+		procedureLogger.Errorf("shadow coordination procedure failed: [%v]", err)
+		return nil, false
+	}
+
+	if result.localProposalErr != nil {
+		procedureLogger.Warnf(
+			"cannot generate local proposal: [%v]",
+			result.localProposalErr,
+		)
+	}
+
+	if result.receivedProposalErr != nil {
+		procedureLogger.Warnf(
+			"proposal not received from leader: [%v]",
+			result.receivedProposalErr,
+		)
+	} else {
+		result.validationErr = node.validateShadowProposal(
+			procedureLogger,
+			walletPublicKeyHash,
+			result.receivedProposal,
+		)
+		if result.validationErr != nil {
+			procedureLogger.Warnf(
+				"received proposal is invalid: [%v]",
+				result.validationErr,
+			)
+		}
+	}
+
+	if result.localProposal != nil && result.receivedProposal != nil &&
+		!result.proposalsMatch() {
+		procedureLogger.Warnf(
+			"received proposal differs from the local one; "+
+				"local: [%+v], received: [%+v]",
+			result.localProposal,
+			result.receivedProposal,
+		)
+	}
+
+	procedureLogger.Infof(
+		"shadow coordination procedure finished with result [%s]",
+		result,
+	)
+
+	node.journal.recordShadowCoordination(walletPublicKeyHash, result)
+
+	return nil, false
+}
+
+// validateShadowProposal validates the given proposal received from the
+// coordination leader the same way the corresponding wallet action does
+// before execution.
+func (n *node) validateShadowProposal(
+	validateProposalLogger log.StandardLogger,
+	walletPublicKeyHash [20]byte,
+	proposal CoordinationProposal,
+) error {
+	switch p := proposal.(type) {
+	case *NoopProposal:
+		return nil
+	case *HeartbeatProposal:
+		return n.chain.ValidateHeartbeatProposal(walletPublicKeyHash, p)
+	case *DepositSweepProposal:
+		_, err := ValidateDepositSweepProposal(
+			validateProposalLogger,
+			walletPublicKeyHash,
+			p,
+			DepositSweepRequiredFundingTxConfirmations,
+			n.chain,
+			n.btcChain,
+		)
+		return err
+	case *RedemptionProposal:
+		_, err := ValidateRedemptionProposal(
+			validateProposalLogger,
+			walletPublicKeyHash,
+			p,
+			n.chain,
+		)
+		return err
+	case *MovingFundsProposal:
+		walletMainUtxo, err := DetermineWalletMainUtxo(
+			walletPublicKeyHash,
+			n.chain,
+			n.btcChain,
+		)
+		if err != nil {
+			return fmt.Errorf(
+				"error while determining wallet's main UTXO: [%v]",
+				err,
+			)
+		}
+		if walletMainUtxo == nil {
+			return fmt.Errorf("moving funds wallet has no main UTXO")
+		}
+
+		return ValidateMovingFundsProposal(
+			validateProposalLogger,
+			walletPublicKeyHash,
+			walletMainUtxo,
+			p,
+			n.chain,
+		)
+	case *MovedFundsSweepProposal:
+		return ValidateMovedFundsSweepProposal(
+			validateProposalLogger,
+			walletPublicKeyHash,
+			p,
+			n.chain,
+		)
+	case *FeeBumpProposal:
+		return ValidateFeeBumpProposal(
+			validateProposalLogger,
+			walletPublicKeyHash,
+			p,
+			n.chain,
+			n.btcChain,
+		)
+	default:
+		return fmt.Errorf("unsupported proposal type [%T]", proposal)
+	}
+}
@@ -0,0 +1,365 @@
+package tbtc
+
+import (
+	"context"
+	"encoding/hex"
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+
+	"golang.org/x/exp/slices"
+
+	"github.com/keep-network/keep-core/internal/testutils"
+	"github.com/keep-network/keep-core/pkg/bitcoin"
+	"github.com/keep-network/keep-core/pkg/chain"
+	"github.com/keep-network/keep-core/pkg/chain/local_v1"
+	"github.com/keep-network/keep-core/pkg/generator"
+	"github.com/keep-network/keep-core/pkg/net"
+	netlocal "github.com/keep-network/keep-core/pkg/net/local"
+	"github.com/keep-network/keep-core/pkg/operator"
+	"github.com/keep-network/keep-core/pkg/protocol/group"
+)
+
+func TestCoordinationExecutor_Shadow(t *testing.T) {
+	// Uncompressed public key corresponding to the 20-byte public key hash:
+	// aa768412ceed10bd423c025542ca90071f9fb62d.
+	publicKeyHex, err := hex.DecodeString(
+		"0471e30bca60f6548d7b42582a478ea37ada63b402af7b3ddd57f0c95bb6843175" +
+			"aa0d2053a91a050a6797d85c38f2909cb7027f2344a01986aa2f9f8ca7a0c289",
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	redeemerOutputScript, err := hex.DecodeString(
+		"00148db50eb52063ea9d98b3eac91489a90f738986f6",
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	coordinationBlock := uint64(900)
+
+	type operatorFixture struct {
+		chain              Chain
+		address            chain.Address
+		publicKey          *operator.PublicKey
+		waitForBlockHeight func(ctx context.Context, blockHeight uint64) error
+	}
+
+	generateOperator := func(privateKey int64) *operatorFixture {
+		privateKeyBigInt := big.NewInt(privateKey)
+		x, y := local_v1.DefaultCurve.ScalarBaseMult(privateKeyBigInt.Bytes())
+
+		localChain := ConnectWithKey(
+			&operator.PrivateKey{
+				PublicKey: operator.PublicKey{
+					Curve: operator.Secp256k1,
+					X:     x,
+					Y:     y,
+				},
+				D: privateKeyBigInt,
+			},
+			100*time.Millisecond,
+		)
+
+		localChain.setBlockHashByNumber(
+			coordinationBlock-32,
+			"1422996cbcbc38fc924a46f4df5f9064279d3ab43396e58386dac9b87440d64f",
+		)
+
+		operatorAddress, err := localChain.operatorAddress()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, operatorPublicKey, err := localChain.OperatorKeyPair()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		waitForBlockHeight := func(ctx context.Context, blockHeight uint64) error {
+			blockCounter, err := localChain.BlockCounter()
+			if err != nil {
+				return err
+			}
+
+			wait, err := blockCounter.BlockHeightWaiter(blockHeight)
+			if err != nil {
+				return err
+			}
+
+			select {
+			case <-wait:
+			case <-ctx.Done():
+			}
+
+			return nil
+		}
+
+		return &operatorFixture{
+			chain:              localChain,
+			address:            operatorAddress,
+			publicKey:          operatorPublicKey,
+			waitForBlockHeight: waitForBlockHeight,
+		}
+	}
+
+	operator1 := generateOperator(1)
+	operator2 := generateOperator(2)
+	operator3 := generateOperator(3)
+
+	// Same signing group as in the coordinate test so operator2 is
+	// the leader of the window and redemption is on the actions checklist.
+	coordinatedWallet := wallet{
+		publicKey: unmarshalPublicKey(publicKeyHex),
+		signingGroupOperators: []chain.Address{
+			operator2.address,
+			operator3.address,
+			operator1.address,
+			operator1.address,
+			operator3.address,
+			operator2.address,
+			operator2.address,
+			operator3.address,
+			operator1.address,
+			operator1.address,
+		},
+	}
+
+	proposal := &RedemptionProposal{
+		RedeemersOutputScripts: []bitcoin.Script{
+			redeemerOutputScript,
+		},
+		RedemptionTxFee: big.NewInt(10000),
+	}
+
+	proposalGenerator := newMockCoordinationProposalGenerator(
+		func(
+			walletPublicKeyHash [20]byte,
+			actionsChecklist []WalletActionType,
+			_ uint,
+		) (CoordinationProposal, error) {
+			if slices.Contains(actionsChecklist, ActionRedemption) {
+				return proposal, nil
+			}
+
+			return &NoopProposal{}, nil
+		},
+	)
+
+	membershipValidator := group.NewMembershipValidator(
+		&testutils.MockLogger{},
+		coordinatedWallet.signingGroupOperators,
+		Connect().Signing(),
+	)
+
+	generateExecutor := func(operator *operatorFixture) *coordinationExecutor {
+		broadcastChannel, err := netlocal.ConnectWithKey(operator.publicKey).
+			BroadcastChannelFor("shadow-test")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		broadcastChannel.SetUnmarshaler(func() net.TaggedUnmarshaler {
+			return &coordinationMessage{}
+		})
+
+		return newCoordinationExecutor(
+			operator.chain,
+			coordinatedWallet,
+			coordinatedWallet.membersByOperator(operator.address),
+			operator.address,
+			proposalGenerator,
+			broadcastChannel,
+			membershipValidator,
+			generator.NewProtocolLatch(),
+			operator.waitForBlockHeight,
+			nil,
+		)
+	}
+
+	window := newCoordinationWindow(coordinationBlock)
+
+	// The shadow node shares the keystore, and therefore the members,
+	// with the leader.
+	shadowExecutor := generateExecutor(operator2)
+	shadowExecutor.shadowMode = true
+
+	shadowResultChan := make(chan *shadowCoordinationResult, 1)
+	go func() {
+		result, err := shadowExecutor.shadow(window)
+		if err != nil {
+			t.Error(err)
+		}
+		shadowResultChan <- result
+	}()
+
+	// Give the shadow executor some time to start listening.
+	time.Sleep(100 * time.Millisecond)
+
+	leaderResult, err := generateExecutor(operator2).coordinate(window)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result := <-shadowResultChan
+	if result == nil {
+		t.Fatal("missing shadow result")
+	}
+
+	testutils.AssertStringsEqual(
+		t,
+		"leader",
+		leaderResult.leader.String(),
+		result.leader.String(),
+	)
+	if result.localProposalErr != nil {
+		t.Errorf("unexpected local proposal error: [%v]", result.localProposalErr)
+	}
+	if result.receivedProposalErr != nil {
+		t.Errorf(
+			"unexpected received proposal error: [%v]",
+			result.receivedProposalErr,
+		)
+	}
+	if !reflect.DeepEqual(proposal, result.receivedProposal) {
+		t.Errorf(
+			"unexpected received proposal\nexpected: %v\nactual:   %v",
+			proposal,
+			result.receivedProposal,
+		)
+	}
+	testutils.AssertBoolsEqual(t, "proposals match", true, result.proposalsMatch())
+}
+
+func TestShadowCoordinationResult_ProposalsMatch(t *testing.T) {
+	heartbeat := func(message byte) *HeartbeatProposal {
+		return &HeartbeatProposal{Message: [16]byte{message}}
+	}
+
+	var tests = map[string]struct {
+		localProposal    CoordinationProposal
+		receivedProposal CoordinationProposal
+		expectedMatch    bool
+	}{
+		"same proposals": {
+			localProposal:    heartbeat(0x01),
+			receivedProposal: heartbeat(0x01),
+			expectedMatch:    true,
+		},
+		"different payloads": {
+			localProposal:    heartbeat(0x01),
+			receivedProposal: heartbeat(0x02),
+			expectedMatch:    false,
+		},
+		"different action types": {
+			localProposal:    heartbeat(0x01),
+			receivedProposal: &NoopProposal{},
+			expectedMatch:    false,
+		},
+		"missing local proposal": {
+			receivedProposal: heartbeat(0x01),
+			expectedMatch:    false,
+		},
+		"missing received proposal": {
+			localProposal: heartbeat(0x01),
+			expectedMatch: false,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			result := &shadowCoordinationResult{
+				localProposal:    test.localProposal,
+				receivedProposal: test.receivedProposal,
+			}
+
+			testutils.AssertBoolsEqual(
+				t,
+				"proposals match",
+				test.expectedMatch,
+				result.proposalsMatch(),
+			)
+		})
+	}
+}
+
+func TestAuditJournal_RecordShadowCoordination(t *testing.T) {
+	journal, err := newAuditJournal(
+		newJournalPersistenceHandle(),
+		func() (uint64, error) { return 0, nil },
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	journal.recordShadowCoordination(
+		[20]byte{0x01},
+		&shadowCoordinationResult{
+			window:           newCoordinationWindow(900),
+			leader:           chain.Address("leader"),
+			actionsChecklist: []WalletActionType{ActionRedemption, ActionHeartbeat},
+			localProposal:    &HeartbeatProposal{Message: [16]byte{0x01}},
+			receivedProposal: &NoopProposal{},
+		},
+	)
+
+	entries, err := journal.query(JournalQuery{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertIntsEqual(t, "entries count", 1, len(entries))
+	testutils.AssertStringsEqual(
+		t,
+		"local action type",
+		ActionHeartbeat.String(),
+		entries[0].LocalActionType,
+	)
+	testutils.AssertStringsEqual(
+		t,
+		"received action type",
+		ActionNoop.String(),
+		entries[0].ActionType,
+	)
+	testutils.AssertBoolsEqual(t, "proposals match", false, entries[0].ProposalsMatch)
+}
+
+func TestEnsureShadowIdentity(t *testing.T) {
+	keyStorePersistence := createMockKeyStorePersistence(
+		t,
+		createMockSigner(t),
+	)
+
+	var tests = map[string]struct {
+		operatorAddress chain.Address
+		expectError     bool
+	}{
+		"operator is not a wallet member": {
+			operatorAddress: "address-6",
+			expectError:     false,
+		},
+		"operator is a wallet member": {
+			operatorAddress: "address-3",
+			expectError:     true,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			err := EnsureShadowIdentity(
+				keyStorePersistence,
+				test.operatorAddress,
+			)
+
+			testutils.AssertBoolsEqual(
+				t,
+				"error",
+				test.expectError,
+				err != nil,
+			)
+		})
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/keep-network/keep-core/pkg/bitcoin"
 	"github.com/keep-network/keep-core/pkg/clientinfo"
 	"github.com/keep-network/keep-core/pkg/generator"
 	"github.com/keep-network/keep-core/pkg/net"
@@ -34,6 +35,23 @@ const (
 	// completed by the slowest signing group member (the one who sends the
 	// signingDoneMessage as the last one).
 	signingBatchInterludeBlocks = 2
+
+	// signingBatchMaxSize determines the maximum number of messages signed
+	// together, within the same signing attempts. Messages of a batch are
+	// signed concurrently so this limit bounds the computational and network
+	// load of a single signing attempt and ensures the attempt fits within
+	// signingAttemptMaximumProtocolBlocks.
+	signingBatchMaxSize = 5
+
+	// BatchSigningActivationBlock is the Ethereum block height at which
+	// signing batches start to be signed in chunks of up to signingBatchMaxSize
+	// messages signed together, instead of signing messages one after another.
+	// All operators must upgrade to a binary containing this constant before
+	// the activation block is reached. The block is expected around
+	// 2027-02-09, assuming 12-second blocks, two weeks after
+	// DepositSweepCPFPActivationBlock so signing changes are activated
+	// separately from the coordination changes.
+	BatchSigningActivationBlock = uint64(27037000)
 )
 
 // errSigningExecutorBusy is an error returned when the signing executor
@@ -61,6 +79,18 @@ type signingExecutor struct {
 	// limit is hit the signer gives up.
 	signingAttemptsLimit uint
 
+	// timingSchedule determines attempt timings of signing protocols
+	// started at the given block.
+	timingSchedule TimingSchedule
+
+	// journal is optional and used for recording outcomes and phase timings
+	// of signing attempts.
+	journal *auditJournal
+
+	// signingCaptureRecorder is optional and used for capturing signing
+	// protocol messages of failed signing attempts.
+	signingCaptureRecorder *signingCaptureRecorder
+
 	// metricsRecorder is optional and used for recording performance metrics
 	metricsRecorder interface {
 		IncrementCounter(name string, value float64)
@@ -89,15 +119,19 @@ func newSigningExecutor(
 		getCurrentBlockFn:    getCurrentBlockFn,
 		waitForBlockFn:       waitForBlockFn,
 		signingAttemptsLimit: signingAttemptsLimit,
+		timingSchedule:       DefaultTimingSchedule(),
 	}
 }
 
-// signBatch performs the signing process for each message from the given
-// messages batch, one after another. If at least one message cannot be signed,
-// this function returns an error. If all messages were signed successfully,
-// a slice of signatures is returned. Order of the returned signatures matches
-// the order of the messages in the batch, i.e. the first signature corresponds
-// to the first message, and so on.
+// signBatch performs the signing process for all messages from the given
+// messages batch. If the batch starts before BatchSigningActivationBlock,
+// messages are signed one after another. Otherwise, the batch is split into
+// chunks of up to signingBatchMaxSize messages and messages of each chunk are
+// signed together, chunk after chunk. If at least one message cannot be
+// signed, this function returns an error. If all messages were signed
+// successfully, a slice of signatures is returned. Order of the returned
+// signatures matches the order of the messages in the batch, i.e. the first
+// signature corresponds to the first message, and so on.
 func (se *signingExecutor) signBatch(
 	ctx context.Context,
 	messages []*big.Int,
@@ -138,6 +172,10 @@ func (se *signingExecutor) signBatch(
 		zap.String("signedMessages", strings.Join(messagesDigests, ", ")),
 	)
 
+	if startBlock >= BatchSigningActivationBlock {
+		return se.signChunks(ctx, messages, startBlock, signingBatchLogger)
+	}
+
 	signingStartBlock := startBlock // start block for the first signing
 	signatures := make([]*tecdsa.Signature, len(messages))
 	endBlocks := make([]uint64, len(messages))
@@ -173,6 +211,56 @@ func (se *signingExecutor) signBatch(
 	return signatures, nil
 }
 
+// signChunks splits the given messages into chunks of up to
+// signingBatchMaxSize messages and signs messages of each chunk together,
+// chunk after chunk. Signatures are returned in the order of the messages.
+func (se *signingExecutor) signChunks(
+	ctx context.Context,
+	messages []*big.Int,
+	startBlock uint64,
+	signingBatchLogger *zap.SugaredLogger,
+) ([]*tecdsa.Signature, error) {
+	signingStartBlock := startBlock // start block for the first chunk
+	signatures := make([]*tecdsa.Signature, 0, len(messages))
+
+	for chunkStart := 0; chunkStart < len(messages); chunkStart += signingBatchMaxSize {
+		chunkEnd := chunkStart + signingBatchMaxSize
+		if chunkEnd > len(messages) {
+			chunkEnd = len(messages)
+		}
+
+		signingChunkLogger := signingBatchLogger.With(
+			zap.String(
+				"chunk",
+				fmt.Sprintf("%v-%v/%v", chunkStart+1, chunkEnd, len(messages)),
+			),
+		)
+
+		signingChunkLogger.Infof("generating signatures for messages chunk")
+
+		chunkSignatures, _, endBlock, err := se.signMessages(
+			ctx,
+			messages[chunkStart:chunkEnd],
+			signingStartBlock,
+		)
+		if err != nil {
+			// Error metrics are recorded in the signMessages() method for
+			// all error paths.
+			return nil, err
+		}
+
+		signingChunkLogger.Infof(
+			"generated signatures for messages chunk at block [%v]",
+			endBlock,
+		)
+
+		signatures = append(signatures, chunkSignatures...)
+		signingStartBlock = endBlock + signingBatchInterludeBlocks
+	}
+
+	return signatures, nil
+}
+
 // sign performs the signing process for the given message. The process is
 // triggered according to the given start block. If the message cannot be signed
 // within a limited time window, an error is returned. If the message was
@@ -185,6 +273,32 @@ func (se *signingExecutor) sign(
 	message *big.Int,
 	startBlock uint64,
 ) (*tecdsa.Signature, *signingActivityReport, uint64, error) {
+	signatures, activityReport, endBlock, err := se.signMessages(
+		ctx,
+		[]*big.Int{message},
+		startBlock,
+	)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	return signatures[0], activityReport, endBlock, nil
+}
+
+// signMessages performs the signing process for all the given messages
+// together. Messages are signed within the same signing attempts and the
+// process succeeds only if all messages are signed. The process is triggered
+// according to the given start block. If the messages cannot be signed within
+// a limited time window, an error is returned. If the messages were signed
+// successfully, this function returns the signatures, in the order of the
+// messages, along with the activity report of signing group members and
+// the block at which the signatures were calculated. The end block is common
+// for all wallet signers so can be used as a synchronization point.
+func (se *signingExecutor) signMessages(
+	ctx context.Context,
+	messages []*big.Int,
+	startBlock uint64,
+) ([]*tecdsa.Signature, *signingActivityReport, uint64, error) {
 	if lockAcquired := se.lock.TryAcquire(1); !lockAcquired {
 		// Record failure metrics for lock acquisition failure
 		if se.metricsRecorder != nil {
@@ -212,18 +326,35 @@ func (se *signingExecutor) sign(
 		return nil, nil, 0, fmt.Errorf("cannot marshal wallet public key: [%v]", err)
 	}
 
+	timing := se.timingSchedule.timingAt(startBlock)
+
 	loopTimeoutBlock := startBlock +
-		uint64(se.signingAttemptsLimit*signingAttemptMaximumBlocks())
+		uint64(se.signingAttemptsLimit)*timing.Signing.MaximumBlocks()
+
+	walletPublicKeyHash := bitcoin.PublicKeyHash(wallet.publicKey)
+
+	batchID := signingBatchID(messages)
+
+	signedMessagesField := zap.String(
+		"signedMessage",
+		fmt.Sprintf("0x%x", batchID),
+	)
+	if len(messages) > 1 {
+		signedMessagesField = zap.String(
+			"signedMessagesBatch",
+			fmt.Sprintf("0x%x", batchID),
+		)
+	}
 
 	signingLogger := logger.With(
 		zap.String("wallet", fmt.Sprintf("0x%x", walletPublicKeyBytes)),
-		zap.String("signedMessage", fmt.Sprintf("0x%x", message)),
+		signedMessagesField,
 		zap.Uint64("signingStartBlock", startBlock),
 		zap.Uint64("signingTimeoutBlock", loopTimeoutBlock),
 	)
 
 	type signingOutcome struct {
-		signature      *tecdsa.Signature
+		signatures     []*tecdsa.Signature
 		activityReport *signingActivityReport
 		endBlock       uint64
 	}
@@ -253,15 +384,29 @@ func (se *signingExecutor) sign(
 
 			retryLoop := newSigningRetryLoop(
 				signingLogger,
-				message,
+				messages,
 				startBlock,
 				signer.signingGroupMemberIndex,
 				wallet.signingGroupOperators,
 				se.groupParameters,
+				timing,
 				announcer,
 				doneCheck,
 			)
 
+			if se.journal != nil {
+				retryLoop.attemptRecorder = func(
+					attempt *JournalAttemptTiming,
+					attemptErr error,
+				) {
+					se.journal.recordAttempt(
+						&walletPublicKeyHash,
+						attempt,
+						attemptErr,
+					)
+				}
+			}
+
 			// Set up the loop timeout signal. This context is associated with
 			// all attempts and gets canceled in three situations:
 			// - one of the attempts failed with an error,
@@ -284,7 +429,7 @@ func (se *signingExecutor) sign(
 				loopCtx,
 				se.waitForBlockFn,
 				se.getCurrentBlockFn,
-				func(attempt *signingAttemptParams) (*signing.Result, uint64, error) {
+				func(attempt *signingAttemptParams) ([]*signing.Result, uint64, error) {
 					signingAttemptLogger := signingLogger.With(
 						zap.Uint("attemptNumber", attempt.number),
 						zap.Uint64("attemptStartBlock", attempt.startBlock),
@@ -315,25 +460,89 @@ func (se *signingExecutor) sign(
 
 					sessionID := fmt.Sprintf(
 						"%v-%v",
-						message.Text(16),
+						batchID.Text(16),
 						attempt.number,
 					)
 
-					result, err := signing.Execute(
-						attemptCtx,
-						signingAttemptLogger,
-						message,
-						sessionID,
-						signer.signingGroupMemberIndex,
-						signer.privateKeyShare,
-						wallet.groupSize(),
-						wallet.groupDishonestThreshold(
-							se.groupParameters.HonestThreshold,
-						),
-						attempt.excludedMembersIndexes,
-						se.broadcastChannel,
-						se.membershipValidator,
+					dishonestThreshold := wallet.groupDishonestThreshold(
+						se.groupParameters.HonestThreshold,
 					)
+
+					// A single message is signed in a standalone session
+					// to keep it compatible with clients not supporting
+					// signing batches. Each message of a bigger batch is
+					// signed in its own session of the batch.
+					messagesSessionIDs := []string{sessionID}
+					if len(messages) > 1 {
+						messagesSessionIDs = make([]string, len(messages))
+						for i := range messages {
+							messagesSessionIDs[i] = signing.BatchSessionID(
+								sessionID,
+								i,
+							)
+						}
+					}
+
+					attemptCaptures := make(
+						[]*signingAttemptCapture,
+						len(messages),
+					)
+					for i, message := range messages {
+						attemptCaptures[i] = se.signingCaptureRecorder.begin(
+							attemptCtx,
+							se.broadcastChannel,
+							&SigningCapture{
+								SessionID:              messagesSessionIDs[i],
+								AttemptNumber:          attempt.number,
+								MemberIndex:            signer.signingGroupMemberIndex,
+								WalletPublicKey:        walletPublicKeyBytes,
+								Message:                fmt.Sprintf("0x%x", message),
+								GroupSize:              wallet.groupSize(),
+								DishonestThreshold:     dishonestThreshold,
+								SigningGroupOperators:  wallet.signingGroupOperators,
+								ExcludedMembersIndexes: attempt.excludedMembersIndexes,
+								StartBlock:             attempt.startBlock,
+								TimeoutBlock:           attempt.timeoutBlock,
+							},
+						)
+					}
+
+					var results []*signing.Result
+					var err error
+					if len(messages) == 1 {
+						var result *signing.Result
+						result, err = signing.Execute(
+							attemptCtx,
+							signingAttemptLogger,
+							messages[0],
+							sessionID,
+							signer.signingGroupMemberIndex,
+							signer.privateKeyShare,
+							wallet.groupSize(),
+							dishonestThreshold,
+							attempt.excludedMembersIndexes,
+							se.broadcastChannel,
+							se.membershipValidator,
+						)
+						results = []*signing.Result{result}
+					} else {
+						results, err = signing.ExecuteBatch(
+							attemptCtx,
+							signingAttemptLogger,
+							messages,
+							sessionID,
+							signer.signingGroupMemberIndex,
+							signer.privateKeyShare,
+							wallet.groupSize(),
+							dishonestThreshold,
+							attempt.excludedMembersIndexes,
+							se.broadcastChannel,
+							se.membershipValidator,
+						)
+					}
+					for _, attemptCapture := range attemptCaptures {
+						attemptCapture.end(err)
+					}
 					if err != nil {
 						return nil, 0, err
 					}
@@ -343,7 +552,7 @@ func (se *signingExecutor) sign(
 						return nil, 0, err
 					}
 
-					return result, endBlock, nil
+					return results, endBlock, nil
 				},
 			)
 			if err != nil {
@@ -384,15 +593,20 @@ func (se *signingExecutor) sign(
 				}
 			}()
 
+			signatures := make([]*tecdsa.Signature, len(loopResult.results))
+			for i, result := range loopResult.results {
+				signatures[i] = result.Signature
+			}
+
 			signingLogger.Infof(
-				"[member:%v] generated signature [%v] at block [%v]",
+				"[member:%v] generated signatures %v at block [%v]",
 				signer.signingGroupMemberIndex,
-				loopResult.result.Signature,
+				signatures,
 				loopResult.latestEndBlock,
 			)
 
 			signingOutcomeChan <- &signingOutcome{
-				signature:      loopResult.result.Signature,
+				signatures:     signatures,
 				activityReport: loopResult.activityReport,
 				endBlock:       loopResult.latestEndBlock,
 			}
@@ -415,7 +629,7 @@ func (se *signingExecutor) sign(
 			se.metricsRecorder.IncrementCounter(clientinfo.MetricSigningSuccessTotal, 1)
 			se.metricsRecorder.RecordDuration(clientinfo.MetricSigningDurationSeconds, time.Since(startTime))
 		}
-		return outcome.signature, outcome.activityReport, outcome.endBlock, nil
+		return outcome.signatures, outcome.activityReport, outcome.endBlock, nil
 	default:
 		if se.metricsRecorder != nil {
 			// All signers failed to produce a signature within the timeout period.
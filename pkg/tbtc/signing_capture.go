@@ -0,0 +1,246 @@
+package tbtc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/keep-network/keep-common/pkg/persistence"
+
+	"github.com/keep-network/keep-core/pkg/chain"
+	"github.com/keep-network/keep-core/pkg/net"
+	"github.com/keep-network/keep-core/pkg/protocol/group"
+	"github.com/keep-network/keep-core/pkg/tecdsa/signing"
+	"go.uber.org/zap"
+)
+
+// signingCaptureDirectory is the name of the work persistence directory
+// holding signing captures.
+const signingCaptureDirectory = "signing_captures"
+
+// SigningCapture holds signing protocol messages received by a single
+// signing group member during a failed signing attempt, along with
+// parameters of the attempt needed to replay the messages offline.
+type SigningCapture struct {
+	SessionID     string            `json:"session_id"`
+	AttemptNumber uint              `json:"attempt_number"`
+	MemberIndex   group.MemberIndex `json:"member_index"`
+
+	WalletPublicKey        []byte              `json:"wallet_public_key"`
+	Message                string              `json:"message"`
+	GroupSize              int                 `json:"group_size"`
+	DishonestThreshold     int                 `json:"dishonest_threshold"`
+	SigningGroupOperators  chain.Addresses     `json:"signing_group_operators"`
+	ExcludedMembersIndexes []group.MemberIndex `json:"excluded_members_indexes"`
+	StartBlock             uint64              `json:"start_block"`
+	TimeoutBlock           uint64              `json:"timeout_block"`
+
+	// Error is the error the attempt failed with.
+	Error    string                     `json:"error"`
+	Messages []*signing.CapturedMessage `json:"messages"`
+}
+
+// Replay replays captured messages offline and reports which members did
+// not send their messages or sent invalid data. The given signing is used
+// to validate the sender public keys against signing group operators.
+func (sc *SigningCapture) Replay(chainSigning chain.Signing) *signing.ReplayReport {
+	replayLogger := logger.With(zap.String("sessionID", sc.SessionID))
+
+	return signing.Replay(
+		replayLogger,
+		sc.SessionID,
+		sc.MemberIndex,
+		sc.GroupSize,
+		sc.DishonestThreshold,
+		sc.ExcludedMembersIndexes,
+		group.NewMembershipValidator(
+			replayLogger,
+			sc.SigningGroupOperators,
+			chainSigning,
+		),
+		sc.Messages,
+	)
+}
+
+// signingCaptureName returns the name of the file holding the given capture.
+func signingCaptureName(sessionID string, memberIndex group.MemberIndex) string {
+	return fmt.Sprintf("%s_%d", sessionID, memberIndex)
+}
+
+// ReadSigningCaptures reads all signing captures from the given work
+// persistence handle. Captures are sorted by session ID and member index.
+func ReadSigningCaptures(
+	handle persistence.BasicHandle,
+) ([]*SigningCapture, error) {
+	captures := make([]*SigningCapture, 0)
+
+	descriptorsChan, errorsChan := handle.ReadAll()
+
+	var errs, readErrs []error
+
+	// Channels are not buffered so descriptors and errors must be read
+	// concurrently.
+	var wg sync.WaitGroup
+	wg.Add(2)
+
+	go func() {
+		defer wg.Done()
+
+		for descriptor := range descriptorsChan {
+			if descriptor.Directory() != signingCaptureDirectory {
+				continue
+			}
+
+			content, err := descriptor.Content()
+			if err != nil {
+				errs = append(errs, fmt.Errorf(
+					"cannot read signing capture [%s]: [%v]",
+					descriptor.Name(),
+					err,
+				))
+				continue
+			}
+
+			capture := &SigningCapture{}
+			if err := json.Unmarshal(content, capture); err != nil {
+				errs = append(errs, fmt.Errorf(
+					"cannot decode signing capture [%s]: [%v]",
+					descriptor.Name(),
+					err,
+				))
+				continue
+			}
+
+			captures = append(captures, capture)
+		}
+	}()
+
+	go func() {
+		defer wg.Done()
+
+		for err := range errorsChan {
+			readErrs = append(readErrs, err)
+		}
+	}()
+
+	wg.Wait()
+
+	errs = append(errs, readErrs...)
+	if len(errs) > 0 {
+		return nil, errs[0]
+	}
+
+	sort.Slice(captures, func(i, j int) bool {
+		if captures[i].SessionID != captures[j].SessionID {
+			return captures[i].SessionID < captures[j].SessionID
+		}
+		return captures[i].MemberIndex < captures[j].MemberIndex
+	})
+
+	return captures, nil
+}
+
+// signingCaptureRecorder captures signing protocol messages received during
+// signing attempts and persists captures of failed attempts for an offline
+// replay. Captures of successful attempts are discarded.
+type signingCaptureRecorder struct {
+	handle persistence.BasicHandle
+}
+
+func newSigningCaptureRecorder(
+	handle persistence.BasicHandle,
+) *signingCaptureRecorder {
+	return &signingCaptureRecorder{handle: handle}
+}
+
+// begin starts capturing signing protocol messages received from the given
+// broadcast channel for the given attempt. Capturing stops once end is
+// called or the given context is done. Returns nil if the recorder is nil,
+// i.e. capturing is disabled.
+func (scr *signingCaptureRecorder) begin(
+	ctx context.Context,
+	broadcastChannel net.BroadcastChannel,
+	capture *SigningCapture,
+) *signingAttemptCapture {
+	if scr == nil {
+		return nil
+	}
+
+	captureCtx, cancelCaptureCtx := context.WithCancel(ctx)
+
+	attemptCapture := &signingAttemptCapture{
+		recorder:         scr,
+		cancelCaptureCtx: cancelCaptureCtx,
+		capture:          capture,
+	}
+
+	broadcastChannel.Recv(captureCtx, func(netMessage net.Message) {
+		capturedMessage, ok := signing.CaptureMessage(netMessage)
+		if !ok {
+			return
+		}
+
+		attemptCapture.mutex.Lock()
+		defer attemptCapture.mutex.Unlock()
+
+		attemptCapture.capture.Messages = append(
+			attemptCapture.capture.Messages,
+			capturedMessage,
+		)
+	})
+
+	return attemptCapture
+}
+
+// signingAttemptCapture is the capture of an ongoing signing attempt.
+type signingAttemptCapture struct {
+	recorder         *signingCaptureRecorder
+	cancelCaptureCtx context.CancelFunc
+
+	mutex   sync.Mutex
+	capture *SigningCapture
+}
+
+// end stops capturing and persists the capture if the attempt failed.
+// Errors are logged and not returned as capturing must never disrupt
+// the signing.
+func (sac *signingAttemptCapture) end(attemptErr error) {
+	if sac == nil {
+		return
+	}
+
+	sac.cancelCaptureCtx()
+
+	if attemptErr == nil {
+		return
+	}
+
+	sac.mutex.Lock()
+	defer sac.mutex.Unlock()
+
+	sac.capture.Error = attemptErr.Error()
+
+	content, err := json.Marshal(sac.capture)
+	if err != nil {
+		logger.Errorf("cannot encode signing capture: [%v]", err)
+		return
+	}
+
+	name := signingCaptureName(sac.capture.SessionID, sac.capture.MemberIndex)
+
+	err = sac.recorder.handle.Save(content, signingCaptureDirectory, name)
+	if err != nil {
+		logger.Errorf("cannot persist signing capture [%s]: [%v]", name, err)
+		return
+	}
+
+	logger.Infof(
+		"[member:%v] persisted capture of failed signing attempt [%s] "+
+			"with [%v] messages",
+		sac.capture.MemberIndex,
+		sac.capture.SessionID,
+		len(sac.capture.Messages),
+	)
+}
@@ -0,0 +1,117 @@
+package tbtc
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/keep-network/keep-core/internal/testutils"
+	"github.com/keep-network/keep-core/pkg/chain"
+	netlocal "github.com/keep-network/keep-core/pkg/net/local"
+	"github.com/keep-network/keep-core/pkg/protocol/group"
+)
+
+func TestSigningCaptureRecorder(t *testing.T) {
+	var tests = map[string]struct {
+		recorderEnabled  bool
+		attemptErr       error
+		expectedCaptures int
+	}{
+		"failed attempt": {
+			recorderEnabled:  true,
+			attemptErr:       fmt.Errorf("attempt failed"),
+			expectedCaptures: 1,
+		},
+		"successful attempt": {
+			recorderEnabled:  true,
+			attemptErr:       nil,
+			expectedCaptures: 0,
+		},
+		"capturing disabled": {
+			recorderEnabled:  false,
+			attemptErr:       fmt.Errorf("attempt failed"),
+			expectedCaptures: 0,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			handle := newJournalPersistenceHandle()
+
+			var recorder *signingCaptureRecorder
+			if test.recorderEnabled {
+				recorder = newSigningCaptureRecorder(handle)
+			}
+
+			broadcastChannel, err := netlocal.Connect().
+				BroadcastChannelFor("test")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ctx, cancelCtx := context.WithCancel(context.Background())
+			defer cancelCtx()
+
+			attemptCapture := recorder.begin(
+				ctx,
+				broadcastChannel,
+				&SigningCapture{
+					SessionID:     "ff-1",
+					AttemptNumber: 1,
+					MemberIndex:   2,
+					GroupSize:     3,
+					SigningGroupOperators: chain.Addresses{
+						"operator1",
+						"operator2",
+						"operator3",
+					},
+					ExcludedMembersIndexes: []group.MemberIndex{3},
+				},
+			)
+
+			attemptCapture.end(test.attemptErr)
+
+			captures, err := ReadSigningCaptures(handle)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			testutils.AssertIntsEqual(
+				t,
+				"captures count",
+				test.expectedCaptures,
+				len(captures),
+			)
+
+			if test.expectedCaptures == 0 {
+				return
+			}
+
+			capture := captures[0]
+			testutils.AssertStringsEqual(
+				t,
+				"session ID",
+				"ff-1",
+				capture.SessionID,
+			)
+			testutils.AssertStringsEqual(
+				t,
+				"error",
+				test.attemptErr.Error(),
+				capture.Error,
+			)
+
+			// Member 1 is the only remaining operating member and did not
+			// send any messages.
+			report := capture.Replay(Connect().Signing())
+			for _, phase := range report.Phases {
+				testutils.AssertIntsEqual(
+					t,
+					"missing members count of phase "+phase.MessageType,
+					1,
+					len(phase.Missing),
+				)
+			}
+		})
+	}
+}
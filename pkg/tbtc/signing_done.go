@@ -30,12 +30,14 @@ const signingDoneCheckInterval = 100 * time.Millisecond
 var errWaitDoneTimedOut = fmt.Errorf("cannot receive signing done messages on time")
 
 // signingDoneMessage is a message used to signal a successful signature
-// calculation across all signing group members.
+// calculation across all signing group members. The message covers the whole
+// batch of signed messages. Signatures are in the same order as messages,
+// i.e. the first signature corresponds to the first message, and so on.
 type signingDoneMessage struct {
 	senderID      group.MemberIndex
-	message       *big.Int
+	messages      []*big.Int
 	attemptNumber uint64
-	signature     *tecdsa.Signature
+	signatures    []*tecdsa.Signature
 	endBlock      uint64
 }
 
@@ -71,15 +73,15 @@ func newSigningDoneCheck(
 
 // listen runs the signing done check listening routine. This function listens
 // for incoming signing done checks from members participating in the given
-// signing attempt. Messages are filtered out based on the attempt number. Only
-// one message for the given attempt can be sent by the given signing group
-// member. This function should be called before the signing attempt starts to
+// signing attempt of the given messages batch. Messages are filtered out based
+// on the signed messages batch and the attempt number. Only one message for
+// the given attempt can be sent by the given signing group member. This function should be called before the signing attempt starts to
 // ensure signing done messages are getting received as early as possible. This
 // is especially important when the current member is the slowest one with
 // executing the signing.
 func (sdc *signingDoneCheck) listen(
 	ctx context.Context,
-	message *big.Int,
+	messages []*big.Int,
 	attemptNumber uint64,
 	attemptTimeoutBlock uint64,
 	attemptMembersIndexes []group.MemberIndex,
@@ -110,7 +112,7 @@ func (sdc *signingDoneCheck) listen(
 				if !sdc.isValidDoneMessage(
 					doneMessage,
 					netMessage.SenderPublicKey(),
-					message,
+					messages,
 					attemptNumber,
 					attemptTimeoutBlock,
 				) {
@@ -129,32 +131,39 @@ func (sdc *signingDoneCheck) listen(
 }
 
 // signalDone broadcasts the signing done check along with information necessary
-// to attribute the result to the given signing attempt.
+// to attribute the results to the given signing attempt. Results must be
+// in the same order as the signed messages.
 func (sdc *signingDoneCheck) signalDone(
 	ctx context.Context,
 	memberIndex group.MemberIndex,
-	message *big.Int,
+	messages []*big.Int,
 	attemptNumber uint64,
-	result *signing.Result,
+	results []*signing.Result,
 	endBlock uint64,
 ) error {
+	signatures := make([]*tecdsa.Signature, len(results))
+	for i, result := range results {
+		signatures[i] = result.Signature
+	}
+
 	return sdc.broadcastChannel.Send(ctx, &signingDoneMessage{
 		senderID:      memberIndex,
-		message:       message,
+		messages:      messages,
 		attemptNumber: attemptNumber,
-		signature:     result.Signature,
+		signatures:    signatures,
 		endBlock:      endBlock,
 	}, net.BackoffRetransmissionStrategy)
 }
 
 // waitUntilAllDone blocks until it receives all the required done checks from
 // members or until the passed context is done. In the first case, it returns
-// the signature computed by the signing members and the block at which the
-// slowest signer completed the signature computation process. If the expected
-// done checks are not received on time, the function returns an error. If at
-// least one signature is different from others, the function returns an error.
+// the signatures computed by the signing members, in the order of signed
+// messages, and the block at which the slowest signer completed the signatures
+// computation process. If the expected done checks are not received on time,
+// the function returns an error. If at least one signature is different from
+// others, the function returns an error.
 func (sdc *signingDoneCheck) waitUntilAllDone(ctx context.Context) (
-	*signing.Result,
+	[]*signing.Result,
 	uint64,
 	error,
 ) {
@@ -170,19 +179,22 @@ func (sdc *signingDoneCheck) waitUntilAllDone(ctx context.Context) (
 
 		case <-ticker.C:
 			if sdc.expectedSignersCount == len(sdc.doneSigners) {
-				var signature *tecdsa.Signature
+				var signatures []*tecdsa.Signature
 				var latestEndBlock uint64
 
 				for _, doneMessage := range sdc.doneSigners {
-					if signature == nil {
-						signature = doneMessage.signature
+					if signatures == nil {
+						signatures = doneMessage.signatures
 					} else {
-						if !signature.Equals(doneMessage.signature) {
-							return nil, 0, fmt.Errorf(
-								"not matching signatures detected: [%v] and [%v]",
-								signature,
-								doneMessage.signature,
-							)
+						for i, signature := range signatures {
+							if !signature.Equals(doneMessage.signatures[i]) {
+								return nil, 0, fmt.Errorf(
+									"not matching signatures detected: "+
+										"[%v] and [%v]",
+									signature,
+									doneMessage.signatures[i],
+								)
+							}
 						}
 					}
 
@@ -191,7 +203,12 @@ func (sdc *signingDoneCheck) waitUntilAllDone(ctx context.Context) (
 					}
 				}
 
-				return &signing.Result{Signature: signature}, latestEndBlock, nil
+				results := make([]*signing.Result, len(signatures))
+				for i, signature := range signatures {
+					results[i] = &signing.Result{Signature: signature}
+				}
+
+				return results, latestEndBlock, nil
 			}
 		}
 	}
@@ -202,7 +219,7 @@ func (sdc *signingDoneCheck) waitUntilAllDone(ctx context.Context) (
 func (sdc *signingDoneCheck) isValidDoneMessage(
 	doneMessage *signingDoneMessage,
 	senderPublicKey []byte,
-	message *big.Int,
+	messages []*big.Int,
 	attemptNumber uint64,
 	attemptTimeoutBlock uint64,
 ) bool {
@@ -219,10 +236,16 @@ func (sdc *signingDoneCheck) isValidDoneMessage(
 		return false
 	}
 
-	if doneMessage.message.Cmp(message) != 0 {
+	if len(doneMessage.messages) != len(messages) {
 		return false
 	}
 
+	for i, message := range messages {
+		if doneMessage.messages[i].Cmp(message) != 0 {
+			return false
+		}
+	}
+
 	if doneMessage.attemptNumber != attemptNumber {
 		return false
 	}
@@ -231,9 +254,15 @@ func (sdc *signingDoneCheck) isValidDoneMessage(
 		return false
 	}
 
-	if doneMessage.signature == nil {
+	if len(doneMessage.signatures) != len(messages) {
 		return false
 	}
 
+	for _, signature := range doneMessage.signatures {
+		if signature == nil {
+			return false
+		}
+	}
+
 	return true
 }
@@ -22,7 +22,7 @@ import (
 	"github.com/keep-network/keep-core/pkg/tecdsa/signing"
 )
 
-// TestSigningDoneCheck is a happy path test.
+// TestSigningDoneCheck is a happy path test for a batch of messages.
 func TestSigningDoneCheck(t *testing.T) {
 	groupParameters := &GroupParameters{
 		GroupSize:       5,
@@ -41,21 +41,30 @@ func TestSigningDoneCheck(t *testing.T) {
 	ctx, cancelCtx := context.WithCancel(context.Background())
 	defer cancelCtx()
 
-	message := big.NewInt(100)
+	messages := []*big.Int{big.NewInt(100), big.NewInt(101)}
 	attemptNumber := uint64(2)
 	attemptTimeoutBlock := uint64(1000)
 	attemptMemberIndexes := memberIndexes[:groupParameters.HonestThreshold]
-	result := &signing.Result{
-		Signature: &tecdsa.Signature{
-			R:          big.NewInt(200),
-			S:          big.NewInt(300),
-			RecoveryID: 2,
+	results := []*signing.Result{
+		{
+			Signature: &tecdsa.Signature{
+				R:          big.NewInt(200),
+				S:          big.NewInt(300),
+				RecoveryID: 2,
+			},
+		},
+		{
+			Signature: &tecdsa.Signature{
+				R:          big.NewInt(201),
+				S:          big.NewInt(301),
+				RecoveryID: 1,
+			},
 		},
 	}
 
 	type outcome struct {
 		memberIndex group.MemberIndex
-		result      *signing.Result
+		results     []*signing.Result
 		endBlock    uint64
 		err         error
 	}
@@ -70,7 +79,7 @@ func TestSigningDoneCheck(t *testing.T) {
 
 			doneCheck.listen(
 				ctx,
-				message,
+				messages,
 				attemptNumber,
 				attemptTimeoutBlock,
 				attemptMemberIndexes,
@@ -80,9 +89,9 @@ func TestSigningDoneCheck(t *testing.T) {
 				err := doneCheck.signalDone(
 					ctx,
 					memberIndex,
-					message,
+					messages,
 					attemptNumber,
-					result,
+					results,
 					500+uint64(memberIndex),
 				)
 				if err != nil {
@@ -91,11 +100,11 @@ func TestSigningDoneCheck(t *testing.T) {
 				}
 			}
 
-			result, endBlock, err := doneCheck.waitUntilAllDone(ctx)
+			results, endBlock, err := doneCheck.waitUntilAllDone(ctx)
 
 			outcomesChan <- &outcome{
 				memberIndex: memberIndex,
-				result:      result,
+				results:     results,
 				endBlock:    endBlock,
 				err:         err,
 			}
@@ -117,19 +126,25 @@ func TestSigningDoneCheck(t *testing.T) {
 			)
 		}
 
-		if outcome.result == nil {
-			t.Errorf("unexpected nil result")
-		}
+		testutils.AssertIntsEqual(
+			t,
+			fmt.Sprintf("results count for member [%v]", outcome.memberIndex),
+			len(results),
+			len(outcome.results),
+		)
 
-		if !result.Signature.Equals(outcome.result.Signature) {
-			t.Errorf(
-				"unexpected signature for member [%v]\n"+
-					"expected: [%v]\n"+
-					"actual:   [%v]",
-				outcome.memberIndex,
-				result.Signature,
-				outcome.result.Signature,
-			)
+		for i, result := range outcome.results {
+			if !results[i].Signature.Equals(result.Signature) {
+				t.Errorf(
+					"unexpected signature [%v] for member [%v]\n"+
+						"expected: [%v]\n"+
+						"actual:   [%v]",
+					i,
+					outcome.memberIndex,
+					results[i].Signature,
+					result.Signature,
+				)
+			}
 		}
 
 		testutils.AssertIntsEqual(
@@ -161,21 +176,23 @@ func TestSigningDoneCheck_MissingConfirmation(t *testing.T) {
 	ctx, cancelCtx := context.WithTimeout(context.Background(), 100*time.Millisecond)
 	defer cancelCtx()
 
-	message := big.NewInt(100)
+	messages := []*big.Int{big.NewInt(100)}
 	attemptNumber := uint64(1)
 	attemptTimeoutBlock := uint64(1000)
 	attemptMemberIndexes := memberIndexes[:groupParameters.HonestThreshold]
-	result := &signing.Result{
-		Signature: &tecdsa.Signature{
-			R:          big.NewInt(200),
-			S:          big.NewInt(300),
-			RecoveryID: 2,
+	results := []*signing.Result{
+		{
+			Signature: &tecdsa.Signature{
+				R:          big.NewInt(200),
+				S:          big.NewInt(300),
+				RecoveryID: 2,
+			},
 		},
 	}
 
 	doneCheck.listen(
 		ctx,
-		message,
+		messages,
 		attemptNumber,
 		attemptTimeoutBlock,
 		attemptMemberIndexes,
@@ -185,9 +202,9 @@ func TestSigningDoneCheck_MissingConfirmation(t *testing.T) {
 		err := doneCheck.signalDone(
 			ctx,
 			uint8(i),
-			message,
+			messages,
 			attemptNumber,
-			result,
+			results,
 			100,
 		)
 		if err != nil {
@@ -204,6 +221,73 @@ func TestSigningDoneCheck_MissingConfirmation(t *testing.T) {
 	testutils.AssertErrorsSame(t, errWaitDoneTimedOut, err)
 }
 
+// TestSigningDoneCheck_AnotherBatch covers scenario when members provided
+// done checks for a batch of messages other than the expected one.
+func TestSigningDoneCheck_AnotherBatch(t *testing.T) {
+	groupParameters := &GroupParameters{
+		GroupSize:       5,
+		GroupQuorum:     4,
+		HonestThreshold: 3,
+	}
+
+	doneCheck := setupSigningDoneCheck(t, groupParameters)
+
+	memberIndexes := make([]group.MemberIndex, doneCheck.groupSize)
+	for i := range memberIndexes {
+		memberIndex := group.MemberIndex(i + 1)
+		memberIndexes[i] = memberIndex
+	}
+
+	ctx, cancelCtx := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancelCtx()
+
+	messages := []*big.Int{big.NewInt(100), big.NewInt(101)}
+	attemptNumber := uint64(1)
+	attemptTimeoutBlock := uint64(1000)
+	attemptMemberIndexes := memberIndexes[:groupParameters.HonestThreshold]
+	results := []*signing.Result{
+		{
+			Signature: &tecdsa.Signature{
+				R:          big.NewInt(200),
+				S:          big.NewInt(300),
+				RecoveryID: 2,
+			},
+		},
+	}
+
+	doneCheck.listen(
+		ctx,
+		messages,
+		attemptNumber,
+		attemptTimeoutBlock,
+		attemptMemberIndexes,
+	)
+
+	// All members provide done checks covering just the first message
+	// of the batch.
+	for _, memberIndex := range attemptMemberIndexes {
+		err := doneCheck.signalDone(
+			ctx,
+			memberIndex,
+			messages[:1],
+			attemptNumber,
+			results,
+			100,
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	returnedResults, endBlock, err := doneCheck.waitUntilAllDone(ctx)
+
+	if returnedResults != nil {
+		t.Errorf("expected nil results, has [%v]", returnedResults)
+	}
+	testutils.AssertIntsEqual(t, "end block", 0, int(endBlock))
+	testutils.AssertErrorsSame(t, errWaitDoneTimedOut, err)
+}
+
 // TestSigningDoneCheck_AnotherSignature covers scenario when one member
 // did provide signature other than other members.
 func TestSigningDoneCheck_AnotherSignature(t *testing.T) {
@@ -224,28 +308,32 @@ func TestSigningDoneCheck_AnotherSignature(t *testing.T) {
 	ctx, cancelCtx := context.WithCancel(context.Background())
 	defer cancelCtx()
 
-	message := big.NewInt(100)
+	messages := []*big.Int{big.NewInt(100)}
 	attemptNumber := uint64(1)
 	attemptTimeoutBlock := uint64(1000)
 	attemptMemberIndexes := memberIndexes[:groupParameters.HonestThreshold]
-	correctResult := &signing.Result{
-		Signature: &tecdsa.Signature{
-			R:          big.NewInt(200),
-			S:          big.NewInt(300),
-			RecoveryID: 2,
+	correctResults := []*signing.Result{
+		{
+			Signature: &tecdsa.Signature{
+				R:          big.NewInt(200),
+				S:          big.NewInt(300),
+				RecoveryID: 2,
+			},
 		},
 	}
-	incorrectResult := &signing.Result{
-		Signature: &tecdsa.Signature{
-			R:          big.NewInt(201),
-			S:          big.NewInt(300),
-			RecoveryID: 2,
+	incorrectResults := []*signing.Result{
+		{
+			Signature: &tecdsa.Signature{
+				R:          big.NewInt(201),
+				S:          big.NewInt(300),
+				RecoveryID: 2,
+			},
 		},
 	}
 
 	doneCheck.listen(
 		ctx,
-		message,
+		messages,
 		attemptNumber,
 		attemptTimeoutBlock,
 		attemptMemberIndexes,
@@ -256,9 +344,9 @@ func TestSigningDoneCheck_AnotherSignature(t *testing.T) {
 		err := doneCheck.signalDone(
 			ctx,
 			uint8(i),
-			message,
+			messages,
 			attemptNumber,
-			correctResult,
+			correctResults,
 			100,
 		)
 		if err != nil {
@@ -270,9 +358,9 @@ func TestSigningDoneCheck_AnotherSignature(t *testing.T) {
 	err := doneCheck.signalDone(
 		ctx,
 		uint8(groupParameters.HonestThreshold),
-		message,
+		messages,
 		attemptNumber,
-		incorrectResult,
+		incorrectResults,
 		100,
 	)
 	if err != nil {
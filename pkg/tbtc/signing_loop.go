@@ -19,6 +19,8 @@ import (
 	"golang.org/x/exp/slices"
 )
 
+// Default durations of the signing attempt phases. These are the signing
+// timings of the first version of DefaultTimingSchedule.
 const (
 	// signingAttemptAnnouncementDelayBlocks determines the duration of the
 	// announcement phase delay that is preserved before starting the
@@ -36,17 +38,8 @@ const (
 	signingAttemptCoolDownBlocks = 5
 )
 
-// signingAttemptMaximumBlocks returns the maximum block duration of a single
-// signing attempt.
-func signingAttemptMaximumBlocks() uint {
-	return signingAttemptAnnouncementDelayBlocks +
-		signingAttemptAnnouncementActiveBlocks +
-		signingAttemptMaximumProtocolBlocks +
-		signingAttemptCoolDownBlocks
-}
-
 // signingAnnouncer represents a component responsible for exchanging readiness
-// announcements for the given signing attempt of the given message.
+// announcements for the given signing attempt of the given messages batch.
 type signingAnnouncer interface {
 	Announce(
 		ctx context.Context,
@@ -60,7 +53,7 @@ type signingAnnouncer interface {
 type signingDoneCheckStrategy interface {
 	listen(
 		ctx context.Context,
-		message *big.Int,
+		messages []*big.Int,
 		attemptNumber uint64,
 		attemptTimeoutBlock uint64,
 		attemptMembersIndexes []group.MemberIndex,
@@ -69,25 +62,51 @@ type signingDoneCheckStrategy interface {
 	signalDone(
 		ctx context.Context,
 		memberIndex group.MemberIndex,
-		message *big.Int,
+		messages []*big.Int,
 		attemptNumber uint64,
-		result *signing.Result,
+		results []*signing.Result,
 		endBlock uint64,
 	) error
 
-	waitUntilAllDone(ctx context.Context) (*signing.Result, uint64, error)
+	waitUntilAllDone(ctx context.Context) ([]*signing.Result, uint64, error)
+}
+
+// signingBatchID returns an identifier of the given batch of messages. If the
+// batch consists of a single message, the identifier is the message itself.
+// Otherwise, the identifier is the hash of all messages of the batch.
+func signingBatchID(messages []*big.Int) *big.Int {
+	if len(messages) == 1 {
+		return messages[0]
+	}
+
+	hash := sha256.New()
+	for _, message := range messages {
+		messageBytes := message.Bytes()
+		// Prefix each message with its length to make the resulting
+		// concatenation of messages unambiguous.
+		lengthBytes := make([]byte, 4)
+		binary.BigEndian.PutUint32(lengthBytes, uint32(len(messageBytes)))
+		hash.Write(lengthBytes)
+		hash.Write(messageBytes)
+	}
+
+	return new(big.Int).SetBytes(hash.Sum(nil))
 }
 
 // signingRetryLoop is a struct that encapsulates the signing retry logic.
+// A single retry loop signs a batch of messages. All messages of the batch
+// are signed within the same attempts.
 type signingRetryLoop struct {
 	logger log.StandardLogger
 
-	message *big.Int
+	messages []*big.Int
+	batchID  *big.Int
 
 	signingGroupMemberIndex group.MemberIndex
 	signingGroupOperators   chain.Addresses
 
 	groupParameters *GroupParameters
+	timing          *TimingScheduleVersion
 
 	announcer signingAnnouncer
 
@@ -96,31 +115,40 @@ type signingRetryLoop struct {
 	attemptSeed       int64
 
 	doneCheck signingDoneCheckStrategy
+
+	// attemptRecorder is optional and used for recording outcomes and
+	// phase timings of signing attempts.
+	attemptRecorder func(attempt *JournalAttemptTiming, attemptErr error)
 }
 
 func newSigningRetryLoop(
 	logger log.StandardLogger,
-	message *big.Int,
+	messages []*big.Int,
 	initialStartBlock uint64,
 	signingGroupMemberIndex group.MemberIndex,
 	signingGroupOperators chain.Addresses,
 	groupParameters *GroupParameters,
+	timing *TimingScheduleVersion,
 	announcer signingAnnouncer,
 	doneCheck signingDoneCheckStrategy,
 ) *signingRetryLoop {
+	batchID := signingBatchID(messages)
+
 	// Compute the 8-byte seed needed for the random retry algorithm. We take
-	// the first 8 bytes of the hash of the signed message. This allows us to
-	// not care in this piece of the code about the length of the message and
-	// how this message is proposed.
-	messageSha256 := sha256.Sum256(message.Bytes())
-	attemptSeed := int64(binary.BigEndian.Uint64(messageSha256[:8]))
+	// the first 8 bytes of the hash of the signed messages batch identifier.
+	// This allows us to not care in this piece of the code about the length
+	// of the message and how this message is proposed.
+	batchIDSha256 := sha256.Sum256(batchID.Bytes())
+	attemptSeed := int64(binary.BigEndian.Uint64(batchIDSha256[:8]))
 
 	return &signingRetryLoop{
 		logger:                  logger,
-		message:                 message,
+		messages:                messages,
+		batchID:                 batchID,
 		signingGroupMemberIndex: signingGroupMemberIndex,
 		signingGroupOperators:   signingGroupOperators,
 		groupParameters:         groupParameters,
+		timing:                  timing,
 		announcer:               announcer,
 		attemptCounter:          0,
 		attemptStartBlock:       initialStartBlock,
@@ -137,8 +165,9 @@ type signingAttemptParams struct {
 	excludedMembersIndexes []group.MemberIndex
 }
 
-// signingAttemptFn represents a function performing a signing attempt.
-type signingAttemptFn func(*signingAttemptParams) (*signing.Result, uint64, error)
+// signingAttemptFn represents a function performing a signing attempt. The
+// function must return results in the order of the signed messages.
+type signingAttemptFn func(*signingAttemptParams) ([]*signing.Result, uint64, error)
 
 // signingActivityReport holds information about the activity of the signing
 // group members during the signing process.
@@ -149,8 +178,9 @@ type signingActivityReport struct {
 
 // signingRetryLoopResult represents the result of the signing retry loop.
 type signingRetryLoopResult struct {
-	// result is the outcome of the signing process.
-	result *signing.Result
+	// results are the outcomes of the signing process, in the order of
+	// the signed messages.
+	results []*signing.Result
 	// activityReport holds information about the activity of the signing
 	// group members during the signing process.
 	activityReport *signingActivityReport
@@ -164,10 +194,11 @@ type signingRetryLoopResult struct {
 }
 
 // start begins the signing retry loop using the given signing attempt function.
-// The retry loop terminates when the signing result is produced or the ctx
-// parameter is done, whatever comes first. The signing result is produced
+// The retry loop terminates when the signing results are produced or the ctx
+// parameter is done, whatever comes first. The signing results are produced
 // only if all signers who participated in signing confirmed they are done
-// by sending a valid `signingDoneMessage` during the signing done check phase.
+// with the whole batch by sending a valid `signingDoneMessage` during the
+// signing done check phase.
 func (srl *signingRetryLoop) start(
 	ctx context.Context,
 	waitForBlockFn waitForBlockFn,
@@ -192,14 +223,14 @@ func (srl *signingRetryLoop) start(
 		// by some additional delay blocks. We need a small cool down in
 		// order to mitigate all corner cases where the actual attempt duration
 		// was slightly longer than the expected duration determined by the
-		// signingAttemptMaximumProtocolBlocks constant.
+		// maximum protocol duration of the signing timing.
 		//
 		// For example, the attempt may fail at the end of the protocol but the
 		// error is returned after some time and more blocks than expected are
 		// mined in the meantime.
 		if srl.attemptCounter > 1 {
 			srl.attemptStartBlock = srl.attemptStartBlock +
-				uint64(signingAttemptMaximumBlocks())
+				srl.timing.Signing.MaximumBlocks()
 		}
 
 		srl.logger.Infof(
@@ -208,8 +239,27 @@ func (srl *signingRetryLoop) start(
 			srl.attemptCounter,
 		)
 
-		announcementStartBlock := srl.attemptStartBlock + signingAttemptAnnouncementDelayBlocks
-		announcementEndBlock := announcementStartBlock + signingAttemptAnnouncementActiveBlocks
+		announcementStartBlock := srl.attemptStartBlock +
+			srl.timing.Signing.AnnouncementDelayBlocks
+		announcementEndBlock := announcementStartBlock +
+			srl.timing.Signing.AnnouncementActiveBlocks
+		timeoutBlock := announcementEndBlock +
+			srl.timing.Signing.MaximumProtocolBlocks
+
+		attemptTiming := &JournalAttemptTiming{
+			Protocol: "signing",
+			SessionID: fmt.Sprintf(
+				"%v-%v",
+				srl.batchID.Text(16),
+				srl.attemptCounter,
+			),
+			MemberIndex:        srl.signingGroupMemberIndex,
+			AttemptNumber:      srl.attemptCounter,
+			TimingVersion:      srl.timing.Version,
+			StartBlock:         srl.attemptStartBlock,
+			ProtocolStartBlock: announcementEndBlock,
+			TimeoutBlock:       timeoutBlock,
+		}
 
 		currentBlock, err := getCurrentBlockFn()
 		if err != nil {
@@ -260,7 +310,7 @@ func (srl *signingRetryLoop) start(
 		readyMembersIndexes, err := srl.announcer.Announce(
 			announceCtx,
 			srl.signingGroupMemberIndex,
-			fmt.Sprintf("%v-%v", srl.message, srl.attemptCounter),
+			fmt.Sprintf("%v-%v", srl.batchID, srl.attemptCounter),
 		)
 		if err != nil {
 			srl.logger.Warnf(
@@ -270,6 +320,7 @@ func (srl *signingRetryLoop) start(
 				srl.attemptCounter,
 				err,
 			)
+			srl.recordAttempt(attemptTiming, AttemptNotReady, err)
 			continue
 		}
 
@@ -305,6 +356,7 @@ func (srl *signingRetryLoop) start(
 				len(readyMembersIndexes),
 				unreadyMembersIndexes,
 			)
+			srl.recordAttempt(attemptTiming, AttemptNotReady, nil)
 			continue
 		}
 
@@ -332,8 +384,6 @@ func (srl *signingRetryLoop) start(
 			srl.signingGroupMemberIndex,
 		)
 
-		timeoutBlock := announcementEndBlock + signingAttemptMaximumProtocolBlocks
-
 		// doneCheckTimeoutCtx is active until the timeout even if the protocol
 		// completed successfully earlier. This is needed to ensure all protocol
 		// participants have a chance to receive signingDoneMessage.
@@ -341,12 +391,14 @@ func (srl *signingRetryLoop) start(
 
 		srl.doneCheck.listen(
 			doneCheckTimeoutCtx,
-			srl.message,
+			srl.messages,
 			uint64(srl.attemptCounter),
 			timeoutBlock,
 			includedMembersIndexes,
 		)
 
+		attemptTiming.Participated = !attemptSkipped
+
 		if !attemptSkipped {
 			srl.logger.Infof(
 				"[member:%v] eligible for attempt [%v]",
@@ -354,7 +406,7 @@ func (srl *signingRetryLoop) start(
 				srl.attemptCounter,
 			)
 
-			result, endBlock, err := signingAttemptFn(&signingAttemptParams{
+			results, endBlock, err := signingAttemptFn(&signingAttemptParams{
 				number:                 srl.attemptCounter,
 				startBlock:             announcementEndBlock,
 				timeoutBlock:           timeoutBlock,
@@ -368,9 +420,17 @@ func (srl *signingRetryLoop) start(
 					srl.attemptCounter,
 					err,
 				)
+				if srl.attemptRecorder != nil {
+					if currentBlock, blockErr := getCurrentBlockFn(); blockErr == nil {
+						attemptTiming.ProtocolEndBlock = currentBlock
+					}
+				}
+				srl.recordAttempt(attemptTiming, AttemptFailed, err)
 				continue
 			}
 
+			attemptTiming.ProtocolEndBlock = endBlock
+
 			srl.logger.Infof(
 				"[member:%v] exchanging signing done checks for attempt [%v]",
 				srl.signingGroupMemberIndex,
@@ -380,9 +440,9 @@ func (srl *signingRetryLoop) start(
 			err = srl.doneCheck.signalDone(
 				doneCheckTimeoutCtx,
 				srl.signingGroupMemberIndex,
-				srl.message,
+				srl.messages,
 				uint64(srl.attemptCounter),
-				result,
+				results,
 				endBlock,
 			)
 			if err != nil {
@@ -393,6 +453,7 @@ func (srl *signingRetryLoop) start(
 					srl.attemptCounter,
 					err,
 				)
+				srl.recordAttempt(attemptTiming, AttemptFailed, err)
 				continue
 			}
 		} else {
@@ -404,7 +465,7 @@ func (srl *signingRetryLoop) start(
 			)
 		}
 
-		result, latestEndBlock, err := srl.doneCheck.waitUntilAllDone(doneCheckTimeoutCtx)
+		results, latestEndBlock, err := srl.doneCheck.waitUntilAllDone(doneCheckTimeoutCtx)
 		if err != nil {
 			srl.logger.Warnf(
 				"[member:%v] cannot wait for signing done "+
@@ -413,16 +474,20 @@ func (srl *signingRetryLoop) start(
 				srl.attemptCounter,
 				err,
 			)
+			srl.recordAttempt(attemptTiming, AttemptFailed, err)
 			continue
 		}
 
+		attemptTiming.DoneBlock = latestEndBlock
+		srl.recordAttempt(attemptTiming, AttemptSucceeded, nil)
+
 		activityReport := &signingActivityReport{
 			activeMembers:   readyMembersIndexes,
 			inactiveMembers: unreadyMembersIndexes,
 		}
 
 		return &signingRetryLoopResult{
-			result:              result,
+			results:             results,
 			activityReport:      activityReport,
 			latestEndBlock:      latestEndBlock,
 			attemptTimeoutBlock: timeoutBlock,
@@ -430,6 +495,21 @@ func (srl *signingRetryLoop) start(
 	}
 }
 
+// recordAttempt records the given outcome of the given attempt, if the attempt
+// recorder is set.
+func (srl *signingRetryLoop) recordAttempt(
+	attempt *JournalAttemptTiming,
+	outcome string,
+	attemptErr error,
+) {
+	if srl.attemptRecorder == nil {
+		return
+	}
+
+	attempt.Outcome = outcome
+	srl.attemptRecorder(attempt, attemptErr)
+}
+
 // performMembersSelection runs the member selection process whose result
 // is a list of members' indexes that should be excluded by the client
 // for the given signing attempt.
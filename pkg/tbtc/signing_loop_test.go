@@ -18,6 +18,7 @@ import (
 
 func TestSigningRetryLoop(t *testing.T) {
 	message := big.NewInt(100)
+	messages := []*big.Int{message}
 
 	groupParameters := &GroupParameters{
 		GroupSize:       10,
@@ -52,6 +53,7 @@ func TestSigningRetryLoop(t *testing.T) {
 			RecoveryID: 2,
 		},
 	}
+	testResults := []*signing.Result{testResult}
 
 	var tests = map[string]struct {
 		signingGroupMemberIndex     group.MemberIndex
@@ -59,7 +61,7 @@ func TestSigningRetryLoop(t *testing.T) {
 		currentBlockFn              getCurrentBlockFn
 		incomingAnnouncementsFn     func(sessionID string) ([]group.MemberIndex, error)
 		signingAttemptFn            signingAttemptFn
-		waitUntilAllDoneOutcomeFn   func(attemptNumber uint64) (*signing.Result, uint64, error)
+		waitUntilAllDoneOutcomeFn   func(attemptNumber uint64) ([]*signing.Result, uint64, error)
 		expectedOutgoingDoneChecks  []*signingDoneMessage
 		expectedErr                 error
 		expectedResult              *signingRetryLoopResult
@@ -81,26 +83,26 @@ func TestSigningRetryLoop(t *testing.T) {
 			},
 			signingAttemptFn: func(
 				attempt *signingAttemptParams,
-			) (*signing.Result, uint64, error) {
-				return testResult, 215, nil // an arbitrary end block
+			) ([]*signing.Result, uint64, error) {
+				return testResults, 215, nil // an arbitrary end block
 			},
-			waitUntilAllDoneOutcomeFn: func(attemptNumber uint64) (*signing.Result, uint64, error) {
+			waitUntilAllDoneOutcomeFn: func(attemptNumber uint64) ([]*signing.Result, uint64, error) {
 				// Simulate that the done check phase determines the same
 				// end block as the executing signer.
-				return testResult, 215, nil
+				return testResults, 215, nil
 			},
 			expectedOutgoingDoneChecks: []*signingDoneMessage{
 				{
 					senderID:      1,
-					message:       message,
+					messages:      messages,
 					attemptNumber: 1,
-					signature:     testResult.Signature,
+					signatures:    []*tecdsa.Signature{testResult.Signature},
 					endBlock:      215,
 				},
 			},
 			expectedErr: nil,
 			expectedResult: &signingRetryLoopResult{
-				result: testResult,
+				results: testResults,
 				activityReport: &signingActivityReport{
 					activeMembers:   signingGroupMembersIndexes,
 					inactiveMembers: []group.MemberIndex{},
@@ -135,26 +137,26 @@ func TestSigningRetryLoop(t *testing.T) {
 			},
 			signingAttemptFn: func(
 				attempt *signingAttemptParams,
-			) (*signing.Result, uint64, error) {
-				return testResult, 215, nil // an arbitrary end block
+			) ([]*signing.Result, uint64, error) {
+				return testResults, 215, nil // an arbitrary end block
 			},
-			waitUntilAllDoneOutcomeFn: func(attemptNumber uint64) (*signing.Result, uint64, error) {
+			waitUntilAllDoneOutcomeFn: func(attemptNumber uint64) ([]*signing.Result, uint64, error) {
 				// Simulate that the done check phase determines the same
 				// end block as the executing signer.
-				return testResult, 215, nil
+				return testResults, 215, nil
 			},
 			expectedOutgoingDoneChecks: []*signingDoneMessage{
 				{
 					senderID:      1,
-					message:       message,
+					messages:      messages,
 					attemptNumber: 1,
-					signature:     testResult.Signature,
+					signatures:    []*tecdsa.Signature{testResult.Signature},
 					endBlock:      215,
 				},
 			},
 			expectedErr: nil,
 			expectedResult: &signingRetryLoopResult{
-				result: testResult,
+				results: testResults,
 				activityReport: &signingActivityReport{
 					activeMembers:   []group.MemberIndex{1, 2, 3, 6, 7, 9},
 					inactiveMembers: []group.MemberIndex{4, 5, 8, 10},
@@ -193,26 +195,26 @@ func TestSigningRetryLoop(t *testing.T) {
 			},
 			signingAttemptFn: func(
 				attempt *signingAttemptParams,
-			) (*signing.Result, uint64, error) {
-				return testResult, 260, nil // an arbitrary end block
+			) ([]*signing.Result, uint64, error) {
+				return testResults, 260, nil // an arbitrary end block
 			},
-			waitUntilAllDoneOutcomeFn: func(attemptNumber uint64) (*signing.Result, uint64, error) {
+			waitUntilAllDoneOutcomeFn: func(attemptNumber uint64) ([]*signing.Result, uint64, error) {
 				// Simulate that the done check phase determines the same
 				// end block as the executing signer.
-				return testResult, 260, nil
+				return testResults, 260, nil
 			},
 			expectedOutgoingDoneChecks: []*signingDoneMessage{
 				{
 					senderID:      3,
-					message:       message,
+					messages:      messages,
 					attemptNumber: 2,
-					signature:     testResult.Signature,
+					signatures:    []*tecdsa.Signature{testResult.Signature},
 					endBlock:      260,
 				},
 			},
 			expectedErr: nil,
 			expectedResult: &signingRetryLoopResult{
-				result: testResult,
+				results: testResults,
 				activityReport: &signingActivityReport{
 					activeMembers:   signingGroupMembersIndexes,
 					inactiveMembers: []group.MemberIndex{},
@@ -253,26 +255,26 @@ func TestSigningRetryLoop(t *testing.T) {
 			},
 			signingAttemptFn: func(
 				attempt *signingAttemptParams,
-			) (*signing.Result, uint64, error) {
-				return testResult, 260, nil // an arbitrary end block
+			) ([]*signing.Result, uint64, error) {
+				return testResults, 260, nil // an arbitrary end block
 			},
-			waitUntilAllDoneOutcomeFn: func(attemptNumber uint64) (*signing.Result, uint64, error) {
+			waitUntilAllDoneOutcomeFn: func(attemptNumber uint64) ([]*signing.Result, uint64, error) {
 				// Simulate that the done check phase determines the same
 				// end block as the executing signer.
-				return testResult, 260, nil
+				return testResults, 260, nil
 			},
 			expectedOutgoingDoneChecks: []*signingDoneMessage{
 				{
 					senderID:      4,
-					message:       message,
+					messages:      messages,
 					attemptNumber: 2,
-					signature:     testResult.Signature,
+					signatures:    []*tecdsa.Signature{testResult.Signature},
 					endBlock:      260,
 				},
 			},
 			expectedErr: nil,
 			expectedResult: &signingRetryLoopResult{
-				result: testResult,
+				results: testResults,
 				activityReport: &signingActivityReport{
 					activeMembers:   signingGroupMembersIndexes,
 					inactiveMembers: []group.MemberIndex{},
@@ -309,30 +311,30 @@ func TestSigningRetryLoop(t *testing.T) {
 			},
 			signingAttemptFn: func(
 				attempt *signingAttemptParams,
-			) (*signing.Result, uint64, error) {
+			) ([]*signing.Result, uint64, error) {
 				if attempt.number <= 1 {
 					return nil, 0, fmt.Errorf("invalid data")
 				}
 
-				return testResult, 260, nil // an arbitrary end block
+				return testResults, 260, nil // an arbitrary end block
 			},
-			waitUntilAllDoneOutcomeFn: func(attemptNumber uint64) (*signing.Result, uint64, error) {
+			waitUntilAllDoneOutcomeFn: func(attemptNumber uint64) ([]*signing.Result, uint64, error) {
 				// Simulate that the done check phase determines the same
 				// end block as the executing signer.
-				return testResult, 260, nil
+				return testResults, 260, nil
 			},
 			expectedOutgoingDoneChecks: []*signingDoneMessage{
 				{
 					senderID:      4,
-					message:       message,
+					messages:      messages,
 					attemptNumber: 2,
-					signature:     testResult.Signature,
+					signatures:    []*tecdsa.Signature{testResult.Signature},
 					endBlock:      260,
 				},
 			},
 			expectedErr: nil,
 			expectedResult: &signingRetryLoopResult{
-				result: testResult,
+				results: testResults,
 				activityReport: &signingActivityReport{
 					activeMembers:   signingGroupMembersIndexes,
 					inactiveMembers: []group.MemberIndex{},
@@ -369,14 +371,14 @@ func TestSigningRetryLoop(t *testing.T) {
 			},
 			signingAttemptFn: func(
 				attempt *signingAttemptParams,
-			) (*signing.Result, uint64, error) {
+			) ([]*signing.Result, uint64, error) {
 				return nil, 0, fmt.Errorf("invalid data")
 			},
-			waitUntilAllDoneOutcomeFn: func(attemptNumber uint64) (*signing.Result, uint64, error) {
+			waitUntilAllDoneOutcomeFn: func(attemptNumber uint64) ([]*signing.Result, uint64, error) {
 				// Simulate the result and the end block have been determined
 				// by listening for signing done checks.
 				if attemptNumber == 2 {
-					return testResult, 260, nil
+					return testResults, 260, nil
 				}
 
 				panic("undefined behavior")
@@ -384,7 +386,7 @@ func TestSigningRetryLoop(t *testing.T) {
 			expectedOutgoingDoneChecks: nil,
 			expectedErr:                nil,
 			expectedResult: &signingRetryLoopResult{
-				result: testResult,
+				results: testResults,
 				activityReport: &signingActivityReport{
 					activeMembers:   signingGroupMembersIndexes,
 					inactiveMembers: []group.MemberIndex{},
@@ -420,18 +422,18 @@ func TestSigningRetryLoop(t *testing.T) {
 			},
 			signingAttemptFn: func(
 				attempt *signingAttemptParams,
-			) (*signing.Result, uint64, error) {
+			) ([]*signing.Result, uint64, error) {
 				if attempt.number == 1 {
-					return testResult, 215, nil // an arbitrary end block
+					return testResults, 215, nil // an arbitrary end block
 				}
 
 				if attempt.number == 2 {
-					return testResult, 260, nil // an arbitrary end block
+					return testResults, 260, nil // an arbitrary end block
 				}
 
 				panic("undefined behavior")
 			},
-			waitUntilAllDoneOutcomeFn: func(attemptNumber uint64) (*signing.Result, uint64, error) {
+			waitUntilAllDoneOutcomeFn: func(attemptNumber uint64) ([]*signing.Result, uint64, error) {
 				// Fail the done check for the first attempt.
 				if attemptNumber == 1 {
 					return nil, 0, fmt.Errorf("network error")
@@ -439,27 +441,27 @@ func TestSigningRetryLoop(t *testing.T) {
 
 				// Simulate that the done check phase determines the same
 				// end block as the executing signer.
-				return testResult, 260, nil
+				return testResults, 260, nil
 			},
 			expectedOutgoingDoneChecks: []*signingDoneMessage{
 				{
 					senderID:      4,
-					message:       message,
+					messages:      messages,
 					attemptNumber: 1,
-					signature:     testResult.Signature,
+					signatures:    []*tecdsa.Signature{testResult.Signature},
 					endBlock:      215,
 				},
 				{
 					senderID:      4,
-					message:       message,
+					messages:      messages,
 					attemptNumber: 2,
-					signature:     testResult.Signature,
+					signatures:    []*tecdsa.Signature{testResult.Signature},
 					endBlock:      260,
 				},
 			},
 			expectedErr: nil,
 			expectedResult: &signingRetryLoopResult{
-				result: testResult,
+				results: testResults,
 				activityReport: &signingActivityReport{
 					activeMembers:   signingGroupMembersIndexes,
 					inactiveMembers: []group.MemberIndex{},
@@ -499,7 +501,7 @@ func TestSigningRetryLoop(t *testing.T) {
 			},
 			signingAttemptFn: func(
 				attempt *signingAttemptParams,
-			) (*signing.Result, uint64, error) {
+			) ([]*signing.Result, uint64, error) {
 				return nil, 0, fmt.Errorf("invalid data")
 			},
 			expectedErr:                 context.Canceled,
@@ -521,7 +523,7 @@ func TestSigningRetryLoop(t *testing.T) {
 			},
 			signingAttemptFn: func(
 				attempt *signingAttemptParams,
-			) (*signing.Result, uint64, error) {
+			) ([]*signing.Result, uint64, error) {
 				return nil, 0, fmt.Errorf("invalid data")
 			},
 			// The retry loop keeps skipping all attempts because they are all
@@ -549,26 +551,26 @@ func TestSigningRetryLoop(t *testing.T) {
 			},
 			signingAttemptFn: func(
 				attempt *signingAttemptParams,
-			) (*signing.Result, uint64, error) {
-				return testResult, 260, nil // an arbitrary end block
+			) ([]*signing.Result, uint64, error) {
+				return testResults, 260, nil // an arbitrary end block
 			},
-			waitUntilAllDoneOutcomeFn: func(attemptNumber uint64) (*signing.Result, uint64, error) {
+			waitUntilAllDoneOutcomeFn: func(attemptNumber uint64) ([]*signing.Result, uint64, error) {
 				// Simulate that the done check phase determines the same
 				// end block as the executing signer.
-				return testResult, 260, nil
+				return testResults, 260, nil
 			},
 			expectedOutgoingDoneChecks: []*signingDoneMessage{
 				{
 					senderID:      3,
-					message:       message,
+					messages:      messages,
 					attemptNumber: 2,
-					signature:     testResult.Signature,
+					signatures:    []*tecdsa.Signature{testResult.Signature},
 					endBlock:      260,
 				},
 			},
 			expectedErr: nil,
 			expectedResult: &signingRetryLoopResult{
-				result: testResult,
+				results: testResults,
 				activityReport: &signingActivityReport{
 					activeMembers:   signingGroupMembersIndexes,
 					inactiveMembers: []group.MemberIndex{},
@@ -606,11 +608,12 @@ func TestSigningRetryLoop(t *testing.T) {
 
 			retryLoop := newSigningRetryLoop(
 				&testutils.MockLogger{},
-				message,
+				messages,
 				200,
 				test.signingGroupMemberIndex,
 				signingGroupOperators,
 				groupParameters,
+				DefaultTimingSchedule().timingAt(200),
 				announcer,
 				doneCheck,
 			)
@@ -626,7 +629,7 @@ func TestSigningRetryLoop(t *testing.T) {
 					return nil
 				},
 				test.currentBlockFn,
-				func(params *signingAttemptParams) (*signing.Result, uint64, error) {
+				func(params *signingAttemptParams) ([]*signing.Result, uint64, error) {
 					lastExecutedAttempt = params
 					return test.signingAttemptFn(params)
 				},
@@ -700,6 +703,50 @@ func TestSigningRetryLoop(t *testing.T) {
 	}
 }
 
+func TestSigningBatchID(t *testing.T) {
+	var tests = map[string]struct {
+		messages        []*big.Int
+		expectedBatchID *big.Int
+	}{
+		"single message": {
+			messages:        []*big.Int{big.NewInt(100)},
+			expectedBatchID: big.NewInt(100),
+		},
+		"batch of messages": {
+			messages: []*big.Int{big.NewInt(100), big.NewInt(200)},
+			// sha256(0x0000000164_00000001c8)
+			expectedBatchID: func() *big.Int {
+				batchID, _ := new(big.Int).SetString(
+					"22c97f63bbeb566d57948568d55fd2e1a65472ed994f42e8ba1af37e61de98ec",
+					16,
+				)
+				return batchID
+			}(),
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			testutils.AssertBigIntsEqual(
+				t,
+				"batch ID",
+				test.expectedBatchID,
+				signingBatchID(test.messages),
+			)
+		})
+	}
+
+	// The batch ID must depend on the order of messages.
+	reversedBatchID := signingBatchID(
+		[]*big.Int{big.NewInt(200), big.NewInt(100)},
+	)
+	if reversedBatchID.Cmp(
+		signingBatchID([]*big.Int{big.NewInt(100), big.NewInt(200)}),
+	) == 0 {
+		t.Errorf("batch ID must depend on the order of messages")
+	}
+}
+
 type mockSigningAnnouncer struct {
 	// outgoingAnnouncements holds all announcements that are sent by the
 	// announcer.
@@ -725,12 +772,12 @@ func (msa *mockSigningAnnouncer) Announce(
 type mockSigningDoneCheck struct {
 	outgoingDoneChecks        []*signingDoneMessage
 	currentAttemptNumber      uint64
-	waitUntilAllDoneOutcomeFn func(attemptNumber uint64) (*signing.Result, uint64, error)
+	waitUntilAllDoneOutcomeFn func(attemptNumber uint64) ([]*signing.Result, uint64, error)
 }
 
 func (msdc *mockSigningDoneCheck) listen(
 	ctx context.Context,
-	message *big.Int,
+	messages []*big.Int,
 	attemptNumber uint64,
 	attemptTimeoutBlock uint64,
 	attemptMembersIndexes []group.MemberIndex,
@@ -741,22 +788,27 @@ func (msdc *mockSigningDoneCheck) listen(
 func (msdc *mockSigningDoneCheck) signalDone(
 	ctx context.Context,
 	memberIndex group.MemberIndex,
-	message *big.Int,
+	messages []*big.Int,
 	attemptNumber uint64,
-	result *signing.Result,
+	results []*signing.Result,
 	endBlock uint64,
 ) error {
+	signatures := make([]*tecdsa.Signature, len(results))
+	for i, result := range results {
+		signatures[i] = result.Signature
+	}
+
 	msdc.outgoingDoneChecks = append(msdc.outgoingDoneChecks, &signingDoneMessage{
 		senderID:      memberIndex,
-		message:       message,
+		messages:      messages,
 		attemptNumber: attemptNumber,
-		signature:     result.Signature,
+		signatures:    signatures,
 		endBlock:      endBlock,
 	})
 
 	return nil
 }
 
-func (msdc *mockSigningDoneCheck) waitUntilAllDone(ctx context.Context) (*signing.Result, uint64, error) {
+func (msdc *mockSigningDoneCheck) waitUntilAllDone(ctx context.Context) ([]*signing.Result, uint64, error) {
 	return msdc.waitUntilAllDoneOutcomeFn(msdc.currentAttemptNumber)
 }
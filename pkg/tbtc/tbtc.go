@@ -65,6 +65,19 @@ type Config struct {
 	PreParamsGenerationConcurrency int
 	// Concurrency level for key-generation for tECDSA.
 	KeyGenerationConcurrency int
+	// ShadowMode makes the node follow chain events and coordination windows,
+	// generate proposals locally and validate proposals received from
+	// coordination leaders without ever broadcasting messages, signing,
+	// or submitting on-chain transactions.
+	ShadowMode bool
+	// CaptureSigningMessages makes the node capture signing protocol messages
+	// received during signing attempts and persist captures of failed
+	// attempts in the work directory, so they can be replayed offline.
+	CaptureSigningMessages bool
+	// DepositSweepStrategy is the name of the strategy used to select
+	// deposits swept by the deposit sweep proposals this node generates.
+	// If empty, the default strategy is used.
+	DepositSweepStrategy string
 }
 
 // Initialize kicks off the TBTC by initializing internal state, ensuring
@@ -104,7 +117,23 @@ func Initialize(
 		return fmt.Errorf("cannot set up TBTC node: [%v]", err)
 	}
 
-	err = node.runCoordinationLayer(ctx)
+	var coordinationSettings []*coordinationLayerSettings
+	if config.ShadowMode {
+		logger.Warnf(
+			"running in the shadow mode; the node will not broadcast " +
+				"messages, sign, nor submit on-chain transactions",
+		)
+
+		coordinationSettings = append(
+			coordinationSettings,
+			&coordinationLayerSettings{
+				executeCoordinationProcedureFn: executeShadowCoordinationProcedure,
+				processCoordinationResultFn:    processCoordinationResult,
+			},
+		)
+	}
+
+	err = node.runCoordinationLayer(ctx, coordinationSettings...)
 	if err != nil {
 		return fmt.Errorf("cannot run coordination layer: [%w]", err)
 	}
@@ -145,6 +174,19 @@ func Initialize(
 				}
 			},
 		)
+
+		registerOperatorAPI(clientInfo, node)
+	}
+
+	// Nodes running in the shadow mode neither join the sortition pool nor
+	// take part in DKG as it would require submitting on-chain transactions
+	// and broadcasting messages.
+	if config.ShadowMode {
+		_ = chain.OnWalletClosed(func(event *WalletClosedEvent) {
+			go handleWalletClosedEvent(node, deduplicator, event)
+		})
+
+		return nil
 	}
 
 	err = sortition.MonitorPool(
@@ -290,42 +332,50 @@ func Initialize(
 	})
 
 	_ = chain.OnWalletClosed(func(event *WalletClosedEvent) {
-		go func() {
-			if ok := deduplicator.notifyWalletClosed(
-				event.WalletID,
-			); !ok {
-				logger.Warnf(
-					"Wallet closure for wallet with ID [0x%x] at block [%v] "+
-						"has been already processed",
-					event.WalletID,
-					event.BlockNumber,
-				)
-				return
-			}
-
-			logger.Infof(
-				"Wallet with ID [0x%x] has been closed at block [%v]; "+
-					"proceeding with handling wallet closure",
-				event.WalletID,
-				event.BlockNumber,
-			)
-
-			err := node.handleWalletClosure(
-				event.WalletID,
-			)
-			if err != nil {
-				logger.Errorf(
-					"Failure while handling wallet closure with ID [0x%x]: [%v]",
-					event.WalletID,
-					err,
-				)
-			}
-		}()
+		go handleWalletClosedEvent(node, deduplicator, event)
 	})
 
 	return nil
 }
 
+// handleWalletClosedEvent handles the given wallet closed event unless
+// the event has been already processed.
+func handleWalletClosedEvent(
+	node *node,
+	deduplicator *deduplicator,
+	event *WalletClosedEvent,
+) {
+	if ok := deduplicator.notifyWalletClosed(
+		event.WalletID,
+	); !ok {
+		logger.Warnf(
+			"Wallet closure for wallet with ID [0x%x] at block [%v] "+
+				"has been already processed",
+			event.WalletID,
+			event.BlockNumber,
+		)
+		return
+	}
+
+	logger.Infof(
+		"Wallet with ID [0x%x] has been closed at block [%v]; "+
+			"proceeding with handling wallet closure",
+		event.WalletID,
+		event.BlockNumber,
+	)
+
+	err := node.handleWalletClosure(
+		event.WalletID,
+	)
+	if err != nil {
+		logger.Errorf(
+			"Failure while handling wallet closure with ID [0x%x]: [%v]",
+			event.WalletID,
+			err,
+		)
+	}
+}
+
 // enoughPreParamsInPoolPolicy is a policy that enforces the sufficient size
 // of the DKG pre-parameters pool before joining the sortition pool.
 type enoughPreParamsInPoolPolicy struct {
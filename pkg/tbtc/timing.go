@@ -0,0 +1,319 @@
+package tbtc
+
+import (
+	"fmt"
+	"sort"
+)
+
+const (
+	// AttemptSucceeded denotes an attempt that produced the protocol result.
+	AttemptSucceeded = "succeeded"
+	// AttemptFailed denotes an attempt that did not produce the protocol
+	// result before the attempt timeout.
+	AttemptFailed = "failed"
+	// AttemptSkipped denotes an attempt the member was not selected for
+	// and whose outcome is unknown to the member.
+	AttemptSkipped = "skipped"
+	// AttemptNotReady denotes an attempt that did not start because not
+	// enough members announced readiness.
+	AttemptNotReady = "not_ready"
+
+	// attemptNearDeadlineRatio is the fraction of the maximum protocol
+	// duration above which an attempt is reported as close to the deadline.
+	attemptNearDeadlineRatio = 0.8
+)
+
+// AttemptTiming determines block durations of the phases of a single
+// signing or DKG attempt.
+type AttemptTiming struct {
+	// AnnouncementDelayBlocks is the duration of the delay preserved before
+	// the announcement phase.
+	AnnouncementDelayBlocks uint64
+	// AnnouncementActiveBlocks is the duration of the announcement phase
+	// performed at the beginning of each attempt.
+	AnnouncementActiveBlocks uint64
+	// MaximumProtocolBlocks is the maximum duration of the actual protocol
+	// computations.
+	MaximumProtocolBlocks uint64
+	// CoolDownBlocks is the duration of the cool down period preserved
+	// between subsequent attempts.
+	CoolDownBlocks uint64
+}
+
+// MaximumBlocks returns the maximum block duration of a single attempt.
+func (at *AttemptTiming) MaximumBlocks() uint64 {
+	return at.AnnouncementDelayBlocks +
+		at.AnnouncementActiveBlocks +
+		at.MaximumProtocolBlocks +
+		at.CoolDownBlocks
+}
+
+// TimingScheduleVersion holds attempt timings of the signing and DKG
+// protocols in force since the given activation block.
+type TimingScheduleVersion struct {
+	Version         uint
+	ActivationBlock uint64
+	Signing         AttemptTiming
+	DKG             AttemptTiming
+}
+
+// TimingSchedule is a list of timing schedule versions, ordered by their
+// activation blocks. All members of a signing group or a DKG group must use
+// the same attempt timings, so the version is selected based on the start
+// block of the protocol which is common for all members. The version used
+// is the one with the highest activation block not greater than the start
+// block.
+type TimingSchedule []TimingScheduleVersion
+
+// NewTimingSchedule creates a new timing schedule from the given versions.
+// The first version must activate at block 0 so there are timings for every
+// protocol execution. Subsequent versions must have increasing version
+// numbers and activation blocks.
+func NewTimingSchedule(
+	versions ...TimingScheduleVersion,
+) (TimingSchedule, error) {
+	if len(versions) == 0 {
+		return nil, fmt.Errorf("at least one timing version is required")
+	}
+
+	if versions[0].ActivationBlock != 0 {
+		return nil, fmt.Errorf(
+			"first timing version must activate at block 0; "+
+				"has activation block [%v]",
+			versions[0].ActivationBlock,
+		)
+	}
+
+	for i, version := range versions {
+		if version.Signing.AnnouncementActiveBlocks == 0 ||
+			version.Signing.MaximumProtocolBlocks == 0 {
+			return nil, fmt.Errorf(
+				"signing timing of version [%v] has empty phases",
+				version.Version,
+			)
+		}
+
+		if version.DKG.AnnouncementActiveBlocks == 0 ||
+			version.DKG.MaximumProtocolBlocks == 0 {
+			return nil, fmt.Errorf(
+				"DKG timing of version [%v] has empty phases",
+				version.Version,
+			)
+		}
+
+		if i == 0 {
+			continue
+		}
+
+		previous := versions[i-1]
+
+		if version.Version <= previous.Version {
+			return nil, fmt.Errorf(
+				"version [%v] does not follow version [%v]",
+				version.Version,
+				previous.Version,
+			)
+		}
+
+		if version.ActivationBlock <= previous.ActivationBlock {
+			return nil, fmt.Errorf(
+				"activation block [%v] of version [%v] is not after "+
+					"activation block [%v] of version [%v]",
+				version.ActivationBlock,
+				version.Version,
+				previous.ActivationBlock,
+				previous.Version,
+			)
+		}
+	}
+
+	return versions, nil
+}
+
+// timingAt returns the timing version in force for a protocol started at
+// the given block.
+func (ts TimingSchedule) timingAt(startBlock uint64) *TimingScheduleVersion {
+	selected := ts[0]
+	for _, version := range ts[1:] {
+		if startBlock < version.ActivationBlock {
+			break
+		}
+		selected = version
+	}
+
+	return &selected
+}
+
+// DefaultTimingSchedule returns the attempt timing schedule of the tBTC
+// protocol. Protocol-wide timing changes are introduced as new versions
+// appended to this schedule. All operators must upgrade to a binary
+// containing the new version before its activation block is reached.
+func DefaultTimingSchedule() TimingSchedule {
+	schedule, err := NewTimingSchedule(
+		TimingScheduleVersion{
+			Version:         1,
+			ActivationBlock: 0,
+			Signing: AttemptTiming{
+				AnnouncementDelayBlocks:  signingAttemptAnnouncementDelayBlocks,
+				AnnouncementActiveBlocks: signingAttemptAnnouncementActiveBlocks,
+				MaximumProtocolBlocks:    signingAttemptMaximumProtocolBlocks,
+				CoolDownBlocks:           signingAttemptCoolDownBlocks,
+			},
+			DKG: AttemptTiming{
+				AnnouncementDelayBlocks:  dkgAttemptAnnouncementDelayBlocks,
+				AnnouncementActiveBlocks: dkgAttemptAnnouncementActiveBlocks,
+				MaximumProtocolBlocks:    dkgAttemptMaximumProtocolBlocks,
+				CoolDownBlocks:           dkgAttemptCoolDownBlocks,
+			},
+		},
+	)
+	if err != nil {
+		// The default schedule is static so this can happen only if
+		// the schedule is broken by a programming error.
+		panic(fmt.Sprintf("invalid default timing schedule: [%v]", err))
+	}
+
+	return schedule
+}
+
+// AttemptTimingSummary summarizes timings of attempts of the given protocol
+// executed with the given timing version.
+type AttemptTimingSummary struct {
+	Protocol      string `json:"protocol"`
+	TimingVersion uint   `json:"timing_version"`
+	// MaximumProtocolBlocks is the maximum protocol duration of the
+	// summarized attempts.
+	MaximumProtocolBlocks uint64 `json:"maximum_protocol_blocks"`
+
+	Attempts  int `json:"attempts"`
+	Succeeded int `json:"succeeded"`
+	Failed    int `json:"failed"`
+	Skipped   int `json:"skipped"`
+	NotReady  int `json:"not_ready"`
+	// TimedOut is the number of failed attempts that ended at or after
+	// the attempt timeout block.
+	TimedOut int `json:"timed_out"`
+
+	// Protocol durations, in blocks, of the succeeded attempts the member
+	// participated in. Zero if there are no such attempts.
+	MinProtocolBlocks    uint64 `json:"min_protocol_blocks"`
+	MedianProtocolBlocks uint64 `json:"median_protocol_blocks"`
+	MaxProtocolBlocks    uint64 `json:"max_protocol_blocks"`
+	// MinMarginBlocks is the smallest number of blocks left to the attempt
+	// timeout among the succeeded attempts the member participated in.
+	MinMarginBlocks uint64 `json:"min_margin_blocks"`
+	// NearDeadline is the number of succeeded attempts that took more than
+	// 80% of the maximum protocol duration.
+	NearDeadline int `json:"near_deadline"`
+}
+
+// AttemptTimingReport summarizes timings of signing and DKG attempts
+// recorded in the audit journal. The report shows how close attempts come
+// to their deadlines and helps to justify changes of the timing schedule.
+type AttemptTimingReport struct {
+	Summaries []*AttemptTimingSummary `json:"summaries"`
+}
+
+// NewAttemptTimingReport builds an attempt timing report from the given
+// audit journal entries. Entries other than attempt entries are ignored.
+func NewAttemptTimingReport(entries []*JournalEntry) *AttemptTimingReport {
+	type summaryKey struct {
+		protocol      string
+		timingVersion uint
+	}
+
+	summaries := make(map[summaryKey]*AttemptTimingSummary)
+	protocolBlocks := make(map[summaryKey][]uint64)
+
+	for _, entry := range entries {
+		attempt := entry.Attempt
+		if entry.Type != JournalAttempt || attempt == nil {
+			continue
+		}
+
+		key := summaryKey{attempt.Protocol, attempt.TimingVersion}
+
+		summary, ok := summaries[key]
+		if !ok {
+			summary = &AttemptTimingSummary{
+				Protocol:      attempt.Protocol,
+				TimingVersion: attempt.TimingVersion,
+			}
+			summaries[key] = summary
+		}
+
+		maximumProtocolBlocks := attempt.TimeoutBlock - attempt.ProtocolStartBlock
+		if maximumProtocolBlocks > summary.MaximumProtocolBlocks {
+			summary.MaximumProtocolBlocks = maximumProtocolBlocks
+		}
+
+		summary.Attempts++
+
+		switch attempt.Outcome {
+		case AttemptSucceeded:
+			summary.Succeeded++
+		case AttemptFailed:
+			summary.Failed++
+			if attempt.ProtocolEndBlock >= attempt.TimeoutBlock {
+				summary.TimedOut++
+			}
+		case AttemptSkipped:
+			summary.Skipped++
+		case AttemptNotReady:
+			summary.NotReady++
+		}
+
+		if attempt.Outcome != AttemptSucceeded || !attempt.Participated {
+			continue
+		}
+
+		endBlock := attempt.ProtocolEndBlock
+		if attempt.DoneBlock > endBlock {
+			endBlock = attempt.DoneBlock
+		}
+
+		blocks := uint64(0)
+		if endBlock > attempt.ProtocolStartBlock {
+			blocks = endBlock - attempt.ProtocolStartBlock
+		}
+
+		protocolBlocks[key] = append(protocolBlocks[key], blocks)
+
+		if float64(blocks) > attemptNearDeadlineRatio*float64(maximumProtocolBlocks) {
+			summary.NearDeadline++
+		}
+	}
+
+	report := &AttemptTimingReport{
+		Summaries: make([]*AttemptTimingSummary, 0, len(summaries)),
+	}
+
+	for key, summary := range summaries {
+		blocks := protocolBlocks[key]
+		if len(blocks) > 0 {
+			sort.Slice(blocks, func(i, j int) bool {
+				return blocks[i] < blocks[j]
+			})
+
+			summary.MinProtocolBlocks = blocks[0]
+			summary.MedianProtocolBlocks = blocks[len(blocks)/2]
+			summary.MaxProtocolBlocks = blocks[len(blocks)-1]
+
+			if summary.MaximumProtocolBlocks > summary.MaxProtocolBlocks {
+				summary.MinMarginBlocks =
+					summary.MaximumProtocolBlocks - summary.MaxProtocolBlocks
+			}
+		}
+
+		report.Summaries = append(report.Summaries, summary)
+	}
+
+	sort.Slice(report.Summaries, func(i, j int) bool {
+		if report.Summaries[i].Protocol != report.Summaries[j].Protocol {
+			return report.Summaries[i].Protocol < report.Summaries[j].Protocol
+		}
+		return report.Summaries[i].TimingVersion < report.Summaries[j].TimingVersion
+	})
+
+	return report
+}
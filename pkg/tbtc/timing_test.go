@@ -0,0 +1,249 @@
+package tbtc
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/keep-network/keep-core/internal/testutils"
+)
+
+func TestNewTimingSchedule(t *testing.T) {
+	timing := AttemptTiming{
+		AnnouncementDelayBlocks:  1,
+		AnnouncementActiveBlocks: 5,
+		MaximumProtocolBlocks:    30,
+		CoolDownBlocks:           5,
+	}
+
+	tests := map[string]struct {
+		versions      []TimingScheduleVersion
+		expectedError bool
+	}{
+		"valid schedule": {
+			versions: []TimingScheduleVersion{
+				{Version: 1, ActivationBlock: 0, Signing: timing, DKG: timing},
+				{Version: 2, ActivationBlock: 100, Signing: timing, DKG: timing},
+			},
+		},
+		"no versions": {
+			versions:      nil,
+			expectedError: true,
+		},
+		"first version not active from genesis": {
+			versions: []TimingScheduleVersion{
+				{Version: 1, ActivationBlock: 100, Signing: timing, DKG: timing},
+			},
+			expectedError: true,
+		},
+		"empty signing timing": {
+			versions: []TimingScheduleVersion{
+				{Version: 1, ActivationBlock: 0, DKG: timing},
+			},
+			expectedError: true,
+		},
+		"empty DKG timing": {
+			versions: []TimingScheduleVersion{
+				{Version: 1, ActivationBlock: 0, Signing: timing},
+			},
+			expectedError: true,
+		},
+		"non-increasing version": {
+			versions: []TimingScheduleVersion{
+				{Version: 1, ActivationBlock: 0, Signing: timing, DKG: timing},
+				{Version: 1, ActivationBlock: 100, Signing: timing, DKG: timing},
+			},
+			expectedError: true,
+		},
+		"non-increasing activation block": {
+			versions: []TimingScheduleVersion{
+				{Version: 1, ActivationBlock: 0, Signing: timing, DKG: timing},
+				{Version: 2, ActivationBlock: 100, Signing: timing, DKG: timing},
+				{Version: 3, ActivationBlock: 100, Signing: timing, DKG: timing},
+			},
+			expectedError: true,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			_, err := NewTimingSchedule(test.versions...)
+
+			testutils.AssertBoolsEqual(
+				t,
+				"error",
+				test.expectedError,
+				err != nil,
+			)
+		})
+	}
+}
+
+func TestTimingSchedule_TimingAt(t *testing.T) {
+	timing := AttemptTiming{
+		AnnouncementActiveBlocks: 5,
+		MaximumProtocolBlocks:    30,
+	}
+
+	schedule, err := NewTimingSchedule(
+		TimingScheduleVersion{Version: 1, ActivationBlock: 0, Signing: timing, DKG: timing},
+		TimingScheduleVersion{Version: 2, ActivationBlock: 1000, Signing: timing, DKG: timing},
+		TimingScheduleVersion{Version: 5, ActivationBlock: 5000, Signing: timing, DKG: timing},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		startBlock      uint64
+		expectedVersion uint
+	}{
+		"genesis": {
+			startBlock:      0,
+			expectedVersion: 1,
+		},
+		"before second version": {
+			startBlock:      999,
+			expectedVersion: 1,
+		},
+		"at second version activation": {
+			startBlock:      1000,
+			expectedVersion: 2,
+		},
+		"after last version activation": {
+			startBlock:      9000,
+			expectedVersion: 5,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			version := schedule.timingAt(test.startBlock)
+
+			testutils.AssertUintsEqual(
+				t,
+				"version",
+				uint64(test.expectedVersion),
+				uint64(version.Version),
+			)
+		})
+	}
+}
+
+func TestDefaultTimingSchedule(t *testing.T) {
+	timing := DefaultTimingSchedule().timingAt(0)
+
+	testutils.AssertUintsEqual(
+		t,
+		"signing attempt maximum blocks",
+		signingAttemptAnnouncementDelayBlocks+
+			signingAttemptAnnouncementActiveBlocks+
+			signingAttemptMaximumProtocolBlocks+
+			signingAttemptCoolDownBlocks,
+		timing.Signing.MaximumBlocks(),
+	)
+	testutils.AssertUintsEqual(
+		t,
+		"DKG attempt maximum blocks",
+		dkgAttemptAnnouncementDelayBlocks+
+			dkgAttemptAnnouncementActiveBlocks+
+			dkgAttemptMaximumProtocolBlocks+
+			dkgAttemptCoolDownBlocks,
+		timing.DKG.MaximumBlocks(),
+	)
+}
+
+func TestNewAttemptTimingReport(t *testing.T) {
+	signingAttempt := func(
+		version uint,
+		outcome string,
+		participated bool,
+		protocolEndBlock uint64,
+		doneBlock uint64,
+	) *JournalEntry {
+		return &JournalEntry{
+			Type: JournalAttempt,
+			Attempt: &JournalAttemptTiming{
+				Protocol:           "signing",
+				TimingVersion:      version,
+				ProtocolStartBlock: 100,
+				ProtocolEndBlock:   protocolEndBlock,
+				DoneBlock:          doneBlock,
+				TimeoutBlock:       130,
+				Participated:       participated,
+				Outcome:            outcome,
+			},
+		}
+	}
+
+	entries := []*JournalEntry{
+		{Type: JournalCoordinationWindow},
+		signingAttempt(1, AttemptSucceeded, true, 105, 110),
+		signingAttempt(1, AttemptSucceeded, true, 120, 126),
+		signingAttempt(1, AttemptSucceeded, true, 112, 0),
+		signingAttempt(1, AttemptSucceeded, false, 0, 129),
+		signingAttempt(1, AttemptFailed, true, 130, 0),
+		signingAttempt(1, AttemptFailed, true, 115, 0),
+		signingAttempt(1, AttemptNotReady, false, 0, 0),
+		signingAttempt(2, AttemptSucceeded, true, 101, 0),
+		{
+			Type: JournalAttempt,
+			Attempt: &JournalAttemptTiming{
+				Protocol:           "dkg",
+				TimingVersion:      1,
+				ProtocolStartBlock: 100,
+				TimeoutBlock:       300,
+				Outcome:            AttemptSkipped,
+			},
+		},
+	}
+
+	expectedReport := &AttemptTimingReport{
+		Summaries: []*AttemptTimingSummary{
+			{
+				Protocol:              "dkg",
+				TimingVersion:         1,
+				MaximumProtocolBlocks: 200,
+				Attempts:              1,
+				Skipped:               1,
+			},
+			{
+				Protocol:              "signing",
+				TimingVersion:         1,
+				MaximumProtocolBlocks: 30,
+				Attempts:              7,
+				Succeeded:             4,
+				Failed:                2,
+				NotReady:              1,
+				TimedOut:              1,
+				MinProtocolBlocks:     10,
+				MedianProtocolBlocks:  12,
+				MaxProtocolBlocks:     26,
+				MinMarginBlocks:       4,
+				NearDeadline:          1,
+			},
+			{
+				Protocol:              "signing",
+				TimingVersion:         2,
+				MaximumProtocolBlocks: 30,
+				Attempts:              1,
+				Succeeded:             1,
+				MinProtocolBlocks:     1,
+				MedianProtocolBlocks:  1,
+				MaxProtocolBlocks:     1,
+				MinMarginBlocks:       29,
+			},
+		},
+	}
+
+	report := NewAttemptTimingReport(entries)
+
+	if !reflect.DeepEqual(expectedReport, report) {
+		t.Errorf(
+			"unexpected report\n"+
+				"expected: [%+v]\n"+
+				"actual:   [%+v]",
+			expectedReport,
+			report,
+		)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"crypto/ecdsa"
 	"crypto/elliptic"
+	"encoding/base64"
 	"encoding/hex"
 	"fmt"
 	"math/big"
@@ -32,6 +33,7 @@ const (
 	ActionRedemption
 	ActionMovingFunds
 	ActionMovedFundsSweep
+	ActionFeeBump
 )
 
 // ParseWalletActionType parses the given value into a WalletActionType.
@@ -49,11 +51,28 @@ func ParseWalletActionType(value uint8) (WalletActionType, error) {
 		return ActionMovingFunds, nil
 	case 5:
 		return ActionMovedFundsSweep, nil
+	case 6:
+		return ActionFeeBump, nil
 	default:
 		return 0, fmt.Errorf("unknown wallet action type [%v]", value)
 	}
 }
 
+// parseWalletActionTypeName parses the given name, as returned by
+// WalletActionType.String, into a WalletActionType.
+func parseWalletActionTypeName(name string) (WalletActionType, error) {
+	for value := uint8(0); ; value++ {
+		actionType, err := ParseWalletActionType(value)
+		if err != nil {
+			return 0, fmt.Errorf("unknown wallet action type [%s]", name)
+		}
+
+		if actionType.String() == name {
+			return actionType, nil
+		}
+	}
+}
+
 func (wat WalletActionType) String() string {
 	switch wat {
 	case ActionNoop:
@@ -68,6 +87,8 @@ func (wat WalletActionType) String() string {
 		return "MovingFunds"
 	case ActionMovedFundsSweep:
 		return "MovedFundsSweep"
+	case ActionFeeBump:
+		return "FeeBump"
 	default:
 		panic("unknown wallet action type")
 	}
@@ -89,6 +110,8 @@ func (wat WalletActionType) MetricName() string {
 		return "moving_funds"
 	case ActionMovedFundsSweep:
 		return "moved_funds_sweep"
+	case ActionFeeBump:
+		return "fee_bump"
 	default:
 		panic("unknown wallet action type")
 	}
@@ -157,11 +180,15 @@ type walletDispatcher struct {
 		SetGauge(name string, value float64)
 		RecordDuration(name string, duration time.Duration)
 	}
+	// journal is optional and used for recording the start and end of
+	// dispatched actions.
+	journal *auditJournal
 }
 
-func newWalletDispatcher() *walletDispatcher {
+func newWalletDispatcher(journal *auditJournal) *walletDispatcher {
 	return &walletDispatcher{
 		actions: make(map[string]WalletActionType),
+		journal: journal,
 	}
 }
 
@@ -176,6 +203,21 @@ func (wd *walletDispatcher) setMetricsRecorder(recorder interface {
 	wd.metricsRecorder = recorder
 }
 
+// inFlightActions returns a snapshot of actions currently executed by
+// wallets. The mapping key is the uncompressed public key (with 04 prefix)
+// of the wallet.
+func (wd *walletDispatcher) inFlightActions() map[string]WalletActionType {
+	wd.actionsMutex.Lock()
+	defer wd.actionsMutex.Unlock()
+
+	actions := make(map[string]WalletActionType, len(wd.actions))
+	for key, actionType := range wd.actions {
+		actions[key] = actionType
+	}
+
+	return actions
+}
+
 // dispatch sends the given walletAction for execution. If the wallet is
 // already busy, an errWalletBusy error is returned and the action is ignored.
 func (wd *walletDispatcher) dispatch(action walletAction) error {
@@ -242,7 +284,24 @@ func (wd *walletDispatcher) dispatch(action walletAction) error {
 
 		walletActionLogger.Infof("starting action execution")
 
+		walletPublicKeyHash := bitcoin.PublicKeyHash(action.wallet().publicKey)
+		wd.journal.recordWalletActionStarted(walletPublicKeyHash, actionType)
+
 		err := action.execute()
+
+		var transactionHash *bitcoin.Hash
+		if broadcaster, ok := action.(interface {
+			broadcastedTransaction() *bitcoin.Hash
+		}); ok {
+			transactionHash = broadcaster.broadcastedTransaction()
+		}
+		wd.journal.recordWalletActionFinished(
+			walletPublicKeyHash,
+			actionType,
+			transactionHash,
+			err,
+		)
+
 		if err != nil {
 			walletActionLogger.Errorf(
 				"action execution terminated with error: [%v]",
@@ -293,6 +352,11 @@ type walletTransactionExecutor struct {
 	signingExecutor walletSigningExecutor
 
 	waitForBlockFn waitForBlockFn
+
+	// broadcastedTransactionHash is the hash of the last transaction known
+	// on the Bitcoin chain after broadcasting. Nil if no transaction was
+	// broadcasted yet.
+	broadcastedTransactionHash *bitcoin.Hash
 }
 
 func newWalletTransactionExecutor(
@@ -328,6 +392,17 @@ func (wte *walletTransactionExecutor) signTransaction(
 		)
 	}
 
+	// Expose the transaction in the standard PSBT format so, operators can
+	// independently verify what the signing group is about to sign.
+	if psbt, err := unsignedTx.ExportPSBT(); err != nil {
+		signTxLogger.Warnf("cannot export transaction as PSBT: [%v]", err)
+	} else {
+		signTxLogger.Debugf(
+			"transaction's PSBT: [%s]",
+			base64.StdEncoding.EncodeToString(psbt),
+		)
+	}
+
 	signTxLogger.Infof("signing transaction's sig hashes")
 
 	signingCtx, cancelSigningCtx := withCancelOnBlock(
@@ -442,11 +517,19 @@ func (wte *walletTransactionExecutor) broadcastTransaction(
 			}
 
 			broadcastTxLogger.Infof("transaction is known on Bitcoin chain")
+			wte.broadcastedTransactionHash = &txHash
 			return nil
 		}
 	}
 }
 
+// broadcastedTransaction returns the hash of the last transaction known on
+// the Bitcoin chain after broadcasting, or nil if there is no such
+// transaction.
+func (wte *walletTransactionExecutor) broadcastedTransaction() *bitcoin.Hash {
+	return wte.broadcastedTransactionHash
+}
+
 // wallet represents a tBTC wallet. A wallet is one of the basic building
 // blocks of the system that takes BTC under custody during the deposit
 // process and gives that BTC back during redemptions.
@@ -501,6 +584,41 @@ func (w *wallet) membersByOperator(operator chain.Address) []group.MemberIndex {
 	return members
 }
 
+// WalletMembersIDs returns the sortition pool IDs of wallet members
+// controlled by the given wallet operators. IDs are returned in the order
+// of the operators, as expected by the Bridge functions taking the wallet
+// members IDs.
+func WalletMembersIDs(
+	walletOperators []chain.Address,
+	operatorIDs interface {
+		GetOperatorID(operatorAddress chain.Address) (chain.OperatorID, error)
+	},
+) ([]uint32, error) {
+	// Cache mapping operator addresses to their wallet member IDs. It helps to
+	// limit the number of calls to the ETH client if some operator addresses
+	// occur on the list multiple times.
+	operatorIDCache := make(map[chain.Address]uint32)
+
+	walletMemberIDs := make([]uint32, 0)
+
+	for _, operatorAddress := range walletOperators {
+		// Search for the operator address in the cache. Store the operator
+		// address in the cache if it's not there.
+		if operatorID, found := operatorIDCache[operatorAddress]; !found {
+			fetchedOperatorID, err := operatorIDs.GetOperatorID(operatorAddress)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get operator ID: [%w]", err)
+			}
+			operatorIDCache[operatorAddress] = fetchedOperatorID
+			walletMemberIDs = append(walletMemberIDs, fetchedOperatorID)
+		} else {
+			walletMemberIDs = append(walletMemberIDs, operatorID)
+		}
+	}
+
+	return walletMemberIDs, nil
+}
+
 func (w *wallet) String() string {
 	publicKey := elliptic.Marshal(
 		w.publicKey.Curve,
@@ -760,6 +878,103 @@ func EnsureWalletSyncedBetweenChains(
 	}
 }
 
+// DetermineWalletUnconfirmedChange determines the unconfirmed wallet
+// transaction that spends the given wallet main UTXO and the change output
+// through which that transaction returns funds to the wallet. Such a change
+// output can be spent by a child transaction in order to speed up the
+// confirmation of its parent (child-pays-for-parent). Both returned values
+// are nil if the wallet main UTXO is nil or is not spent by any transaction
+// living in the mempool. An error is returned if the spending transaction
+// does not return funds to the wallet or its change output is already spent
+// by another mempool transaction.
+func DetermineWalletUnconfirmedChange(
+	walletPublicKeyHash [20]byte,
+	walletMainUtxo *bitcoin.UnspentTransactionOutput,
+	btcChain bitcoin.Chain,
+) (*bitcoin.Transaction, *bitcoin.UnspentTransactionOutput, error) {
+	// Fresh wallets are not supported. Their first transaction spends
+	// deposits only and there is no main UTXO that would allow determining
+	// it reliably.
+	if walletMainUtxo == nil {
+		return nil, nil, nil
+	}
+
+	mempool, err := btcChain.GetMempoolForPublicKeyHash(walletPublicKeyHash)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot get mempool for wallet: [%v]", err)
+	}
+
+	var parentTx *bitcoin.Transaction
+	for _, transaction := range mempool {
+		for _, input := range transaction.Inputs {
+			if input.Outpoint.TransactionHash == walletMainUtxo.Outpoint.TransactionHash &&
+				input.Outpoint.OutputIndex == walletMainUtxo.Outpoint.OutputIndex {
+				parentTx = transaction
+				break
+			}
+		}
+
+		if parentTx != nil {
+			break
+		}
+	}
+
+	if parentTx == nil {
+		return nil, nil, nil
+	}
+
+	parentTxHash := parentTx.Hash()
+
+	walletP2PKH, err := bitcoin.PayToPublicKeyHash(walletPublicKeyHash)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot construct P2PKH for wallet: [%v]", err)
+	}
+	walletP2WPKH, err := bitcoin.PayToWitnessPublicKeyHash(walletPublicKeyHash)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot construct P2WPKH for wallet: [%v]", err)
+	}
+
+	// Wallet transactions return funds to the wallet using at most one
+	// output so, there is no ambiguity here.
+	var change *bitcoin.UnspentTransactionOutput
+	for outputIndex, output := range parentTx.Outputs {
+		script := output.PublicKeyScript
+		if bytes.Equal(script, walletP2PKH) || bytes.Equal(script, walletP2WPKH) {
+			change = &bitcoin.UnspentTransactionOutput{
+				Outpoint: &bitcoin.TransactionOutpoint{
+					TransactionHash: parentTxHash,
+					OutputIndex:     uint32(outputIndex),
+				},
+				Value: output.Value,
+			}
+			break
+		}
+	}
+
+	if change == nil {
+		return nil, nil, fmt.Errorf(
+			"unconfirmed transaction [%s] does not return funds to the wallet",
+			parentTxHash.Hex(bitcoin.ReversedByteOrder),
+		)
+	}
+
+	for _, transaction := range mempool {
+		for _, input := range transaction.Inputs {
+			if input.Outpoint.TransactionHash == change.Outpoint.TransactionHash &&
+				input.Outpoint.OutputIndex == change.Outpoint.OutputIndex {
+				return nil, nil, fmt.Errorf(
+					"change output of unconfirmed transaction [%s] is "+
+						"already spent by transaction [%s]",
+					parentTxHash.Hex(bitcoin.ReversedByteOrder),
+					transaction.Hash().Hex(bitcoin.ReversedByteOrder),
+				)
+			}
+		}
+	}
+
+	return parentTx, change, nil
+}
+
 // signer represents a threshold signer of a tBTC wallet. A signer holds
 // a wallet tECDSA private key share and is able to participate in the
 // signing process.
@@ -51,9 +51,13 @@ func TestParseWalletActionType(t *testing.T) {
 			value:          5,
 			expectedAction: ActionMovedFundsSweep,
 		},
+		"fee bump": {
+			value:          6,
+			expectedAction: ActionFeeBump,
+		},
 		"unknown": {
-			value:       6,
-			expectedErr: fmt.Errorf("unknown wallet action type [6]"),
+			value:       7,
+			expectedErr: fmt.Errorf("unknown wallet action type [7]"),
 		},
 	}
 
@@ -81,7 +85,7 @@ func TestParseWalletActionType(t *testing.T) {
 }
 
 func TestWalletDispatcher_Dispatch(t *testing.T) {
-	walletDispatcher := newWalletDispatcher()
+	walletDispatcher := newWalletDispatcher(nil)
 
 	wallet1 := generateWallet(big.NewInt(100))
 	wallet2 := generateWallet(big.NewInt(101))
@@ -320,6 +324,138 @@ func TestDetermineWalletMainUtxo(t *testing.T) {
 	}
 }
 
+func TestDetermineWalletUnconfirmedChange(t *testing.T) {
+	walletPublicKeyHash := [20]byte{0x8d, 0xb5, 0x0e, 0xb5}
+
+	walletScript, err := bitcoin.PayToWitnessPublicKeyHash(walletPublicKeyHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	otherScript, err := bitcoin.PayToWitnessPublicKeyHash([20]byte{0x01})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mainUtxo := &bitcoin.UnspentTransactionOutput{
+		Outpoint: &bitcoin.TransactionOutpoint{
+			TransactionHash: bitcoin.Hash{0x02},
+			OutputIndex:     0,
+		},
+		Value: 100000,
+	}
+
+	newTransaction := func(
+		outpoint *bitcoin.TransactionOutpoint,
+		outputs ...*bitcoin.TransactionOutput,
+	) *bitcoin.Transaction {
+		return &bitcoin.Transaction{
+			Version: 1,
+			Inputs: []*bitcoin.TransactionInput{
+				{Outpoint: outpoint, Sequence: 0xffffffff},
+			},
+			Outputs: outputs,
+		}
+	}
+
+	// Redemption paying the redeemer first and the wallet change last.
+	parentTx := newTransaction(
+		mainUtxo.Outpoint,
+		&bitcoin.TransactionOutput{Value: 10000, PublicKeyScript: otherScript},
+		&bitcoin.TransactionOutput{Value: 89000, PublicKeyScript: walletScript},
+	)
+	childTx := newTransaction(
+		&bitcoin.TransactionOutpoint{
+			TransactionHash: parentTx.Hash(),
+			OutputIndex:     1,
+		},
+		&bitcoin.TransactionOutput{Value: 88000, PublicKeyScript: walletScript},
+	)
+	unrelatedTx := newTransaction(
+		&bitcoin.TransactionOutpoint{
+			TransactionHash: bitcoin.Hash{0x03},
+			OutputIndex:     0,
+		},
+		&bitcoin.TransactionOutput{Value: 5000, PublicKeyScript: walletScript},
+	)
+
+	var tests = map[string]struct {
+		mainUtxo       *bitcoin.UnspentTransactionOutput
+		mempool        []*bitcoin.Transaction
+		expectedParent *bitcoin.Transaction
+		expectedChange *bitcoin.UnspentTransactionOutput
+		expectedErr    error
+	}{
+		"no main UTXO": {
+			mainUtxo: nil,
+			mempool:  []*bitcoin.Transaction{parentTx},
+		},
+		"main UTXO not spent": {
+			mainUtxo: mainUtxo,
+			mempool:  []*bitcoin.Transaction{unrelatedTx},
+		},
+		"main UTXO spent by a transaction with change": {
+			mainUtxo:       mainUtxo,
+			mempool:        []*bitcoin.Transaction{unrelatedTx, parentTx},
+			expectedParent: parentTx,
+			expectedChange: &bitcoin.UnspentTransactionOutput{
+				Outpoint: &bitcoin.TransactionOutpoint{
+					TransactionHash: parentTx.Hash(),
+					OutputIndex:     1,
+				},
+				Value: 89000,
+			},
+		},
+		"change already spent": {
+			mainUtxo: mainUtxo,
+			mempool:  []*bitcoin.Transaction{parentTx, childTx},
+			expectedErr: fmt.Errorf(
+				"change output of unconfirmed transaction [%s] is "+
+					"already spent by transaction [%s]",
+				parentTx.Hash().Hex(bitcoin.ReversedByteOrder),
+				childTx.Hash().Hex(bitcoin.ReversedByteOrder),
+			),
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			btcChain := newLocalBitcoinChain()
+			btcChain.mempool = test.mempool
+
+			parent, change, err := DetermineWalletUnconfirmedChange(
+				walletPublicKeyHash,
+				test.mainUtxo,
+				btcChain,
+			)
+
+			if !reflect.DeepEqual(test.expectedErr, err) {
+				t.Errorf(
+					"unexpected error\n"+
+						"expected: [%v]\n"+
+						"actual:   [%v]",
+					test.expectedErr,
+					err,
+				)
+			}
+
+			if test.expectedParent != parent {
+				t.Errorf("unexpected parent transaction")
+			}
+
+			if !reflect.DeepEqual(test.expectedChange, change) {
+				t.Errorf(
+					"unexpected change\n"+
+						"expected: [%+v]\n"+
+						"actual:   [%+v]",
+					test.expectedChange,
+					change,
+				)
+			}
+		})
+	}
+}
+
 func TestWallet_MembersByOperator(t *testing.T) {
 	wallet := &wallet{
 		// Set only relevant fields.
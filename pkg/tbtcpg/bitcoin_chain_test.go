@@ -13,6 +13,7 @@ type LocalBitcoinChain struct {
 	transactions              map[bitcoin.Hash]*bitcoin.Transaction
 	transactionsConfirmations map[bitcoin.Hash]uint
 	satPerVByteFeeEstimation  map[uint32]int64
+	mempool                   map[[20]byte][]*bitcoin.Transaction
 }
 
 func NewLocalBitcoinChain() *LocalBitcoinChain {
@@ -20,6 +21,7 @@ func NewLocalBitcoinChain() *LocalBitcoinChain {
 		transactions:              make(map[bitcoin.Hash]*bitcoin.Transaction),
 		transactionsConfirmations: make(map[bitcoin.Hash]uint),
 		satPerVByteFeeEstimation:  make(map[uint32]int64),
+		mempool:                   make(map[[20]byte][]*bitcoin.Transaction),
 	}
 }
 
@@ -108,7 +110,20 @@ func (lbc *LocalBitcoinChain) GetTxHashesForPublicKeyHash(
 func (lbc *LocalBitcoinChain) GetMempoolForPublicKeyHash(
 	publicKeyHash [20]byte,
 ) ([]*bitcoin.Transaction, error) {
-	panic("unsupported")
+	lbc.mutex.Lock()
+	defer lbc.mutex.Unlock()
+
+	return lbc.mempool[publicKeyHash], nil
+}
+
+func (lbc *LocalBitcoinChain) SetMempoolForPublicKeyHash(
+	publicKeyHash [20]byte,
+	transactions []*bitcoin.Transaction,
+) {
+	lbc.mutex.Lock()
+	defer lbc.mutex.Unlock()
+
+	lbc.mempool[publicKeyHash] = transactions
 }
 
 func (lbc *LocalBitcoinChain) GetUtxosForPublicKeyHash(
@@ -0,0 +1,290 @@
+package tbtcpg
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DepositRefundSafetyMargin is the minimum time between the sweep proposal
+// and the refund locktime of each swept deposit. It mirrors the
+// DEPOSIT_REFUND_SAFETY_MARGIN constant of the WalletProposalValidator
+// contract which rejects sweep proposals containing deposits whose refund
+// locktime is closer. Such deposits are not eligible for sweeping regardless
+// of the deposit selection strategy.
+const DepositRefundSafetyMargin = 24 * time.Hour
+
+const (
+	// DepositSelectionOldestFirst sweeps the deposits revealed first. All
+	// deposits of a sweep must target the same vault so the vault of the
+	// oldest deposit is swept.
+	DepositSelectionOldestFirst = "oldest-first"
+	// DepositSelectionValueWeighted sweeps the deposits holding the highest
+	// amounts. Every deposit adds the same size to the sweep transaction
+	// so this strategy maximizes the amount swept per fee paid. The vault
+	// whose deposits hold the highest total amount is swept.
+	DepositSelectionValueWeighted = "value-weighted"
+	// DepositSelectionGroupByVault sweeps the vault having the most deposits
+	// waiting for a sweep, oldest deposits first. This maximizes the number
+	// of deposits swept per transaction.
+	DepositSelectionGroupByVault = "group-by-vault"
+	// DepositSelectionRefundLocktime sweeps the deposits whose refund
+	// locktime is the closest, so they are swept before depositors can
+	// claim refunds. The vault of the most urgent deposit is swept.
+	DepositSelectionRefundLocktime = "refund-locktime-risk"
+)
+
+// DefaultDepositSelectionStrategy is the name of the deposit selection
+// strategy used if none is configured.
+const DefaultDepositSelectionStrategy = DepositSelectionGroupByVault
+
+// DepositSelectionStrategy selects deposits swept together by a single
+// deposit sweep transaction.
+type DepositSelectionStrategy interface {
+	// Name returns the name the strategy is configured with.
+	Name() string
+
+	// SelectDeposits selects at most maxNumberOfDeposits deposits out of the
+	// given ones that are eligible for sweeping and sorted by the reveal
+	// block in the ascending order. All selected deposits target the same
+	// vault. The returned report explains why each deposit was included or
+	// skipped.
+	SelectDeposits(
+		deposits []*Deposit,
+		maxNumberOfDeposits int,
+	) *DepositSelectionReport
+}
+
+// DepositSelectionDecision is the decision about a single deposit made by
+// a deposit selection strategy.
+type DepositSelectionDecision struct {
+	Deposit  *Deposit
+	Included bool
+	Reason   string
+}
+
+// DepositSelectionReport holds decisions made by a deposit selection
+// strategy. Decisions are in the same order as deposits passed to the
+// strategy.
+type DepositSelectionReport struct {
+	Strategy  string
+	Decisions []*DepositSelectionDecision
+}
+
+// Included returns deposits included in the sweep, sorted by the reveal
+// block in the ascending order.
+func (dsr *DepositSelectionReport) Included() []*Deposit {
+	included := make([]*Deposit, 0)
+	for _, decision := range dsr.Decisions {
+		if decision.Included {
+			included = append(included, decision.Deposit)
+		}
+	}
+
+	return included
+}
+
+// NewDepositSelectionStrategy returns the deposit selection strategy with
+// the given name. An empty name means the default strategy.
+func NewDepositSelectionStrategy(name string) (DepositSelectionStrategy, error) {
+	switch name {
+	case DepositSelectionOldestFirst:
+		return &depositSelectionStrategy{
+			name: DepositSelectionOldestFirst,
+			less: func(a, b *Deposit) bool {
+				return a.RevealBlock < b.RevealBlock
+			},
+			// Prefer the vault holding the oldest deposit.
+			better: func(a, b []*Deposit) bool {
+				return a[0].RevealBlock < b[0].RevealBlock
+			},
+		}, nil
+	case DepositSelectionValueWeighted:
+		return &depositSelectionStrategy{
+			name: DepositSelectionValueWeighted,
+			less: func(a, b *Deposit) bool {
+				return a.AmountBtc > b.AmountBtc
+			},
+			better: func(a, b []*Deposit) bool {
+				return totalAmountBtc(a) > totalAmountBtc(b)
+			},
+		}, nil
+	case "", DepositSelectionGroupByVault:
+		return &depositSelectionStrategy{
+			name: DepositSelectionGroupByVault,
+			less: func(a, b *Deposit) bool {
+				return a.RevealBlock < b.RevealBlock
+			},
+			better: func(a, b []*Deposit) bool {
+				return len(a) > len(b)
+			},
+			compareWholeGroups: true,
+		}, nil
+	case DepositSelectionRefundLocktime:
+		return &depositSelectionStrategy{
+			name: DepositSelectionRefundLocktime,
+			less: func(a, b *Deposit) bool {
+				return a.RefundLocktime.Before(b.RefundLocktime)
+			},
+			// Prefer the vault holding the most urgent deposit.
+			better: func(a, b []*Deposit) bool {
+				return a[0].RefundLocktime.Before(b[0].RefundLocktime)
+			},
+		}, nil
+	default:
+		return nil, fmt.Errorf(
+			"unsupported deposit selection strategy [%s]",
+			name,
+		)
+	}
+}
+
+// ValidateDepositSelectionStrategy returns an error if there is no deposit
+// selection strategy with the given name. An empty name is valid and means
+// the default strategy.
+func ValidateDepositSelectionStrategy(name string) error {
+	_, err := NewDepositSelectionStrategy(name)
+	return err
+}
+
+// depositSelectionStrategy is a deposit selection strategy that groups
+// deposits by vault, orders deposits of each vault by priority and sweeps
+// the best vault. Only the vault dimension is fixed; all other on-chain
+// rules of WalletProposalValidator are satisfied by the eligibility checks
+// made before the selection.
+type depositSelectionStrategy struct {
+	name string
+	// less reports whether deposit a should be swept before deposit b.
+	less func(a, b *Deposit) bool
+	// better reports whether the vault with deposits a should be swept
+	// rather than the vault with deposits b. Deposits are ordered by less
+	// and never empty.
+	better func(a, b []*Deposit) bool
+	// compareWholeGroups makes better compare all deposits of the vaults
+	// instead of only those fitting in a single sweep.
+	compareWholeGroups bool
+}
+
+func (dss *depositSelectionStrategy) Name() string {
+	return dss.name
+}
+
+func (dss *depositSelectionStrategy) SelectDeposits(
+	deposits []*Deposit,
+	maxNumberOfDeposits int,
+) *DepositSelectionReport {
+	decisions := make(map[*Deposit]*DepositSelectionDecision)
+
+	type vaultGroup struct {
+		label    string
+		deposits []*Deposit
+	}
+
+	// Groups are kept in the order of their oldest deposits so ties are
+	// resolved deterministically.
+	groups := make([]*vaultGroup, 0)
+	groupsByKey := make(map[string]*vaultGroup)
+
+	for _, deposit := range deposits {
+		key, label := vaultKey(deposit)
+
+		group, exists := groupsByKey[key]
+		if !exists {
+			group = &vaultGroup{label: label}
+			groupsByKey[key] = group
+			groups = append(groups, group)
+		}
+		group.deposits = append(group.deposits, deposit)
+	}
+
+	var selectedGroup *vaultGroup
+	var selectedBatch []*Deposit
+	for _, group := range groups {
+		sort.SliceStable(group.deposits, func(i, j int) bool {
+			return dss.less(group.deposits[i], group.deposits[j])
+		})
+
+		batch := group.deposits
+		if maxNumberOfDeposits > 0 && len(batch) > maxNumberOfDeposits {
+			batch = batch[:maxNumberOfDeposits]
+		}
+
+		if selectedGroup == nil {
+			selectedGroup, selectedBatch = group, batch
+			continue
+		}
+
+		var isBetter bool
+		if dss.compareWholeGroups {
+			isBetter = dss.better(group.deposits, selectedGroup.deposits)
+		} else {
+			isBetter = dss.better(batch, selectedBatch)
+		}
+
+		if isBetter {
+			selectedGroup, selectedBatch = group, batch
+		}
+	}
+
+	for _, group := range groups {
+		for i, deposit := range group.deposits {
+			decision := &DepositSelectionDecision{Deposit: deposit}
+
+			switch {
+			case group != selectedGroup:
+				decision.Reason = fmt.Sprintf(
+					"vault [%s] not selected; vault [%s] is preferred "+
+						"and all deposits of a sweep must target the "+
+						"same vault",
+					group.label,
+					selectedGroup.label,
+				)
+			case i >= len(selectedBatch):
+				decision.Reason = fmt.Sprintf(
+					"sweep max size [%d] reached",
+					maxNumberOfDeposits,
+				)
+			default:
+				decision.Included = true
+				decision.Reason = fmt.Sprintf(
+					"priority [%d/%d] in vault [%s]",
+					i+1,
+					len(group.deposits),
+					group.label,
+				)
+			}
+
+			decisions[deposit] = decision
+		}
+	}
+
+	report := &DepositSelectionReport{
+		Strategy:  dss.name,
+		Decisions: make([]*DepositSelectionDecision, len(deposits)),
+	}
+	for i, deposit := range deposits {
+		report.Decisions[i] = decisions[deposit]
+	}
+
+	return report
+}
+
+// vaultKey returns the key deposits are grouped by and the label of the
+// deposit's vault. Vault addresses are compared case-insensitively.
+func vaultKey(deposit *Deposit) (string, string) {
+	if deposit.Vault == nil {
+		return "", "vault=0x0 (nil)"
+	}
+
+	return strings.ToLower(string(*deposit.Vault)), string(*deposit.Vault)
+}
+
+func totalAmountBtc(deposits []*Deposit) float64 {
+	total := float64(0)
+	for _, deposit := range deposits {
+		total += deposit.AmountBtc
+	}
+
+	return total
+}
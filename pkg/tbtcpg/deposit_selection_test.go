@@ -0,0 +1,218 @@
+package tbtcpg_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/keep-network/keep-core/internal/testutils"
+	"github.com/keep-network/keep-core/pkg/bitcoin"
+	"github.com/keep-network/keep-core/pkg/chain"
+	"github.com/keep-network/keep-core/pkg/tbtcpg"
+)
+
+func TestDepositSelectionStrategy_SelectDeposits(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+
+	vaultA := chain.Address("0xAA1122BB3344CC5566DD7788EE9900FF00112233")
+	vaultB := chain.Address("0xBB1122BB3344CC5566DD7788EE9900FF00112233")
+
+	newDeposit := func(
+		index byte,
+		vault *chain.Address,
+		amountBtc float64,
+		refundLocktime time.Duration,
+	) *tbtcpg.Deposit {
+		return &tbtcpg.Deposit{
+			DepositReference: tbtcpg.DepositReference{
+				FundingTxHash: bitcoin.Hash{index},
+				RevealBlock:   1000 + uint64(index),
+			},
+			DepositKey:     string([]byte{'0' + index}),
+			AmountBtc:      amountBtc,
+			Vault:          vault,
+			RefundLocktime: now.Add(refundLocktime),
+		}
+	}
+
+	// Deposits are sorted by the reveal block, as passed by the task.
+	deposits := []*tbtcpg.Deposit{
+		newDeposit(1, &vaultA, 0.1, 30*24*time.Hour),
+		newDeposit(2, &vaultB, 2, 20*24*time.Hour),
+		newDeposit(3, &vaultB, 0.5, 12*time.Hour),
+		newDeposit(4, &vaultB, 0.2, 10*24*time.Hour),
+		newDeposit(5, &vaultA, 1, 2*24*time.Hour),
+		newDeposit(6, nil, 2.6, 60*24*time.Hour),
+	}
+
+	var tests = map[string]struct {
+		strategy            string
+		maxNumberOfDeposits int
+		expectedIncluded    []byte
+		expectedSkipped     []byte
+	}{
+		"oldest first": {
+			strategy:            tbtcpg.DepositSelectionOldestFirst,
+			maxNumberOfDeposits: 5,
+			expectedIncluded:    []byte{1, 5},
+			expectedSkipped:     []byte{2, 3, 4, 6},
+		},
+		"value weighted": {
+			strategy:            tbtcpg.DepositSelectionValueWeighted,
+			maxNumberOfDeposits: 5,
+			expectedIncluded:    []byte{2, 3, 4},
+			expectedSkipped:     []byte{1, 5, 6},
+		},
+		"value weighted - max size limits vault value": {
+			strategy:            tbtcpg.DepositSelectionValueWeighted,
+			maxNumberOfDeposits: 1,
+			expectedIncluded:    []byte{6},
+			expectedSkipped:     []byte{1, 2, 3, 4, 5},
+		},
+		"group by vault": {
+			strategy:            tbtcpg.DepositSelectionGroupByVault,
+			maxNumberOfDeposits: 5,
+			expectedIncluded:    []byte{2, 3, 4},
+			expectedSkipped:     []byte{1, 5, 6},
+		},
+		"group by vault - max size reached": {
+			strategy:            tbtcpg.DepositSelectionGroupByVault,
+			maxNumberOfDeposits: 2,
+			expectedIncluded:    []byte{2, 3},
+			expectedSkipped:     []byte{1, 4, 5, 6},
+		},
+		"default strategy": {
+			strategy:            "",
+			maxNumberOfDeposits: 5,
+			expectedIncluded:    []byte{2, 3, 4},
+			expectedSkipped:     []byte{1, 5, 6},
+		},
+		"refund locktime risk": {
+			strategy:            tbtcpg.DepositSelectionRefundLocktime,
+			maxNumberOfDeposits: 5,
+			expectedIncluded:    []byte{2, 3, 4},
+			expectedSkipped:     []byte{1, 5, 6},
+		},
+		"refund locktime risk - most urgent first": {
+			strategy:            tbtcpg.DepositSelectionRefundLocktime,
+			maxNumberOfDeposits: 1,
+			expectedIncluded:    []byte{3},
+			expectedSkipped:     []byte{1, 2, 4, 5, 6},
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			strategy, err := tbtcpg.NewDepositSelectionStrategy(test.strategy)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			report := strategy.SelectDeposits(
+				deposits,
+				test.maxNumberOfDeposits,
+			)
+
+			testutils.AssertIntsEqual(
+				t,
+				"decisions count",
+				len(deposits),
+				len(report.Decisions),
+			)
+
+			included := make([]byte, 0)
+			skipped := make([]byte, 0)
+			for i, decision := range report.Decisions {
+				if decision.Deposit != deposits[i] {
+					t.Fatalf("unexpected deposit of decision [%d]", i)
+				}
+
+				if decision.Reason == "" {
+					t.Errorf("missing reason of decision [%d]", i)
+				}
+
+				if decision.Included {
+					included = append(included, decision.Deposit.FundingTxHash[0])
+				} else {
+					skipped = append(skipped, decision.Deposit.FundingTxHash[0])
+				}
+			}
+
+			testutils.AssertBytesEqual(t, test.expectedIncluded, included)
+			testutils.AssertBytesEqual(t, test.expectedSkipped, skipped)
+
+			// Included deposits must keep the reveal order.
+			includedDeposits := report.Included()
+			testutils.AssertIntsEqual(
+				t,
+				"included deposits count",
+				len(test.expectedIncluded),
+				len(includedDeposits),
+			)
+			for i, deposit := range includedDeposits {
+				testutils.AssertIntsEqual(
+					t,
+					"included deposit",
+					int(test.expectedIncluded[i]),
+					int(deposit.FundingTxHash[0]),
+				)
+			}
+		})
+	}
+}
+
+func TestNewDepositSelectionStrategy(t *testing.T) {
+	var tests = map[string]struct {
+		name         string
+		expectedName string
+		expectError  bool
+	}{
+		"empty name": {
+			name:         "",
+			expectedName: tbtcpg.DefaultDepositSelectionStrategy,
+		},
+		"oldest first": {
+			name:         "oldest-first",
+			expectedName: tbtcpg.DepositSelectionOldestFirst,
+		},
+		"value weighted": {
+			name:         "value-weighted",
+			expectedName: tbtcpg.DepositSelectionValueWeighted,
+		},
+		"group by vault": {
+			name:         "group-by-vault",
+			expectedName: tbtcpg.DepositSelectionGroupByVault,
+		},
+		"refund locktime risk": {
+			name:         "refund-locktime-risk",
+			expectedName: tbtcpg.DepositSelectionRefundLocktime,
+		},
+		"unknown": {
+			name:        "newest-first",
+			expectError: true,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			strategy, err := tbtcpg.NewDepositSelectionStrategy(test.name)
+
+			if test.expectError {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			testutils.AssertStringsEqual(
+				t,
+				"strategy name",
+				test.expectedName,
+				strategy.Name(),
+			)
+		})
+	}
+}
@@ -1,11 +1,11 @@
 package tbtcpg
 
 import (
+	"encoding/binary"
 	"fmt"
 	"math"
 	"math/big"
 	"sort"
-	"strings"
 	"time"
 
 	"github.com/ipfs/go-log/v2"
@@ -26,22 +26,41 @@ const depositScriptByteSize = 126
 // 30 days assuming 12 seconds per block.
 const DepositSweepLookBackBlocks = uint64(216000)
 
+// DepositSelectionCandidatesFactor bounds the number of eligible deposits
+// the deposit selection strategy chooses from. At most this many times the
+// deposit sweep max size of the oldest eligible deposits are fetched, as
+// each of them requires chain and Bitcoin lookups in every proposal cycle.
+const DepositSelectionCandidatesFactor = 4
+
 // DepositSweepTask is a task that may produce a deposit sweep proposal.
 type DepositSweepTask struct {
 	chain    Chain
 	btcChain bitcoin.Chain
+	strategy DepositSelectionStrategy
 }
 
 func NewDepositSweepTask(
 	chain Chain,
 	btcChain bitcoin.Chain,
 ) *DepositSweepTask {
+	// The default strategy is always supported.
+	strategy, _ := NewDepositSelectionStrategy(DefaultDepositSelectionStrategy)
+
 	return &DepositSweepTask{
 		chain:    chain,
 		btcChain: btcChain,
+		strategy: strategy,
 	}
 }
 
+// SetDepositSelectionStrategy sets the strategy used to select deposits
+// to sweep.
+func (dst *DepositSweepTask) SetDepositSelectionStrategy(
+	strategy DepositSelectionStrategy,
+) {
+	dst.strategy = strategy
+}
+
 func (dst *DepositSweepTask) Run(request *tbtc.CoordinationProposalRequest) (
 	tbtc.CoordinationProposal,
 	bool,
@@ -79,10 +98,23 @@ func (dst *DepositSweepTask) Run(request *tbtc.CoordinationProposalRequest) (
 		return nil, false, nil
 	}
 
+	parentTx, err := dst.FindParentTransaction(
+		taskLogger,
+		walletPublicKeyHash,
+		request.CoordinationBlock,
+	)
+	if err != nil {
+		return nil, false, fmt.Errorf(
+			"cannot find unconfirmed parent transaction: [%w]",
+			err,
+		)
+	}
+
 	proposal, err := dst.ProposeDepositsSweep(
 		taskLogger,
 		walletPublicKeyHash,
 		deposits,
+		parentTx,
 		0,
 	)
 	if err != nil {
@@ -117,6 +149,7 @@ type Deposit struct {
 	AmountBtc           float64
 	Confirmations       uint
 	Vault               *chain.Address
+	RefundLocktime      time.Time
 }
 
 // FindDeposits finds deposits according to the given criteria. It always
@@ -138,13 +171,15 @@ func FindDeposits(
 		maxNumberOfDeposits,
 		skipSwept,
 		skipUnconfirmed,
+		false,
 		0,
 	)
 }
 
 // findDeposits finds deposits according to the given criteria.
-// The filterStartBlock parameter controls the earliest block from which
-// deposit-revealed events are queried.
+// The skipRefundRisk parameter makes it skip deposits whose refund locktime
+// is within the DepositRefundSafetyMargin. The filterStartBlock parameter
+// controls the earliest block from which deposit-revealed events are queried.
 func findDeposits(
 	fnLogger log.StandardLogger,
 	chain Chain,
@@ -153,6 +188,7 @@ func findDeposits(
 	maxNumberOfDeposits int,
 	skipSwept bool,
 	skipUnconfirmed bool,
+	skipRefundRisk bool,
 	filterStartBlock uint64,
 ) ([]*Deposit, error) {
 	fnLogger.Infof("reading revealed deposits from chain")
@@ -207,6 +243,20 @@ func findDeposits(
 		depositKey := chain.BuildDepositKey(event.FundingTxHash, event.FundingOutputIndex)
 		depositKeyStr := depositKey.Text(16)
 
+		refundLocktime := time.Unix(
+			int64(binary.LittleEndian.Uint32(event.RefundLocktime[:])),
+			0,
+		)
+		if skipRefundRisk &&
+			!refundLocktime.After(timeNow.Add(DepositRefundSafetyMargin)) {
+			fnLogger.Infof(
+				"deposit [%s] refund locktime [%s] is within the safety margin",
+				depositKeyStr,
+				refundLocktime.Format(time.RFC3339),
+			)
+			continue
+		}
+
 		fnLogger.Debugf("getting details of deposit [%s]", depositKeyStr)
 
 		depositRequest, found, err := chain.GetDepositRequest(
@@ -271,6 +321,7 @@ func findDeposits(
 				AmountBtc:           convertSatToBtc(float64(depositRequest.Amount)),
 				Confirmations:       confirmations,
 				Vault:               depositRequest.Vault,
+				RefundLocktime:      refundLocktime,
 			},
 		)
 	}
@@ -280,8 +331,9 @@ func findDeposits(
 
 // FindDepositsToSweep finds deposits that can be swept.
 // maxNumberOfDeposits is used as a ceiling for the number of deposits in the
-// result. If number of discovered deposits meets the maxNumberOfDeposits the
-// function will stop fetching more deposits.
+// result. Deposits are selected out of all deposits eligible for sweeping
+// using the task's deposit selection strategy. All returned deposits target
+// the same vault.
 // This function will return a list of deposits from the wallet that can be swept.
 // Deposits with insufficient number of funding transaction confirmations will
 // not be taken into consideration for sweeping.
@@ -292,11 +344,73 @@ func (dst *DepositSweepTask) FindDepositsToSweep(
 	walletPublicKeyHash [20]byte,
 	maxNumberOfDeposits uint16,
 ) ([]*DepositReference, error) {
+	report, err := dst.SelectDepositsToSweep(
+		taskLogger,
+		walletPublicKeyHash,
+		maxNumberOfDeposits,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	depositsToSweep := report.Included()
+
+	if len(depositsToSweep) == 0 {
+		return nil, nil
+	}
+
+	taskLogger.Infof(
+		"found [%d] deposits to sweep",
+		len(depositsToSweep),
+	)
+
+	for _, deposit := range depositsToSweep {
+		taskLogger.Infof(
+			"deposit [%s] - [%s]",
+			deposit.DepositKey,
+			fmt.Sprintf(
+				"reveal block: [%d], funding transaction: [%s], output index: [%d]",
+				deposit.RevealBlock,
+				deposit.FundingTxHash.Hex(bitcoin.ReversedByteOrder),
+				deposit.FundingOutputIndex,
+			))
+	}
+
+	depositsRefs := make([]*DepositReference, len(depositsToSweep))
+	for i, deposit := range depositsToSweep {
+		depositsRefs[i] = &DepositReference{
+			FundingTxHash:      deposit.FundingTxHash,
+			FundingOutputIndex: deposit.FundingOutputIndex,
+			RevealBlock:        deposit.RevealBlock,
+			Vault:              deposit.Vault,
+		}
+	}
+
+	return depositsRefs, nil
+}
+
+// SelectDepositsToSweep finds deposits of the given wallet that are eligible
+// for sweeping and selects at most maxNumberOfDeposits of them using the
+// task's deposit selection strategy. The returned report explains why each
+// eligible deposit was included or skipped. Deposits that are not eligible
+// for sweeping, i.e. already swept, too young, not confirmed enough or
+// close to their refund locktime, are not part of the report. Only the
+// oldest eligible deposits are considered, see
+// DepositSelectionCandidatesFactor.
+func (dst *DepositSweepTask) SelectDepositsToSweep(
+	taskLogger log.StandardLogger,
+	walletPublicKeyHash [20]byte,
+	maxNumberOfDeposits uint16,
+) (*DepositSelectionReport, error) {
 	if walletPublicKeyHash == [20]byte{} {
 		return nil, fmt.Errorf("wallet public key hash is required")
 	}
 
-	taskLogger.Infof("fetching max [%d] deposits", maxNumberOfDeposits)
+	taskLogger.Infof(
+		"selecting max [%d] deposits using strategy [%s]",
+		maxNumberOfDeposits,
+		dst.strategy.Name(),
+	)
 
 	blockCounter, err := dst.chain.BlockCounter()
 	if err != nil {
@@ -319,12 +433,16 @@ func (dst *DepositSweepTask) FindDepositsToSweep(
 		filterStartBlock = currentBlockNumber - DepositSweepLookBackBlocks
 	}
 
+	// Fetch more eligible deposits than fit in a single sweep so the
+	// strategy can choose among them, but keep the number of lookups
+	// bounded.
 	unsweptDeposits, err := findDeposits(
 		taskLogger,
 		dst.chain,
 		dst.btcChain,
 		walletPublicKeyHash,
-		int(maxNumberOfDeposits),
+		int(maxNumberOfDeposits)*DepositSelectionCandidatesFactor,
+		true,
 		true,
 		true,
 		filterStartBlock,
@@ -333,74 +451,19 @@ func (dst *DepositSweepTask) FindDepositsToSweep(
 		return nil, err
 	}
 
-	// Group unswept deposits by their target vault address. The
-	// maxNumberOfDeposits cap is applied inside findDeposits() before
-	// this grouping step, so the grouping operates on an already-capped
-	// set. This is an acceptable trade-off: each vault group will
-	// contain at least one deposit (a valid sweep batch), and because
-	// vault=0x0 (nil-vault) deposits are rare in practice the
-	// throughput impact of the cap reducing a minority group is
-	// negligible.
-	type vaultGroup struct {
-		vaultLabel string
-		deposits   []*Deposit
-	}
-
-	groups := make(map[string]*vaultGroup)
-
-	for _, deposit := range unsweptDeposits {
-		var key string
-		var label string
-
-		if deposit.Vault == nil {
-			key = ""
-			label = "vault=0x0 (nil)"
-		} else {
-			key = strings.ToLower(string(*deposit.Vault))
-			label = string(*deposit.Vault)
-		}
-
-		g, exists := groups[key]
-		if !exists {
-			g = &vaultGroup{vaultLabel: label}
-			groups[key] = g
-		}
-		g.deposits = append(g.deposits, deposit)
-	}
-
-	// Select the vault group with the most deposits. This
-	// largest-group-first policy maximises the number of deposits
-	// swept per transaction. A theoretical starvation risk exists for
-	// minority vault groups when deposits arrive faster than the sweep
-	// cadence can process them; monitoring via the Warn-level logs
-	// emitted below for vault=0x0 deposits is the mitigation strategy
-	// so operators can detect and act on stuck deposits.
-	var selectedGroup *vaultGroup
-	for _, g := range groups {
-		if selectedGroup == nil || len(g.deposits) > len(selectedGroup.deposits) {
-			selectedGroup = g
-		}
-	}
-
-	var depositsToSweep []*Deposit
-	if selectedGroup != nil {
-		depositsToSweep = selectedGroup.deposits
-	}
-
-	if len(groups) > 1 {
-		taskLogger.Infof(
-			"multiple vault groups detected: [%d] groups, selecting [%s] with [%d] deposits",
-			len(groups),
-			selectedGroup.vaultLabel,
-			len(selectedGroup.deposits),
-		)
+	report := dst.strategy.SelectDeposits(
+		unsweptDeposits,
+		int(maxNumberOfDeposits),
+	)
 
-		for _, g := range groups {
+	for _, decision := range report.Decisions {
+		if decision.Included {
 			taskLogger.Infof(
-				"vault group [%s]: [%d] deposits",
-				g.vaultLabel,
-				len(g.deposits),
+				"deposit [%s] included: [%s]",
+				decision.Deposit.DepositKey,
+				decision.Reason,
 			)
+			continue
 		}
 
 		// Vault=0x0 deposits that are not selected for sweeping are
@@ -413,56 +476,91 @@ func (dst *DepositSweepTask) FindDepositsToSweep(
 		//     different vault, making it eligible for a future sweep.
 		// The Warn-level log below flags these deposits for operator
 		// awareness and manual follow-up.
-		if nilGroup, ok := groups[""]; ok {
-			for _, deposit := range nilGroup.deposits {
-				taskLogger.Warnf(
-					"vault=0x0 deposit [%s] with wallet PKH [0x%x] requires manual follow-up",
-					deposit.DepositKey,
-					deposit.WalletPublicKeyHash,
-				)
-			}
+		if decision.Deposit.Vault == nil {
+			taskLogger.Warnf(
+				"vault=0x0 deposit [%s] with wallet PKH [0x%x] skipped: "+
+					"[%s]; requires manual follow-up",
+				decision.Deposit.DepositKey,
+				decision.Deposit.WalletPublicKeyHash,
+				decision.Reason,
+			)
+			continue
 		}
+
+		taskLogger.Infof(
+			"deposit [%s] skipped: [%s]",
+			decision.Deposit.DepositKey,
+			decision.Reason,
+		)
 	}
 
-	if len(depositsToSweep) == 0 {
+	return report, nil
+}
+
+// FindParentTransaction finds the unconfirmed wallet transaction that
+// spends the wallet main UTXO registered in the Bridge and whose change
+// can be spent by the deposit sweep transaction in order to lift the
+// parent's confirmation (child-pays-for-parent). Returns nil if there is
+// no such transaction, the wallet's unconfirmed transaction cannot be used
+// as a parent, or child-pays-for-parent sweeps are not active yet at the
+// given coordination block. Followers check the activation against the
+// coordination block so the current block must not be used here.
+func (dst *DepositSweepTask) FindParentTransaction(
+	taskLogger log.StandardLogger,
+	walletPublicKeyHash [20]byte,
+	coordinationBlock uint64,
+) (*bitcoin.Transaction, error) {
+	if coordinationBlock < tbtc.DepositSweepCPFPActivationBlock {
 		return nil, nil
 	}
 
-	taskLogger.Infof(
-		"found [%d] deposits to sweep",
-		len(depositsToSweep),
+	walletMainUtxo, err := tbtc.DetermineWalletMainUtxo(
+		walletPublicKeyHash,
+		dst.chain,
+		dst.btcChain,
 	)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"cannot determine wallet's main UTXO: [%w]",
+			err,
+		)
+	}
 
-	for _, deposit := range depositsToSweep {
-		taskLogger.Infof(
-			"deposit [%s] - [%s]",
-			deposit.DepositKey,
-			fmt.Sprintf(
-				"reveal block: [%d], funding transaction: [%s], output index: [%d]",
-				deposit.RevealBlock,
-				deposit.FundingTxHash.Hex(bitcoin.ReversedByteOrder),
-				deposit.FundingOutputIndex,
-			))
+	parentTx, _, err := tbtc.DetermineWalletUnconfirmedChange(
+		walletPublicKeyHash,
+		walletMainUtxo,
+		dst.btcChain,
+	)
+	if err != nil {
+		// The unconfirmed transaction cannot be lifted by a child. Do not
+		// fail the whole task though and let the regular sweep be proposed.
+		taskLogger.Warnf(
+			"cannot use unconfirmed transaction as parent: [%v]",
+			err,
+		)
+		return nil, nil
 	}
 
-	depositsRefs := make([]*DepositReference, len(depositsToSweep))
-	for i, deposit := range depositsToSweep {
-		depositsRefs[i] = &DepositReference{
-			FundingTxHash:      deposit.FundingTxHash,
-			FundingOutputIndex: deposit.FundingOutputIndex,
-			RevealBlock:        deposit.RevealBlock,
-			Vault:              deposit.Vault,
-		}
+	if parentTx != nil {
+		taskLogger.Infof(
+			"wallet main UTXO is spent by unconfirmed transaction [%s]",
+			parentTx.Hash().Hex(bitcoin.ReversedByteOrder),
+		)
 	}
 
-	return depositsRefs, nil
+	return parentTx, nil
 }
 
-// ProposeDepositsSweep returns a deposit sweep proposal.
+// ProposeDepositsSweep returns a deposit sweep proposal. The parentTx
+// argument is optional and denotes an unconfirmed wallet transaction
+// whose change should be spent by the sweep transaction. If the fee is
+// not provided, it is estimated so the package made of the parent and the
+// sweep transaction reaches the current fee rate.
 func (dst *DepositSweepTask) ProposeDepositsSweep(
 	taskLogger log.StandardLogger,
 	walletPublicKeyHash [20]byte,
 	deposits []*DepositReference,
+	parentTx *bitcoin.Transaction,
 	fee int64,
 ) (*tbtc.DepositSweepProposal, error) {
 	if len(deposits) == 0 {
@@ -480,11 +578,21 @@ func (dst *DepositSweepTask) ProposeDepositsSweep(
 			return nil, fmt.Errorf("cannot get deposit tx max fee: [%w]", err)
 		}
 
-		estimatedFee, _, err := estimateDepositsSweepFee(
-			dst.btcChain,
-			len(deposits),
-			perDepositMaxFee,
-		)
+		var estimatedFee int64
+		if parentTx != nil {
+			estimatedFee, err = estimateDepositsSweepPackageFee(
+				dst.btcChain,
+				parentTx,
+				len(deposits),
+				perDepositMaxFee,
+			)
+		} else {
+			estimatedFee, _, err = estimateDepositsSweepFee(
+				dst.btcChain,
+				len(deposits),
+				perDepositMaxFee,
+			)
+		}
 		if err != nil {
 			return nil, fmt.Errorf("cannot estimate sweep transaction fee: [%v]", err)
 		}
@@ -518,6 +626,10 @@ func (dst *DepositSweepTask) ProposeDepositsSweep(
 		DepositsRevealBlocks: depositsRevealBlocks,
 	}
 
+	if parentTx != nil {
+		proposal.ParentTxHash = parentTx.Hash()
+	}
+
 	taskLogger.Infof("validating the deposit sweep proposal")
 
 	if _, err := tbtc.ValidateDepositSweepProposal(
@@ -619,21 +731,17 @@ func estimateDepositsSweepFee(
 	depositsCount int,
 	perDepositMaxFee uint64,
 ) (int64, int64, error) {
-	transactionSize, err := bitcoin.NewTransactionSizeEstimator().
-		// 1 P2WPKH main UTXO input.
-		AddPublicKeyHashInputs(1, true).
-		// depositsCount P2WSH deposit inputs.
-		AddScriptHashInputs(depositsCount, depositScriptByteSize, true).
-		// 1 P2WPKH output.
-		AddPublicKeyHashOutputs(1, true).
-		VirtualSize()
+	transactionSize, err := estimateDepositsSweepVirtualSize(depositsCount)
 	if err != nil {
 		return 0, 0, fmt.Errorf("cannot estimate transaction virtual size: [%v]", err)
 	}
 
 	feeEstimator := bitcoin.NewTransactionFeeEstimator(btcChain)
 
-	totalFee, err := feeEstimator.EstimateFee(transactionSize)
+	totalFee, err := feeEstimator.EstimateFee(
+		transactionSize,
+		bitcoin.ConfirmationTarget(btcChain, tbtc.ActionDepositSweep.String()),
+	)
 	if err != nil {
 		return 0, 0, fmt.Errorf("cannot estimate transaction fee: [%v]", err)
 	}
@@ -651,6 +759,67 @@ func estimateDepositsSweepFee(
 	return totalFee, int64(satPerVByteFee), nil
 }
 
+// estimateDepositsSweepPackageFee estimates the total fee for a deposit
+// sweep transaction spending the change of the given unconfirmed parent
+// transaction. The fee is calibrated so the package made of the parent and
+// the sweep transaction reaches the current fee rate. If such a fee exceeds
+// the maximum fee allowed by the Bridge, the maximum fee is used as long as
+// it covers the sweep transaction alone. The parent is lifted only partially
+// in that case.
+func estimateDepositsSweepPackageFee(
+	btcChain bitcoin.Chain,
+	parentTx *bitcoin.Transaction,
+	depositsCount int,
+	perDepositMaxFee uint64,
+) (int64, error) {
+	transactionSize, err := estimateDepositsSweepVirtualSize(depositsCount)
+	if err != nil {
+		return 0, fmt.Errorf("cannot estimate transaction virtual size: [%v]", err)
+	}
+
+	satPerVByteFee, err := btcChain.EstimateSatPerVByteFee(
+		bitcoin.ConfirmationTarget(btcChain, tbtc.ActionDepositSweep.String()),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("cannot get estimated sat/vbyte fee: [%v]", err)
+	}
+
+	builder := bitcoin.NewTransactionBuilder(btcChain)
+	if err := builder.AddUnconfirmedParent(parentTx); err != nil {
+		return 0, fmt.Errorf("cannot add unconfirmed parent: [%v]", err)
+	}
+
+	packageFee, err := builder.PackageFee(transactionSize, satPerVByteFee)
+	if err != nil {
+		return 0, fmt.Errorf("cannot compute package fee: [%v]", err)
+	}
+
+	totalMaxFee := int64(depositsCount) * int64(perDepositMaxFee)
+
+	if satPerVByteFee*transactionSize > totalMaxFee {
+		return 0, fmt.Errorf("estimated fee exceeds the maximum fee")
+	}
+
+	if packageFee > totalMaxFee {
+		return totalMaxFee, nil
+	}
+
+	return packageFee, nil
+}
+
+// estimateDepositsSweepVirtualSize estimates the virtual size of a deposit
+// sweep transaction with the given count of deposits.
+func estimateDepositsSweepVirtualSize(depositsCount int) (int64, error) {
+	return bitcoin.NewTransactionSizeEstimator().
+		// 1 P2WPKH main UTXO input.
+		AddPublicKeyHashInputs(1, true).
+		// depositsCount P2WSH deposit inputs.
+		AddScriptHashInputs(depositsCount, depositScriptByteSize, true).
+		// 1 P2WPKH output.
+		AddPublicKeyHashOutputs(1, true).
+		VirtualSize()
+}
+
 func convertSatToBtc(sats float64) float64 {
 	return sats / float64(100000000)
 }
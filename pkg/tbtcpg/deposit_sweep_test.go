@@ -1,6 +1,8 @@
 package tbtcpg_test
 
 import (
+	"encoding/binary"
+	"math/big"
 	"reflect"
 	"testing"
 	"time"
@@ -31,6 +33,18 @@ func TestDepositSweepLookBackBlocks(t *testing.T) {
 	}
 }
 
+// distantRefundLocktime returns a deposit refund locktime far enough in the
+// future for the deposit to be eligible for sweeping.
+func distantRefundLocktime() [4]byte {
+	return refundLocktime(time.Now().Add(30 * 24 * time.Hour))
+}
+
+func refundLocktime(locktime time.Time) [4]byte {
+	var result [4]byte
+	binary.LittleEndian.PutUint32(result[:], uint32(locktime.Unix()))
+	return result
+}
+
 func TestDepositSweepTask_FindDepositsToSweep_BoundedLookback(t *testing.T) {
 	// When the current block (300000) exceeds the look-back window
 	// (216000 blocks), the filter start block should be bounded:
@@ -83,6 +97,7 @@ func TestDepositSweepTask_FindDepositsToSweep_BoundedLookback(t *testing.T) {
 			WalletPublicKeyHash: walletPublicKeyHash,
 			FundingTxHash:       fundingTxHash,
 			FundingOutputIndex:  1,
+			RefundLocktime:      distantRefundLocktime(),
 		},
 	)
 	if err != nil {
@@ -162,6 +177,7 @@ func TestDepositSweepTask_FindDepositsToSweep_UnderflowGuard(t *testing.T) {
 			WalletPublicKeyHash: walletPublicKeyHash,
 			FundingTxHash:       fundingTxHash,
 			FundingOutputIndex:  1,
+			RefundLocktime:      distantRefundLocktime(),
 		},
 	)
 	if err != nil {
@@ -188,6 +204,109 @@ func TestDepositSweepTask_FindDepositsToSweep_UnderflowGuard(t *testing.T) {
 	}
 }
 
+func TestDepositSweepTask_SelectDepositsToSweep_Eligibility(t *testing.T) {
+	currentBlock := uint64(300000)
+	filterStartBlock := currentBlock - tbtcpg.DepositSweepLookBackBlocks
+
+	walletPublicKeyHash := hexToByte20(
+		"7670343fc00ccc2d0cd65360e6ad400697ea0fed",
+	)
+
+	tbtcChain := tbtcpg.NewLocalChain()
+	btcChain := tbtcpg.NewLocalBitcoinChain()
+
+	blockCounter := tbtcpg.NewMockBlockCounter()
+	blockCounter.SetCurrentBlock(currentBlock)
+	tbtcChain.SetBlockCounter(blockCounter)
+	tbtcChain.SetDepositMinAge(3600)
+
+	maxNumberOfDeposits := uint16(1)
+
+	// The oldest deposit is close to its refund locktime and must not be
+	// swept by any strategy. The remaining deposits exceed the number of
+	// candidates fetched for the strategy.
+	depositsCount := 2 + int(maxNumberOfDeposits)*tbtcpg.DepositSelectionCandidatesFactor
+	fundingTxHashes := make([]bitcoin.Hash, depositsCount)
+	for i := range fundingTxHashes {
+		fundingTxHash := bitcoin.Hash{byte(i + 1)}
+		fundingTxHashes[i] = fundingTxHash
+
+		locktime := distantRefundLocktime()
+		if i == 0 {
+			locktime = refundLocktime(
+				time.Now().Add(tbtcpg.DepositRefundSafetyMargin / 2),
+			)
+		}
+
+		tbtcChain.SetDepositRequest(
+			fundingTxHash,
+			0,
+			&tbtc.DepositChainRequest{
+				RevealedAt: time.Now().Add(-2 * time.Hour),
+				SweptAt:    time.Unix(0, 0),
+			},
+		)
+
+		btcChain.SetTransaction(fundingTxHash, &bitcoin.Transaction{})
+		btcChain.SetTransactionConfirmations(
+			fundingTxHash,
+			tbtc.DepositSweepRequiredFundingTxConfirmations,
+		)
+
+		err := tbtcChain.AddPastDepositRevealedEvent(
+			&tbtc.DepositRevealedEventFilter{
+				StartBlock:          filterStartBlock,
+				WalletPublicKeyHash: [][20]byte{walletPublicKeyHash},
+			},
+			&tbtc.DepositRevealedEvent{
+				BlockNumber:         290000 + uint64(i),
+				WalletPublicKeyHash: walletPublicKeyHash,
+				FundingTxHash:       fundingTxHash,
+				FundingOutputIndex:  0,
+				RefundLocktime:      locktime,
+			},
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	task := tbtcpg.NewDepositSweepTask(tbtcChain, btcChain)
+
+	report, err := task.SelectDepositsToSweep(
+		&testutils.MockLogger{},
+		walletPublicKeyHash,
+		maxNumberOfDeposits,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertIntsEqual(
+		t,
+		"decisions count",
+		int(maxNumberOfDeposits)*tbtcpg.DepositSelectionCandidatesFactor,
+		len(report.Decisions),
+	)
+
+	for i, decision := range report.Decisions {
+		if decision.Deposit.FundingTxHash != fundingTxHashes[i+1] {
+			t.Errorf("unexpected deposit of decision [%d]", i)
+		}
+	}
+
+	included := report.Included()
+	testutils.AssertIntsEqual(
+		t,
+		"included deposits count",
+		int(maxNumberOfDeposits),
+		len(included),
+	)
+	if included[0].FundingTxHash != fundingTxHashes[1] {
+		t.Errorf("unexpected included deposit")
+	}
+}
+
 func TestDepositSweepTask_FindDepositsToSweep(t *testing.T) {
 	err := log.SetLogLevel("*", "DEBUG")
 	if err != nil {
@@ -246,6 +365,7 @@ func TestDepositSweepTask_FindDepositsToSweep(t *testing.T) {
 						WalletPublicKeyHash: deposit.WalletPublicKeyHash,
 						FundingTxHash:       deposit.FundingTxHash,
 						FundingOutputIndex:  deposit.FundingOutputIndex,
+						RefundLocktime:      distantRefundLocktime(),
 					},
 				)
 				if err != nil {
@@ -346,6 +466,7 @@ func TestDepositSweepTask_ProposeDepositsSweep(t *testing.T) {
 				&testutils.MockLogger{},
 				scenario.WalletPublicKeyHash,
 				scenario.DepositsReferences(),
+				nil,
 				scenario.SweepTxFee,
 			)
 
@@ -379,6 +500,188 @@ func TestDepositSweepTask_ProposeDepositsSweep(t *testing.T) {
 	}
 }
 
+func TestDepositSweepTask_ProposeDepositsSweep_WithParent(t *testing.T) {
+	walletPublicKeyHash := hexToByte20(
+		"8db50eb52063ea9d98b3eac91489a90f738986f6",
+	)
+
+	walletScript, err := bitcoin.PayToWitnessPublicKeyHash(walletPublicKeyHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The transaction holding the wallet's main UTXO.
+	mainUtxoTx := &bitcoin.Transaction{
+		Version: 1,
+		Inputs: []*bitcoin.TransactionInput{
+			{
+				Outpoint: &bitcoin.TransactionOutpoint{
+					TransactionHash: bitcoin.Hash{0x01},
+					OutputIndex:     0,
+				},
+				Sequence: 0xffffffff,
+			},
+		},
+		Outputs: []*bitcoin.TransactionOutput{
+			{Value: 1000000, PublicKeyScript: walletScript},
+		},
+	}
+
+	// The unconfirmed parent transaction spending the main UTXO. It pays
+	// a fee of 200 satoshi.
+	parentTx := &bitcoin.Transaction{
+		Version: 1,
+		Inputs: []*bitcoin.TransactionInput{
+			{
+				Outpoint: &bitcoin.TransactionOutpoint{
+					TransactionHash: mainUtxoTx.Hash(),
+					OutputIndex:     0,
+				},
+				Witness: [][]byte{
+					make([]byte, 72),
+					make([]byte, 33),
+				},
+				Sequence: 0xffffffff,
+			},
+		},
+		Outputs: []*bitcoin.TransactionOutput{
+			{Value: 999800, PublicKeyScript: walletScript},
+		},
+	}
+
+	parentFee := int64(200)
+	parentVirtualSize := parentTx.VirtualSize()
+
+	childVirtualSize, err := bitcoin.NewTransactionSizeEstimator().
+		AddPublicKeyHashInputs(1, true).
+		AddScriptHashInputs(1, 126, true).
+		AddPublicKeyHashOutputs(1, true).
+		VirtualSize()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	deposit := &tbtcpg.DepositReference{
+		FundingTxHash:      bitcoin.Hash{0x02},
+		FundingOutputIndex: 1,
+		RevealBlock:        100,
+	}
+
+	tests := map[string]struct {
+		satPerVByteFee int64
+		txMaxFee       uint64
+		expectedFee    int64
+		expectedErr    bool
+	}{
+		"package fee below the maximum fee": {
+			satPerVByteFee: 10,
+			txMaxFee:       100000,
+			expectedFee:    10*(parentVirtualSize+childVirtualSize) - parentFee,
+		},
+		"package fee above the maximum fee": {
+			satPerVByteFee: 10,
+			txMaxFee:       uint64(10*childVirtualSize + 100),
+			expectedFee:    10*childVirtualSize + 100,
+		},
+		"child fee above the maximum fee": {
+			satPerVByteFee: 10,
+			txMaxFee:       uint64(10*childVirtualSize - 1),
+			expectedErr:    true,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			tbtcChain := tbtcpg.NewLocalChain()
+			btcChain := tbtcpg.NewLocalBitcoinChain()
+
+			tbtcChain.SetDepositParameters(0, 0, test.txMaxFee, 0)
+
+			err := tbtcChain.AddPastDepositRevealedEvent(
+				&tbtc.DepositRevealedEventFilter{
+					StartBlock:          deposit.RevealBlock,
+					EndBlock:            &deposit.RevealBlock,
+					WalletPublicKeyHash: [][20]byte{walletPublicKeyHash},
+				},
+				&tbtc.DepositRevealedEvent{
+					WalletPublicKeyHash: walletPublicKeyHash,
+					FundingTxHash:       deposit.FundingTxHash,
+					FundingOutputIndex:  deposit.FundingOutputIndex,
+				},
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			tbtcChain.SetDepositRequest(
+				deposit.FundingTxHash,
+				deposit.FundingOutputIndex,
+				&tbtc.DepositChainRequest{},
+			)
+
+			btcChain.SetTransaction(deposit.FundingTxHash, &bitcoin.Transaction{})
+			btcChain.SetTransactionConfirmations(
+				deposit.FundingTxHash,
+				tbtc.DepositSweepRequiredFundingTxConfirmations,
+			)
+			btcChain.SetTransaction(mainUtxoTx.Hash(), mainUtxoTx)
+			btcChain.SetEstimateSatPerVByteFee(1, test.satPerVByteFee)
+
+			expectedProposal := &tbtc.DepositSweepProposal{
+				DepositsKeys: []struct {
+					FundingTxHash      bitcoin.Hash
+					FundingOutputIndex uint32
+				}{
+					{
+						FundingTxHash:      deposit.FundingTxHash,
+						FundingOutputIndex: deposit.FundingOutputIndex,
+					},
+				},
+				SweepTxFee:           big.NewInt(test.expectedFee),
+				DepositsRevealBlocks: []*big.Int{big.NewInt(100)},
+				ParentTxHash:         parentTx.Hash(),
+			}
+
+			if !test.expectedErr {
+				err = tbtcChain.SetDepositSweepProposalValidationResult(
+					walletPublicKeyHash,
+					expectedProposal,
+					nil,
+					true,
+				)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			task := tbtcpg.NewDepositSweepTask(tbtcChain, btcChain)
+
+			proposal, err := task.ProposeDepositsSweep(
+				&testutils.MockLogger{},
+				walletPublicKeyHash,
+				[]*tbtcpg.DepositReference{deposit},
+				parentTx,
+				0,
+			)
+
+			if test.expectedErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if diff := deep.Equal(proposal, expectedProposal); diff != nil {
+				t.Errorf("invalid deposit sweep proposal: %v", diff)
+			}
+		})
+	}
+}
+
 // setupVaultGroupingDeposit registers a single deposit in the mock chains
 // with the given vault and returns the funding tx hash used.
 func setupVaultGroupingDeposit(
@@ -422,6 +725,7 @@ func setupVaultGroupingDeposit(
 			WalletPublicKeyHash: walletPublicKeyHash,
 			FundingTxHash:       fundingTxHash,
 			FundingOutputIndex:  outputIndex,
+			RefundLocktime:      distantRefundLocktime(),
 		},
 	)
 	if err != nil {
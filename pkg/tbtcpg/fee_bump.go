@@ -0,0 +1,158 @@
+package tbtcpg
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/keep-network/keep-core/pkg/bitcoin"
+	"github.com/keep-network/keep-core/pkg/tbtc"
+)
+
+// FeeBumpTask is a task that may produce a fee bump proposal replacing
+// a wallet transaction stuck in the Bitcoin mempool.
+type FeeBumpTask struct {
+	chain    Chain
+	btcChain bitcoin.Chain
+}
+
+func NewFeeBumpTask(
+	chain Chain,
+	btcChain bitcoin.Chain,
+) *FeeBumpTask {
+	return &FeeBumpTask{
+		chain:    chain,
+		btcChain: btcChain,
+	}
+}
+
+func (fbt *FeeBumpTask) Run(request *tbtc.CoordinationProposalRequest) (
+	tbtc.CoordinationProposal,
+	bool,
+	error,
+) {
+	walletPublicKeyHash := request.WalletPublicKeyHash
+
+	taskLogger := logger.With(
+		zap.String("task", fbt.ActionType().String()),
+		zap.String("walletPKH", fmt.Sprintf("0x%x", walletPublicKeyHash)),
+	)
+
+	// Pick the oldest transaction that is unconfirmed long enough.
+	var stuckTxHash bitcoin.Hash
+	var stuckTxFirstSeen time.Time
+	for txHash, firstSeen := range request.UnconfirmedTransactions {
+		if time.Since(firstSeen) < tbtc.FeeBumpTransactionMinAge {
+			continue
+		}
+
+		if stuckTxFirstSeen.IsZero() || firstSeen.Before(stuckTxFirstSeen) {
+			stuckTxHash = txHash
+			stuckTxFirstSeen = firstSeen
+		}
+	}
+
+	if stuckTxFirstSeen.IsZero() {
+		taskLogger.Info("wallet has no stuck transactions")
+		return nil, false, nil
+	}
+
+	taskLogger = taskLogger.With(
+		zap.String("txHash", stuckTxHash.Hex(bitcoin.ReversedByteOrder)),
+	)
+
+	stuckTx, err := fbt.btcChain.GetTransaction(stuckTxHash)
+	if err != nil {
+		return nil, false, fmt.Errorf(
+			"cannot get stuck transaction: [%w]",
+			err,
+		)
+	}
+
+	currentFee, minFee, maxFee, err := tbtc.DetermineFeeBumpRange(
+		walletPublicKeyHash,
+		stuckTx,
+		fbt.chain,
+		fbt.btcChain,
+	)
+	if err != nil {
+		return nil, false, fmt.Errorf(
+			"cannot determine fee bump range: [%w]",
+			err,
+		)
+	}
+
+	feeEstimator := bitcoin.NewTransactionFeeEstimator(fbt.btcChain)
+	estimatedFee, err := feeEstimator.EstimateFee(
+		stuckTx.VirtualSize(),
+		bitcoin.ConfirmationTarget(fbt.btcChain, tbtc.ActionFeeBump.String()),
+	)
+	if err != nil {
+		return nil, false, fmt.Errorf(
+			"cannot estimate transaction fee: [%w]",
+			err,
+		)
+	}
+
+	if estimatedFee <= currentFee {
+		taskLogger.Infof(
+			"current fee [%v] is not lower than the estimated fee [%v]; "+
+				"skipping fee bump",
+			currentFee,
+			estimatedFee,
+		)
+		return nil, false, nil
+	}
+
+	fee := estimatedFee
+	if fee < minFee {
+		fee = minFee
+	}
+	if fee > maxFee {
+		fee = maxFee
+	}
+
+	if fee < minFee {
+		taskLogger.Infof(
+			"minimum replacement fee [%v] exceeds the maximum fee [%v]; "+
+				"skipping fee bump",
+			minFee,
+			maxFee,
+		)
+		return nil, false, nil
+	}
+
+	taskLogger.Infof(
+		"bumping transaction fee from [%v] to [%v]",
+		currentFee,
+		fee,
+	)
+
+	proposal := &tbtc.FeeBumpProposal{
+		TransactionHash: stuckTxHash,
+		TxFee:           big.NewInt(fee),
+	}
+
+	taskLogger.Infof("validating the fee bump proposal")
+
+	if err := tbtc.ValidateFeeBumpProposal(
+		taskLogger,
+		walletPublicKeyHash,
+		proposal,
+		fbt.chain,
+		fbt.btcChain,
+	); err != nil {
+		return nil, false, fmt.Errorf(
+			"failed to verify fee bump proposal: [%w]",
+			err,
+		)
+	}
+
+	return proposal, true, nil
+}
+
+func (fbt *FeeBumpTask) ActionType() tbtc.WalletActionType {
+	return tbtc.ActionFeeBump
+}
@@ -0,0 +1,183 @@
+package tbtcpg_test
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/keep-network/keep-core/internal/testutils"
+	"github.com/keep-network/keep-core/pkg/bitcoin"
+	"github.com/keep-network/keep-core/pkg/tbtc"
+	"github.com/keep-network/keep-core/pkg/tbtcpg"
+)
+
+func TestFeeBumpTask_Run(t *testing.T) {
+	walletPublicKeyHash := hexToByte20(
+		"8db50eb52063ea9d98b3eac91489a90f738986f6",
+	)
+
+	walletScript, err := bitcoin.PayToWitnessPublicKeyHash(walletPublicKeyHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	redeemerScript, err := bitcoin.PayToPublicKeyHash(
+		hexToByte20("4130879211c54df460e484ddf9aac009cb38ee74"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The transaction holding the wallet's main UTXO.
+	mainUtxoTx := &bitcoin.Transaction{
+		Version: 1,
+		Inputs: []*bitcoin.TransactionInput{
+			{
+				Outpoint: &bitcoin.TransactionOutpoint{
+					TransactionHash: bitcoin.Hash{0x01},
+					OutputIndex:     0,
+				},
+				Sequence: 0xffffffff,
+			},
+		},
+		Outputs: []*bitcoin.TransactionOutput{
+			{Value: 1000000, PublicKeyScript: walletScript},
+		},
+	}
+
+	// The redemption transaction stuck in the mempool. It pays a fee
+	// of 1000 satoshi.
+	stuckTx := &bitcoin.Transaction{
+		Version: 1,
+		Inputs: []*bitcoin.TransactionInput{
+			{
+				Outpoint: &bitcoin.TransactionOutpoint{
+					TransactionHash: mainUtxoTx.Hash(),
+					OutputIndex:     0,
+				},
+				Witness: [][]byte{
+					make([]byte, 72),
+					make([]byte, 33),
+				},
+				Sequence: 0xffffffff,
+			},
+		},
+		Outputs: []*bitcoin.TransactionOutput{
+			{Value: 990000, PublicKeyScript: walletScript},
+			{Value: 9000, PublicKeyScript: redeemerScript},
+		},
+	}
+
+	virtualSize := stuckTx.VirtualSize()
+	currentFee := int64(1000)
+	minFee := currentFee + virtualSize
+
+	tests := map[string]struct {
+		firstSeenAgo     time.Duration
+		satPerVByteFee   int64
+		txMaxFee         uint64
+		expectedProposal tbtc.CoordinationProposal
+		expectedOk       bool
+	}{
+		"transaction not stuck long enough": {
+			firstSeenAgo:     time.Hour,
+			satPerVByteFee:   20,
+			txMaxFee:         5000,
+			expectedProposal: nil,
+			expectedOk:       false,
+		},
+		"current fee not lower than the estimate": {
+			firstSeenAgo:     7 * time.Hour,
+			satPerVByteFee:   1,
+			txMaxFee:         5000,
+			expectedProposal: nil,
+			expectedOk:       false,
+		},
+		"estimated fee within the allowed range": {
+			firstSeenAgo:   7 * time.Hour,
+			satPerVByteFee: 20,
+			txMaxFee:       5000,
+			expectedProposal: &tbtc.FeeBumpProposal{
+				TransactionHash: stuckTx.Hash(),
+				TxFee:           big.NewInt(20 * virtualSize),
+			},
+			expectedOk: true,
+		},
+		"estimated fee below the minimum replacement fee": {
+			firstSeenAgo:   7 * time.Hour,
+			satPerVByteFee: currentFee/virtualSize + 1,
+			txMaxFee:       5000,
+			expectedProposal: &tbtc.FeeBumpProposal{
+				TransactionHash: stuckTx.Hash(),
+				TxFee:           big.NewInt(minFee),
+			},
+			expectedOk: true,
+		},
+		"estimated fee above the maximum fee": {
+			firstSeenAgo:   7 * time.Hour,
+			satPerVByteFee: 100,
+			txMaxFee:       5000,
+			expectedProposal: &tbtc.FeeBumpProposal{
+				TransactionHash: stuckTx.Hash(),
+				TxFee:           big.NewInt(5000),
+			},
+			expectedOk: true,
+		},
+		"maximum fee below the minimum replacement fee": {
+			firstSeenAgo:     7 * time.Hour,
+			satPerVByteFee:   100,
+			txMaxFee:         uint64(minFee - 1),
+			expectedProposal: nil,
+			expectedOk:       false,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			tbtcChain := tbtcpg.NewLocalChain()
+			btcChain := tbtcpg.NewLocalBitcoinChain()
+
+			btcChain.SetTransaction(mainUtxoTx.Hash(), mainUtxoTx)
+			btcChain.SetTransaction(stuckTx.Hash(), stuckTx)
+			btcChain.SetMempoolForPublicKeyHash(
+				walletPublicKeyHash,
+				[]*bitcoin.Transaction{stuckTx},
+			)
+			btcChain.SetEstimateSatPerVByteFee(1, test.satPerVByteFee)
+
+			tbtcChain.SetPendingRedemptionRequest(
+				walletPublicKeyHash,
+				&tbtc.RedemptionRequest{
+					RedeemerOutputScript: redeemerScript,
+					RequestedAmount:      10000,
+					TreasuryFee:          0,
+					TxMaxFee:             test.txMaxFee,
+				},
+			)
+			tbtcChain.SetRedemptionParameters(0, 0, 0, 100000, 0, nil, 0)
+
+			task := tbtcpg.NewFeeBumpTask(tbtcChain, btcChain)
+
+			proposal, ok, err := task.Run(&tbtc.CoordinationProposalRequest{
+				WalletPublicKeyHash: walletPublicKeyHash,
+				UnconfirmedTransactions: map[bitcoin.Hash]time.Time{
+					stuckTx.Hash(): time.Now().Add(-test.firstSeenAgo),
+				},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			testutils.AssertBoolsEqual(t, "ok", test.expectedOk, ok)
+
+			if !reflect.DeepEqual(test.expectedProposal, proposal) {
+				t.Errorf(
+					"unexpected proposal\nexpected: [%v]\nactual:   [%v]",
+					test.expectedProposal,
+					proposal,
+				)
+			}
+		})
+	}
+}
@@ -402,7 +402,10 @@ func EstimateMovedFundsSweepFee(
 
 	feeEstimator := bitcoin.NewTransactionFeeEstimator(btcChain)
 
-	totalFee, err := feeEstimator.EstimateFee(transactionSize)
+	totalFee, err := feeEstimator.EstimateFee(
+		transactionSize,
+		bitcoin.ConfirmationTarget(btcChain, tbtc.ActionMovedFundsSweep.String()),
+	)
 	if err != nil {
 		return 0, fmt.Errorf("cannot estimate transaction fee: [%v]", err)
 	}
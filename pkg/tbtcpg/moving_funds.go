@@ -440,39 +440,24 @@ func (mft *MovingFundsTask) GetWalletMembersInfo(
 	walletOperators []chain.Address,
 	executingOperator chain.Address,
 ) ([]uint32, uint32, error) {
-	// Cache mapping operator addresses to their wallet member IDs. It helps to
-	// limit the number of calls to the ETH client if some operator addresses
-	// occur on the list multiple times.
-	operatorIDCache := make(map[chain.Address]uint32)
 	// TODO: Consider adding a global cache at the `ProposalGenerator` level.
+	walletMemberIDs, err := tbtc.WalletMembersIDs(walletOperators, mft.chain)
+	if err != nil {
+		return nil, 0, err
+	}
 
 	walletMemberIndex := 0
-	walletMemberIDs := make([]uint32, 0)
-
 	for index, operatorAddress := range walletOperators {
 		// If the operator address is the address of the executing operator save
 		// its position. Note that since the executing operator can control
 		// multiple wallet members its address can occur on the list multiple
-		// times. For clarity, we should save the first occurrence on the list,
-		// i.e. when `walletMemberIndex` still holds the value of `0`.
-		if operatorAddress == executingOperator && walletMemberIndex == 0 {
+		// times. For clarity, we should save the first occurrence on the list.
+		if operatorAddress == executingOperator {
 			// Increment the index by 1 as operator indexing starts at 1, not 0.
 			// This ensures the operator's position is correctly identified in
 			// the range [1, walletOperators.length].
 			walletMemberIndex = index + 1
-		}
-
-		// Search for the operator address in the cache. Store the operator
-		// address in the cache if it's not there.
-		if operatorID, found := operatorIDCache[operatorAddress]; !found {
-			fetchedOperatorID, err := mft.chain.GetOperatorID(operatorAddress)
-			if err != nil {
-				return nil, 0, fmt.Errorf("failed to get operator ID: [%w]", err)
-			}
-			operatorIDCache[operatorAddress] = fetchedOperatorID
-			walletMemberIDs = append(walletMemberIDs, fetchedOperatorID)
-		} else {
-			walletMemberIDs = append(walletMemberIDs, operatorID)
+			break
 		}
 	}
 
@@ -647,7 +632,10 @@ func EstimateMovingFundsFee(
 
 	feeEstimator := bitcoin.NewTransactionFeeEstimator(btcChain)
 
-	totalFee, err := feeEstimator.EstimateFee(transactionSize)
+	totalFee, err := feeEstimator.EstimateFee(
+		transactionSize,
+		bitcoin.ConfirmationTarget(btcChain, tbtc.ActionMovingFunds.String()),
+	)
 	if err != nil {
 		return 0, fmt.Errorf("cannot estimate transaction fee: [%v]", err)
 	}
@@ -483,6 +483,8 @@ func EstimateRedemptionFee(
 			sizeEstimator.AddScriptHashOutputs(1, false)
 		case bitcoin.P2WSHScript:
 			sizeEstimator.AddScriptHashOutputs(1, true)
+		case bitcoin.P2TRScript:
+			sizeEstimator.AddTaprootOutputs(1)
 		default:
 			return 0, fmt.Errorf("non-standard redeemer output script type")
 		}
@@ -495,7 +497,10 @@ func EstimateRedemptionFee(
 
 	feeEstimator := bitcoin.NewTransactionFeeEstimator(btcChain)
 
-	totalFee, err := feeEstimator.EstimateFee(transactionSize)
+	totalFee, err := feeEstimator.EstimateFee(
+		transactionSize,
+		bitcoin.ConfirmationTarget(btcChain, tbtc.ActionRedemption.String()),
+	)
 	if err != nil {
 		return 0, fmt.Errorf("cannot estimate transaction fee: [%v]", err)
 	}
@@ -43,6 +43,29 @@ func TestEstimateRedemptionFee(t *testing.T) {
 	testutils.AssertIntsEqual(t, "fee", expectedFee, int(actualFee))
 }
 
+func TestEstimateRedemptionFee_Taproot(t *testing.T) {
+	btcChain := tbtcpg.NewLocalBitcoinChain()
+	btcChain.SetEstimateSatPerVByteFee(1, 16)
+
+	redeemerOutputScript, err := hex.DecodeString(
+		"5120a60869f0dbcf1dc659c9cecbaf8050135ea9e8cdc487053f1dc6880949dc684c",
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	actualFee, err := tbtcpg.EstimateRedemptionFee(
+		btcChain,
+		[]bitcoin.Script{redeemerOutputScript},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedFee := 2448 // transactionVirtualSize * satPerVByteFee = 153 * 16 = 2448
+	testutils.AssertIntsEqual(t, "fee", expectedFee, int(actualFee))
+}
+
 func TestRedemptionAction_FindPendingRedemptions(t *testing.T) {
 	scenarios, err := test.LoadFindPendingRedemptionsTestScenario()
 	if err != nil {
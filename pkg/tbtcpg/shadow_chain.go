@@ -0,0 +1,281 @@
+package tbtcpg
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"time"
+
+	"github.com/keep-network/keep-core/pkg/bitcoin"
+	"github.com/keep-network/keep-core/pkg/chain"
+	"github.com/keep-network/keep-core/pkg/subscription"
+	"github.com/keep-network/keep-core/pkg/tbtc"
+)
+
+// shadowChain is a Chain decorator used in the shadow mode. It does not embed
+// the underlying chain on purpose: every method is either explicitly
+// forwarded as read-only or rejected with ErrShadowMode. This way, a method
+// added to the Chain interface does not reach the underlying chain unless
+// it is reviewed and forwarded here.
+type shadowChain struct {
+	chain Chain
+}
+
+// SubmitMovingFundsCommitment is rejected in the shadow mode.
+func (sc *shadowChain) SubmitMovingFundsCommitment(
+	walletPublicKeyHash [20]byte,
+	walletMainUTXO bitcoin.UnspentTransactionOutput,
+	walletMembersIDs []uint32,
+	walletMemberIndex uint32,
+	targetWallets [][20]byte,
+) error {
+	return ErrShadowMode
+}
+
+// The methods below are read-only and are forwarded to the underlying chain.
+
+func (sc *shadowChain) CalculateWalletID(
+	walletPublicKey *ecdsa.PublicKey,
+) ([32]byte, error) {
+	return sc.chain.CalculateWalletID(walletPublicKey)
+}
+
+func (sc *shadowChain) IsWalletRegistered(EcdsaWalletID [32]byte) (bool, error) {
+	return sc.chain.IsWalletRegistered(EcdsaWalletID)
+}
+
+func (sc *shadowChain) GetWallet(
+	walletPublicKeyHash [20]byte,
+) (*tbtc.WalletChainData, error) {
+	return sc.chain.GetWallet(walletPublicKeyHash)
+}
+
+func (sc *shadowChain) OnWalletClosed(
+	handler func(event *tbtc.WalletClosedEvent),
+) subscription.EventSubscription {
+	return sc.chain.OnWalletClosed(handler)
+}
+
+func (sc *shadowChain) ComputeMainUtxoHash(
+	mainUtxo *bitcoin.UnspentTransactionOutput,
+) [32]byte {
+	return sc.chain.ComputeMainUtxoHash(mainUtxo)
+}
+
+func (sc *shadowChain) PastDepositRevealedEvents(
+	filter *tbtc.DepositRevealedEventFilter,
+) ([]*tbtc.DepositRevealedEvent, error) {
+	return sc.chain.PastDepositRevealedEvents(filter)
+}
+
+func (sc *shadowChain) GetPendingRedemptionRequest(
+	walletPublicKeyHash [20]byte,
+	redeemerOutputScript bitcoin.Script,
+) (*tbtc.RedemptionRequest, bool, error) {
+	return sc.chain.GetPendingRedemptionRequest(
+		walletPublicKeyHash,
+		redeemerOutputScript,
+	)
+}
+
+func (sc *shadowChain) GetDepositRequest(
+	fundingTxHash bitcoin.Hash,
+	fundingOutputIndex uint32,
+) (*tbtc.DepositChainRequest, bool, error) {
+	return sc.chain.GetDepositRequest(fundingTxHash, fundingOutputIndex)
+}
+
+func (sc *shadowChain) GetMovedFundsSweepRequest(
+	movingFundsTxHash bitcoin.Hash,
+	movingFundsTxOutpointIndex uint32,
+) (*tbtc.MovedFundsSweepRequest, bool, error) {
+	return sc.chain.GetMovedFundsSweepRequest(
+		movingFundsTxHash,
+		movingFundsTxOutpointIndex,
+	)
+}
+
+func (sc *shadowChain) GetDepositParameters() (
+	dustThreshold uint64,
+	treasuryFeeDivisor uint64,
+	txMaxFee uint64,
+	revealAheadPeriod uint32,
+	err error,
+) {
+	return sc.chain.GetDepositParameters()
+}
+
+func (sc *shadowChain) GetRedemptionParameters() (
+	dustThreshold uint64,
+	treasuryFeeDivisor uint64,
+	txMaxFee uint64,
+	txMaxTotalFee uint64,
+	timeout uint32,
+	timeoutSlashingAmount *big.Int,
+	timeoutNotifierRewardMultiplier uint32,
+	err error,
+) {
+	return sc.chain.GetRedemptionParameters()
+}
+
+func (sc *shadowChain) GetMovingFundsParameters() (
+	txMaxTotalFee uint64,
+	dustThreshold uint64,
+	timeoutResetDelay uint32,
+	timeout uint32,
+	timeoutSlashingAmount *big.Int,
+	timeoutNotifierRewardMultiplier uint32,
+	commitmentGasOffset uint16,
+	sweepTxMaxTotalFee uint64,
+	sweepTimeout uint32,
+	sweepTimeoutSlashingAmount *big.Int,
+	sweepTimeoutNotifierRewardMultiplier uint32,
+	err error,
+) {
+	return sc.chain.GetMovingFundsParameters()
+}
+
+func (sc *shadowChain) PastMovingFundsCommitmentSubmittedEvents(
+	filter *tbtc.MovingFundsCommitmentSubmittedEventFilter,
+) ([]*tbtc.MovingFundsCommitmentSubmittedEvent, error) {
+	return sc.chain.PastMovingFundsCommitmentSubmittedEvents(filter)
+}
+
+func (sc *shadowChain) PastNewWalletRegisteredEvents(
+	filter *tbtc.NewWalletRegisteredEventFilter,
+) ([]*tbtc.NewWalletRegisteredEvent, error) {
+	return sc.chain.PastNewWalletRegisteredEvents(filter)
+}
+
+func (sc *shadowChain) GetWalletParameters() (
+	creationPeriod uint32,
+	creationMinBtcBalance uint64,
+	creationMaxBtcBalance uint64,
+	closureMinBtcBalance uint64,
+	maxAge uint32,
+	maxBtcTransfer uint64,
+	closingPeriod uint32,
+	err error,
+) {
+	return sc.chain.GetWalletParameters()
+}
+
+func (sc *shadowChain) GetLiveWalletsCount() (uint32, error) {
+	return sc.chain.GetLiveWalletsCount()
+}
+
+func (sc *shadowChain) BuildDepositKey(
+	fundingTxHash bitcoin.Hash,
+	fundingOutputIndex uint32,
+) *big.Int {
+	return sc.chain.BuildDepositKey(fundingTxHash, fundingOutputIndex)
+}
+
+func (sc *shadowChain) PastRedemptionRequestedEvents(
+	filter *tbtc.RedemptionRequestedEventFilter,
+) ([]*tbtc.RedemptionRequestedEvent, error) {
+	return sc.chain.PastRedemptionRequestedEvents(filter)
+}
+
+func (sc *shadowChain) BuildRedemptionKey(
+	walletPublicKeyHash [20]byte,
+	redeemerOutputScript bitcoin.Script,
+) (*big.Int, error) {
+	return sc.chain.BuildRedemptionKey(walletPublicKeyHash, redeemerOutputScript)
+}
+
+func (sc *shadowChain) GetRedemptionMaxSize() (uint16, error) {
+	return sc.chain.GetRedemptionMaxSize()
+}
+
+func (sc *shadowChain) GetRedemptionRequestMinAge() (uint32, error) {
+	return sc.chain.GetRedemptionRequestMinAge()
+}
+
+func (sc *shadowChain) ValidateDepositSweepProposal(
+	walletPublicKeyHash [20]byte,
+	proposal *tbtc.DepositSweepProposal,
+	depositsExtraInfo []struct {
+		*tbtc.Deposit
+		FundingTx *bitcoin.Transaction
+	},
+) error {
+	return sc.chain.ValidateDepositSweepProposal(
+		walletPublicKeyHash,
+		proposal,
+		depositsExtraInfo,
+	)
+}
+
+func (sc *shadowChain) ValidateRedemptionProposal(
+	walletPublicKeyHash [20]byte,
+	proposal *tbtc.RedemptionProposal,
+) error {
+	return sc.chain.ValidateRedemptionProposal(walletPublicKeyHash, proposal)
+}
+
+func (sc *shadowChain) GetDepositSweepMaxSize() (uint16, error) {
+	return sc.chain.GetDepositSweepMaxSize()
+}
+
+func (sc *shadowChain) BlockCounter() (chain.BlockCounter, error) {
+	return sc.chain.BlockCounter()
+}
+
+func (sc *shadowChain) AverageBlockTime() time.Duration {
+	return sc.chain.AverageBlockTime()
+}
+
+func (sc *shadowChain) GetOperatorID(
+	operatorAddress chain.Address,
+) (chain.OperatorID, error) {
+	return sc.chain.GetOperatorID(operatorAddress)
+}
+
+func (sc *shadowChain) ValidateHeartbeatProposal(
+	walletPublicKeyHash [20]byte,
+	proposal *tbtc.HeartbeatProposal,
+) error {
+	return sc.chain.ValidateHeartbeatProposal(walletPublicKeyHash, proposal)
+}
+
+func (sc *shadowChain) PastMovingFundsCompletedEvents(
+	filter *tbtc.MovingFundsCompletedEventFilter,
+) ([]*tbtc.MovingFundsCompletedEvent, error) {
+	return sc.chain.PastMovingFundsCompletedEvents(filter)
+}
+
+func (sc *shadowChain) ValidateMovingFundsProposal(
+	walletPublicKeyHash [20]byte,
+	mainUTXO *bitcoin.UnspentTransactionOutput,
+	proposal *tbtc.MovingFundsProposal,
+) error {
+	return sc.chain.ValidateMovingFundsProposal(
+		walletPublicKeyHash,
+		mainUTXO,
+		proposal,
+	)
+}
+
+func (sc *shadowChain) ValidateMovedFundsSweepProposal(
+	walletPublicKeyHash [20]byte,
+	proposal *tbtc.MovedFundsSweepProposal,
+) error {
+	return sc.chain.ValidateMovedFundsSweepProposal(walletPublicKeyHash, proposal)
+}
+
+func (sc *shadowChain) ComputeMovingFundsCommitmentHash(
+	targetWallets [][20]byte,
+) [32]byte {
+	return sc.chain.ComputeMovingFundsCommitmentHash(targetWallets)
+}
+
+func (sc *shadowChain) GetRedemptionDelay(
+	walletPublicKeyHash [20]byte,
+	redeemerOutputScript bitcoin.Script,
+) (time.Duration, error) {
+	return sc.chain.GetRedemptionDelay(walletPublicKeyHash, redeemerOutputScript)
+}
+
+func (sc *shadowChain) GetDepositMinAge() (uint32, error) {
+	return sc.chain.GetDepositMinAge()
+}
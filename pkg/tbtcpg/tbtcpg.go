@@ -44,6 +44,18 @@ func (pg *ProposalGenerator) SetRedemptionMetricsRecorder(recorder interface {
 	}
 }
 
+// SetDepositSelectionStrategy sets the strategy used by the deposit sweep
+// task to select deposits to sweep.
+func (pg *ProposalGenerator) SetDepositSelectionStrategy(
+	strategy DepositSelectionStrategy,
+) {
+	for _, task := range pg.tasks {
+		if depositSweepTask, ok := task.(*DepositSweepTask); ok {
+			depositSweepTask.SetDepositSelectionStrategy(strategy)
+		}
+	}
+}
+
 // NewProposalGenerator returns a new proposal generator.
 func NewProposalGenerator(
 	chain Chain,
@@ -55,6 +67,7 @@ func NewProposalGenerator(
 		NewHeartbeatTask(chain),
 		NewMovingFundsTask(chain, btcChain),
 		NewMovedFundsSweepTask(chain, btcChain),
+		NewFeeBumpTask(chain, btcChain),
 	}
 
 	return &ProposalGenerator{
@@ -62,6 +75,22 @@ func NewProposalGenerator(
 	}
 }
 
+// ErrShadowMode is the error returned when a proposal task attempts to
+// submit a transaction to the chain while the generator runs in the
+// shadow mode.
+var ErrShadowMode = fmt.Errorf("chain submissions are disabled in shadow mode")
+
+// NewShadowProposalGenerator returns a new proposal generator that never
+// submits transactions to the chain. It is meant to be used by nodes
+// running in the shadow mode. Proposal tasks that need to submit a
+// transaction before generating a proposal fail with ErrShadowMode.
+func NewShadowProposalGenerator(
+	chain Chain,
+	btcChain bitcoin.Chain,
+) *ProposalGenerator {
+	return NewProposalGenerator(&shadowChain{chain: chain}, btcChain)
+}
+
 // Generate generates a coordination proposal based on the given checklist
 // of possible wallet actions. The checklist is a list of actions that
 // should be checked for the given coordination window. This function returns
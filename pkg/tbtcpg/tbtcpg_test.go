@@ -1,8 +1,10 @@
 package tbtcpg
 
 import (
+	"errors"
 	"fmt"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/keep-network/keep-core/pkg/tbtc"
@@ -262,3 +264,47 @@ func (mcp *mockCoordinationProposal) Marshal() ([]byte, error) {
 func (mcp *mockCoordinationProposal) Unmarshal(bytes []byte) error {
 	panic("unsupported")
 }
+
+func TestShadowChain_RejectsSubmissions(t *testing.T) {
+	// The underlying chain is nil so any call reaching it panics.
+	shadow := reflect.ValueOf(&shadowChain{chain: nil})
+
+	chainType := reflect.TypeOf((*Chain)(nil)).Elem()
+
+	submittingMethods := 0
+	for i := 0; i < chainType.NumMethod(); i++ {
+		method := chainType.Method(i)
+		if !strings.HasPrefix(method.Name, "Submit") {
+			continue
+		}
+		submittingMethods++
+
+		t.Run(method.Name, func(t *testing.T) {
+			defer func() {
+				if r := recover(); r != nil {
+					t.Fatalf("call reached the underlying chain: [%v]", r)
+				}
+			}()
+
+			args := make([]reflect.Value, method.Type.NumIn())
+			for j := range args {
+				args[j] = reflect.Zero(method.Type.In(j))
+			}
+
+			results := shadow.MethodByName(method.Name).Call(args)
+
+			err, _ := results[len(results)-1].Interface().(error)
+			if !errors.Is(err, ErrShadowMode) {
+				t.Errorf(
+					"unexpected error\nexpected: [%v]\nactual:   [%v]",
+					ErrShadowMode,
+					err,
+				)
+			}
+		})
+	}
+
+	if submittingMethods == 0 {
+		t.Fatal("no submitting methods found in the chain interface")
+	}
+}
@@ -0,0 +1,114 @@
+package signing
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/ipfs/go-log/v2"
+	"github.com/keep-network/keep-core/pkg/net"
+	"github.com/keep-network/keep-core/pkg/protocol/group"
+	"github.com/keep-network/keep-core/pkg/tecdsa"
+)
+
+// BatchSessionID returns the session ID of the signing protocol session
+// executed for the message with the given index, as part of the signing batch
+// identified by the given batch session ID.
+func BatchSessionID(sessionID string, messageIndex int) string {
+	return fmt.Sprintf("%v-%v", sessionID, messageIndex)
+}
+
+// ExecuteBatch runs the tECDSA signing protocol for all the given messages
+// at once. Each message is signed in an independent protocol session but all
+// sessions are executed concurrently and use the same broadcast channel.
+// Sessions are distinguished by session IDs determined by the BatchSessionID
+// function for the given batch session ID. All other parameters have the same
+// meaning as for the Execute function and are common for all sessions.
+//
+// If at least one session fails, all other sessions of the batch are stopped
+// and the first encountered error is returned. If all sessions succeed,
+// the returned results are in the same order as the given messages, i.e.
+// the first result corresponds to the first message, and so on.
+func ExecuteBatch(
+	ctx context.Context,
+	logger log.StandardLogger,
+	messages []*big.Int,
+	sessionID string,
+	memberIndex group.MemberIndex,
+	privateKeyShare *tecdsa.PrivateKeyShare,
+	groupSize int,
+	dishonestThreshold int,
+	excludedMembersIndexes []group.MemberIndex,
+	channel net.BroadcastChannel,
+	membershipValidator *group.MembershipValidator,
+) ([]*Result, error) {
+	if len(messages) == 0 {
+		return nil, fmt.Errorf("signing batch must contain at least one message")
+	}
+
+	// The batch context is canceled only if one of the sessions fails.
+	// Successful sessions must not be stopped once they complete as other
+	// members may still need their retransmitted messages.
+	batchCtx, cancelBatchCtx := context.WithCancel(ctx)
+
+	results := make([]*Result, len(messages))
+
+	// Keep only the first error as errors of other sessions are most likely
+	// caused by the batch context cancellation.
+	var firstErr error
+	firstErrOnce := sync.Once{}
+
+	wg := sync.WaitGroup{}
+	wg.Add(len(messages))
+
+	for i, message := range messages {
+		go func(i int, message *big.Int) {
+			defer wg.Done()
+
+			result, err := Execute(
+				batchCtx,
+				logger,
+				message,
+				BatchSessionID(sessionID, i),
+				memberIndex,
+				privateKeyShare,
+				groupSize,
+				dishonestThreshold,
+				excludedMembersIndexes,
+				channel,
+				membershipValidator,
+			)
+			if err != nil {
+				firstErrOnce.Do(func() {
+					firstErr = fmt.Errorf(
+						"cannot sign message [%v/%v] of the batch: [%v]",
+						i+1,
+						len(messages),
+						err,
+					)
+				})
+				cancelBatchCtx()
+				return
+			}
+
+			results[i] = result
+		}(i, message)
+	}
+
+	wg.Wait()
+
+	if firstErr != nil {
+		cancelBatchCtx()
+		return nil, firstErr
+	}
+
+	// Release the batch context along with the parent context as
+	// the successful sessions must keep retransmitting their messages.
+	go func() {
+		<-ctx.Done()
+		cancelBatchCtx()
+	}()
+
+	return results, nil
+}
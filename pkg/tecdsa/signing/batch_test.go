@@ -0,0 +1,129 @@
+package signing
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/keep-network/keep-core/internal/testutils"
+	"github.com/keep-network/keep-core/pkg/chain"
+	"github.com/keep-network/keep-core/pkg/chain/local_v1"
+	"github.com/keep-network/keep-core/pkg/internal/tecdsatest"
+	netlocal "github.com/keep-network/keep-core/pkg/net/local"
+	"github.com/keep-network/keep-core/pkg/operator"
+	"github.com/keep-network/keep-core/pkg/protocol/group"
+	"github.com/keep-network/keep-core/pkg/tecdsa"
+)
+
+func TestExecuteBatch(t *testing.T) {
+	groupSize := 5
+	honestThreshold := 3
+
+	localChain := local_v1.Connect(groupSize, honestThreshold)
+
+	testData, err := tecdsatest.LoadPrivateKeyShareTestFixtures(1)
+	if err != nil {
+		t.Fatalf("failed to load test data: [%v]", err)
+	}
+
+	operatorsAddresses := make([]chain.Address, groupSize)
+	for i := range operatorsAddresses {
+		_, operatorPublicKey, err := operator.GenerateKeyPair(
+			local_v1.DefaultCurve,
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		operatorAddress, err := localChain.Signing().PublicKeyToAddress(
+			operatorPublicKey,
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		operatorsAddresses[i] = operatorAddress
+	}
+
+	var tests = map[string]struct {
+		messages      []*big.Int
+		cancelCtx     bool
+		expectedError string
+	}{
+		"empty batch": {
+			messages:      []*big.Int{},
+			expectedError: "signing batch must contain at least one message",
+		},
+		"sessions stopped": {
+			messages: []*big.Int{
+				big.NewInt(100),
+				big.NewInt(200),
+				big.NewInt(300),
+			},
+			cancelCtx:     true,
+			expectedError: "context canceled",
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			channel, err := netlocal.Connect().BroadcastChannelFor("test")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			RegisterUnmarshallers(channel)
+
+			membershipValidator := group.NewMembershipValidator(
+				&testutils.MockLogger{},
+				operatorsAddresses,
+				localChain.Signing(),
+			)
+
+			ctx, cancelCtx := context.WithCancel(context.Background())
+			defer cancelCtx()
+
+			if test.cancelCtx {
+				cancelCtx()
+			}
+
+			results, err := ExecuteBatch(
+				ctx,
+				&testutils.MockLogger{},
+				test.messages,
+				"batch-1",
+				group.MemberIndex(1),
+				tecdsa.NewPrivateKeyShare(testData[0]),
+				groupSize,
+				groupSize-honestThreshold,
+				[]group.MemberIndex{},
+				channel,
+				membershipValidator,
+			)
+
+			if results != nil {
+				t.Errorf("expected nil results, has [%v]", results)
+			}
+
+			if err == nil || !strings.Contains(err.Error(), test.expectedError) {
+				t.Errorf(
+					"unexpected error\n"+
+						"expected: [%v]\n"+
+						"actual:   [%v]",
+					test.expectedError,
+					err,
+				)
+			}
+		})
+	}
+}
+
+func TestBatchSessionID(t *testing.T) {
+	testutils.AssertStringsEqual(
+		t,
+		"batch session ID",
+		"ff-2-1",
+		BatchSessionID("ff-2", 1),
+	)
+}
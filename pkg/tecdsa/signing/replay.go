@@ -0,0 +1,494 @@
+package signing
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bnb-chain/tss-lib/ecdsa/signing"
+	"github.com/bnb-chain/tss-lib/tss"
+	"github.com/ipfs/go-log/v2"
+
+	"github.com/keep-network/keep-core/pkg/net"
+	"github.com/keep-network/keep-core/pkg/protocol/group"
+	"github.com/keep-network/keep-core/pkg/protocol/state"
+)
+
+// CapturedMessage is a signing protocol message received from the broadcast
+// channel and captured for an offline replay.
+type CapturedMessage struct {
+	ReceivedAt      time.Time `json:"received_at"`
+	SenderPublicKey []byte    `json:"sender_public_key"`
+	Type            string    `json:"type"`
+	Payload         []byte    `json:"payload"`
+}
+
+// CaptureMessage captures the given network message for an offline replay.
+// The second return value is false if the given message is not a signing
+// protocol message.
+func CaptureMessage(netMessage net.Message) (*CapturedMessage, bool) {
+	protocolMessage, ok := netMessage.Payload().(replayableMessage)
+	if !ok || !strings.HasPrefix(protocolMessage.Type(), messageTypePrefix) {
+		return nil, false
+	}
+
+	payload, err := protocolMessage.Marshal()
+	if err != nil {
+		return nil, false
+	}
+
+	return &CapturedMessage{
+		ReceivedAt:      time.Now(),
+		SenderPublicKey: netMessage.SenderPublicKey(),
+		Type:            protocolMessage.Type(),
+		Payload:         payload,
+	}, true
+}
+
+// replayableMessage is a signing protocol message that can be captured
+// and replayed.
+type replayableMessage interface {
+	message
+	Marshal() ([]byte, error)
+	Unmarshal(bytes []byte) error
+}
+
+// replayNetMessage is a captured message put back into the net.Message form
+// the protocol states work with.
+type replayNetMessage struct {
+	senderPublicKey []byte
+	payload         replayableMessage
+}
+
+func (rnm *replayNetMessage) TransportSenderID() net.TransportIdentifier {
+	return nil
+}
+
+func (rnm *replayNetMessage) SenderPublicKey() []byte {
+	return rnm.senderPublicKey
+}
+
+func (rnm *replayNetMessage) Payload() interface{} {
+	return rnm.payload
+}
+
+func (rnm *replayNetMessage) Type() string {
+	return rnm.payload.Type()
+}
+
+func (rnm *replayNetMessage) Seqno() uint64 {
+	return 0
+}
+
+// ReplayFinding describes a single message the replay found faulty.
+type ReplayFinding struct {
+	SenderID        group.MemberIndex
+	SenderPublicKey []byte
+	Reason          string
+}
+
+// ReplayPhase is the outcome of the replay of a single protocol phase.
+type ReplayPhase struct {
+	// MessageType is the type of messages exchanged in the phase.
+	MessageType string
+	// Senders holds members whose messages were accepted in the phase.
+	Senders []group.MemberIndex
+	// Missing holds operating members who did not send their messages.
+	Missing []group.MemberIndex
+	// Invalid holds messages accepted in the phase but carrying invalid data.
+	Invalid []*ReplayFinding
+}
+
+// ReplayReport is the outcome of an offline replay of captured signing
+// protocol messages.
+type ReplayReport struct {
+	// Phases holds outcomes of subsequent protocol phases.
+	Phases []*ReplayPhase
+	// Ignored holds messages the protocol states would not accept, e.g.
+	// messages that cannot be unmarshaled, messages from members excluded
+	// from the signing or messages of other sessions.
+	Ignored []*ReplayFinding
+}
+
+// Culprits returns members who sent invalid data in any phase.
+func (rr *ReplayReport) Culprits() []group.MemberIndex {
+	culprits := make(map[group.MemberIndex]bool)
+	for _, phase := range rr.Phases {
+		for _, finding := range phase.Invalid {
+			culprits[finding.SenderID] = true
+		}
+	}
+
+	result := make([]group.MemberIndex, 0, len(culprits))
+	for culprit := range culprits {
+		result = append(result, culprit)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i] < result[j] })
+
+	return result
+}
+
+// replayPhaseDefinition defines how messages of a single protocol phase
+// are replayed.
+type replayPhaseDefinition struct {
+	newMessage func() replayableMessage
+	validate   func(m *member, message replayableMessage) error
+}
+
+// replayPhases holds definitions of all protocol phases, in the order the
+// state machine executes them.
+var replayPhases = []replayPhaseDefinition{
+	{
+		newMessage: func() replayableMessage { return &ephemeralPublicKeyMessage{} },
+		validate: func(m *member, message replayableMessage) error {
+			skgm := &symmetricKeyGeneratingMember{
+				ephemeralKeyPairGeneratingMember: &ephemeralKeyPairGeneratingMember{
+					member: m,
+				},
+			}
+
+			if !skgm.isValidEphemeralPublicKeyMessage(
+				message.(*ephemeralPublicKeyMessage),
+			) {
+				return fmt.Errorf(
+					"message does not contain ephemeral public keys " +
+						"for all group members",
+				)
+			}
+
+			return nil
+		},
+	},
+	{
+		newMessage: func() replayableMessage { return &tssRoundOneMessage{} },
+		validate: func(m *member, message replayableMessage) error {
+			trom := message.(*tssRoundOneMessage)
+			return m.validateReplayedTssPayloads(
+				trom.senderID,
+				trom.broadcastPayload,
+				&signing.SignRound1Message2{},
+				trom.peersPayload,
+			)
+		},
+	},
+	{
+		newMessage: func() replayableMessage { return &tssRoundTwoMessage{} },
+		validate: func(m *member, message replayableMessage) error {
+			trtm := message.(*tssRoundTwoMessage)
+			return m.validateReplayedTssPayloads(
+				trtm.senderID,
+				nil,
+				nil,
+				trtm.peersPayload,
+			)
+		},
+	},
+	replayBroadcastPhase(
+		func() replayableMessage { return &tssRoundThreeMessage{} },
+		func(message replayableMessage) []byte {
+			return message.(*tssRoundThreeMessage).broadcastPayload
+		},
+		&signing.SignRound3Message{},
+	),
+	replayBroadcastPhase(
+		func() replayableMessage { return &tssRoundFourMessage{} },
+		func(message replayableMessage) []byte {
+			return message.(*tssRoundFourMessage).broadcastPayload
+		},
+		&signing.SignRound4Message{},
+	),
+	replayBroadcastPhase(
+		func() replayableMessage { return &tssRoundFiveMessage{} },
+		func(message replayableMessage) []byte {
+			return message.(*tssRoundFiveMessage).broadcastPayload
+		},
+		&signing.SignRound5Message{},
+	),
+	replayBroadcastPhase(
+		func() replayableMessage { return &tssRoundSixMessage{} },
+		func(message replayableMessage) []byte {
+			return message.(*tssRoundSixMessage).broadcastPayload
+		},
+		&signing.SignRound6Message{},
+	),
+	replayBroadcastPhase(
+		func() replayableMessage { return &tssRoundSevenMessage{} },
+		func(message replayableMessage) []byte {
+			return message.(*tssRoundSevenMessage).broadcastPayload
+		},
+		&signing.SignRound7Message{},
+	),
+	replayBroadcastPhase(
+		func() replayableMessage { return &tssRoundEightMessage{} },
+		func(message replayableMessage) []byte {
+			return message.(*tssRoundEightMessage).broadcastPayload
+		},
+		&signing.SignRound8Message{},
+	),
+	replayBroadcastPhase(
+		func() replayableMessage { return &tssRoundNineMessage{} },
+		func(message replayableMessage) []byte {
+			return message.(*tssRoundNineMessage).broadcastPayload
+		},
+		&signing.SignRound9Message{},
+	),
+}
+
+// replayBroadcastPhase defines a phase whose messages carry only a TSS
+// broadcast payload.
+func replayBroadcastPhase(
+	newMessage func() replayableMessage,
+	broadcastPayload func(message replayableMessage) []byte,
+	expectedBroadcastContent tss.MessageContent,
+) replayPhaseDefinition {
+	return replayPhaseDefinition{
+		newMessage: newMessage,
+		validate: func(m *member, message replayableMessage) error {
+			return m.validateReplayedTssPayloads(
+				message.SenderID(),
+				broadcastPayload(message),
+				expectedBroadcastContent,
+				nil,
+			)
+		},
+	}
+}
+
+// Replay feeds the given captured messages into the signing protocol
+// states, as seen by the given member, and reports which members did not
+// send their messages or sent invalid data in subsequent protocol phases.
+//
+// Messages are filtered the same way the protocol states do. Accepted
+// messages are validated as far as possible without the secrets of the
+// original execution: their structure, TSS broadcast payloads and presence
+// of P2P payloads for all operating members are checked. P2P payloads are
+// encrypted with ephemeral keys that are never persisted so, their content
+// cannot be validated offline.
+func Replay(
+	logger log.StandardLogger,
+	sessionID string,
+	memberIndex group.MemberIndex,
+	groupSize int,
+	dishonestThreshold int,
+	excludedMembersIndexes []group.MemberIndex,
+	membershipValidator *group.MembershipValidator,
+	capturedMessages []*CapturedMessage,
+) *ReplayReport {
+	m := &member{
+		logger:              logger,
+		id:                  memberIndex,
+		group:               group.NewGroup(dishonestThreshold, groupSize),
+		membershipValidator: membershipValidator,
+		sessionID:           sessionID,
+	}
+
+	for _, excludedMemberIndex := range excludedMembersIndexes {
+		if excludedMemberIndex != m.id {
+			m.group.MarkMemberAsDisqualified(excludedMemberIndex)
+		}
+	}
+
+	report := &ReplayReport{}
+
+	phasesByType := make(map[string]replayPhaseDefinition)
+	for _, phase := range replayPhases {
+		phasesByType[phase.newMessage().Type()] = phase
+	}
+
+	history := state.NewBaseAsyncState()
+
+	for _, capturedMessage := range capturedMessages {
+		ignore := func(senderID group.MemberIndex, reason string) {
+			report.Ignored = append(report.Ignored, &ReplayFinding{
+				SenderID:        senderID,
+				SenderPublicKey: capturedMessage.SenderPublicKey,
+				Reason:          reason,
+			})
+		}
+
+		phase, ok := phasesByType[capturedMessage.Type]
+		if !ok {
+			ignore(0, fmt.Sprintf("unknown message type [%v]", capturedMessage.Type))
+			continue
+		}
+
+		protocolMessage := phase.newMessage()
+		if err := protocolMessage.Unmarshal(capturedMessage.Payload); err != nil {
+			ignore(0, fmt.Sprintf("cannot unmarshal message: [%v]", err))
+			continue
+		}
+
+		if protocolMessage.SenderID() == m.id {
+			continue
+		}
+
+		if !m.shouldAcceptMessage(
+			protocolMessage.SenderID(),
+			capturedMessage.SenderPublicKey,
+		) {
+			ignore(
+				protocolMessage.SenderID(),
+				"sender is not an operating member or its public key "+
+					"does not match the member index",
+			)
+			continue
+		}
+
+		if protocolMessage.SessionID() != m.sessionID {
+			ignore(
+				protocolMessage.SenderID(),
+				fmt.Sprintf(
+					"message of another session [%v]",
+					protocolMessage.SessionID(),
+				),
+			)
+			continue
+		}
+
+		history.ReceiveToHistory(&replayNetMessage{
+			senderPublicKey: capturedMessage.SenderPublicKey,
+			payload:         protocolMessage,
+		})
+	}
+
+	for _, phase := range replayPhases {
+		report.Phases = append(report.Phases, m.replayPhase(history, phase))
+	}
+
+	return report
+}
+
+// replayPhase replays messages of the given phase held by the history.
+func (m *member) replayPhase(
+	history *state.BaseAsyncState,
+	phase replayPhaseDefinition,
+) *ReplayPhase {
+	messageType := phase.newMessage().Type()
+
+	result := &ReplayPhase{MessageType: messageType}
+
+	accepted := make(map[group.MemberIndex]*replayNetMessage)
+	for _, netMessage := range history.GetAllReceivedMessages(messageType) {
+		replayed := netMessage.(*replayNetMessage)
+		senderID := replayed.payload.SenderID()
+
+		first, ok := accepted[senderID]
+		if !ok {
+			// The protocol states take into account only the first message
+			// of the given sender.
+			accepted[senderID] = replayed
+			result.Senders = append(result.Senders, senderID)
+			continue
+		}
+
+		if !replayedPayloadsEqual(first.payload, replayed.payload) {
+			result.Invalid = append(result.Invalid, &ReplayFinding{
+				SenderID:        senderID,
+				SenderPublicKey: replayed.senderPublicKey,
+				Reason:          "sender sent conflicting messages",
+			})
+		}
+	}
+
+	sort.Slice(result.Senders, func(i, j int) bool {
+		return result.Senders[i] < result.Senders[j]
+	})
+
+	for _, senderID := range result.Senders {
+		replayed := accepted[senderID]
+		if err := phase.validate(m, replayed.payload); err != nil {
+			result.Invalid = append(result.Invalid, &ReplayFinding{
+				SenderID:        senderID,
+				SenderPublicKey: replayed.senderPublicKey,
+				Reason:          err.Error(),
+			})
+		}
+	}
+
+	for _, memberIndex := range m.group.OperatingMemberIndexes() {
+		if _, ok := accepted[memberIndex]; !ok && memberIndex != m.id {
+			result.Missing = append(result.Missing, memberIndex)
+		}
+	}
+
+	return result
+}
+
+// validateReplayedTssPayloads validates TSS payloads of a replayed message.
+// If the expected broadcast content is nil, the message must not carry
+// a broadcast payload. If the peers payload is nil, the message is not
+// expected to carry P2P payloads and they are not checked.
+func (m *member) validateReplayedTssPayloads(
+	senderID group.MemberIndex,
+	broadcastPayload []byte,
+	expectedBroadcastContent tss.MessageContent,
+	peersPayload map[group.MemberIndex][]byte,
+) error {
+	if expectedBroadcastContent == nil {
+		if len(broadcastPayload) > 0 {
+			return fmt.Errorf("unexpected TSS broadcast payload")
+		}
+	} else {
+		if len(broadcastPayload) == 0 {
+			return fmt.Errorf("missing TSS broadcast payload")
+		}
+
+		parsed, err := tss.ParseWireMessage(
+			broadcastPayload,
+			tss.NewPartyID(
+				strconv.Itoa(int(senderID)),
+				"",
+				big.NewInt(int64(senderID)),
+			),
+			true,
+		)
+		if err != nil {
+			return fmt.Errorf("cannot parse TSS broadcast payload: [%v]", err)
+		}
+
+		if reflect.TypeOf(parsed.Content()) !=
+			reflect.TypeOf(expectedBroadcastContent) {
+			return fmt.Errorf(
+				"unexpected TSS broadcast payload type [%v]",
+				parsed.Type(),
+			)
+		}
+
+		if !parsed.ValidateBasic() {
+			return fmt.Errorf("TSS broadcast payload is malformed")
+		}
+	}
+
+	for _, receiverID := range m.group.OperatingMemberIndexes() {
+		if peersPayload == nil || receiverID == senderID {
+			continue
+		}
+
+		if len(peersPayload[receiverID]) == 0 {
+			return fmt.Errorf("no P2P payload for member [%v]", receiverID)
+		}
+	}
+
+	return nil
+}
+
+// replayedPayloadsEqual checks whether the given messages are equal in
+// their marshaled form.
+func replayedPayloadsEqual(a, b replayableMessage) bool {
+	aBytes, err := a.Marshal()
+	if err != nil {
+		return false
+	}
+
+	bBytes, err := b.Marshal()
+	if err != nil {
+		return false
+	}
+
+	return bytes.Equal(aBytes, bBytes)
+}
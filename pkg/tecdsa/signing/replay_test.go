@@ -0,0 +1,290 @@
+package signing
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/keep-network/keep-core/internal/testutils"
+	"github.com/keep-network/keep-core/pkg/chain"
+	"github.com/keep-network/keep-core/pkg/chain/local_v1"
+	"github.com/keep-network/keep-core/pkg/crypto/ephemeral"
+	"github.com/keep-network/keep-core/pkg/operator"
+	"github.com/keep-network/keep-core/pkg/protocol/group"
+)
+
+func TestReplay(t *testing.T) {
+	localChain := local_v1.Connect(groupSize, groupSize-dishonestThreshold)
+
+	operatorsAddresses := make([]chain.Address, groupSize)
+	operatorsPublicKeys := make([][]byte, groupSize)
+	for i := range operatorsAddresses {
+		_, operatorPublicKey, err := operator.GenerateKeyPair(
+			local_v1.DefaultCurve,
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		operatorAddress, err := localChain.Signing().PublicKeyToAddress(
+			operatorPublicKey,
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		operatorsAddresses[i] = operatorAddress
+		operatorsPublicKeys[i] = operator.MarshalUncompressed(operatorPublicKey)
+	}
+
+	membershipValidator := group.NewMembershipValidator(
+		&testutils.MockLogger{},
+		operatorsAddresses,
+		localChain.Signing(),
+	)
+
+	members, err := initializeTssRoundOneMembersGroup(
+		dishonestThreshold,
+		groupSize,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The replay is done from the perspective of the first member so,
+	// messages of the remaining members are captured.
+	ephemeralPublicKeyMessages := make(
+		map[group.MemberIndex]*ephemeralPublicKeyMessage,
+	)
+	tssRoundOneMessages := make(map[group.MemberIndex]*tssRoundOneMessage)
+	for _, member := range members[1:] {
+		ephemeralPublicKeyMessage, err := (&ephemeralKeyPairGeneratingMember{
+			member:            member.member,
+			ephemeralKeyPairs: make(map[group.MemberIndex]*ephemeral.KeyPair),
+		}).generateEphemeralKeyPair()
+		if err != nil {
+			t.Fatal(err)
+		}
+		ephemeralPublicKeyMessages[member.id] = ephemeralPublicKeyMessage
+
+		ctx, cancelCtx := context.WithTimeout(
+			context.Background(),
+			10*time.Second,
+		)
+		tssRoundOneMessage, err := member.tssRoundOne(ctx)
+		cancelCtx()
+		if err != nil {
+			t.Fatal(err)
+		}
+		tssRoundOneMessages[member.id] = tssRoundOneMessage
+	}
+
+	capture := func(
+		senderID group.MemberIndex,
+		message replayableMessage,
+	) *CapturedMessage {
+		payload, err := message.Marshal()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return &CapturedMessage{
+			SenderPublicKey: operatorsPublicKeys[senderID-1],
+			Type:            message.Type(),
+			Payload:         payload,
+		}
+	}
+
+	copyTssRoundOneMessage := func(
+		senderID group.MemberIndex,
+	) *tssRoundOneMessage {
+		original := tssRoundOneMessages[senderID]
+
+		peersPayload := make(map[group.MemberIndex][]byte)
+		for receiverID, payload := range original.peersPayload {
+			peersPayload[receiverID] = payload
+		}
+
+		return &tssRoundOneMessage{
+			senderID:         original.senderID,
+			broadcastPayload: original.broadcastPayload,
+			peersPayload:     peersPayload,
+			sessionID:        original.sessionID,
+		}
+	}
+
+	var tests = map[string]struct {
+		capturedMessages       func() []*CapturedMessage
+		excludedMembersIndexes []group.MemberIndex
+		expectedSenders        [][]group.MemberIndex
+		expectedCulprits       []group.MemberIndex
+		expectedIgnored        int
+	}{
+		"all messages valid": {
+			capturedMessages: func() []*CapturedMessage {
+				return []*CapturedMessage{
+					capture(2, ephemeralPublicKeyMessages[2]),
+					capture(3, ephemeralPublicKeyMessages[3]),
+					capture(2, tssRoundOneMessages[2]),
+					capture(3, tssRoundOneMessages[3]),
+				}
+			},
+			expectedSenders:  [][]group.MemberIndex{{2, 3}, {2, 3}},
+			expectedCulprits: []group.MemberIndex{},
+		},
+		"malformed TSS broadcast payload": {
+			capturedMessages: func() []*CapturedMessage {
+				message := copyTssRoundOneMessage(3)
+				message.broadcastPayload = []byte{0x01, 0x02}
+
+				return []*CapturedMessage{
+					capture(2, ephemeralPublicKeyMessages[2]),
+					capture(3, ephemeralPublicKeyMessages[3]),
+					capture(2, tssRoundOneMessages[2]),
+					capture(3, message),
+				}
+			},
+			expectedSenders:  [][]group.MemberIndex{{2, 3}, {2, 3}},
+			expectedCulprits: []group.MemberIndex{3},
+		},
+		"missing P2P payload": {
+			capturedMessages: func() []*CapturedMessage {
+				message := copyTssRoundOneMessage(2)
+				delete(message.peersPayload, 1)
+
+				return []*CapturedMessage{
+					capture(2, ephemeralPublicKeyMessages[2]),
+					capture(3, ephemeralPublicKeyMessages[3]),
+					capture(2, message),
+					capture(3, tssRoundOneMessages[3]),
+				}
+			},
+			expectedSenders:  [][]group.MemberIndex{{2, 3}, {2, 3}},
+			expectedCulprits: []group.MemberIndex{2},
+		},
+		"incomplete ephemeral public keys": {
+			capturedMessages: func() []*CapturedMessage {
+				// The message lacks the ephemeral public key for member 2.
+				message := &ephemeralPublicKeyMessage{
+					senderID: 3,
+					ephemeralPublicKeys: map[group.MemberIndex]*ephemeral.PublicKey{
+						1: ephemeralPublicKeyMessages[3].ephemeralPublicKeys[1],
+					},
+					sessionID: sessionID,
+				}
+
+				return []*CapturedMessage{
+					capture(2, ephemeralPublicKeyMessages[2]),
+					capture(3, message),
+				}
+			},
+			expectedSenders:  [][]group.MemberIndex{{2, 3}, {}},
+			expectedCulprits: []group.MemberIndex{3},
+		},
+		"conflicting messages": {
+			capturedMessages: func() []*CapturedMessage {
+				message := copyTssRoundOneMessage(2)
+				message.broadcastPayload = tssRoundOneMessages[3].broadcastPayload
+
+				return []*CapturedMessage{
+					capture(2, ephemeralPublicKeyMessages[2]),
+					capture(3, ephemeralPublicKeyMessages[3]),
+					capture(2, tssRoundOneMessages[2]),
+					capture(2, message),
+					capture(3, tssRoundOneMessages[3]),
+				}
+			},
+			expectedSenders:  [][]group.MemberIndex{{2, 3}, {2, 3}},
+			expectedCulprits: []group.MemberIndex{2},
+		},
+		"ignored messages": {
+			capturedMessages: func() []*CapturedMessage {
+				otherSessionMessage := copyTssRoundOneMessage(2)
+				otherSessionMessage.sessionID = "session-2"
+
+				return []*CapturedMessage{
+					capture(2, ephemeralPublicKeyMessages[2]),
+					// Member 3 is excluded from the signing.
+					capture(3, ephemeralPublicKeyMessages[3]),
+					capture(2, otherSessionMessage),
+					{Type: "unknown"},
+				}
+			},
+			excludedMembersIndexes: []group.MemberIndex{3},
+			expectedSenders:        [][]group.MemberIndex{{2}, {}},
+			expectedCulprits:       []group.MemberIndex{},
+			expectedIgnored:        3,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			report := Replay(
+				&testutils.MockLogger{},
+				sessionID,
+				1,
+				groupSize,
+				dishonestThreshold,
+				test.excludedMembersIndexes,
+				membershipValidator,
+				test.capturedMessages(),
+			)
+
+			testutils.AssertIntsEqual(
+				t,
+				"phases count",
+				len(replayPhases),
+				len(report.Phases),
+			)
+
+			for i, phase := range report.Phases {
+				expectedSenders := []group.MemberIndex{}
+				if i < len(test.expectedSenders) {
+					expectedSenders = test.expectedSenders[i]
+				}
+
+				actualSenders := phase.Senders
+				if actualSenders == nil {
+					actualSenders = []group.MemberIndex{}
+				}
+
+				if !reflect.DeepEqual(expectedSenders, actualSenders) {
+					t.Errorf(
+						"unexpected senders of phase [%v]\n"+
+							"expected: [%v]\n"+
+							"actual:   [%v]",
+						phase.MessageType,
+						expectedSenders,
+						actualSenders,
+					)
+				}
+
+				testutils.AssertIntsEqual(
+					t,
+					"missing members count of phase "+phase.MessageType,
+					groupSize-1-len(test.excludedMembersIndexes)-
+						len(expectedSenders),
+					len(phase.Missing),
+				)
+			}
+
+			if !reflect.DeepEqual(test.expectedCulprits, report.Culprits()) {
+				t.Errorf(
+					"unexpected culprits\n"+
+						"expected: [%v]\n"+
+						"actual:   [%v]",
+					test.expectedCulprits,
+					report.Culprits(),
+				)
+			}
+
+			testutils.AssertIntsEqual(
+				t,
+				"ignored messages count",
+				test.expectedIgnored,
+				len(report.Ignored),
+			)
+		})
+	}
+}